@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+)
+
+// AggregateEventType returns the most severe event type among the events,
+// used to drive the top-level component health state.
+// Returns EventTypeInfo if there is no event, as there is nothing to act on.
+func AggregateEventType(events []components.Event) common.EventType {
+	if len(events) == 0 {
+		return common.EventTypeInfo
+	}
+
+	ret := common.EventTypeUnknown
+	for _, ev := range events {
+		if ev.Type.Severity() > ret.Severity() {
+			ret = ev.Type
+		}
+	}
+	return ret
+}
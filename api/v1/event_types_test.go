@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestAggregateEventType(t *testing.T) {
+	tests := []struct {
+		name     string
+		types    []common.EventType
+		expected common.EventType
+	}{
+		{
+			name:     "empty events",
+			types:    nil,
+			expected: common.EventTypeInfo,
+		},
+		{
+			name:     "only unknown",
+			types:    []common.EventType{common.EventTypeUnknown, ""},
+			expected: common.EventTypeUnknown,
+		},
+		{
+			name:     "info and warning",
+			types:    []common.EventType{common.EventTypeInfo, common.EventTypeWarning, common.EventTypeInfo},
+			expected: common.EventTypeWarning,
+		},
+		{
+			name:     "critical over warning",
+			types:    []common.EventType{common.EventTypeWarning, common.EventTypeCritical, common.EventTypeUnknown},
+			expected: common.EventTypeCritical,
+		},
+		{
+			name:     "fatal is the highest",
+			types:    []common.EventType{common.EventTypeFatal, common.EventTypeCritical, common.EventTypeWarning, common.EventTypeInfo},
+			expected: common.EventTypeFatal,
+		},
+		{
+			name:     "unknown is the lowest",
+			types:    []common.EventType{common.EventTypeUnknown, common.EventTypeInfo},
+			expected: common.EventTypeInfo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make([]components.Event, 0, len(tt.types))
+			for _, typ := range tt.types {
+				events = append(events, components.Event{Type: typ})
+			}
+			if got := AggregateEventType(events); got != tt.expected {
+				t.Errorf("AggregateEventType() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
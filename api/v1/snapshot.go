@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// SignedInfoSnapshot is a tamper-evident snapshot of the full health model (info),
+// signed by the node so that a central collector can verify the reported health
+// was not altered in transit.
+type SignedInfoSnapshot struct {
+	// Time is the time when the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Info is the JSON-encoded LeptonInfo, kept raw so that the signature
+	// is verified against the exact bytes that were signed.
+	Info json.RawMessage `json:"info"`
+	// Signature is the ed25519 signature of the "Time" and the "Info" bytes
+	// (see signedSnapshotMessage), so that neither can be altered or replayed
+	// with the other.
+	Signature []byte `json:"signature"`
+}
+
+// signedSnapshotMessage returns the bytes to sign for the snapshot.
+// The time is encoded in the Unix nanoseconds, so the message does not
+// depend on how the time zone is encoded in JSON.
+func signedSnapshotMessage(t time.Time, info []byte) []byte {
+	msg := strconv.AppendInt(nil, t.UnixNano(), 10)
+	msg = append(msg, '\n')
+	return append(msg, info...)
+}
+
+var ErrInvalidSignature = errors.New("invalid snapshot signature")
+
+// SignedSnapshot returns the JSON-encoded snapshot of the info with an attached ed25519 signature.
+func SignedSnapshot(info LeptonInfo, key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key size %d", len(key))
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal info: %w", err)
+	}
+
+	now := time.Now().UTC()
+	snapshot := SignedInfoSnapshot{
+		Time:      now,
+		Info:      b,
+		Signature: ed25519.Sign(key, signedSnapshotMessage(now, b)),
+	}
+	return json.Marshal(snapshot)
+}
+
+// VerifySignedSnapshot verifies the signed snapshot with the public key,
+// and returns the snapshot time and the decoded info if and only if
+// the signature is valid for both.
+func VerifySignedSnapshot(data []byte, pub ed25519.PublicKey) (time.Time, LeptonInfo, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return time.Time{}, nil, fmt.Errorf("invalid public key size %d", len(pub))
+	}
+
+	var snapshot SignedInfoSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	if !ed25519.Verify(pub, signedSnapshotMessage(snapshot.Time, snapshot.Info), snapshot.Signature) {
+		return time.Time{}, nil, ErrInvalidSignature
+	}
+
+	var info LeptonInfo
+	if err := json.Unmarshal(snapshot.Info, &info); err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to unmarshal info: %w", err)
+	}
+	return snapshot.Time, info, nil
+}
+
+// Snapshot is the bundle of all the component states, events, and metrics
+// of a node, to inspect the node offline (e.g., "gpud snapshot --out file.json.gz").
+type Snapshot struct {
+	Header SnapshotHeader `json:"header"`
+	Info   LeptonInfo     `json:"info"`
+}
+
+// SnapshotHeader describes where and when the snapshot was taken.
+type SnapshotHeader struct {
+	// Time is the time when the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Version is the gpud version of the node.
+	Version string `json:"version"`
+	// Hostname is the hostname of the node.
+	Hostname string `json:"hostname"`
+}
+
+// WriteSnapshot writes the snapshot as the gzipped JSON.
+func WriteSnapshot(w io.Writer, snapshot Snapshot) error {
+	gw := gzip.NewWriter(w)
+	if err := json.NewEncoder(gw).Encode(snapshot); err != nil {
+		_ = gw.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return gw.Close()
+}
+
+// LoadSnapshot reads the gzipped JSON snapshot written by WriteSnapshot.
+func LoadSnapshot(r io.Reader) (Snapshot, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read gzipped snapshot: %w", err)
+	}
+	defer gr.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(gr).Decode(&snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"sort"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+)
+
+// SnapshotDiff is the difference between two snapshots of a node
+// (e.g., before and after a reboot to confirm an Xid error is cleared).
+type SnapshotDiff struct {
+	// HealthChanges is the list of the component states whose health changed.
+	HealthChanges []HealthChange `json:"health_changes,omitempty"`
+	// NewEvents is the list of the events only found in the "after" snapshot.
+	NewEvents []SnapshotEvent `json:"new_events,omitempty"`
+	// ClearedEvents is the list of the events only found in the "before" snapshot.
+	ClearedEvents []SnapshotEvent `json:"cleared_events,omitempty"`
+	// MetricDeltas is the list of the metrics whose latest values changed.
+	MetricDeltas []MetricDelta `json:"metric_deltas,omitempty"`
+}
+
+// HealthChange is the health change of a component state.
+// The health is empty if the state is not found in the snapshot.
+type HealthChange struct {
+	Component string `json:"component"`
+	State     string `json:"state"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+}
+
+// SnapshotEvent is an event with the name of the component that reported it.
+type SnapshotEvent struct {
+	Component string `json:"component"`
+	components.Event
+}
+
+// MetricDelta is the change of the latest value of a metric.
+type MetricDelta struct {
+	Component           string  `json:"component"`
+	MetricName          string  `json:"metric_name"`
+	MetricSecondaryName string  `json:"metric_secondary_name,omitempty"`
+	Before              float64 `json:"before"`
+	After               float64 `json:"after"`
+	Delta               float64 `json:"delta"`
+}
+
+// DiffSnapshots returns the health changes, the new and cleared events,
+// and the metric deltas between the two snapshots.
+// The results are sorted by the component name for the stable output.
+func DiffSnapshots(before, after Snapshot) SnapshotDiff {
+	return SnapshotDiff{
+		HealthChanges: diffHealth(before.Info, after.Info),
+		NewEvents:     diffEvents(after.Info, before.Info),
+		ClearedEvents: diffEvents(before.Info, after.Info),
+		MetricDeltas:  diffMetrics(before.Info, after.Info),
+	}
+}
+
+type stateKey struct {
+	component string
+	state     string
+}
+
+func stateHealth(s components.State) string {
+	if s.Health != "" {
+		return s.Health
+	}
+	if s.Healthy {
+		return components.StateHealthy
+	}
+	return components.StateUnhealthy
+}
+
+func healthByState(info LeptonInfo) map[stateKey]string {
+	m := make(map[stateKey]string)
+	for _, ci := range info {
+		for _, s := range ci.Info.States {
+			m[stateKey{component: ci.Component, state: s.Name}] = stateHealth(s)
+		}
+	}
+	return m
+}
+
+func diffHealth(before, after LeptonInfo) []HealthChange {
+	b, a := healthByState(before), healthByState(after)
+
+	keys := make(map[stateKey]struct{}, len(b)+len(a))
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+
+	var changes []HealthChange
+	for k := range keys {
+		if b[k] == a[k] {
+			continue
+		}
+		changes = append(changes, HealthChange{
+			Component: k.component,
+			State:     k.state,
+			Before:    b[k],
+			After:     a[k],
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Component != changes[j].Component {
+			return changes[i].Component < changes[j].Component
+		}
+		return changes[i].State < changes[j].State
+	})
+	return changes
+}
+
+type eventKey struct {
+	component string
+	name      string
+	unixTime  int64
+	message   string
+}
+
+func toEventKey(component string, ev components.Event) eventKey {
+	return eventKey{
+		component: component,
+		name:      ev.Name,
+		// the snapshot JSON encodes the event time in seconds
+		unixTime: ev.Time.Unix(),
+		message:  ev.Message,
+	}
+}
+
+// diffEvents returns the events in "a" that are not found in "b".
+func diffEvents(a, b LeptonInfo) []SnapshotEvent {
+	found := make(map[eventKey]struct{})
+	for _, ci := range b {
+		for _, ev := range ci.Info.Events {
+			found[toEventKey(ci.Component, ev)] = struct{}{}
+		}
+	}
+
+	var evs []SnapshotEvent
+	for _, ci := range a {
+		for _, ev := range ci.Info.Events {
+			if _, ok := found[toEventKey(ci.Component, ev)]; ok {
+				continue
+			}
+			evs = append(evs, SnapshotEvent{Component: ci.Component, Event: ev})
+		}
+	}
+	sort.SliceStable(evs, func(i, j int) bool {
+		if evs[i].Component != evs[j].Component {
+			return evs[i].Component < evs[j].Component
+		}
+		return evs[i].Time.Before(&evs[j].Time)
+	})
+	return evs
+}
+
+type metricKey struct {
+	component     string
+	name          string
+	secondaryName string
+}
+
+type metricValue struct {
+	time  time.Time
+	value float64
+}
+
+// latestMetrics returns the latest value of each metric.
+func latestMetrics(info LeptonInfo) map[metricKey]metricValue {
+	m := make(map[metricKey]metricValue)
+	for _, ci := range info {
+		for _, mt := range ci.Info.Metrics {
+			k := metricKey{component: ci.Component, name: mt.MetricName, secondaryName: mt.MetricSecondaryName}
+			t := time.Unix(mt.UnixSeconds, 0)
+			if prev, ok := m[k]; ok && prev.time.After(t) {
+				continue
+			}
+			m[k] = metricValue{time: t, value: mt.Value}
+		}
+	}
+	return m
+}
+
+// diffMetrics returns the deltas of the metrics found in both snapshots.
+func diffMetrics(before, after LeptonInfo) []MetricDelta {
+	b, a := latestMetrics(before), latestMetrics(after)
+
+	var deltas []MetricDelta
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok || bv.value == av.value {
+			continue
+		}
+		deltas = append(deltas, MetricDelta{
+			Component:           k.component,
+			MetricName:          k.name,
+			MetricSecondaryName: k.secondaryName,
+			Before:              bv.value,
+			After:               av.value,
+			Delta:               av.value - bv.value,
+		})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Component != deltas[j].Component {
+			return deltas[i].Component < deltas[j].Component
+		}
+		if deltas[i].MetricName != deltas[j].MetricName {
+			return deltas[i].MetricName < deltas[j].MetricName
+		}
+		return deltas[i].MetricSecondaryName < deltas[j].MetricSecondaryName
+	})
+	return deltas
+}
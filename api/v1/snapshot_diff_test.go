@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+)
+
+func xidSnapshot(now time.Time, health string, events []components.Event, xidTotal float64) Snapshot {
+	return Snapshot{
+		Header: SnapshotHeader{Time: now, Version: "v0.4.0", Hostname: "gpu-node-1"},
+		Info: LeptonInfo{
+			{
+				Component: "accelerator-nvidia-error-xid",
+				Info: components.Info{
+					States: []components.State{
+						{Name: "error_xid", Healthy: health == components.StateHealthy, Health: health},
+					},
+					Events: events,
+					Metrics: []components.Metric{
+						{Metric: components_metrics_state.Metric{UnixSeconds: now.Add(-time.Hour).Unix(), MetricName: "xid_total", Value: 0}},
+						{Metric: components_metrics_state.Metric{UnixSeconds: now.Unix(), MetricName: "xid_total", Value: xidTotal}},
+					},
+				},
+			},
+			{
+				Component: "disk",
+				Info: components.Info{
+					States: []components.State{{Name: "disk", Healthy: true}},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffSnapshotsClearedXid(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	xid48 := components.Event{
+		Time:    metav1.NewTime(now.Add(-10 * time.Minute)),
+		Name:    "error_xid",
+		Type:    common.EventTypeCritical,
+		Message: "xid 48 detected on GPU-0",
+	}
+
+	before := xidSnapshot(now, components.StateUnhealthy, []components.Event{xid48}, 1)
+	// after the reboot, the xid 48 event is gone
+	after := xidSnapshot(now.Add(time.Hour), components.StateHealthy, nil, 1)
+
+	diff := DiffSnapshots(before, after)
+
+	if len(diff.ClearedEvents) != 1 {
+		t.Fatalf("expected 1 cleared event, got %+v", diff.ClearedEvents)
+	}
+	cleared := diff.ClearedEvents[0]
+	if cleared.Component != "accelerator-nvidia-error-xid" || cleared.Message != xid48.Message {
+		t.Errorf("unexpected cleared event %+v", cleared)
+	}
+	if len(diff.NewEvents) != 0 {
+		t.Errorf("expected no new events, got %+v", diff.NewEvents)
+	}
+
+	expectedChange := HealthChange{
+		Component: "accelerator-nvidia-error-xid",
+		State:     "error_xid",
+		Before:    components.StateUnhealthy,
+		After:     components.StateHealthy,
+	}
+	if len(diff.HealthChanges) != 1 || diff.HealthChanges[0] != expectedChange {
+		t.Errorf("expected health change %+v, got %+v", expectedChange, diff.HealthChanges)
+	}
+
+	// the latest xid_total is 1 in both snapshots
+	if len(diff.MetricDeltas) != 0 {
+		t.Errorf("expected no metric deltas, got %+v", diff.MetricDeltas)
+	}
+}
+
+func TestDiffSnapshotsNewEventAndMetricDelta(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	xid79 := components.Event{
+		Time:    metav1.NewTime(now.Add(-time.Minute)),
+		Name:    "error_xid",
+		Type:    common.EventTypeCritical,
+		Message: "xid 79 detected on GPU-1",
+	}
+
+	before := xidSnapshot(now, components.StateHealthy, nil, 1)
+	after := xidSnapshot(now.Add(time.Hour), components.StateHealthy, []components.Event{xid79}, 3)
+
+	diff := DiffSnapshots(before, after)
+	if len(diff.NewEvents) != 1 || diff.NewEvents[0].Message != xid79.Message {
+		t.Errorf("expected the new xid 79 event, got %+v", diff.NewEvents)
+	}
+	if len(diff.ClearedEvents) != 0 {
+		t.Errorf("expected no cleared events, got %+v", diff.ClearedEvents)
+	}
+	if len(diff.HealthChanges) != 0 {
+		t.Errorf("expected no health changes, got %+v", diff.HealthChanges)
+	}
+
+	expectedDelta := MetricDelta{
+		Component:  "accelerator-nvidia-error-xid",
+		MetricName: "xid_total",
+		Before:     1,
+		After:      3,
+		Delta:      2,
+	}
+	if len(diff.MetricDeltas) != 1 || diff.MetricDeltas[0] != expectedDelta {
+		t.Errorf("expected metric delta %+v, got %+v", expectedDelta, diff.MetricDeltas)
+	}
+}
+
+func TestDiffSnapshotsIdentical(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	s := xidSnapshot(now, components.StateHealthy, []components.Event{
+		{Time: metav1.NewTime(now), Name: "error_xid", Message: "xid 13"},
+	}, 1)
+
+	diff := DiffSnapshots(s, s)
+	if len(diff.HealthChanges) != 0 || len(diff.NewEvents) != 0 || len(diff.ClearedEvents) != 0 || len(diff.MetricDeltas) != 0 {
+		t.Errorf("expected empty diff, got %+v", diff)
+	}
+}
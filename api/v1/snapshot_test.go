@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+)
+
+func testInfo() LeptonInfo {
+	return LeptonInfo{
+		{
+			Component: "accelerator-nvidia-error-xid",
+			Info: components.Info{
+				States: []components.State{
+					{Name: "error_xid", Healthy: false, Health: components.StateUnhealthy, Reason: "xid 79 detected"},
+				},
+			},
+		},
+	}
+}
+
+func TestSignedSnapshotRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	before := time.Now()
+	b, err := SignedSnapshot(testInfo(), priv)
+	if err != nil {
+		t.Fatalf("SignedSnapshot() error = %v", err)
+	}
+
+	ts, info, err := VerifySignedSnapshot(b, pub)
+	if err != nil {
+		t.Fatalf("VerifySignedSnapshot() error = %v", err)
+	}
+	if ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("unexpected snapshot time %v", ts)
+	}
+	if len(info) != 1 || info[0].Component != "accelerator-nvidia-error-xid" {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if info[0].Info.States[0].Reason != "xid 79 detected" {
+		t.Errorf("unexpected reason %q", info[0].Info.States[0].Reason)
+	}
+}
+
+func TestSignedSnapshotTamperDetection(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	b, err := SignedSnapshot(testInfo(), priv)
+	if err != nil {
+		t.Fatalf("SignedSnapshot() error = %v", err)
+	}
+
+	// flip the unhealthy state to healthy in transit
+	var snapshot SignedInfoSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		t.Fatalf("failed to unmarshal snapshot: %v", err)
+	}
+	snapshot.Info = bytes.Replace(snapshot.Info, []byte(`"health":"Unhealthy"`), []byte(`"health":"Healthy"`), 1)
+	tampered, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+	}
+	if _, _, err := VerifySignedSnapshot(tampered, pub); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for tampered snapshot, got %v", err)
+	}
+
+	// replay the signed info with a newer time
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		t.Fatalf("failed to unmarshal snapshot: %v", err)
+	}
+	snapshot.Time = snapshot.Time.Add(time.Hour)
+	replayed, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+	}
+	if _, _, err := VerifySignedSnapshot(replayed, pub); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for the altered time, got %v", err)
+	}
+
+	// verify with a different key
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if _, _, err := VerifySignedSnapshot(b, otherPub); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for different key, got %v", err)
+	}
+}
+
+func TestSignedSnapshotInvalidKey(t *testing.T) {
+	if _, err := SignedSnapshot(testInfo(), ed25519.PrivateKey("short")); err == nil {
+		t.Error("expected error for invalid private key")
+	}
+	if _, _, err := VerifySignedSnapshot([]byte(`{}`), ed25519.PublicKey("short")); err == nil {
+		t.Error("expected error for invalid public key")
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	snapshot := Snapshot{
+		Header: SnapshotHeader{
+			Time:     now,
+			Version:  "v0.4.0",
+			Hostname: "gpu-node-1",
+		},
+		Info: LeptonInfo{
+			{
+				Component: "accelerator-nvidia-error-xid",
+				StartTime: now.Add(-time.Hour),
+				EndTime:   now,
+				Info: components.Info{
+					States: []components.State{
+						{Name: "error_xid", Healthy: false, Health: components.StateUnhealthy, Reason: "xid 79 detected"},
+					},
+					Events: []components.Event{
+						{
+							Time:      metav1.NewTime(now.Add(-time.Minute)),
+							Name:      "error_xid",
+							Type:      common.EventTypeCritical,
+							Message:   "xid 79",
+							ExtraInfo: map[string]string{"xid": "79"},
+						},
+					},
+					Metrics: []components.Metric{
+						{
+							Metric: components_metrics_state.Metric{
+								UnixSeconds: now.Unix(),
+								MetricName:  "xid_total",
+								Value:       1,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, snapshot); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+	// gzip magic header
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("expected gzipped snapshot, got %q", buf.Bytes()[:2])
+	}
+
+	loaded, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	// compare in JSON, as the decoded times are in the local time zone
+	expected, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, got) {
+		t.Errorf("snapshot mismatch\nexpected: %s\ngot: %s", expected, got)
+	}
+	if loaded.Header.Hostname != "gpu-node-1" || loaded.Header.Version != "v0.4.0" {
+		t.Errorf("unexpected header %+v", loaded.Header)
+	}
+}
+
+func TestLoadSnapshotInvalid(t *testing.T) {
+	if _, err := LoadSnapshot(bytes.NewReader([]byte(`{"header":{}}`))); err == nil {
+		t.Error("expected error for non-gzipped snapshot")
+	}
+}
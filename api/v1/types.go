@@ -28,9 +28,25 @@ type LeptonComponentMetrics struct {
 	Metrics   []components.Metric `json:"metrics"`
 }
 
+// Metric is a single metric data point with the name of the component that reported it.
+type Metric struct {
+	Component string `json:"component"`
+	components.Metric
+}
+
 type LeptonComponentInfo struct {
-	Component string          `json:"component"`
-	StartTime time.Time       `json:"startTime"`
-	EndTime   time.Time       `json:"endTime"`
-	Info      components.Info `json:"info"`
+	Component string            `json:"component" binding:"required"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime"`
+	Info      components.Info   `json:"info"`
+
+	// Set to the cursor of the next page of the events,
+	// if the event limit is set and more events exist.
+	NextEventCursor string `json:"nextEventCursor,omitempty"`
+
+	// Set to the error of the component, if any of its events, states,
+	// or metrics failed to be gathered (e.g., the component panicked).
+	// The rest of the info is still returned.
+	Error string `json:"error,omitempty"`
 }
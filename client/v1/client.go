@@ -3,9 +3,14 @@ package v1
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/leptonai/gpud/components/common"
 	"github.com/leptonai/gpud/internal/server"
 )
 
@@ -15,6 +20,20 @@ type Op struct {
 	requestContentType    string
 	requestAcceptEncoding string
 	components            map[string]any
+	labelSelector         string
+	since                 time.Time
+	bearerToken           string
+	eventLimit            int
+	eventCursors          []string
+	minSeverity           common.EventType
+
+	retryAttempts int
+	retryBackoff  time.Duration
+	retryBudget   *RetryBudget
+
+	clientCertFile string
+	clientKeyFile  string
+	caCertFile     string
 }
 
 type OpOption func(*Op)
@@ -25,9 +44,13 @@ func (op *Op) applyOpts(opts []OpOption) error {
 	}
 
 	if op.httpClient == nil {
+		tlsCfg, err := op.tlsConfig()
+		if err != nil {
+			return err
+		}
 		op.httpClient = &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				TLSClientConfig: tlsCfg,
 			},
 		}
 	}
@@ -36,9 +59,45 @@ func (op *Op) applyOpts(opts []OpOption) error {
 		op.checkInterval = time.Second
 	}
 
+	if op.retryBudget == nil {
+		op.retryBudget = DefaultRetryBudget
+	}
+
 	return nil
 }
 
+// tlsConfig returns the TLS config for the default HTTP client.
+// Skips the server certificate verification unless the CA certificate is set.
+func (op *Op) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: true}
+
+	if op.clientCertFile != "" || op.clientKeyFile != "" {
+		if op.clientCertFile == "" || op.clientKeyFile == "" {
+			return nil, errors.New("both client certificate and key files are required")
+		}
+		cert, err := tls.LoadX509KeyPair(op.clientCertFile, op.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if op.caCertFile != "" {
+		b, err := os.ReadFile(op.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("failed to parse CA certificate %q", op.caCertFile)
+		}
+		cfg.RootCAs = pool
+		cfg.InsecureSkipVerify = false
+	}
+
+	return cfg, nil
+}
+
 func WithHTTPClient(cli *http.Client) OpOption {
 	return func(op *Op) {
 		op.httpClient = cli
@@ -65,7 +124,9 @@ func WithRequestContentTypeJSON() OpOption {
 	}
 }
 
-// WithAcceptEncodingGzip requests gzip encoding for the response.
+// WithAcceptEncodingGzip reads the gzip-compressed body in the read functions (e.g., ReadInfo).
+// The get functions (e.g., GetInfo) always accept the gzip encoding
+// and decompress the response based on its "Content-Encoding", with or without this option.
 func WithAcceptEncodingGzip() OpOption {
 	return func(op *Op) {
 		op.requestAcceptEncoding = server.RequestHeaderEncodingGzip
@@ -80,3 +141,74 @@ func WithComponent(component string) OpOption {
 		op.components[component] = nil
 	}
 }
+
+// WithLabelSelector sets the label selector (e.g., "role=inference")
+// to filter the components by their labels.
+func WithLabelSelector(selector string) OpOption {
+	return func(op *Op) {
+		op.labelSelector = selector
+	}
+}
+
+// WithSince sets the start time of the events or metrics to query.
+// If not set, the server default is used.
+func WithSince(since time.Time) OpOption {
+	return func(op *Op) {
+		op.since = since
+	}
+}
+
+// WithEventLimit sets the maximum number of events per component to query the info,
+// paginating the events (see v1.LeptonComponentInfo.NextEventCursor).
+// If not set, all the events are returned.
+func WithEventLimit(limit int) OpOption {
+	return func(op *Op) {
+		op.eventLimit = limit
+	}
+}
+
+// WithEventCursor sets the cursor of the events page to query the info,
+// from the v1.LeptonComponentInfo.NextEventCursor of the previous page.
+// The cursor is per component, thus can be set multiple times to page
+// the events of multiple components.
+func WithEventCursor(cursor string) OpOption {
+	return func(op *Op) {
+		op.eventCursors = append(op.eventCursors, cursor)
+	}
+}
+
+// WithMinSeverity sets the minimum event type (e.g., common.EventTypeCritical)
+// of the events to query or to watch, filtered by the server.
+// If not set, all the events are returned.
+func WithMinSeverity(t common.EventType) OpOption {
+	return func(op *Op) {
+		op.minSeverity = t
+	}
+}
+
+// WithBearerToken sets the bearer token for the "Authorization" header
+// of all the requests (e.g., for the remote gpud behind a proxy).
+func WithBearerToken(token string) OpOption {
+	return func(op *Op) {
+		op.bearerToken = token
+	}
+}
+
+// WithClientCert sets the client certificate and key files (PEM encoded)
+// for the mutual TLS authentication.
+// Ignored if the HTTP client is set via WithHTTPClient.
+func WithClientCert(certFile, keyFile string) OpOption {
+	return func(op *Op) {
+		op.clientCertFile = certFile
+		op.clientKeyFile = keyFile
+	}
+}
+
+// WithCACert sets the CA certificate file (PEM encoded) to verify the server certificate.
+// If not set, the server certificate is not verified.
+// Ignored if the HTTP client is set via WithHTTPClient.
+func WithCACert(caFile string) OpOption {
+	return func(op *Op) {
+		op.caCertFile = caFile
+	}
+}
@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gpud-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return cert, certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := writeClientCert(t, dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`["cpu"]`)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600); err != nil {
+		t.Fatalf("failed to write CA certificate: %v", err)
+	}
+
+	ctx := context.Background()
+
+	comps, err := GetComponents(ctx, srv.URL, WithClientCert(certFile, keyFile), WithCACert(caFile))
+	if err != nil {
+		t.Fatalf("GetComponents() with client cert error = %v", err)
+	}
+	if len(comps) != 1 || comps[0] != "cpu" {
+		t.Errorf("unexpected components %v", comps)
+	}
+
+	if _, err := GetComponents(ctx, srv.URL, WithCACert(caFile)); err == nil {
+		t.Error("GetComponents() without client cert should return error")
+	}
+}
+
+func TestTLSOptionErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, certFile, keyFile := writeClientCert(t, dir)
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []OpOption
+	}{
+		{name: "missing key", opts: []OpOption{WithClientCert(certFile, "")}},
+		{name: "non-existent cert", opts: []OpOption{WithClientCert(filepath.Join(dir, "missing.crt"), keyFile)}},
+		{name: "invalid key", opts: []OpOption{WithClientCert(certFile, invalidFile)}},
+		{name: "non-existent CA", opts: []OpOption{WithCACert(filepath.Join(dir, "missing.crt"))}},
+		{name: "invalid CA", opts: []OpOption{WithCACert(invalidFile)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &Op{}
+			if err := op.applyOpts(tt.opts); err == nil {
+				t.Error("expected error from applyOpts")
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	client_v1 "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/errdefs"
+	"github.com/leptonai/gpud/log"
+)
+
+func main() {
+	baseURL := "https://localhost:15132"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	metrics, err := client_v1.GetMetrics(
+		ctx,
+		baseURL,
+		client_v1.WithComponent("accelerator-nvidia-temperature"),
+		client_v1.WithSince(time.Now().Add(-10*time.Minute)),
+	)
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotFound) {
+			log.Logger.Warnw("component not found")
+			return
+		}
+
+		log.Logger.Errorw("error fetching component metrics", "error", err)
+		return
+	}
+
+	for _, m := range metrics {
+		log.Logger.Infof("component: %q, metric: %q (%q), value: %f, time: %v\n", m.Component, m.MetricName, m.MetricSecondaryName, m.Value, time.Unix(m.UnixSeconds, 0))
+	}
+}
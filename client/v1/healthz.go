@@ -22,17 +22,20 @@ func CheckHealthz(ctx context.Context, addr string, opts ...OpOption) error {
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
+	}
 
 	exp, err := server.DefaultHealthz.JSON()
 	if err != nil {
 		return fmt.Errorf("failed to marshal expected healthz response: %w", err)
 	}
 
-	return checkHealthz(op.httpClient, req, exp)
+	return checkHealthz(op, req, exp)
 }
 
-func checkHealthz(cli *http.Client, req *http.Request, exp []byte) error {
-	resp, err := cli.Do(req)
+func checkHealthz(op *Op, req *http.Request, exp []byte) error {
+	resp, err := op.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to make request to /healthz: %w", err)
 	}
@@ -64,6 +67,9 @@ func BlockUntilServerReady(ctx context.Context, addr string, opts ...OpOption) e
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
+	}
 
 	exp, err := server.DefaultHealthz.JSON()
 	if err != nil {
@@ -75,7 +81,7 @@ func BlockUntilServerReady(ctx context.Context, addr string, opts ...OpOption) e
 	for range 30 {
 		select {
 		case <-ticker.C:
-			if err := checkHealthz(op.httpClient, req, exp); err == nil {
+			if err := checkHealthz(op, req, exp); err == nil {
 				return nil
 			}
 		case <-ctx.Done():
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/errdefs"
+	"github.com/leptonai/gpud/internal/server"
+)
+
+// Probe runs the on-demand probe of the component in the server.
+// The server default timeout is used if the timeout is zero.
+// Returns errdefs.ErrNotFound if the component is not found,
+// and errdefs.ErrNotImplemented if the component does not support the probe.
+func Probe(ctx context.Context, addr string, component string, timeout time.Duration, opts ...OpOption) (components.ProbeResult, error) {
+	op := &Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return components.ProbeResult{}, err
+	}
+
+	reqURL, err := url.Parse(fmt.Sprintf("%s/v1%s", addr, server.URLPathProbe))
+	if err != nil {
+		return components.ProbeResult{}, err
+	}
+	q := reqURL.Query()
+	q.Add("component", component)
+	if timeout > 0 {
+		q.Add("timeout", timeout.String())
+	}
+	reqURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return components.ProbeResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
+	}
+
+	resp, err := op.do(req)
+	if err != nil {
+		return components.ProbeResult{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return components.ProbeResult{}, errdefs.ErrNotFound
+	case http.StatusNotImplemented:
+		return components.ProbeResult{}, errdefs.ErrNotImplemented
+	default:
+		return components.ProbeResult{}, errors.New("server not ready, response not 200")
+	}
+
+	var result components.ProbeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return components.ProbeResult{}, fmt.Errorf("failed to decode probe result: %w", err)
+	}
+	return result, nil
+}
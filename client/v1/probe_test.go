@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+)
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/probe" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("component") {
+		case "disk":
+			if got := r.URL.Query().Get("timeout"); got != "5s" {
+				t.Errorf("expected timeout 5s, got %q", got)
+			}
+			_, _ = w.Write([]byte(`{"component":"disk","passed":true,"latency":"1ms"}`))
+		case "os":
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	result, err := Probe(context.Background(), srv.URL, "disk", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Component != "disk" || result.Latency.Duration != time.Millisecond {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := Probe(context.Background(), srv.URL, "os", 0); !errors.Is(err, errdefs.ErrNotImplemented) {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+	if _, err := Probe(context.Background(), srv.URL, "unknown", 0); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
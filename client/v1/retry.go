@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is returned when a request failed and the retry budget
+// has no tokens left to retry it, failing fast without backing off.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+const (
+	// DefaultRetryBudgetCapacity is the max number of retries
+	// the default retry budget allows in a burst.
+	DefaultRetryBudgetCapacity = 10
+	// DefaultRetryBudgetRefillInterval is the interval to refill one retry token
+	// of the default retry budget.
+	DefaultRetryBudgetRefillInterval = time.Second
+)
+
+// DefaultRetryBudget is the retry budget shared by all the calls with retries enabled
+// (see WithRetry), unless overwritten with WithRetryBudget.
+var DefaultRetryBudget = NewRetryBudget(DefaultRetryBudgetCapacity, DefaultRetryBudgetRefillInterval)
+
+// RetryBudget is a token bucket that bounds the number of retries
+// across the concurrent calls, so that the client does not keep hammering
+// a gpud daemon that is down or overloaded.
+// Only the retries take a token, not the first attempt of each call.
+type RetryBudget struct {
+	mu             sync.Mutex
+	capacity       int
+	refillInterval time.Duration
+	tokens         int
+	lastRefill     time.Time
+
+	now func() time.Time
+}
+
+// NewRetryBudget creates a retry budget that allows up to "capacity" retries in a burst
+// and refills one token every "refillInterval".
+func NewRetryBudget(capacity int, refillInterval time.Duration) *RetryBudget {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if refillInterval <= 0 {
+		refillInterval = DefaultRetryBudgetRefillInterval
+	}
+	b := &RetryBudget{
+		capacity:       capacity,
+		refillInterval: refillInterval,
+		tokens:         capacity,
+		now:            time.Now,
+	}
+	b.lastRefill = b.now()
+	return b
+}
+
+// TryTake takes a retry token, and returns false if the budget is exhausted.
+func (b *RetryBudget) TryTake() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refillLocked()
+	if b.tokens == 0 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Available returns the number of retry tokens currently available.
+func (b *RetryBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refillLocked()
+	return b.tokens
+}
+
+func (b *RetryBudget) refillLocked() {
+	now := b.now()
+	refills := int(now.Sub(b.lastRefill) / b.refillInterval)
+	if refills <= 0 {
+		return
+	}
+	b.tokens = min(b.capacity, b.tokens+refills)
+	b.lastRefill = b.lastRefill.Add(time.Duration(refills) * b.refillInterval)
+	if b.tokens == b.capacity {
+		// no need to carry over the partial interval when the bucket is full
+		b.lastRefill = now
+	}
+}
+
+// WithRetry retries the failed requests (e.g., connection refused, 5xx responses)
+// up to "attempts" in total, waiting "backoff" between the attempts.
+// Each retry takes a token from the retry budget (see WithRetryBudget),
+// and the call fails fast with ErrRetryBudgetExhausted if the budget is exhausted.
+func WithRetry(attempts int, backoff time.Duration) OpOption {
+	return func(op *Op) {
+		op.retryAttempts = attempts
+		op.retryBackoff = backoff
+	}
+}
+
+// WithRetryBudget sets the retry budget to share across the calls.
+// If not set, DefaultRetryBudget is used.
+func WithRetryBudget(budget *RetryBudget) OpOption {
+	return func(op *Op) {
+		op.retryBudget = budget
+	}
+}
+
+// retryable returns true if the request should be retried.
+// 501 is not retried, as the server does not support the request.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// do sends the request, retrying with the retry budget if enabled.
+// The requests are sent without bodies, so they can be sent as is multiple times.
+func (op *Op) do(req *http.Request) (*http.Response, error) {
+	resp, err := op.httpClient.Do(req)
+	for attempt := 1; attempt < op.retryAttempts && retryable(resp, err); attempt++ {
+		if !op.retryBudget.TryTake() {
+			if err == nil {
+				resp.Body.Close()
+				err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+			}
+			return nil, fmt.Errorf("%w (last error: %v)", ErrRetryBudgetExhausted, err)
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if err := sleepWithContext(req.Context(), op.retryBackoff); err != nil {
+			return nil, err
+		}
+		resp, err = op.httpClient.Do(req)
+	}
+	return resp, err
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryBudget returns a retry budget with the clock controlled by the test.
+func newTestRetryBudget(capacity int, refillInterval time.Duration) (*RetryBudget, func(time.Duration)) {
+	var mu sync.Mutex
+	now := time.Unix(0, 0)
+
+	b := NewRetryBudget(capacity, refillInterval)
+	b.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	b.lastRefill = b.now()
+
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+	return b, advance
+}
+
+func TestRetryBudget(t *testing.T) {
+	b, advance := newTestRetryBudget(2, time.Second)
+
+	if !b.TryTake() || !b.TryTake() {
+		t.Fatal("expected 2 tokens")
+	}
+	if b.TryTake() {
+		t.Fatal("expected budget exhausted")
+	}
+
+	advance(500 * time.Millisecond)
+	if b.TryTake() {
+		t.Fatal("expected budget exhausted before the refill interval")
+	}
+
+	advance(500 * time.Millisecond)
+	if got := b.Available(); got != 1 {
+		t.Fatalf("expected 1 token after refill, got %d", got)
+	}
+
+	// never refills beyond the capacity
+	advance(time.Hour)
+	if got := b.Available(); got != 2 {
+		t.Fatalf("expected 2 tokens, got %d", got)
+	}
+}
+
+func TestGetComponentsRetry(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["comp1"]`))
+	}))
+	defer srv.Close()
+
+	b, _ := newTestRetryBudget(10, time.Second)
+	comps, err := GetComponents(context.Background(), srv.URL, WithRetry(3, 0), WithRetryBudget(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comps) != 1 || comps[0] != "comp1" {
+		t.Fatalf("unexpected components %v", comps)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+	if got := b.Available(); got != 8 {
+		t.Fatalf("expected 8 tokens left, got %d", got)
+	}
+}
+
+func TestGetComponentsRetryBudgetExhausted(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	b, advance := newTestRetryBudget(4, time.Second)
+	opts := []OpOption{WithRetry(3, 10*time.Millisecond), WithRetryBudget(b)}
+
+	// saturate the budget with the concurrent calls
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := GetComponents(context.Background(), srv.URL, opts...); err == nil {
+				t.Error("expected error")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// each call makes one attempt, and only 4 retries are allowed in total
+	if got := hits.Load(); got != 10+4 {
+		t.Fatalf("expected 14 requests, got %d", got)
+	}
+	if got := b.Available(); got != 0 {
+		t.Fatalf("expected budget exhausted, got %d tokens", got)
+	}
+
+	// fails fast without backing off
+	hits.Store(0)
+	start := time.Now()
+	_, err := GetComponents(context.Background(), srv.URL, WithRetry(3, time.Minute), WithRetryBudget(b))
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected fast fail, took %v", elapsed)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+
+	// recovers after the budget refills
+	advance(2 * time.Second)
+	hits.Store(0)
+	_, err = GetComponents(context.Background(), srv.URL, opts...)
+	if err == nil || errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected the retries exhausted with the server error, got %v", err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestGetComponentsNoRetry(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	b, _ := newTestRetryBudget(4, time.Second)
+	if _, err := GetComponents(context.Background(), srv.URL, WithRetryBudget(b)); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+	if got := b.Available(); got != 4 {
+		t.Fatalf("expected budget untouched, got %d tokens", got)
+	}
+}
@@ -1,15 +1,21 @@
 package v1
 
 import (
+	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	v1 "github.com/leptonai/gpud/api/v1"
 	"github.com/leptonai/gpud/errdefs"
@@ -17,6 +23,40 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// RequestHeaderAuthorization is the header to set the bearer token.
+const RequestHeaderAuthorization = "Authorization"
+
+// decodeResponseBody returns the response body decompressed by its "Content-Encoding",
+// as the server only compresses the response large enough.
+func decodeResponseBody(resp *http.Response) (io.ReadCloser, error) {
+	switch resp.Header.Get(server.ResponseHeaderContentEncoding) {
+	case server.RequestHeaderEncodingGzip:
+		gr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gr, nil
+	case server.RequestHeaderEncodingDeflate:
+		zr, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create deflate reader: %w", err)
+		}
+		return zr, nil
+	case "":
+		return io.NopCloser(resp.Body), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", resp.Header.Get(server.ResponseHeaderContentEncoding))
+	}
+}
+
+// decodedOpts returns the options to read the body from decodeResponseBody,
+// so that the read functions do not decompress it again.
+func decodedOpts(opts []OpOption) []OpOption {
+	return append(opts[:len(opts):len(opts)], func(op *Op) {
+		op.requestAcceptEncoding = ""
+	})
+}
+
 func GetComponents(ctx context.Context, addr string, opts ...OpOption) ([]string, error) {
 	op := &Op{}
 	if err := op.applyOpts(opts); err != nil {
@@ -30,11 +70,13 @@ func GetComponents(ctx context.Context, addr string, opts ...OpOption) ([]string
 	if op.requestContentType != "" {
 		req.Header.Set(server.RequestHeaderContentType, op.requestContentType)
 	}
-	if op.requestAcceptEncoding != "" {
-		req.Header.Set(server.RequestHeaderAcceptEncoding, op.requestAcceptEncoding)
+	// always accept the compressed response, decoded by the response "Content-Encoding"
+	req.Header.Set(server.RequestHeaderAcceptEncoding, server.RequestHeaderEncodingGzip)
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
 	}
 
-	resp, err := op.httpClient.Do(req)
+	resp, err := op.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -43,7 +85,13 @@ func GetComponents(ctx context.Context, addr string, opts ...OpOption) ([]string
 		return nil, errors.New("server not ready, response not 200")
 	}
 
-	return ReadComponents(resp.Body, opts...)
+	rd, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return ReadComponents(rd, decodedOpts(opts)...)
 }
 
 func ReadComponents(rd io.Reader, opts ...OpOption) ([]string, error) {
@@ -118,6 +166,15 @@ func GetInfo(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonInfo,
 		}
 		q.Add("components", strings.Join(components, ","))
 	}
+	if op.labelSelector != "" {
+		q.Add("label", op.labelSelector)
+	}
+	if op.eventLimit > 0 {
+		q.Add("eventLimit", strconv.Itoa(op.eventLimit))
+	}
+	for _, cursor := range op.eventCursors {
+		q.Add("eventCursor", cursor)
+	}
 	reqURL.RawQuery = q.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
@@ -127,11 +184,13 @@ func GetInfo(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonInfo,
 	if op.requestContentType != "" {
 		req.Header.Set(server.RequestHeaderContentType, op.requestContentType)
 	}
-	if op.requestAcceptEncoding != "" {
-		req.Header.Set(server.RequestHeaderAcceptEncoding, op.requestAcceptEncoding)
+	// always accept the compressed response, decoded by the response "Content-Encoding"
+	req.Header.Set(server.RequestHeaderAcceptEncoding, server.RequestHeaderEncodingGzip)
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
 	}
 
-	resp, err := op.httpClient.Do(req)
+	resp, err := op.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -140,7 +199,13 @@ func GetInfo(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonInfo,
 		return nil, errors.New("server not ready, response not 200")
 	}
 
-	return ReadInfo(resp.Body, opts...)
+	rd, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return ReadInfo(rd, decodedOpts(opts)...)
 }
 
 func ReadInfo(rd io.Reader, opts ...OpOption) (v1.LeptonInfo, error) {
@@ -225,11 +290,13 @@ func GetStates(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonSta
 	if op.requestContentType != "" {
 		req.Header.Set(server.RequestHeaderContentType, op.requestContentType)
 	}
-	if op.requestAcceptEncoding != "" {
-		req.Header.Set(server.RequestHeaderAcceptEncoding, op.requestAcceptEncoding)
+	// always accept the compressed response, decoded by the response "Content-Encoding"
+	req.Header.Set(server.RequestHeaderAcceptEncoding, server.RequestHeaderEncodingGzip)
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
 	}
 
-	resp, err := op.httpClient.Do(req)
+	resp, err := op.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -242,7 +309,13 @@ func GetStates(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonSta
 		return nil, errors.New("server not ready, response not 200")
 	}
 
-	return ReadStates(resp.Body, opts...)
+	rd, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return ReadStates(rd, decodedOpts(opts)...)
 }
 
 func ReadStates(rd io.Reader, opts ...OpOption) (v1.LeptonStates, error) {
@@ -305,27 +378,58 @@ func GetEvents(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonEve
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/events", addr), nil)
+	reqURL, err := url.Parse(fmt.Sprintf("%s/v1/events", addr))
+	if err != nil {
+		return nil, err
+	}
+	q := reqURL.Query()
+	if len(op.components) > 0 {
+		components := make([]string, 0, len(op.components))
+		for component := range op.components {
+			components = append(components, component)
+		}
+		q.Add("components", strings.Join(components, ","))
+	}
+	if !op.since.IsZero() {
+		q.Add("startTime", strconv.FormatInt(op.since.Unix(), 10))
+	}
+	if op.minSeverity != "" {
+		q.Add("minSeverity", string(op.minSeverity))
+	}
+	reqURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	if op.requestContentType != "" {
 		req.Header.Set(server.RequestHeaderContentType, op.requestContentType)
 	}
-	if op.requestAcceptEncoding != "" {
-		req.Header.Set(server.RequestHeaderAcceptEncoding, op.requestAcceptEncoding)
+	// always accept the compressed response, decoded by the response "Content-Encoding"
+	req.Header.Set(server.RequestHeaderAcceptEncoding, server.RequestHeaderEncodingGzip)
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
 	}
 
-	resp, err := op.httpClient.Do(req)
+	resp, err := op.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errdefs.ErrNotFound
+		}
 		return nil, errors.New("server not ready, response not 200")
 	}
 
-	return ReadEvents(resp.Body, opts...)
+	rd, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return ReadEvents(rd, decodedOpts(opts)...)
 }
 
 func ReadEvents(rd io.Reader, opts ...OpOption) (v1.LeptonEvents, error) {
@@ -382,33 +486,143 @@ func ReadEvents(rd io.Reader, opts ...OpOption) (v1.LeptonEvents, error) {
 	return evs, nil
 }
 
-func GetMetrics(ctx context.Context, addr string, opts ...OpOption) (v1.LeptonMetrics, error) {
+// GetMetrics returns the metrics of the components (all if not specified),
+// flattened into a single slice in the order returned by the server.
+// Use WithSince to query the metrics since the given time (server default is 30 minutes).
+func GetMetrics(ctx context.Context, addr string, opts ...OpOption) ([]v1.Metric, error) {
 	op := &Op{}
 	if err := op.applyOpts(opts); err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/metrics", addr), nil)
+	reqURL, err := url.Parse(fmt.Sprintf("%s/v1/metrics", addr))
+	if err != nil {
+		return nil, err
+	}
+	q := reqURL.Query()
+	if len(op.components) > 0 {
+		components := make([]string, 0, len(op.components))
+		for component := range op.components {
+			components = append(components, component)
+		}
+		q.Add("components", strings.Join(components, ","))
+	}
+	if !op.since.IsZero() {
+		q.Add("since", op.since.UTC().Format(time.RFC3339Nano))
+	}
+	reqURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	if op.requestContentType != "" {
 		req.Header.Set(server.RequestHeaderContentType, op.requestContentType)
 	}
-	if op.requestAcceptEncoding != "" {
-		req.Header.Set(server.RequestHeaderAcceptEncoding, op.requestAcceptEncoding)
+	// always accept the compressed response, decoded by the response "Content-Encoding"
+	req.Header.Set(server.RequestHeaderAcceptEncoding, server.RequestHeaderEncodingGzip)
+	if op.bearerToken != "" {
+		req.Header.Set(RequestHeaderAuthorization, "Bearer "+op.bearerToken)
 	}
 
-	resp, err := op.httpClient.Do(req)
+	resp, err := op.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errdefs.ErrNotFound
+		}
 		return nil, errors.New("server not ready, response not 200")
 	}
 
-	return ReadMetrics(resp.Body, opts...)
+	rd, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	metrics, err := ReadMetrics(rd, decodedOpts(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]v1.Metric, 0)
+	for _, m := range metrics {
+		for _, metric := range m.Metrics {
+			ret = append(ret, v1.Metric{
+				Component: m.Component,
+				Metric:    metric,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// MetricsCSVHeader is the header row of the metrics in the CSV format.
+var MetricsCSVHeader = []string{"timestamp", "component", "metric", "labels", "value"}
+
+// GetMetricsCSV returns the metrics of the components (all if not specified)
+// in the CSV format, one row per metric, with the MetricsCSVHeader header row.
+// The labels are the metric secondary name and the extra info, serialized as
+// the "key=value" pairs sorted by the key and joined by ";".
+func GetMetricsCSV(ctx context.Context, addr string, opts ...OpOption) ([]byte, error) {
+	metrics, err := GetMetrics(ctx, addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return MetricsToCSV(metrics)
+}
+
+// MetricsToCSV flattens the metrics into the CSV rows.
+func MetricsToCSV(metrics []v1.Metric) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	if err := w.Write(MetricsCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, m := range metrics {
+		row := []string{
+			time.Unix(m.UnixSeconds, 0).UTC().Format(time.RFC3339),
+			m.Component,
+			m.MetricName,
+			metricLabels(m),
+			strconv.FormatFloat(m.Value, 'f', -1, 64),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// metricLabels serializes the labels sorted by the key,
+// so that the same metric always has the same labels.
+func metricLabels(m v1.Metric) string {
+	labels := make(map[string]string, len(m.ExtraInfo)+1)
+	for k, v := range m.ExtraInfo {
+		labels[k] = v
+	}
+	if m.MetricSecondaryName != "" {
+		labels["metric_secondary_name"] = m.MetricSecondaryName
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ";")
 }
 
 func ReadMetrics(rd io.Reader, opts ...OpOption) (v1.LeptonMetrics, error) {
@@ -0,0 +1,380 @@
+package v1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	"github.com/leptonai/gpud/components"
+)
+
+func TestGetInfoWithLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []OpOption
+		expected string
+	}{
+		{name: "no selector", opts: nil, expected: ""},
+		{name: "matching selector", opts: []OpOption{WithLabelSelector("role=inference")}, expected: "role=inference"},
+		{name: "non-matching selector", opts: []OpOption{WithLabelSelector("role=unknown")}, expected: "role=unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/info" {
+					t.Errorf("expected /v1/info path, got %s", r.URL.Path)
+					http.NotFound(w, r)
+					return
+				}
+				if got := r.URL.Query().Get("label"); got != tt.expected {
+					t.Errorf("expected label %q, got %q", tt.expected, got)
+				}
+
+				body := `[{"component":"cpu","labels":{"role":"inference"}}]`
+				if tt.expected == "role=unknown" {
+					body = `[]`
+				}
+				w.WriteHeader(http.StatusOK)
+				if _, err := w.Write([]byte(body)); err != nil {
+					t.Errorf("error writing response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			info, err := GetInfo(context.Background(), srv.URL, tt.opts...)
+			if err != nil {
+				t.Fatalf("GetInfo() error = %v", err)
+			}
+			if tt.expected == "role=unknown" {
+				if len(info) != 0 {
+					t.Errorf("expected no component, got %d", len(info))
+				}
+				return
+			}
+			if len(info) != 1 || info[0].Component != "cpu" {
+				t.Errorf("unexpected info %+v", info)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []OpOption
+		expected string
+	}{
+		{name: "without token", opts: nil, expected: ""},
+		{name: "with token", opts: []OpOption{WithBearerToken("test-token")}, expected: "Bearer test-token"},
+		{name: "with token and component", opts: []OpOption{WithBearerToken("test-token"), WithComponent("cpu")}, expected: "Bearer test-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get(RequestHeaderAuthorization); got != tt.expected {
+					t.Errorf("expected authorization header %q, got %q (path %s)", tt.expected, got, r.URL.Path)
+				}
+				if _, ok := r.Header[RequestHeaderAuthorization]; tt.expected == "" && ok {
+					t.Errorf("expected no authorization header (path %s)", r.URL.Path)
+				}
+				w.WriteHeader(http.StatusOK)
+				if _, err := w.Write([]byte(`[]`)); err != nil {
+					t.Errorf("error writing response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+			if _, err := GetInfo(ctx, srv.URL, tt.opts...); err != nil {
+				t.Errorf("GetInfo() error = %v", err)
+			}
+			if _, err := GetStates(ctx, srv.URL, tt.opts...); err != nil {
+				t.Errorf("GetStates() error = %v", err)
+			}
+			if _, err := GetEvents(ctx, srv.URL, tt.opts...); err != nil {
+				t.Errorf("GetEvents() error = %v", err)
+			}
+			if _, err := GetMetrics(ctx, srv.URL, tt.opts...); err != nil {
+				t.Errorf("GetMetrics() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGetEventsQuery(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/events" {
+			t.Errorf("expected /v1/events path, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("components"); got != "cpu" {
+			t.Errorf("expected components %q, got %q", "cpu", got)
+		}
+		if got := r.URL.Query().Get("startTime"); got != "1700000000" {
+			t.Errorf("expected startTime %q, got %q", "1700000000", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"component":"cpu","events":[{"name":"test"}]}]`)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	evs, err := GetEvents(context.Background(), srv.URL, WithComponent("cpu"), WithSince(since))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(evs) != 1 || len(evs[0].Events) != 1 || evs[0].Events[0].Name != "test" {
+		t.Errorf("unexpected events %+v", evs)
+	}
+}
+
+func TestGetEventsOccurrences(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"component":"accelerator-nvidia-error-xid","events":[{"time":"2024-11-01T10:00:00Z","name":"error_xid","first_seen":"2024-11-01T10:00:00Z","last_seen":"2024-11-01T10:04:00Z","occurrences":42}]}]`)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	evs, err := GetEvents(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(evs) != 1 || len(evs[0].Events) != 1 {
+		t.Fatalf("unexpected events %+v", evs)
+	}
+	ev := evs[0].Events[0]
+	if ev.Occurrences != 42 {
+		t.Errorf("expected 42 occurrences, got %d", ev.Occurrences)
+	}
+	if ev.FirstSeen == nil || !ev.FirstSeen.Time.Equal(time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first seen %v", ev.FirstSeen)
+	}
+	if ev.LastSeen == nil || !ev.LastSeen.Time.Equal(time.Date(2024, 11, 1, 10, 4, 0, 0, time.UTC)) {
+		t.Errorf("unexpected last seen %v", ev.LastSeen)
+	}
+}
+
+func TestGetMetricsQuery(t *testing.T) {
+	since := time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/metrics" {
+			t.Errorf("expected /v1/metrics path, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("components"); got != "cpu" {
+			t.Errorf("expected components %q, got %q", "cpu", got)
+		}
+		if got := r.URL.Query().Get("since"); got != "2024-11-01T10:00:00Z" {
+			t.Errorf("expected since %q, got %q", "2024-11-01T10:00:00Z", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"component":"cpu","metrics":[{"unix_seconds":1730455200,"metric_name":"used_percent","value":10},{"unix_seconds":1730455260,"metric_name":"used_percent","value":20}]}]`)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	metrics, err := GetMetrics(context.Background(), srv.URL, WithComponent("cpu"), WithSince(since))
+	if err != nil {
+		t.Fatalf("GetMetrics() error = %v", err)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("expected 2 metrics, got %+v", metrics)
+	}
+	for i, expected := range []float64{10, 20} {
+		if metrics[i].Component != "cpu" || metrics[i].MetricName != "used_percent" || metrics[i].Value != expected {
+			t.Errorf("unexpected metric %+v", metrics[i])
+		}
+	}
+}
+
+func TestGetMetricsCSV(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[
+{"component":"cpu","metrics":[{"unix_seconds":1730455200,"metric_name":"used_percent","value":10.5}]},
+{"component":"accelerator-nvidia-temperature","metrics":[{"unix_seconds":1730455260,"metric_name":"current_celsius","metric_secondary_name":"GPU-0","value":45,"extra_info":{"uuid":"GPU-0","bus_id":"0000:01:00.0"}}]}
+]`)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	b, err := GetMetricsCSV(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetMetricsCSV() error = %v", err)
+	}
+
+	expected := `timestamp,component,metric,labels,value
+2024-11-01T10:00:00Z,cpu,used_percent,,10.5
+2024-11-01T10:01:00Z,accelerator-nvidia-temperature,current_celsius,bus_id=0000:01:00.0;metric_secondary_name=GPU-0;uuid=GPU-0,45
+`
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(b))
+	}
+}
+
+func TestMetricsToCSVEmpty(t *testing.T) {
+	b, err := MetricsToCSV(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "timestamp,component,metric,labels,value\n" {
+		t.Errorf("unexpected csv %q", string(b))
+	}
+}
+
+func TestGetInfoEventPage(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []OpOption
+		expectedLimit  string
+		expectedCursor string
+	}{
+		{name: "no pagination", opts: nil},
+		{name: "first page", opts: []OpOption{WithEventLimit(100)}, expectedLimit: "100"},
+		{name: "next page", opts: []OpOption{WithEventLimit(100), WithEventCursor("MTAw")}, expectedLimit: "100", expectedCursor: "MTAw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if _, ok := q["eventLimit"]; ok != (tt.expectedLimit != "") {
+					t.Errorf("unexpected eventLimit presence in %q", r.URL.RawQuery)
+				}
+				if got := q.Get("eventLimit"); got != tt.expectedLimit {
+					t.Errorf("expected eventLimit %q, got %q", tt.expectedLimit, got)
+				}
+				if got := q.Get("eventCursor"); got != tt.expectedCursor {
+					t.Errorf("expected eventCursor %q, got %q", tt.expectedCursor, got)
+				}
+				w.WriteHeader(http.StatusOK)
+				if _, err := w.Write([]byte(`[{"component":"cpu","nextEventCursor":"MjAw"}]`)); err != nil {
+					t.Errorf("error writing response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			info, err := GetInfo(context.Background(), srv.URL, tt.opts...)
+			if err != nil {
+				t.Fatalf("GetInfo() error = %v", err)
+			}
+			if len(info) != 1 || info[0].NextEventCursor != "MjAw" {
+				t.Errorf("unexpected info %+v", info)
+			}
+		})
+	}
+}
+
+func TestGetInfoCompressed(t *testing.T) {
+	events := make([]components.Event, 0, 100)
+	for i := 0; i < 100; i++ {
+		events = append(events, components.Event{Name: fmt.Sprintf("event-%d", i), Message: "the same repetitive event message"})
+	}
+	large, err := json.Marshal(v1.LeptonInfo{{Component: "cpu", Info: components.Info{Events: events}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []OpOption
+		encoding string
+		body     []byte
+		events   int
+	}{
+		{name: "gzip", encoding: "gzip", body: large, events: 100},
+		{name: "deflate", encoding: "deflate", body: large, events: 100},
+		{name: "uncompressed small body", body: []byte(`[{"component":"cpu"}]`)},
+		{name: "uncompressed small body with gzip option", opts: []OpOption{WithAcceptEncodingGzip()}, body: []byte(`[{"component":"cpu"}]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+					t.Errorf("expected Accept-Encoding gzip, got %q", got)
+				}
+
+				var buf bytes.Buffer
+				var cw io.WriteCloser
+				switch tt.encoding {
+				case "gzip":
+					cw = gzip.NewWriter(&buf)
+				case "deflate":
+					cw = zlib.NewWriter(&buf)
+				}
+				if cw != nil {
+					if _, err := cw.Write(tt.body); err != nil {
+						t.Error(err)
+					}
+					if err := cw.Close(); err != nil {
+						t.Error(err)
+					}
+					w.Header().Set("Content-Encoding", tt.encoding)
+				} else {
+					buf.Write(tt.body)
+				}
+				w.WriteHeader(http.StatusOK)
+				if _, err := w.Write(buf.Bytes()); err != nil {
+					t.Errorf("error writing response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			info, err := GetInfo(context.Background(), srv.URL, tt.opts...)
+			if err != nil {
+				t.Fatalf("GetInfo() error = %v", err)
+			}
+			if len(info) != 1 || info[0].Component != "cpu" || len(info[0].Info.Events) != tt.events {
+				t.Errorf("unexpected info %+v", info)
+			}
+		})
+	}
+}
+
+func TestGetStatesStale(t *testing.T) {
+	lastUpdated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/states" {
+			t.Errorf("expected /v1/states path, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		body := `[{"component":"accelerator-nvidia-power","states":[{"name":"power","healthy":true,"last_updated":"2024-01-01T00:00:00Z","stale":true}]}]`
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("error writing response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	states, err := GetStates(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetStates() error = %v", err)
+	}
+	if len(states) != 1 || len(states[0].States) != 1 {
+		t.Fatalf("unexpected states %+v", states)
+	}
+	s := states[0].States[0]
+	if !s.Stale || !s.Healthy {
+		t.Errorf("expected healthy stale state, got %+v", s)
+	}
+	if !s.LastUpdated.Time.Equal(lastUpdated) {
+		t.Errorf("expected last updated %v, got %v", lastUpdated, s.LastUpdated)
+	}
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+)
+
+// WatchedEvent is the component event streamed by WatchHealthStates.
+type WatchedEvent struct {
+	Component string           `json:"component"`
+	Event     components.Event `json:"event"`
+}
+
+// WatchHealthStates streams the new component events (i.e., the health state transitions),
+// polling the server every check interval (see WithCheckInterval) until the context is done.
+// Use WithMinSeverity to only stream the events at or above the severity
+// (e.g., common.EventTypeCritical for the critical and fatal events),
+// and WithSince to also stream the events since the given time (defaults to now).
+// The failed polls are retried at the next interval.
+// The returned channel is closed when the context is done.
+func WatchHealthStates(ctx context.Context, addr string, opts ...OpOption) (<-chan WatchedEvent, error) {
+	op := &Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return nil, err
+	}
+
+	since := op.since
+	if since.IsZero() {
+		since = time.Now().UTC()
+	}
+	// the event times are in seconds (metav1.Time)
+	since = since.Truncate(time.Second)
+
+	ch := make(chan WatchedEvent, 16)
+	go func() {
+		defer close(ch)
+
+		// the server queries the events since the start time in seconds,
+		// thus the events in the last second are returned again in the next poll
+		// (deduplicated by the seen keys)
+		seen := make(map[string]time.Time)
+
+		ticker := time.NewTicker(op.checkInterval)
+		defer ticker.Stop()
+		for {
+			latest := since
+			// cap the caller's options to not write into their backing array
+			evs, err := GetEvents(ctx, addr, append(opts[:len(opts):len(opts)], WithSince(since))...)
+			if err == nil {
+				for _, compEvents := range evs {
+					for _, ev := range compEvents.Events {
+						// in case the server does not support the severity filtering
+						if op.minSeverity != "" && ev.Type.Severity() < op.minSeverity.Severity() {
+							continue
+						}
+						// in case the server does not filter by the start time,
+						// as the seen keys before the start time are pruned
+						if ev.Time.Time.Before(since) {
+							continue
+						}
+
+						key := fmt.Sprintf("%s/%s/%s/%d", compEvents.Component, ev.Name, ev.Message, ev.Time.UnixNano())
+						if _, ok := seen[key]; ok {
+							continue
+						}
+						seen[key] = ev.Time.Time
+
+						select {
+						case ch <- WatchedEvent{Component: compEvents.Component, Event: ev}:
+						case <-ctx.Done():
+							return
+						}
+
+						if ev.Time.Time.After(latest) {
+							latest = ev.Time.Time
+						}
+					}
+				}
+			}
+
+			since = latest.Truncate(time.Second)
+
+			// only keep the keys that can be returned again
+			for key, t := range seen {
+				if t.Before(since) {
+					delete(seen, key)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestWatchHealthStatesMinSeverity(t *testing.T) {
+	start := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+
+	var mu sync.Mutex
+	var minSeverities []string
+	polls := 0
+
+	// fake stream that does not filter by the severity,
+	// returning the previous events again with a new one on each poll
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		minSeverities = append(minSeverities, r.URL.Query().Get("minSeverity"))
+		polls++
+		n := polls
+		mu.Unlock()
+
+		var events []components.Event
+		for i := 0; i < n; i++ {
+			ts := metav1.NewTime(start.Add(time.Duration(i) * time.Second))
+			events = append(events,
+				components.Event{Time: ts, Name: "warning", Type: common.EventTypeWarning},
+				components.Event{Time: ts, Name: "fatal", Type: common.EventTypeFatal},
+			)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v1.LeptonEvents{{Component: "comp1", Events: events}})
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := WatchHealthStates(ctx, srv.URL,
+		WithMinSeverity(common.EventTypeCritical),
+		WithSince(start),
+		WithCheckInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []WatchedEvent
+	for len(got) < 3 {
+		select {
+		case ev := <-ch:
+			got = append(got, ev)
+		case <-ctx.Done():
+			t.Fatalf("timed out, got %+v", got)
+		}
+	}
+	cancel()
+	for range ch {
+	}
+
+	for i, ev := range got {
+		if ev.Component != "comp1" || ev.Event.Type != common.EventTypeFatal {
+			t.Errorf("expected only fatal events, got %+v", ev)
+		}
+		// delivered once each in order, without the duplicates from the previous polls
+		if want := start.Add(time.Duration(i) * time.Second); !ev.Event.Time.Time.Equal(want) {
+			t.Errorf("event %d: expected time %v, got %v", i, want, ev.Event.Time.Time)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range minSeverities {
+		if s != string(common.EventTypeCritical) {
+			t.Errorf("expected minSeverity %q in the request, got %q", common.EventTypeCritical, s)
+		}
+	}
+}
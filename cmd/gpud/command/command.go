@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	nvidia_component_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	query_config "github.com/leptonai/gpud/components/query/config"
 	"github.com/leptonai/gpud/config"
+	"github.com/leptonai/gpud/internal/server"
 	"github.com/leptonai/gpud/version"
 
 	"github.com/urfave/cli"
@@ -30,6 +33,7 @@ var (
 
 	retentionPeriod           time.Duration
 	refreshComponentsInterval time.Duration
+	pollTimeout               time.Duration
 
 	webEnable        bool
 	webAdmin         bool
@@ -47,6 +51,8 @@ var (
 	enableAutoUpdate   bool
 	autoUpdateExitCode int
 
+	dryRun bool
+
 	filesToCheck         cli.StringSlice
 	kernelModulesToCheck cli.StringSlice
 
@@ -57,6 +63,7 @@ var (
 	nvidiaSMIQueryCommand    string
 	ibstatCommand            string
 	infinibandClassDirectory string
+	nvidiaSMIXMLFallback     bool
 )
 
 const (
@@ -202,6 +209,12 @@ sudo rm /etc/systemd/system/gpud.service
 					Destination: &retentionPeriod,
 					Value:       config.DefaultRetentionPeriod.Duration,
 				},
+				&cli.DurationFlag{
+					Name:        "poll-timeout",
+					Usage:       "set the default timeout for each component poll (a timed out poll is canceled and marks the component state stale)",
+					Destination: &pollTimeout,
+					Value:       query_config.DefaultGetTimeout,
+				},
 				&cli.DurationFlag{
 					Name:        "refresh-components-interval",
 					Usage:       "set the time period to refresh selected components",
@@ -240,6 +253,11 @@ sudo rm /etc/systemd/system/gpud.service
 					Destination: &autoUpdateExitCode,
 					Value:       -1,
 				},
+				&cli.BoolFlag{
+					Name:        "dry-run",
+					Usage:       "only log the repair actions (e.g., reboot) without executing them (default: false)",
+					Destination: &dryRun,
+				},
 				&cli.StringSliceFlag{
 					Name:  "files-to-check",
 					Usage: "enable 'file' component that returns healthy if and only if all the files exist (default: [], use '--files-to-check=a --files-to-check=b' for multiple files)",
@@ -260,6 +278,11 @@ sudo rm /etc/systemd/system/gpud.service
 					Usage:       "ignore connection errors to kubelet read-only port, useful when kubelet readOnlyPort is disabled (default: false)",
 					Destination: &kubeletIgnoreConnectionErrors,
 				},
+				&cli.BoolFlag{
+					Name:        "nvidia-smi-xml-fallback",
+					Usage:       "collect the basic GPU info from 'nvidia-smi -q -x' when NVML is unavailable (default: false)",
+					Destination: &nvidiaSMIXMLFallback,
+				},
 
 				// only for testing
 				cli.StringFlag{
@@ -487,12 +510,146 @@ sudo rm /etc/systemd/system/gpud.service
 			},
 		},
 
+		{
+			Name:  "events",
+			Usage: "prints the recent events of the components from the local gpud",
+			UsageText: `# to print the events of all components in the last hour
+gpud events
+
+# to print the events of the disk component in the last 3 hours in JSON
+gpud events --component disk --since 3h --json
+`,
+			Action: cmdEvents,
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "component",
+					Usage: "set the component to print the events of (use '--component=a --component=b' for multiple components, leave empty for all components)",
+				},
+				cli.DurationFlag{
+					Name:  "since",
+					Usage: "set the time period to print the events since",
+					Value: time.Hour,
+				},
+				cli.BoolFlag{
+					Name:  "json",
+					Usage: "print the events in JSON",
+				},
+			},
+		},
+
+		{
+			Name:  "fix",
+			Usage: "fixes the common misconfigurations of the host",
+			Subcommands: []cli.Command{
+				{
+					Name:  "persistence-mode",
+					Usage: "enables the persistence mode of the NVIDIA GPUs with the mode disabled (requires root, same as 'nvidia-smi -pm 1')",
+					UsageText: `# to print the GPUs with persistence mode disabled
+gpud fix persistence-mode
+
+# to enable persistence mode
+sudo gpud fix persistence-mode --apply
+`,
+					Action: cmdFixPersistenceMode,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "apply",
+							Usage: "enable the persistence mode (default: false, only print the GPUs to fix)",
+						},
+					},
+				},
+			},
+		},
+
+		{
+			Name:  "reset-gpu",
+			Usage: "resets the NVIDIA GPU of the UUID, e.g., the GPU reporting the Xid that suggests a reset (requires root, same as 'nvidia-smi --gpu-reset')",
+			UsageText: `# to check whether the GPU can be reset
+gpud reset-gpu GPU-a1b2c3d4
+
+# to reset the GPU (stop the gpud daemon first, since it holds the GPU)
+sudo gpud down
+sudo gpud reset-gpu GPU-a1b2c3d4 --yes
+`,
+			Action: cmdResetGPU,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "yes",
+					Usage: "confirm to reset the GPU (default: false, only check whether the GPU can be reset)",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "reset the GPU even with the running processes (default: false)",
+				},
+			},
+		},
+
+		{
+			Name:  "snapshot",
+			Usage: "saves the states, events, and metrics of all components from the local gpud to a gzipped JSON file for offline analysis",
+			UsageText: `# to save the snapshot of the local gpud
+gpud snapshot --out snapshot.json.gz
+`,
+			Action: cmdSnapshot,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "out",
+					Usage: "set the file path to save the gzipped JSON snapshot",
+				},
+			},
+		},
+
+		{
+			Name:  "probe",
+			Usage: "actively tests a component in the local gpud on demand (e.g., a lightweight NVML query, or a stat of the mount points)",
+			UsageText: `# to probe the NVIDIA GPUs
+gpud probe accelerator-nvidia-info
+
+# to probe the disks with the timeout
+gpud probe --timeout 5s disk
+`,
+			Action: cmdProbe,
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name:  "timeout",
+					Usage: "set the timeout of the probe",
+					Value: server.DefaultProbeTimeout,
+				},
+			},
+		},
+
+		{
+			Name:  "inspect",
+			Usage: "generates the hardware inspection report of the GPUs from the local gpud, combining the critical Xids, ECC errors, row remappings, NVLink errors, PCIe links, and temperatures",
+			UsageText: `# to print the report of the local gpud
+gpud inspect
+
+# to print the report in JSON
+gpud inspect --json
+`,
+			Action: cmdInspect,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "json",
+					Usage: "set true to print the report in JSON",
+				},
+			},
+		},
+
 		{
 			Name: "is-nvidia",
 
 			Usage:  "quick check if the host has NVIDIA GPUs installed",
 			Action: cmdIsNvidia,
 		},
+		{
+			Name:  "doctor",
+			Usage: "checks the prerequisites of gpud (e.g., NVML, nvidia-smi, dmesg) with remediation hints",
+			UsageText: `# to check the prerequisites (exits non-zero if any critical check fails)
+sudo gpud doctor
+`,
+			Action: cmdDoctor,
+		},
 		{
 			Name:    "accelerator",
 			Aliases: []string{"a"},
@@ -592,6 +749,78 @@ cat summary.txt
 				},
 			},
 		},
+		{
+			Name:    "simulate",
+			Aliases: []string{"replay"},
+			Usage:   "simulates the classification and alerting pipeline against a saved log file (never takes any action)",
+			UsageText: `# to simulate what gpud would have done for a saved dmesg log
+gpud simulate /var/log/dmesg.log
+
+# to replay a captured dmesg log through the xid pipeline
+gpud replay --dmesg /var/log/dmesg.log
+
+# to simulate with the quiet hours from 10PM to 6AM
+gpud simulate --quiet-hours 22:00-06:00 /var/log/dmesg.log
+
+# to simulate with xid 13 ignored and xid 43 forced as critical
+gpud simulate --xid-ignore 13 --xid-force-critical 43 /var/log/dmesg.log
+
+# to simulate with the critical xids keeping the xid component healthy
+gpud simulate --event-type-health Critical=true /var/log/dmesg.log
+
+# to print the decisions and states in JSON
+gpud simulate --json /var/log/dmesg.log
+`,
+			Action: cmdSimulate,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "dmesg",
+					Usage: "set the captured dmesg or syslog file to simulate (alternative to the log file argument)",
+				},
+				cli.StringSliceFlag{
+					Name:  "quiet-hours",
+					Usage: "set the daily quiet hours in the 'HH:MM-HH:MM' format to suppress the ticket-class alerts (use '--quiet-hours=a --quiet-hours=b' for multiple windows)",
+				},
+				cli.DurationFlag{
+					Name:  "coalesce-window",
+					Usage: "set the window to coalesce the same xid on the same device",
+					Value: nvidia_component_error_xid.DefaultCoalesceWindow,
+				},
+				cli.IntSliceFlag{
+					Name:  "xid-ignore",
+					Usage: "set the xid to ignore when computing the health state, same as the 'xid_ignore_list' config (use '--xid-ignore=a --xid-ignore=b' for multiple xids)",
+				},
+				cli.IntSliceFlag{
+					Name:  "xid-force-critical",
+					Usage: "set the xid to escalate to at least critical when computing the health state, same as the 'xid_force_critical_list' config (use '--xid-force-critical=a --xid-force-critical=b' for multiple xids)",
+				},
+				cli.StringSliceFlag{
+					Name:  "event-type-health",
+					Usage: "set whether the xid events of the type keep the xid component healthy in the 'TYPE=true|false' format, same as the 'event_type_health_mapping' config (use '--event-type-health=a --event-type-health=b' for multiple types)",
+				},
+				cli.BoolFlag{
+					Name:  "json",
+					Usage: "print the decisions and states in JSON",
+				},
+			},
+		},
+		{
+			Name:  "xid",
+			Usage: "prints the xid details known to gpud in JSON",
+			UsageText: `# to print the details of xid 79
+gpud xid 79
+
+# to print the details of all known xids
+gpud xid --all
+`,
+			Action: cmdXid,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "all",
+					Usage: "print the details of all known xids",
+				},
+			},
+		},
 		{
 			Name:  "join",
 			Usage: "join gpud machine into a lepton cluster",
@@ -0,0 +1,181 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/pkg/file"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli"
+)
+
+// doctorCheck is a prerequisite check of "gpud doctor".
+type doctorCheck struct {
+	name string
+	// set true to fail "gpud doctor" if the check fails
+	critical bool
+	// remediation hint printed when the check fails
+	hint string
+	// returns the detail to print on success
+	run func(ctx context.Context) (string, error)
+}
+
+type doctorResult struct {
+	name     string
+	critical bool
+	hint     string
+	detail   string
+	err      error
+}
+
+var errDoctorCriticalCheckFailed = errors.New("critical prerequisite check(s) failed")
+
+func cmdDoctor(cliContext *cli.Context) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	results := runDoctorChecks(ctx, defaultDoctorChecks())
+	if failed := writeDoctorReport(cliContext.App.Writer, results); failed {
+		return errDoctorCriticalCheckFailed
+	}
+	return nil
+}
+
+func defaultDoctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{
+			name:     "NVML",
+			critical: true,
+			hint:     "install the NVIDIA driver and make sure libnvidia-ml.so is in the library path (e.g., ldconfig -p | grep libnvidia-ml)",
+			run:      checkNVML,
+		},
+		{
+			name: "nvidia-smi",
+			hint: "install the NVIDIA driver utilities (e.g., nvidia-utils) and add nvidia-smi to the PATH",
+			run: func(ctx context.Context) (string, error) {
+				if !nvidia_query.SMIExists() {
+					return "", errors.New("nvidia-smi not found")
+				}
+				return "found", nil
+			},
+		},
+		{
+			name:     "dmesg",
+			critical: true,
+			hint:     "run gpud as root, or allow reading the kernel logs (sysctl kernel.dmesg_restrict=0)",
+			run: func(ctx context.Context) (string, error) {
+				return checkCommand(ctx, "dmesg")
+			},
+		},
+		{
+			name: "lsblk",
+			hint: "install util-linux to enable the disk checks",
+			run: func(ctx context.Context) (string, error) {
+				return checkCommand(ctx, "lsblk", "--version")
+			},
+		},
+	}
+}
+
+func checkNVML(ctx context.Context) (string, error) {
+	nvmlLib := nvidia_query_nvml.NewNVML()
+	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
+		return "", fmt.Errorf("failed to initialize NVML: %v", nvml.ErrorString(ret))
+	}
+	defer func() {
+		_ = nvmlLib.Shutdown()
+	}()
+
+	driverVersion, ret := nvmlLib.SystemGetDriverVersion()
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("failed to get driver version: %v", nvml.ErrorString(ret))
+	}
+	// e.g., 12040 for CUDA 12.4
+	cudaVersion, ret := nvmlLib.SystemGetCudaDriverVersion()
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("failed to get CUDA version: %v", nvml.ErrorString(ret))
+	}
+	return fmt.Sprintf("driver %s, CUDA %d.%d", driverVersion, cudaVersion/1000, (cudaVersion%1000)/10), nil
+}
+
+// checkCommand runs the command to check that it is installed and runnable
+// by the current user (e.g., dmesg fails with "Operation not permitted" if restricted).
+func checkCommand(ctx context.Context, name string, args ...string) (string, error) {
+	p, err := file.LocateExecutable(name)
+	if err != nil {
+		return "", fmt.Errorf("%s not found", name)
+	}
+	out, err := exec.CommandContext(ctx, p, args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return "", fmt.Errorf("failed to run %s: %w", name, err)
+		}
+		return "", fmt.Errorf("failed to run %s: %w (%s)", name, err, firstLine(msg))
+	}
+	return "found " + p, nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func runDoctorChecks(ctx context.Context, checks []doctorCheck) []doctorResult {
+	results := make([]doctorResult, 0, len(checks))
+	for _, c := range checks {
+		detail, err := c.run(ctx)
+		results = append(results, doctorResult{
+			name:     c.name,
+			critical: c.critical,
+			hint:     c.hint,
+			detail:   detail,
+			err:      err,
+		})
+	}
+	return results
+}
+
+// writeDoctorReport writes the pass/fail report of the checks,
+// and returns true if any critical check failed.
+func writeDoctorReport(wr io.Writer, results []doctorResult) bool {
+	criticalFailed, warned := 0, 0
+	for _, r := range results {
+		if r.err == nil {
+			fmt.Fprintf(wr, "%s %s: %s\n", checkMark, r.name, r.detail)
+			continue
+		}
+
+		level := "optional"
+		if r.critical {
+			level = "critical"
+			criticalFailed++
+		} else {
+			warned++
+		}
+		fmt.Fprintf(wr, "%s %s (%s): %v\n", warningSign, r.name, level, r.err)
+		if r.hint != "" {
+			fmt.Fprintf(wr, "    hint: %s\n", r.hint)
+		}
+	}
+
+	switch {
+	case criticalFailed > 0:
+		fmt.Fprintf(wr, "\n%d critical check(s) failed, %d optional check(s) failed\n", criticalFailed, warned)
+	case warned > 0:
+		fmt.Fprintf(wr, "\nall critical checks passed, %d optional check(s) failed\n", warned)
+	default:
+		fmt.Fprintf(wr, "\nall checks passed\n")
+	}
+	return criticalFailed > 0
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func stubDoctorCheck(name string, critical bool, detail string, err error) doctorCheck {
+	return doctorCheck{
+		name:     name,
+		critical: critical,
+		hint:     "fix " + name,
+		run: func(ctx context.Context) (string, error) {
+			return detail, err
+		},
+	}
+}
+
+func TestDoctorReportAllPass(t *testing.T) {
+	results := runDoctorChecks(context.Background(), []doctorCheck{
+		stubDoctorCheck("NVML", true, "driver 535.161.08, CUDA 12.2", nil),
+		stubDoctorCheck("nvidia-smi", false, "found", nil),
+		stubDoctorCheck("dmesg", true, "found /usr/bin/dmesg", nil),
+	})
+
+	var buf bytes.Buffer
+	if failed := writeDoctorReport(&buf, results); failed {
+		t.Fatal("expected no critical failure")
+	}
+
+	expected := checkMark + " NVML: driver 535.161.08, CUDA 12.2\n" +
+		checkMark + " nvidia-smi: found\n" +
+		checkMark + " dmesg: found /usr/bin/dmesg\n" +
+		"\nall checks passed\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected report:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDoctorReportMissingNVML(t *testing.T) {
+	results := runDoctorChecks(context.Background(), []doctorCheck{
+		stubDoctorCheck("NVML", true, "", errors.New("failed to initialize NVML: ERROR_LIBRARY_NOT_FOUND")),
+		stubDoctorCheck("nvidia-smi", false, "", errors.New("nvidia-smi not found")),
+		stubDoctorCheck("dmesg", true, "found /usr/bin/dmesg", nil),
+	})
+
+	var buf bytes.Buffer
+	if failed := writeDoctorReport(&buf, results); !failed {
+		t.Fatal("expected critical failure")
+	}
+
+	expected := warningSign + " NVML (critical): failed to initialize NVML: ERROR_LIBRARY_NOT_FOUND\n" +
+		"    hint: fix NVML\n" +
+		warningSign + " nvidia-smi (optional): nvidia-smi not found\n" +
+		"    hint: fix nvidia-smi\n" +
+		checkMark + " dmesg: found /usr/bin/dmesg\n" +
+		"\n1 critical check(s) failed, 1 optional check(s) failed\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected report:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDoctorReportOptionalFailure(t *testing.T) {
+	results := runDoctorChecks(context.Background(), []doctorCheck{
+		stubDoctorCheck("NVML", true, "driver 535.161.08, CUDA 12.2", nil),
+		stubDoctorCheck("lsblk", false, "", errors.New("lsblk not found")),
+	})
+
+	var buf bytes.Buffer
+	if failed := writeDoctorReport(&buf, results); failed {
+		t.Fatal("expected no critical failure for an optional check")
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\nall critical checks passed, 1 optional check(s) failed\n")) {
+		t.Errorf("unexpected report summary:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/config"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+// getEvents is the client function to query the local gpud, overwritten in tests.
+var getEvents = client.GetEvents
+
+func cmdEvents(cliContext *cli.Context) error {
+	rootCtx, rootCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer rootCancel()
+
+	opts := []client.OpOption{
+		client.WithSince(time.Now().Add(-cliContext.Duration("since"))),
+	}
+	for _, component := range cliContext.StringSlice("component") {
+		opts = append(opts, client.WithComponent(component))
+	}
+
+	evs, err := getEvents(rootCtx, fmt.Sprintf("https://localhost:%d", config.DefaultGPUdPort), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	if cliContext.Bool("json") {
+		return writeJSON(cliContext.App.Writer, evs)
+	}
+	renderEvents(cliContext.App.Writer, evs)
+	return nil
+}
+
+// renderEvents renders the events of all components in a table,
+// sorted by time in the ascending order (latest event last).
+func renderEvents(wr io.Writer, evs v1.LeptonEvents) {
+	type row struct {
+		time      time.Time
+		component string
+		name      string
+		eventType common.EventType
+		message   string
+	}
+	rows := make([]row, 0)
+	for _, ev := range evs {
+		for _, e := range ev.Events {
+			message := e.Message
+			if e.Occurrences > 1 && e.LastSeen != nil {
+				message += fmt.Sprintf(" (%d occurrences, last seen %s)", e.Occurrences, e.LastSeen.UTC().Format(time.RFC3339))
+			}
+			rows = append(rows, row{
+				time:      e.Time.Time,
+				component: ev.Component,
+				name:      e.Name,
+				eventType: e.Type,
+				message:   message,
+			})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].time.Before(rows[j].time)
+	})
+
+	table := tablewriter.NewWriter(wr)
+	table.SetHeader([]string{"Time", "Component", "Name", "Type", "Message"})
+	table.SetAutoWrapText(false)
+	for _, r := range rows {
+		table.Append([]string{
+			r.time.UTC().Format(time.RFC3339),
+			r.component,
+			r.name,
+			colorEventType(r.eventType),
+			r.message,
+		})
+	}
+	table.Render()
+}
+
+// colorEventType colors the event type by its severity.
+func colorEventType(eventType common.EventType) string {
+	switch eventType {
+	case common.EventTypeFatal, common.EventTypeCritical:
+		return "\033[31m" + string(eventType) + "\033[0m"
+	case common.EventTypeWarning:
+		return "\033[33m" + string(eventType) + "\033[0m"
+	case common.EventTypeInfo:
+		return "\033[32m" + string(eventType) + "\033[0m"
+	default:
+		return string(eventType)
+	}
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func stubGetEvents(t *testing.T, evs v1.LeptonEvents) {
+	orig := getEvents
+	getEvents = func(ctx context.Context, addr string, opts ...client.OpOption) (v1.LeptonEvents, error) {
+		return evs, nil
+	}
+	t.Cleanup(func() { getEvents = orig })
+}
+
+func TestCmdEvents(t *testing.T) {
+	now := time.Now()
+	stubGetEvents(t, v1.LeptonEvents{
+		{
+			Component: "accelerator-nvidia-error-xid",
+			Events: []components.Event{
+				{Time: metav1.NewTime(now), Name: "error_xid", Type: common.EventTypeCritical, Message: "XID 79 detected on GPU-0"},
+			},
+		},
+		{
+			Component: "disk",
+			Events: []components.Event{
+				{Time: metav1.NewTime(now.Add(-time.Minute)), Name: "disk_full", Type: common.EventTypeWarning, Message: "disk usage above 90%"},
+			},
+		},
+	})
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	if err := app.Run([]string{"gpud", "events", "--since", "1h"}); err != nil {
+		t.Fatalf("failed to run events command: %v", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{"error_xid", "Critical", "XID 79 detected on GPU-0", "disk_full", "Warning", "disk usage above 90%"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+	// sorted by time, the older disk event first
+	if strings.Index(out, "disk_full") > strings.Index(out, "error_xid") {
+		t.Errorf("expected events sorted by time:\n%s", out)
+	}
+}
+
+func TestCmdEventsOccurrences(t *testing.T) {
+	firstSeen := metav1.NewTime(time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC))
+	lastSeen := metav1.NewTime(time.Date(2024, 11, 1, 10, 4, 0, 0, time.UTC))
+	stubGetEvents(t, v1.LeptonEvents{
+		{
+			Component: "accelerator-nvidia-error-xid",
+			Events: []components.Event{
+				{Time: firstSeen, Name: "error_xid", Message: "XID 74 detected on GPU-0", FirstSeen: &firstSeen, LastSeen: &lastSeen, Occurrences: 42},
+			},
+		},
+	})
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	if err := app.Run([]string{"gpud", "events"}); err != nil {
+		t.Fatalf("failed to run events command: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "XID 74 detected on GPU-0 (42 occurrences, last seen 2024-11-01T10:04:00Z)") {
+		t.Errorf("expected the occurrences in output:\n%s", out)
+	}
+}
+
+func TestCmdEventsJSON(t *testing.T) {
+	stubGetEvents(t, v1.LeptonEvents{
+		{
+			Component: "disk",
+			Events:    []components.Event{{Name: "disk_full", Type: common.EventTypeWarning, Message: "disk usage above 90%"}},
+		},
+	})
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	if err := app.Run([]string{"gpud", "events", "--component", "disk", "--json"}); err != nil {
+		t.Fatalf("failed to run events command: %v", err)
+	}
+
+	var evs v1.LeptonEvents
+	if err := json.Unmarshal(buf.Bytes(), &evs); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if len(evs) != 1 || evs[0].Component != "disk" || evs[0].Events[0].Name != "disk_full" {
+		t.Errorf("unexpected events %+v", evs)
+	}
+}
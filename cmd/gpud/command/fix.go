@@ -0,0 +1,76 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli"
+)
+
+func cmdFixPersistenceMode(cliContext *cli.Context) error {
+	nvmlLib := nvidia_query_nvml.NewNVML()
+	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %v", nvml.ErrorString(ret))
+	}
+	defer func() {
+		_ = nvmlLib.Shutdown()
+	}()
+
+	devs, err := device.New(nvmlLib).GetDevices()
+	if err != nil {
+		return fmt.Errorf("failed to get devices: %w", err)
+	}
+	return fixPersistenceMode(cliContext.App.Writer, devs, cliContext.Bool("apply"))
+}
+
+var errFixPersistenceModeFailed = errors.New("failed to enable persistence mode on some GPU(s)")
+
+// fixPersistenceMode enables the persistence mode of the devices with the mode disabled.
+// Only prints the devices to fix if "apply" is false.
+func fixPersistenceMode(wr io.Writer, devs []device.Device, apply bool) error {
+	toFix, failed := 0, 0
+	for _, dev := range devs {
+		uuid, ret := dev.GetUUID()
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get device uuid: %v", nvml.ErrorString(ret))
+		}
+
+		mode, err := nvidia_query_nvml.GetPersistenceMode(uuid, dev)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !mode.Supported:
+			fmt.Fprintf(wr, "%s %s: persistence mode not supported\n", warningSign, uuid)
+			continue
+		case mode.Enabled:
+			fmt.Fprintf(wr, "%s %s: persistence mode already enabled\n", checkMark, uuid)
+			continue
+		}
+
+		toFix++
+		if !apply {
+			fmt.Fprintf(wr, "%s %s: persistence mode disabled (use --apply to enable)\n", warningSign, uuid)
+			continue
+		}
+		if err := nvidia_query_nvml.EnablePersistenceMode(dev); err != nil {
+			fmt.Fprintf(wr, "%s %s: %v\n", warningSign, uuid, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(wr, "%s %s: persistence mode enabled\n", checkMark, uuid)
+	}
+
+	if failed > 0 {
+		return errFixPersistenceModeFailed
+	}
+	if toFix > 0 && !apply {
+		fmt.Fprintf(wr, "\n%d GPU(s) with persistence mode disabled, re-run with --apply to enable\n", toFix)
+	}
+	return nil
+}
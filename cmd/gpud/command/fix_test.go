@@ -0,0 +1,79 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+)
+
+func mockPersistenceModeDevice(uuid string, mode *nvml.EnableState, setRet nvml.Return) device.Device {
+	return testutil.CreateDevice(&mock.Device{
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return uuid, nvml.SUCCESS
+		},
+		GetPersistenceModeFunc: func() (nvml.EnableState, nvml.Return) {
+			return *mode, nvml.SUCCESS
+		},
+		SetPersistenceModeFunc: func(state nvml.EnableState) nvml.Return {
+			if setRet == nvml.SUCCESS {
+				*mode = state
+			}
+			return setRet
+		},
+	})
+}
+
+func TestFixPersistenceMode(t *testing.T) {
+	on, off := nvml.FEATURE_ENABLED, nvml.FEATURE_DISABLED
+	devs := []device.Device{
+		mockPersistenceModeDevice("GPU-0", &on, nvml.SUCCESS),
+		mockPersistenceModeDevice("GPU-1", &off, nvml.SUCCESS),
+	}
+
+	// dry run without --apply
+	var buf bytes.Buffer
+	if err := fixPersistenceMode(&buf, devs, false); err != nil {
+		t.Fatal(err)
+	}
+	if off != nvml.FEATURE_DISABLED {
+		t.Fatal("expected persistence mode unchanged without apply")
+	}
+	if !strings.Contains(buf.String(), "GPU-1: persistence mode disabled (use --apply to enable)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := fixPersistenceMode(&buf, devs, true); err != nil {
+		t.Fatal(err)
+	}
+	if off != nvml.FEATURE_ENABLED {
+		t.Fatal("expected persistence mode enabled with apply")
+	}
+	for _, s := range []string{"GPU-0: persistence mode already enabled", "GPU-1: persistence mode enabled"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in output:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestFixPersistenceModeFailure(t *testing.T) {
+	off := nvml.FEATURE_DISABLED
+	devs := []device.Device{
+		mockPersistenceModeDevice("GPU-0", &off, nvml.ERROR_NO_PERMISSION),
+	}
+
+	var buf bytes.Buffer
+	if err := fixPersistenceMode(&buf, devs, true); !errors.Is(err, errFixPersistenceModeFailed) {
+		t.Fatalf("expected %v, got %v", errFixPersistenceModeFailed, err)
+	}
+	if !strings.Contains(buf.String(), "GPU-0: failed to enable device persistence mode") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,331 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	"github.com/leptonai/gpud/components"
+	nvidia_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/ecc"
+	nvidia_ecc_id "github.com/leptonai/gpud/components/accelerator/nvidia/ecc/id"
+	nvidia_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	nvidia_xid_id "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid/id"
+	nvidia_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/nvlink"
+	nvidia_pcie "github.com/leptonai/gpud/components/accelerator/nvidia/pcie"
+	nvidia_pcie_id "github.com/leptonai/gpud/components/accelerator/nvidia/pcie/id"
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+	nvidia_remapped_rows "github.com/leptonai/gpud/components/accelerator/nvidia/remapped-rows"
+	nvidia_thermal_threshold "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold"
+	nvidia_thermal_threshold_id "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold/id"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/config"
+
+	"github.com/urfave/cli"
+)
+
+func cmdInspect(cliContext *cli.Context) error {
+	rootCtx, rootCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer rootCancel()
+
+	info, err := getInfo(rootCtx, fmt.Sprintf("https://localhost:%d", config.DefaultGPUdPort))
+	if err != nil {
+		return fmt.Errorf("failed to get info: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	report := newInspectReport(hostname, time.Now().UTC(), info)
+	if cliContext.Bool("json") {
+		return writeJSON(cliContext.App.Writer, report)
+	}
+	return report.writeText(cliContext.App.Writer)
+}
+
+// inspectReport is the hardware inspection report of the GPUs,
+// combining the signals from multiple components.
+type inspectReport struct {
+	Time     time.Time `json:"time"`
+	Hostname string    `json:"hostname"`
+
+	// UUIDs of the GPUs with any signal implicating the hardware issue.
+	ImplicatedGPUs []string `json:"implicated_gpus"`
+	// Per-GPU signals in the ascending order of the UUID.
+	GPUs []inspectGPU `json:"gpus"`
+
+	// Components with no data in the local gpud (e.g., disabled),
+	// or whose data failed to parse.
+	MissingComponents []string `json:"missing_components,omitempty"`
+
+	// Number of the Xid events skipped since their data failed to decode.
+	SkippedXidEvents int `json:"skipped_xid_events,omitempty"`
+}
+
+type inspectGPU struct {
+	UUID       string          `json:"uuid"`
+	Implicated bool            `json:"implicated"`
+	Signals    []inspectSignal `json:"signals"`
+}
+
+type inspectSignal struct {
+	Component string `json:"component"`
+	Message   string `json:"message"`
+	// Set true if the signal implicates the hardware issue of the GPU.
+	Implicated bool `json:"implicated"`
+}
+
+// newInspectReport builds the report from the states and events of the components.
+func newInspectReport(hostname string, now time.Time, info v1.LeptonInfo) inspectReport {
+	gpus := make(map[string]*inspectGPU)
+	add := func(uuid string, component string, implicated bool, format string, args ...any) {
+		gpu, ok := gpus[uuid]
+		if !ok {
+			gpu = &inspectGPU{UUID: uuid}
+			gpus[uuid] = gpu
+		}
+		gpu.Signals = append(gpu.Signals, inspectSignal{
+			Component:  component,
+			Message:    fmt.Sprintf(format, args...),
+			Implicated: implicated,
+		})
+		gpu.Implicated = gpu.Implicated || implicated
+	}
+
+	byComponent := make(map[string]v1.LeptonComponentInfo, len(info))
+	for _, ci := range info {
+		byComponent[ci.Component] = ci
+	}
+
+	var missing []string
+	skippedXids := 0
+	for _, inspect := range []struct {
+		component string
+		fn        func(v1.LeptonComponentInfo, func(string, string, bool, string, ...any)) error
+	}{
+		{nvidia_xid_id.Name, func(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+			skippedXids = inspectXids(ci, add)
+			return nil
+		}},
+		{nvidia_ecc_id.Name, inspectECC},
+		{nvidia_remapped_rows.Name, inspectRemappedRows},
+		{nvidia_nvlink.Name, inspectNVLink},
+		{nvidia_pcie_id.Name, inspectPCIe},
+		{nvidia_thermal_threshold_id.Name, inspectThermal},
+	} {
+		ci, ok := byComponent[inspect.component]
+		if !ok {
+			missing = append(missing, inspect.component)
+			continue
+		}
+		if err := inspect.fn(ci, add); err != nil {
+			missing = append(missing, inspect.component)
+		}
+	}
+
+	report := inspectReport{
+		Time:              now,
+		Hostname:          hostname,
+		ImplicatedGPUs:    []string{},
+		GPUs:              []inspectGPU{},
+		MissingComponents: missing,
+		SkippedXidEvents:  skippedXids,
+	}
+	for _, gpu := range gpus {
+		report.GPUs = append(report.GPUs, *gpu)
+	}
+	sort.Slice(report.GPUs, func(i, j int) bool {
+		return report.GPUs[i].UUID < report.GPUs[j].UUID
+	})
+	for _, gpu := range report.GPUs {
+		if gpu.Implicated {
+			report.ImplicatedGPUs = append(report.ImplicatedGPUs, gpu.UUID)
+		}
+	}
+	return report
+}
+
+// inspectXids adds the critical Xid events, implicated if the hardware inspection is suggested.
+// The events whose Xid data is the raw Xid (e.g., not resolved by the older versions)
+// are resolved with the synthesized detail for the unknown Xids.
+// Returns the number of the events skipped since their data failed to decode,
+// so that one malformed event does not drop all the other Xid signals.
+func inspectXids(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) (skipped int) {
+	for _, ev := range ci.Info.Events {
+		if ev.Name != nvidia_xid.EventNameErroXid {
+			continue
+		}
+		xidErr, err := decodeXidError(ev)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if !xidErr.CriticalErrorMarkedByGPUd {
+			continue
+		}
+
+		var actions []common.RepairActionType
+		if xidErr.SuggestedActionsByGPUd != nil {
+			actions = xidErr.SuggestedActionsByGPUd.RepairActions
+		}
+		implicated := false
+		for _, a := range actions {
+			if a == common.RepairActionTypeHardwareInspection {
+				implicated = true
+				break
+			}
+		}
+		add(xidErr.DeviceUUID, ci.Component, implicated, "critical Xid %d at %s (suggested actions %v)", xidErr.Xid, ev.Time.UTC().Format(time.RFC3339), actions)
+	}
+	return skipped
+}
+
+// decodeXidError decodes the Xid error from the event,
+// resolving the raw Xid with "GetDetailOrDefault".
+func decodeXidError(ev components.Event) (nvidia_xid.XidError, error) {
+	data := ev.ExtraInfo[nvidia_xid.EventKeyErroXidData]
+
+	var xidErr nvidia_xid.XidError
+	if err := json.Unmarshal([]byte(data), &xidErr); err == nil {
+		return xidErr, nil
+	}
+
+	id, err := strconv.Atoi(data)
+	if err != nil {
+		return xidErr, fmt.Errorf("failed to decode xid data %q: %w", data, err)
+	}
+	detail := nvidia_query_xid.GetDetailOrDefault(id)
+	return nvidia_xid.XidError{
+		Time:                      ev.Time,
+		DeviceUUID:                ev.ExtraInfo[nvidia_xid.EventKeyDeviceUUID],
+		Xid:                       uint64(id),
+		SuggestedActionsByGPUd:    detail.SuggestedActionsByGPUd,
+		CriticalErrorMarkedByGPUd: detail.CriticalErrorMarkedByGPUd,
+	}, nil
+}
+
+// inspectECC adds the ECC error counts, implicated if any volatile uncorrected error.
+func inspectECC(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+	o, err := nvidia_ecc.ParseStatesToOutput(ci.Info.States...)
+	if err != nil {
+		return err
+	}
+	for _, e := range o.ErrorCountsNVML {
+		if !e.Supported {
+			continue
+		}
+		add(e.UUID, ci.Component, e.Volatile.Total.Uncorrected > 0,
+			"ECC volatile %d corrected, %d uncorrected (aggregate %d corrected, %d uncorrected)",
+			e.Volatile.Total.Corrected, e.Volatile.Total.Uncorrected, e.Aggregate.Total.Corrected, e.Aggregate.Total.Uncorrected)
+	}
+	return nil
+}
+
+// inspectRemappedRows adds the row remapping status, implicated if the GPU qualifies for RMA.
+func inspectRemappedRows(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+	o, err := nvidia_remapped_rows.ParseStatesToOutput(ci.Info.States...)
+	if err != nil {
+		return err
+	}
+	for _, r := range o.RemappedRowsNVML {
+		if !r.Supported {
+			continue
+		}
+		add(r.UUID, ci.Component, r.QualifiesForRMA(),
+			"row remapping %d correctable, %d uncorrectable (pending %v, failed %v)",
+			r.RemappedDueToCorrectableErrors, r.RemappedDueToUncorrectableErrors, r.RemappingPending, r.RemappingFailed)
+	}
+	return nil
+}
+
+// inspectNVLink adds the NVLink error counts, implicated if any link is down.
+func inspectNVLink(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+	o, err := nvidia_nvlink.ParseStatesToOutput(ci.Info.States...)
+	if err != nil {
+		return err
+	}
+	for _, d := range o.NVLinkDevices {
+		if !d.Supported {
+			continue
+		}
+		add(d.UUID, ci.Component, !d.States.AllFeatureEnabled(),
+			"NVLink %d link(s), all enabled %v, %d crc, %d replay, %d recovery errors",
+			len(d.States), d.States.AllFeatureEnabled(), d.States.TotalCRCErrors(), d.States.TotalRelayErrors(), d.States.TotalRecoveryErrors())
+	}
+	return nil
+}
+
+// inspectPCIe adds the PCIe link state, implicated if the link width is downtrained.
+func inspectPCIe(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+	o, err := nvidia_pcie.ParseStatesToOutput(ci.Info.States...)
+	if err != nil {
+		return err
+	}
+	for _, l := range o.PCIeLinksNVML {
+		if !l.Supported {
+			continue
+		}
+		add(l.UUID, ci.Component, l.WidthDowntrained(),
+			"PCIe link x%d Gen%d (max x%d Gen%d)",
+			l.CurrentLinkWidth, l.CurrentLinkGeneration, l.MaxLinkWidth, l.MaxLinkGeneration)
+	}
+	return nil
+}
+
+// inspectThermal adds the temperature readings, implicated if the shutdown threshold is reached.
+func inspectThermal(ci v1.LeptonComponentInfo, add func(string, string, bool, string, ...any)) error {
+	o, err := nvidia_thermal_threshold.ParseStatesToOutput(ci.Info.States...)
+	if err != nil {
+		return err
+	}
+	for _, t := range o.TemperaturesNVML {
+		add(t.UUID, ci.Component, t.ThresholdCelsiusShutdown > 0 && t.CurrentCelsiusGPUCore >= t.ThresholdCelsiusShutdown,
+			"temperature %d °C (slowdown threshold %d °C, shutdown threshold %d °C)",
+			t.CurrentCelsiusGPUCore, t.ThresholdCelsiusSlowdown, t.ThresholdCelsiusShutdown)
+	}
+	return nil
+}
+
+func (r inspectReport) writeText(wr io.Writer) error {
+	fmt.Fprintf(wr, "hardware inspection report for %s at %s\n\n", r.Hostname, r.Time.Format(time.RFC3339))
+
+	for _, gpu := range r.GPUs {
+		mark := checkMark
+		if gpu.Implicated {
+			mark = warningSign
+		}
+		fmt.Fprintf(wr, "%s GPU %s\n", mark, gpu.UUID)
+		for _, s := range gpu.Signals {
+			prefix := " "
+			if s.Implicated {
+				prefix = "!"
+			}
+			fmt.Fprintf(wr, "  %s [%s] %s\n", prefix, s.Component, s.Message)
+		}
+		fmt.Fprintln(wr)
+	}
+
+	for _, c := range r.MissingComponents {
+		fmt.Fprintf(wr, "%s no data from %s\n", inProgress, c)
+	}
+	if r.SkippedXidEvents > 0 {
+		fmt.Fprintf(wr, "%s skipped %d Xid event(s) that failed to decode\n", warningSign, r.SkippedXidEvents)
+	}
+	if len(r.MissingComponents) > 0 || r.SkippedXidEvents > 0 {
+		fmt.Fprintln(wr)
+	}
+
+	if len(r.ImplicatedGPUs) == 0 {
+		_, err := fmt.Fprintf(wr, "%s no GPU implicated for the hardware inspection\n", checkMark)
+		return err
+	}
+	_, err := fmt.Fprintf(wr, "%s %d GPU(s) implicated for the hardware inspection: %v\n", warningSign, len(r.ImplicatedGPUs), r.ImplicatedGPUs)
+	return err
+}
@@ -0,0 +1,267 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/components"
+	nvidia_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/ecc"
+	nvidia_ecc_id "github.com/leptonai/gpud/components/accelerator/nvidia/ecc/id"
+	nvidia_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	nvidia_xid_id "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid/id"
+	nvidia_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/nvlink"
+	nvidia_pcie "github.com/leptonai/gpud/components/accelerator/nvidia/pcie"
+	nvidia_pcie_id "github.com/leptonai/gpud/components/accelerator/nvidia/pcie/id"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+	nvidia_remapped_rows "github.com/leptonai/gpud/components/accelerator/nvidia/remapped-rows"
+	nvidia_thermal_threshold "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold"
+	nvidia_thermal_threshold_id "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold/id"
+	"github.com/leptonai/gpud/components/common"
+)
+
+const (
+	inspectGPUHealthy = "GPU-00000000-0000-0000-0000-000000000000"
+	inspectGPUFaulty  = "GPU-11111111-1111-1111-1111-111111111111"
+)
+
+func stubGetInfo(t *testing.T, info v1.LeptonInfo) {
+	orig := getInfo
+	getInfo = func(ctx context.Context, addr string, opts ...client.OpOption) (v1.LeptonInfo, error) {
+		return info, nil
+	}
+	t.Cleanup(func() { getInfo = orig })
+}
+
+func newInspectXidEvent(t *testing.T, now time.Time, uuid string, xid int) components.Event {
+	t.Helper()
+
+	detail, ok := nvidia_query_xid.GetDetail(xid)
+	if !ok {
+		t.Fatalf("xid %d not found", xid)
+	}
+	data, err := json.Marshal(nvidia_xid.XidError{
+		Time:                      metav1.NewTime(now),
+		DataSource:                "dmesg",
+		DeviceUUID:                uuid,
+		Xid:                       uint64(xid),
+		SuggestedActionsByGPUd:    detail.SuggestedActionsByGPUd,
+		CriticalErrorMarkedByGPUd: detail.CriticalErrorMarkedByGPUd,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return components.Event{
+		Time: metav1.NewTime(now),
+		Name: nvidia_xid.EventNameErroXid,
+		Type: common.EventTypeCritical,
+		ExtraInfo: map[string]string{
+			nvidia_xid.EventKeyErroXidData: string(data),
+			nvidia_xid.EventKeyDeviceUUID:  uuid,
+		},
+	}
+}
+
+func newInspectComponentInfo(t *testing.T, component string, o interface {
+	States() ([]components.State, error)
+}) v1.LeptonComponentInfo {
+	t.Helper()
+
+	states, err := o.States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v1.LeptonComponentInfo{Component: component, Info: components.Info{States: states}}
+}
+
+func newInspectInfo(t *testing.T, now time.Time) v1.LeptonInfo {
+	return v1.LeptonInfo{
+		{
+			Component: nvidia_xid_id.Name,
+			Info: components.Info{
+				Events: []components.Event{
+					newInspectXidEvent(t, now, inspectGPUFaulty, 79),
+					// non-critical, not reported
+					newInspectXidEvent(t, now, inspectGPUHealthy, 13),
+				},
+			},
+		},
+		newInspectComponentInfo(t, nvidia_ecc_id.Name, &nvidia_ecc.Output{
+			ErrorCountsNVML: []nvidia_query_nvml.ECCErrors{
+				{UUID: inspectGPUHealthy, Supported: true},
+				{
+					UUID:      inspectGPUFaulty,
+					Supported: true,
+					Volatile:  nvidia_query_nvml.AllECCErrorCounts{Total: nvidia_query_nvml.ECCErrorCounts{Uncorrected: 2}},
+				},
+			},
+		}),
+		newInspectComponentInfo(t, nvidia_remapped_rows.Name, &nvidia_remapped_rows.Output{
+			RemappedRowsNVML: []nvidia_query_nvml.RemappedRows{
+				{UUID: inspectGPUHealthy, Supported: true},
+				{UUID: inspectGPUFaulty, Supported: true, RemappingFailed: true, RemappedDueToUncorrectableErrors: 1},
+			},
+		}),
+		newInspectComponentInfo(t, nvidia_nvlink.Name, &nvidia_nvlink.Output{
+			NVLinkDevices: []nvidia_query_nvml.NVLink{
+				{UUID: inspectGPUHealthy, Supported: true, States: nvidia_query_nvml.NVLinkStates{{Link: 0, FeatureEnabled: true}}},
+				{UUID: inspectGPUFaulty, Supported: true, States: nvidia_query_nvml.NVLinkStates{{Link: 0, FeatureEnabled: true, CRCErrors: 5}}},
+			},
+		}),
+		newInspectComponentInfo(t, nvidia_pcie_id.Name, &nvidia_pcie.Output{
+			PCIeLinksNVML: []nvidia_query_nvml.PCIeLink{
+				{UUID: inspectGPUHealthy, Supported: true, CurrentLinkWidth: 16, MaxLinkWidth: 16, CurrentLinkGeneration: 5, MaxLinkGeneration: 5},
+				{UUID: inspectGPUFaulty, Supported: true, CurrentLinkWidth: 8, MaxLinkWidth: 16, CurrentLinkGeneration: 5, MaxLinkGeneration: 5},
+			},
+		}),
+		newInspectComponentInfo(t, nvidia_thermal_threshold_id.Name, &nvidia_thermal_threshold.Output{
+			TemperaturesNVML: []nvidia_query_nvml.Temperature{
+				{UUID: inspectGPUHealthy, CurrentCelsiusGPUCore: 40, ThresholdCelsiusSlowdown: 87, ThresholdCelsiusShutdown: 92},
+				{UUID: inspectGPUFaulty, CurrentCelsiusGPUCore: 60, ThresholdCelsiusSlowdown: 87, ThresholdCelsiusShutdown: 92},
+			},
+		}),
+	}
+}
+
+func TestNewInspectReport(t *testing.T) {
+	now := time.Now().UTC()
+	report := newInspectReport("host-1", now, newInspectInfo(t, now))
+
+	if len(report.ImplicatedGPUs) != 1 || report.ImplicatedGPUs[0] != inspectGPUFaulty {
+		t.Fatalf("expected implicated GPU %s, got %v", inspectGPUFaulty, report.ImplicatedGPUs)
+	}
+	if len(report.MissingComponents) != 0 {
+		t.Errorf("unexpected missing components %v", report.MissingComponents)
+	}
+	if len(report.GPUs) != 2 || report.GPUs[0].UUID != inspectGPUHealthy || report.GPUs[1].UUID != inspectGPUFaulty {
+		t.Fatalf("unexpected GPUs %+v", report.GPUs)
+	}
+
+	healthy := report.GPUs[0]
+	if healthy.Implicated {
+		t.Errorf("expected %s not implicated", healthy.UUID)
+	}
+	for _, s := range healthy.Signals {
+		if s.Component == nvidia_xid_id.Name {
+			t.Errorf("unexpected non-critical xid signal %+v", s)
+		}
+	}
+
+	faulty := report.GPUs[1]
+	implicatedBy := make(map[string]bool)
+	for _, s := range faulty.Signals {
+		if s.Implicated {
+			implicatedBy[s.Component] = true
+		}
+	}
+	for _, c := range []string{
+		nvidia_xid_id.Name,
+		nvidia_ecc_id.Name,
+		nvidia_remapped_rows.Name,
+		nvidia_pcie_id.Name,
+	} {
+		if !implicatedBy[c] {
+			t.Errorf("expected %s to implicate %s, got %+v", c, faulty.UUID, faulty.Signals)
+		}
+	}
+	for _, c := range []string{nvidia_nvlink.Name, nvidia_thermal_threshold_id.Name} {
+		if implicatedBy[c] {
+			t.Errorf("expected %s not to implicate %s", c, faulty.UUID)
+		}
+	}
+	if len(faulty.Signals) != 6 {
+		t.Errorf("expected 6 signals, got %+v", faulty.Signals)
+	}
+}
+
+func TestNewInspectReportUndecodedXids(t *testing.T) {
+	now := time.Now().UTC()
+	info := v1.LeptonInfo{
+		{
+			Component: nvidia_xid_id.Name,
+			Info: components.Info{
+				Events: []components.Event{
+					newInspectXidEvent(t, now, inspectGPUFaulty, 79),
+					// raw Xid (e.g., unknown to the older versions), resolved with the default detail
+					{Time: metav1.NewTime(now), Name: nvidia_xid.EventNameErroXid, ExtraInfo: map[string]string{
+						nvidia_xid.EventKeyErroXidData: "999",
+						nvidia_xid.EventKeyDeviceUUID:  inspectGPUHealthy,
+					}},
+					// malformed, skipped
+					{Time: metav1.NewTime(now), Name: nvidia_xid.EventNameErroXid, ExtraInfo: map[string]string{
+						nvidia_xid.EventKeyErroXidData: "{",
+					}},
+				},
+			},
+		},
+	}
+	report := newInspectReport("host-1", now, info)
+
+	for _, c := range report.MissingComponents {
+		if c == nvidia_xid_id.Name {
+			t.Fatalf("unexpected missing component %s", c)
+		}
+	}
+	if report.SkippedXidEvents != 1 {
+		t.Errorf("expected 1 skipped xid event, got %d", report.SkippedXidEvents)
+	}
+	if len(report.ImplicatedGPUs) != 1 || report.ImplicatedGPUs[0] != inspectGPUFaulty {
+		t.Errorf("expected implicated GPU %s, got %v", inspectGPUFaulty, report.ImplicatedGPUs)
+	}
+}
+
+func TestNewInspectReportMissingComponents(t *testing.T) {
+	report := newInspectReport("host-1", time.Now(), v1.LeptonInfo{
+		{Component: nvidia_ecc_id.Name, Info: components.Info{States: []components.State{{Name: "unknown"}}}},
+	})
+	if len(report.GPUs) != 0 || len(report.ImplicatedGPUs) != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if len(report.MissingComponents) != 6 {
+		t.Errorf("expected 6 missing components, got %v", report.MissingComponents)
+	}
+}
+
+func TestCmdInspect(t *testing.T) {
+	now := time.Now().UTC()
+	stubGetInfo(t, newInspectInfo(t, now))
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	if err := app.Run([]string{"gpud", "inspect"}); err != nil {
+		t.Fatalf("failed to run inspect command: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		warningSign + " GPU " + inspectGPUFaulty,
+		checkMark + " GPU " + inspectGPUHealthy,
+		"critical Xid 79",
+		"PCIe link x8 Gen5 (max x16 Gen5)",
+		"1 GPU(s) implicated for the hardware inspection: [" + inspectGPUFaulty + "]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"gpud", "inspect", "--json"}); err != nil {
+		t.Fatalf("failed to run inspect command: %v", err)
+	}
+	var report inspectReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse the JSON report: %v", err)
+	}
+	if len(report.ImplicatedGPUs) != 1 || report.ImplicatedGPUs[0] != inspectGPUFaulty {
+		t.Errorf("expected implicated GPU %s, got %v", inspectGPUFaulty, report.ImplicatedGPUs)
+	}
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/config"
+
+	"github.com/urfave/cli"
+)
+
+// probeComponent is the client function to probe the component in the local gpud, overwritten in tests.
+var probeComponent = client.Probe
+
+var errProbeFailed = errors.New("probe failed")
+
+func cmdProbe(cliContext *cli.Context) error {
+	if cliContext.NArg() != 1 {
+		return errors.New("requires exactly one component name argument")
+	}
+	name := cliContext.Args().First()
+	timeout := cliContext.Duration("timeout")
+
+	// leave room for the server to respond after the probe timeout
+	rootCtx, rootCancel := context.WithTimeout(context.Background(), timeout+30*time.Second)
+	defer rootCancel()
+
+	result, err := probeComponent(rootCtx, fmt.Sprintf("https://localhost:%d", config.DefaultGPUdPort), name, timeout)
+	if err != nil {
+		return fmt.Errorf("failed to probe %s: %w", name, err)
+	}
+
+	if !result.Passed {
+		fmt.Fprintf(cliContext.App.Writer, "%s %s probe failed in %v: %s\n", warningSign, result.Component, result.Latency.Duration, result.Error)
+		return errProbeFailed
+	}
+	fmt.Fprintf(cliContext.App.Writer, "%s %s probe passed in %v", checkMark, result.Component, result.Latency.Duration)
+	if result.Message != "" {
+		fmt.Fprintf(cliContext.App.Writer, ": %s", result.Message)
+	}
+	fmt.Fprintln(cliContext.App.Writer)
+	return nil
+}
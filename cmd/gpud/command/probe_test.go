@@ -0,0 +1,63 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/components"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func stubProbeComponent(t *testing.T, result components.ProbeResult) {
+	orig := probeComponent
+	probeComponent = func(ctx context.Context, addr string, component string, timeout time.Duration, opts ...client.OpOption) (components.ProbeResult, error) {
+		if timeout != 5*time.Second {
+			t.Errorf("expected timeout 5s, got %v", timeout)
+		}
+		result.Component = component
+		return result, nil
+	}
+	t.Cleanup(func() { probeComponent = orig })
+}
+
+func TestCmdProbe(t *testing.T) {
+	stubProbeComponent(t, components.ProbeResult{
+		Passed:  true,
+		Latency: metav1.Duration{Duration: 3 * time.Millisecond},
+		Message: "found 8 GPU(s) via NVML",
+	})
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	if err := app.Run([]string{"gpud", "probe", "--timeout", "5s", "accelerator-nvidia-info"}); err != nil {
+		t.Fatalf("failed to run probe command: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "accelerator-nvidia-info probe passed in 3ms: found 8 GPU(s) via NVML") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestCmdProbeFailed(t *testing.T) {
+	stubProbeComponent(t, components.ProbeResult{
+		Latency: metav1.Duration{Duration: 5 * time.Second},
+		Error:   "probe did not complete: context deadline exceeded",
+	})
+
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	err := app.Run([]string{"gpud", "probe", "--timeout", "5s", "disk"})
+	if !errors.Is(err, errProbeFailed) {
+		t.Fatalf("expected errProbeFailed, got %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "disk probe failed in 5s: probe did not complete") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/internal/repair"
+	pkg_systemd "github.com/leptonai/gpud/pkg/systemd"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/urfave/cli"
+)
+
+func cmdResetGPU(cliContext *cli.Context) error {
+	if cliContext.NArg() != 1 {
+		return errors.New("requires exactly one gpu uuid argument")
+	}
+	uuid := cliContext.Args().First()
+
+	// the gpud daemon holds NVML (and the GPU device files) while running,
+	// thus the reset fails with "in use" unless the daemon is stopped first
+	if cliContext.Bool("yes") && pkg_systemd.SystemctlExists() {
+		if active, err := pkg_systemd.IsActive("gpud.service"); err == nil && active {
+			return errGPUdRunning
+		}
+	}
+
+	nvmlLib := nvidia_query_nvml.NewNVML()
+	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %v", nvml.ErrorString(ret))
+	}
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			_ = nvmlLib.Shutdown()
+		})
+	}
+	defer shutdown()
+
+	devs, err := device.New(nvmlLib).GetDevices()
+	if err != nil {
+		return fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	executor, err := repair.New()
+	if err != nil {
+		return err
+	}
+	reset := func(ctx context.Context, uuid string) error {
+		// release NVML before the reset, otherwise "nvidia-smi --gpu-reset"
+		// fails since this process still holds the GPU
+		shutdown()
+
+		_, err := executor.Execute(ctx, common.RepairActionTypeResetGPU, uuid)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return resetGPU(ctx, cliContext.App.Writer, devs, uuid, cliContext.Bool("yes"), cliContext.Bool("force"), reset)
+}
+
+var (
+	errGPUNotFound        = errors.New("gpu not found")
+	errGPUResetNotAllowed = errors.New("gpu cannot be reset")
+	errGPUHasProcesses    = errors.New("gpu has running processes (use --force to reset anyway)")
+	errGPUdRunning        = errors.New("gpud is running and holds the gpu (stop it first with 'sudo gpud down')")
+)
+
+// resetGPU resets the GPU of the UUID, if the GPU can be reset.
+// Refuses to reset the GPU with running processes unless "force" is true.
+// Only prints the GPU to reset if "yes" is false.
+func resetGPU(ctx context.Context, wr io.Writer, devs []device.Device, uuid string, yes bool, force bool, reset func(ctx context.Context, uuid string) error) error {
+	var dev device.Device
+	for _, d := range devs {
+		u, ret := d.GetUUID()
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get device uuid: %v", nvml.ErrorString(ret))
+		}
+		if u == uuid {
+			dev = d
+			break
+		}
+	}
+	if dev == nil {
+		return fmt.Errorf("%w: %s", errGPUNotFound, uuid)
+	}
+
+	capability, err := nvidia_query_nvml.GetResetCapability(uuid, dev)
+	if err != nil {
+		return err
+	}
+	if !capability.Supported {
+		return fmt.Errorf("%w: %v", errGPUResetNotAllowed, capability.Reasons)
+	}
+	if len(capability.RunningProcessIDs) > 0 {
+		fmt.Fprintf(wr, "%s %s: %d process(es) running %v\n", warningSign, uuid, len(capability.RunningProcessIDs), capability.RunningProcessIDs)
+		if !force {
+			return errGPUHasProcesses
+		}
+	}
+
+	if !yes {
+		fmt.Fprintf(wr, "%s %s: GPU can be reset (re-run with --yes to reset)\n", checkMark, uuid)
+		return nil
+	}
+
+	fmt.Fprintf(wr, "%s %s: resetting GPU\n", inProgress, uuid)
+	if err := reset(ctx, uuid); err != nil {
+		return err
+	}
+	fmt.Fprintf(wr, "%s %s: GPU reset\n", checkMark, uuid)
+	return nil
+}
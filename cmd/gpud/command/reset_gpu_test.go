@@ -0,0 +1,94 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+)
+
+func mockResetGPUDevice(uuid string, pids ...uint32) device.Device {
+	procs := make([]nvml.ProcessInfo, 0, len(pids))
+	for _, pid := range pids {
+		procs = append(procs, nvml.ProcessInfo{Pid: pid})
+	}
+	return testutil.CreateDevice(&mock.Device{
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return uuid, nvml.SUCCESS
+		},
+		GetDisplayActiveFunc: func() (nvml.EnableState, nvml.Return) {
+			return nvml.FEATURE_DISABLED, nvml.SUCCESS
+		},
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return procs, nvml.SUCCESS
+		},
+		GetGraphicsRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return nil, nvml.SUCCESS
+		},
+	})
+}
+
+func TestResetGPU(t *testing.T) {
+	devs := []device.Device{
+		mockResetGPUDevice("GPU-idle"),
+		mockResetGPUDevice("GPU-busy", 1234),
+	}
+	var resets []string
+	reset := func(_ context.Context, uuid string) error {
+		resets = append(resets, uuid)
+		return nil
+	}
+	ctx := context.Background()
+
+	// check only without --yes
+	var buf bytes.Buffer
+	if err := resetGPU(ctx, &buf, devs, "GPU-idle", false, false, reset); err != nil {
+		t.Fatal(err)
+	}
+	if len(resets) != 0 {
+		t.Fatal("expected no reset without --yes")
+	}
+	if !strings.Contains(buf.String(), "GPU-idle: GPU can be reset (re-run with --yes to reset)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := resetGPU(ctx, &buf, devs, "GPU-idle", true, false, reset); err != nil {
+		t.Fatal(err)
+	}
+	if len(resets) != 1 || resets[0] != "GPU-idle" {
+		t.Fatalf("expected idle GPU reset, got %v", resets)
+	}
+
+	// refuses the GPU with running processes
+	buf.Reset()
+	if err := resetGPU(ctx, &buf, devs, "GPU-busy", true, false, reset); !errors.Is(err, errGPUHasProcesses) {
+		t.Fatalf("expected %v, got %v", errGPUHasProcesses, err)
+	}
+	if len(resets) != 1 {
+		t.Fatalf("expected busy GPU not reset, got %v", resets)
+	}
+	if !strings.Contains(buf.String(), "GPU-busy: 1 process(es) running [1234]") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	// unless forced
+	buf.Reset()
+	if err := resetGPU(ctx, &buf, devs, "GPU-busy", true, true, reset); err != nil {
+		t.Fatal(err)
+	}
+	if len(resets) != 2 || resets[1] != "GPU-busy" {
+		t.Fatalf("expected busy GPU reset with --force, got %v", resets)
+	}
+
+	if err := resetGPU(ctx, &buf, devs, "GPU-unknown", true, true, reset); !errors.Is(err, errGPUNotFound) {
+		t.Fatalf("expected %v, got %v", errGPUNotFound, err)
+	}
+}
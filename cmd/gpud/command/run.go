@@ -9,10 +9,12 @@ import (
 	"runtime"
 	"time"
 
+	query_config "github.com/leptonai/gpud/components/query/config"
 	"github.com/leptonai/gpud/config"
 	lepServer "github.com/leptonai/gpud/internal/server"
 	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/manager"
+	"github.com/leptonai/gpud/pkg/sqlite"
 	pkd_systemd "github.com/leptonai/gpud/pkg/systemd"
 	"github.com/leptonai/gpud/version"
 
@@ -45,6 +47,9 @@ func cmdRun(cliContext *cli.Context) error {
 		gin.SetMode(gin.DebugMode)
 	}
 
+	// set before creating the component configs
+	query_config.SetDefaultGetTimeout(pollTimeout)
+
 	configOpts := []config.OpOption{
 		config.WithFilesToCheck(filesToCheck...),
 		config.WithFilesToCheck(filesToCheck...),
@@ -55,6 +60,7 @@ func cmdRun(cliContext *cli.Context) error {
 		config.WithNvidiaSMIQueryCommand(nvidiaSMIQueryCommand),
 		config.WithIbstatCommand(ibstatCommand),
 		config.WithInfinibandClassDirectory(infinibandClassDirectory),
+		config.WithNvidiaSMIXMLFallback(nvidiaSMIXMLFallback),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -94,6 +100,7 @@ func cmdRun(cliContext *cli.Context) error {
 
 	cfg.EnableAutoUpdate = enableAutoUpdate
 	cfg.AutoUpdateExitCode = autoUpdateExitCode
+	cfg.DryRun = dryRun
 
 	if err := cfg.Validate(); err != nil {
 		return err
@@ -112,7 +119,21 @@ func cmdRun(cliContext *cli.Context) error {
 	// start the signal handler as soon as we can to make sure that
 	// we don't miss any signals during boot
 	signal.Notify(signals, handledSignals...)
-	m, err := manager.New()
+	mcfg := manager.Config{RetentionPeriod: manager.DefaultRetentionPeriod}
+	if cfg.State != "" {
+		dbRW, err := sqlite.Open(cfg.State)
+		if err != nil {
+			return err
+		}
+		defer dbRW.Close()
+		dbRO, err := sqlite.Open(cfg.State, sqlite.WithReadOnly(true))
+		if err != nil {
+			return err
+		}
+		defer dbRO.Close()
+		mcfg.DBRW, mcfg.DBRO = dbRW, dbRO
+	}
+	m, err := manager.New(mcfg)
 	if err != nil {
 		return err
 	}
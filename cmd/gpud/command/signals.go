@@ -12,6 +12,7 @@ import (
 	"github.com/leptonai/gpud/pkg/systemd"
 
 	sd "github.com/coreos/go-systemd/v22/daemon"
+	"go.uber.org/zap/zapcore"
 	"golang.org/x/sys/unix"
 )
 
@@ -19,6 +20,7 @@ var handledSignals = []os.Signal{
 	unix.SIGTERM,
 	unix.SIGINT,
 	unix.SIGUSR1,
+	unix.SIGUSR2,
 	unix.SIGPIPE,
 }
 
@@ -26,6 +28,12 @@ func handleSignals(ctx context.Context, cancel context.CancelFunc, signals chan
 	done := make(chan struct{}, 1)
 	go func() {
 		var server *server.Server
+
+		// level to restore when the debug logging is toggled off
+		restoreLvl := log.Logger.Level()
+		if restoreLvl == zapcore.DebugLevel {
+			restoreLvl = zapcore.InfoLevel
+		}
 		for {
 			select {
 			case s := <-serverC:
@@ -42,6 +50,8 @@ func handleSignals(ctx context.Context, cancel context.CancelFunc, signals chan
 				switch s {
 				case unix.SIGUSR1:
 					dumpStacks(true)
+				case unix.SIGUSR2:
+					toggleDebugLogging(restoreLvl)
 				default:
 					cancel()
 
@@ -66,6 +76,17 @@ func handleSignals(ctx context.Context, cancel context.CancelFunc, signals chan
 	return done
 }
 
+// toggleDebugLogging switches the log level to debug without restarting gpud,
+// or back to the restore level if already in debug
+func toggleDebugLogging(restoreLvl zapcore.Level) {
+	if log.Logger.Level() == zapcore.DebugLevel {
+		log.Logger.SetLevel(restoreLvl)
+	} else {
+		log.Logger.SetLevel(zapcore.DebugLevel)
+	}
+	log.Logger.Warnw("log level changed", "level", log.Logger.Level())
+}
+
 // notifyReady notifies systemd that the daemon is ready to serve requests
 func notifyReady(ctx context.Context) error {
 	return sdNotify(ctx, sd.SdNotifyReady)
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	nvidia_component_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/components/simulate"
+
+	"github.com/urfave/cli"
+)
+
+func cmdSimulate(cliContext *cli.Context) error {
+	logFile := cliContext.String("dmesg")
+	switch {
+	case logFile != "" && cliContext.NArg() != 0:
+		return errors.New("cannot set both the log file argument and --dmesg")
+	case logFile == "" && cliContext.NArg() != 1:
+		return errors.New("requires exactly one log file argument (or --dmesg)")
+	case logFile == "":
+		logFile = cliContext.Args().First()
+	}
+
+	opts := []simulate.OpOption{
+		simulate.WithCoalesceWindow(cliContext.Duration("coalesce-window")),
+	}
+	for _, s := range cliContext.StringSlice("quiet-hours") {
+		w, err := parseTimeWindow(s)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, simulate.WithQuietHours(w))
+	}
+	if ignore, forceCritical := cliContext.IntSlice("xid-ignore"), cliContext.IntSlice("xid-force-critical"); len(ignore) > 0 || len(forceCritical) > 0 {
+		opts = append(opts, simulate.WithXidOverrides(nvidia_component_error_xid.XidOverrides{
+			Ignore:        ignore,
+			ForceCritical: forceCritical,
+		}))
+	}
+	if ss := cliContext.StringSlice("event-type-health"); len(ss) > 0 {
+		mapping, err := parseEventTypeHealthMapping(ss)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, simulate.WithEventTypeHealthMapping(mapping))
+	}
+
+	f, err := os.Open(logFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decisions, err := simulate.Run(f, opts...)
+	if err != nil {
+		return err
+	}
+
+	wr := cliContext.App.Writer
+	if cliContext.Bool("json") {
+		return writeJSON(wr, decisions)
+	}
+
+	if len(decisions) == 0 {
+		fmt.Fprintf(wr, "%s no matching event found in %q\n", checkMark, logFile)
+		return nil
+	}
+
+	for _, d := range decisions {
+		mark := checkMark
+		if !d.Coalesced && !d.Alert.Suppressed && d.Alert.Class != common.AlertClassNone {
+			mark = warningSign
+		}
+		fmt.Fprintf(wr, "%s %s\n", mark, d.Summary())
+	}
+	return nil
+}
+
+// parseTimeWindow parses the "HH:MM-HH:MM" time window.
+func parseTimeWindow(s string) (common.TimeWindow, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return common.TimeWindow{}, fmt.Errorf("invalid time window %q (expected 'HH:MM-HH:MM')", s)
+	}
+	w := common.TimeWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+	if err := w.Validate(); err != nil {
+		return common.TimeWindow{}, err
+	}
+	return w, nil
+}
+
+// parseEventTypeHealthMapping parses the "TYPE=true|false" pairs
+// (e.g., "Critical=true" to keep the component healthy on the critical events).
+func parseEventTypeHealthMapping(ss []string) (common.EventTypeHealthMapping, error) {
+	mapping := make(common.EventTypeHealthMapping, len(ss))
+	for _, s := range ss {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid event type health %q (expected 'TYPE=true|false')", s)
+		}
+		healthy, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event type health %q: %w", s, err)
+		}
+		mapping[common.EventType(strings.TrimSpace(k))] = healthy
+	}
+	return mapping, nil
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/simulate"
+)
+
+func TestCmdSimulate(t *testing.T) {
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+
+	const logFile = "../../../components/accelerator/nvidia/error/xid/testdata/dmesg.xid48-xid63.log"
+	if err := app.Run([]string{"gpud", "simulate", "--json", logFile}); err != nil {
+		t.Fatalf("failed to run simulate command: %v", err)
+	}
+	var decisions []simulate.Decision
+	if err := json.Unmarshal(buf.Bytes(), &decisions); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if len(decisions) != 3 {
+		t.Fatalf("expected 3 decisions, got %d", len(decisions))
+	}
+	if !decisions[1].Coalesced {
+		t.Errorf("expected the repeated xid 48 to be coalesced")
+	}
+	last := decisions[len(decisions)-1].State
+	if last == nil || last.Health != components.StateUnhealthy || last.ReasonCode != components.ReasonCodeXidCritical {
+		t.Errorf("expected unhealthy with %q, got %+v", components.ReasonCodeXidCritical, last)
+	}
+
+	if err := app.Run([]string{"gpud", "simulate"}); err == nil {
+		t.Error("expected error without the log file")
+	}
+	if err := app.Run([]string{"gpud", "simulate", "--dmesg", logFile, logFile}); err == nil {
+		t.Error("expected error with both the log file argument and --dmesg")
+	}
+
+	// ignoring xid 63 keeps the state from the preceding xid 48
+	buf.Reset()
+	if err := app.Run([]string{"gpud", "simulate", "--json", "--xid-ignore", "63", logFile}); err != nil {
+		t.Fatalf("failed to run simulate command with xid overrides: %v", err)
+	}
+	decisions = nil
+	if err := json.Unmarshal(buf.Bytes(), &decisions); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	last = decisions[len(decisions)-1].State
+	if last == nil || last.ReasonCode != components.ReasonCodeECCDBE {
+		t.Errorf("expected %q with xid 63 ignored, got %+v", components.ReasonCodeECCDBE, last)
+	}
+
+	if err := app.Run([]string{"gpud", "simulate", "--event-type-health", "Critical", logFile}); err == nil {
+		t.Error("expected error for the invalid event type health mapping")
+	}
+
+	// "replay --dmesg" is an alias of "simulate"
+	buf.Reset()
+	if err := app.Run([]string{"gpud", "replay", "--json", "--dmesg", logFile}); err != nil {
+		t.Fatalf("failed to run replay command: %v", err)
+	}
+	var replayed []simulate.Decision
+	if err := json.Unmarshal(buf.Bytes(), &replayed); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if len(replayed) != len(decisions) {
+		t.Errorf("expected %d decisions from replay, got %d", len(decisions), len(replayed))
+	}
+}
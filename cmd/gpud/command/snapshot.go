@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/config"
+	"github.com/leptonai/gpud/version"
+
+	"github.com/urfave/cli"
+)
+
+// getInfo is the client function to query the local gpud, overwritten in tests.
+var getInfo = client.GetInfo
+
+func cmdSnapshot(cliContext *cli.Context) error {
+	out := cliContext.String("out")
+	if out == "" {
+		return errors.New("--out is required")
+	}
+
+	rootCtx, rootCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer rootCancel()
+
+	snapshot, err := takeSnapshot(rootCtx, fmt.Sprintf("https://localhost:%d", config.DefaultGPUdPort))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	if err := v1.WriteSnapshot(f, snapshot); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+
+	fmt.Fprintf(cliContext.App.Writer, "%s saved the snapshot of %d component(s) to %q\n", checkMark, len(snapshot.Info), out)
+	return nil
+}
+
+// takeSnapshot queries the local gpud for the states, events, and metrics of all components.
+func takeSnapshot(ctx context.Context, addr string) (v1.Snapshot, error) {
+	info, err := getInfo(ctx, addr)
+	if err != nil {
+		return v1.Snapshot{}, fmt.Errorf("failed to get info: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return v1.Snapshot{}, fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	return v1.Snapshot{
+		Header: v1.SnapshotHeader{
+			Time:     time.Now().UTC(),
+			Version:  version.Version,
+			Hostname: hostname,
+		},
+		Info: info,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	client "github.com/leptonai/gpud/client/v1"
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/version"
+)
+
+func TestCmdSnapshot(t *testing.T) {
+	orig := getInfo
+	getInfo = func(ctx context.Context, addr string, opts ...client.OpOption) (v1.LeptonInfo, error) {
+		return v1.LeptonInfo{
+			{
+				Component: "disk",
+				Info: components.Info{
+					States: []components.State{{Name: "disk", Healthy: true, Health: components.StateHealthy}},
+				},
+			},
+		}, nil
+	}
+	t.Cleanup(func() { getInfo = orig })
+
+	out := filepath.Join(t.TempDir(), "snapshot.json.gz")
+
+	app := App()
+	app.Writer = new(bytes.Buffer)
+	if err := app.Run([]string{"gpud", "snapshot", "--out", out}); err != nil {
+		t.Fatalf("failed to run snapshot command: %v", err)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	snapshot, err := v1.LoadSnapshot(f)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Header.Hostname != hostname {
+		t.Errorf("expected hostname %q, got %q", hostname, snapshot.Header.Hostname)
+	}
+	if snapshot.Header.Version != version.Version {
+		t.Errorf("expected version %q, got %q", version.Version, snapshot.Header.Version)
+	}
+	if len(snapshot.Info) != 1 || snapshot.Info[0].Component != "disk" {
+		t.Errorf("unexpected info %+v", snapshot.Info)
+	}
+}
@@ -107,7 +107,7 @@ func checkDiskComponent() error {
 
 	for _, ss := range states {
 		for _, s := range ss.States {
-			log.Logger.Infof("state: %q, healthy: %v, extra info: %q\n", s.Name, s.Healthy, s.ExtraInfo)
+			log.Logger.Infof("state: %q, healthy: %v, stale: %v, last updated: %v, extra info: %q\n", s.Name, s.Healthy, s.Stale, s.LastUpdated, s.ExtraInfo)
 		}
 	}
 
@@ -135,7 +135,7 @@ func checkNvidiaInfoComponent() error {
 
 	for _, ss := range states {
 		for _, s := range ss.States {
-			log.Logger.Infof("state: %q, healthy: %v, extra info: %q\n", s.Name, s.Healthy, s.ExtraInfo)
+			log.Logger.Infof("state: %q, healthy: %v, stale: %v, last updated: %v, extra info: %q\n", s.Name, s.Healthy, s.Stale, s.LastUpdated, s.ExtraInfo)
 		}
 	}
 
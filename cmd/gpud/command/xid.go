@@ -0,0 +1,46 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+
+	"github.com/urfave/cli"
+)
+
+func cmdXid(cliContext *cli.Context) error {
+	if cliContext.Bool("all") {
+		if cliContext.NArg() != 0 {
+			return errors.New("xid id argument is not allowed with --all")
+		}
+		return writeJSON(cliContext.App.Writer, nvidia_query_xid.GetAllDetails())
+	}
+
+	if cliContext.NArg() != 1 {
+		return errors.New("requires exactly one xid id argument (or --all)")
+	}
+	arg := cliContext.Args().First()
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Errorf("invalid xid %q: %w", arg, err)
+	}
+
+	detail, ok := nvidia_query_xid.GetDetail(id)
+	if !ok {
+		return fmt.Errorf("unknown xid %d", id)
+	}
+	return writeJSON(cliContext.App.Writer, detail)
+}
+
+func writeJSON(wr io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(wr, string(b))
+	return err
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+)
+
+func TestCmdXid(t *testing.T) {
+	app := App()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+
+	if err := app.Run([]string{"gpud", "xid", "79"}); err != nil {
+		t.Fatalf("failed to run xid command: %v", err)
+	}
+	var detail nvidia_query_xid.Detail
+	if err := json.Unmarshal(buf.Bytes(), &detail); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if detail.Xid != 79 {
+		t.Errorf("expected xid 79, got %d", detail.Xid)
+	}
+	if detail.Name == "" || detail.EventType == "" {
+		t.Errorf("expected name and event type, got %+v", detail)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"gpud", "xid", "--all"}); err != nil {
+		t.Fatalf("failed to run xid command: %v", err)
+	}
+	var details []nvidia_query_xid.Detail
+	if err := json.Unmarshal(buf.Bytes(), &details); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if len(details) != len(nvidia_query_xid.GetAllDetails()) {
+		t.Errorf("expected %d details, got %d", len(nvidia_query_xid.GetAllDetails()), len(details))
+	}
+}
+
+func TestCmdXidUnknown(t *testing.T) {
+	app := App()
+	app.Writer = new(bytes.Buffer)
+
+	err := app.Run([]string{"gpud", "xid", "100000"})
+	if err == nil {
+		t.Fatal("expected error for unknown xid")
+	}
+	if !strings.Contains(err.Error(), "unknown xid 100000") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -48,7 +48,7 @@ func (c *component) Name() string { return nvidia_clock_speed_id.Name }
 
 func (c *component) Start() error { return nil }
 
-func (c *component) States(ctx context.Context) ([]components.State, error) {
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
 	last, err := c.poller.LastSuccess()
 	if err == query.ErrNoData { // no data
 		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_clock_speed_id.Name)
@@ -68,13 +68,10 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid output type: %T", last.Output)
 	}
-	if lerr := c.poller.LastError(); lerr != nil {
-		log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "error", lerr)
-	}
-	lastSuccessPollElapsed := time.Now().UTC().Sub(allOutput.Time)
-	if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
-		log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
-	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
 
 	if allOutput.SMIExists && len(allOutput.SMIQueryErrors) > 0 {
 		cs := make([]components.State, 0)
@@ -18,6 +18,10 @@ type ToolOverwrites struct {
 	NvidiaSMIQueryCommand    string `json:"nvidia_smi_query_command"`
 	IbstatCommand            string `json:"ibstat_command"`
 	InfinibandClassDirectory string `json:"infiniband_class_directory"`
+
+	// NvidiaSMIXMLFallback collects the "nvidia-smi -q -x" output
+	// for the basic device info when NVML is unavailable.
+	NvidiaSMIXMLFallback bool `json:"nvidia_smi_xml_fallback,omitempty"`
 }
 
 func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
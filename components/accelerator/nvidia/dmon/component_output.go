@@ -0,0 +1,153 @@
+package dmon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_dmon_id "github.com/leptonai/gpud/components/accelerator/nvidia/dmon/id"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	"github.com/leptonai/gpud/components/query"
+)
+
+// ToOutput converts the "nvidia-smi dmon" samples to Output,
+// reporting the markers that are observed in at least the sustained number of consecutive samples.
+func ToOutput(samples []nvidia_query.DmonSample, sustained int) *Output {
+	return &Output{
+		Samples:          len(samples),
+		SustainedSamples: sustained,
+		Findings:         nvidia_query.FindSustainedDmonMarkers(samples, sustained),
+	}
+}
+
+type Output struct {
+	// Samples is the number of per-GPU samples collected.
+	Samples          int                        `json:"samples"`
+	SustainedSamples int                        `json:"sustained_samples"`
+	Findings         []nvidia_query.DmonFinding `json:"findings"`
+}
+
+func (o *Output) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func ParseOutputJSON(data []byte) (*Output, error) {
+	o := new(Output)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+const (
+	StateNameDmon = "dmon"
+
+	StateKeyDmonData           = "data"
+	StateKeyDmonEncoding       = "encoding"
+	StateValueDmonEncodingJSON = "json"
+)
+
+func ParseStateDmon(m map[string]string) (*Output, error) {
+	data := m[StateKeyDmonData]
+	return ParseOutputJSON([]byte(data))
+}
+
+func ParseStatesToOutput(states ...components.State) (*Output, error) {
+	for _, state := range states {
+		switch state.Name {
+		case StateNameDmon:
+			o, err := ParseStateDmon(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			return o, nil
+
+		default:
+			return nil, fmt.Errorf("unknown state name: %s", state.Name)
+		}
+	}
+	return nil, errors.New("no state found")
+}
+
+// Returns the output evaluation reason and its health state.
+// The sustained "ERR!" markers mark the component unhealthy,
+// and the sustained throttle markers mark it degraded.
+func (o *Output) Evaluate() (string, string) {
+	health := components.StateHealthy
+	reasons := []string{}
+	for _, f := range o.Findings {
+		switch f.Marker {
+		case nvidia_query.DmonMarkerError:
+			health = components.StateUnhealthy
+			reasons = append(reasons, fmt.Sprintf("GPU %d reported ERR! for %d consecutive samples", f.GPU, f.Samples))
+		default:
+			if health == components.StateHealthy {
+				health = components.StateDegraded
+			}
+			reasons = append(reasons, fmt.Sprintf("GPU %d reported %s for %d consecutive samples", f.GPU, strings.ReplaceAll(f.Marker, "_", " "), f.Samples))
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("no issue detected in %d samples", o.Samples))
+	}
+	return strings.Join(reasons, "; "), health
+}
+
+func (o *Output) States() ([]components.State, error) {
+	outputReasons, health := o.Evaluate()
+	b, _ := o.JSON()
+	state := components.State{
+		Name:    StateNameDmon,
+		Healthy: health != components.StateUnhealthy,
+		Health:  health,
+		Reason:  outputReasons,
+		ExtraInfo: map[string]string{
+			StateKeyDmonData:     string(b),
+			StateKeyDmonEncoding: StateValueDmonEncodingJSON,
+		},
+	}
+	return []components.State{state}, nil
+}
+
+var (
+	defaultPollerOnce sync.Once
+	defaultPoller     query.Poller
+)
+
+func setDefaultPoller(cfg Config) {
+	defaultPollerOnce.Do(func() {
+		defaultPoller = query.New(
+			nvidia_dmon_id.Name,
+			cfg.Query,
+			CreateGet(cfg),
+			nil,
+		)
+	})
+}
+
+func getDefaultPoller() query.Poller {
+	return defaultPoller
+}
+
+func CreateGet(cfg Config) query.GetFunc {
+	return func(ctx context.Context) (_ any, e error) {
+		defer func() {
+			if e != nil {
+				components_metrics.SetGetFailed(nvidia_dmon_id.Name)
+			} else {
+				components_metrics.SetGetSuccess(nvidia_dmon_id.Name)
+			}
+		}()
+
+		samples, err := nvidia_query.RunDmon(ctx, cfg.NvidiaSMICommand, cfg.Samples)
+		if err != nil {
+			return nil, err
+		}
+		return ToOutput(samples, cfg.SustainedSamples), nil
+	}
+}
@@ -0,0 +1,97 @@
+package dmon
+
+import (
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+)
+
+func TestOutputStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []nvidia_query.DmonSample
+		wantHealth string
+		wantReason string
+	}{
+		{
+			name: "no marker",
+			samples: []nvidia_query.DmonSample{
+				{GPU: 0, Values: map[string]string{"pwr": "70", "pviol": "0", "tviol": "-"}},
+				{GPU: 0, Values: map[string]string{"pwr": "71", "pviol": "0", "tviol": "-"}},
+			},
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected in 2 samples",
+		},
+		{
+			name: "sustained throttle",
+			samples: []nvidia_query.DmonSample{
+				{GPU: 1, Values: map[string]string{"pwr": "700", "pviol": "20", "tviol": "0"}},
+				{GPU: 1, Values: map[string]string{"pwr": "701", "pviol": "35", "tviol": "0"}},
+			},
+			wantHealth: components.StateDegraded,
+			wantReason: "GPU 1 reported power throttle for 2 consecutive samples",
+		},
+		{
+			name: "sustained error",
+			samples: []nvidia_query.DmonSample{
+				{GPU: 2, Values: map[string]string{"pwr": "-", "sm": "ERR!"}},
+				{GPU: 2, Values: map[string]string{"pwr": "-", "sm": "ERR!"}},
+			},
+			wantHealth: components.StateUnhealthy,
+			wantReason: "GPU 2 reported ERR! for 2 consecutive samples",
+		},
+		{
+			name: "non-sustained error",
+			samples: []nvidia_query.DmonSample{
+				{GPU: 2, Values: map[string]string{"pwr": "-", "sm": "ERR!"}},
+				{GPU: 2, Values: map[string]string{"pwr": "-", "sm": "10"}},
+			},
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected in 2 samples",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := ToOutput(tt.samples, 2).States()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if states[0].Health != tt.wantHealth {
+				t.Errorf("expected health %q, got %q", tt.wantHealth, states[0].Health)
+			}
+			if states[0].Healthy != (tt.wantHealth != components.StateUnhealthy) {
+				t.Errorf("unexpected healthy %v for health %q", states[0].Healthy, states[0].Health)
+			}
+			if states[0].Reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, states[0].Reason)
+			}
+
+			parsed, err := ParseStatesToOutput(states...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parsed.Samples != len(tt.samples) || parsed.SustainedSamples != 2 {
+				t.Errorf("unexpected parsed output %+v", parsed)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Samples != DefaultSamples || cfg.SustainedSamples != DefaultSustainedSamples {
+		t.Errorf("unexpected defaults %+v", cfg)
+	}
+
+	cfg = &Config{Samples: 2, SustainedSamples: 3}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for sustained samples exceeding samples")
+	}
+}
@@ -0,0 +1,66 @@
+package dmon
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	query_config "github.com/leptonai/gpud/components/query/config"
+)
+
+const (
+	// DefaultSamples is the default number of "nvidia-smi dmon" samples
+	// (one sample per second) to collect per poll.
+	DefaultSamples = 5
+	// DefaultSustainedSamples is the default number of consecutive samples
+	// with the same marker to report.
+	DefaultSustainedSamples = 3
+)
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// Samples is the number of "nvidia-smi dmon" samples to collect per poll (e.g., "-c 5").
+	// If not set, it defaults to DefaultSamples.
+	Samples int `json:"samples"`
+	// SustainedSamples is the number of consecutive samples with the "ERR!"
+	// or throttle markers at which the GPU is reported.
+	// If not set, it defaults to DefaultSustainedSamples.
+	SustainedSamples int `json:"sustained_samples"`
+
+	nvidia_common.ToolOverwrites
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Samples == 0 {
+		cfg.Samples = DefaultSamples
+	}
+	if cfg.SustainedSamples == 0 {
+		cfg.SustainedSamples = DefaultSustainedSamples
+	}
+	if cfg.Samples < 0 || cfg.SustainedSamples < 0 {
+		return fmt.Errorf("invalid negative samples %d or sustained samples %d", cfg.Samples, cfg.SustainedSamples)
+	}
+	if cfg.SustainedSamples > cfg.Samples {
+		return fmt.Errorf("sustained samples %d exceeds samples %d", cfg.SustainedSamples, cfg.Samples)
+	}
+	return nil
+}
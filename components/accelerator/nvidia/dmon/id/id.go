@@ -0,0 +1,4 @@
+// Package id defines the "nvidia-smi dmon" component ID.
+package id
+
+const Name = "accelerator-nvidia-dmon"
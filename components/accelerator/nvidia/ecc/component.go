@@ -12,6 +12,7 @@ import (
 	nvidia_ecc_id "github.com/leptonai/gpud/components/accelerator/nvidia/ecc/id"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
 	nvidia_query_metrics_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/ecc"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
 	"github.com/leptonai/gpud/components/query"
 	"github.com/leptonai/gpud/log"
 
@@ -48,7 +49,7 @@ func (c *component) Name() string { return nvidia_ecc_id.Name }
 
 func (c *component) Start() error { return nil }
 
-func (c *component) States(ctx context.Context) ([]components.State, error) {
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
 	last, err := c.poller.LastSuccess()
 	if err == query.ErrNoData { // no data
 		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_ecc_id.Name)
@@ -68,13 +69,10 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid output type: %T", last.Output)
 	}
-	if lerr := c.poller.LastError(); lerr != nil {
-		log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "error", lerr)
-	}
-	lastSuccessPollElapsed := time.Now().UTC().Sub(allOutput.Time)
-	if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
-		log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
-	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
 
 	if allOutput.SMIExists && len(allOutput.SMIQueryErrors) > 0 {
 		cs := make([]components.State, 0)
@@ -119,7 +117,24 @@ func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.
 		return nil, fmt.Errorf("failed to read volatile total corrected: %w", err)
 	}
 
-	ms := make([]components.Metric, 0, len(aggTotalCorrecteds)+len(aggTotalUncorrecteds)+len(volTotalCorrecteds)+len(volTotalUncorrecteds))
+	volSingleBits, err := nvidia_query_metrics_ecc.ReadVolatileSingleBit(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volatile single bit: %w", err)
+	}
+	volDoubleBits, err := nvidia_query_metrics_ecc.ReadVolatileDoubleBit(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volatile double bit: %w", err)
+	}
+	aggSingleBits, err := nvidia_query_metrics_ecc.ReadAggregateSingleBit(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aggregate single bit: %w", err)
+	}
+	aggDoubleBits, err := nvidia_query_metrics_ecc.ReadAggregateDoubleBit(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aggregate double bit: %w", err)
+	}
+
+	ms := make([]components.Metric, 0, len(aggTotalCorrecteds)+len(aggTotalUncorrecteds)+len(volTotalCorrecteds)+len(volTotalUncorrecteds)+len(volSingleBits)+len(volDoubleBits)+len(aggSingleBits)+len(aggDoubleBits))
 	for _, m := range aggTotalCorrecteds {
 		ms = append(ms, components.Metric{
 			Metric: m,
@@ -152,6 +167,16 @@ func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.
 			},
 		})
 	}
+	for _, mss := range []components_metrics_state.Metrics{volSingleBits, volDoubleBits, aggSingleBits, aggDoubleBits} {
+		for _, m := range mss {
+			ms = append(ms, components.Metric{
+				Metric: m,
+				ExtraInfo: map[string]string{
+					"gpu_id": m.MetricSecondaryName,
+				},
+			})
+		}
+	}
 
 	return ms, nil
 }
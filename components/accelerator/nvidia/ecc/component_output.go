@@ -127,10 +127,12 @@ func (o *Output) States() ([]components.State, error) {
 	}
 
 	reason := strings.Join(reasons, "; ")
+	reasonCode := ""
 	if len(reason) == 0 {
 		reason = "no issue detected"
 	} else {
 		reason = fmt.Sprintf("note that when an uncorrectable ECC error is detected, the NVIDIA driver software will perform error recovery -- %s", reason)
+		reasonCode = components.ReasonCodeECCDBE
 	}
 
 	b, _ := o.JSON()
@@ -142,7 +144,8 @@ func (o *Output) States() ([]components.State, error) {
 		// ref. https://docs.nvidia.com/deploy/a100-gpu-mem-error-mgmt/index.html
 		Healthy: true,
 
-		Reason: reason,
+		Reason:     reason,
+		ReasonCode: reasonCode,
 		ExtraInfo: map[string]string{
 			StateKeyECCData:     string(b),
 			StateKeyECCEncoding: StateValueECCEncodingJSON,
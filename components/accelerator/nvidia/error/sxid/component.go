@@ -17,6 +17,7 @@ import (
 	"github.com/leptonai/gpud/components"
 	nvidia_component_error_sxid_id "github.com/leptonai/gpud/components/accelerator/nvidia/error/sxid/id"
 	"github.com/leptonai/gpud/components/accelerator/nvidia/query/sxid/dmesg"
+	"github.com/leptonai/gpud/components/common"
 	"github.com/leptonai/gpud/components/db"
 	os_id "github.com/leptonai/gpud/components/os/id"
 	"github.com/leptonai/gpud/log"
@@ -40,10 +41,15 @@ type SXIDComponent struct {
 	currState    components.State
 	extraEventCh chan *components.Event
 	store        db.Store
-	mu           sync.RWMutex
+	// overrides the default event type to health mapping
+	eventTypeHealth common.EventTypeHealthMapping
+	mu              sync.RWMutex
 }
 
-func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB) *SXIDComponent {
+func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB, opts ...OpOption) *SXIDComponent {
+	op := &Op{}
+	op.applyOpts(opts)
+
 	cctx, ccancel := context.WithCancel(ctx)
 
 	extraEventCh := make(chan *components.Event, 256)
@@ -54,10 +60,11 @@ func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB) *SXIDComponent {
 		return nil
 	}
 	return &SXIDComponent{
-		rootCtx:      cctx,
-		cancel:       ccancel,
-		extraEventCh: extraEventCh,
-		store:        localStore,
+		rootCtx:         cctx,
+		cancel:          ccancel,
+		extraEventCh:    extraEventCh,
+		store:           localStore,
+		eventTypeHealth: op.eventTypeHealth,
 	}
 }
 
@@ -146,7 +153,7 @@ func (c *SXIDComponent) start(watcher pkg_dmesg.Watcher, updatePeriod time.Durat
 				continue
 			}
 			c.mu.Lock()
-			c.currState = EvolveHealthyState(events)
+			c.currState = EvolveHealthyStateWithMapping(events, c.eventTypeHealth)
 			c.mu.Unlock()
 		case dmesgLine := <-watcher.Watch():
 			log.Logger.Debugw("dmesg line", "line", dmesgLine)
@@ -183,7 +190,7 @@ func (c *SXIDComponent) start(watcher pkg_dmesg.Watcher, updatePeriod time.Durat
 				continue
 			}
 			c.mu.Lock()
-			c.currState = EvolveHealthyState(events)
+			c.currState = EvolveHealthyStateWithMapping(events, c.eventTypeHealth)
 			c.mu.Unlock()
 		}
 	}
@@ -215,7 +222,7 @@ func (c *SXIDComponent) updateCurrentState() error {
 	}
 	events := mergeEvents(osEvents, localEvents)
 	c.mu.Lock()
-	c.currState = EvolveHealthyState(events)
+	c.currState = EvolveHealthyStateWithMapping(events, c.eventTypeHealth)
 	c.mu.Unlock()
 	return nil
 }
@@ -21,7 +21,14 @@ const rebootThreshold = 2
 
 // EvolveHealthyState resolves the state of the SXID error component.
 // note: assume events are sorted by time in descending order
-func EvolveHealthyState(events []components.Event) (ret components.State) {
+func EvolveHealthyState(events []components.Event) components.State {
+	return EvolveHealthyStateWithMapping(events, nil)
+}
+
+// EvolveHealthyStateWithMapping is EvolveHealthyState with the event type
+// to health mapping overridden (e.g., keep healthy on the critical SXids).
+// note: assume events are sorted by time in descending order
+func EvolveHealthyStateWithMapping(events []components.Event, eventTypeHealth common.EventTypeHealthMapping) (ret components.State) {
 	defer func() {
 		log.Logger.Debugf("EvolveHealthyState: %v", ret)
 	}()
@@ -47,6 +54,17 @@ func EvolveHealthyState(events []components.Event) (ret components.State) {
 			case common.EventTypeFatal:
 				currEvent = StateUnhealthy
 			}
+			if healthy, ok := eventTypeHealth[resolvedEvent.Type]; ok {
+				if healthy {
+					// mapped to healthy, thus neither reported as the last error
+					// nor suggesting the repair actions
+					log.Logger.Debugw("ignoring sxid mapped to healthy", "type", resolvedEvent.Type)
+					continue
+				}
+				if currEvent == StateHealthy {
+					currEvent = StateDegraded
+				}
+			}
 			if currEvent < lastHealth {
 				continue
 			}
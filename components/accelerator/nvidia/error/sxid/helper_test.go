@@ -108,3 +108,25 @@ func TestStateUpdateBasedOnEvents(t *testing.T) {
 		assert.Equal(t, components.StateHealthy, state.Health)
 	})
 }
+
+func TestEvolveHealthyStateWithMapping(t *testing.T) {
+	critical := createSXidEvent(time.Time{}, 123, common.EventTypeCritical, common.RepairActionTypeRebootSystem)
+	fatal := createSXidEvent(time.Time{}, 456, common.EventTypeFatal, common.RepairActionTypeRebootSystem)
+	criticalHealthy := common.EventTypeHealthMapping{common.EventTypeCritical: true}
+
+	state := EvolveHealthyStateWithMapping([]components.Event{critical}, nil)
+	assert.False(t, state.Healthy)
+	assert.Equal(t, components.StateDegraded, state.Health)
+
+	state = EvolveHealthyStateWithMapping([]components.Event{critical}, criticalHealthy)
+	assert.True(t, state.Healthy)
+	assert.Equal(t, components.StateHealthy, state.Health)
+	// neither reported as the error nor suggesting the reboot
+	assert.Empty(t, state.Error)
+	assert.Nil(t, state.SuggestedActions)
+
+	// fatal still marks the component unhealthy
+	state = EvolveHealthyStateWithMapping([]components.Event{critical, fatal}, criticalHealthy)
+	assert.False(t, state.Healthy)
+	assert.Equal(t, components.StateUnhealthy, state.Health)
+}
@@ -0,0 +1,24 @@
+package sxid
+
+import "github.com/leptonai/gpud/components/common"
+
+type Op struct {
+	eventTypeHealth common.EventTypeHealthMapping
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithEventTypeHealthMapping overrides whether the SXid events of the type
+// mark the component unhealthy (e.g., keep healthy on the critical SXids).
+// Defaults to the "Critical" and "Fatal" SXids marking the component unhealthy.
+func WithEventTypeHealthMapping(mapping common.EventTypeHealthMapping) OpOption {
+	return func(op *Op) {
+		op.eventTypeHealth = mapping
+	}
+}
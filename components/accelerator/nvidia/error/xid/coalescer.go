@@ -0,0 +1,150 @@
+package xid
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// coalescer collapses the identical (xid, device uuid) events within a window
+// into a single event, to avoid flooding the event store
+// (e.g., Xid 74 firing hundreds of times per minute on a degrading NVLink).
+// The window starts from the first occurrence, so a continuous flood
+// results in one event per window.
+// Only the latest entry per (xid, device uuid) is kept, so the memory
+// is bounded by the number of distinct xids and GPUs.
+type coalescer struct {
+	window  time.Duration
+	entries map[coalesceKey]*coalesceEntry
+}
+
+// coalesceKey is the (xid, device uuid) key of the event (see XidEventKey).
+type coalesceKey string
+
+// XidEventKey returns the canonical key that identifies the same Xid events,
+// which is the key used internally to coalesce the identical (xid, device uuid) events.
+// The external integrations (e.g., the webhook deduplication) should use this key,
+// to be consistent with the coalescing.
+func XidEventKey(uuid string, xid int) string {
+	return fmt.Sprintf("xid-%d/%s", xid, uuid)
+}
+
+type coalesceEntry struct {
+	// the event as currently stored, used to locate the event to update
+	stored components.Event
+
+	firstSeen time.Time
+	lastSeen  time.Time
+	count     int
+}
+
+func newCoalescer(window time.Duration) *coalescer {
+	return &coalescer{
+		window:  window,
+		entries: make(map[coalesceKey]*coalesceEntry),
+	}
+}
+
+func newCoalesceKey(ev components.Event) coalesceKey {
+	uuid := ev.ExtraInfo[EventKeyDeviceUUID]
+	xid, err := strconv.Atoi(ev.ExtraInfo[EventKeyErroXidData])
+	if err != nil {
+		// not expected for the events created by the component,
+		// still keep the same format to not collapse the malformed events
+		return coalesceKey(fmt.Sprintf("xid-%s/%s", ev.ExtraInfo[EventKeyErroXidData], uuid))
+	}
+	return coalesceKey(XidEventKey(uuid, xid))
+}
+
+// seed loads the coalesced events from the store, so that the dmesg lines
+// replayed after a restart are not counted twice.
+func (c *coalescer) seed(events []components.Event) {
+	for _, ev := range events {
+		if ev.Name != EventNameErroXid || ev.ExtraInfo == nil {
+			continue
+		}
+		ev = withOccurrenceFields(ev)
+		if ev.Occurrences == 0 {
+			continue
+		}
+		lastSeen := ev.LastSeen.Time
+
+		key := newCoalesceKey(ev)
+		if prev, ok := c.entries[key]; ok && !lastSeen.After(prev.lastSeen) {
+			continue
+		}
+		c.entries[key] = &coalesceEntry{
+			stored:    ev,
+			firstSeen: ev.Time.Time,
+			lastSeen:  lastSeen,
+			count:     ev.Occurrences,
+		}
+	}
+}
+
+// add coalesces the event, and returns the event to store with the occurrence count.
+// If the previously stored event is non-nil, the event is a duplicate
+// and the stored one should be updated rather than inserting a new one.
+// Returns false if the event has already been counted (e.g., replayed dmesg line).
+func (c *coalescer) add(ev components.Event) (components.Event, *components.Event, bool) {
+	key := newCoalesceKey(ev)
+
+	entry, ok := c.entries[key]
+	if ok && !ev.Time.Time.After(entry.lastSeen) {
+		return components.Event{}, nil, false
+	}
+
+	if ok && ev.Time.Time.Sub(entry.firstSeen) <= c.window {
+		prev := entry.stored
+
+		entry.count++
+		entry.lastSeen = ev.Time.Time
+		entry.stored = withOccurrence(prev, entry.count, entry.lastSeen)
+		return entry.stored, &prev, true
+	}
+
+	entry = &coalesceEntry{
+		stored:    withOccurrence(ev, 1, ev.Time.Time),
+		firstSeen: ev.Time.Time,
+		lastSeen:  ev.Time.Time,
+		count:     1,
+	}
+	c.entries[key] = entry
+	return entry.stored, nil, true
+}
+
+func withOccurrence(ev components.Event, count int, lastSeen time.Time) components.Event {
+	extraInfo := make(map[string]string, len(ev.ExtraInfo)+2)
+	for k, v := range ev.ExtraInfo {
+		extraInfo[k] = v
+	}
+	extraInfo[EventKeyOccurrenceCount] = strconv.Itoa(count)
+	extraInfo[EventKeyLastSeen] = lastSeen.UTC().Format(time.RFC3339Nano)
+
+	ev.ExtraInfo = extraInfo
+	return withOccurrenceFields(ev)
+}
+
+// withOccurrenceFields sets the first seen, the last seen and the occurrences
+// of the coalesced event from its extra info, as the store only persists the extra info.
+// The event is returned as is if not coalesced.
+func withOccurrenceFields(ev components.Event) components.Event {
+	count, err := strconv.Atoi(ev.ExtraInfo[EventKeyOccurrenceCount])
+	if err != nil {
+		return ev
+	}
+	lastSeen, err := time.Parse(time.RFC3339Nano, ev.ExtraInfo[EventKeyLastSeen])
+	if err != nil {
+		return ev
+	}
+
+	firstSeen := ev.Time
+	ev.FirstSeen = &firstSeen
+	ev.LastSeen = &metav1.Time{Time: lastSeen}
+	ev.Occurrences = count
+	return ev
+}
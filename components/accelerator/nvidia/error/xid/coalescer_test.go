@@ -0,0 +1,187 @@
+package xid
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+type mockWatcher struct {
+	ch chan pkg_dmesg.LogLine
+}
+
+func (w *mockWatcher) Watch() <-chan pkg_dmesg.LogLine { return w.ch }
+func (w *mockWatcher) Close()                          {}
+
+func TestXIDComponent_CoalesceEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+	component := New(ctx, dbRW, dbRO, WithCoalesceWindow(time.Minute))
+	assert.NotNil(t, component)
+
+	watcher := &mockWatcher{ch: make(chan pkg_dmesg.LogLine, 100)}
+	go component.start(watcher, time.Hour)
+	defer func() {
+		if err := component.Close(); err != nil {
+			t.Error("failed to close component")
+		}
+	}()
+
+	startTime := time.Now().Add(-time.Hour).UTC()
+	for i := 0; i < 50; i++ {
+		watcher.ch <- pkg_dmesg.LogLine{
+			Timestamp: startTime.Add(time.Duration(i) * 100 * time.Millisecond),
+			Content:   "NVRM: Xid (PCI:0000:05:00): 74, pid='<unknown>', name=<unknown>, NVLink: fatal error detected on link 0",
+		}
+	}
+
+	var events []components.Event
+	var err error
+	for i := 0; i < 50; i++ {
+		events, err = component.store.Get(ctx, startTime.Add(-time.Minute))
+		assert.NoError(t, err)
+		if len(events) == 1 && events[0].ExtraInfo[EventKeyOccurrenceCount] == "50" {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, "74", events[0].ExtraInfo[EventKeyErroXidData])
+	assert.Equal(t, "PCI:0000:05:00", events[0].ExtraInfo[EventKeyDeviceUUID])
+	assert.Equal(t, "50", events[0].ExtraInfo[EventKeyOccurrenceCount])
+	assert.Equal(t, startTime.Unix(), events[0].Time.Unix())
+	assert.Equal(t, startTime.Add(49*100*time.Millisecond).Format(time.RFC3339Nano), events[0].ExtraInfo[EventKeyLastSeen])
+
+	// the type and suggested actions are stored for the event store sinks
+	detail, ok := nvidia_query_xid.GetDetail(74)
+	assert.True(t, ok)
+	assert.Equal(t, detail.EventType, events[0].Type)
+	assert.Equal(t, detail.SuggestedActionsByGPUd, events[0].SuggestedActions)
+
+	// the coalesced history is exposed in the event fields
+	evs, err := component.Events(ctx, startTime.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, evs, 1)
+	assert.Equal(t, 50, evs[0].Occurrences)
+	assert.NotNil(t, evs[0].FirstSeen)
+	assert.NotNil(t, evs[0].LastSeen)
+	assert.Equal(t, startTime.Unix(), evs[0].FirstSeen.Unix())
+	assert.True(t, evs[0].LastSeen.Time.Equal(startTime.Add(49*100*time.Millisecond)))
+}
+
+func TestXidEventKey(t *testing.T) {
+	assert.Equal(t, "xid-79/GPU-0", XidEventKey("GPU-0", 79))
+	assert.Equal(t, XidEventKey("GPU-0", 79), XidEventKey("GPU-0", 79))
+
+	keys := map[string]struct{}{}
+	for _, uuid := range []string{"GPU-0", "GPU-1", "PCI:0000:05:00"} {
+		for _, xid := range []int{1, 13, 79, 113} {
+			keys[XidEventKey(uuid, xid)] = struct{}{}
+		}
+	}
+	assert.Len(t, keys, 12)
+
+	// same key as the coalescing
+	ev := components.Event{
+		ExtraInfo: map[string]string{
+			EventKeyErroXidData: "79",
+			EventKeyDeviceUUID:  "GPU-0",
+		},
+	}
+	assert.Equal(t, XidEventKey("GPU-0", 79), string(newCoalesceKey(ev)))
+}
+
+func TestCoalescer(t *testing.T) {
+	c := newCoalescer(time.Minute)
+
+	now := time.Now().UTC()
+	newEvent := func(ts time.Time, xid int, uuid string) components.Event {
+		return components.Event{
+			Time: metav1.Time{Time: ts},
+			Name: EventNameErroXid,
+			ExtraInfo: map[string]string{
+				EventKeyErroXidData: strconv.Itoa(xid),
+				EventKeyDeviceUUID:  uuid,
+			},
+		}
+	}
+
+	ev, prev, ok := c.add(newEvent(now, 74, "GPU-0"))
+	assert.True(t, ok)
+	assert.Nil(t, prev)
+	assert.Equal(t, "1", ev.ExtraInfo[EventKeyOccurrenceCount])
+
+	// duplicate within the window updates the first event
+	ev, prev, ok = c.add(newEvent(now.Add(time.Second), 74, "GPU-0"))
+	assert.True(t, ok)
+	assert.NotNil(t, prev)
+	assert.Equal(t, "1", prev.ExtraInfo[EventKeyOccurrenceCount])
+	assert.Equal(t, "2", ev.ExtraInfo[EventKeyOccurrenceCount])
+	assert.Equal(t, now.Unix(), ev.Time.Unix())
+
+	// different device is not coalesced
+	_, prev, ok = c.add(newEvent(now.Add(time.Second), 74, "GPU-1"))
+	assert.True(t, ok)
+	assert.Nil(t, prev)
+
+	// replayed event is skipped
+	_, _, ok = c.add(newEvent(now, 74, "GPU-0"))
+	assert.False(t, ok)
+
+	// new window starts a new event
+	ev, prev, ok = c.add(newEvent(now.Add(2*time.Minute), 74, "GPU-0"))
+	assert.True(t, ok)
+	assert.Nil(t, prev)
+	assert.Equal(t, "1", ev.ExtraInfo[EventKeyOccurrenceCount])
+
+	// seeded from the stored events
+	c2 := newCoalescer(time.Minute)
+	c2.seed([]components.Event{ev})
+	_, _, ok = c2.add(newEvent(now.Add(2*time.Minute), 74, "GPU-0"))
+	assert.False(t, ok)
+	ev, prev, ok = c2.add(newEvent(now.Add(2*time.Minute+time.Second), 74, "GPU-0"))
+	assert.True(t, ok)
+	assert.NotNil(t, prev)
+	assert.Equal(t, "2", ev.ExtraInfo[EventKeyOccurrenceCount])
+}
+
+func TestXIDComponent_AddXidFindsUntypedEvent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+	component := New(ctx, dbRW, dbRO)
+	defer func() {
+		if err := component.Close(); err != nil {
+			t.Error("failed to close component")
+		}
+	}()
+
+	// the older versions stored the event without the type and suggested actions
+	ts := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	assert.NoError(t, component.store.Insert(ctx, components.Event{
+		Time: metav1.Time{Time: ts},
+		Name: EventNameErroXid,
+		ExtraInfo: map[string]string{
+			EventKeyErroXidData: "74",
+			EventKeyDeviceUUID:  "PCI:0000:05:00",
+		},
+	}))
+
+	component.addXid(XidEvent{Time: ts, Xid: 74, DeviceUUID: "PCI:0000:05:00"})
+
+	events, err := component.store.Get(ctx, ts.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
 	"sort"
 	"strconv"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	os_id "github.com/leptonai/gpud/components/os/id"
 	"github.com/leptonai/gpud/log"
 	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+	pkg_host "github.com/leptonai/gpud/pkg/host"
 )
 
 const (
@@ -31,8 +33,22 @@ const (
 	EventKeyErroXidData = "data"
 	EventKeyDeviceUUID  = "device_uuid"
 
+	// number of the identical (xid, device uuid) events coalesced into the event
+	EventKeyOccurrenceCount = "occurrence_count"
+	// time of the last coalesced event in RFC3339 format
+	EventKeyLastSeen = "last_seen"
+	// where the Xid error was observed, only set for the sources other than the kernel messages,
+	// so that the events stored by the older versions (without the key) are still found
+	EventKeyDataSource = "data_source"
+
+	// DataSourceDmesg is the data source of the Xid errors from the kernel messages.
+	DataSourceDmesg = "dmesg"
+	// DataSourceKubernetesNodeEvents is the data source of the Xid errors from the Kubernetes node events.
+	DataSourceKubernetesNodeEvents = "kubernetes-node-events"
+
 	DefaultRetentionPeriod   = 3 * 24 * time.Hour
 	DefaultStateUpdatePeriod = 30 * time.Second
+	DefaultCoalesceWindow    = 5 * time.Minute
 )
 
 type XIDComponent struct {
@@ -40,11 +56,21 @@ type XIDComponent struct {
 	cancel       context.CancelFunc
 	currState    components.State
 	extraEventCh chan *components.Event
+	nodeXidCh    chan XidEvent
+	nodeEvents   *nodeEventsConfig
 	store        db.Store
+	coalescer    *coalescer
+	recentXids   *recentXids
+	logSources   []string
+	overrides    XidOverrides
+	bootTime     time.Time
 	mu           sync.RWMutex
 }
 
-func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB) *XIDComponent {
+func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB, opts ...OpOption) *XIDComponent {
+	op := &Op{}
+	op.applyOpts(opts)
+
 	cctx, ccancel := context.WithCancel(ctx)
 
 	extraEventCh := make(chan *components.Event, 256)
@@ -54,11 +80,24 @@ func New(ctx context.Context, dbRW *sql.DB, dbRO *sql.DB) *XIDComponent {
 		ccancel()
 		return nil
 	}
+	// zero if unknown, used to suppress the reboot suggestions
+	// for the xid errors before the current boot
+	bootTime, err := pkg_host.GetBootTime()
+	if err != nil {
+		log.Logger.Warnw("failed to get boot time", "error", err)
+	}
 	return &XIDComponent{
 		rootCtx:      cctx,
 		cancel:       ccancel,
 		extraEventCh: extraEventCh,
+		nodeXidCh:    make(chan XidEvent, 256),
+		nodeEvents:   op.nodeEvents,
 		store:        localStore,
+		coalescer:    newCoalescer(op.coalesceWindow),
+		recentXids:   newRecentXids(op.recentXidsCapacity, op.recentXidsRetention),
+		logSources:   op.logSources,
+		overrides:    op.overrides,
+		bootTime:     bootTime,
 	}
 }
 
@@ -80,14 +119,34 @@ func (c *XIDComponent) Start() error {
 		}
 		break
 	}
-	watcher, err := pkg_dmesg.NewWatcher()
+
+	// in case the dmesg lines are replayed after restart
+	events, err := c.store.Get(c.rootCtx, time.Now().Add(-DefaultRetentionPeriod))
+	if err != nil {
+		log.Logger.Errorw("failed to get events for coalescing", "error", err)
+	} else {
+		c.coalescer.seed(events)
+		c.recentXids.seed(events, time.Now())
+	}
+
+	watcher, err := newLogSourcesWatcher(c.logSources)
 	if err != nil {
-		log.Logger.Errorw("failed to create dmesg watcher", "error", err)
+		log.Logger.Errorw("failed to create log sources watcher", "sources", c.logSources, "error", err)
 		return nil
 	}
 
 	go c.start(watcher, DefaultStateUpdatePeriod)
 
+	if c.nodeEvents != nil {
+		client, err := newNodeEventsClient(*c.nodeEvents)
+		if err != nil {
+			// optional, keep scanning the log sources
+			log.Logger.Errorw("failed to create kubernetes node events client", "node", c.nodeEvents.nodeName, "error", err)
+		} else {
+			go c.pollNodeEvents(client, DefaultNodeEventsPollInterval)
+		}
+	}
+
 	return nil
 }
 
@@ -104,11 +163,23 @@ func (c *XIDComponent) Events(ctx context.Context, since time.Time) ([]component
 		return nil, err
 	}
 	for _, event := range events {
-		ret = append(ret, resolveXIDEvent(event))
+		xid, err := strconv.Atoi(event.ExtraInfo[EventKeyErroXidData])
+		resolved := resolveXIDEvent(withOccurrenceFields(event))
+		if err == nil {
+			c.overrides.applyTo(xid, &resolved)
+		}
+		ret = append(ret, resolved)
 	}
 	return ret, nil
 }
 
+// RecentXids returns the recent Xid events of the GPU within the duration,
+// in the ascending time order, bounded by the per-GPU capacity and the retention.
+// Returns all the retained events of the GPU if within is zero.
+func (c *XIDComponent) RecentXids(uuid string, within time.Duration) []XidEvent {
+	return c.recentXids.get(uuid, within, time.Now())
+}
+
 func (c *XIDComponent) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
 	log.Logger.Debugw("querying metrics", "since", since)
 
@@ -148,7 +219,7 @@ func (c *XIDComponent) start(watcher pkg_dmesg.Watcher, updatePeriod time.Durati
 				continue
 			}
 			c.mu.Lock()
-			c.currState = EvolveHealthyState(events)
+			c.currState = EvolveHealthyStateWithOverrides(events, c.bootTime, c.overrides)
 			c.mu.Unlock()
 		case dmesgLine := <-watcher.Watch():
 			log.Logger.Debugw("dmesg line", "line", dmesgLine)
@@ -157,38 +228,103 @@ func (c *XIDComponent) start(watcher pkg_dmesg.Watcher, updatePeriod time.Durati
 				log.Logger.Debugw("not xid event, skip")
 				continue
 			}
-			event := components.Event{
-				Time: metav1.Time{Time: dmesgLine.Timestamp},
-				Name: EventNameErroXid,
-				ExtraInfo: map[string]string{
-					EventKeyErroXidData: strconv.FormatInt(int64(xidErr.Xid), 10),
-					EventKeyDeviceUUID:  xidErr.DeviceUUID,
-				},
-			}
-			currEvent, err := c.store.Find(c.rootCtx, event)
-			if err != nil {
-				log.Logger.Errorw("failed to check event existence", "error", err)
-				continue
-			}
+			c.addXid(XidEvent{
+				Time:       dmesgLine.Timestamp,
+				Xid:        int(xidErr.Xid),
+				DeviceUUID: xidErr.DeviceUUID,
+			})
+		case xe := <-c.nodeXidCh:
+			log.Logger.Debugw("xid from node event", "xid", xe.Xid, "deviceUUID", xe.DeviceUUID)
+			c.addXid(xe)
+		}
+	}
+}
 
-			if currEvent != nil {
-				log.Logger.Debugw("no new events created")
-				continue
-			}
-			if err = c.store.Insert(c.rootCtx, event); err != nil {
-				log.Logger.Errorw("failed to create event", "error", err)
-				continue
-			}
-			events, err := c.store.Get(c.rootCtx, time.Time{})
-			if err != nil {
-				log.Logger.Errorw("failed to get all events", "error", err)
-				continue
-			}
-			c.mu.Lock()
-			c.currState = EvolveHealthyState(events)
-			c.mu.Unlock()
+// addXid records the Xid error observed from the log sources or the Kubernetes node events,
+// coalescing the duplicates, and evolves the health state.
+func (c *XIDComponent) addXid(xe XidEvent) {
+	event := newXidEvent(xe, c.overrides)
+
+	currEvent, err := c.findEvent(event)
+	if err != nil {
+		log.Logger.Errorw("failed to check event existence", "error", err)
+		return
+	}
+
+	if currEvent != nil {
+		log.Logger.Debugw("no new events created")
+		return
+	}
+
+	coalesced, prev, ok := c.coalescer.add(event)
+	if !ok {
+		log.Logger.Debugw("event already coalesced, skip")
+		return
+	}
+	c.recentXids.add(xe, time.Now())
+	if prev != nil {
+		// duplicate within the window, only update the count and the last seen time
+		if err = c.store.UpdateExtraInfo(c.rootCtx, *prev, coalesced.ExtraInfo); err != nil {
+			log.Logger.Errorw("failed to update event", "error", err)
 		}
+		return
+	}
+	if err = c.store.Insert(c.rootCtx, coalesced); err != nil {
+		log.Logger.Errorw("failed to create event", "error", err)
+		return
+	}
+	events, err := c.store.Get(c.rootCtx, time.Time{})
+	if err != nil {
+		log.Logger.Errorw("failed to get all events", "error", err)
+		return
+	}
+	c.mu.Lock()
+	c.currState = EvolveHealthyStateWithOverrides(events, c.bootTime, c.overrides)
+	c.mu.Unlock()
+}
+
+// findEvent looks up the stored event by the fields stored by all versions
+// (time, name, and extra info), with the resolved type and without (the older versions
+// stored the events without the type), so that the events stored before the upgrade
+// are not inserted again. The suggested actions are not part of the key,
+// since they are derived from the Xid and may change (e.g., MIG mode).
+func (c *XIDComponent) findEvent(event components.Event) (*components.Event, error) {
+	key := components.Event{
+		Time:      event.Time,
+		Name:      event.Name,
+		Type:      event.Type,
+		ExtraInfo: event.ExtraInfo,
+	}
+	found, err := c.store.Find(c.rootCtx, key)
+	if err != nil || found != nil || key.Type == "" {
+		return found, err
+	}
+
+	key.Type = ""
+	return c.store.Find(c.rootCtx, key)
+}
+
+// newXidEvent creates the event to store for the Xid error.
+func newXidEvent(xe XidEvent, overrides XidOverrides) components.Event {
+	event := components.Event{
+		Time: metav1.Time{Time: xe.Time},
+		Name: EventNameErroXid,
+		ExtraInfo: map[string]string{
+			EventKeyErroXidData: strconv.FormatInt(int64(xe.Xid), 10),
+			EventKeyDeviceUUID:  xe.DeviceUUID,
+		},
+	}
+	if xe.DataSource != "" && xe.DataSource != DataSourceDmesg {
+		event.ExtraInfo[EventKeyDataSource] = xe.DataSource
 	}
+	// also record the type and suggested actions (resolved again on read),
+	// so that the event store sinks (e.g., webhook) can filter and format the event
+	// (resolve modifies the extra info in place, thus copy)
+	resolved := resolveXIDEvent(components.Event{Time: event.Time, Name: event.Name, ExtraInfo: maps.Clone(event.ExtraInfo)})
+	overrides.applyTo(int(xe.Xid), &resolved)
+	event.Type = resolved.Type
+	event.SuggestedActions = resolved.SuggestedActions
+	return event
 }
 
 func (c *XIDComponent) SetHealthy() error {
@@ -217,7 +353,7 @@ func (c *XIDComponent) updateCurrentState() error {
 	}
 	events := mergeEvents(osEvents, localEvents)
 	c.mu.Lock()
-	c.currState = EvolveHealthyState(events)
+	c.currState = EvolveHealthyStateWithOverrides(events, c.bootTime, c.overrides)
 	c.mu.Unlock()
 	return nil
 }
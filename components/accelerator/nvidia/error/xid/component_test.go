@@ -183,6 +183,8 @@ func TestXIDComponent_States(t *testing.T) {
 	defer cleanup()
 	component := New(ctx, dbRW, dbRO)
 	assert.NotNil(t, component)
+	// the test events may be older than the host boot time
+	component.bootTime = time.Time{}
 	watcher, err := pkg_dmesg.NewWatcher()
 	assert.NoError(t, err)
 	go component.start(watcher, 100*time.Millisecond)
@@ -0,0 +1,83 @@
+package xid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+type Config struct {
+	// Log sources to scan the Xid errors from, merged and de-duplicated.
+	// Each source is either "dmesg" for the kernel ring buffer,
+	// "kmsg" for the kernel log device "/dev/kmsg" (e.g., in a container),
+	// an absolute path for a log file (e.g., "/var/log/syslog"),
+	// or a command that streams the logs (e.g., "journalctl -k -f").
+	// Defaults to "dmesg" if empty.
+	XidLogSources []string `json:"xid_log_sources,omitempty"`
+
+	// Xids to ignore when computing the health state (e.g., [13, 31]),
+	// overriding the defaults in the details table.
+	XidIgnoreList []int `json:"xid_ignore_list,omitempty"`
+
+	// Xids to escalate to at least critical when computing the health state (e.g., [43]),
+	// overriding the defaults in the details table.
+	// Must not overlap with XidIgnoreList.
+	XidForceCriticalList []int `json:"xid_force_critical_list,omitempty"`
+
+	// Set true to read the Xid errors from the Kubernetes node events
+	// (e.g., surfaced by the NVIDIA device plugin) in addition to the log sources.
+	XidKubernetesNodeEvents bool `json:"xid_kubernetes_node_events,omitempty"`
+	// Path to the kubeconfig to read the node events with.
+	// Uses the in-cluster config if empty.
+	XidKubeconfig string `json:"xid_kubeconfig,omitempty"`
+	// Name of the Kubernetes node to read the events of.
+	// Defaults to the hostname if empty.
+	XidKubernetesNodeName string `json:"xid_kubernetes_node_name,omitempty"`
+}
+
+var ErrXidInIgnoreAndForceCriticalLists = errors.New("xid in both ignore and force critical lists")
+
+func (cfg Config) Validate() error {
+	for _, xid := range cfg.XidForceCriticalList {
+		if slices.Contains(cfg.XidIgnoreList, xid) {
+			return fmt.Errorf("%w: %d", ErrXidInIgnoreAndForceCriticalLists, xid)
+		}
+	}
+	return nil
+}
+
+func ParseConfig(b any) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	if err := json.Unmarshal(raw, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Options returns the component options for the config.
+func (cfg Config) Options() []OpOption {
+	var opts []OpOption
+	if len(cfg.XidLogSources) > 0 {
+		opts = append(opts, WithLogSources(cfg.XidLogSources...))
+	}
+	if len(cfg.XidIgnoreList) > 0 || len(cfg.XidForceCriticalList) > 0 {
+		opts = append(opts, WithXidOverrides(XidOverrides{
+			Ignore:        cfg.XidIgnoreList,
+			ForceCritical: cfg.XidForceCriticalList,
+		}))
+	}
+	if cfg.XidKubernetesNodeEvents {
+		nodeName := cfg.XidKubernetesNodeName
+		if nodeName == "" {
+			nodeName, _ = os.Hostname()
+		}
+		opts = append(opts, WithKubernetesNodeEvents(cfg.XidKubeconfig, nodeName))
+	}
+	return opts
+}
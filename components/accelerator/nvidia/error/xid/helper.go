@@ -3,12 +3,16 @@ package xid
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/leptonai/gpud/components"
 	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
 	"github.com/leptonai/gpud/components/common"
 	"github.com/leptonai/gpud/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -19,9 +23,43 @@ const (
 
 const rebootThreshold = 2
 
+// XidOverrides overrides the event types of the Xids defined in the details table.
+type XidOverrides struct {
+	// Xids to ignore when evolving the health state
+	// (e.g., Xid 13 routinely triggered by the known workloads).
+	Ignore []int
+	// Xids to escalate to at least critical (degraded health).
+	// Takes precedence over Ignore if an Xid is in both.
+	ForceCritical []int
+	// Overrides whether the event types mark the component unhealthy,
+	// applied after the Xid overrides above.
+	EventTypeHealth common.EventTypeHealthMapping
+}
+
+// applyTo overrides the type of the resolved Xid event.
+// Returns false if the Xid is ignored.
+func (o XidOverrides) applyTo(xid int, ev *components.Event) bool {
+	if slices.Contains(o.ForceCritical, xid) {
+		if ev.Type != common.EventTypeFatal {
+			ev.Type = common.EventTypeCritical
+		}
+		return true
+	}
+	if slices.Contains(o.Ignore, xid) {
+		ev.Type = common.EventTypeInfo
+		ev.SuggestedActions = nil
+		return false
+	}
+	return true
+}
+
 // EvolveHealthyState resolves the state of the XID error component.
 // note: assume events are sorted by time in descending order
-func EvolveHealthyState(events []components.Event) (ret components.State) {
+func EvolveHealthyState(events []components.Event) components.State {
+	return evolveHealthyState(events, XidOverrides{})
+}
+
+func evolveHealthyState(events []components.Event, overrides XidOverrides) (ret components.State) {
 	defer func() {
 		log.Logger.Debugf("EvolveHealthyState: %v", ret)
 	}()
@@ -39,6 +77,10 @@ func EvolveHealthyState(events []components.Event) (ret components.State) {
 				log.Logger.Errorf("failed to unmarshal event %s %s extra info: %s", resolvedEvent.Name, resolvedEvent.Message, err)
 				continue
 			}
+			if !overrides.applyTo(int(currXidErr.Xid), &resolvedEvent) {
+				log.Logger.Debugw("ignoring xid", "xid", currXidErr.Xid)
+				continue
+			}
 
 			currEvent := StateHealthy
 			switch resolvedEvent.Type {
@@ -47,6 +89,17 @@ func EvolveHealthyState(events []components.Event) (ret components.State) {
 			case common.EventTypeFatal:
 				currEvent = StateUnhealthy
 			}
+			if healthy, ok := overrides.EventTypeHealth[resolvedEvent.Type]; ok {
+				if healthy {
+					// mapped to healthy, thus neither reported as the last error
+					// nor suggesting the repair actions
+					log.Logger.Debugw("ignoring xid mapped to healthy", "type", resolvedEvent.Type)
+					continue
+				}
+				if currEvent == StateHealthy {
+					currEvent = StateDegraded
+				}
+			}
 			if currEvent < lastHealth {
 				continue
 			}
@@ -79,6 +132,7 @@ func EvolveHealthyState(events []components.Event) (ret components.State) {
 		}
 	}
 	var reason string
+	var reasonCode string
 	var stateError string
 	if lastXidErr == nil {
 		reason = "XIDComponent is healthy"
@@ -86,17 +140,69 @@ func EvolveHealthyState(events []components.Event) (ret components.State) {
 		xidErrBytes, _ := lastXidErr.JSON()
 		reason = string(xidErrBytes)
 		stateError = fmt.Sprintf("xid %d detected by %s", lastXidErr.Xid, lastXidErr.DataSource)
+		if lastHealth != StateHealthy {
+			reasonCode = xidReasonCode(lastXidErr.Xid)
+		}
 	}
 	return components.State{
 		Name:             StateNameErrorXid,
 		Healthy:          lastHealth == StateHealthy,
 		Health:           translateToStateHealth(lastHealth),
 		Reason:           reason,
+		ReasonCode:       reasonCode,
 		Error:            stateError,
 		SuggestedActions: lastSuggestedAction,
 	}
 }
 
+// xidReasonCode returns the reason code of the state for the critical Xid.
+func xidReasonCode(xid uint64) string {
+	// Xid 48 is the double bit ECC error
+	if xid == 48 {
+		return components.ReasonCodeECCDBE
+	}
+	return components.ReasonCodeXidCritical
+}
+
+// EvolveHealthyStateWithBootTime resolves the state of the XID error component,
+// treating the system boot as a reboot if no reboot event has been recorded since the boot,
+// so that the Xid errors before the boot (e.g., replayed from the log files)
+// do not suggest another reboot.
+// note: assume events are sorted by time in descending order
+func EvolveHealthyStateWithBootTime(events []components.Event, bootTime time.Time) components.State {
+	return EvolveHealthyStateWithOverrides(events, bootTime, XidOverrides{})
+}
+
+// EvolveHealthyStateWithOverrides is EvolveHealthyStateWithBootTime
+// with the Xid event types overridden (e.g., ignoring the benign Xids).
+// note: assume events are sorted by time in descending order
+func EvolveHealthyStateWithOverrides(events []components.Event, bootTime time.Time, overrides XidOverrides) components.State {
+	if bootTime.IsZero() {
+		return evolveHealthyState(events, overrides)
+	}
+
+	sinceBoot := components.FilterEventsSinceBoot(events, bootTime)
+	if len(sinceBoot) == len(events) {
+		// no event before the boot
+		return evolveHealthyState(events, overrides)
+	}
+	for _, ev := range sinceBoot {
+		if ev.Name == "reboot" {
+			// already recorded by the os component
+			return evolveHealthyState(events, overrides)
+		}
+	}
+
+	merged := make([]components.Event, 0, len(events)+1)
+	merged = append(merged, sinceBoot...)
+	merged = append(merged, components.Event{
+		Time: metav1.Time{Time: bootTime},
+		Name: "reboot",
+	})
+	merged = append(merged, events[len(sinceBoot):]...)
+	return evolveHealthyState(merged, overrides)
+}
+
 func translateToStateHealth(health int) string {
 	switch health {
 	case StateHealthy:
@@ -114,19 +220,30 @@ func resolveXIDEvent(event components.Event) components.Event {
 	ret := event
 	if event.ExtraInfo != nil {
 		if currXid, err := strconv.Atoi(event.ExtraInfo[EventKeyErroXidData]); err == nil {
-			detail, ok := nvidia_query_xid.GetDetail(currXid)
-			if !ok {
-				return ret
+			// unknown Xids (e.g., introduced by the newer drivers) are resolved
+			// with the synthesized detail, rather than left unresolved
+			detail := nvidia_query_xid.GetDetailOrDefault(currXid)
+			suggestedActions := detail.SuggestedActionsByGPUd
+			if currXid == 95 {
+				// the guideline differs by the MIG mode (drain the instances vs. reboot immediately)
+				if migEnabled, ok := lookupMIGMode(event.ExtraInfo[EventKeyDeviceUUID]); ok {
+					suggestedActions = nvidia_query_xid.SuggestedActionsForXid95(migEnabled)
+				}
 			}
+
 			ret.Type = detail.EventType
 			ret.Message = fmt.Sprintf("XID %d detected on %s", currXid, event.ExtraInfo[EventKeyDeviceUUID])
-			ret.SuggestedActions = detail.SuggestedActionsByGPUd
+			ret.SuggestedActions = suggestedActions
+			dataSource := event.ExtraInfo[EventKeyDataSource]
+			if dataSource == "" {
+				dataSource = DataSourceDmesg
+			}
 			raw, _ := json.Marshal(&XidError{
 				Time:                      event.Time,
-				DataSource:                "dmesg",
+				DataSource:                dataSource,
 				DeviceUUID:                event.ExtraInfo[EventKeyDeviceUUID],
 				Xid:                       uint64(currXid),
-				SuggestedActionsByGPUd:    detail.SuggestedActionsByGPUd,
+				SuggestedActionsByGPUd:    suggestedActions,
 				CriticalErrorMarkedByGPUd: detail.CriticalErrorMarkedByGPUd,
 			})
 			ret.ExtraInfo[EventKeyErroXidData] = string(raw)
@@ -38,6 +38,7 @@ func TestStateUpdateBasedOnEvents(t *testing.T) {
 		assert.True(t, state.Healthy)
 		assert.Equal(t, components.StateHealthy, state.Health)
 		assert.Equal(t, "XIDComponent is healthy", state.Reason)
+		assert.Empty(t, state.ReasonCode)
 	})
 
 	t.Run("critical xid", func(t *testing.T) {
@@ -48,6 +49,18 @@ func TestStateUpdateBasedOnEvents(t *testing.T) {
 		assert.False(t, state.Healthy)
 		assert.Equal(t, components.StateDegraded, state.Health)
 		assert.Contains(t, state.Error, "xid 123")
+		assert.Equal(t, components.ReasonCodeXidCritical, state.ReasonCode)
+	})
+
+	t.Run("double bit ecc xid", func(t *testing.T) {
+		events := []components.Event{
+			createXidEvent(time.Time{}, 48, common.EventTypeCritical, common.RepairActionTypeRebootSystem),
+		}
+		state := EvolveHealthyState(events)
+		assert.False(t, state.Healthy)
+		assert.Equal(t, components.StateDegraded, state.Health)
+		assert.Contains(t, state.Error, "xid 48")
+		assert.Equal(t, components.ReasonCodeECCDBE, state.ReasonCode)
 	})
 
 	t.Run("fatal xid", func(t *testing.T) {
@@ -68,6 +81,7 @@ func TestStateUpdateBasedOnEvents(t *testing.T) {
 		state := EvolveHealthyState(events)
 		assert.True(t, state.Healthy)
 		assert.Equal(t, components.StateHealthy, state.Health)
+		assert.Empty(t, state.ReasonCode)
 	})
 
 	t.Run("reboot multiple time cannot recover", func(t *testing.T) {
@@ -108,3 +122,156 @@ func TestStateUpdateBasedOnEvents(t *testing.T) {
 		assert.Equal(t, components.StateHealthy, state.Health)
 	})
 }
+
+func TestEvolveHealthyStateWithBootTime(t *testing.T) {
+	bootTime := time.Unix(1737432104, 0).UTC()
+
+	t.Run("xid before boot", func(t *testing.T) {
+		events := []components.Event{
+			createXidEvent(bootTime.Add(-time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+		}
+		state := EvolveHealthyStateWithBootTime(events, bootTime)
+		assert.True(t, state.Healthy)
+		assert.Equal(t, components.StateHealthy, state.Health)
+		assert.Nil(t, state.SuggestedActions)
+	})
+
+	t.Run("xid after boot", func(t *testing.T) {
+		events := []components.Event{
+			createXidEvent(bootTime.Add(time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+			createXidEvent(bootTime.Add(-time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+		}
+		state := EvolveHealthyStateWithBootTime(events, bootTime)
+		assert.False(t, state.Healthy)
+		assert.Equal(t, common.RepairActionTypeRebootSystem, state.SuggestedActions.RepairActions[0])
+	})
+
+	t.Run("reboot already recorded", func(t *testing.T) {
+		events := []components.Event{
+			{Time: metav1.Time{Time: bootTime.Add(time.Minute)}, Name: "reboot"},
+			createXidEvent(bootTime.Add(-time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+			{Time: metav1.Time{Time: bootTime.Add(-2 * time.Hour)}, Name: "reboot"},
+			createXidEvent(bootTime.Add(-3*time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+		}
+		state := EvolveHealthyStateWithBootTime(events, bootTime)
+		assert.True(t, state.Healthy)
+		assert.Nil(t, state.SuggestedActions)
+	})
+
+	t.Run("zero boot time", func(t *testing.T) {
+		events := []components.Event{
+			createXidEvent(bootTime.Add(-time.Hour), 79, common.EventTypeFatal, common.RepairActionTypeRebootSystem),
+		}
+		state := EvolveHealthyStateWithBootTime(events, time.Time{})
+		assert.False(t, state.Healthy)
+		assert.Equal(t, common.RepairActionTypeRebootSystem, state.SuggestedActions.RepairActions[0])
+	})
+}
+
+func TestEvolveHealthyStateWithOverrides(t *testing.T) {
+	newEvent := func(xid string) components.Event {
+		return components.Event{
+			Time: metav1.Time{Time: time.Now()},
+			Name: EventNameErroXid,
+			ExtraInfo: map[string]string{
+				EventKeyErroXidData: xid,
+				EventKeyDeviceUUID:  "GPU-0",
+			},
+		}
+	}
+
+	t.Run("ignore xid 13", func(t *testing.T) {
+		state := EvolveHealthyStateWithOverrides([]components.Event{newEvent("13")}, time.Time{}, XidOverrides{})
+		assert.Equal(t, components.StateHealthy, state.Health)
+		assert.NotNil(t, state.SuggestedActions)
+
+		state = EvolveHealthyStateWithOverrides([]components.Event{newEvent("13")}, time.Time{}, XidOverrides{Ignore: []int{13}})
+		assert.Equal(t, components.StateHealthy, state.Health)
+		assert.Nil(t, state.SuggestedActions)
+		assert.Equal(t, "XIDComponent is healthy", state.Reason)
+	})
+
+	t.Run("force critical xid 43", func(t *testing.T) {
+		state := EvolveHealthyStateWithOverrides([]components.Event{newEvent("43")}, time.Time{}, XidOverrides{})
+		assert.Equal(t, components.StateHealthy, state.Health)
+
+		state = EvolveHealthyStateWithOverrides([]components.Event{newEvent("43")}, time.Time{}, XidOverrides{ForceCritical: []int{43}})
+		assert.Equal(t, components.StateDegraded, state.Health)
+		assert.False(t, state.Healthy)
+		assert.Equal(t, "xid 43 detected by dmesg", state.Error)
+	})
+
+	t.Run("force critical wins over ignore", func(t *testing.T) {
+		state := EvolveHealthyStateWithOverrides([]components.Event{newEvent("43")}, time.Time{}, XidOverrides{Ignore: []int{43}, ForceCritical: []int{43}})
+		assert.Equal(t, components.StateDegraded, state.Health)
+	})
+
+	t.Run("ignored xid does not mask the others", func(t *testing.T) {
+		events := []components.Event{newEvent("13"), newEvent("43")}
+		state := EvolveHealthyStateWithOverrides(events, time.Time{}, XidOverrides{Ignore: []int{13}, ForceCritical: []int{43}})
+		assert.Equal(t, components.StateDegraded, state.Health)
+	})
+}
+
+func TestEvolveHealthyStateWithEventTypeHealth(t *testing.T) {
+	critical := createXidEvent(time.Time{}, 123, common.EventTypeCritical, common.RepairActionTypeRebootSystem)
+	fatal := createXidEvent(time.Time{}, 456, common.EventTypeFatal, common.RepairActionTypeRebootSystem)
+	criticalHealthy := XidOverrides{EventTypeHealth: common.EventTypeHealthMapping{common.EventTypeCritical: true}}
+
+	t.Run("critical still healthy", func(t *testing.T) {
+		state := EvolveHealthyStateWithOverrides([]components.Event{critical}, time.Time{}, XidOverrides{})
+		assert.False(t, state.Healthy)
+		assert.Equal(t, components.StateDegraded, state.Health)
+
+		state = EvolveHealthyStateWithOverrides([]components.Event{critical}, time.Time{}, criticalHealthy)
+		assert.True(t, state.Healthy)
+		assert.Equal(t, components.StateHealthy, state.Health)
+		assert.Empty(t, state.ReasonCode)
+		// neither reported as the error nor suggesting the reboot
+		assert.Empty(t, state.Error)
+		assert.Nil(t, state.SuggestedActions)
+	})
+
+	t.Run("fatal unaffected", func(t *testing.T) {
+		state := EvolveHealthyStateWithOverrides([]components.Event{critical, fatal}, time.Time{}, criticalHealthy)
+		assert.False(t, state.Healthy)
+		assert.Equal(t, components.StateUnhealthy, state.Health)
+		assert.Contains(t, state.Error, "xid 456")
+	})
+
+	t.Run("warning unhealthy", func(t *testing.T) {
+		warning := createXidEvent(time.Time{}, 789, common.EventTypeWarning, common.RepairActionTypeIgnoreNoActionRequired)
+		state := EvolveHealthyStateWithOverrides([]components.Event{warning}, time.Time{}, XidOverrides{})
+		assert.True(t, state.Healthy)
+
+		state = EvolveHealthyStateWithOverrides([]components.Event{warning}, time.Time{}, XidOverrides{EventTypeHealth: common.EventTypeHealthMapping{common.EventTypeWarning: false}})
+		assert.False(t, state.Healthy)
+		assert.Equal(t, components.StateDegraded, state.Health)
+	})
+}
+
+func TestConfigValidateXidLists(t *testing.T) {
+	cfg := Config{XidIgnoreList: []int{13, 31}, XidForceCriticalList: []int{43}}
+	assert.NoError(t, cfg.Validate())
+	assert.Len(t, cfg.Options(), 1)
+
+	cfg = Config{XidIgnoreList: []int{13, 43}, XidForceCriticalList: []int{43}}
+	assert.ErrorIs(t, cfg.Validate(), ErrXidInIgnoreAndForceCriticalLists)
+}
+
+func TestResolveXIDEventUnknownXid(t *testing.T) {
+	resolved := resolveXIDEvent(components.Event{
+		Name: EventNameErroXid,
+		ExtraInfo: map[string]string{
+			EventKeyErroXidData: "999",
+			EventKeyDeviceUUID:  "GPU-abc",
+		},
+	})
+	assert.Equal(t, common.EventTypeWarning, resolved.Type)
+	assert.Equal(t, "XID 999 detected on GPU-abc", resolved.Message)
+
+	var xidErr XidError
+	assert.NoError(t, json.Unmarshal([]byte(resolved.ExtraInfo[EventKeyErroXidData]), &xidErr))
+	assert.Equal(t, uint64(999), xidErr.Xid)
+	assert.False(t, xidErr.CriticalErrorMarkedByGPUd)
+}
@@ -0,0 +1,223 @@
+package xid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/xid/dmesg"
+	"github.com/leptonai/gpud/log"
+)
+
+// DefaultNodeEventsPollInterval is the default interval to list the Kubernetes node events.
+const DefaultNodeEventsPollInterval = time.Minute
+
+// nodeEventsConfig is the config to read the Xid errors from the Kubernetes node events
+// (e.g., surfaced by the NVIDIA device plugin).
+type nodeEventsConfig struct {
+	// path to the kubeconfig, or empty to use the in-cluster config
+	kubeconfig string
+	// name of the node to list the events of
+	nodeName string
+}
+
+// nodeEventsClient lists the Kubernetes events of the node from the API server.
+// The client-go REST config handles the kubeconfig (or in-cluster) auth,
+// and the events list is decoded with the core/v1 types.
+// The typed clientset is not used, since the client-go in go.mod
+// does not build against the newer k8s.io/api.
+type nodeEventsClient struct {
+	server     string
+	nodeName   string
+	httpClient *http.Client
+}
+
+func newNodeEventsClient(cfg nodeEventsConfig) (*nodeEventsClient, error) {
+	if cfg.nodeName == "" {
+		return nil, errors.New("node name is required")
+	}
+
+	var restCfg *rest.Config
+	var err error
+	if cfg.kubeconfig == "" {
+		restCfg, err = rest.InClusterConfig()
+	} else {
+		restCfg, err = clientcmd.BuildConfigFromFlags("", cfg.kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes client config: %w", err)
+	}
+	restCfg.Timeout = 30 * time.Second
+
+	httpClient, err := rest.HTTPClientFor(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes http client: %w", err)
+	}
+	return &nodeEventsClient{
+		server:     strings.TrimSuffix(restCfg.Host, "/"),
+		nodeName:   cfg.nodeName,
+		httpClient: httpClient,
+	}, nil
+}
+
+// list lists the events of the node.
+func (c *nodeEventsClient) list(ctx context.Context) ([]corev1.Event, error) {
+	q := url.Values{}
+	q.Set("fieldSelector", "involvedObject.kind=Node,involvedObject.name="+c.nodeName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+"/api/v1/events?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing node events failed %d", resp.StatusCode)
+	}
+
+	evs := new(corev1.EventList)
+	if err := json.NewDecoder(resp.Body).Decode(evs); err != nil {
+		return nil, err
+	}
+	return evs.Items, nil
+}
+
+var (
+	// e.g., "XidCriticalError: Xid=79 on Device=GPU-...; marking device as unhealthy."
+	nodeEventXidRegex     = regexp.MustCompile(`(?i)\bxid[\s=:#]{1,3}(\d+)`)
+	nodeEventGPUUUIDRegex = regexp.MustCompile(`GPU-[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}`)
+)
+
+// parseNodeEventXid returns the Xid error reported in the node event.
+// Returns false if the event is not about an Xid error.
+func parseNodeEventXid(ev corev1.Event) (XidEvent, bool) {
+	if ev.InvolvedObject.Kind != "Node" {
+		return XidEvent{}, false
+	}
+
+	xe := XidEvent{Time: nodeEventTime(ev), DataSource: DataSourceKubernetesNodeEvents}
+
+	// the kernel message may be forwarded as is
+	if xidErr := dmesg.Match(ev.Message); xidErr != nil {
+		xe.Xid = xidErr.Xid
+		xe.DeviceUUID = xidErr.DeviceUUID
+	} else {
+		m := nodeEventXidRegex.FindStringSubmatch(ev.Message)
+		if m == nil {
+			return XidEvent{}, false
+		}
+		xid, err := strconv.Atoi(m[1])
+		if err != nil || xid == 0 {
+			return XidEvent{}, false
+		}
+		xe.Xid = xid
+	}
+	if xe.DeviceUUID == "" {
+		xe.DeviceUUID = nodeEventGPUUUIDRegex.FindString(ev.Message)
+	}
+	return xe, true
+}
+
+// nodeEventTime returns the time of the latest occurrence of the event.
+func nodeEventTime(ev corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// nodeEventsSeen tracks the occurrence counts of the node events already processed,
+// so that only the new occurrences are reported on every poll.
+type nodeEventsSeen map[string]int32
+
+// newXids returns the Xid errors of the events not seen before,
+// or seen with a lower occurrence count (the event is updated on the repeated occurrences).
+// The events no longer listed (e.g., expired by the API server) are forgotten,
+// so "evs" must be the full list of the node events.
+func (s nodeEventsSeen) newXids(evs []corev1.Event) []XidEvent {
+	listed := make(map[string]struct{}, len(evs))
+
+	var xids []XidEvent
+	for _, ev := range evs {
+		key := string(ev.UID)
+		if key == "" {
+			key = ev.Namespace + "/" + ev.Name
+		}
+		listed[key] = struct{}{}
+
+		count := ev.Count
+		if count == 0 {
+			count = 1
+		}
+		if prev, ok := s[key]; ok && prev >= count {
+			continue
+		}
+		s[key] = count
+
+		xe, ok := parseNodeEventXid(ev)
+		if !ok {
+			continue
+		}
+		xids = append(xids, xe)
+	}
+
+	for key := range s {
+		if _, ok := listed[key]; !ok {
+			delete(s, key)
+		}
+	}
+	return xids
+}
+
+// pollNodeEvents lists the node events every interval, and sends the new Xid errors.
+func (c *XIDComponent) pollNodeEvents(client *nodeEventsClient, interval time.Duration) {
+	seen := make(nodeEventsSeen)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cctx, ccancel := context.WithTimeout(c.rootCtx, time.Minute)
+		evs, err := client.list(cctx)
+		ccancel()
+		var xids []XidEvent
+		if err != nil {
+			// keep the seen events, not to report them again on the next successful list
+			log.Sampled("xid-node-events-list-failed", 10*time.Minute).Warnw("failed to list node events", "node", client.nodeName, "error", err)
+		} else {
+			xids = seen.newXids(evs)
+		}
+		for _, xe := range xids {
+			select {
+			case <-c.rootCtx.Done():
+				return
+			case c.nodeXidCh <- xe:
+			}
+		}
+
+		select {
+		case <-c.rootCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package xid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+const testGPUUUID = "GPU-6e6b1a43-1b61-7a5a-2c4f-5e8c1b2a3d4f"
+
+func newGPUNodeEvent(uid string, nodeName string, xid string, count int32, ts time.Time) corev1.Event {
+	return corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName + "." + uid, Namespace: "default", UID: types.UID(uid)},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: nodeName,
+		},
+		Reason:        "XidCriticalError",
+		Message:       "XidCriticalError: Xid=" + xid + " on Device=" + testGPUUUID + "; marking device as unhealthy.",
+		Type:          corev1.EventTypeWarning,
+		Count:         count,
+		LastTimestamp: metav1.NewTime(ts),
+	}
+}
+
+// fakeAPIServer serves the node events like the Kubernetes API server.
+type fakeAPIServer struct {
+	mu  sync.Mutex
+	evs []corev1.Event
+
+	fieldSelector string
+	authorization string
+}
+
+func (s *fakeAPIServer) setEvents(evs ...corev1.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evs = evs
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v1/events" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fieldSelector = r.URL.Query().Get("fieldSelector")
+	s.authorization = r.Header.Get("Authorization")
+	_ = json.NewEncoder(w).Encode(corev1.EventList{Items: s.evs})
+}
+
+func writeTestKubeconfig(t *testing.T, server string) string {
+	t.Helper()
+
+	// client-go only sends the credentials over TLS
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: test
+contexts:
+- name: test
+  context:
+    cluster: test-cluster
+    user: test-user
+clusters:
+- name: test-cluster
+  cluster:
+    server: ` + server + `
+    insecure-skip-tls-verify: true
+users:
+- name: test-user
+  user:
+    token: test-token
+`
+	p := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(p, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseNodeEventXid(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	xe, ok := parseNodeEventXid(newGPUNodeEvent("1", "node-1", "79", 1, now))
+	assert.True(t, ok)
+	assert.Equal(t, XidEvent{Time: now, Xid: 79, DeviceUUID: testGPUUUID, DataSource: DataSourceKubernetesNodeEvents}, xe)
+
+	// kernel message forwarded as is
+	ev := newGPUNodeEvent("2", "node-1", "0", 1, now)
+	ev.Message = "NVRM: Xid (PCI:0000:9b:00): 48, pid=1234, name=python, DBE (0x1)"
+	xe, ok = parseNodeEventXid(ev)
+	assert.True(t, ok)
+	assert.Equal(t, 48, xe.Xid)
+
+	// not an xid event
+	ev = newGPUNodeEvent("3", "node-1", "0", 1, now)
+	ev.Message = "Node node-1 status is now: NodeReady"
+	_, ok = parseNodeEventXid(ev)
+	assert.False(t, ok)
+
+	// not a node event
+	ev = newGPUNodeEvent("4", "node-1", "79", 1, now)
+	ev.InvolvedObject.Kind = "Pod"
+	_, ok = parseNodeEventXid(ev)
+	assert.False(t, ok)
+}
+
+func TestNodeEventsSeen(t *testing.T) {
+	now := time.Now().UTC()
+	seen := make(nodeEventsSeen)
+
+	xids := seen.newXids([]corev1.Event{newGPUNodeEvent("1", "node-1", "79", 1, now)})
+	assert.Len(t, xids, 1)
+
+	// same event, nothing new
+	xids = seen.newXids([]corev1.Event{newGPUNodeEvent("1", "node-1", "79", 1, now)})
+	assert.Len(t, xids, 0)
+
+	// repeated occurrence of the same event
+	xids = seen.newXids([]corev1.Event{newGPUNodeEvent("1", "node-1", "79", 2, now.Add(time.Minute))})
+	assert.Len(t, xids, 1)
+	assert.Equal(t, now.Add(time.Minute), xids[0].Time)
+
+	// the events no longer listed are forgotten
+	xids = seen.newXids([]corev1.Event{newGPUNodeEvent("2", "node-1", "48", 1, now)})
+	assert.Len(t, xids, 1)
+	assert.Len(t, seen, 1)
+	_, ok := seen["1"]
+	assert.False(t, ok)
+}
+
+func TestNodeEventsClientKubeconfig(t *testing.T) {
+	fake := &fakeAPIServer{}
+	fake.setEvents(newGPUNodeEvent("1", "node-1", "79", 1, time.Now()))
+	srv := httptest.NewTLSServer(fake)
+	defer srv.Close()
+
+	client, err := newNodeEventsClient(nodeEventsConfig{
+		kubeconfig: writeTestKubeconfig(t, srv.URL),
+		nodeName:   "node-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err := client.list(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, evs, 1)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, "involvedObject.kind=Node,involvedObject.name=node-1", fake.fieldSelector)
+	assert.Equal(t, "Bearer test-token", fake.authorization)
+}
+
+func TestNodeEventsClientInvalidConfig(t *testing.T) {
+	_, err := newNodeEventsClient(nodeEventsConfig{kubeconfig: "/does/not/exist", nodeName: "node-1"})
+	assert.Error(t, err)
+
+	_, err = newNodeEventsClient(nodeEventsConfig{kubeconfig: writeTestKubeconfig(t, "http://localhost"), nodeName: ""})
+	assert.Error(t, err)
+}
+
+func TestXIDComponentNodeEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	fake := &fakeAPIServer{}
+	now := time.Now().UTC().Truncate(time.Second)
+	fake.setEvents(newGPUNodeEvent("1", "node-1", "79", 1, now.Add(-time.Minute)))
+	srv := httptest.NewTLSServer(fake)
+	defer srv.Close()
+
+	component := New(ctx, dbRW, dbRO, WithKubernetesNodeEvents(writeTestKubeconfig(t, srv.URL), "node-1"))
+	assert.NotNil(t, component)
+	component.bootTime = time.Time{}
+	defer func() {
+		if err := component.Close(); err != nil {
+			t.Error("failed to close component")
+		}
+	}()
+
+	watcher, err := pkg_dmesg.NewWatcher()
+	assert.NoError(t, err)
+	go component.start(watcher, time.Second)
+
+	client, err := newNodeEventsClient(*component.nodeEvents)
+	assert.NoError(t, err)
+	go component.pollNodeEvents(client, 100*time.Millisecond)
+
+	var xids []XidEvent
+	for i := 0; i < 50; i++ {
+		if xids = component.RecentXids(testGPUUUID, time.Hour); len(xids) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if len(xids) != 1 || xids[0].Xid != 79 {
+		t.Fatalf("expected xid 79 from the node event, got %+v", xids)
+	}
+
+	events, err := component.Events(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventNameErroXid, events[0].Name)
+	var xidErr XidError
+	assert.NoError(t, json.Unmarshal([]byte(events[0].ExtraInfo[EventKeyErroXidData]), &xidErr))
+	assert.Equal(t, uint64(79), xidErr.Xid)
+	assert.Equal(t, testGPUUUID, xidErr.DeviceUUID)
+	assert.Equal(t, DataSourceKubernetesNodeEvents, xidErr.DataSource)
+
+	states, err := component.States(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, states, 1)
+	assert.False(t, states[0].Healthy)
+}
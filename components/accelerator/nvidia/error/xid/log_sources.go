@@ -0,0 +1,190 @@
+package xid
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/log"
+	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+)
+
+// LogSourceDmesg is the log source to watch the kernel ring buffer.
+const LogSourceDmesg = "dmesg"
+
+// LogSourceKmsg is the log source to read the kernel ring buffer from "/dev/kmsg"
+// (e.g., gpud running in a container without the "dmesg" binary).
+const LogSourceKmsg = "kmsg"
+
+// DefaultLogSources is the default log sources to scan the Xid errors from.
+var DefaultLogSources = []string{LogSourceDmesg}
+
+// dedupWindow is the window within which the same Xid line from
+// different log sources is considered duplicate.
+const dedupWindow = 5 * time.Minute
+
+// newLogSourceWatcher creates the watcher for the log source:
+// "dmesg" for the kernel ring buffer, "kmsg" (or "/dev/kmsg") for the kernel log device,
+// an absolute path for a log file (e.g., "/var/log/syslog"),
+// or otherwise a command that streams the logs (e.g., "journalctl -k -f").
+func newLogSourceWatcher(src string) (pkg_dmesg.Watcher, error) {
+	switch {
+	case src == LogSourceDmesg:
+		return pkg_dmesg.NewWatcher()
+	case src == LogSourceKmsg, src == pkg_dmesg.DefaultKmsgFile:
+		return pkg_dmesg.NewKmsgWatcher(pkg_dmesg.DefaultKmsgFile)
+	case strings.HasPrefix(src, "/"):
+		return pkg_dmesg.NewFileWatcher(src)
+	default:
+		return pkg_dmesg.NewWatcherWithCommands([][]string{{src}})
+	}
+}
+
+// newLogSourcesWatcher creates the watcher that merges the lines
+// from all the log sources, de-duplicating the Xid lines seen from multiple sources.
+// The sources that fail to start are skipped.
+func newLogSourcesWatcher(srcs []string) (pkg_dmesg.Watcher, error) {
+	watchers := make([]pkg_dmesg.Watcher, 0, len(srcs))
+	var lastErr error
+	for _, src := range srcs {
+		w, err := newLogSourceWatcher(src)
+		if err != nil {
+			log.Logger.Errorw("failed to create log source watcher", "source", src, "error", err)
+			lastErr = err
+			continue
+		}
+		watchers = append(watchers, w)
+	}
+	if len(watchers) == 0 {
+		return nil, lastErr
+	}
+	if len(watchers) == 1 {
+		return watchers[0], nil
+	}
+	return newMultiWatcher(watchers...), nil
+}
+
+type sourcedLine struct {
+	src  int
+	line pkg_dmesg.LogLine
+}
+
+type multiWatcher struct {
+	watchers []pkg_dmesg.Watcher
+	ch       chan pkg_dmesg.LogLine
+	cancel   context.CancelFunc
+}
+
+func newMultiWatcher(watchers ...pkg_dmesg.Watcher) *multiWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &multiWatcher{
+		watchers: watchers,
+		ch:       make(chan pkg_dmesg.LogLine, 1000),
+		cancel:   cancel,
+	}
+
+	merged := make(chan sourcedLine, 1000)
+	var wg sync.WaitGroup
+	for i, watcher := range watchers {
+		wg.Add(1)
+		go func(src int, watcher pkg_dmesg.Watcher) {
+			defer wg.Done()
+			for line := range watcher.Watch() {
+				select {
+				case <-ctx.Done():
+					return
+				case merged <- sourcedLine{src: src, line: line}:
+				}
+			}
+		}(i, watcher)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	go func() {
+		defer close(w.ch)
+
+		dedup := newSourceDedup(dedupWindow)
+		for sl := range merged {
+			if dedup.isDuplicate(sl.src, sl.line.Content, time.Now()) {
+				log.Logger.Debugw("duplicate xid line from another log source, skip", "line", sl.line.Content)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case w.ch <- sl.line:
+			}
+		}
+	}()
+
+	return w
+}
+
+func (w *multiWatcher) Watch() <-chan pkg_dmesg.LogLine {
+	return w.ch
+}
+
+func (w *multiWatcher) Close() {
+	w.cancel()
+	for _, watcher := range w.watchers {
+		watcher.Close()
+	}
+}
+
+// sourceDedup drops the Xid lines already seen from another log source
+// (e.g., the same kernel message in both dmesg and syslog),
+// while keeping the repeated lines from the same source (e.g., Xid floods).
+type sourceDedup struct {
+	window  time.Duration
+	entries map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	// number of occurrences per source
+	counts   map[int]int
+	lastSeen time.Time
+}
+
+func newSourceDedup(window time.Duration) *sourceDedup {
+	return &sourceDedup{
+		window:  window,
+		entries: make(map[string]*dedupEntry),
+	}
+}
+
+// isDuplicate returns true if another source has already reported
+// the same occurrence of the Xid line.
+func (d *sourceDedup) isDuplicate(src int, content string, now time.Time) bool {
+	// the sources may prefix the kernel message differently
+	// (e.g., "host kernel: [ 123.456789] NVRM: Xid ..." in syslog)
+	idx := strings.Index(content, "NVRM: Xid")
+	if idx == -1 {
+		return false
+	}
+	key := strings.TrimSpace(content[idx:])
+
+	for k, e := range d.entries {
+		if now.Sub(e.lastSeen) > d.window {
+			delete(d.entries, k)
+		}
+	}
+
+	e, ok := d.entries[key]
+	if !ok {
+		e = &dedupEntry{counts: make(map[int]int)}
+		d.entries[key] = e
+	}
+	e.counts[src]++
+	e.lastSeen = now
+
+	for other, cnt := range e.counts {
+		if other != src && cnt >= e.counts[src] {
+			return true
+		}
+	}
+	return false
+}
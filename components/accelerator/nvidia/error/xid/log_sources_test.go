@@ -0,0 +1,107 @@
+package xid
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestSourceDedup(t *testing.T) {
+	d := newSourceDedup(time.Minute)
+	now := time.Now()
+
+	dmesgLine := "NVRM: Xid (PCI:0000:05:00): 79, pid='<unknown>', name=<unknown>, GPU has fallen off the bus."
+	syslogLine := "host kernel: [ 123.456789] " + dmesgLine
+
+	// non-xid lines are never dropped
+	assert.False(t, d.isDuplicate(0, "hello", now))
+	assert.False(t, d.isDuplicate(1, "hello", now))
+
+	// same occurrence from two sources
+	assert.False(t, d.isDuplicate(0, dmesgLine, now))
+	assert.True(t, d.isDuplicate(1, syslogLine, now))
+
+	// repeated lines from the same source are kept
+	assert.False(t, d.isDuplicate(0, dmesgLine, now))
+	assert.False(t, d.isDuplicate(0, dmesgLine, now))
+	assert.True(t, d.isDuplicate(1, syslogLine, now))
+	assert.True(t, d.isDuplicate(1, syslogLine, now))
+
+	// the other source reporting more occurrences than the first one
+	assert.False(t, d.isDuplicate(1, syslogLine, now))
+
+	// expired
+	assert.False(t, d.isDuplicate(1, syslogLine, now.Add(2*time.Minute)))
+}
+
+func TestXIDComponent_LogSourceFiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	// the same kernel messages logged in both files, thus should be de-duplicated
+	dir := t.TempDir()
+	syslog := filepath.Join(dir, "syslog")
+	kernLog := filepath.Join(dir, "kern.log")
+	lines := "2025-01-21T04:41:44.000000+00:00 host kernel: NVRM: Xid (PCI:0000:05:00): 79, pid='<unknown>', name=<unknown>, GPU has fallen off the bus.\n" +
+		"2025-01-21T04:41:45.000000+00:00 host kernel: eth0: link up\n" +
+		"2025-01-21T04:41:46.000000+00:00 host kernel: NVRM: Xid (PCI:0000:06:00): 63, pid='<unknown>', name=<unknown>, Row Remapper: New row marked for remapping, reset gpu to activate.\n"
+	for _, f := range []string{syslog, kernLog} {
+		if err := os.WriteFile(f, []byte(lines), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	component := New(ctx, dbRW, dbRO, WithLogSources(syslog, kernLog))
+	assert.NotNil(t, component)
+	assert.Equal(t, []string{syslog, kernLog}, component.logSources)
+
+	watcher, err := newLogSourcesWatcher(component.logSources)
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	go component.start(watcher, time.Hour)
+	defer func() {
+		if err := component.Close(); err != nil {
+			t.Error("failed to close component")
+		}
+	}()
+
+	since := time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC)
+	var events []components.Event
+	for i := 0; i < 50; i++ {
+		events, err = component.store.Get(ctx, since)
+		assert.NoError(t, err)
+		if len(events) == 2 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Len(t, events, 2)
+
+	xids := map[string]string{}
+	for _, ev := range events {
+		xids[ev.ExtraInfo[EventKeyErroXidData]] = ev.ExtraInfo[EventKeyDeviceUUID]
+		assert.Equal(t, "1", ev.ExtraInfo[EventKeyOccurrenceCount])
+	}
+	assert.Equal(t, map[string]string{"79": "PCI:0000:05:00", "63": "PCI:0000:06:00"}, xids)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(map[string]any{"xid_log_sources": []string{"dmesg", "/var/log/syslog"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dmesg", "/var/log/syslog"}, cfg.XidLogSources)
+	assert.Len(t, cfg.Options(), 1)
+
+	cfg, err = ParseConfig(map[string]any{})
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Options())
+}
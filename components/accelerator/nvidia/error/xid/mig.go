@@ -0,0 +1,51 @@
+package xid
+
+import (
+	"strings"
+
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+)
+
+// lookupMIGMode returns the MIG mode of the GPU reporting the Xid from the last NVIDIA query,
+// where the device ID is either the GPU UUID or the PCI device ID in the dmesg (e.g., "PCI:0000:05:00").
+// Returns false for ok if unknown (e.g., not queried yet, or MIG not supported).
+// Overridable for testing.
+var lookupMIGMode = func(deviceID string) (enabled bool, ok bool) {
+	poller := nvidia_query.GetDefaultPoller()
+	if poller == nil {
+		return false, false
+	}
+	last, err := poller.LastSuccess()
+	if err != nil || last == nil {
+		return false, false
+	}
+	output, isOutput := last.Output.(*nvidia_query.Output)
+	if !isOutput || output.NVML == nil {
+		return false, false
+	}
+	for _, dev := range output.NVML.DeviceInfos {
+		if !matchDevice(dev, deviceID) {
+			continue
+		}
+		if !dev.MIGMode.Supported {
+			return false, false
+		}
+		return dev.MIGMode.Enabled, true
+	}
+	return false, false
+}
+
+// matchDevice returns true if the device ID in the Xid event refers to the device.
+// The PCI device ID in the dmesg omits the function (e.g., "0000:05:00" for "0000:05:00.0").
+func matchDevice(dev *nvidia_query_nvml.DeviceInfo, deviceID string) bool {
+	if deviceID == "" {
+		return false
+	}
+	if dev.UUID == deviceID {
+		return true
+	}
+	pciID := strings.ToLower(strings.TrimPrefix(deviceID, "PCI:"))
+	busID := strings.ToLower(dev.PCIBusID)
+	return busID == pciID || strings.HasPrefix(busID, pciID+".")
+}
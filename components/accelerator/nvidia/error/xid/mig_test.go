@@ -0,0 +1,109 @@
+package xid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestMatchDevice(t *testing.T) {
+	dev := &nvidia_query_nvml.DeviceInfo{UUID: "GPU-abc", PCIBusID: "0000:9B:00.0"}
+	for _, tt := range []struct {
+		deviceID string
+		expected bool
+	}{
+		{"GPU-abc", true},
+		{"PCI:0000:9b:00", true},
+		{"0000:9b:00", true},
+		{"0000:9b:00.0", true},
+		{"PCI:0000:9c:00", false},
+		{"0000:9b:0", false},
+		{"GPU-def", false},
+		{"", false},
+	} {
+		assert.Equal(t, tt.expected, matchDevice(dev, tt.deviceID), tt.deviceID)
+	}
+}
+
+func TestResolveXIDEventMIG(t *testing.T) {
+	orig := lookupMIGMode
+	t.Cleanup(func() { lookupMIGMode = orig })
+
+	detail, ok := nvidia_query_xid.GetDetail(95)
+	assert.True(t, ok)
+
+	for _, tt := range []struct {
+		name            string
+		xid             string
+		migEnabled      bool
+		migKnown        bool
+		expectedActions []common.RepairActionType
+	}{
+		{
+			name:            "MIG enabled",
+			xid:             "95",
+			migEnabled:      true,
+			migKnown:        true,
+			expectedActions: nvidia_query_xid.SuggestedActionsForXid95(true).RepairActions,
+		},
+		{
+			name:            "MIG disabled",
+			xid:             "95",
+			migKnown:        true,
+			expectedActions: nvidia_query_xid.SuggestedActionsForXid95(false).RepairActions,
+		},
+		{
+			name:            "MIG unknown",
+			xid:             "95",
+			expectedActions: detail.SuggestedActionsByGPUd.RepairActions,
+		},
+		{
+			name:            "other xid",
+			xid:             "79",
+			migEnabled:      true,
+			migKnown:        true,
+			expectedActions: []common.RepairActionType{common.RepairActionTypeRebootSystem, common.RepairActionTypeHardwareInspection},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookedUp string
+			lookupMIGMode = func(deviceID string) (bool, bool) {
+				lookedUp = deviceID
+				return tt.migEnabled, tt.migKnown
+			}
+
+			resolved := resolveXIDEvent(components.Event{
+				Name: EventNameErroXid,
+				ExtraInfo: map[string]string{
+					EventKeyErroXidData: tt.xid,
+					EventKeyDeviceUUID:  "PCI:0000:9b:00",
+				},
+			})
+			assert.Equal(t, tt.expectedActions, resolved.SuggestedActions.RepairActions)
+
+			var xidErr XidError
+			assert.NoError(t, json.Unmarshal([]byte(resolved.ExtraInfo[EventKeyErroXidData]), &xidErr))
+			assert.Equal(t, tt.expectedActions, xidErr.SuggestedActionsByGPUd.RepairActions)
+
+			if tt.xid == "95" {
+				assert.Equal(t, "PCI:0000:9b:00", lookedUp)
+			} else {
+				assert.Empty(t, lookedUp)
+			}
+		})
+	}
+
+	// MIG-enabled Xid 95 does not suggest the reboot
+	lookupMIGMode = func(string) (bool, bool) { return true, true }
+	state := EvolveHealthyState([]components.Event{
+		{Name: EventNameErroXid, Type: common.EventTypeCritical, ExtraInfo: map[string]string{EventKeyErroXidData: "95", EventKeyDeviceUUID: "GPU-abc"}},
+	})
+	assert.False(t, state.Healthy)
+	assert.False(t, state.SuggestedActions.RequiresReboot())
+}
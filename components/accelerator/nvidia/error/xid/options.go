@@ -0,0 +1,108 @@
+package xid
+
+import (
+	"time"
+
+	"github.com/leptonai/gpud/components/common"
+)
+
+type Op struct {
+	coalesceWindow      time.Duration
+	logSources          []string
+	recentXidsCapacity  int
+	recentXidsRetention time.Duration
+	overrides           XidOverrides
+	eventTypeHealth     common.EventTypeHealthMapping
+	nodeEvents          *nodeEventsConfig
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	if op.coalesceWindow <= 0 {
+		op.coalesceWindow = DefaultCoalesceWindow
+	}
+	if len(op.logSources) == 0 {
+		op.logSources = DefaultLogSources
+	}
+	if op.recentXidsCapacity <= 0 {
+		op.recentXidsCapacity = DefaultRecentXidsCapacity
+	}
+	if op.recentXidsRetention <= 0 {
+		op.recentXidsRetention = DefaultRecentXidsRetention
+	}
+	if len(op.eventTypeHealth) > 0 {
+		op.overrides.EventTypeHealth = op.eventTypeHealth
+	}
+}
+
+// WithCoalesceWindow sets the window within which the identical (xid, device uuid)
+// events are collapsed into a single event with the occurrence count.
+// Defaults to DefaultCoalesceWindow.
+func WithCoalesceWindow(window time.Duration) OpOption {
+	return func(op *Op) {
+		op.coalesceWindow = window
+	}
+}
+
+// WithLogSources sets the log sources to scan the Xid errors from,
+// where the lines are merged and de-duplicated across the sources.
+// See "newLogSourceWatcher" for the supported sources.
+// Defaults to DefaultLogSources.
+func WithLogSources(srcs ...string) OpOption {
+	return func(op *Op) {
+		op.logSources = append(op.logSources, srcs...)
+	}
+}
+
+// WithRecentXidsCapacity sets the maximum number of recent Xid events
+// kept in memory per GPU (see "RecentXids").
+// Defaults to DefaultRecentXidsCapacity.
+func WithRecentXidsCapacity(capacity int) OpOption {
+	return func(op *Op) {
+		op.recentXidsCapacity = capacity
+	}
+}
+
+// WithRecentXidsRetention sets the maximum age of the recent Xid events
+// kept in memory (see "RecentXids").
+// Defaults to DefaultRecentXidsRetention.
+func WithRecentXidsRetention(retention time.Duration) OpOption {
+	return func(op *Op) {
+		op.recentXidsRetention = retention
+	}
+}
+
+// WithXidOverrides sets the Xids to ignore or to force as critical
+// when evolving the health state, overriding the defaults in the details table.
+func WithXidOverrides(overrides XidOverrides) OpOption {
+	return func(op *Op) {
+		op.overrides = overrides
+	}
+}
+
+// WithEventTypeHealthMapping overrides whether the Xid events of the type
+// mark the component unhealthy (e.g., keep healthy on the critical Xids).
+// Defaults to the "Critical" and "Fatal" Xids marking the component unhealthy.
+func WithEventTypeHealthMapping(mapping common.EventTypeHealthMapping) OpOption {
+	return func(op *Op) {
+		op.eventTypeHealth = mapping
+	}
+}
+
+// WithKubernetesNodeEvents enables reading the Xid errors from the Kubernetes events
+// of the node (e.g., surfaced by the NVIDIA device plugin), merged with the Xid errors
+// from the log sources. Uses the in-cluster config if the kubeconfig is empty.
+// Disabled by default.
+func WithKubernetesNodeEvents(kubeconfig string, nodeName string) OpOption {
+	return func(op *Op) {
+		op.nodeEvents = &nodeEventsConfig{
+			kubeconfig: kubeconfig,
+			nodeName:   nodeName,
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package xid
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+)
+
+const (
+	// DefaultRecentXidsCapacity is the default maximum number of recent Xid events kept per GPU.
+	DefaultRecentXidsCapacity = 32
+	// DefaultRecentXidsRetention is the default maximum age of the recent Xid events.
+	DefaultRecentXidsRetention = DefaultRetentionPeriod
+)
+
+// XidEvent is an Xid error observed on a GPU.
+type XidEvent struct {
+	Time       time.Time `json:"time"`
+	Xid        int       `json:"xid"`
+	DeviceUUID string    `json:"device_uuid"`
+	// DataSource is where the Xid error was observed,
+	// empty for the kernel messages (see DataSourceDmesg).
+	DataSource string `json:"data_source,omitempty"`
+}
+
+// recentXids keeps the most recent Xid events per GPU in bounded ring buffers,
+// to correlate the Xid errors (e.g., Xid 45 following Xid 48) without querying the store.
+// The oldest event is evicted when the ring is full or the event is older than the retention.
+type recentXids struct {
+	mu        sync.Mutex
+	capacity  int
+	retention time.Duration
+	rings     map[string]*xidRing
+}
+
+func newRecentXids(capacity int, retention time.Duration) *recentXids {
+	return &recentXids{
+		capacity:  capacity,
+		retention: retention,
+		rings:     make(map[string]*xidRing),
+	}
+}
+
+// seed loads the events from the store, so that the recent events
+// survive the restarts. The coalesced events are seeded at their last seen time.
+func (r *recentXids) seed(events []components.Event, now time.Time) {
+	// the ring expects the events in the ascending time order
+	evs := make([]XidEvent, 0, len(events))
+	for _, ev := range events {
+		xe, ok := toXidEvent(ev)
+		if !ok {
+			continue
+		}
+		evs = append(evs, xe)
+	}
+	sort.Slice(evs, func(i, j int) bool {
+		return evs[i].Time.Before(evs[j].Time)
+	})
+	for _, ev := range evs {
+		r.add(ev, now)
+	}
+}
+
+func toXidEvent(ev components.Event) (XidEvent, bool) {
+	if ev.Name != EventNameErroXid || ev.ExtraInfo == nil {
+		return XidEvent{}, false
+	}
+	xid, err := strconv.Atoi(ev.ExtraInfo[EventKeyErroXidData])
+	if err != nil {
+		return XidEvent{}, false
+	}
+	t := ev.Time.Time
+	if lastSeen, err := time.Parse(time.RFC3339Nano, ev.ExtraInfo[EventKeyLastSeen]); err == nil {
+		t = lastSeen
+	}
+	return XidEvent{
+		Time:       t,
+		Xid:        xid,
+		DeviceUUID: ev.ExtraInfo[EventKeyDeviceUUID],
+		DataSource: ev.ExtraInfo[EventKeyDataSource],
+	}, true
+}
+
+func (r *recentXids) add(ev XidEvent, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.retention > 0 && now.Sub(ev.Time) > r.retention {
+		return
+	}
+
+	ring, ok := r.rings[ev.DeviceUUID]
+	if !ok {
+		ring = &xidRing{buf: make([]XidEvent, r.capacity)}
+		r.rings[ev.DeviceUUID] = ring
+	}
+	ring.push(ev)
+	if r.retention > 0 {
+		ring.evictBefore(now.Add(-r.retention))
+	}
+}
+
+// get returns the events of the GPU within the duration before now,
+// in the ascending time order. Returns all retained events if within is zero.
+func (r *recentXids) get(uuid string, within time.Duration, now time.Time) []XidEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ring, ok := r.rings[uuid]
+	if !ok {
+		return nil
+	}
+	if r.retention > 0 {
+		ring.evictBefore(now.Add(-r.retention))
+	}
+
+	var evs []XidEvent
+	for i := 0; i < ring.n; i++ {
+		ev := ring.buf[(ring.start+i)%len(ring.buf)]
+		if within > 0 && now.Sub(ev.Time) > within {
+			continue
+		}
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+// xidRing is a fixed-size circular buffer of the Xid events.
+type xidRing struct {
+	buf   []XidEvent
+	start int
+	n     int
+}
+
+func (r *xidRing) push(ev XidEvent) {
+	if len(r.buf) == 0 {
+		return
+	}
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = ev
+		r.n++
+		return
+	}
+	// full, overwrite the oldest
+	r.buf[r.start] = ev
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// evictBefore drops the oldest events older than the given time.
+func (r *xidRing) evictBefore(t time.Time) {
+	for r.n > 0 && r.buf[r.start].Time.Before(t) {
+		r.buf[r.start] = XidEvent{}
+		r.start = (r.start + 1) % len(r.buf)
+		r.n--
+	}
+}
@@ -0,0 +1,127 @@
+package xid
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/leptonai/gpud/components"
+)
+
+func TestRecentXidsWindowAndUUID(t *testing.T) {
+	now := time.Now()
+	r := newRecentXids(10, 24*time.Hour)
+
+	r.add(XidEvent{Time: now.Add(-3 * time.Hour), Xid: 48, DeviceUUID: "GPU-0"}, now)
+	r.add(XidEvent{Time: now.Add(-30 * time.Minute), Xid: 45, DeviceUUID: "GPU-0"}, now)
+	r.add(XidEvent{Time: now.Add(-10 * time.Minute), Xid: 79, DeviceUUID: "GPU-1"}, now)
+	r.add(XidEvent{Time: now.Add(-time.Minute), Xid: 13, DeviceUUID: "GPU-0"}, now)
+
+	evs := r.get("GPU-0", time.Hour, now)
+	if len(evs) != 2 {
+		t.Fatalf("expected 2 events within 1h, got %+v", evs)
+	}
+	if evs[0].Xid != 45 || evs[1].Xid != 13 {
+		t.Errorf("expected xids [45 13] in ascending time order, got %+v", evs)
+	}
+
+	if evs := r.get("GPU-0", 0, now); len(evs) != 3 {
+		t.Errorf("expected all 3 events of GPU-0, got %+v", evs)
+	}
+	if evs := r.get("GPU-1", time.Hour, now); len(evs) != 1 || evs[0].Xid != 79 {
+		t.Errorf("expected xid 79 for GPU-1, got %+v", evs)
+	}
+	if evs := r.get("GPU-2", time.Hour, now); len(evs) != 0 {
+		t.Errorf("expected no events for unknown GPU, got %+v", evs)
+	}
+}
+
+func TestRecentXidsCapacityEviction(t *testing.T) {
+	now := time.Now()
+	r := newRecentXids(3, 24*time.Hour)
+
+	for i := 0; i < 5; i++ {
+		r.add(XidEvent{Time: now.Add(time.Duration(i-5) * time.Minute), Xid: i, DeviceUUID: "GPU-0"}, now)
+	}
+	r.add(XidEvent{Time: now.Add(-time.Minute), Xid: 100, DeviceUUID: "GPU-1"}, now)
+
+	evs := r.get("GPU-0", 0, now)
+	if len(evs) != 3 {
+		t.Fatalf("expected capacity bounded 3 events, got %+v", evs)
+	}
+	for i, want := range []int{2, 3, 4} {
+		if evs[i].Xid != want {
+			t.Errorf("event %d: expected xid %d, got %d", i, want, evs[i].Xid)
+		}
+	}
+
+	// capacity is per GPU
+	if evs := r.get("GPU-1", 0, now); len(evs) != 1 {
+		t.Errorf("expected 1 event for GPU-1, got %+v", evs)
+	}
+}
+
+func TestRecentXidsRetentionEviction(t *testing.T) {
+	now := time.Now()
+	r := newRecentXids(10, time.Hour)
+
+	// older than the retention, never added
+	r.add(XidEvent{Time: now.Add(-2 * time.Hour), Xid: 1, DeviceUUID: "GPU-0"}, now)
+	r.add(XidEvent{Time: now.Add(-50 * time.Minute), Xid: 2, DeviceUUID: "GPU-0"}, now)
+	r.add(XidEvent{Time: now.Add(-5 * time.Minute), Xid: 3, DeviceUUID: "GPU-0"}, now)
+
+	if evs := r.get("GPU-0", 0, now); len(evs) != 2 {
+		t.Fatalf("expected 2 retained events, got %+v", evs)
+	}
+
+	// 20 minutes later, the second event is older than the retention
+	later := now.Add(20 * time.Minute)
+	evs := r.get("GPU-0", 0, later)
+	if len(evs) != 1 || evs[0].Xid != 3 {
+		t.Errorf("expected only xid 3 retained, got %+v", evs)
+	}
+}
+
+func TestRecentXidsSeed(t *testing.T) {
+	now := time.Now()
+	r := newRecentXids(10, 24*time.Hour)
+
+	lastSeen := now.Add(-10 * time.Minute)
+	r.seed([]components.Event{
+		{
+			// the store returns the events in the descending time order
+			Time: metav1.Time{Time: now.Add(-20 * time.Minute)},
+			Name: EventNameErroXid,
+			ExtraInfo: map[string]string{
+				EventKeyErroXidData:     "45",
+				EventKeyDeviceUUID:      "GPU-0",
+				EventKeyOccurrenceCount: "3",
+				EventKeyLastSeen:        lastSeen.UTC().Format(time.RFC3339Nano),
+			},
+		},
+		{
+			Time: metav1.Time{Time: now.Add(-time.Hour)},
+			Name: EventNameErroXid,
+			ExtraInfo: map[string]string{
+				EventKeyErroXidData: "48",
+				EventKeyDeviceUUID:  "GPU-0",
+			},
+		},
+		{
+			Time: metav1.Time{Time: now.Add(-time.Hour)},
+			Name: "reboot",
+		},
+	}, now)
+
+	evs := r.get("GPU-0", 0, now)
+	if len(evs) != 2 {
+		t.Fatalf("expected 2 seeded events, got %+v", evs)
+	}
+	if evs[0].Xid != 48 || evs[1].Xid != 45 {
+		t.Errorf("expected xids [48 45] in ascending time order, got %+v", evs)
+	}
+	if !evs[1].Time.Equal(lastSeen) {
+		t.Errorf("expected the coalesced event at its last seen time %v, got %v", lastSeen, evs[1].Time)
+	}
+}
@@ -0,0 +1,86 @@
+package xid
+
+import (
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/xid/dmesg"
+	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+)
+
+// Replayer feeds the log lines one at a time through the same
+// parser, coalescing, and health evaluation as the component.
+// The events are kept in memory, and nothing is written to the store.
+type Replayer struct {
+	overrides XidOverrides
+	co        *coalescer
+
+	// index of the latest stored event per (xid, device uuid),
+	// to update in place when coalesced
+	stored map[coalesceKey]int
+	events []components.Event
+}
+
+// NewReplayer creates a replayer.
+// Only the "WithCoalesceWindow", "WithXidOverrides", and
+// "WithEventTypeHealthMapping" options apply.
+func NewReplayer(opts ...OpOption) *Replayer {
+	op := &Op{}
+	op.applyOpts(opts)
+
+	return &Replayer{
+		overrides: op.overrides,
+		co:        newCoalescer(op.coalesceWindow),
+		stored:    make(map[coalesceKey]int),
+	}
+}
+
+// Add replays the log line, and returns the resulting event
+// with the occurrence count if coalesced into the previously stored event
+// of the same (xid, device uuid) within the coalesce window,
+// and the health state evaluated after the event.
+// Returns false if the line is not a Xid error,
+// or the same Xid error was already counted (e.g., the same line logged twice).
+func (r *Replayer) Add(line pkg_dmesg.LogLine) (event components.Event, coalesced bool, state components.State, ok bool) {
+	xidErr := dmesg.Match(line.Content)
+	if xidErr == nil {
+		return components.Event{}, false, components.State{}, false
+	}
+
+	event, prev, ok := r.co.add(newXidEvent(XidEvent{
+		Time:       line.Timestamp,
+		Xid:        xidErr.Xid,
+		DeviceUUID: xidErr.DeviceUUID,
+	}, r.overrides))
+	if !ok {
+		return components.Event{}, false, components.State{}, false
+	}
+
+	key := newCoalesceKey(event)
+	if idx, found := r.stored[key]; prev != nil && found {
+		r.events[idx] = event
+	} else {
+		r.stored[key] = len(r.events)
+		r.events = append(r.events, event)
+	}
+
+	state = EvolveHealthyStateWithOverrides(sortEventsDesc(r.events), time.Time{}, r.overrides)
+	return event, prev != nil, state, true
+}
+
+// sortEventsDesc returns a copy of the events sorted by time in descending order,
+// as expected by the health evaluation.
+// The extra info is also copied, as the evaluation resolves the events in place.
+func sortEventsDesc(events []components.Event) []components.Event {
+	sorted := make([]components.Event, len(events))
+	for i, ev := range events {
+		ev.ExtraInfo = maps.Clone(ev.ExtraInfo)
+		sorted[i] = ev
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Time.After(sorted[j].Time.Time)
+	})
+	return sorted
+}
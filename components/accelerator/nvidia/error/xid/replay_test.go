@@ -0,0 +1,81 @@
+package xid
+
+import (
+	"bufio"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	pkg_dmesg "github.com/leptonai/gpud/pkg/dmesg"
+)
+
+type replayed struct {
+	event     components.Event
+	coalesced bool
+	state     components.State
+}
+
+func replayFile(t *testing.T, file string, opts ...OpOption) []replayed {
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	r := NewReplayer(opts...)
+
+	var rs []replayed
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if event, coalesced, state, ok := r.Add(pkg_dmesg.ParseLogLine(scanner.Text())); ok {
+			rs = append(rs, replayed{event: event, coalesced: coalesced, state: state})
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return rs
+}
+
+func TestReplayer(t *testing.T) {
+	rs := replayFile(t, "testdata/dmesg.xid48-xid63.log")
+	require.Len(t, rs, 3)
+
+	// xid 48 (double bit ecc error) marks the gpu unhealthy
+	assert.Equal(t, "48", rs[0].event.ExtraInfo[EventKeyErroXidData])
+	assert.Equal(t, common.EventTypeFatal, rs[0].event.Type)
+	assert.False(t, rs[0].coalesced)
+	assert.False(t, rs[0].state.Healthy)
+	assert.Equal(t, components.StateUnhealthy, rs[0].state.Health)
+	assert.Equal(t, components.ReasonCodeECCDBE, rs[0].state.ReasonCode)
+	require.NotNil(t, rs[0].state.SuggestedActions)
+	assert.Equal(t, common.RepairActionTypeRebootSystem, rs[0].state.SuggestedActions.RepairActions[0])
+
+	// the same xid 48 within the coalesce window only bumps the occurrences
+	assert.True(t, rs[1].coalesced)
+	assert.Equal(t, 2, rs[1].event.Occurrences)
+	assert.Equal(t, components.ReasonCodeECCDBE, rs[1].state.ReasonCode)
+
+	// xid 63 following xid 48 escalates to the critical xid
+	assert.Equal(t, "63", rs[2].event.ExtraInfo[EventKeyErroXidData])
+	assert.False(t, rs[2].coalesced)
+	assert.False(t, rs[2].state.Healthy)
+	assert.Equal(t, components.StateUnhealthy, rs[2].state.Health)
+	assert.Equal(t, components.ReasonCodeXidCritical, rs[2].state.ReasonCode)
+	assert.Contains(t, rs[2].state.Error, "xid 63")
+}
+
+func TestReplayerWithOverrides(t *testing.T) {
+	rs := replayFile(t, "testdata/dmesg.xid48-xid63.log", WithXidOverrides(XidOverrides{Ignore: []int{63}}))
+	require.Len(t, rs, 3)
+
+	// ignored xid 63 does not change the state
+	assert.Equal(t, components.StateUnhealthy, rs[2].state.Health)
+	assert.Equal(t, components.ReasonCodeECCDBE, rs[2].state.ReasonCode)
+}
+
+func TestReplayerNoXid(t *testing.T) {
+	r := NewReplayer()
+	_, _, _, ok := r.Add(pkg_dmesg.ParseLogLine("kern  :info  : 2025-01-21T02:00:03,000000+00:00 eth0: link up"))
+	assert.False(t, ok)
+}
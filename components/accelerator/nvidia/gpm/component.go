@@ -47,7 +47,7 @@ func (c *component) Name() string { return Name }
 
 func (c *component) Start() error { return nil }
 
-func (c *component) States(ctx context.Context) ([]components.State, error) {
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
 	last, err := c.poller.LastSuccess()
 	if err == query.ErrNoData { // no data
 		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", Name)
@@ -70,13 +70,10 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 
 	o := &Output{}
 	if gpmEvent != nil && len(gpmEvent.Metrics) > 0 {
-		if lerr := c.poller.LastError(); lerr != nil {
-			log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "error", lerr)
-		}
-		lastSuccessPollElapsed := time.Now().UTC().Sub(gpmEvent.Time.Time)
-		if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
-			log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
-		}
+		freshness := query.CheckFreshness(c.poller, gpmEvent.Time.Time, time.Now().UTC())
+		defer func() {
+			freshness.Mark(states)
+		}()
 
 		o.NVMLGPMEvent = gpmEvent
 	}
@@ -0,0 +1,129 @@
+// Package infinibandlink monitors the InfiniBand port link states and rates from the sysfs.
+package infinibandlink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_infiniband_link_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/id"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/metrics"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+)
+
+func New(ctx context.Context, cfg nvidia_common.Config) components.Component {
+	cfg.Query.SetDefaultsIfNotSet()
+	setDefaultPoller(cfg)
+
+	cctx, ccancel := context.WithCancel(ctx)
+	getDefaultPoller().Start(cctx, cfg.Query, nvidia_infiniband_link_id.Name)
+
+	return &component{
+		rootCtx: ctx,
+		cancel:  ccancel,
+		poller:  getDefaultPoller(),
+	}
+}
+
+var _ components.Component = (*component)(nil)
+
+type component struct {
+	rootCtx  context.Context
+	cancel   context.CancelFunc
+	poller   query.Poller
+	gatherer prometheus.Gatherer
+}
+
+func (c *component) Name() string { return nvidia_infiniband_link_id.Name }
+
+func (c *component) Start() error { return nil }
+
+func (c *component) States(ctx context.Context) ([]components.State, error) {
+	last, err := c.poller.Last()
+	if err == query.ErrNoData { // no data
+		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_infiniband_link_id.Name)
+		return []components.State{
+			{
+				Name:    nvidia_infiniband_link_id.Name,
+				Healthy: true,
+				Reason:  query.ErrNoData.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if last.Error != nil {
+		return []components.State{
+			{
+				Name:    nvidia_infiniband_link_id.Name,
+				Healthy: false,
+				Error:   last.Error.Error(),
+				Reason:  "last query failed",
+			},
+		}, nil
+	}
+	if last.Output == nil {
+		return []components.State{
+			{
+				Name:    nvidia_infiniband_link_id.Name,
+				Healthy: true,
+				Reason:  "no output",
+			},
+		}, nil
+	}
+
+	output, ok := last.Output.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("invalid output type: %T", last.Output)
+	}
+	return output.States()
+}
+
+func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
+	return nil, nil
+}
+
+func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
+	log.Logger.Debugw("querying metrics", "since", since)
+
+	rates, err := metrics.ReadRateGbps(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates: %w", err)
+	}
+	actives, err := metrics.ReadActive(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read active states: %w", err)
+	}
+
+	ms := make([]components.Metric, 0, len(rates)+len(actives))
+	for _, m := range rates {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	for _, m := range actives {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	return ms, nil
+}
+
+func (c *component) Close() error {
+	log.Logger.Debugw("closing component")
+
+	// safe to call stop multiple times
+	_ = c.poller.Stop(nvidia_infiniband_link_id.Name)
+
+	return nil
+}
+
+var _ components.PromRegisterer = (*component)(nil)
+
+func (c *component) RegisterCollectors(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	c.gatherer = reg
+	return metrics.Register(reg, dbRW, dbRO, tableName)
+}
@@ -0,0 +1,122 @@
+package infinibandlink
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_infiniband_link_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/id"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/metrics"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/infiniband"
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	"github.com/leptonai/gpud/components/query"
+)
+
+type Output struct {
+	Ports []infiniband.SysfsPort `json:"ports"`
+}
+
+const (
+	StateKeyDevice    = "device"
+	StateKeyPort      = "port"
+	StateKeyState     = "state"
+	StateKeyPhysState = "phys_state"
+	StateKeyRate      = "rate"
+	StateKeyLinkLayer = "link_layer"
+)
+
+// States returns the health state per port,
+// degraded if the port is not active (e.g., "DOWN" or "Polling").
+// Returns a single healthy state if the host has no infiniband device,
+// since the component is not applicable.
+func (o *Output) States() ([]components.State, error) {
+	if len(o.Ports) == 0 {
+		return []components.State{
+			{
+				Name:    nvidia_infiniband_link_id.Name,
+				Healthy: true,
+				Health:  components.StateHealthy,
+				Reason:  "no infiniband port found (not applicable)",
+			},
+		}, nil
+	}
+
+	states := make([]components.State, 0, len(o.Ports))
+	for _, p := range o.Ports {
+		health := components.StateHealthy
+		reason := fmt.Sprintf("port %s is %s with link %s at %s", p.Name(), p.State, p.PhysState, p.Rate)
+		if !p.Active() {
+			health = components.StateDegraded
+			reason = fmt.Sprintf("port %s is not active (state %s, physical state %s)", p.Name(), p.State, p.PhysState)
+		}
+		states = append(states, components.State{
+			Name:    p.Name(),
+			Healthy: health == components.StateHealthy,
+			Health:  health,
+			Reason:  reason,
+			ExtraInfo: map[string]string{
+				StateKeyDevice:    p.Device,
+				StateKeyPort:      strconv.Itoa(p.Port),
+				StateKeyState:     p.State,
+				StateKeyPhysState: p.PhysState,
+				StateKeyRate:      p.Rate,
+				StateKeyLinkLayer: p.LinkLayer,
+			},
+		})
+	}
+	return states, nil
+}
+
+var (
+	defaultPollerOnce sync.Once
+	defaultPoller     query.Poller
+)
+
+func setDefaultPoller(cfg nvidia_common.Config) {
+	defaultPollerOnce.Do(func() {
+		defaultPoller = query.New(
+			nvidia_infiniband_link_id.Name,
+			cfg.Query,
+			CreateGet(cfg),
+			nil,
+		)
+	})
+}
+
+func getDefaultPoller() query.Poller {
+	return defaultPoller
+}
+
+func CreateGet(cfg nvidia_common.Config) query.GetFunc {
+	return func(ctx context.Context) (_ any, e error) {
+		defer func() {
+			if e != nil {
+				components_metrics.SetGetFailed(nvidia_infiniband_link_id.Name)
+			} else {
+				components_metrics.SetGetSuccess(nvidia_infiniband_link_id.Name)
+			}
+		}()
+
+		ports, err := infiniband.ReadSysfsPorts(cfg.InfinibandClassDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now().UTC()
+		metrics.SetLastUpdateUnixSeconds(float64(now.Unix()))
+		for _, p := range ports {
+			if err := metrics.SetRateGbps(ctx, p.Name(), p.RateGbps, now); err != nil {
+				return nil, err
+			}
+			if err := metrics.SetActive(ctx, p.Name(), p.Active(), now); err != nil {
+				return nil, err
+			}
+		}
+
+		return &Output{Ports: ports}, nil
+	}
+}
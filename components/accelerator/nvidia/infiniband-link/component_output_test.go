@@ -0,0 +1,96 @@
+package infinibandlink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_infiniband_link_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/id"
+)
+
+func writePort(t *testing.T, classDir string, dev string, state string, physState string, rate string) {
+	t.Helper()
+
+	dir := filepath.Join(classDir, dev, "ports", "1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"state":      state + "\n",
+		"phys_state": physState + "\n",
+		"rate":       rate + "\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOutputStates(t *testing.T) {
+	classDir := t.TempDir()
+	writePort(t, classDir, "mlx5_0", "4: ACTIVE", "5: LinkUp", "400 Gb/sec (4X NDR)")
+	writePort(t, classDir, "mlx5_1", "1: DOWN", "2: Polling", "10 Gb/sec (4X SDR)")
+
+	cfg := nvidia_common.Config{ToolOverwrites: nvidia_common.ToolOverwrites{InfinibandClassDirectory: classDir}}
+	v, err := CreateGet(cfg)(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, ok := v.(*Output)
+	if !ok {
+		t.Fatalf("unexpected output type %T", v)
+	}
+	if len(output.Ports) != 2 {
+		t.Fatalf("expected 2 ports, got %+v", output.Ports)
+	}
+
+	states, err := output.States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 states, got %+v", states)
+	}
+
+	if states[0].Name != "mlx5_0/1" || !states[0].Healthy || states[0].Health != components.StateHealthy {
+		t.Errorf("unexpected active port state %+v", states[0])
+	}
+	if states[0].ExtraInfo[StateKeyRate] != "400 Gb/sec (4X NDR)" {
+		t.Errorf("unexpected rate %q", states[0].ExtraInfo[StateKeyRate])
+	}
+
+	if states[1].Name != "mlx5_1/1" || states[1].Healthy || states[1].Health != components.StateDegraded {
+		t.Errorf("unexpected down port state %+v", states[1])
+	}
+	if states[1].ExtraInfo[StateKeyState] != "DOWN" || states[1].ExtraInfo[StateKeyPhysState] != "Polling" {
+		t.Errorf("unexpected down port extra info %+v", states[1].ExtraInfo)
+	}
+	if states[1].Reason != "port mlx5_1/1 is not active (state DOWN, physical state Polling)" {
+		t.Errorf("unexpected reason %q", states[1].Reason)
+	}
+}
+
+func TestOutputStatesNoDevice(t *testing.T) {
+	cfg := nvidia_common.Config{ToolOverwrites: nvidia_common.ToolOverwrites{InfinibandClassDirectory: filepath.Join(t.TempDir(), "infiniband")}}
+	v, err := CreateGet(cfg)(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := v.(*Output).States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("expected 1 state, got %+v", states)
+	}
+	if states[0].Name != nvidia_infiniband_link_id.Name || !states[0].Healthy {
+		t.Errorf("unexpected state %+v", states[0])
+	}
+	if states[0].Reason != "no infiniband port found (not applicable)" {
+		t.Errorf("unexpected reason %q", states[0].Reason)
+	}
+}
@@ -0,0 +1,4 @@
+// Package id defines the InfiniBand link status component ID.
+package id
+
+const Name = "accelerator-nvidia-infiniband-link"
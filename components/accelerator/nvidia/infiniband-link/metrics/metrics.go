@@ -0,0 +1,116 @@
+// Package metrics implements the InfiniBand link metrics collection and reporting.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const SubSystem = "accelerator_nvidia_infiniband_link"
+
+var (
+	lastUpdateUnixSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "last_update_unix_seconds",
+			Help:      "tracks the last update time in unix seconds",
+		},
+	)
+
+	rateGbps = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "rate_gbps",
+			Help:      "tracks the infiniband port link rate in Gb/sec",
+		},
+		[]string{"port"},
+	)
+	rateGbpsAverager = components_metrics.NewNoOpAverager()
+
+	active = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "active",
+			Help:      "tracks whether the infiniband port is active with its link up (1) or not (0)",
+		},
+		[]string{"port"},
+	)
+	activeAverager = components_metrics.NewNoOpAverager()
+)
+
+func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
+	rateGbpsAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_rate_gbps")
+	activeAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_active")
+}
+
+func ReadRateGbps(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return rateGbpsAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadActive(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return activeAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func SetLastUpdateUnixSeconds(unixSeconds float64) {
+	lastUpdateUnixSeconds.Set(unixSeconds)
+}
+
+// SetRateGbps sets the link rate of the port (e.g., "mlx5_0/1").
+func SetRateGbps(ctx context.Context, port string, gbps float64, currentTime time.Time) error {
+	rateGbps.WithLabelValues(port).Set(gbps)
+
+	if err := rateGbpsAverager.Observe(
+		ctx,
+		gbps,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(port),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetActive sets whether the port (e.g., "mlx5_0/1") is active with its link up.
+func SetActive(ctx context.Context, port string, isActive bool, currentTime time.Time) error {
+	v := float64(0)
+	if isActive {
+		v = 1
+	}
+	active.WithLabelValues(port).Set(v)
+
+	if err := activeAverager.Observe(
+		ctx,
+		v,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(port),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	InitAveragers(dbRW, dbRO, tableName)
+
+	if err := reg.Register(lastUpdateUnixSeconds); err != nil {
+		return err
+	}
+	if err := reg.Register(rateGbps); err != nil {
+		return err
+	}
+	if err := reg.Register(active); err != nil {
+		return err
+	}
+	return nil
+}
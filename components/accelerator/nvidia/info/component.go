@@ -3,33 +3,61 @@ package info
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/leptonai/gpud/components"
-	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
 	"github.com/leptonai/gpud/components/query"
 	"github.com/leptonai/gpud/log"
 )
 
 const Name = "accelerator-nvidia-info"
 
-func New(ctx context.Context, cfg nvidia_common.Config) (components.Component, error) {
+func New(ctx context.Context, cfg Config) (components.Component, error) {
 	if nvidia_query.GetDefaultPoller() == nil {
 		return nil, nvidia_query.ErrDefaultPollerNotSet
 	}
 
 	cfg.Query.SetDefaultsIfNotSet()
 
+	c := &component{
+		rootCtx: ctx,
+		poller:  nvidia_query.GetDefaultPoller(),
+
+		expectedGPUCount: cfg.ExpectedGPUCount,
+	}
+
+	// the count learned before the restart (or reboot) is used,
+	// since the GPU may be already missing when gpud starts
+	if cfg.Query.State != nil && cfg.Query.State.DBRW != nil && cfg.Query.State.DBRO != nil {
+		if err := CreateTableGPUCount(ctx, cfg.Query.State.DBRW); err != nil {
+			return nil, err
+		}
+		c.dbRW = cfg.Query.State.DBRW
+
+		if c.expectedGPUCount == 0 {
+			learned, err := ReadLearnedGPUCount(ctx, cfg.Query.State.DBRO)
+			if err != nil {
+				return nil, err
+			}
+			if learned > 0 {
+				log.Logger.Infow("loaded the learned gpu count", "count", learned)
+				c.expectedGPUCount = learned
+				c.learnedGPUCount = true
+			}
+		}
+	}
+
 	cctx, ccancel := context.WithCancel(ctx)
 	nvidia_query.GetDefaultPoller().Start(cctx, cfg.Query, Name)
+	c.cancel = ccancel
 
-	return &component{
-		rootCtx: ctx,
-		cancel:  ccancel,
-		poller:  nvidia_query.GetDefaultPoller(),
-	}, nil
+	return c, nil
 }
 
 var _ components.Component = (*component)(nil)
@@ -38,13 +66,39 @@ type component struct {
 	rootCtx context.Context
 	cancel  context.CancelFunc
 	poller  query.Poller
+
+	// to persist the learned number of the GPUs (nil if no database)
+	dbRW *sql.DB
+
+	// expected number of the GPUs, learned (the max ever enumerated) if not configured
+	expectedGPUCountMu sync.Mutex
+	expectedGPUCount   int
+	learnedGPUCount    bool
 }
 
 func (c *component) Name() string { return Name }
 
 func (c *component) Start() error { return nil }
 
-func (c *component) States(ctx context.Context) ([]components.State, error) {
+// StateReasonDriverLibraryMismatch is the reason of the unhealthy state
+// when the NVIDIA driver and the NVML library versions do not match.
+const StateReasonDriverLibraryMismatch = "driver/library mismatch, reboot required"
+
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
+	// the mismatch does not resolve until reboot
+	// so report the single state rather than the stale/missing query results
+	if lerr := c.poller.LastError(); errors.Is(lerr, nvidia_query_nvml.ErrDriverLibraryMismatch) {
+		return []components.State{
+			{
+				Name:    Name,
+				Healthy: false,
+				Health:  components.StateUnhealthy,
+				Error:   lerr.Error(),
+				Reason:  StateReasonDriverLibraryMismatch,
+			},
+		}, nil
+	}
+
 	last, err := c.poller.LastSuccess()
 	if err == query.ErrNoData { // no data
 		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", Name)
@@ -64,13 +118,10 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid output type: %T", last.Output)
 	}
-	if lerr := c.poller.LastError(); lerr != nil {
-		log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "error", lerr)
-	}
-	lastSuccessPollElapsed := time.Now().UTC().Sub(allOutput.Time)
-	if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
-		log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
-	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
 
 	if allOutput.SMIExists && len(allOutput.SMIQueryErrors) > 0 {
 		cs := make([]components.State, 0)
@@ -88,9 +139,52 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 		return cs, nil
 	}
 	output := ToOutput(allOutput)
+	output.GPUCount = c.checkGPUCount(allOutput)
 	return output.States()
 }
 
+// checkGPUCount returns the number of the GPUs enumerated by NVML against the expected.
+// Returns nil if NVML data is not available.
+func (c *component) checkGPUCount(o *nvidia_query.Output) *GPUCount {
+	if o.NVML == nil {
+		return nil
+	}
+	cnt := o.GPUCountFromNVML()
+
+	c.expectedGPUCountMu.Lock()
+	defer c.expectedGPUCountMu.Unlock()
+
+	// learns the max count ever enumerated (persisted across the restarts),
+	// rather than the first poll, which may be already missing a GPU
+	if (c.expectedGPUCount == 0 || c.learnedGPUCount) && cnt > c.expectedGPUCount {
+		log.Logger.Infow("learned the expected gpu count", "count", cnt)
+		c.expectedGPUCount = cnt
+		c.learnedGPUCount = true
+
+		if c.dbRW != nil {
+			if err := UpdateLearnedGPUCount(c.rootCtx, c.dbRW, cnt); err != nil {
+				log.Logger.Warnw("failed to persist the learned gpu count", "count", cnt, "error", err)
+			}
+		}
+	}
+	return &GPUCount{
+		NVML:     cnt,
+		Expected: c.expectedGPUCount,
+		Learned:  c.learnedGPUCount,
+	}
+}
+
+var _ components.Prober = (*component)(nil)
+
+// Probe queries the number of the GPUs from the NVML library.
+func (c *component) Probe(ctx context.Context) components.ProbeResult {
+	count, err := nvidia_query_nvml.GetDeviceCount()
+	if err != nil {
+		return components.ProbeResult{Error: err.Error()}
+	}
+	return components.ProbeResult{Passed: true, Message: fmt.Sprintf("found %d GPU(s) via NVML", count)}
+}
+
 func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
 	return nil, nil
 }
@@ -130,5 +224,7 @@ func (c *component) Output() (any, error) {
 	if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
 		log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
 	}
-	return ToOutput(allOutput), nil
+	output := ToOutput(allOutput)
+	output.GPUCount = c.checkGPUCount(allOutput)
+	return output, nil
 }
@@ -1,12 +1,15 @@
 package info
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
 	"github.com/dustin/go-humanize"
 	"github.com/leptonai/gpud/components"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/common"
 )
 
 // ToOutput converts nvidia_query.Output to Output.
@@ -31,6 +34,7 @@ func ToOutput(i *nvidia_query.Output) *Output {
 	}
 
 	o := &Output{
+		DataSource: i.DataSource,
 		GPU: GPU{
 			DeviceCount: i.GPUDeviceCount,
 			Attached:    i.GPUCount(),
@@ -55,6 +59,12 @@ func ToOutput(i *nvidia_query.Output) *Output {
 		}
 	}
 
+	if i.NVML != nil {
+		for _, dev := range i.NVML.DeviceInfos {
+			o.MIGModes = append(o.MIGModes, dev.MIGMode)
+		}
+	}
+
 	return o
 }
 
@@ -64,6 +74,15 @@ type Output struct {
 	GPU     GPU     `json:"gpu"`
 	Memory  Memory  `json:"memory"`
 	Product Product `json:"products"`
+
+	// DataSource is the source of the device data (e.g., "nvidia-smi-xml" if NVML is unavailable).
+	DataSource string `json:"data_source,omitempty"`
+
+	// MIG modes of the GPUs, based on the NVML.
+	MIGModes []nvidia_query_nvml.MIGMode `json:"mig_modes,omitempty"`
+
+	// GPUCount is the number of the GPUs enumerated by NVML against the expected.
+	GPUCount *GPUCount `json:"gpu_count,omitempty"`
 }
 
 type Driver struct {
@@ -83,6 +102,21 @@ type GPU struct {
 	Attached int `json:"attached"`
 }
 
+type GPUCount struct {
+	// NVML is the number of the GPUs enumerated by NVML.
+	NVML int `json:"nvml"`
+	// Expected is the expected number of the GPUs.
+	Expected int `json:"expected"`
+	// Learned is true if the expected count is learned (the max count ever enumerated),
+	// rather than configured.
+	Learned bool `json:"learned"`
+}
+
+// Mismatch returns true if the enumerated count differs from the expected.
+func (g GPUCount) Mismatch() bool {
+	return g.Expected > 0 && g.NVML != g.Expected
+}
+
 type Memory struct {
 	TotalBytes     uint64 `json:"total_bytes"`
 	TotalHumanized string `json:"total_humanized"`
@@ -113,6 +147,19 @@ const (
 	StateKeyProductName         = "name"
 	StateKeyProductBrand        = "brand"
 	StateKeyProductArchitecture = "architecture"
+
+	StateKeyDataSource      = "data_source"
+	StateKeyDataSourceValue = "source"
+
+	StateKeyGPUCount         = "gpu_count"
+	StateKeyGPUCountNVML     = "nvml"
+	StateKeyGPUCountExpected = "expected"
+	StateKeyGPUCountLearned  = "learned"
+
+	StateKeyMIG               = "mig"
+	StateKeyMIGData           = "data"
+	StateKeyMIGEncoding       = "encoding"
+	StateValueMIGEncodingJSON = "json"
 )
 
 func ParseStateKeyDriver(m map[string]string) (Driver, error) {
@@ -161,6 +208,38 @@ func ParseStateKeyProduct(m map[string]string) (Product, error) {
 	return p, nil
 }
 
+func ParseStateKeyDataSource(m map[string]string) (string, error) {
+	return m[StateKeyDataSourceValue], nil
+}
+
+func ParseStateKeyGPUCount(m map[string]string) (*GPUCount, error) {
+	g := &GPUCount{}
+
+	var err error
+	g.NVML, err = strconv.Atoi(m[StateKeyGPUCountNVML])
+	if err != nil {
+		return nil, err
+	}
+	g.Expected, err = strconv.Atoi(m[StateKeyGPUCountExpected])
+	if err != nil {
+		return nil, err
+	}
+	g.Learned, err = strconv.ParseBool(m[StateKeyGPUCountLearned])
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func ParseStateKeyMIG(m map[string]string) ([]nvidia_query_nvml.MIGMode, error) {
+	var modes []nvidia_query_nvml.MIGMode
+	if err := json.Unmarshal([]byte(m[StateKeyMIGData]), &modes); err != nil {
+		return nil, err
+	}
+	return modes, nil
+}
+
 func ParseStatesToOutput(states ...components.State) (*Output, error) {
 	o := &Output{}
 	for _, state := range states {
@@ -200,6 +279,27 @@ func ParseStatesToOutput(states ...components.State) (*Output, error) {
 			}
 			o.Product = product
 
+		case StateKeyDataSource:
+			src, err := ParseStateKeyDataSource(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			o.DataSource = src
+
+		case StateKeyGPUCount:
+			cnt, err := ParseStateKeyGPUCount(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			o.GPUCount = cnt
+
+		case StateKeyMIG:
+			modes, err := ParseStateKeyMIG(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			o.MIGModes = modes
+
 		default:
 			return nil, fmt.Errorf("unknown state name: %s", state.Name)
 		}
@@ -254,5 +354,99 @@ func (o *Output) States() ([]components.State, error) {
 			},
 		},
 	}
+
+	if o.DataSource != "" {
+		cs = append(cs, o.dataSourceState())
+	}
+
+	if o.GPUCount != nil {
+		cs = append(cs, o.GPUCount.state())
+	}
+
+	b, err := json.Marshal(o.MIGModes)
+	if err != nil {
+		return nil, err
+	}
+	cs = append(cs, components.State{
+		Name:    StateKeyMIG,
+		Healthy: true,
+		Reason:  o.migReason(),
+		ExtraInfo: map[string]string{
+			StateKeyMIGData:     string(b),
+			StateKeyMIGEncoding: StateValueMIGEncodingJSON,
+		},
+	})
 	return cs, nil
 }
+
+// dataSourceState returns the degraded state if the device data is from the fallback,
+// as NVML is unavailable (e.g., failed to initialize).
+func (o *Output) dataSourceState() components.State {
+	st := components.State{
+		Name:    StateKeyDataSource,
+		Healthy: true,
+		Health:  components.StateHealthy,
+		Reason:  fmt.Sprintf("gpu info collected from %s", o.DataSource),
+		ExtraInfo: map[string]string{
+			StateKeyDataSourceValue: o.DataSource,
+		},
+	}
+	if o.DataSource == nvidia_query.DataSourceSMIXML {
+		st.Health = components.StateDegraded
+		st.Reason = fmt.Sprintf("gpu info collected from %s (NVML unavailable, fallback with basic device info, temperature, and memory only)", o.DataSource)
+	}
+	return st
+}
+
+// state returns the critical state if the enumerated count differs from the expected
+// (e.g., a GPU fell off the bus, as in Xid 79).
+func (g GPUCount) state() components.State {
+	st := components.State{
+		Name:    StateKeyGPUCount,
+		Healthy: true,
+		Health:  components.StateHealthy,
+		Reason:  fmt.Sprintf("%d gpu(s) found via NVML (expected %d)", g.NVML, g.Expected),
+		ExtraInfo: map[string]string{
+			StateKeyGPUCountNVML:     strconv.Itoa(g.NVML),
+			StateKeyGPUCountExpected: strconv.Itoa(g.Expected),
+			StateKeyGPUCountLearned:  strconv.FormatBool(g.Learned),
+		},
+	}
+	if g.Mismatch() {
+		st.Healthy = false
+		st.Health = components.StateUnhealthy
+		st.Reason = fmt.Sprintf("%d gpu(s) found via NVML but expected %d (check the number of attached GPUs)", g.NVML, g.Expected)
+		st.SuggestedActions = &common.SuggestedActions{
+			Descriptions:  []string{"GPU may have fallen off the bus, inspect the GPUs and the PCIe connections"},
+			RepairActions: []common.RepairActionType{common.RepairActionTypeHardwareInspection},
+		}
+	}
+	return st
+}
+
+// migReason summarizes the MIG modes (e.g., "MIG enabled on 2 of 8 GPU(s) with 4 instance(s)").
+func (o *Output) migReason() string {
+	supported, enabled, instances, pending := 0, 0, 0, 0
+	for _, m := range o.MIGModes {
+		if !m.Supported {
+			continue
+		}
+		supported++
+		if m.Enabled {
+			enabled++
+			instances += len(m.Instances)
+		}
+		if m.Pending {
+			pending++
+		}
+	}
+	if supported == 0 {
+		return "no GPU supports MIG"
+	}
+
+	reason := fmt.Sprintf("MIG enabled on %d of %d GPU(s) with %d instance(s)", enabled, supported, instances)
+	if pending > 0 {
+		reason += fmt.Sprintf(", MIG mode change pending on %d GPU(s) (requires GPU reset)", pending)
+	}
+	return reason
+}
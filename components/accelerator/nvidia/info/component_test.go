@@ -2,12 +2,22 @@ package info
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"testing"
+	"time"
 
-	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	"github.com/leptonai/gpud/components"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/components/query"
+	query_config "github.com/leptonai/gpud/components/query/config"
+	"github.com/leptonai/gpud/pkg/sqlite"
 
+	go_nvml "github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestComponentWithNoPoller(t *testing.T) {
@@ -15,7 +25,7 @@ func TestComponentWithNoPoller(t *testing.T) {
 	defer cancel()
 
 	defaultPoller := nvidia_query.GetDefaultPoller()
-	_, err := New(ctx, nvidia_common.Config{})
+	_, err := New(ctx, Config{})
 
 	if defaultPoller != nil {
 		// expects no error
@@ -25,3 +35,241 @@ func TestComponentWithNoPoller(t *testing.T) {
 		assert.Equal(t, err, nvidia_query.ErrDefaultPollerNotSet)
 	}
 }
+
+type mismatchPoller struct {
+	query.Poller
+	err error
+}
+
+func (p *mismatchPoller) LastSuccess() (*query.Item, error) { return nil, query.ErrNoData }
+func (p *mismatchPoller) LastError() error                  { return p.err }
+
+func TestComponentStatesDriverLibraryMismatch(t *testing.T) {
+	mismatchErr := fmt.Errorf("failed to start nvml instance: %w",
+		fmt.Errorf("failed to initialize NVML: %v (%w)", go_nvml.ErrorString(go_nvml.ERROR_LIB_RM_VERSION_MISMATCH), nvidia_query_nvml.ErrDriverLibraryMismatch))
+	c := &component{poller: &mismatchPoller{err: mismatchErr}}
+
+	for i := 0; i < 3; i++ {
+		states, err := c.States(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, states, 1)
+		assert.False(t, states[0].Healthy)
+		assert.Equal(t, components.StateUnhealthy, states[0].Health)
+		assert.Equal(t, StateReasonDriverLibraryMismatch, states[0].Reason)
+		assert.Equal(t, mismatchErr.Error(), states[0].Error)
+	}
+}
+
+func TestOutputMIGState(t *testing.T) {
+	o := ToOutput(&nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{UUID: "gpu-0", MIGMode: nvidia_query_nvml.MIGMode{UUID: "gpu-0", Supported: true}},
+				{UUID: "gpu-1", MIGMode: nvidia_query_nvml.MIGMode{
+					UUID:    "gpu-1",
+					Enabled: true,
+					Instances: []nvidia_query_nvml.MIGInstance{
+						{UUID: "MIG-0", Profile: "4g.40gb"},
+						{UUID: "MIG-1", Profile: "3g.40gb"},
+					},
+					Supported: true,
+				}},
+			},
+		},
+	})
+
+	states, err := o.States()
+	assert.NoError(t, err)
+
+	var migState *components.State
+	for i := range states {
+		if states[i].Name == StateKeyMIG {
+			migState = &states[i]
+		}
+	}
+	if assert.NotNil(t, migState) {
+		assert.True(t, migState.Healthy)
+		assert.Equal(t, "MIG enabled on 1 of 2 GPU(s) with 2 instance(s)", migState.Reason)
+
+		parsed, err := ParseStatesToOutput(*migState)
+		assert.NoError(t, err)
+		assert.Equal(t, o.MIGModes, parsed.MIGModes)
+	}
+
+	o.MIGModes[0].Pending = true
+	assert.Equal(t, "MIG enabled on 1 of 2 GPU(s) with 2 instance(s), MIG mode change pending on 1 GPU(s) (requires GPU reset)", o.migReason())
+
+	assert.Equal(t, "no GPU supports MIG", (&Output{}).migReason())
+}
+
+func TestOutputSMIXMLFallback(t *testing.T) {
+	b, err := os.ReadFile("../query/testdata/nvidia-smi-xml.550.90.07.out.0.valid")
+	assert.NoError(t, err)
+	smi, err := nvidia_query.ParseSMIXMLOutput(b)
+	assert.NoError(t, err)
+
+	o := ToOutput(&nvidia_query.Output{
+		DataSource:     nvidia_query.DataSourceSMIXML,
+		GPUDeviceCount: 2,
+		SMI:            smi,
+	})
+	assert.Equal(t, "550.90.07", o.Driver.Version)
+	assert.Equal(t, "12.4", o.CUDA.Version)
+	assert.Equal(t, 2, o.GPU.Attached)
+	assert.Equal(t, uint64(81559*1024*1024), o.Memory.TotalBytes)
+	assert.Equal(t, "NVIDIA H100 80GB HBM3", o.Product.Name)
+
+	states, err := o.States()
+	assert.NoError(t, err)
+
+	var srcState *components.State
+	for i := range states {
+		if states[i].Name == StateKeyDataSource {
+			srcState = &states[i]
+		}
+	}
+	if assert.NotNil(t, srcState) {
+		assert.True(t, srcState.Healthy)
+		assert.Equal(t, components.StateDegraded, srcState.Health)
+		assert.Equal(t, nvidia_query.DataSourceSMIXML, srcState.ExtraInfo[StateKeyDataSourceValue])
+
+		parsed, err := ParseStatesToOutput(*srcState)
+		assert.NoError(t, err)
+		assert.Equal(t, nvidia_query.DataSourceSMIXML, parsed.DataSource)
+	}
+
+	// healthy if the data is from NVML
+	o.DataSource = nvidia_query.DataSourceNVML
+	assert.Equal(t, components.StateHealthy, o.dataSourceState().Health)
+}
+
+type gpuCountPoller struct {
+	query.Poller
+	item query.Item
+}
+
+func (p *gpuCountPoller) ID() string                        { return "test" }
+func (p *gpuCountPoller) Config() query_config.Config       { return query_config.Config{} }
+func (p *gpuCountPoller) Last() (*query.Item, error)        { return &p.item, nil }
+func (p *gpuCountPoller) LastSuccess() (*query.Item, error) { return &p.item, nil }
+func (p *gpuCountPoller) LastError() error                  { return nil }
+
+func (p *gpuCountPoller) setGPUCount(cnt int) {
+	o := &nvidia_query.Output{
+		Time: time.Now().UTC(),
+		NVML: &nvidia_query_nvml.Output{},
+	}
+	for i := 0; i < cnt; i++ {
+		o.NVML.DeviceInfos = append(o.NVML.DeviceInfos, &nvidia_query_nvml.DeviceInfo{UUID: fmt.Sprintf("gpu-%d", i)})
+	}
+	p.item = query.Item{Time: metav1.NewTime(o.Time), Output: o}
+}
+
+func gpuCountState(t *testing.T, c *component) components.State {
+	states, err := c.States(context.Background())
+	assert.NoError(t, err)
+	for _, st := range states {
+		if st.Name == StateKeyGPUCount {
+			return st
+		}
+	}
+	t.Fatalf("no %q state in %+v", StateKeyGPUCount, states)
+	return components.State{}
+}
+
+func TestComponentStatesGPUCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		expected    int
+		count       int
+		wantHealthy bool
+	}{
+		{name: "matching", expected: 8, count: 8, wantHealthy: true},
+		{name: "fewer than expected", expected: 8, count: 7, wantHealthy: false},
+		{name: "none found", expected: 8, count: 0, wantHealthy: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &gpuCountPoller{}
+			pl.setGPUCount(tt.count)
+			c := &component{poller: pl, expectedGPUCount: tt.expected}
+
+			st := gpuCountState(t, c)
+			assert.Equal(t, tt.wantHealthy, st.Healthy)
+			if tt.wantHealthy {
+				assert.Equal(t, components.StateHealthy, st.Health)
+				assert.Nil(t, st.SuggestedActions)
+			} else {
+				assert.Equal(t, components.StateUnhealthy, st.Health)
+				if assert.NotNil(t, st.SuggestedActions) {
+					assert.Equal(t, []common.RepairActionType{common.RepairActionTypeHardwareInspection}, st.SuggestedActions.RepairActions)
+				}
+			}
+
+			parsed, err := ParseStatesToOutput(st)
+			assert.NoError(t, err)
+			assert.Equal(t, &GPUCount{NVML: tt.count, Expected: tt.expected}, parsed.GPUCount)
+		})
+	}
+}
+
+func TestComponentStatesGPUCountLearned(t *testing.T) {
+	pl := &gpuCountPoller{}
+	c := &component{poller: pl}
+
+	// learned on the successful poll
+	pl.setGPUCount(8)
+	st := gpuCountState(t, c)
+	assert.True(t, st.Healthy)
+	assert.Equal(t, "8", st.ExtraInfo[StateKeyGPUCountExpected])
+	assert.Equal(t, "true", st.ExtraInfo[StateKeyGPUCountLearned])
+
+	// a GPU fell off the bus
+	pl.setGPUCount(7)
+	st = gpuCountState(t, c)
+	assert.False(t, st.Healthy)
+	assert.Equal(t, components.StateUnhealthy, st.Health)
+	assert.Equal(t, "7 gpu(s) found via NVML but expected 8 (check the number of attached GPUs)", st.Reason)
+
+	// recovered (e.g., after reboot)
+	pl.setGPUCount(8)
+	assert.True(t, gpuCountState(t, c).Healthy)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, (&Config{ExpectedGPUCount: 8}).Validate())
+	assert.Error(t, (&Config{ExpectedGPUCount: -1}).Validate())
+}
+
+func TestComponentStatesGPUCountLearnedPersisted(t *testing.T) {
+	ctx := context.Background()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+	assert.NoError(t, CreateTableGPUCount(ctx, dbRW))
+
+	pl := &gpuCountPoller{}
+	c := &component{rootCtx: ctx, poller: pl, dbRW: dbRW}
+
+	// learns the max count, not the first poll
+	pl.setGPUCount(7)
+	assert.True(t, gpuCountState(t, c).Healthy)
+	pl.setGPUCount(8)
+	assert.True(t, gpuCountState(t, c).Healthy)
+
+	learned, err := ReadLearnedGPUCount(ctx, dbRO)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, learned)
+
+	// restarted (e.g., after reboot) with a GPU already missing
+	pl.setGPUCount(7)
+	restarted := &component{rootCtx: ctx, poller: pl, dbRW: dbRW, expectedGPUCount: learned, learnedGPUCount: true}
+	st := gpuCountState(t, restarted)
+	assert.False(t, st.Healthy)
+	assert.Equal(t, "8", st.ExtraInfo[StateKeyGPUCountExpected])
+
+	// never decreases
+	assert.NoError(t, UpdateLearnedGPUCount(ctx, dbRW, 4))
+	learned, err = ReadLearnedGPUCount(ctx, dbRO)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, learned)
+}
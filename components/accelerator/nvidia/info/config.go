@@ -0,0 +1,46 @@
+package info
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	query_config "github.com/leptonai/gpud/components/query/config"
+)
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// ExpectedGPUCount is the number of the GPUs expected to be enumerated by NVML.
+	// The state is critical if the count differs (e.g., a GPU fell off the bus).
+	// If not set, the max count ever enumerated is learned and persisted
+	// across the restarts.
+	ExpectedGPUCount int `json:"expected_gpu_count"`
+
+	nvidia_common.ToolOverwrites
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.ExpectedGPUCount < 0 {
+		return errors.New("expected gpu count must be non-negative")
+	}
+	return nil
+}
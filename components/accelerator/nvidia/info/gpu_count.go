@@ -0,0 +1,61 @@
+package info
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+const (
+	// TableNameGPUCount is the table that persists the learned number of the GPUs
+	// across the restarts and reboots, so that the GPU missing at startup
+	// (e.g., fell off the bus with Xid 79 before the reboot) is still detected.
+	TableNameGPUCount = "components_accelerator_nvidia_info_gpu_count"
+
+	ColumnGPUCountID    = "id"
+	ColumnGPUCountCount = "count"
+)
+
+func CreateTableGPUCount(ctx context.Context, dbRW *sql.DB) error {
+	_, err := dbRW.ExecContext(ctx, fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+	%s INTEGER PRIMARY KEY CHECK (%s = 0),
+	%s INTEGER NOT NULL
+);`, TableNameGPUCount, ColumnGPUCountID, ColumnGPUCountID, ColumnGPUCountCount))
+	return err
+}
+
+// ReadLearnedGPUCount reads the learned number of the GPUs.
+// Returns zero if not learned yet.
+func ReadLearnedGPUCount(ctx context.Context, dbRO *sql.DB) (int, error) {
+	query := fmt.Sprintf(`
+SELECT %s FROM %s WHERE %s = 0;
+`, ColumnGPUCountCount, TableNameGPUCount, ColumnGPUCountID)
+
+	var cnt int
+	start := time.Now()
+	err := dbRO.QueryRowContext(ctx, query).Scan(&cnt)
+	sqlite.RecordSelect(time.Since(start).Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return cnt, err
+}
+
+// UpdateLearnedGPUCount persists the learned number of the GPUs,
+// only if larger than the one already persisted.
+func UpdateLearnedGPUCount(ctx context.Context, dbRW *sql.DB, cnt int) error {
+	query := fmt.Sprintf(`
+INSERT INTO %s (%s, %s) VALUES (0, ?)
+ON CONFLICT(%s) DO UPDATE SET %s = MAX(%s, excluded.%s);
+`, TableNameGPUCount, ColumnGPUCountID, ColumnGPUCountCount, ColumnGPUCountID, ColumnGPUCountCount, ColumnGPUCountCount, ColumnGPUCountCount)
+
+	start := time.Now()
+	_, err := dbRW.ExecContext(ctx, query, cnt)
+	sqlite.RecordInsertUpdate(time.Since(start).Seconds())
+	return err
+}
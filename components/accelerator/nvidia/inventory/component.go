@@ -0,0 +1,93 @@
+// Package inventory reports the NVIDIA GPU board inventory (e.g., serial number, VBIOS version).
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_inventory_id "github.com/leptonai/gpud/components/accelerator/nvidia/inventory/id"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+)
+
+func New(ctx context.Context, cfg nvidia_common.Config) (components.Component, error) {
+	if nvidia_query.GetDefaultPoller() == nil {
+		return nil, nvidia_query.ErrDefaultPollerNotSet
+	}
+
+	cfg.Query.SetDefaultsIfNotSet()
+
+	cctx, ccancel := context.WithCancel(ctx)
+	nvidia_query.GetDefaultPoller().Start(cctx, cfg.Query, nvidia_inventory_id.Name)
+
+	return &component{
+		rootCtx: ctx,
+		cancel:  ccancel,
+		poller:  nvidia_query.GetDefaultPoller(),
+	}, nil
+}
+
+var _ components.Component = (*component)(nil)
+
+type component struct {
+	rootCtx context.Context
+	cancel  context.CancelFunc
+	poller  query.Poller
+}
+
+func (c *component) Name() string { return nvidia_inventory_id.Name }
+
+func (c *component) Start() error { return nil }
+
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
+	last, err := c.poller.LastSuccess()
+	if err == query.ErrNoData { // no data
+		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_inventory_id.Name)
+		return []components.State{
+			{
+				Name:    nvidia_inventory_id.Name,
+				Healthy: true,
+				Error:   query.ErrNoData.Error(),
+				Reason:  query.ErrNoData.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allOutput, ok := last.Output.(*nvidia_query.Output)
+	if !ok {
+		return nil, fmt.Errorf("invalid output type: %T", last.Output)
+	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
+
+	output := ToOutput(allOutput)
+	return output.States()
+}
+
+func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
+	return nil, nil
+}
+
+func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
+	log.Logger.Debugw("querying metrics", "since", since)
+
+	return nil, nil
+}
+
+func (c *component) Close() error {
+	log.Logger.Debugw("closing component")
+
+	// safe to call stop multiple times
+	_ = c.poller.Stop(nvidia_inventory_id.Name)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+)
+
+// ToOutput converts nvidia_query.Output to Output.
+// It returns an empty non-nil object, if the input or the required field is nil (e.g., i.NVML).
+func ToOutput(i *nvidia_query.Output) *Output {
+	o := &Output{}
+	if i == nil {
+		return o
+	}
+
+	if i.NVML != nil {
+		for _, device := range i.NVML.DeviceInfos {
+			inv := device.Inventory
+			if inv.UUID == "" {
+				inv.UUID = device.UUID
+			}
+			o.InventoriesNVML = append(o.InventoriesNVML, inv)
+		}
+		sort.Slice(o.InventoriesNVML, func(i, j int) bool {
+			return o.InventoriesNVML[i].UUID < o.InventoriesNVML[j].UUID
+		})
+	}
+
+	return o
+}
+
+type Output struct {
+	InventoriesNVML []nvidia_query_nvml.Inventory `json:"inventories_nvml"`
+}
+
+func (o *Output) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func ParseOutputJSON(data []byte) (*Output, error) {
+	o := new(Output)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+const (
+	StateNameInventory = "inventory"
+
+	StateKeyInventoryData           = "data"
+	StateKeyInventoryEncoding       = "encoding"
+	StateValueInventoryEncodingJSON = "json"
+)
+
+func ParseStateInventory(m map[string]string) (*Output, error) {
+	data := m[StateKeyInventoryData]
+	return ParseOutputJSON([]byte(data))
+}
+
+func ParseStatesToOutput(states ...components.State) (*Output, error) {
+	for _, state := range states {
+		switch state.Name {
+		case StateNameInventory:
+			o, err := ParseStateInventory(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			return o, nil
+
+		default:
+			return nil, fmt.Errorf("unknown state name: %s", state.Name)
+		}
+	}
+	return nil, errors.New("no state found")
+}
+
+// Returns the output evaluation reason with the inventory of each GPU.
+// The inventory is informational only, thus always healthy.
+func (o *Output) Evaluate() (string, bool, error) {
+	reasons := []string{}
+	for _, inv := range o.InventoriesNVML {
+		reasons = append(reasons, fmt.Sprintf("GPU %s serial %s, board part number %s, vbios %s",
+			inv.UUID, orUnknown(inv.Serial), orUnknown(inv.BoardPartNumber), orUnknown(inv.VBIOSVersion)))
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no GPU found")
+	}
+	return strings.Join(reasons, "; "), true, nil
+}
+
+// orUnknown returns "unknown" for the fields not supported by the device
+// (e.g., serial number of the non-datacenter GPUs).
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func (o *Output) States() ([]components.State, error) {
+	outputReasons, healthy, err := o.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	b, _ := o.JSON()
+
+	state := components.State{
+		Name:    StateNameInventory,
+		Healthy: healthy,
+		Health:  components.StateHealthy,
+		Reason:  outputReasons,
+		ExtraInfo: map[string]string{
+			StateKeyInventoryData:     string(b),
+			StateKeyInventoryEncoding: StateValueInventoryEncodingJSON,
+		},
+	}
+	return []components.State{state}, nil
+}
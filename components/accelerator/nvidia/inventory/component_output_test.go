@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func newFakeDevice(serial string, serialRet nvml.Return) *mock.Device {
+	return &mock.Device{
+		GetSerialFunc: func() (string, nvml.Return) {
+			return serial, serialRet
+		},
+		GetBoardPartNumberFunc: func() (string, nvml.Return) {
+			return "692-2G520-0200-000", nvml.SUCCESS
+		},
+		GetVbiosVersionFunc: func() (string, nvml.Return) {
+			return "96.00.89.00.01", nvml.SUCCESS
+		},
+	}
+}
+
+func TestOutputStates(t *testing.T) {
+	in := &nvidia_query.Output{NVML: &nvidia_query_nvml.Output{}}
+	for uuid, dev := range map[string]*mock.Device{
+		"gpu-0": newFakeDevice("1654922012345", nvml.SUCCESS),
+		// e.g., non-datacenter GPUs do not report the serial number
+		"gpu-1": newFakeDevice("", nvml.ERROR_NOT_SUPPORTED),
+	} {
+		inv, err := nvidia_query_nvml.GetInventory(uuid, testutil.CreateDevice(dev))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		in.NVML.DeviceInfos = append(in.NVML.DeviceInfos, &nvidia_query_nvml.DeviceInfo{UUID: uuid, Inventory: inv})
+	}
+
+	states, err := ToOutput(in).States()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("expected 1 state, got %d", len(states))
+	}
+	if !states[0].Healthy || states[0].Health != components.StateHealthy {
+		t.Errorf("expected healthy, got %q (%s)", states[0].Health, states[0].Reason)
+	}
+	if !strings.Contains(states[0].Reason, "GPU gpu-1 serial unknown") {
+		t.Errorf("expected unknown serial in the reason, got %q", states[0].Reason)
+	}
+
+	parsed, err := ParseStatesToOutput(states...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed.InventoriesNVML) != 2 {
+		t.Fatalf("expected 2 inventories, got %d", len(parsed.InventoriesNVML))
+	}
+	expected := nvidia_query_nvml.Inventory{
+		UUID:            "gpu-0",
+		Serial:          "1654922012345",
+		BoardPartNumber: "692-2G520-0200-000",
+		VBIOSVersion:    "96.00.89.00.01",
+	}
+	if parsed.InventoriesNVML[0] != expected {
+		t.Errorf("expected %+v, got %+v", expected, parsed.InventoriesNVML[0])
+	}
+	if inv := parsed.InventoriesNVML[1]; inv.Serial != "" || inv.VBIOSVersion == "" {
+		t.Errorf("expected empty serial with the vbios version, got %+v", inv)
+	}
+}
+
+func TestOutputStatesNoDevice(t *testing.T) {
+	states, err := ToOutput(nil).States()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(states) != 1 || !states[0].Healthy || states[0].Reason != "no GPU found" {
+		t.Errorf("unexpected states %+v", states)
+	}
+}
@@ -0,0 +1,4 @@
+// Package id defines the GPU inventory component ID.
+package id
+
+const Name = "accelerator-nvidia-inventory"
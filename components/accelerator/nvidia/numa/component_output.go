@@ -0,0 +1,141 @@
+package numa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	"github.com/leptonai/gpud/pkg/pci"
+)
+
+// ToOutput converts nvidia_query.Output to Output.
+// The expected NUMA nodes are keyed by the PCI bus ID.
+// It returns an empty non-nil object, if the input or the required field is nil (e.g., i.NVML).
+func ToOutput(i *nvidia_query.Output, expectedNUMANodes map[string]int) *Output {
+	o := &Output{}
+	if len(expectedNUMANodes) > 0 {
+		o.ExpectedNUMANodes = make(map[string]int, len(expectedNUMANodes))
+		for busID, node := range expectedNUMANodes {
+			o.ExpectedNUMANodes[pci.NormalizeBusID(busID)] = node
+		}
+	}
+	if i == nil {
+		return o
+	}
+
+	if i.NVML != nil {
+		for _, device := range i.NVML.DeviceInfos {
+			o.NUMANodesNVML = append(o.NUMANodesNVML, NUMANode{
+				UUID:     device.UUID,
+				PCIBusID: pci.NormalizeBusID(device.PCIBusID),
+				Node:     device.NUMANode,
+			})
+		}
+	}
+
+	return o
+}
+
+// NUMANode is the NUMA node that the GPU is attached to.
+type NUMANode struct {
+	UUID     string `json:"uuid"`
+	PCIBusID string `json:"pci_bus_id"`
+	// Node is -1 if the kernel does not report the NUMA affinity.
+	Node int `json:"node"`
+}
+
+type Output struct {
+	NUMANodesNVML     []NUMANode     `json:"numa_nodes_nvml"`
+	ExpectedNUMANodes map[string]int `json:"expected_numa_nodes,omitempty"`
+}
+
+func (o *Output) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func ParseOutputJSON(data []byte) (*Output, error) {
+	o := new(Output)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+const (
+	StateNameNUMA = "numa"
+
+	StateKeyNUMAData           = "data"
+	StateKeyNUMAEncoding       = "encoding"
+	StateValueNUMAEncodingJSON = "json"
+)
+
+func ParseStateNUMA(m map[string]string) (*Output, error) {
+	data := m[StateKeyNUMAData]
+	return ParseOutputJSON([]byte(data))
+}
+
+func ParseStatesToOutput(states ...components.State) (*Output, error) {
+	for _, state := range states {
+		switch state.Name {
+		case StateNameNUMA:
+			o, err := ParseStateNUMA(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			return o, nil
+
+		default:
+			return nil, fmt.Errorf("unknown state name: %s", state.Name)
+		}
+	}
+	return nil, errors.New("no state found")
+}
+
+// Mismatches returns the informational notes for the GPUs
+// whose NUMA node differs from the expected mapping.
+func (o *Output) Mismatches() []string {
+	var notes []string
+	for _, n := range o.NUMANodesNVML {
+		expected, ok := o.ExpectedNUMANodes[n.PCIBusID]
+		if !ok || expected == n.Node {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("GPU %s (%s) is on NUMA node %d (expected %d)", n.UUID, n.PCIBusID, n.Node, expected))
+	}
+	sort.Strings(notes)
+	return notes
+}
+
+// Returns the output evaluation reason and its healthy-ness.
+// The NUMA topology mismatches are informational,
+// thus never mark the component unhealthy.
+func (o *Output) Evaluate() (string, bool, error) {
+	reasons := o.Mismatches()
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no issue detected")
+	}
+	return strings.Join(reasons, "; "), true, nil
+}
+
+func (o *Output) States() ([]components.State, error) {
+	outputReasons, healthy, err := o.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	b, _ := o.JSON()
+	state := components.State{
+		Name:    StateNameNUMA,
+		Healthy: healthy,
+		Health:  components.StateHealthy,
+		Reason:  outputReasons,
+		ExtraInfo: map[string]string{
+			StateKeyNUMAData:     string(b),
+			StateKeyNUMAEncoding: StateValueNUMAEncodingJSON,
+		},
+	}
+	return []components.State{state}, nil
+}
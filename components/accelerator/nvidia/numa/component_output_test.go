@@ -0,0 +1,78 @@
+package numa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+)
+
+func TestOutputStates(t *testing.T) {
+	in := &nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{UUID: "gpu-0", PCIBusID: "00000000:3B:00.0", NUMANode: 0},
+				{UUID: "gpu-1", PCIBusID: "00000000:5E:00.0", NUMANode: 0},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expected   map[string]int
+		wantReason string
+	}{
+		{
+			name:       "no expected mapping",
+			wantReason: "no issue detected",
+		},
+		{
+			name:       "matching topology",
+			expected:   map[string]int{"0000:3b:00.0": 0, "0000:5e:00.0": 0},
+			wantReason: "no issue detected",
+		},
+		{
+			name:       "mismatching topology",
+			expected:   map[string]int{"0000:3b:00.0": 0, "0000:5E:00.0": 1},
+			wantReason: "GPU gpu-1 (0000:5e:00.0) is on NUMA node 0 (expected 1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := ToOutput(in, tt.expected).States()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if !states[0].Healthy || states[0].Health != components.StateHealthy {
+				t.Errorf("expected healthy state, got %q", states[0].Health)
+			}
+			if states[0].Reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, states[0].Reason)
+			}
+
+			parsed, err := ParseStatesToOutput(states...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(parsed.NUMANodesNVML) != 2 || !strings.HasPrefix(parsed.NUMANodesNVML[0].PCIBusID, "0000:") {
+				t.Errorf("unexpected parsed output %+v", parsed)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{ExpectedNUMANodes: map[string]int{"0000:3b:00.0": -1}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for negative NUMA node")
+	}
+	cfg = &Config{ExpectedNUMANodes: map[string]int{"0000:3b:00.0": 1}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
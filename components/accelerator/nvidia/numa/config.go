@@ -0,0 +1,51 @@
+package numa
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	query_config "github.com/leptonai/gpud/components/query/config"
+	"github.com/leptonai/gpud/pkg/pci"
+)
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// ExpectedNUMANodes maps the GPU PCI bus ID (e.g., "0000:3b:00.0")
+	// to its expected NUMA node. If not set, no topology check is done.
+	ExpectedNUMANodes map[string]int `json:"expected_numa_nodes,omitempty"`
+
+	nvidia_common.ToolOverwrites
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	for busID, node := range cfg.ExpectedNUMANodes {
+		if pci.NormalizeBusID(busID) == "" {
+			return errors.New("empty PCI bus ID in expected NUMA nodes")
+		}
+		if node < 0 {
+			return fmt.Errorf("invalid expected NUMA node %d for PCI device %q", node, busID)
+		}
+	}
+	return nil
+}
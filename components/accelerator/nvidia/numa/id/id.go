@@ -0,0 +1,4 @@
+// Package id defines the GPU NUMA affinity component ID.
+package id
+
+const Name = "accelerator-nvidia-numa"
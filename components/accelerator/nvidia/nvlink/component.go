@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/leptonai/gpud/components"
-	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
 	nvidia_query_metrics_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/nvlink"
 	"github.com/leptonai/gpud/components/query"
@@ -19,7 +18,7 @@ import (
 
 const Name = "accelerator-nvidia-nvlink"
 
-func New(ctx context.Context, cfg nvidia_common.Config) (components.Component, error) {
+func New(ctx context.Context, cfg Config) (components.Component, error) {
 	if nvidia_query.GetDefaultPoller() == nil {
 		return nil, nvidia_query.ErrDefaultPollerNotSet
 	}
@@ -33,6 +32,9 @@ func New(ctx context.Context, cfg nvidia_common.Config) (components.Component, e
 		rootCtx: ctx,
 		cancel:  ccancel,
 		poller:  nvidia_query.GetDefaultPoller(),
+
+		errorWindow:     cfg.ErrorWindow.Duration,
+		errorThresholds: cfg.ErrorThresholds,
 	}, nil
 }
 
@@ -43,13 +45,16 @@ type component struct {
 	cancel   context.CancelFunc
 	poller   query.Poller
 	gatherer prometheus.Gatherer
+
+	errorWindow     time.Duration
+	errorThresholds ErrorThresholds
 }
 
 func (c *component) Name() string { return Name }
 
 func (c *component) Start() error { return nil }
 
-func (c *component) States(ctx context.Context) ([]components.State, error) {
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
 	last, err := c.poller.LastSuccess()
 	if err == query.ErrNoData { // no data
 		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", Name)
@@ -69,13 +74,10 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid output type: %T", last.Output)
 	}
-	if lerr := c.poller.LastError(); lerr != nil {
-		log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "error", lerr)
-	}
-	lastSuccessPollElapsed := time.Now().UTC().Sub(allOutput.Time)
-	if lastSuccessPollElapsed > 2*c.poller.Config().Interval.Duration {
-		log.Logger.Warnw("last poll is too old", "elapsed", lastSuccessPollElapsed, "interval", c.poller.Config().Interval.Duration)
-	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
 
 	if allOutput.NVML == nil {
 		return []components.State{
@@ -85,10 +87,32 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 			},
 		}, nil
 	}
-	output := ToOutput(allOutput)
+	output := ToOutput(allOutput, c.readBaseline(allOutput.Time), c.errorWindow, c.errorThresholds)
 	return output.States()
 }
 
+// readBaseline returns the oldest successful poll result within the error window
+// before "now", to count the errors within the window. Returns nil if not found.
+func (c *component) readBaseline(now time.Time) *nvidia_query.Output {
+	if c.errorWindow == 0 {
+		return nil
+	}
+	items, err := c.poller.All(now.Add(-c.errorWindow))
+	if err != nil {
+		return nil
+	}
+	for _, item := range items {
+		if item.Error != nil {
+			continue
+		}
+		o, ok := item.Output.(*nvidia_query.Output)
+		if ok && o.NVML != nil {
+			return o
+		}
+	}
+	return nil
+}
+
 func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
 	return nil, nil
 }
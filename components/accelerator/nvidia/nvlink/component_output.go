@@ -4,32 +4,158 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/leptonai/gpud/components"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
 	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/common"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ToOutput converts nvidia_query.Output to Output.
+// The errors within the window are counted against the "baseline" poll result
+// (the oldest one within the window), and the links at (or above) the thresholds are reported.
 // It returns an empty non-nil object, if the input or the required field is nil (e.g., i.SMI).
-func ToOutput(i *nvidia_query.Output) *Output {
+func ToOutput(i *nvidia_query.Output, baseline *nvidia_query.Output, window time.Duration, thresholds ErrorThresholds) *Output {
 	if i == nil {
 		return &Output{}
 	}
 
-	o := &Output{}
+	o := &Output{
+		ErrorWindow: metav1.Duration{Duration: window},
+	}
 
 	if i.NVML != nil {
 		for _, device := range i.NVML.DeviceInfos {
 			o.NVLinkDevices = append(o.NVLinkDevices, device.NVLink)
 		}
 	}
+	o.DownLinks = FindDownLinks(o.NVLinkDevices)
+
+	if baseline != nil && baseline.NVML != nil {
+		baselineDevices := make([]nvidia_query_nvml.NVLink, 0, len(baseline.NVML.DeviceInfos))
+		for _, device := range baseline.NVML.DeviceInfos {
+			baselineDevices = append(baselineDevices, device.NVLink)
+		}
+		o.ExceededLinkErrors = FindExceededLinkErrors(baselineDevices, o.NVLinkDevices, thresholds)
+	}
 
 	return o
 }
 
 type Output struct {
 	NVLinkDevices []nvidia_query_nvml.NVLink `json:"nvlink_devices"`
+
+	// ErrorWindow is the window the errors are counted within.
+	ErrorWindow metav1.Duration `json:"error_window"`
+	// ExceededLinkErrors is the list of the links with the errors
+	// at (or above) the thresholds within the error window.
+	ExceededLinkErrors []LinkErrors `json:"exceeded_link_errors,omitempty"`
+	// DownLinks is the list of the links that are down
+	// while the other links of the same GPU are up.
+	DownLinks []LinkDown `json:"down_links,omitempty"`
+}
+
+// LinkDown is a link that is down (feature disabled).
+type LinkDown struct {
+	UUID string `json:"uuid"`
+	Link int    `json:"link"`
+}
+
+// FindDownLinks returns the links that are down while the other links of the same GPU are up.
+// The GPUs with all the links down are skipped, as the GPUs without NVLink report so.
+func FindDownLinks(devices []nvidia_query_nvml.NVLink) []LinkDown {
+	var down []LinkDown
+	for _, device := range devices {
+		if !device.Supported || device.States.AllFeatureEnabled() {
+			continue
+		}
+		anyUp := false
+		for _, state := range device.States {
+			if state.FeatureEnabled {
+				anyUp = true
+				break
+			}
+		}
+		if !anyUp {
+			continue
+		}
+		for _, state := range device.States {
+			if !state.FeatureEnabled {
+				down = append(down, LinkDown{UUID: device.UUID, Link: state.Link})
+			}
+		}
+	}
+	return down
+}
+
+const (
+	LinkErrorTypeReplay   = "replay"
+	LinkErrorTypeRecovery = "recovery"
+	LinkErrorTypeCRC      = "crc"
+)
+
+// LinkErrors is the number of the errors of the type within the error window.
+type LinkErrors struct {
+	UUID      string `json:"uuid"`
+	Link      int    `json:"link"`
+	Type      string `json:"type"`
+	Errors    uint64 `json:"errors"`
+	Threshold uint64 `json:"threshold"`
+}
+
+// FindExceededLinkErrors returns the links whose error counters increased
+// by the thresholds since the baseline. The links not found in the baseline are skipped,
+// and the counters lower than the baseline (e.g., reset by the driver reload) are counted from zero.
+func FindExceededLinkErrors(baseline []nvidia_query_nvml.NVLink, current []nvidia_query_nvml.NVLink, thresholds ErrorThresholds) []LinkErrors {
+	baselineStates := make(map[string]map[int]nvidia_query_nvml.NVLinkState)
+	for _, device := range baseline {
+		baselineStates[device.UUID] = make(map[int]nvidia_query_nvml.NVLinkState)
+		for _, state := range device.States {
+			baselineStates[device.UUID][state.Link] = state
+		}
+	}
+
+	var exceeded []LinkErrors
+	for _, device := range current {
+		for _, state := range device.States {
+			prev, ok := baselineStates[device.UUID][state.Link]
+			if !ok {
+				continue
+			}
+
+			for _, c := range []struct {
+				typ       string
+				errors    uint64
+				threshold uint64
+			}{
+				{typ: LinkErrorTypeReplay, errors: counterIncrease(prev.ReplayErrors, state.ReplayErrors), threshold: thresholds.Replay},
+				{typ: LinkErrorTypeRecovery, errors: counterIncrease(prev.RecoveryErrors, state.RecoveryErrors), threshold: thresholds.Recovery},
+				{typ: LinkErrorTypeCRC, errors: counterIncrease(prev.CRCErrors+prev.CRCDataErrors, state.CRCErrors+state.CRCDataErrors), threshold: thresholds.CRC},
+			} {
+				if c.threshold == 0 || c.errors < c.threshold {
+					continue
+				}
+				exceeded = append(exceeded, LinkErrors{
+					UUID:      device.UUID,
+					Link:      state.Link,
+					Type:      c.typ,
+					Errors:    c.errors,
+					Threshold: c.threshold,
+				})
+			}
+		}
+	}
+	return exceeded
+}
+
+func counterIncrease(prev, cur uint64) uint64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
 }
 
 func (o *Output) JSON() ([]byte, error) {
@@ -74,8 +200,10 @@ func ParseStatesToOutput(states ...components.State) (*Output, error) {
 	return nil, errors.New("no state found")
 }
 
-// Returns the output evaluation reason and its healthy-ness.
-func (o *Output) Evaluate() (string, bool, error) {
+// Evaluate returns the output evaluation reason and the event type.
+// The links with the errors at (or above) the thresholds within the error window,
+// or the links down while the other links of the GPU are up, are a warning.
+func (o *Output) Evaluate() (string, common.EventType, error) {
 	reason := fmt.Sprintf("%d GPU(s):", len(o.NVLinkDevices))
 
 	// iterate all links per GPU and sum all the errors
@@ -84,26 +212,49 @@ func (o *Output) Evaluate() (string, bool, error) {
 		allRelayErrs := uint64(0)
 		allRecErrs := uint64(0)
 		for _, link := range device.States {
-			allCRCErrs += link.CRCErrors
+			allCRCErrs += link.CRCErrors + link.CRCDataErrors
 			allRelayErrs += link.ReplayErrors
 			allRecErrs += link.RecoveryErrors
 		}
 		reason += fmt.Sprintf("\n- %s: %d crc, %d relay, %d recovery errors (total %d links)", device.UUID, allCRCErrs, allRelayErrs, allRecErrs, len(device.States))
 	}
 
-	return reason, true, nil
+	eventType := common.EventTypeInfo
+	for _, e := range o.ExceededLinkErrors {
+		reason += fmt.Sprintf("\n- %s link %d: %d %s errors in the last %v (threshold %d)", e.UUID, e.Link, e.Errors, e.Type, o.ErrorWindow.Duration, e.Threshold)
+		eventType = common.EventTypeWarning
+	}
+	for _, l := range o.DownLinks {
+		reason += fmt.Sprintf("\n- %s link %d: down", l.UUID, l.Link)
+		eventType = common.EventTypeWarning
+	}
+
+	return reason, eventType, nil
 }
 
 func (o *Output) States() ([]components.State, error) {
-	outputReasons, healthy, err := o.Evaluate()
+	outputReasons, eventType, err := o.Evaluate()
 	if err != nil {
 		return nil, err
 	}
 	b, _ := o.JSON()
+
+	health := components.StateHealthy
+	if eventType == common.EventTypeWarning {
+		health = components.StateDegraded
+	}
+
+	reasonCode := ""
+	if len(o.DownLinks) > 0 {
+		reasonCode = components.ReasonCodeNVLinkDown
+	}
+
 	state := components.State{
-		Name:    StateNameNVLinkDevices,
-		Healthy: healthy,
-		Reason:  outputReasons,
+		Name:       StateNameNVLinkDevices,
+		Healthy:    true,
+		Health:     health,
+		Reason:     outputReasons,
+		ReasonCode: reasonCode,
 		ExtraInfo: map[string]string{
 			StateKeyNVLinkDevicesData:     string(b),
 			StateKeyNVLinkDevicesEncoding: StateValueNVLinkDevicesEncodingJSON,
@@ -0,0 +1,194 @@
+package nvlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+// createFakeOutput returns the poll result of a GPU with a single link
+// with the given error counters.
+func createFakeOutput(t *testing.T, replay, recovery, crcFlit, crcData uint64) *nvidia_query.Output {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetNvLinkStateFunc: func(link int) (nvml.EnableState, nvml.Return) {
+			if link > 0 {
+				return nvml.FEATURE_DISABLED, nvml.ERROR_INVALID_ARGUMENT
+			}
+			return nvml.FEATURE_ENABLED, nvml.SUCCESS
+		},
+		GetNvLinkErrorCounterFunc: func(link int, counter nvml.NvLinkErrorCounter) (uint64, nvml.Return) {
+			switch counter {
+			case nvml.NVLINK_ERROR_DL_REPLAY:
+				return replay, nvml.SUCCESS
+			case nvml.NVLINK_ERROR_DL_RECOVERY:
+				return recovery, nvml.SUCCESS
+			case nvml.NVLINK_ERROR_DL_CRC_FLIT:
+				return crcFlit, nvml.SUCCESS
+			case nvml.NVLINK_ERROR_DL_CRC_DATA:
+				return crcData, nvml.SUCCESS
+			}
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetFieldValuesFunc: func(values []nvml.FieldValue) nvml.Return {
+			return nvml.SUCCESS
+		},
+	})
+
+	nvlink, err := nvidia_query_nvml.GetNVLink("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{{UUID: "gpu-0", NVLink: nvlink}},
+		},
+	}
+}
+
+func TestOutputStates(t *testing.T) {
+	thresholds := ErrorThresholds{Replay: 100, Recovery: 1, CRC: 100}
+
+	tests := []struct {
+		name       string
+		baseline   *nvidia_query.Output
+		current    *nvidia_query.Output
+		wantHealth string
+		wantErrors []LinkErrors
+	}{
+		{
+			name:       "no baseline",
+			current:    createFakeOutput(t, 1000, 10, 1000, 1000),
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name:       "below thresholds",
+			baseline:   createFakeOutput(t, 1000, 10, 1000, 1000),
+			current:    createFakeOutput(t, 1099, 10, 1050, 1049),
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name:       "replay and crc errors at thresholds",
+			baseline:   createFakeOutput(t, 1000, 10, 1000, 1000),
+			current:    createFakeOutput(t, 1100, 10, 1050, 1050),
+			wantHealth: components.StateDegraded,
+			wantErrors: []LinkErrors{
+				{UUID: "gpu-0", Link: 0, Type: LinkErrorTypeReplay, Errors: 100, Threshold: 100},
+				{UUID: "gpu-0", Link: 0, Type: LinkErrorTypeCRC, Errors: 100, Threshold: 100},
+			},
+		},
+		{
+			name:       "recovery error after the counter reset",
+			baseline:   createFakeOutput(t, 1000, 10, 1000, 1000),
+			current:    createFakeOutput(t, 0, 1, 0, 0),
+			wantHealth: components.StateDegraded,
+			wantErrors: []LinkErrors{
+				{UUID: "gpu-0", Link: 0, Type: LinkErrorTypeRecovery, Errors: 1, Threshold: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := ToOutput(tt.current, tt.baseline, 10*time.Minute, thresholds)
+
+			states, err := o.States()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if !states[0].Healthy {
+				t.Errorf("expected healthy, got %+v", states[0])
+			}
+			if states[0].Health != tt.wantHealth {
+				t.Errorf("expected health %q, got %q (reason %q)", tt.wantHealth, states[0].Health, states[0].Reason)
+			}
+
+			parsed, err := ParseStatesToOutput(states...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(parsed.ExceededLinkErrors) != len(tt.wantErrors) {
+				t.Fatalf("expected %+v, got %+v", tt.wantErrors, parsed.ExceededLinkErrors)
+			}
+			for i := range tt.wantErrors {
+				if parsed.ExceededLinkErrors[i] != tt.wantErrors[i] {
+					t.Errorf("expected %+v, got %+v", tt.wantErrors[i], parsed.ExceededLinkErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOutputStatesLinkDown(t *testing.T) {
+	current := &nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{UUID: "gpu-0", NVLink: nvidia_query_nvml.NVLink{
+					UUID:      "gpu-0",
+					Supported: true,
+					States: nvidia_query_nvml.NVLinkStates{
+						{Link: 0, FeatureEnabled: true},
+						{Link: 1, FeatureEnabled: false},
+					},
+				}},
+				// no nvlink, thus all links disabled
+				{UUID: "gpu-1", NVLink: nvidia_query_nvml.NVLink{
+					UUID:      "gpu-1",
+					Supported: true,
+					States: nvidia_query_nvml.NVLinkStates{
+						{Link: 0, FeatureEnabled: false},
+						{Link: 1, FeatureEnabled: false},
+					},
+				}},
+			},
+		},
+	}
+
+	o := ToOutput(current, nil, 10*time.Minute, ErrorThresholds{})
+	if len(o.DownLinks) != 1 || o.DownLinks[0] != (LinkDown{UUID: "gpu-0", Link: 1}) {
+		t.Fatalf("unexpected down links %+v", o.DownLinks)
+	}
+
+	states, err := o.States()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if states[0].Health != components.StateDegraded {
+		t.Errorf("expected degraded, got %q", states[0].Health)
+	}
+	if states[0].ReasonCode != components.ReasonCodeNVLinkDown {
+		t.Errorf("expected reason code %q, got %q", components.ReasonCodeNVLinkDown, states[0].ReasonCode)
+	}
+
+	// no reason code when all the links are up
+	o = ToOutput(createFakeOutput(t, 0, 0, 0, 0), nil, 10*time.Minute, ErrorThresholds{})
+	states, err = o.States()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if states[0].ReasonCode != "" {
+		t.Errorf("expected no reason code, got %q", states[0].ReasonCode)
+	}
+}
+
+func TestConfigValidateDefaults(t *testing.T) {
+	cfg := &Config{ErrorThresholds: ErrorThresholds{Replay: 5}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ErrorWindow.Duration != DefaultErrorWindow {
+		t.Errorf("expected error window %v, got %v", DefaultErrorWindow, cfg.ErrorWindow.Duration)
+	}
+	expected := ErrorThresholds{Replay: 5, Recovery: DefaultRecoveryErrorsThreshold, CRC: DefaultCRCErrorsThreshold}
+	if cfg.ErrorThresholds != expected {
+		t.Errorf("expected %+v, got %+v", expected, cfg.ErrorThresholds)
+	}
+}
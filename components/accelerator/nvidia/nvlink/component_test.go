@@ -4,7 +4,6 @@ import (
 	"context"
 	"testing"
 
-	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
 
 	"github.com/stretchr/testify/assert"
@@ -15,7 +14,7 @@ func TestComponentWithNoPoller(t *testing.T) {
 	defer cancel()
 
 	defaultPoller := nvidia_query.GetDefaultPoller()
-	_, err := New(ctx, nvidia_common.Config{})
+	_, err := New(ctx, Config{})
 
 	if defaultPoller != nil {
 		// expects no error
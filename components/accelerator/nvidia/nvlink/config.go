@@ -0,0 +1,79 @@
+package nvlink
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	query_config "github.com/leptonai/gpud/components/query/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultErrorWindow is the default window to count the NVLink errors within.
+	DefaultErrorWindow = 10 * time.Minute
+
+	DefaultReplayErrorsThreshold   = 100
+	DefaultRecoveryErrorsThreshold = 1
+	DefaultCRCErrorsThreshold      = 100
+)
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// ErrorWindow is the window to count the NVLink errors within.
+	// The window is bounded by the poll results kept in memory
+	// (queue size times the poll interval).
+	// If not set, it defaults to DefaultErrorWindow.
+	ErrorWindow metav1.Duration `json:"error_window"`
+
+	// ErrorThresholds is the number of the errors per link within the error window,
+	// at (or above) which the GPU is reported as degraded.
+	ErrorThresholds ErrorThresholds `json:"error_thresholds"`
+
+	nvidia_common.ToolOverwrites
+}
+
+// ErrorThresholds is the per-link error thresholds.
+// The thresholds not set (zero) default to the Default*ErrorsThreshold values.
+type ErrorThresholds struct {
+	Replay   uint64 `json:"replay"`
+	Recovery uint64 `json:"recovery"`
+	// CRC is the threshold of the crc flit and data errors combined.
+	CRC uint64 `json:"crc"`
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.ErrorWindow.Duration == 0 {
+		cfg.ErrorWindow.Duration = DefaultErrorWindow
+	}
+	if cfg.ErrorThresholds.Replay == 0 {
+		cfg.ErrorThresholds.Replay = DefaultReplayErrorsThreshold
+	}
+	if cfg.ErrorThresholds.Recovery == 0 {
+		cfg.ErrorThresholds.Recovery = DefaultRecoveryErrorsThreshold
+	}
+	if cfg.ErrorThresholds.CRC == 0 {
+		cfg.ErrorThresholds.CRC = DefaultCRCErrorsThreshold
+	}
+	return nil
+}
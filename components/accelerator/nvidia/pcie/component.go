@@ -0,0 +1,135 @@
+// Package pcie tracks the NVIDIA GPU PCIe link width downtraining (and the link generation).
+package pcie
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_pcie_id "github.com/leptonai/gpud/components/accelerator/nvidia/pcie/id"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_metrics_pcie "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/pcie"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+)
+
+func New(ctx context.Context, cfg nvidia_common.Config) (components.Component, error) {
+	if nvidia_query.GetDefaultPoller() == nil {
+		return nil, nvidia_query.ErrDefaultPollerNotSet
+	}
+
+	cfg.Query.SetDefaultsIfNotSet()
+
+	cctx, ccancel := context.WithCancel(ctx)
+	nvidia_query.GetDefaultPoller().Start(cctx, cfg.Query, nvidia_pcie_id.Name)
+
+	return &component{
+		rootCtx: ctx,
+		cancel:  ccancel,
+		poller:  nvidia_query.GetDefaultPoller(),
+	}, nil
+}
+
+var _ components.Component = (*component)(nil)
+
+type component struct {
+	rootCtx  context.Context
+	cancel   context.CancelFunc
+	poller   query.Poller
+	gatherer prometheus.Gatherer
+}
+
+func (c *component) Name() string { return nvidia_pcie_id.Name }
+
+func (c *component) Start() error { return nil }
+
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
+	last, err := c.poller.LastSuccess()
+	if err == query.ErrNoData { // no data
+		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_pcie_id.Name)
+		return []components.State{
+			{
+				Name:    nvidia_pcie_id.Name,
+				Healthy: true,
+				Error:   query.ErrNoData.Error(),
+				Reason:  query.ErrNoData.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allOutput, ok := last.Output.(*nvidia_query.Output)
+	if !ok {
+		return nil, fmt.Errorf("invalid output type: %T", last.Output)
+	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
+
+	output := ToOutput(allOutput)
+	return output.States()
+}
+
+func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
+	return nil, nil
+}
+
+func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
+	log.Logger.Debugw("querying metrics", "since", since)
+
+	currentWidths, err := nvidia_query_metrics_pcie.ReadCurrentLinkWidth(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current link widths: %w", err)
+	}
+	maxWidths, err := nvidia_query_metrics_pcie.ReadMaxLinkWidth(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read max link widths: %w", err)
+	}
+	currentGens, err := nvidia_query_metrics_pcie.ReadCurrentLinkGeneration(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current link generations: %w", err)
+	}
+	maxGens, err := nvidia_query_metrics_pcie.ReadMaxLinkGeneration(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read max link generations: %w", err)
+	}
+
+	ms := make([]components.Metric, 0, len(currentWidths)+len(maxWidths)+len(currentGens)+len(maxGens))
+	for _, mss := range []components_metrics_state.Metrics{currentWidths, maxWidths, currentGens, maxGens} {
+		for _, m := range mss {
+			ms = append(ms, components.Metric{
+				Metric: m,
+				ExtraInfo: map[string]string{
+					"gpu_id": m.MetricSecondaryName,
+				},
+			})
+		}
+	}
+
+	return ms, nil
+}
+
+func (c *component) Close() error {
+	log.Logger.Debugw("closing component")
+
+	// safe to call stop multiple times
+	_ = c.poller.Stop(nvidia_pcie_id.Name)
+
+	return nil
+}
+
+var _ components.PromRegisterer = (*component)(nil)
+
+func (c *component) RegisterCollectors(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	c.gatherer = reg
+	return nvidia_query_metrics_pcie.Register(reg, dbRW, dbRO, tableName)
+}
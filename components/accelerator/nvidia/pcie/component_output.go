@@ -0,0 +1,122 @@
+package pcie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+)
+
+// ToOutput converts nvidia_query.Output to Output.
+// It returns an empty non-nil object, if the input or the required field is nil (e.g., i.NVML).
+func ToOutput(i *nvidia_query.Output) *Output {
+	o := &Output{}
+	if i == nil {
+		return o
+	}
+
+	if i.NVML != nil {
+		for _, device := range i.NVML.DeviceInfos {
+			o.PCIeLinksNVML = append(o.PCIeLinksNVML, device.PCIeLink)
+		}
+	}
+
+	return o
+}
+
+type Output struct {
+	PCIeLinksNVML []nvidia_query_nvml.PCIeLink `json:"pcie_links_nvml"`
+}
+
+func (o *Output) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func ParseOutputJSON(data []byte) (*Output, error) {
+	o := new(Output)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+const (
+	StateNamePCIe = "pcie"
+
+	StateKeyPCIeData           = "data"
+	StateKeyPCIeEncoding       = "encoding"
+	StateValuePCIeEncodingJSON = "json"
+)
+
+func ParseStatePCIe(m map[string]string) (*Output, error) {
+	data := m[StateKeyPCIeData]
+	return ParseOutputJSON([]byte(data))
+}
+
+func ParseStatesToOutput(states ...components.State) (*Output, error) {
+	for _, state := range states {
+		switch state.Name {
+		case StateNamePCIe:
+			o, err := ParseStatePCIe(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			return o, nil
+
+		default:
+			return nil, fmt.Errorf("unknown state name: %s", state.Name)
+		}
+	}
+	return nil, errors.New("no state found")
+}
+
+// Returns the output evaluation reason and its healthy-ness.
+// The GPUs whose current link width is below the max are reported,
+// since the downtrained link often precedes the GPU falling off the bus (Xid 79).
+// The link generation is not evaluated, since the GPUs lower the generation
+// when idle to save power.
+// The GPUs that do not support the PCIe link queries are ignored.
+func (o *Output) Evaluate() (string, bool, error) {
+	reasons := []string{}
+
+	healthy := true
+	for _, l := range o.PCIeLinksNVML {
+		if l.WidthDowntrained() {
+			reasons = append(reasons, fmt.Sprintf("GPU %s PCIe link width downtrained to x%d (max x%d)", l.UUID, l.CurrentLinkWidth, l.MaxLinkWidth))
+			healthy = false
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no issue detected")
+	}
+	return strings.Join(reasons, "; "), healthy, nil
+}
+
+func (o *Output) States() ([]components.State, error) {
+	outputReasons, healthy, err := o.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	b, _ := o.JSON()
+
+	health := components.StateHealthy
+	if !healthy {
+		health = components.StateDegraded
+	}
+	state := components.State{
+		Name:    StateNamePCIe,
+		Healthy: healthy,
+		Health:  health,
+		Reason:  outputReasons,
+		ExtraInfo: map[string]string{
+			StateKeyPCIeData:     string(b),
+			StateKeyPCIeEncoding: StateValuePCIeEncodingJSON,
+		},
+	}
+	return []components.State{state}, nil
+}
@@ -0,0 +1,124 @@
+package pcie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func createDevice(currWidth, maxWidth, currGen, maxGen int) *mock.Device {
+	return &mock.Device{
+		GetCurrPcieLinkWidthFunc: func() (int, nvml.Return) {
+			return currWidth, nvml.SUCCESS
+		},
+		GetMaxPcieLinkWidthFunc: func() (int, nvml.Return) {
+			return maxWidth, nvml.SUCCESS
+		},
+		GetCurrPcieLinkGenerationFunc: func() (int, nvml.Return) {
+			return currGen, nvml.SUCCESS
+		},
+		GetMaxPcieLinkGenerationFunc: func() (int, nvml.Return) {
+			return maxGen, nvml.SUCCESS
+		},
+	}
+}
+
+func TestOutputStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		devices    map[string]*mock.Device
+		wantHealth string
+		wantReason string
+	}{
+		{
+			name:       "no device",
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected",
+		},
+		{
+			name: "healthy x16 gen4",
+			devices: map[string]*mock.Device{
+				"gpu-0": createDevice(16, 16, 4, 4),
+			},
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected",
+		},
+		{
+			name: "downtrained x8",
+			devices: map[string]*mock.Device{
+				"gpu-0": createDevice(16, 16, 4, 4),
+				"gpu-1": createDevice(8, 16, 4, 4),
+			},
+			wantHealth: components.StateDegraded,
+			wantReason: "GPU gpu-1 PCIe link width downtrained to x8 (max x16)",
+		},
+		{
+			name: "idle gen1",
+			devices: map[string]*mock.Device{
+				"gpu-0": createDevice(16, 16, 1, 4),
+			},
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected",
+		},
+		{
+			name: "not supported",
+			devices: map[string]*mock.Device{
+				"gpu-0": {
+					GetCurrPcieLinkWidthFunc: func() (int, nvml.Return) {
+						return 0, nvml.ERROR_NOT_SUPPORTED
+					},
+				},
+			},
+			wantHealth: components.StateHealthy,
+			wantReason: "no issue detected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &nvidia_query.Output{NVML: &nvidia_query_nvml.Output{}}
+			for _, uuid := range []string{"gpu-0", "gpu-1"} {
+				dev, ok := tt.devices[uuid]
+				if !ok {
+					continue
+				}
+				link, err := nvidia_query_nvml.GetPCIeLink(uuid, testutil.CreateDevice(dev))
+				if err != nil {
+					t.Fatalf("failed to get pcie link: %v", err)
+				}
+				in.NVML.DeviceInfos = append(in.NVML.DeviceInfos, &nvidia_query_nvml.DeviceInfo{UUID: uuid, PCIeLink: link})
+			}
+
+			states, err := ToOutput(in).States()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if states[0].Health != tt.wantHealth {
+				t.Errorf("expected health %q, got %q (%s)", tt.wantHealth, states[0].Health, states[0].Reason)
+			}
+			if states[0].Healthy != (tt.wantHealth == components.StateHealthy) {
+				t.Errorf("unexpected healthy %v", states[0].Healthy)
+			}
+			if !strings.Contains(states[0].Reason, tt.wantReason) {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, states[0].Reason)
+			}
+
+			parsed, err := ParseStatesToOutput(states...)
+			if err != nil {
+				t.Fatalf("failed to parse states: %v", err)
+			}
+			if len(parsed.PCIeLinksNVML) != len(tt.devices) {
+				t.Errorf("expected %d pcie links, got %d", len(tt.devices), len(parsed.PCIeLinksNVML))
+			}
+		})
+	}
+}
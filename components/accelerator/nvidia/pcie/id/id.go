@@ -0,0 +1,4 @@
+// Package id defines the GPU PCIe link component ID.
+package id
+
+const Name = "accelerator-nvidia-pcie"
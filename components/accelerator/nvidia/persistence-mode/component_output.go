@@ -21,6 +21,8 @@ func ToOutput(i *nvidia_query.Output) *Output {
 	o := &Output{}
 
 	if i.NVML != nil {
+		o.DriverVersion = i.NVML.DriverVersion
+		o.NVMLVersion = i.NVML.NVMLVersion
 		for _, device := range i.NVML.DeviceInfos {
 			o.PersistenceModesNVML = append(o.PersistenceModesNVML, device.PersistenceMode)
 		}
@@ -38,6 +40,9 @@ func ToOutput(i *nvidia_query.Output) *Output {
 type Output struct {
 	PersistenceModesSMI  []nvidia_query.SMIGPUPersistenceMode `json:"persistence_modes_smi"`
 	PersistenceModesNVML []nvidia_query_nvml.PersistenceMode  `json:"persistence_modes_nvml"`
+
+	DriverVersion string `json:"driver_version,omitempty"`
+	NVMLVersion   string `json:"nvml_version,omitempty"`
 }
 
 func (o *Output) JSON() ([]byte, error) {
@@ -58,6 +63,9 @@ const (
 	StateKeyPersistenceModeData       = "data"
 	StateKeyPersistenceModeEncoding   = "encoding"
 	StateValueMemoryUsageEncodingJSON = "json"
+
+	StateKeyDriverVersion = "driver_version"
+	StateKeyNVMLVersion   = "nvml_version"
 )
 
 func ParseStatePersistenceMode(m map[string]string) (*Output, error) {
@@ -101,7 +109,7 @@ func (o *Output) Evaluate() (string, bool, error) {
 		// legacy mode (https://docs.nvidia.com/deploy/driver-persistence/index.html#installation)
 		// "The reason why we cannot immediately deprecate the legacy persistence mode and switch transparently to the NVIDIA Persistence Daemon is because at this time,
 		// we cannot guarantee that the NVIDIA Persistence Daemon will be running. This would be a feature regression as persistence mode might not be available out-of- the-box."
-		if !p.Enabled {
+		if p.Supported && !p.Enabled {
 			reasons = append(reasons, fmt.Sprintf("persistence mode is not enabled on %s (NVML)", p.UUID))
 			enabled = false
 		}
@@ -116,13 +124,23 @@ func (o *Output) States() ([]components.State, error) {
 		return nil, err
 	}
 	b, _ := o.JSON()
+
+	// persistence mode off does not fail the workloads
+	// but may cause the latency and the flaky NVML reads, thus only warn
+	health := components.StateHealthy
+	if !healthy {
+		health = components.StateDegraded
+	}
 	state := components.State{
 		Name:    StateNamePersistenceMode,
 		Healthy: healthy,
+		Health:  health,
 		Reason:  outputReasons,
 		ExtraInfo: map[string]string{
 			StateKeyPersistenceModeData:     string(b),
 			StateKeyPersistenceModeEncoding: StateValueMemoryUsageEncodingJSON,
+			StateKeyDriverVersion:           o.DriverVersion,
+			StateKeyNVMLVersion:             o.NVMLVersion,
 		},
 	}
 	return []components.State{state}, nil
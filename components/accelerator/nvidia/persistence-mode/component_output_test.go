@@ -0,0 +1,77 @@
+package persistencemode
+
+import (
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+)
+
+func TestOutputStates(t *testing.T) {
+	tests := []struct {
+		name        string
+		modes       []nvidia_query_nvml.PersistenceMode
+		wantHealthy bool
+		wantHealth  string
+		wantReason  string
+	}{
+		{
+			name: "enabled",
+			modes: []nvidia_query_nvml.PersistenceMode{
+				{UUID: "GPU-0", Enabled: true, Supported: true},
+				{UUID: "GPU-1", Enabled: true, Supported: true},
+			},
+			wantHealthy: true,
+			wantHealth:  components.StateHealthy,
+		},
+		{
+			name: "disabled",
+			modes: []nvidia_query_nvml.PersistenceMode{
+				{UUID: "GPU-0", Enabled: true, Supported: true},
+				{UUID: "GPU-1", Enabled: false, Supported: true},
+			},
+			wantHealthy: false,
+			wantHealth:  components.StateDegraded,
+			wantReason:  "persistence mode is not enabled on GPU-1 (NVML)",
+		},
+		{
+			name: "not supported",
+			modes: []nvidia_query_nvml.PersistenceMode{
+				{UUID: "GPU-0", Supported: false},
+			},
+			wantHealthy: true,
+			wantHealth:  components.StateHealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Output{
+				PersistenceModesNVML: tt.modes,
+				DriverVersion:        "535.161.08",
+				NVMLVersion:          "12.535.161.08",
+			}
+			states, err := o.States()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			s := states[0]
+			if s.Healthy != tt.wantHealthy || s.Health != tt.wantHealth || s.Reason != tt.wantReason {
+				t.Errorf("unexpected state %+v", s)
+			}
+			if s.ExtraInfo[StateKeyDriverVersion] != "535.161.08" || s.ExtraInfo[StateKeyNVMLVersion] != "12.535.161.08" {
+				t.Errorf("expected driver and nvml versions, got %v", s.ExtraInfo)
+			}
+
+			parsed, err := ParseStatesToOutput(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.DriverVersion != "535.161.08" || len(parsed.PersistenceModesNVML) != len(tt.modes) {
+				t.Errorf("unexpected parsed output %+v", parsed)
+			}
+		})
+	}
+}
@@ -2,12 +2,18 @@ package persistencemode
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/query"
+	query_config "github.com/leptonai/gpud/components/query/config"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestComponentWithNoPoller(t *testing.T) {
@@ -25,3 +31,60 @@ func TestComponentWithNoPoller(t *testing.T) {
 		assert.Equal(t, err, nvidia_query.ErrDefaultPollerNotSet)
 	}
 }
+
+type stalePoller struct {
+	query.Poller
+	good query.Item
+	last query.Item
+	cfg  query_config.Config
+}
+
+func (p *stalePoller) ID() string                        { return "test" }
+func (p *stalePoller) Config() query_config.Config       { return p.cfg }
+func (p *stalePoller) Last() (*query.Item, error)        { return &p.last, nil }
+func (p *stalePoller) LastSuccess() (*query.Item, error) { return &p.good, nil }
+
+func TestComponentStatesStaleAfterPollError(t *testing.T) {
+	now := time.Now().UTC()
+	goodOutput := &nvidia_query.Output{
+		Time: now.Add(-time.Minute),
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{UUID: "gpu-0", PersistenceMode: nvidia_query_nvml.PersistenceMode{UUID: "gpu-0", Enabled: true}},
+			},
+		},
+	}
+	good := query.Item{Time: metav1.NewTime(goodOutput.Time), Output: goodOutput}
+	cfg := query_config.Config{Interval: metav1.Duration{Duration: time.Minute}}
+
+	expected, err := ToOutput(goodOutput).States()
+	assert.NoError(t, err)
+
+	c := &component{poller: &stalePoller{good: good, last: good, cfg: cfg}}
+	states, err := c.States(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, states, len(expected))
+	for i := range states {
+		assert.False(t, states[i].Stale)
+		assert.True(t, states[i].LastUpdated.Time.Equal(goodOutput.Time))
+	}
+
+	// the most recent poll failed
+	c = &component{poller: &stalePoller{
+		good: good,
+		last: query.Item{Time: metav1.NewTime(now), Error: errors.New("test error")},
+		cfg:  cfg,
+	}}
+	states, err = c.States(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, states, len(expected))
+	for i := range states {
+		assert.True(t, states[i].Stale)
+		assert.True(t, states[i].LastUpdated.Time.Equal(goodOutput.Time))
+
+		// retains the last-good values
+		assert.Equal(t, expected[i].Healthy, states[i].Healthy)
+		assert.Equal(t, expected[i].Reason, states[i].Reason)
+		assert.Equal(t, expected[i].ExtraInfo, states[i].ExtraInfo)
+	}
+}
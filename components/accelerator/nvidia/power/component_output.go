@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/leptonai/gpud/components"
 	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
@@ -24,6 +25,10 @@ func ToOutput(i *nvidia_query.Output) *Output {
 	if i.NVML != nil {
 		for _, device := range i.NVML.DeviceInfos {
 			o.UsagesNVML = append(o.UsagesNVML, device.Power)
+
+			if device.ClockEvents != nil && device.ClockEvents.Supported {
+				o.CappingsNVML = append(o.CappingsNVML, NewPowerCapping(device.Power, *device.ClockEvents))
+			}
 		}
 	}
 
@@ -44,8 +49,43 @@ func ToOutput(i *nvidia_query.Output) *Output {
 }
 
 type Output struct {
-	UsagesSMI  []nvidia_query.ParsedSMIPowerReading `json:"usages_smi"`
-	UsagesNVML []nvidia_query_nvml.Power            `json:"usages_nvml"`
+	UsagesSMI    []nvidia_query.ParsedSMIPowerReading `json:"usages_smi"`
+	UsagesNVML   []nvidia_query_nvml.Power            `json:"usages_nvml"`
+	CappingsNVML []PowerCapping                       `json:"cappings_nvml,omitempty"`
+}
+
+// PowerCapping represents the power draw of a GPU and
+// whether its clocks are currently capped by power or thermal limits.
+type PowerCapping struct {
+	// Represents the GPU UUID.
+	UUID string `json:"uuid"`
+
+	UsageMilliWatts         uint32 `json:"usage_milli_watts"`
+	EnforcedLimitMilliWatts uint32 `json:"enforced_limit_milli_watts"`
+
+	// Represents the bitmask of active clocks event reasons.
+	ReasonsBitmask uint64 `json:"reasons_bitmask"`
+
+	// Set true if the clocks are limited by the SW power cap or the HW power brake.
+	PowerCapped bool `json:"power_capped"`
+	// Set true if the HW power brake slowdown is engaged.
+	PowerBrake bool `json:"power_brake"`
+	// Set true if the HW thermal slowdown is engaged.
+	ThermalBrake bool `json:"thermal_brake"`
+}
+
+// NewPowerCapping derives the power capping status from the power readings and
+// the clock events reasons (nvmlDeviceGetCurrentClocksEventReasons) of a GPU.
+func NewPowerCapping(power nvidia_query_nvml.Power, clockEvents nvidia_query_nvml.ClockEvents) PowerCapping {
+	return PowerCapping{
+		UUID:                    power.UUID,
+		UsageMilliWatts:         power.UsageMilliWatts,
+		EnforcedLimitMilliWatts: power.EnforcedLimitMilliWatts,
+		ReasonsBitmask:          clockEvents.ReasonsBitmask,
+		PowerCapped:             clockEvents.SWPowerCap || clockEvents.HWSlowdownPowerBrake,
+		PowerBrake:              clockEvents.HWSlowdownPowerBrake,
+		ThermalBrake:            clockEvents.HWSlowdownThermal,
+	}
 }
 
 func (o *Output) JSON() ([]byte, error) {
@@ -61,7 +101,8 @@ func ParseOutputJSON(data []byte) (*Output, error) {
 }
 
 const (
-	StateNamePowerUsage = "power_usage"
+	StateNamePowerUsage   = "power_usage"
+	StateNamePowerCapping = "power_capping"
 
 	StateKeyPowerUsageData           = "data"
 	StateKeyPowerUsageEncoding       = "encoding"
@@ -83,6 +124,10 @@ func ParseStatesToOutput(states ...components.State) (*Output, error) {
 			}
 			return o, nil
 
+		case StateNamePowerCapping:
+			// the capping state shares the same output with the usage state
+			continue
+
 		default:
 			return nil, fmt.Errorf("unknown state name: %s", state.Name)
 		}
@@ -129,5 +174,40 @@ func (o *Output) States() ([]components.State, error) {
 			StateKeyPowerUsageEncoding: StateValuePowerUsageEncodingJSON,
 		},
 	}
-	return []components.State{state}, nil
+	return []components.State{state, o.powerCappingState(b)}, nil
+}
+
+// Returns the degraded state if any GPU is being slowed down
+// by the HW power brake or the HW thermal slowdown.
+func (o *Output) powerCappingState(data []byte) components.State {
+	var capped []string
+	var msgs []string
+	for _, c := range o.CappingsNVML {
+		if c.PowerCapped {
+			capped = append(capped, c.UUID)
+		}
+		if c.PowerBrake {
+			msgs = append(msgs, fmt.Sprintf("%s: power brake slowdown engaged (%.2f W / %.2f W)", c.UUID, float64(c.UsageMilliWatts)/1000.0, float64(c.EnforcedLimitMilliWatts)/1000.0))
+		}
+		if c.ThermalBrake {
+			msgs = append(msgs, fmt.Sprintf("%s: thermal slowdown engaged", c.UUID))
+		}
+	}
+
+	health := components.StateHealthy
+	reason := fmt.Sprintf("no power or thermal brake engaged (%d GPU(s) power capped)", len(capped))
+	if len(msgs) > 0 {
+		health = components.StateDegraded
+		reason = strings.Join(msgs, ", ")
+	}
+	return components.State{
+		Name:    StateNamePowerCapping,
+		Healthy: health == components.StateHealthy,
+		Health:  health,
+		Reason:  reason,
+		ExtraInfo: map[string]string{
+			StateKeyPowerUsageData:     string(data),
+			StateKeyPowerUsageEncoding: StateValuePowerUsageEncodingJSON,
+		},
+	}
 }
@@ -0,0 +1,157 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+)
+
+// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlClocksEventReasons.html
+const (
+	reasonSWPowerCap           uint64 = 0x0000000000000004
+	reasonHWSlowdown           uint64 = 0x0000000000000008
+	reasonHWSlowdownThermal    uint64 = 0x0000000000000040
+	reasonHWSlowdownPowerBrake uint64 = 0x0000000000000080
+)
+
+func newFakeDeviceInfo(t *testing.T, uuid string, usageMilliWatts uint32, reasons uint64) *nvidia_query_nvml.DeviceInfo {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetPowerUsageFunc: func() (uint32, nvml.Return) {
+			return usageMilliWatts, nvml.SUCCESS
+		},
+		GetEnforcedPowerLimitFunc: func() (uint32, nvml.Return) {
+			return 700000, nvml.SUCCESS
+		},
+		GetPowerManagementLimitFunc: func() (uint32, nvml.Return) {
+			return 700000, nvml.SUCCESS
+		},
+		GetCurrentClocksEventReasonsFunc: func() (uint64, nvml.Return) {
+			return reasons, nvml.SUCCESS
+		},
+	})
+
+	power, err := nvidia_query_nvml.GetPower(uuid, dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clockEvents, err := nvidia_query_nvml.GetClockEvents(uuid, dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &nvidia_query_nvml.DeviceInfo{
+		UUID:        uuid,
+		Power:       power,
+		ClockEvents: &clockEvents,
+	}
+}
+
+func TestOutputPowerCapping(t *testing.T) {
+	tests := []struct {
+		name            string
+		reasons         uint64
+		wantCapped      bool
+		wantPowerBrake  bool
+		wantThermal     bool
+		wantHealth      string
+		wantHealthyBool bool
+	}{
+		{
+			name:            "normal operation",
+			reasons:         0,
+			wantHealth:      components.StateHealthy,
+			wantHealthyBool: true,
+		},
+		{
+			name:            "sw power capped",
+			reasons:         reasonSWPowerCap,
+			wantCapped:      true,
+			wantHealth:      components.StateHealthy,
+			wantHealthyBool: true,
+		},
+		{
+			name:           "hw power brake",
+			reasons:        reasonSWPowerCap | reasonHWSlowdown | reasonHWSlowdownPowerBrake,
+			wantCapped:     true,
+			wantPowerBrake: true,
+			wantHealth:     components.StateDegraded,
+		},
+		{
+			name:        "hw thermal slowdown",
+			reasons:     reasonHWSlowdown | reasonHWSlowdownThermal,
+			wantThermal: true,
+			wantHealth:  components.StateDegraded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := ToOutput(&nvidia_query.Output{
+				NVML: &nvidia_query_nvml.Output{
+					DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+						newFakeDeviceInfo(t, "GPU-0", 650000, tt.reasons),
+					},
+				},
+			})
+			if len(o.CappingsNVML) != 1 {
+				t.Fatalf("expected 1 capping, got %d", len(o.CappingsNVML))
+			}
+			c := o.CappingsNVML[0]
+			if c.UsageMilliWatts != 650000 || c.EnforcedLimitMilliWatts != 700000 {
+				t.Errorf("unexpected power readings: %+v", c)
+			}
+			if c.PowerCapped != tt.wantCapped {
+				t.Errorf("expected power capped %v, got %v", tt.wantCapped, c.PowerCapped)
+			}
+			if c.PowerBrake != tt.wantPowerBrake {
+				t.Errorf("expected power brake %v, got %v", tt.wantPowerBrake, c.PowerBrake)
+			}
+			if c.ThermalBrake != tt.wantThermal {
+				t.Errorf("expected thermal brake %v, got %v", tt.wantThermal, c.ThermalBrake)
+			}
+
+			states, err := o.States()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(states) != 2 {
+				t.Fatalf("expected 2 states, got %d", len(states))
+			}
+			st := states[1]
+			if st.Name != StateNamePowerCapping {
+				t.Fatalf("expected state %q, got %q", StateNamePowerCapping, st.Name)
+			}
+			if st.Health != tt.wantHealth || st.Healthy != tt.wantHealthyBool {
+				t.Errorf("expected health %q (healthy %v), got %q (healthy %v): %s", tt.wantHealth, tt.wantHealthyBool, st.Health, st.Healthy, st.Reason)
+			}
+
+			parsed, err := ParseStatesToOutput(states...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed.CappingsNVML) != 1 || parsed.CappingsNVML[0] != c {
+				t.Errorf("unexpected parsed cappings: %+v", parsed.CappingsNVML)
+			}
+		})
+	}
+}
+
+func TestOutputPowerCappingUnsupported(t *testing.T) {
+	o := ToOutput(&nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{
+					UUID:        "GPU-0",
+					ClockEvents: &nvidia_query_nvml.ClockEvents{UUID: "GPU-0", Supported: false},
+				},
+			},
+		},
+	})
+	if len(o.CappingsNVML) != 0 {
+		t.Errorf("expected no cappings when clock events are not supported, got %d", len(o.CappingsNVML))
+	}
+}
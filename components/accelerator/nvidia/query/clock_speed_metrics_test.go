@@ -0,0 +1,114 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	metrics_clockspeed "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/clock-speed"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestSetClockSpeedMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	tableName := "test_metrics"
+	if err := components_metrics_state.CreateTableMetrics(ctx, dbRW, tableName); err != nil {
+		t.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	if err := metrics_clockspeed.Register(reg, dbRW, dbRO, tableName); err != nil {
+		t.Fatal(err)
+	}
+
+	newDevice := func(curMHz, maxMHz uint32) device.Device {
+		return testutil.CreateDevice(&mock.Device{
+			GetClockInfoFunc: func(nvml.ClockType) (uint32, nvml.Return) {
+				return curMHz, nvml.SUCCESS
+			},
+			GetMaxClockInfoFunc: func(nvml.ClockType) (uint32, nvml.Return) {
+				return maxMHz, nvml.SUCCESS
+			},
+		})
+	}
+	devs := map[string]device.Device{
+		"GPU-0": newDevice(1980, 1980),
+		"GPU-1": newDevice(900, 1980), // downclocked
+	}
+	now := time.Now().UTC()
+	for uuid, dev := range devs {
+		clockSpeed, err := nvidia_query_nvml.GetClockSpeed(uuid, dev)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", uuid, err)
+		}
+		if err := setClockSpeedMetrics(ctx, &nvidia_query_nvml.DeviceInfo{UUID: uuid, ClockSpeed: clockSpeed}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() != "gpu_id" {
+					continue
+				}
+				if values[mf.GetName()] == nil {
+					values[mf.GetName()] = make(map[string]float64)
+				}
+				values[mf.GetName()][l.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+
+	expected := map[string]map[string]float64{
+		"graphics_mhz":     {"GPU-0": 1980, "GPU-1": 900},
+		"sm_mhz":           {"GPU-0": 1980, "GPU-1": 900},
+		"memory_mhz":       {"GPU-0": 1980, "GPU-1": 900},
+		"graphics_max_mhz": {"GPU-0": 1980, "GPU-1": 1980},
+		"sm_max_mhz":       {"GPU-0": 1980, "GPU-1": 1980},
+		"memory_max_mhz":   {"GPU-0": 1980, "GPU-1": 1980},
+	}
+	for name, want := range expected {
+		got := values[metrics_clockspeed.SubSystem+"_"+name]
+		gpuIDs := make([]string, 0, len(got))
+		for id := range got {
+			gpuIDs = append(gpuIDs, id)
+		}
+		sort.Strings(gpuIDs)
+		if len(gpuIDs) != 2 {
+			t.Errorf("%s: expected metrics for both GPUs, got %v", name, gpuIDs)
+			continue
+		}
+		for id, v := range want {
+			if got[id] != v {
+				t.Errorf("%s: expected %v for %s, got %v", name, v, id, got[id])
+			}
+		}
+	}
+
+	ms, err := metrics_clockspeed.ReadSMMHzs(ctx, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 {
+		t.Errorf("expected SM clock observations for both GPUs, got %+v", ms)
+	}
+}
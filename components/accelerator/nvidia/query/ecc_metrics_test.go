@@ -0,0 +1,112 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	metrics_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/ecc"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestSetECCMetricsMemoryErrorCounts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	tableName := "test_metrics"
+	if err := components_metrics_state.CreateTableMetrics(ctx, dbRW, tableName); err != nil {
+		t.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	if err := metrics_ecc.Register(reg, dbRW, dbRO, tableName); err != nil {
+		t.Fatal(err)
+	}
+
+	eccErrors := func(cnt uint64) nvidia_query_nvml.ECCErrors {
+		return nvidia_query_nvml.ECCErrors{
+			Aggregate: nvidia_query_nvml.AllECCErrorCounts{GPUDeviceMemory: nvidia_query_nvml.ECCErrorCounts{Corrected: cnt, Uncorrected: cnt}},
+			Volatile:  nvidia_query_nvml.AllECCErrorCounts{GPUDeviceMemory: nvidia_query_nvml.ECCErrorCounts{Corrected: cnt, Uncorrected: cnt}},
+			Supported: true,
+		}
+	}
+	devs := []nvidia_query_nvml.DeviceInfo{
+		{
+			UUID:      "GPU-supported",
+			ECCMode:   nvidia_query_nvml.ECCMode{EnabledCurrent: true},
+			ECCErrors: eccErrors(3),
+		},
+		{
+			UUID:      "GPU-unsupported",
+			ECCMode:   nvidia_query_nvml.ECCMode{EnabledCurrent: true},
+			ECCErrors: nvidia_query_nvml.ECCErrors{Supported: false},
+		},
+		{
+			// the device memory counts are not queried without the ECC mode
+			UUID:      "GPU-ecc-disabled",
+			ECCMode:   nvidia_query_nvml.ECCMode{EnabledCurrent: false},
+			ECCErrors: eccErrors(0),
+		},
+	}
+	now := time.Now().UTC()
+	for i := range devs {
+		if err := setECCMetrics(ctx, &devs[i], now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string][]string)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "gpu_id" {
+					found[mf.GetName()] = append(found[mf.GetName()], l.GetValue())
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"volatile_single_bit",
+		"volatile_double_bit",
+		"aggregate_single_bit",
+		"aggregate_double_bit",
+	} {
+		gpuIDs := found[metrics_ecc.SubSystem+"_"+name]
+		sort.Strings(gpuIDs)
+		if len(gpuIDs) != 1 || gpuIDs[0] != "GPU-supported" {
+			t.Errorf("%s: expected only the ECC-supported GPU, got %v", name, gpuIDs)
+		}
+
+		// the averager is also observed for the supported GPU only
+		var read func(context.Context, time.Time) (components_metrics_state.Metrics, error)
+		switch name {
+		case "volatile_single_bit":
+			read = metrics_ecc.ReadVolatileSingleBit
+		case "volatile_double_bit":
+			read = metrics_ecc.ReadVolatileDoubleBit
+		case "aggregate_single_bit":
+			read = metrics_ecc.ReadAggregateSingleBit
+		case "aggregate_double_bit":
+			read = metrics_ecc.ReadAggregateDoubleBit
+		}
+		ms, err := read(ctx, now.Add(-time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ms) != 1 || ms[0].MetricSecondaryName != "GPU-supported" || ms[0].Value != 3 {
+			t.Errorf("%s: unexpected metrics %+v", name, ms)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package infiniband
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultInfinibandClassDirectory is the sysfs directory of the infiniband devices.
+const DefaultInfinibandClassDirectory = "/sys/class/infiniband"
+
+const (
+	// PortStateActive is the logical port state when the port is ready to send and receive packets.
+	PortStateActive = "ACTIVE"
+	// PortPhysStateLinkUp is the physical port state when the link is up.
+	PortPhysStateLinkUp = "LinkUp"
+)
+
+// SysfsPort is the infiniband port status read from the sysfs
+// (e.g., "/sys/class/infiniband/mlx5_0/ports/1").
+type SysfsPort struct {
+	// Device is the infiniband device name (e.g., "mlx5_0").
+	Device string `json:"device"`
+	// Port is the port number of the device (e.g., 1).
+	Port int `json:"port"`
+
+	// State is the logical port state (e.g., "ACTIVE", "DOWN", "INIT").
+	State string `json:"state"`
+	// PhysState is the physical port state (e.g., "LinkUp", "Polling", "Disabled").
+	PhysState string `json:"phys_state"`
+	// Rate is the raw link rate (e.g., "400 Gb/sec (4X NDR)").
+	Rate string `json:"rate"`
+	// RateGbps is the link rate in Gb/sec (e.g., 400).
+	RateGbps float64 `json:"rate_gbps"`
+	// LinkLayer is the link layer of the port (e.g., "InfiniBand", "Ethernet").
+	LinkLayer string `json:"link_layer,omitempty"`
+}
+
+// Name returns the port name in the format of "<device>/<port>" (e.g., "mlx5_0/1").
+func (p SysfsPort) Name() string {
+	return fmt.Sprintf("%s/%d", p.Device, p.Port)
+}
+
+// Active returns true if the port is logically active and its physical link is up.
+func (p SysfsPort) Active() bool {
+	return strings.EqualFold(p.State, PortStateActive) && strings.EqualFold(p.PhysState, PortPhysStateLinkUp)
+}
+
+// ReadSysfsPorts reads all the infiniband ports under the class directory
+// (e.g., "/sys/class/infiniband/*/ports/*"), sorted by the device name and the port number.
+// Returns no port with no error if the directory does not exist
+// (e.g., the host has no infiniband device).
+func ReadSysfsPorts(classDir string) ([]SysfsPort, error) {
+	if classDir == "" {
+		classDir = DefaultInfinibandClassDirectory
+	}
+	devs, err := os.ReadDir(classDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ports []SysfsPort
+	for _, dev := range devs {
+		portsDir := filepath.Join(classDir, dev.Name(), "ports")
+		entries, err := os.ReadDir(portsDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			portNum, err := strconv.Atoi(entry.Name())
+			if err != nil {
+				continue
+			}
+			port, err := readSysfsPort(filepath.Join(portsDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			port.Device = dev.Name()
+			port.Port = portNum
+			ports = append(ports, port)
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Device != ports[j].Device {
+			return ports[i].Device < ports[j].Device
+		}
+		return ports[i].Port < ports[j].Port
+	})
+	return ports, nil
+}
+
+func readSysfsPort(dir string) (SysfsPort, error) {
+	state, err := readSysfsFile(filepath.Join(dir, "state"))
+	if err != nil {
+		return SysfsPort{}, err
+	}
+	physState, err := readSysfsFile(filepath.Join(dir, "phys_state"))
+	if err != nil {
+		return SysfsPort{}, err
+	}
+	rate, err := readSysfsFile(filepath.Join(dir, "rate"))
+	if err != nil {
+		return SysfsPort{}, err
+	}
+	rateGbps, err := ParseSysfsRate(rate)
+	if err != nil {
+		return SysfsPort{}, err
+	}
+
+	// optional, not available in old kernels
+	linkLayer, err := readSysfsFile(filepath.Join(dir, "link_layer"))
+	if err != nil && !os.IsNotExist(err) {
+		return SysfsPort{}, err
+	}
+
+	return SysfsPort{
+		State:     ParseSysfsState(state),
+		PhysState: ParseSysfsState(physState),
+		Rate:      rate,
+		RateGbps:  rateGbps,
+		LinkLayer: linkLayer,
+	}, nil
+}
+
+func readSysfsFile(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// ParseSysfsState parses the port state from the sysfs, dropping the numeric prefix
+// (e.g., "4: ACTIVE" becomes "ACTIVE", "5: LinkUp" becomes "LinkUp").
+func ParseSysfsState(s string) string {
+	if _, after, ok := strings.Cut(s, ":"); ok {
+		return strings.TrimSpace(after)
+	}
+	return strings.TrimSpace(s)
+}
+
+// ParseSysfsRate parses the link rate in Gb/sec from the sysfs
+// (e.g., "400 Gb/sec (4X NDR)" becomes 400).
+func ParseSysfsRate(s string) (float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse rate %q: %w", s, err)
+	}
+	return v, nil
+}
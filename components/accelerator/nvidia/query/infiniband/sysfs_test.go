@@ -0,0 +1,88 @@
+package infiniband
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSysfsPort(t *testing.T, classDir string, dev string, port string, files map[string]string) {
+	t.Helper()
+
+	dir := filepath.Join(classDir, dev, "ports", port)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadSysfsPorts(t *testing.T) {
+	classDir := t.TempDir()
+	writeSysfsPort(t, classDir, "mlx5_1", "1", map[string]string{
+		"state":      "1: DOWN\n",
+		"phys_state": "2: Polling\n",
+		"rate":       "10 Gb/sec (4X SDR)\n",
+	})
+	writeSysfsPort(t, classDir, "mlx5_0", "1", map[string]string{
+		"state":      "4: ACTIVE\n",
+		"phys_state": "5: LinkUp\n",
+		"rate":       "400 Gb/sec (4X NDR)\n",
+		"link_layer": "InfiniBand\n",
+	})
+
+	ports, err := ReadSysfsPorts(classDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []SysfsPort{
+		{Device: "mlx5_0", Port: 1, State: "ACTIVE", PhysState: "LinkUp", Rate: "400 Gb/sec (4X NDR)", RateGbps: 400, LinkLayer: "InfiniBand"},
+		{Device: "mlx5_1", Port: 1, State: "DOWN", PhysState: "Polling", Rate: "10 Gb/sec (4X SDR)", RateGbps: 10},
+	}
+	if !reflect.DeepEqual(ports, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, ports)
+	}
+
+	if !ports[0].Active() || ports[1].Active() {
+		t.Errorf("unexpected active ports %+v", ports)
+	}
+	if ports[0].Name() != "mlx5_0/1" {
+		t.Errorf("unexpected port name %q", ports[0].Name())
+	}
+}
+
+func TestReadSysfsPortsNotExist(t *testing.T) {
+	ports, err := ReadSysfsPorts(filepath.Join(t.TempDir(), "infiniband"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 0 {
+		t.Fatalf("expected no port, got %+v", ports)
+	}
+}
+
+func TestParseSysfsRate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		wantErr  bool
+	}{
+		{input: "400 Gb/sec (4X NDR)", expected: 400},
+		{input: "2.5 Gb/sec (1X SDR)", expected: 2.5},
+		{input: "", expected: 0},
+		{input: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSysfsRate(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSysfsRate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.expected {
+			t.Errorf("ParseSysfsRate(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	metrics_memory "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/memory"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestSetMemoryMetricsDeletesStaleMIG(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	tableName := "test_metrics"
+	if err := components_metrics_state.CreateTableMetrics(ctx, dbRW, tableName); err != nil {
+		t.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	if err := metrics_memory.Register(reg, dbRW, dbRO, tableName); err != nil {
+		t.Fatal(err)
+	}
+
+	migIDs := func() []string {
+		mfs, err := reg.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, mf := range mfs {
+			if mf.GetName() != metrics_memory.SubSystem+"_mig_total_bytes" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "mig_id" {
+						ids = append(ids, l.GetValue())
+					}
+				}
+			}
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	dev := &nvidia_query_nvml.DeviceInfo{
+		UUID: "GPU-0",
+		Memory: nvidia_query_nvml.Memory{
+			TotalBytes:  80,
+			UsedPercent: "0.00",
+			MIGInstances: []nvidia_query_nvml.MIGMemory{
+				{UUID: "MIG-0", TotalBytes: 40, UsedPercent: "0.00"},
+				{UUID: "MIG-1", TotalBytes: 40, UsedPercent: "0.00"},
+			},
+		},
+	}
+	if err := setMemoryMetrics(ctx, dev, time.Now().UTC(), &Output{}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := migIDs(); len(ids) != 2 || ids[0] != "MIG-0" || ids[1] != "MIG-1" {
+		t.Fatalf("expected MIG-0 and MIG-1, got %v", ids)
+	}
+
+	// the destroyed MIG instance is no longer reported
+	dev.Memory.MIGInstances = dev.Memory.MIGInstances[:1]
+	if err := setMemoryMetrics(ctx, dev, time.Now().UTC(), &Output{}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := migIDs(); len(ids) != 1 || ids[0] != "MIG-0" {
+		t.Fatalf("expected only MIG-0, got %v", ids)
+	}
+}
@@ -56,6 +56,35 @@ var (
 		[]string{"gpu_id", "ema_period"},
 	)
 
+	smMHz = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "sm_mhz",
+			Help:      "tracks the current GPU SM clock speed in MHz",
+		},
+		[]string{"gpu_id"},
+	)
+	smMHzAverager = components_metrics.NewNoOpAverager()
+	smMHzAverage  = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "sm_mhz_avg",
+			Help:      "tracks the GPU SM clock speed in MHz with average for the last period",
+		},
+		[]string{"gpu_id", "last_period"},
+	)
+	smMHzEMA = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "sm_mhz_ema",
+			Help:      "tracks the GPU SM clock speed in MHz with exponential moving average",
+		},
+		[]string{"gpu_id", "ema_period"},
+	)
+
 	memoryMHz = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "",
@@ -84,10 +113,41 @@ var (
 		},
 		[]string{"gpu_id", "ema_period"},
 	)
+
+	// the max clock speeds are tracked alongside the current ones
+	// so that downclocking (e.g., due to clock events) is visible
+	graphicsMaxMHz = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "graphics_max_mhz",
+			Help:      "tracks the max GPU graphics clock speed in MHz",
+		},
+		[]string{"gpu_id"},
+	)
+	smMaxMHz = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "sm_max_mhz",
+			Help:      "tracks the max GPU SM clock speed in MHz",
+		},
+		[]string{"gpu_id"},
+	)
+	memoryMaxMHz = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "memory_max_mhz",
+			Help:      "tracks the max GPU memory clock speed in MHz",
+		},
+		[]string{"gpu_id"},
+	)
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
 	graphicsMHzAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_graphics_mhz")
+	smMHzAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_sm_mhz")
 	memoryMHzAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_memory_mhz")
 }
 
@@ -95,6 +155,10 @@ func ReadGraphicsMHzs(ctx context.Context, since time.Time) (components_metrics_
 	return graphicsMHzAverager.Read(ctx, components_metrics.WithSince(since))
 }
 
+func ReadSMMHzs(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return smMHzAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
 func ReadMemoryMHzs(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
 	return memoryMHzAverager.Read(ctx, components_metrics.WithSince(since))
 }
@@ -140,6 +204,43 @@ func SetGraphicsMHz(ctx context.Context, gpuID string, pct uint32, currentTime t
 	return nil
 }
 
+func SetSMMHz(ctx context.Context, gpuID string, mhz uint32, currentTime time.Time) error {
+	smMHz.WithLabelValues(gpuID).Set(float64(mhz))
+
+	if err := smMHzAverager.Observe(
+		ctx,
+		float64(mhz),
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	for _, duration := range defaultPeriods {
+		avg, err := smMHzAverager.Avg(
+			ctx,
+			components_metrics.WithSince(currentTime.Add(-duration)),
+			components_metrics.WithMetricSecondaryName(gpuID),
+		)
+		if err != nil {
+			return err
+		}
+		smMHzAverage.WithLabelValues(gpuID, duration.String()).Set(avg)
+
+		ema, err := smMHzAverager.EMA(
+			ctx,
+			components_metrics.WithEMAPeriod(duration),
+			components_metrics.WithMetricSecondaryName(gpuID),
+		)
+		if err != nil {
+			return err
+		}
+		smMHzEMA.WithLabelValues(gpuID, duration.String()).Set(ema)
+	}
+
+	return nil
+}
+
 func SetMemoryMHz(ctx context.Context, gpuID string, pct uint32, currentTime time.Time) error {
 	memoryMHz.WithLabelValues(gpuID).Set(float64(pct))
 
@@ -177,6 +278,18 @@ func SetMemoryMHz(ctx context.Context, gpuID string, pct uint32, currentTime tim
 	return nil
 }
 
+func SetGraphicsMaxMHz(gpuID string, mhz uint32) {
+	graphicsMaxMHz.WithLabelValues(gpuID).Set(float64(mhz))
+}
+
+func SetSMMaxMHz(gpuID string, mhz uint32) {
+	smMaxMHz.WithLabelValues(gpuID).Set(float64(mhz))
+}
+
+func SetMemoryMaxMHz(gpuID string, mhz uint32) {
+	memoryMaxMHz.WithLabelValues(gpuID).Set(float64(mhz))
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -192,6 +305,15 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(graphicsMHzEMA); err != nil {
 		return err
 	}
+	if err := reg.Register(smMHz); err != nil {
+		return err
+	}
+	if err := reg.Register(smMHzAverage); err != nil {
+		return err
+	}
+	if err := reg.Register(smMHzEMA); err != nil {
+		return err
+	}
 	if err := reg.Register(memoryMHz); err != nil {
 		return err
 	}
@@ -201,5 +323,14 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(memoryMHzEMA); err != nil {
 		return err
 	}
+	if err := reg.Register(graphicsMaxMHz); err != nil {
+		return err
+	}
+	if err := reg.Register(smMaxMHz); err != nil {
+		return err
+	}
+	if err := reg.Register(memoryMaxMHz); err != nil {
+		return err
+	}
 	return nil
 }
@@ -4,6 +4,7 @@ package clock
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 
 	components_metrics "github.com/leptonai/gpud/components/metrics"
@@ -56,6 +57,23 @@ var (
 		[]string{"gpu_id"},
 	)
 	hwSlowdownPowerBrakeAverager = components_metrics.NewNoOpAverager()
+
+	// cumulative throttle durations from the NVML violation counters
+	// use with "rate" to compute the fraction of time the GPU spent throttled
+	violationSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "violation_seconds_total",
+			Help:      "tracks the cumulative duration in seconds the GPU has been throttled due to the power or thermal constraints",
+		},
+		[]string{"gpu_id", "reason"}, // reason is "power" or "thermal"
+	)
+
+	// tracks the last observed violation durations per GPU and reason
+	// to convert the absolute NVML counters into the counter increments
+	lastViolationMu sync.Mutex
+	lastViolation   = make(map[string]time.Duration)
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
@@ -137,6 +155,30 @@ func SetHWSlowdownPowerBrake(ctx context.Context, gpuID string, b bool, currentT
 	return nil
 }
 
+func SetPowerViolationDuration(gpuID string, d time.Duration) {
+	setViolationDuration(gpuID, "power", d)
+}
+
+func SetThermalViolationDuration(gpuID string, d time.Duration) {
+	setViolationDuration(gpuID, "thermal", d)
+}
+
+func setViolationDuration(gpuID string, reason string, d time.Duration) {
+	lastViolationMu.Lock()
+	defer lastViolationMu.Unlock()
+
+	key := gpuID + "/" + reason
+	prev, ok := lastViolation[key]
+	lastViolation[key] = d
+
+	delta := d - prev
+	if !ok || delta < 0 {
+		// first observation or the NVML counter has been reset (e.g., driver reload)
+		delta = d
+	}
+	violationSeconds.WithLabelValues(gpuID, reason).Add(delta.Seconds())
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -152,6 +194,9 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(hwSlowdownPowerBrake); err != nil {
 		return err
 	}
+	if err := reg.Register(violationSeconds); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -67,6 +67,50 @@ var (
 		[]string{"gpu_id"},
 	)
 	volatileTotalUncorrectedAverager = components_metrics.NewNoOpAverager()
+
+	volatileSingleBit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "volatile_single_bit",
+			Help:      "tracks the current volatile device memory single-bit (corrected) ecc errors",
+		},
+		[]string{"gpu_id"},
+	)
+	volatileSingleBitAverager = components_metrics.NewNoOpAverager()
+
+	volatileDoubleBit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "volatile_double_bit",
+			Help:      "tracks the current volatile device memory double-bit (uncorrected) ecc errors",
+		},
+		[]string{"gpu_id"},
+	)
+	volatileDoubleBitAverager = components_metrics.NewNoOpAverager()
+
+	aggregateSingleBit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "aggregate_single_bit",
+			Help:      "tracks the current aggregate device memory single-bit (corrected) ecc errors",
+		},
+		[]string{"gpu_id"},
+	)
+	aggregateSingleBitAverager = components_metrics.NewNoOpAverager()
+
+	aggregateDoubleBit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "aggregate_double_bit",
+			Help:      "tracks the current aggregate device memory double-bit (uncorrected) ecc errors",
+		},
+		[]string{"gpu_id"},
+	)
+	aggregateDoubleBitAverager = components_metrics.NewNoOpAverager()
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
@@ -74,6 +118,10 @@ func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
 	aggregateTotalUncorrectedAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_aggregate_total_uncorrected")
 	volatileTotalCorrectedAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_volatile_total_corrected")
 	volatileTotalUncorrectedAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_volatile_total_uncorrected")
+	volatileSingleBitAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_volatile_single_bit")
+	volatileDoubleBitAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_volatile_double_bit")
+	aggregateSingleBitAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_aggregate_single_bit")
+	aggregateDoubleBitAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_aggregate_double_bit")
 }
 
 func ReadAggregateTotalCorrected(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
@@ -92,6 +140,22 @@ func ReadVolatileTotalUncorrected(ctx context.Context, since time.Time) (compone
 	return volatileTotalUncorrectedAverager.Read(ctx, components_metrics.WithSince(since))
 }
 
+func ReadVolatileSingleBit(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return volatileSingleBitAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadVolatileDoubleBit(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return volatileDoubleBitAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadAggregateSingleBit(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return aggregateSingleBitAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadAggregateDoubleBit(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return aggregateDoubleBitAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
 func SetLastUpdateUnixSeconds(unixSeconds float64) {
 	lastUpdateUnixSeconds.Set(unixSeconds)
 }
@@ -156,6 +220,66 @@ func SetVolatileTotalUncorrected(ctx context.Context, gpuID string, cnt float64,
 	return nil
 }
 
+func SetVolatileSingleBit(ctx context.Context, gpuID string, cnt float64, currentTime time.Time) error {
+	volatileSingleBit.WithLabelValues(gpuID).Set(cnt)
+
+	if err := volatileSingleBitAverager.Observe(
+		ctx,
+		cnt,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SetVolatileDoubleBit(ctx context.Context, gpuID string, cnt float64, currentTime time.Time) error {
+	volatileDoubleBit.WithLabelValues(gpuID).Set(cnt)
+
+	if err := volatileDoubleBitAverager.Observe(
+		ctx,
+		cnt,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SetAggregateSingleBit(ctx context.Context, gpuID string, cnt float64, currentTime time.Time) error {
+	aggregateSingleBit.WithLabelValues(gpuID).Set(cnt)
+
+	if err := aggregateSingleBitAverager.Observe(
+		ctx,
+		cnt,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SetAggregateDoubleBit(ctx context.Context, gpuID string, cnt float64, currentTime time.Time) error {
+	aggregateDoubleBit.WithLabelValues(gpuID).Set(cnt)
+
+	if err := aggregateDoubleBitAverager.Observe(
+		ctx,
+		cnt,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -174,5 +298,17 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(volatileTotalUncorrected); err != nil {
 		return err
 	}
+	if err := reg.Register(volatileSingleBit); err != nil {
+		return err
+	}
+	if err := reg.Register(volatileDoubleBit); err != nil {
+		return err
+	}
+	if err := reg.Register(aggregateSingleBit); err != nil {
+		return err
+	}
+	if err := reg.Register(aggregateDoubleBit); err != nil {
+		return err
+	}
 	return nil
 }
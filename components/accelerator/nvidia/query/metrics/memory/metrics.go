@@ -4,6 +4,7 @@ package memory
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 
 	components_metrics "github.com/leptonai/gpud/components/metrics"
@@ -115,6 +116,34 @@ var (
 		},
 		[]string{"gpu_id", "ema_period"},
 	)
+
+	migTotalBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "mig_total_bytes",
+			Help:      "tracks the total memory of the MIG instance in bytes",
+		},
+		[]string{"gpu_id", "mig_id"},
+	)
+	migUsedBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "mig_used_bytes",
+			Help:      "tracks the used memory of the MIG instance in bytes",
+		},
+		[]string{"gpu_id", "mig_id"},
+	)
+	migUsedPercent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "mig_used_percent",
+			Help:      "tracks the percentage of memory used of the MIG instance",
+		},
+		[]string{"gpu_id", "mig_id"},
+	)
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
@@ -236,6 +265,57 @@ func SetUsedPercent(ctx context.Context, gpuID string, pct float64, currentTime
 	return nil
 }
 
+var (
+	migIDsMu sync.Mutex
+	// MIG instance IDs with the metrics set, keyed by the GPU ID
+	migIDs = make(map[string]map[string]struct{})
+)
+
+func trackMIG(gpuID string, migID string) {
+	migIDsMu.Lock()
+	defer migIDsMu.Unlock()
+	if migIDs[gpuID] == nil {
+		migIDs[gpuID] = make(map[string]struct{})
+	}
+	migIDs[gpuID][migID] = struct{}{}
+}
+
+// DeleteStaleMIG deletes the metrics of the MIG instances of the GPU
+// that are not in the current MIG instances (e.g., the destroyed instances).
+func DeleteStaleMIG(gpuID string, currentMIGIDs []string) {
+	current := make(map[string]struct{}, len(currentMIGIDs))
+	for _, id := range currentMIGIDs {
+		current[id] = struct{}{}
+	}
+
+	migIDsMu.Lock()
+	defer migIDsMu.Unlock()
+	for migID := range migIDs[gpuID] {
+		if _, ok := current[migID]; ok {
+			continue
+		}
+		migTotalBytes.DeleteLabelValues(gpuID, migID)
+		migUsedBytes.DeleteLabelValues(gpuID, migID)
+		migUsedPercent.DeleteLabelValues(gpuID, migID)
+		delete(migIDs[gpuID], migID)
+	}
+}
+
+func SetMIGTotalBytes(gpuID string, migID string, bytes float64) {
+	trackMIG(gpuID, migID)
+	migTotalBytes.WithLabelValues(gpuID, migID).Set(bytes)
+}
+
+func SetMIGUsedBytes(gpuID string, migID string, bytes float64) {
+	trackMIG(gpuID, migID)
+	migUsedBytes.WithLabelValues(gpuID, migID).Set(bytes)
+}
+
+func SetMIGUsedPercent(gpuID string, migID string, pct float64) {
+	trackMIG(gpuID, migID)
+	migUsedPercent.WithLabelValues(gpuID, migID).Set(pct)
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -269,5 +349,14 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(usedPercentEMA); err != nil {
 		return err
 	}
+	if err := reg.Register(migTotalBytes); err != nil {
+		return err
+	}
+	if err := reg.Register(migUsedBytes); err != nil {
+		return err
+	}
+	if err := reg.Register(migUsedPercent); err != nil {
+		return err
+	}
 	return nil
 }
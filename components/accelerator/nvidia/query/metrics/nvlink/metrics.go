@@ -4,6 +4,7 @@ package nvlink
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"time"
 
 	components_metrics "github.com/leptonai/gpud/components/metrics"
@@ -68,6 +69,16 @@ var (
 	)
 	crcErrorsAverager = components_metrics.NewNoOpAverager()
 
+	linkErrors = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "link_errors",
+			Help:      "tracks the NVLink error counters per link (type is one of replay, recovery, crc_flit, crc_data)",
+		},
+		[]string{"gpu_id", "link", "type"},
+	)
+
 	txBytesTotal = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "",
@@ -210,6 +221,19 @@ func SetCRCErrors(ctx context.Context, gpuID string, errors uint64, currentTime
 	return nil
 }
 
+const (
+	LinkErrorTypeReplay   = "replay"
+	LinkErrorTypeRecovery = "recovery"
+	LinkErrorTypeCRCFlit  = "crc_flit"
+	LinkErrorTypeCRCData  = "crc_data"
+)
+
+// SetLinkErrors sets the error counter of the error type for the link.
+// Only exported as the prometheus metrics, as the per-GPU totals are persisted.
+func SetLinkErrors(gpuID string, link int, errType string, errors uint64) {
+	linkErrors.WithLabelValues(gpuID, strconv.Itoa(link), errType).Set(float64(errors))
+}
+
 func SetTxBytes(ctx context.Context, gpuID string, bytes float64, currentTime time.Time) error {
 	txBytesTotal.WithLabelValues(gpuID).Set(bytes)
 
@@ -284,6 +308,9 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(crcErrors); err != nil {
 		return err
 	}
+	if err := reg.Register(linkErrors); err != nil {
+		return err
+	}
 	if err := reg.Register(txBytesTotal); err != nil {
 		return err
 	}
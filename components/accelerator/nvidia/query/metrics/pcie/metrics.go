@@ -0,0 +1,149 @@
+// Package pcie provides the NVIDIA PCIe link metrics collection and reporting.
+package pcie
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const SubSystem = "accelerator_nvidia_pcie"
+
+var (
+	lastUpdateUnixSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "last_update_unix_seconds",
+			Help:      "tracks the last update time in unix seconds",
+		},
+	)
+
+	currentLinkWidth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "current_link_width",
+			Help:      "tracks the current PCIe link width",
+		},
+		[]string{"gpu_id"},
+	)
+	currentLinkWidthAverager = components_metrics.NewNoOpAverager()
+
+	maxLinkWidth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "max_link_width",
+			Help:      "tracks the max PCIe link width",
+		},
+		[]string{"gpu_id"},
+	)
+	maxLinkWidthAverager = components_metrics.NewNoOpAverager()
+
+	currentLinkGeneration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "current_link_generation",
+			Help:      "tracks the current PCIe link generation",
+		},
+		[]string{"gpu_id"},
+	)
+	currentLinkGenerationAverager = components_metrics.NewNoOpAverager()
+
+	maxLinkGeneration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "max_link_generation",
+			Help:      "tracks the max PCIe link generation",
+		},
+		[]string{"gpu_id"},
+	)
+	maxLinkGenerationAverager = components_metrics.NewNoOpAverager()
+)
+
+func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
+	currentLinkWidthAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_current_link_width")
+	maxLinkWidthAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_max_link_width")
+	currentLinkGenerationAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_current_link_generation")
+	maxLinkGenerationAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_max_link_generation")
+}
+
+func ReadCurrentLinkWidth(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return currentLinkWidthAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadMaxLinkWidth(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return maxLinkWidthAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadCurrentLinkGeneration(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return currentLinkGenerationAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadMaxLinkGeneration(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return maxLinkGenerationAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func SetLastUpdateUnixSeconds(unixSeconds float64) {
+	lastUpdateUnixSeconds.Set(unixSeconds)
+}
+
+func SetCurrentLinkWidth(ctx context.Context, gpuID string, width int, currentTime time.Time) error {
+	return set(ctx, currentLinkWidth, currentLinkWidthAverager, gpuID, float64(width), currentTime)
+}
+
+func SetMaxLinkWidth(ctx context.Context, gpuID string, width int, currentTime time.Time) error {
+	return set(ctx, maxLinkWidth, maxLinkWidthAverager, gpuID, float64(width), currentTime)
+}
+
+func SetCurrentLinkGeneration(ctx context.Context, gpuID string, gen int, currentTime time.Time) error {
+	return set(ctx, currentLinkGeneration, currentLinkGenerationAverager, gpuID, float64(gen), currentTime)
+}
+
+func SetMaxLinkGeneration(ctx context.Context, gpuID string, gen int, currentTime time.Time) error {
+	return set(ctx, maxLinkGeneration, maxLinkGenerationAverager, gpuID, float64(gen), currentTime)
+}
+
+func set(ctx context.Context, gauge *prometheus.GaugeVec, averager components_metrics.Averager, gpuID string, v float64, currentTime time.Time) error {
+	gauge.WithLabelValues(gpuID).Set(v)
+
+	if err := averager.Observe(
+		ctx,
+		v,
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(gpuID),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	InitAveragers(dbRW, dbRO, tableName)
+
+	if err := reg.Register(lastUpdateUnixSeconds); err != nil {
+		return err
+	}
+	if err := reg.Register(currentLinkWidth); err != nil {
+		return err
+	}
+	if err := reg.Register(maxLinkWidth); err != nil {
+		return err
+	}
+	if err := reg.Register(currentLinkGeneration); err != nil {
+		return err
+	}
+	if err := reg.Register(maxLinkGeneration); err != nil {
+		return err
+	}
+	return nil
+}
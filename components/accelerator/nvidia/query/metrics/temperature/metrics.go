@@ -67,6 +67,16 @@ var (
 	)
 	thresholdSlowdownCelsiusAverager = components_metrics.NewNoOpAverager()
 
+	thresholdShutdownCelsius = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "shutdown_threshold_celsius",
+			Help:      "tracks the threshold temperature in celsius for shutdown",
+		},
+		[]string{"gpu_id"},
+	)
+
 	slowdownUsedPercent = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "",
@@ -171,6 +181,10 @@ func SetThresholdSlowdownCelsius(ctx context.Context, gpuID string, temp float64
 	return nil
 }
 
+func SetThresholdShutdownCelsius(gpuID string, temp float64) {
+	thresholdShutdownCelsius.WithLabelValues(gpuID).Set(temp)
+}
+
 func SetSlowdownUsedPercent(ctx context.Context, gpuID string, pct float64, currentTime time.Time) error {
 	slowdownUsedPercent.WithLabelValues(gpuID).Set(pct)
 
@@ -226,6 +240,9 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(thresholdSlowdownCelsius); err != nil {
 		return err
 	}
+	if err := reg.Register(thresholdShutdownCelsius); err != nil {
+		return err
+	}
 	if err := reg.Register(slowdownUsedPercent); err != nil {
 		return err
 	}
@@ -0,0 +1,200 @@
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DmonValueError is the value that "nvidia-smi dmon" prints
+	// when it fails to query the field (e.g., GPU fell off the bus).
+	DmonValueError = "ERR!"
+	// DmonValueNotAvailable is the value that "nvidia-smi dmon" prints
+	// when the field is not supported or not available.
+	DmonValueNotAvailable = "-"
+
+	// DmonColumnGPU is the GPU index column.
+	DmonColumnGPU = "gpu"
+	// DmonColumnPowerViolation is the power violation column ("-s v").
+	DmonColumnPowerViolation = "pviol"
+	// DmonColumnThermalViolation is the thermal violation column ("-s v").
+	DmonColumnThermalViolation = "tviol"
+)
+
+// DefaultDmonSelectors selects the power/temperature ("p"), utilization ("u"),
+// clocks ("c"), and the power/thermal violations ("v") metrics.
+const DefaultDmonSelectors = "pucv"
+
+// RunDmon runs "nvidia-smi dmon" for the given number of samples (one sample per second).
+// Make sure to call this with a timeout, as a broken GPU may block the command.
+func RunDmon(ctx context.Context, nvidiaSMICommand string, count int) ([]DmonSample, error) {
+	if nvidiaSMICommand == "" {
+		nvidiaSMICommand = "nvidia-smi"
+	}
+	b, err := RunSMI(ctx, []string{nvidiaSMICommand, "dmon", "-s", DefaultDmonSelectors, "-c", strconv.Itoa(count)})
+	if err != nil {
+		return nil, err
+	}
+	return ParseDmon(b)
+}
+
+// DmonSample is a single per-GPU row of the "nvidia-smi dmon" output.
+type DmonSample struct {
+	// GPU is the GPU index.
+	GPU int `json:"gpu"`
+	// Values maps the column name (e.g., "pwr", "tviol") to its raw value,
+	// which may be "-" (not available) or "ERR!" (failed to query).
+	Values map[string]string `json:"values"`
+}
+
+// ParseDmon parses the "nvidia-smi dmon" columnar output.
+// The header lines start with "#" (column names followed by units),
+// and may be repeated in the middle of the output.
+//
+// e.g.,
+//
+//	# gpu    pwr  gtemp  mtemp     sm    mem ...  pviol  tviol
+//	# Idx      W      C      C      %      % ...      %   bool
+//	    0     70     32     40      0      0 ...      0      0
+func ParseDmon(b []byte) ([]DmonSample, error) {
+	var columns []string
+	var samples []DmonSample
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(strings.TrimPrefix(line, "#"))
+			// the first header line has the column names,
+			// the following one has the units (e.g., "Idx", "W", "C")
+			if len(fields) > 0 && fields[0] != "Idx" {
+				columns = fields
+			}
+			continue
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("dmon data line before the header: %q", line)
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != len(columns) {
+			return nil, fmt.Errorf("dmon data line has %d fields but %d columns: %q", len(fields), len(columns), line)
+		}
+
+		sample := DmonSample{GPU: -1, Values: make(map[string]string, len(columns))}
+		for i, col := range columns {
+			if col == DmonColumnGPU {
+				idx, err := strconv.Atoi(fields[i])
+				if err != nil {
+					return nil, fmt.Errorf("invalid dmon gpu index %q: %w", fields[i], err)
+				}
+				sample.GPU = idx
+				continue
+			}
+			sample.Values[col] = fields[i]
+		}
+		if sample.GPU < 0 {
+			return nil, fmt.Errorf("dmon output has no %q column", DmonColumnGPU)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+const (
+	DmonMarkerError           = "error"
+	DmonMarkerPowerThrottle   = "power_throttle"
+	DmonMarkerThermalThrottle = "thermal_throttle"
+)
+
+// DmonFinding is a marker (e.g., "ERR!" or a throttle) that is observed
+// in the consecutive samples of a GPU.
+type DmonFinding struct {
+	GPU    int    `json:"gpu"`
+	Marker string `json:"marker"`
+	// Samples is the longest number of consecutive samples with the marker.
+	Samples int `json:"samples"`
+}
+
+// FindSustainedDmonMarkers returns the markers that are observed
+// in at least the given number of consecutive samples of the same GPU.
+// The "-" placeholders neither count towards nor reset the throttle streaks.
+// The findings are sorted by the GPU index and the marker.
+func FindSustainedDmonMarkers(samples []DmonSample, sustained int) []DmonFinding {
+	if sustained <= 0 {
+		sustained = 1
+	}
+
+	type key struct {
+		gpu    int
+		marker string
+	}
+	current := make(map[key]int)
+	longest := make(map[key]int)
+	update := func(k key, found bool) {
+		if !found {
+			current[k] = 0
+			return
+		}
+		current[k]++
+		if current[k] > longest[k] {
+			longest[k] = current[k]
+		}
+	}
+
+	for _, s := range samples {
+		hasError := false
+		for _, v := range s.Values {
+			if v == DmonValueError {
+				hasError = true
+				break
+			}
+		}
+		update(key{gpu: s.GPU, marker: DmonMarkerError}, hasError)
+
+		for col, marker := range map[string]string{
+			DmonColumnPowerViolation:   DmonMarkerPowerThrottle,
+			DmonColumnThermalViolation: DmonMarkerThermalThrottle,
+		} {
+			v, ok := s.Values[col]
+			if !ok || v == DmonValueNotAvailable || v == DmonValueError {
+				continue
+			}
+			update(key{gpu: s.GPU, marker: marker}, isDmonViolation(v))
+		}
+	}
+
+	findings := make([]DmonFinding, 0)
+	for k, n := range longest {
+		if n < sustained {
+			continue
+		}
+		findings = append(findings, DmonFinding{GPU: k.gpu, Marker: k.marker, Samples: n})
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].GPU != findings[j].GPU {
+			return findings[i].GPU < findings[j].GPU
+		}
+		return findings[i].Marker < findings[j].Marker
+	})
+	return findings
+}
+
+// isDmonViolation returns true if the violation column value is non-zero
+// (percentage of time for "pviol", boolean for "tviol").
+func isDmonViolation(v string) bool {
+	f, err := strconv.ParseFloat(v, 64)
+	return err == nil && f > 0
+}
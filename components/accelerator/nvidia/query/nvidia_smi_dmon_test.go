@@ -0,0 +1,89 @@
+package query
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseDmon(t *testing.T) {
+	b, err := os.ReadFile("testdata/nvidia-smi-dmon.550.90.07.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := ParseDmon(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 9 {
+		t.Fatalf("expected 9 samples, got %d", len(samples))
+	}
+	if samples[0].GPU != 0 || samples[0].Values["pwr"] != "698" || samples[0].Values[DmonColumnPowerViolation] != "12" {
+		t.Errorf("unexpected first sample %+v", samples[0])
+	}
+	if samples[2].GPU != 2 || samples[2].Values["sm"] != DmonValueError || samples[2].Values["pwr"] != DmonValueNotAvailable {
+		t.Errorf("unexpected third sample %+v", samples[2])
+	}
+	if _, ok := samples[0].Values[DmonColumnGPU]; ok {
+		t.Errorf("expected gpu column to be parsed as index, got %+v", samples[0].Values)
+	}
+
+	tests := []struct {
+		sustained int
+		expected  []DmonFinding
+	}{
+		{
+			sustained: 3,
+			expected: []DmonFinding{
+				{GPU: 0, Marker: DmonMarkerPowerThrottle, Samples: 3},
+				{GPU: 2, Marker: DmonMarkerError, Samples: 3},
+			},
+		},
+		{
+			sustained: 1,
+			expected: []DmonFinding{
+				{GPU: 0, Marker: DmonMarkerPowerThrottle, Samples: 3},
+				{GPU: 1, Marker: DmonMarkerThermalThrottle, Samples: 1},
+				{GPU: 2, Marker: DmonMarkerError, Samples: 3},
+			},
+		},
+		{
+			sustained: 4,
+			expected:  []DmonFinding{},
+		},
+	}
+	for _, tt := range tests {
+		findings := FindSustainedDmonMarkers(samples, tt.sustained)
+		if !reflect.DeepEqual(findings, tt.expected) {
+			t.Errorf("sustained %d: expected %+v, got %+v", tt.sustained, tt.expected, findings)
+		}
+	}
+}
+
+func TestParseDmonErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "data before header",
+			input: "    0     70\n",
+		},
+		{
+			name:  "mismatched fields",
+			input: "# gpu    pwr  gtemp\n# Idx      W      C\n    0     70\n",
+		},
+		{
+			name:  "invalid gpu index",
+			input: "# gpu    pwr\n# Idx      W\n    x     70\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDmon([]byte(tt.input)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
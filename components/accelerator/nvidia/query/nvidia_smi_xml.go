@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DataSourceNVML is the default data source of the nvidia query output.
+	DataSourceNVML = "nvml"
+	// DataSourceSMIXML is the data source of the nvidia query output
+	// when NVML is unavailable and the "nvidia-smi -q -x" output is used as fallback.
+	DataSourceSMIXML = "nvidia-smi-xml"
+)
+
+// GetSMIXMLOutput runs the "nvidia-smi -q -x" command and converts its XML output
+// to the nvidia-smi query output, with the basic device info, temperature, and memory.
+// Make sure to call this with a timeout, as a broken GPU may block the command.
+func GetSMIXMLOutput(ctx context.Context, smiXMLCmds []string) (*SMIOutput, error) {
+	b, err := RunSMI(ctx, smiXMLCmds)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSMIXMLOutput(b)
+}
+
+var ErrNoGPUFoundFromSMIXML = errors.New("no GPU found from nvidia-smi -q -x")
+
+// ParseSMIXMLOutput decodes the "nvidia-smi -q -x" output.
+// Only the basic device info, temperature, and memory are parsed,
+// with the same values as "nvidia-smi --query" (e.g., "81559 MiB", "33 C").
+func ParseSMIXMLOutput(b []byte) (*SMIOutput, error) {
+	raw := rawSMIXMLOutput{}
+	if err := xml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal nvidia-smi xml output: %w", err)
+	}
+
+	o := &SMIOutput{
+		Timestamp:     strings.TrimSpace(raw.Timestamp),
+		DriverVersion: strings.TrimSpace(raw.DriverVersion),
+		CUDAVersion:   strings.TrimSpace(raw.CUDAVersion),
+		Raw:           string(b),
+	}
+	if raw.AttachedGPUs != "" {
+		attached, err := strconv.Atoi(strings.TrimSpace(raw.AttachedGPUs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse attached gpus %q: %w", raw.AttachedGPUs, err)
+		}
+		o.AttachedGPUs = attached
+	}
+
+	for _, g := range raw.GPUs {
+		// same ID as "nvidia-smi --query" (e.g., "GPU 00000000:53:00.0")
+		id := "GPU " + g.ID
+		o.GPUs = append(o.GPUs, NvidiaSMIGPU{
+			ID:                  id,
+			ProductName:         g.ProductName,
+			ProductBrand:        g.ProductBrand,
+			ProductArchitecture: g.ProductArchitecture,
+			PersistenceMode:     g.PersistenceMode,
+			AddressingMode:      g.AddressingMode,
+			FBMemoryUsage: &SMIFBMemoryUsage{
+				ID:       id,
+				Total:    g.FBMemoryUsage.Total,
+				Reserved: g.FBMemoryUsage.Reserved,
+				Used:     g.FBMemoryUsage.Used,
+				Free:     g.FBMemoryUsage.Free,
+			},
+			Temperature: &SMIGPUTemperature{
+				ID:                      id,
+				Current:                 g.Temperature.GPUTemp,
+				Limit:                   g.Temperature.GPUTempTLimit,
+				Shutdown:                g.Temperature.GPUTempMaxThreshold,
+				Slowdown:                g.Temperature.GPUTempSlowThreshold,
+				MaxOperatingLimit:       g.Temperature.GPUTempMaxGPUThreshold,
+				Target:                  g.Temperature.GPUTargetTemperature,
+				MemoryCurrent:           g.Temperature.MemoryTemp,
+				MemoryMaxOperatingLimit: g.Temperature.GPUTempMaxMemThreshold,
+			},
+			FanSpeed: g.FanSpeed,
+		})
+	}
+	if len(o.GPUs) == 0 {
+		return nil, ErrNoGPUFoundFromSMIXML
+	}
+	if o.AttachedGPUs == 0 {
+		o.AttachedGPUs = len(o.GPUs)
+	}
+
+	return o, nil
+}
+
+// ref. "nvidia-smi -q -x" and "nvsmi_device_v12.dtd"
+type rawSMIXMLOutput struct {
+	XMLName       xml.Name       `xml:"nvidia_smi_log"`
+	Timestamp     string         `xml:"timestamp"`
+	DriverVersion string         `xml:"driver_version"`
+	CUDAVersion   string         `xml:"cuda_version"`
+	AttachedGPUs  string         `xml:"attached_gpus"`
+	GPUs          []rawSMIXMLGPU `xml:"gpu"`
+}
+
+type rawSMIXMLGPU struct {
+	// PCI bus ID (e.g., "00000000:53:00.0").
+	ID string `xml:"id,attr"`
+
+	ProductName         string `xml:"product_name"`
+	ProductBrand        string `xml:"product_brand"`
+	ProductArchitecture string `xml:"product_architecture"`
+
+	PersistenceMode string `xml:"persistence_mode"`
+	AddressingMode  string `xml:"addressing_mode"`
+
+	FBMemoryUsage struct {
+		Total    string `xml:"total"`
+		Reserved string `xml:"reserved"`
+		Used     string `xml:"used"`
+		Free     string `xml:"free"`
+	} `xml:"fb_memory_usage"`
+
+	Temperature struct {
+		GPUTemp                string `xml:"gpu_temp"`
+		GPUTempTLimit          string `xml:"gpu_temp_tlimit"`
+		GPUTempMaxThreshold    string `xml:"gpu_temp_max_threshold"`
+		GPUTempSlowThreshold   string `xml:"gpu_temp_slow_threshold"`
+		GPUTempMaxGPUThreshold string `xml:"gpu_temp_max_gpu_threshold"`
+		GPUTargetTemperature   string `xml:"gpu_target_temperature"`
+		MemoryTemp             string `xml:"memory_temp"`
+		GPUTempMaxMemThreshold string `xml:"gpu_temp_max_mem_threshold"`
+	} `xml:"temperature"`
+
+	FanSpeed string `xml:"fan_speed"`
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestParseSMIXMLOutput(t *testing.T) {
+	b, err := os.ReadFile("testdata/nvidia-smi-xml.550.90.07.out.0.valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := ParseSMIXMLOutput(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o.DriverVersion != "550.90.07" || o.CUDAVersion != "12.4" || o.AttachedGPUs != 2 {
+		t.Fatalf("unexpected output %+v", o)
+	}
+	if len(o.GPUs) != 2 {
+		t.Fatalf("expected 2 gpus, got %d", len(o.GPUs))
+	}
+
+	gpu := o.GPUs[1]
+	if gpu.ID != "GPU 00000000:2A:00.0" {
+		t.Fatalf("unexpected id %q", gpu.ID)
+	}
+	if gpu.ProductName != "NVIDIA H100 80GB HBM3" || gpu.ProductBrand != "NVIDIA" || gpu.ProductArchitecture != "Hopper" {
+		t.Fatalf("unexpected product %q %q %q", gpu.ProductName, gpu.ProductBrand, gpu.ProductArchitecture)
+	}
+	if !gpu.GetSMIGPUPersistenceMode().Enabled {
+		t.Fatal("expected persistence mode enabled")
+	}
+
+	mem, err := gpu.FBMemoryUsage.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem.ID != gpu.ID || mem.TotalBytes != 81559*1024*1024 || mem.UsedBytes != 40960*1024*1024 {
+		t.Fatalf("unexpected memory %+v", mem)
+	}
+
+	temp, err := gpu.Temperature.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temp.CurrentHumanized != "41 C" || temp.LimitHumanized != "46 C" {
+		t.Fatalf("unexpected temperature %+v", temp)
+	}
+	if cur, err := gpu.Temperature.GetCurrentCelsius(); err != nil || cur != 41 {
+		t.Fatalf("unexpected current temperature %v (%v)", cur, err)
+	}
+}
+
+func TestParseSMIXMLOutputErrors(t *testing.T) {
+	if _, err := ParseSMIXMLOutput([]byte("not xml")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	_, err := ParseSMIXMLOutput([]byte(`<?xml version="1.0" ?>
+<nvidia_smi_log>
+	<driver_version>550.90.07</driver_version>
+	<attached_gpus>0</attached_gpus>
+</nvidia_smi_log>`))
+	if !errors.Is(err, ErrNoGPUFoundFromSMIXML) {
+		t.Fatalf("expected ErrNoGPUFoundFromSMIXML, got %v", err)
+	}
+}
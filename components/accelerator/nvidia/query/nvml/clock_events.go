@@ -97,6 +97,10 @@ type ClockEvents struct {
 	HWSlowdownThermal bool `json:"hw_thermal_slowdown"`
 	// Set true if the HW Power Brake Slowdown reason due to the external power brake assertion is active.
 	HWSlowdownPowerBrake bool `json:"hw_slowdown_power_brake"`
+	// Set true if the SW Power Cap reason is active (clocks are optimized to not exceed the power limit).
+	SWPowerCap bool `json:"sw_power_cap"`
+	// Set true if the SW Thermal Slowdown reason is active.
+	SWThermalSlowdown bool `json:"sw_thermal_slowdown"`
 
 	// Supported is true if the clock events are supported by the device.
 	Supported bool `json:"supported"`
@@ -144,6 +148,8 @@ func GetClockEvents(uuid string, dev device.Device) (ClockEvents, error) {
 	clockEvents.HWSlowdown = reasons&reasonHWSlowdown != 0
 	clockEvents.HWSlowdownThermal = reasons&reasonHWSlowdownThermal != 0
 	clockEvents.HWSlowdownPowerBrake = reasons&reasonHWSlowdownPowerBrake != 0
+	clockEvents.SWPowerCap = reasons&reasonSWPowerCap != 0
+	clockEvents.SWThermalSlowdown = reasons&reasonSwThermalSlowdown != 0
 
 	hwReasons, otherReasons := getClockEventReasons(reasons)
 	for _, reason := range hwReasons {
@@ -179,6 +185,26 @@ func getClockEventReasons(reasons uint64) ([]string, []string) {
 	return hwSlowdownReasons, otherReasons
 }
 
+// DecodeThrottleReasons decodes the bitmask returned by nvmlDeviceGetCurrentClocksEventReasons
+// (formerly nvmlDeviceGetCurrentClocksThrottleReasons) into human-readable labels,
+// ordered by the bit position. Unknown bits are decoded as "Unknown (0x...)".
+// Returns an empty list if no reason is active.
+func DecodeThrottleReasons(mask uint64) []string {
+	labels := make([]string, 0)
+	for bit := 0; bit < 64; bit++ {
+		flag := uint64(1) << bit
+		if mask&flag == 0 {
+			continue
+		}
+		if rt, ok := clockEventReasonsToInclude[flag]; ok {
+			labels = append(labels, rt.label)
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("Unknown (0x%016x)", flag))
+	}
+	return labels
+}
+
 // 0x0000000000000000 is none
 // ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlClocksEventReasons.html
 const (
@@ -194,6 +220,8 @@ const (
 )
 
 type reasonType struct {
+	// short label as shown in "nvidia-smi --query" (e.g., "SW Power Cap")
+	label        string
 	description  string
 	isHWSlowdown bool
 }
@@ -203,54 +231,63 @@ type reasonType struct {
 var clockEventReasonsToInclude = map[uint64]reasonType{
 	// ref. nvmlClocksEventReasonGpuIdle
 	reasonGPUIdle: {
+		label:        "GPU Idle",
 		description:  "GPU is idle and clocks are dropping to Idle state",
 		isHWSlowdown: false,
 	},
 
 	// ref. nvmlClocksEventReasonApplicationsClocksSetting
 	reasonApplicationsClocksSetting: {
+		label:        "Applications Clocks Setting",
 		description:  "GPU clocks are limited by current setting of applications clocks",
 		isHWSlowdown: false,
 	},
 
 	// ref. nvmlClocksEventReasonSwPowerCap
 	reasonSWPowerCap: {
+		label:        "SW Power Cap",
 		description:  "Clocks have been optimized to not exceed currently set power limits ('SW Power Cap: Active' in nvidia-smi --query)",
 		isHWSlowdown: false,
 	},
 
 	// ref. nvmlClocksThrottleReasonHwSlowdown
 	reasonHWSlowdown: {
+		label:        "HW Slowdown",
 		description:  "HW Slowdown is engaged due to high temperature, power brake assertion, or high power draw ('HW Slowdown: Active' in nvidia-smi --query)",
 		isHWSlowdown: true,
 	},
 
 	// ref. nvmlClocksEventReasonSyncBoost
 	reasonSyncBoost: {
+		label:        "Sync Boost",
 		description:  "GPU is part of a Sync boost group to maximize performance per watt",
 		isHWSlowdown: false,
 	},
 
 	// ref. nvmlClocksEventReasonSwThermalSlowdown
 	reasonSwThermalSlowdown: {
+		label:        "SW Thermal Slowdown",
 		description:  "SW Thermal Slowdown is active to keep GPU and memory temperatures within operating limits",
 		isHWSlowdown: false,
 	},
 
 	// ref. nvmlClocksThrottleReasonHwThermalSlowdown
 	reasonHWSlowdownThermal: {
+		label:        "HW Thermal Slowdown",
 		description:  "HW Thermal Slowdown (reducing the core clocks by a factor of 2 or more) is engaged (temperature being too high) ('HW Thermal Slowdown' in nvidia-smi --query)",
 		isHWSlowdown: true,
 	},
 
 	// ref. nvmlClocksThrottleReasonHwPowerBrakeSlowdown
 	reasonHWSlowdownPowerBrake: {
+		label:        "HW Power Brake Slowdown",
 		description:  "HW Power Brake Slowdown (reducing the core clocks by a factor of 2 or more) is engaged (External Power Brake Assertion being triggered) ('HW Power Brake Slowdown' in nvidia-smi --query)",
 		isHWSlowdown: true,
 	},
 
 	// ref. nvmlClocksEventReasonDisplayClockSetting
 	reasonDisplayClockSetting: {
+		label:        "Display Clock Setting",
 		description:  "GPU clocks are limited by current setting of Display clocks",
 		isHWSlowdown: false,
 	},
@@ -1,6 +1,7 @@
 package nvml
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -170,6 +171,21 @@ func TestGetClockEvents(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "success with power cap and power brake",
+			uuid:        "GPU-EF01",
+			mockReasons: reasonSWPowerCap | reasonHWSlowdownPowerBrake,
+			mockReturn:  nvml.SUCCESS,
+			expectedEvents: ClockEvents{
+				UUID:                 "GPU-EF01",
+				ReasonsBitmask:       reasonSWPowerCap | reasonHWSlowdownPowerBrake,
+				HWSlowdownPowerBrake: true,
+				SWPowerCap:           true,
+				HWSlowdownReasons: []string{
+					"GPU-EF01: HW Power Brake Slowdown (reducing the core clocks by a factor of 2 or more) is engaged (External Power Brake Assertion being triggered) ('HW Power Brake Slowdown' in nvidia-smi --query) (nvml)",
+				},
+			},
+		},
 		{
 			name:          "nvml error",
 			uuid:          "GPU-ERROR",
@@ -216,6 +232,14 @@ func TestGetClockEvents(t *testing.T) {
 				t.Errorf("HWSlowdownThermal mismatch: got %v, want %v",
 					events.HWSlowdownThermal, tc.expectedEvents.HWSlowdownThermal)
 			}
+			if events.HWSlowdownPowerBrake != tc.expectedEvents.HWSlowdownPowerBrake {
+				t.Errorf("HWSlowdownPowerBrake mismatch: got %v, want %v",
+					events.HWSlowdownPowerBrake, tc.expectedEvents.HWSlowdownPowerBrake)
+			}
+			if events.SWPowerCap != tc.expectedEvents.SWPowerCap {
+				t.Errorf("SWPowerCap mismatch: got %v, want %v",
+					events.SWPowerCap, tc.expectedEvents.SWPowerCap)
+			}
 
 			if len(events.HWSlowdownReasons) != len(tc.expectedEvents.HWSlowdownReasons) {
 				t.Errorf("HWSlowdownReasons length mismatch: got %d, want %d",
@@ -231,3 +255,45 @@ func TestGetClockEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeThrottleReasons(t *testing.T) {
+	tests := []struct {
+		name string
+		mask uint64
+		want []string
+	}{
+		{name: "none", mask: 0, want: []string{}},
+		{name: "gpu idle", mask: reasonGPUIdle, want: []string{"GPU Idle"}},
+		{name: "applications clocks", mask: reasonApplicationsClocksSetting, want: []string{"Applications Clocks Setting"}},
+		{name: "sw power cap", mask: reasonSWPowerCap, want: []string{"SW Power Cap"}},
+		{name: "hw slowdown", mask: reasonHWSlowdown, want: []string{"HW Slowdown"}},
+		{name: "sync boost", mask: reasonSyncBoost, want: []string{"Sync Boost"}},
+		{name: "sw thermal slowdown", mask: reasonSwThermalSlowdown, want: []string{"SW Thermal Slowdown"}},
+		{name: "hw thermal slowdown", mask: reasonHWSlowdownThermal, want: []string{"HW Thermal Slowdown"}},
+		{name: "hw power brake", mask: reasonHWSlowdownPowerBrake, want: []string{"HW Power Brake Slowdown"}},
+		{name: "display clocks", mask: reasonDisplayClockSetting, want: []string{"Display Clock Setting"}},
+		{
+			name: "hw slowdown with thermal and power brake",
+			mask: reasonHWSlowdown | reasonHWSlowdownThermal | reasonHWSlowdownPowerBrake,
+			want: []string{"HW Slowdown", "HW Thermal Slowdown", "HW Power Brake Slowdown"},
+		},
+		{
+			name: "idle with power cap",
+			mask: reasonSWPowerCap | reasonGPUIdle,
+			want: []string{"GPU Idle", "SW Power Cap"},
+		},
+		{
+			name: "unknown bit",
+			mask: reasonSyncBoost | 0x0000000000001000,
+			want: []string{"Sync Boost", "Unknown (0x0000000000001000)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeThrottleReasons(tt.mask)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeThrottleReasons(0x%x) = %v, want %v", tt.mask, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,18 +8,31 @@ import (
 )
 
 // ClockSpeed represents the data from the nvmlDeviceGetClockInfo API.
-// Returns the graphics and memory clock speeds in MHz.
+// Returns the graphics, SM, and memory clock speeds in MHz.
 // ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html#group__nvmlDeviceQueries_1g2efc4dd4096173f01d80b2a8bbfd97ad
 type ClockSpeed struct {
 	// Represents the GPU UUID.
 	UUID string `json:"uuid"`
 
 	GraphicsMHz uint32 `json:"graphics_mhz"`
+	SMMHz       uint32 `json:"sm_mhz"`
 	MemoryMHz   uint32 `json:"memory_mhz"`
 
+	// Max clock speeds from the nvmlDeviceGetMaxClockInfo API.
+	// A current clock speed below its max while the clock events
+	// report active reasons (see ClockEvents) means the GPU is being
+	// throttled, rather than simply being idle.
+	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+	GraphicsMaxMHz uint32 `json:"graphics_max_mhz"`
+	SMMaxMHz       uint32 `json:"sm_max_mhz"`
+	MemoryMaxMHz   uint32 `json:"memory_max_mhz"`
+
 	// ClockGraphicsSupported is true if the clock speed is supported by the device.
 	ClockGraphicsSupported bool `json:"clock_graphics_supported"`
 
+	// ClockSMSupported is true if the SM clock speed is supported by the device.
+	ClockSMSupported bool `json:"clock_sm_supported"`
+
 	// ClockMemorySupported is true if the clock speed is supported by the device.
 	ClockMemorySupported bool `json:"clock_memory_supported"`
 }
@@ -29,25 +42,45 @@ func GetClockSpeed(uuid string, dev device.Device) (ClockSpeed, error) {
 		UUID: uuid,
 	}
 
+	var err error
+	clockSpeed.GraphicsMHz, clockSpeed.GraphicsMaxMHz, clockSpeed.ClockGraphicsSupported, err = getClockInfo(dev, nvml.CLOCK_GRAPHICS, "nvml.CLOCK_GRAPHICS")
+	if err != nil {
+		return clockSpeed, err
+	}
+
+	clockSpeed.SMMHz, clockSpeed.SMMaxMHz, clockSpeed.ClockSMSupported, err = getClockInfo(dev, nvml.CLOCK_SM, "nvml.CLOCK_SM")
+	if err != nil {
+		return clockSpeed, err
+	}
+
+	clockSpeed.MemoryMHz, clockSpeed.MemoryMaxMHz, clockSpeed.ClockMemorySupported, err = getClockInfo(dev, nvml.CLOCK_MEM, "nvml.CLOCK_MEM")
+	if err != nil {
+		return clockSpeed, err
+	}
+
+	return clockSpeed, nil
+}
+
+// getClockInfo returns the current and max clock speeds in MHz for the clock type.
+// The max clock speed is left zero if the device does not report it.
+func getClockInfo(dev device.Device, clockType nvml.ClockType, clockName string) (uint32, uint32, bool, error) {
 	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html#group__nvmlDeviceQueries_1g2efc4dd4096173f01d80b2a8bbfd97ad
-	graphicsClock, ret := dev.GetClockInfo(nvml.CLOCK_GRAPHICS)
+	cur, ret := dev.GetClockInfo(clockType)
 	if IsNotSupportError(ret) {
-		clockSpeed.ClockGraphicsSupported = false
-	} else if ret != nvml.SUCCESS { // not a "not supported" error, not a success return, thus return an error here
-		return clockSpeed, fmt.Errorf("failed to get device clock info for nvml.CLOCK_GRAPHICS: %v", nvml.ErrorString(ret))
+		return 0, 0, false, nil
+	}
+	if ret != nvml.SUCCESS { // not a "not supported" error, not a success return, thus return an error here
+		return 0, 0, false, fmt.Errorf("failed to get device clock info for %s: %v", clockName, nvml.ErrorString(ret))
 	}
-	clockSpeed.ClockGraphicsSupported = true
-	clockSpeed.GraphicsMHz = graphicsClock
 
-	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html#group__nvmlDeviceQueries_1g2efc4dd4096173f01d80b2a8bbfd97ad
-	memClock, ret := dev.GetClockInfo(nvml.CLOCK_MEM)
+	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+	maxClock, ret := dev.GetMaxClockInfo(clockType)
 	if IsNotSupportError(ret) {
-		clockSpeed.ClockMemorySupported = false
-	} else if ret != nvml.SUCCESS { // not a "not supported" error, not a success return, thus return an error here
-		return clockSpeed, fmt.Errorf("failed to get device clock info for nvml.CLOCK_MEM: %v", nvml.ErrorString(ret))
+		return cur, 0, true, nil
+	}
+	if ret != nvml.SUCCESS {
+		return cur, 0, true, fmt.Errorf("failed to get device max clock info for %s: %v", clockName, nvml.ErrorString(ret))
 	}
-	clockSpeed.ClockMemorySupported = true
-	clockSpeed.MemoryMHz = memClock
 
-	return clockSpeed, nil
+	return cur, maxClock, true, nil
 }
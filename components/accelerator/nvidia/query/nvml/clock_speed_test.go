@@ -0,0 +1,80 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetClockSpeed(t *testing.T) {
+	cur := map[nvml.ClockType]uint32{nvml.CLOCK_GRAPHICS: 1200, nvml.CLOCK_SM: 1100, nvml.CLOCK_MEM: 2600}
+	maxClocks := map[nvml.ClockType]uint32{nvml.CLOCK_GRAPHICS: 1980, nvml.CLOCK_SM: 1980, nvml.CLOCK_MEM: 2619}
+
+	dev := testutil.CreateDevice(&mock.Device{
+		GetClockInfoFunc: func(ct nvml.ClockType) (uint32, nvml.Return) {
+			return cur[ct], nvml.SUCCESS
+		},
+		GetMaxClockInfoFunc: func(ct nvml.ClockType) (uint32, nvml.Return) {
+			return maxClocks[ct], nvml.SUCCESS
+		},
+	})
+
+	clockSpeed, err := GetClockSpeed("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ClockSpeed{
+		UUID:                   "GPU-0",
+		GraphicsMHz:            1200,
+		SMMHz:                  1100,
+		MemoryMHz:              2600,
+		GraphicsMaxMHz:         1980,
+		SMMaxMHz:               1980,
+		MemoryMaxMHz:           2619,
+		ClockGraphicsSupported: true,
+		ClockSMSupported:       true,
+		ClockMemorySupported:   true,
+	}
+	if clockSpeed != expected {
+		t.Errorf("expected %+v, got %+v", expected, clockSpeed)
+	}
+}
+
+func TestGetClockSpeedNotSupported(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetClockInfoFunc: func(ct nvml.ClockType) (uint32, nvml.Return) {
+			if ct == nvml.CLOCK_SM {
+				return 0, nvml.ERROR_NOT_SUPPORTED
+			}
+			return 1000, nvml.SUCCESS
+		},
+		GetMaxClockInfoFunc: func(ct nvml.ClockType) (uint32, nvml.Return) {
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+	})
+
+	clockSpeed, err := GetClockSpeed("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clockSpeed.ClockSMSupported || clockSpeed.SMMHz != 0 {
+		t.Errorf("expected SM clock to be unsupported, got %+v", clockSpeed)
+	}
+	if !clockSpeed.ClockGraphicsSupported || clockSpeed.GraphicsMHz != 1000 || clockSpeed.GraphicsMaxMHz != 0 {
+		t.Errorf("expected graphics clock without max, got %+v", clockSpeed)
+	}
+}
+
+func TestGetClockSpeedError(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetClockInfoFunc: func(ct nvml.ClockType) (uint32, nvml.Return) {
+			return 0, nvml.ERROR_UNKNOWN
+		},
+	})
+	if _, err := GetClockSpeed("GPU-0", dev); err == nil {
+		t.Fatal("expected error")
+	}
+}
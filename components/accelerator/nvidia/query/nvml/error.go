@@ -1,8 +1,12 @@
 package nvml
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
+	"github.com/leptonai/gpud/errdefs"
+
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 )
 
@@ -14,3 +18,16 @@ func IsNotSupportError(ret nvml.Return) bool {
 	e := nvml.ErrorString(ret)
 	return strings.Contains(strings.ToLower(strings.TrimSpace(e)), "not supported")
 }
+
+// ErrDriverLibraryMismatch is returned when the loaded NVIDIA kernel driver
+// does not match the NVML library version (e.g., driver upgraded without reboot).
+// It does not resolve until the host is rebooted, so the callers should not retry.
+var ErrDriverLibraryMismatch = errors.New("driver/library version mismatch, reboot required")
+
+// newInitError returns the error for the failed NVML initialization.
+func newInitError(ret nvml.Return) error {
+	if ret == nvml.ERROR_LIB_RM_VERSION_MISMATCH {
+		return fmt.Errorf("failed to initialize NVML: %v (%w)", nvml.ErrorString(ret), ErrDriverLibraryMismatch)
+	}
+	return fmt.Errorf("failed to initialize NVML: %v (%w)", nvml.ErrorString(ret), errdefs.ErrUnavailable)
+}
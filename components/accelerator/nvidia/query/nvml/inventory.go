@@ -0,0 +1,53 @@
+package nvml
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// Inventory is the static board info of the GPU, for the asset tracking
+// and the hardware inspection (RMA) tickets.
+type Inventory struct {
+	UUID string `json:"uuid"`
+	// Serial is the board serial number, empty if not supported by the device
+	// (e.g., non-datacenter GPUs).
+	Serial string `json:"serial"`
+	// BoardPartNumber is the board part number, empty if not supported by the device.
+	BoardPartNumber string `json:"board_part_number"`
+	// VBIOSVersion is the VBIOS version, empty if not supported by the device.
+	VBIOSVersion string `json:"vbios_version"`
+}
+
+// GetInventory returns the serial number, the board part number, and the VBIOS version of the GPU.
+// The fields not supported by the device are left empty without an error.
+// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+func GetInventory(uuid string, dev device.Device) (Inventory, error) {
+	inv := Inventory{
+		UUID: uuid,
+	}
+
+	serial, ret := dev.GetSerial()
+	if ret == nvml.SUCCESS {
+		inv.Serial = serial
+	} else if !IsNotSupportError(ret) {
+		return inv, fmt.Errorf("failed to get device serial: %v", nvml.ErrorString(ret))
+	}
+
+	partNumber, ret := dev.GetBoardPartNumber()
+	if ret == nvml.SUCCESS {
+		inv.BoardPartNumber = partNumber
+	} else if !IsNotSupportError(ret) {
+		return inv, fmt.Errorf("failed to get device board part number: %v", nvml.ErrorString(ret))
+	}
+
+	vbios, ret := dev.GetVbiosVersion()
+	if ret == nvml.SUCCESS {
+		inv.VBIOSVersion = vbios
+	} else if !IsNotSupportError(ret) {
+		return inv, fmt.Errorf("failed to get device vbios version: %v", nvml.ErrorString(ret))
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,75 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetInventory(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetSerialFunc: func() (string, nvml.Return) {
+			return "1654922012345", nvml.SUCCESS
+		},
+		GetBoardPartNumberFunc: func() (string, nvml.Return) {
+			return "692-2G520-0200-000", nvml.SUCCESS
+		},
+		GetVbiosVersionFunc: func() (string, nvml.Return) {
+			return "96.00.89.00.01", nvml.SUCCESS
+		},
+	})
+
+	inv, err := GetInventory("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Inventory{
+		UUID:            "gpu-0",
+		Serial:          "1654922012345",
+		BoardPartNumber: "692-2G520-0200-000",
+		VBIOSVersion:    "96.00.89.00.01",
+	}
+	if inv != expected {
+		t.Errorf("expected %+v, got %+v", expected, inv)
+	}
+}
+
+func TestGetInventorySerialNotSupported(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetSerialFunc: func() (string, nvml.Return) {
+			return "", nvml.ERROR_NOT_SUPPORTED
+		},
+		GetBoardPartNumberFunc: func() (string, nvml.Return) {
+			return "", nvml.ERROR_NOT_SUPPORTED
+		},
+		GetVbiosVersionFunc: func() (string, nvml.Return) {
+			return "96.00.89.00.01", nvml.SUCCESS
+		},
+	})
+
+	inv, err := GetInventory("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Serial != "" || inv.BoardPartNumber != "" {
+		t.Errorf("expected empty serial and board part number, got %+v", inv)
+	}
+	if inv.VBIOSVersion != "96.00.89.00.01" {
+		t.Errorf("expected vbios version, got %q", inv.VBIOSVersion)
+	}
+}
+
+func TestGetInventoryError(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetSerialFunc: func() (string, nvml.Return) {
+			return "", nvml.ERROR_UNKNOWN
+		},
+	})
+
+	if _, err := GetInventory("gpu-0", dev); err == nil {
+		t.Fatal("expected error")
+	}
+}
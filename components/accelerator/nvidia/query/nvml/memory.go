@@ -29,6 +29,26 @@ type Memory struct {
 
 	// Supported is true if the memory is supported by the device.
 	Supported bool `json:"supported"`
+
+	// Per-instance memory of the MIG-enabled device (see GetMIGMemory).
+	// Empty if MIG is disabled or no MIG instance is created.
+	MIGInstances []MIGMemory `json:"mig_instances,omitempty"`
+}
+
+// MIGMemory represents the memory of a MIG (Multi-Instance GPU) instance.
+type MIGMemory struct {
+	// Represents the MIG device UUID.
+	UUID string `json:"uuid"`
+
+	TotalBytes uint64 `json:"total_bytes"`
+	UsedBytes  uint64 `json:"used_bytes"`
+	FreeBytes  uint64 `json:"free_bytes"`
+
+	UsedPercent string `json:"used_percent"`
+}
+
+func (mem MIGMemory) GetUsedPercent() (float64, error) {
+	return strconv.ParseFloat(mem.UsedPercent, 64)
 }
 
 func (mem Memory) GetUsedPercent() (float64, error) {
@@ -71,11 +91,41 @@ func GetMemory(uuid string, dev device.Device) (Memory, error) {
 	mem.FreeHumanized = humanize.Bytes(mem.FreeBytes)
 	mem.UsedHumanized = humanize.Bytes(mem.UsedBytes)
 
-	if mem.TotalBytes > 0 {
-		mem.UsedPercent = fmt.Sprintf("%.2f", float64(mem.UsedBytes)/float64(mem.TotalBytes)*100)
-	} else {
-		mem.UsedPercent = "0.0"
-	}
+	mem.UsedPercent = usedPercent(mem.UsedBytes, mem.TotalBytes)
 
 	return mem, nil
 }
+
+// GetMIGMemory returns the memory of each MIG instance,
+// given the MIG devices of the MIG-enabled device (see getMIGMode).
+func GetMIGMemory(migDevs []device.MigDevice) ([]MIGMemory, error) {
+	var migs []MIGMemory
+	for _, migDev := range migDevs {
+		uuid, ret := migDev.GetUUID()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get MIG device uuid: %v", nvml.ErrorString(ret))
+		}
+
+		// ref. https://docs.nvidia.com/deploy/nvml-api/structnvmlMemory__t.html
+		info, ret := migDev.GetMemoryInfo()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get MIG device %s memory info: %v", uuid, nvml.ErrorString(ret))
+		}
+
+		migs = append(migs, MIGMemory{
+			UUID:        uuid,
+			TotalBytes:  info.Total,
+			UsedBytes:   info.Used,
+			FreeBytes:   info.Free,
+			UsedPercent: usedPercent(info.Used, info.Total),
+		})
+	}
+	return migs, nil
+}
+
+func usedPercent(used uint64, total uint64) string {
+	if total == 0 {
+		return "0.0"
+	}
+	return fmt.Sprintf("%.2f", float64(used)/float64(total)*100)
+}
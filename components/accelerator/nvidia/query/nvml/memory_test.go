@@ -0,0 +1,107 @@
+package nvml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func TestGetMemory(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetMemoryInfo_v2Func: func() (nvml.Memory_v2, nvml.Return) {
+			return nvml.Memory_v2{Total: 80 * gib, Reserved: 1 * gib, Used: 20 * gib, Free: 59 * gib}, nvml.SUCCESS
+		},
+	})
+
+	mem, err := GetMemory("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("GetMemory() error = %v", err)
+	}
+	if !mem.Supported || mem.TotalBytes != 80*gib || mem.ReservedBytes != 1*gib || mem.UsedBytes != 20*gib || mem.FreeBytes != 59*gib {
+		t.Fatalf("unexpected memory %+v", mem)
+	}
+	if mem.UsedPercent != "25.00" {
+		t.Fatalf("expected used percent 25.00, got %q", mem.UsedPercent)
+	}
+	if len(mem.MIGInstances) != 0 {
+		t.Fatalf("expected no MIG instances, got %+v", mem.MIGInstances)
+	}
+}
+
+func TestGetMemoryMIG(t *testing.T) {
+	mig := func(uuid string, total, used uint64) device.MigDevice {
+		return testutil.CreateMIGInstance(&mock.Device{
+			GetUUIDFunc: func() (string, nvml.Return) {
+				return uuid, nvml.SUCCESS
+			},
+			GetMemoryInfoFunc: func() (nvml.Memory, nvml.Return) {
+				return nvml.Memory{Total: total, Used: used, Free: total - used}, nvml.SUCCESS
+			},
+		}, "")
+	}
+	dev := testutil.CreateMIGDevice(
+		&mock.Device{
+			GetMigModeFunc: func() (int, int, nvml.Return) {
+				return nvml.DEVICE_MIG_ENABLE, nvml.DEVICE_MIG_ENABLE, nvml.SUCCESS
+			},
+			GetMemoryInfo_v2Func: func() (nvml.Memory_v2, nvml.Return) {
+				return nvml.Memory_v2{}, nvml.ERROR_NOT_SUPPORTED
+			},
+			GetMemoryInfoFunc: func() (nvml.Memory, nvml.Return) {
+				return nvml.Memory{Total: 80 * gib, Used: 30 * gib, Free: 50 * gib}, nvml.SUCCESS
+			},
+		},
+		mig("MIG-0", 40*gib, 10*gib),
+		mig("MIG-1", 20*gib, 20*gib),
+		mig("MIG-2", 20*gib, 0),
+	)
+
+	mem, err := GetMemory("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("GetMemory() error = %v", err)
+	}
+	if mem.TotalBytes != 80*gib || mem.UsedBytes != 30*gib || mem.UsedPercent != "37.50" {
+		t.Fatalf("unexpected memory %+v", mem)
+	}
+
+	_, migDevs, err := getMIGMode("gpu-0", dev)
+	if err != nil {
+		t.Fatalf("getMIGMode() error = %v", err)
+	}
+	mem.MIGInstances, err = GetMIGMemory(migDevs)
+	if err != nil {
+		t.Fatalf("GetMIGMemory() error = %v", err)
+	}
+
+	expected := []MIGMemory{
+		{UUID: "MIG-0", TotalBytes: 40 * gib, UsedBytes: 10 * gib, FreeBytes: 30 * gib, UsedPercent: "25.00"},
+		{UUID: "MIG-1", TotalBytes: 20 * gib, UsedBytes: 20 * gib, FreeBytes: 0, UsedPercent: "100.00"},
+		{UUID: "MIG-2", TotalBytes: 20 * gib, UsedBytes: 0, FreeBytes: 20 * gib, UsedPercent: "0.00"},
+	}
+	if !reflect.DeepEqual(mem.MIGInstances, expected) {
+		t.Fatalf("expected MIG instances %+v, got %+v", expected, mem.MIGInstances)
+	}
+}
+
+func TestGetMIGMemoryError(t *testing.T) {
+	migDevs := []device.MigDevice{
+		testutil.CreateMIGInstance(&mock.Device{
+			GetUUIDFunc: func() (string, nvml.Return) {
+				return "MIG-0", nvml.SUCCESS
+			},
+			GetMemoryInfoFunc: func() (nvml.Memory, nvml.Return) {
+				return nvml.Memory{}, nvml.ERROR_UNKNOWN
+			},
+		}, ""),
+	}
+	if _, err := GetMIGMemory(migDevs); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,91 @@
+package nvml
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// MIGMode is the MIG (Multi-Instance GPU) mode of the device.
+// When enabled, the GPU is partitioned into the GPU instances,
+// each presented as a separate device with its own UUID.
+// ref. https://docs.nvidia.com/datacenter/tesla/mig-user-guide/index.html
+//
+// The MIG mode also changes the handling of the uncontained ECC errors (Xid 95),
+// where only the affected GPU needs the reset when MIG is enabled.
+// ref. https://docs.nvidia.com/deploy/gpu-debug-guidelines/index.html#xid-messages
+type MIGMode struct {
+	UUID    string `json:"uuid"`
+	Enabled bool   `json:"enabled"`
+	// Set true if the MIG mode change is pending,
+	// which requires the GPU reset (or the reboot) to take effect.
+	Pending bool `json:"pending"`
+
+	// GPU instances of the MIG-enabled device.
+	Instances []MIGInstance `json:"instances,omitempty"`
+
+	// Supported is true if the MIG mode is supported by the device.
+	Supported bool `json:"supported"`
+}
+
+// MIGInstance represents a GPU instance of the MIG-enabled device.
+type MIGInstance struct {
+	// Represents the MIG device UUID.
+	UUID string `json:"uuid"`
+	// Profile is the MIG profile of the instance (e.g., "3g.20gb").
+	Profile string `json:"profile"`
+}
+
+func GetMIGMode(uuid string, dev device.Device) (MIGMode, error) {
+	mode, _, err := getMIGMode(uuid, dev)
+	return mode, err
+}
+
+// getMIGMode also returns the MIG devices of the instances, so that the instances
+// are queried (e.g., GetMIGMemory) without enumerating the MIG devices again.
+func getMIGMode(uuid string, dev device.Device) (MIGMode, []device.MigDevice, error) {
+	mode := MIGMode{
+		UUID:      uuid,
+		Supported: true,
+	}
+
+	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlMultiInstanceGPU.html
+	current, pending, ret := dev.GetMigMode()
+	if IsNotSupportError(ret) {
+		mode.Supported = false
+		return mode, nil, nil
+	}
+
+	// not a "not supported" error, not a success return, thus return an error here
+	if ret != nvml.SUCCESS {
+		return mode, nil, fmt.Errorf("failed to get device MIG mode: %v", nvml.ErrorString(ret))
+	}
+	mode.Enabled = current == nvml.DEVICE_MIG_ENABLE
+	mode.Pending = pending != current
+
+	if !mode.Enabled {
+		return mode, nil, nil
+	}
+
+	migDevs, err := dev.GetMigDevices()
+	if err != nil {
+		return mode, nil, fmt.Errorf("failed to get MIG devices: %w", err)
+	}
+	for _, migDev := range migDevs {
+		migUUID, ret := migDev.GetUUID()
+		if ret != nvml.SUCCESS {
+			return mode, nil, fmt.Errorf("failed to get MIG device uuid: %v", nvml.ErrorString(ret))
+		}
+		profile, err := migDev.GetProfile()
+		if err != nil {
+			return mode, nil, fmt.Errorf("failed to get MIG device %s profile: %w", migUUID, err)
+		}
+		mode.Instances = append(mode.Instances, MIGInstance{
+			UUID:    migUUID,
+			Profile: profile.String(),
+		})
+	}
+
+	return mode, migDevs, nil
+}
@@ -0,0 +1,97 @@
+package nvml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetMIGMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int
+		pending  int
+		ret      nvml.Return
+		migs     []MIGInstance
+		expected MIGMode
+		wantErr  bool
+	}{
+		{
+			name:     "MIG disabled",
+			current:  nvml.DEVICE_MIG_DISABLE,
+			pending:  nvml.DEVICE_MIG_DISABLE,
+			ret:      nvml.SUCCESS,
+			expected: MIGMode{UUID: "gpu-0", Supported: true},
+		},
+		{
+			name:     "MIG enable pending",
+			current:  nvml.DEVICE_MIG_DISABLE,
+			pending:  nvml.DEVICE_MIG_ENABLE,
+			ret:      nvml.SUCCESS,
+			expected: MIGMode{UUID: "gpu-0", Pending: true, Supported: true},
+		},
+		{
+			name:    "MIG enabled with two instances",
+			current: nvml.DEVICE_MIG_ENABLE,
+			pending: nvml.DEVICE_MIG_ENABLE,
+			ret:     nvml.SUCCESS,
+			migs: []MIGInstance{
+				{UUID: "MIG-0", Profile: "4g.40gb"},
+				{UUID: "MIG-1", Profile: "3g.40gb"},
+			},
+			expected: MIGMode{
+				UUID:    "gpu-0",
+				Enabled: true,
+				Instances: []MIGInstance{
+					{UUID: "MIG-0", Profile: "4g.40gb"},
+					{UUID: "MIG-1", Profile: "3g.40gb"},
+				},
+				Supported: true,
+			},
+		},
+		{
+			name:     "not supported",
+			ret:      nvml.ERROR_NOT_SUPPORTED,
+			expected: MIGMode{UUID: "gpu-0", Supported: false},
+		},
+		{
+			name:    "unknown error",
+			ret:     nvml.ERROR_UNKNOWN,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.Device{
+				GetMigModeFunc: func() (int, int, nvml.Return) {
+					return tt.current, tt.pending, tt.ret
+				},
+			}
+			var migs []device.MigDevice
+			for _, mig := range tt.migs {
+				migs = append(migs, testutil.CreateMIGInstance(&mock.Device{
+					GetUUIDFunc: func() (string, nvml.Return) {
+						return mig.UUID, nvml.SUCCESS
+					},
+				}, mig.Profile))
+			}
+			dev := testutil.CreateMIGDevice(m, migs...)
+
+			mode, err := GetMIGMode("gpu-0", dev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMIGMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(mode, tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, mode)
+			}
+		})
+	}
+}
@@ -55,6 +55,14 @@ func (s NVLinkStates) TotalCRCErrors() uint64 {
 	return total
 }
 
+func (s NVLinkStates) TotalCRCDataErrors() uint64 {
+	var total uint64
+	for _, state := range s {
+		total += state.CRCDataErrors
+	}
+	return total
+}
+
 func (s NVLinkStates) TotalThroughputRawTxBytes() uint64 {
 	var total uint64
 	for _, state := range s {
@@ -81,8 +89,10 @@ type NVLinkState struct {
 	ReplayErrors uint64 `json:"replay_errors"`
 	// RecoveryErrors is the number of recovery errors.
 	RecoveryErrors uint64 `json:"recovery_errors"`
-	// CRCErrors is the number of crc errors.
+	// CRCErrors is the number of crc flit errors.
 	CRCErrors uint64 `json:"crc_errors"`
+	// CRCDataErrors is the number of crc data errors.
+	CRCDataErrors uint64 `json:"crc_data_errors"`
 
 	// ThroughputRawTxBytes is the NVLink TX Data throughput + protocol overhead in bytes.
 	ThroughputRawTxBytes uint64 `json:"throughput_raw_tx_bytes"`
@@ -119,21 +129,19 @@ func GetNVLink(uuid string, dev device.Device) (NVLink, error) {
 		// e.g.,
 		// nvidia-smi nvlink -e
 		// ref. https://docs.nvidia.com/deploy/nvml-api/group__NvLink.html#group__NvLink_1gba53d5dbe3b6b25418964d77f6ff2337
-		replayErrors, ret := nvml.DeviceGetNvLinkErrorCounter(dev, link, nvml.NVLINK_ERROR_DL_REPLAY)
-		if ret == nvml.SUCCESS {
-			nvlinkState.ReplayErrors = replayErrors
-		}
-
-		// ref. https://docs.nvidia.com/deploy/nvml-api/group__NvLink.html#group__NvLink_1gba53d5dbe3b6b25418964d77f6ff2337
-		recoveryErrors, ret := nvml.DeviceGetNvLinkErrorCounter(dev, link, nvml.NVLINK_ERROR_DL_RECOVERY)
-		if ret == nvml.SUCCESS {
-			nvlinkState.RecoveryErrors = recoveryErrors
-		}
-
-		// ref. https://docs.nvidia.com/deploy/nvml-api/group__NvLink.html#group__NvLink_1gba53d5dbe3b6b25418964d77f6ff2337
-		crcErrors, ret := nvml.DeviceGetNvLinkErrorCounter(dev, link, nvml.NVLINK_ERROR_DL_CRC_FLIT)
-		if ret == nvml.SUCCESS {
-			nvlinkState.CRCErrors = crcErrors
+		for _, c := range []struct {
+			dst     *uint64
+			counter nvml.NvLinkErrorCounter
+		}{
+			{dst: &nvlinkState.ReplayErrors, counter: nvml.NVLINK_ERROR_DL_REPLAY},
+			{dst: &nvlinkState.RecoveryErrors, counter: nvml.NVLINK_ERROR_DL_RECOVERY},
+			{dst: &nvlinkState.CRCErrors, counter: nvml.NVLINK_ERROR_DL_CRC_FLIT},
+			{dst: &nvlinkState.CRCDataErrors, counter: nvml.NVLINK_ERROR_DL_CRC_DATA},
+		} {
+			cnt, ret := nvml.DeviceGetNvLinkErrorCounter(dev, link, c.counter)
+			if ret == nvml.SUCCESS {
+				*c.dst = cnt
+			}
 		}
 
 		// use nvmlDeviceGetFieldValues
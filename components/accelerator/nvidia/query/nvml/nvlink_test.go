@@ -0,0 +1,82 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetNVLinkErrorCounters(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetNvLinkStateFunc: func(link int) (nvml.EnableState, nvml.Return) {
+			if link >= 2 {
+				return nvml.FEATURE_DISABLED, nvml.ERROR_INVALID_ARGUMENT
+			}
+			return nvml.FEATURE_ENABLED, nvml.SUCCESS
+		},
+		GetNvLinkErrorCounterFunc: func(link int, counter nvml.NvLinkErrorCounter) (uint64, nvml.Return) {
+			// e.g., link 1 crc data is 1*10+3 = 13
+			switch counter {
+			case nvml.NVLINK_ERROR_DL_REPLAY, nvml.NVLINK_ERROR_DL_RECOVERY, nvml.NVLINK_ERROR_DL_CRC_FLIT, nvml.NVLINK_ERROR_DL_CRC_DATA:
+				return uint64(link*10) + uint64(counter), nvml.SUCCESS
+			}
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetFieldValuesFunc: func(values []nvml.FieldValue) nvml.Return {
+			return nvml.SUCCESS
+		},
+	})
+
+	nvlink, err := GetNVLink("gpu-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nvlink.Supported {
+		t.Fatal("expected nvlink supported")
+	}
+
+	expected := NVLinkStates{
+		{Link: 0, FeatureEnabled: true, ReplayErrors: 0, RecoveryErrors: 1, CRCErrors: 2, CRCDataErrors: 3},
+		{Link: 1, FeatureEnabled: true, ReplayErrors: 10, RecoveryErrors: 11, CRCErrors: 12, CRCDataErrors: 13},
+	}
+	if len(nvlink.States) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, nvlink.States)
+	}
+	for i := range expected {
+		if nvlink.States[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], nvlink.States[i])
+		}
+	}
+
+	if v := nvlink.States.TotalRelayErrors(); v != 10 {
+		t.Errorf("expected 10 replay errors, got %d", v)
+	}
+	if v := nvlink.States.TotalRecoveryErrors(); v != 12 {
+		t.Errorf("expected 12 recovery errors, got %d", v)
+	}
+	if v := nvlink.States.TotalCRCErrors(); v != 14 {
+		t.Errorf("expected 14 crc errors, got %d", v)
+	}
+	if v := nvlink.States.TotalCRCDataErrors(); v != 16 {
+		t.Errorf("expected 16 crc data errors, got %d", v)
+	}
+}
+
+func TestGetNVLinkNotSupported(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetNvLinkStateFunc: func(link int) (nvml.EnableState, nvml.Return) {
+			return nvml.FEATURE_DISABLED, nvml.ERROR_NOT_SUPPORTED
+		},
+	})
+
+	nvlink, err := GetNVLink("gpu-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nvlink.Supported || len(nvlink.States) != 0 {
+		t.Errorf("expected nvlink not supported, got %+v", nvlink)
+	}
+}
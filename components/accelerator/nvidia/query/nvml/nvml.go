@@ -20,6 +20,7 @@ import (
 	nvidia_xid_sxid_state "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid-sxid-state"
 	mocknvml "github.com/leptonai/gpud/e2e/mock/nvml"
 	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/pci"
 )
 
 func NewNVML() nvml.Interface {
@@ -41,9 +42,11 @@ func NewNVInfo(nvmlLib nvml.Interface, deviceLib device.Interface) nvinfo.Interf
 }
 
 type Output struct {
-	Exists      bool          `json:"exists"`
-	Message     string        `json:"message"`
-	DeviceInfos []*DeviceInfo `json:"device_infos"`
+	Exists        bool          `json:"exists"`
+	Message       string        `json:"message"`
+	DriverVersion string        `json:"driver_version"`
+	NVMLVersion   string        `json:"nvml_version"`
+	DeviceInfos   []*DeviceInfo `json:"device_infos"`
 }
 
 type Instance interface {
@@ -69,6 +72,7 @@ type instance struct {
 	mu sync.RWMutex
 
 	driverVersion string
+	nvmlVersion   string
 
 	rootCtx    context.Context
 	rootCancel context.CancelFunc
@@ -124,11 +128,22 @@ type DeviceInfo struct {
 	BusID uint32 `json:"bus_id"`
 	// DeviceID is the device ID from PCI info API.
 	DeviceID uint32 `json:"device_id"`
+	// PCIBusID is the PCI bus ID in the sysfs format (e.g., "0000:3b:00.0").
+	PCIBusID string `json:"pci_bus_id"`
+	// Set true if the device supports the PCIe secondary bus reset (SBR),
+	// meaning the GPU can be reset without a full system reboot.
+	SecondaryBusResetSupported bool `json:"secondary_bus_reset_supported"`
+	// NUMANode is the NUMA node that the GPU is attached to
+	// (-1 if the kernel does not report the NUMA affinity).
+	NUMANode int `json:"numa_node"`
 
 	Name            string `json:"name"`
 	GPUCores        int    `json:"gpu_cores"`
 	SupportedEvents uint64 `json:"supported_events"`
 
+	// Static board info (e.g., serial number) for the asset tracking.
+	Inventory Inventory `json:"inventory"`
+
 	// Set true if the device supports NVML error checks (health checks).
 	XidErrorSupported bool `json:"xid_error_supported"`
 	// Set true if the device supports GPM metrics.
@@ -136,6 +151,7 @@ type DeviceInfo struct {
 
 	GSPFirmwareMode GSPFirmwareMode `json:"gsp_firmware_mode"`
 	PersistenceMode PersistenceMode `json:"persistence_mode"`
+	MIGMode         MIGMode         `json:"mig_mode"`
 	ClockEvents     *ClockEvents    `json:"clock_events,omitempty"`
 	ClockSpeed      ClockSpeed      `json:"clock_speed"`
 	Memory          Memory          `json:"memory"`
@@ -148,13 +164,17 @@ type DeviceInfo struct {
 	ECCErrors       ECCErrors       `json:"ecc_errors"`
 	RemappedRows    RemappedRows    `json:"remapped_rows"`
 
+	ViolationCounters ViolationCounters `json:"violation_counters"`
+
+	PCIeLink PCIeLink `json:"pcie_link"`
+
 	device device.Device `json:"-"`
 }
 
 func GetDriverVersion() (string, error) {
 	nvmlLib := NewNVML()
 	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
-		return "", fmt.Errorf("failed to initialize NVML: %v", nvml.ErrorString(ret))
+		return "", newInitError(ret)
 	}
 
 	ver, ret := nvmlLib.SystemGetDriverVersion()
@@ -168,6 +188,24 @@ func GetDriverVersion() (string, error) {
 	return ver, nil
 }
 
+// GetDeviceCount returns the number of the GPUs from the NVML library,
+// as a lightweight query to check that the library and the driver respond.
+func GetDeviceCount() (int, error) {
+	nvmlLib := NewNVML()
+	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
+		return 0, newInitError(ret)
+	}
+	defer func() {
+		_ = nvmlLib.Shutdown()
+	}()
+
+	count, ret := nvmlLib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return 0, fmt.Errorf("failed to get device count: %v", nvml.ErrorString(ret))
+	}
+	return count, nil
+}
+
 func ParseDriverVersion(version string) (major, minor, patch int, err error) {
 	var parsed [3]int
 	if _, err = fmt.Sscanf(version, "%d.%d.%d", &parsed[0], &parsed[1], &parsed[2]); err != nil {
@@ -186,6 +224,10 @@ func ClockEventsSupportedVersion(major int) bool {
 }
 
 func NewInstance(ctx context.Context, opts ...OpOption) (Instance, error) {
+	return newInstance(ctx, NewNVML(), opts...)
+}
+
+func newInstance(ctx context.Context, nvmlLib nvml.Interface, opts ...OpOption) (Instance, error) {
 	op := &Op{}
 	if err := op.applyOpts(opts); err != nil {
 		return nil, err
@@ -196,11 +238,9 @@ func NewInstance(ctx context.Context, opts ...OpOption) (Instance, error) {
 		gpmMetricsIDs = append(gpmMetricsIDs, id)
 	}
 
-	nvmlLib := NewNVML()
-
 	log.Logger.Debugw("initializing nvml library")
 	if ret := nvmlLib.Init(); ret != nvml.SUCCESS {
-		return nil, fmt.Errorf("failed to initialize NVML: %v", nvml.ErrorString(ret))
+		return nil, newInitError(ret)
 	}
 
 	log.Logger.Debugw("getting driver version from nvml library")
@@ -219,6 +259,12 @@ func NewInstance(ctx context.Context, opts ...OpOption) (Instance, error) {
 		log.Logger.Warnw("old nvidia driver -- skipping clock events, see https://github.com/NVIDIA/go-nvml/pull/123", "version", driverVersion)
 	}
 
+	// e.g., 12.535.161.08
+	nvmlVersion, ret := nvmlLib.SystemGetNVMLVersion()
+	if ret != nvml.SUCCESS {
+		log.Logger.Warnw("failed to get nvml version", "error", nvml.ErrorString(ret))
+	}
+
 	log.Logger.Debugw("successfully initialized NVML", "driverVersion", driverVersion)
 
 	log.Logger.Debugw("creating device library")
@@ -246,6 +292,7 @@ func NewInstance(ctx context.Context, opts ...OpOption) (Instance, error) {
 		rootCancel: rootCancel,
 
 		driverVersion: driverVersion,
+		nvmlVersion:   nvmlVersion,
 
 		nvmlLib:   nvmlLib,
 		deviceLib: deviceLib,
@@ -329,12 +376,38 @@ func (inst *instance) Start() error {
 			return fmt.Errorf("failed to get device PCI info: %v", nvml.ErrorString(ret))
 		}
 
+		pciBusID, err := d.GetPCIBusID()
+		if err != nil {
+			// optional, only used for the secondary bus reset support and the NUMA affinity
+			log.Logger.Warnw("failed to get device PCI bus ID", "uuid", uuid, "error", err)
+			pciBusID = ""
+		}
+		sbrSupported := false
+		numaNode := pci.NoNUMANode
+		if pciBusID != "" {
+			sbrSupported, err = pci.SupportsSecondaryBusReset(pciBusID)
+			if err != nil {
+				log.Logger.Warnw("failed to check secondary bus reset support", "pciBusID", pciBusID, "error", err)
+			}
+			numaNode, err = pci.GetNUMAAffinity(pciBusID)
+			if err != nil {
+				log.Logger.Warnw("failed to get NUMA affinity", "pciBusID", pciBusID, "error", err)
+			}
+		}
+
 		log.Logger.Debugw("getting device name")
 		name, ret := d.GetName()
 		if ret != nvml.SUCCESS {
 			return fmt.Errorf("failed to get device name: %v", nvml.ErrorString(ret))
 		}
 
+		log.Logger.Debugw("getting device inventory")
+		inventory, err := GetInventory(uuid, d)
+		if err != nil {
+			// optional, only used for the asset tracking
+			log.Logger.Warnw("failed to get device inventory", "uuid", uuid, "error", err)
+		}
+
 		log.Logger.Debugw("getting device cores")
 		cores, ret := d.GetNumGpuCores()
 		if ret != nvml.SUCCESS {
@@ -373,8 +446,13 @@ func (inst *instance) Start() error {
 			BusID:         pciInfo.Bus,
 			DeviceID:      pciInfo.Device,
 
-			Name:     name,
-			GPUCores: cores,
+			PCIBusID:                   pciBusID,
+			SecondaryBusResetSupported: sbrSupported,
+			NUMANode:                   numaNode,
+
+			Name:      name,
+			GPUCores:  cores,
+			Inventory: inventory,
 
 			SupportedEvents: supportedEvents,
 
@@ -453,8 +531,10 @@ func (inst *instance) Get() (*Output, error) {
 	}
 
 	st := &Output{
-		Exists:  inst.nvmlExists,
-		Message: inst.nvmlExistsMsg,
+		Exists:        inst.nvmlExists,
+		Message:       inst.nvmlExistsMsg,
+		DriverVersion: inst.driverVersion,
+		NVMLVersion:   inst.nvmlVersion,
 	}
 
 	// nvidia-smi polling happens periodically
@@ -471,9 +551,14 @@ func (inst *instance) Get() (*Output, error) {
 			BusID:         devInfo.BusID,
 			DeviceID:      devInfo.DeviceID,
 
+			PCIBusID:                   devInfo.PCIBusID,
+			SecondaryBusResetSupported: devInfo.SecondaryBusResetSupported,
+			NUMANode:                   devInfo.NUMANode,
+
 			Name:            devInfo.Name,
 			GPUCores:        devInfo.GPUCores,
 			SupportedEvents: devInfo.SupportedEvents,
+			Inventory:       devInfo.Inventory,
 
 			XidErrorSupported:   devInfo.XidErrorSupported,
 			GPMMetricsSupported: devInfo.GPMMetricsSupported,
@@ -493,6 +578,12 @@ func (inst *instance) Get() (*Output, error) {
 			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
 		}
 
+		var migDevs []device.MigDevice
+		latestInfo.MIGMode, migDevs, err = getMIGMode(devInfo.UUID, devInfo.device)
+		if err != nil {
+			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
+		}
+
 		if inst.clockEventsSupported {
 			clockEvents, err := GetClockEvents(devInfo.UUID, devInfo.device)
 			if err != nil {
@@ -541,6 +632,13 @@ func (inst *instance) Get() (*Output, error) {
 		if err != nil {
 			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
 		}
+		if len(migDevs) > 0 {
+			// the failed MIG instance query does not discard the device memory
+			latestInfo.Memory.MIGInstances, err = GetMIGMemory(migDevs)
+			if err != nil {
+				joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
+			}
+		}
 
 		latestInfo.NVLink, err = GetNVLink(devInfo.UUID, devInfo.device)
 		if err != nil {
@@ -581,6 +679,16 @@ func (inst *instance) Get() (*Output, error) {
 		if err != nil {
 			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
 		}
+
+		latestInfo.ViolationCounters, err = GetViolationCounters(devInfo.UUID, devInfo.device)
+		if err != nil {
+			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
+		}
+
+		latestInfo.PCIeLink, err = GetPCIeLink(devInfo.UUID, devInfo.device)
+		if err != nil {
+			joinedErrs = append(joinedErrs, fmt.Errorf("%w (GPU uuid %s)", err, devInfo.UUID))
+		}
 	}
 
 	sort.Slice(st.DeviceInfos, func(i, j int) bool {
@@ -597,6 +705,9 @@ func (inst *instance) Get() (*Output, error) {
 var (
 	defaultInstanceMu sync.RWMutex
 	defaultInstance   Instance
+	// non-nil if the default instance failed with the driver/library mismatch
+	// that does not resolve until reboot, to not re-initialize NVML on every poll
+	defaultInstanceMismatchErr error
 
 	defaultInstanceReadyCloseOnce sync.Once
 	defaultInstanceReadyc         = make(chan any)
@@ -637,14 +748,21 @@ func StartDefaultInstance(rootCtx context.Context, opts ...OpOption) error {
 	if defaultInstance != nil {
 		return nil
 	}
+	if defaultInstanceMismatchErr != nil {
+		return defaultInstanceMismatchErr
+	}
 
 	log.Logger.Debugw("creating a new default nvml instance")
 
-	var err error
-	defaultInstance, err = NewInstance(rootCtx, opts...)
+	instance, err := NewInstance(rootCtx, opts...)
 	if err != nil {
+		if errors.Is(err, ErrDriverLibraryMismatch) {
+			log.Logger.Errorw("nvidia driver/library version mismatch -- reboot required, skipping nvml", "error", err)
+			defaultInstanceMismatchErr = err
+		}
 		return err
 	}
+	defaultInstance = instance
 
 	defer func() {
 		defaultInstanceReadyCloseOnce.Do(func() {
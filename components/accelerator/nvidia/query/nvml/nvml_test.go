@@ -1,11 +1,17 @@
 package nvml
 
 import (
+	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/leptonai/gpud/errdefs"
+
 	mocknvml "github.com/leptonai/gpud/e2e/mock/nvml"
 )
 
@@ -71,3 +77,22 @@ func TestNewNVML(t *testing.T) {
 	nvmlLib = NewNVML()
 	assert.Equal(t, mocknvml.MockInstance, nvmlLib)
 }
+
+func TestNewInstanceDriverLibraryMismatch(t *testing.T) {
+	nvmlLib := &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.ERROR_LIB_RM_VERSION_MISMATCH
+		},
+	}
+	inst, err := newInstance(context.Background(), nvmlLib)
+	assert.Nil(t, inst)
+	assert.True(t, errors.Is(err, ErrDriverLibraryMismatch), "unexpected error %v", err)
+	assert.False(t, errors.Is(err, errdefs.ErrUnavailable))
+
+	nvmlLib.InitFunc = func() nvml.Return {
+		return nvml.ERROR_LIBRARY_NOT_FOUND
+	}
+	_, err = newInstance(context.Background(), nvmlLib)
+	assert.False(t, errors.Is(err, ErrDriverLibraryMismatch))
+	assert.True(t, errors.Is(err, errdefs.ErrUnavailable))
+}
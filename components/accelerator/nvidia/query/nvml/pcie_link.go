@@ -0,0 +1,73 @@
+package nvml
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// PCIeLink is the current and max PCIe link width and generation of the GPU.
+// The current link below the max indicates the link has been downtrained,
+// which often precedes the GPU falling off the bus (Xid 79).
+// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+type PCIeLink struct {
+	// Represents the GPU UUID.
+	UUID string `json:"uuid"`
+
+	// The current and max link width (e.g., 16 for x16).
+	CurrentLinkWidth int `json:"current_link_width"`
+	MaxLinkWidth     int `json:"max_link_width"`
+
+	// The current and max link generation (e.g., 4 for Gen4).
+	// The max is the one supported by both the GPU and the system.
+	CurrentLinkGeneration int `json:"current_link_generation"`
+	MaxLinkGeneration     int `json:"max_link_generation"`
+
+	// Supported is true if the PCIe link info is supported by the device.
+	Supported bool `json:"supported"`
+}
+
+// WidthDowntrained returns true if the current link width is below the max.
+func (l PCIeLink) WidthDowntrained() bool {
+	return l.Supported && l.MaxLinkWidth > 0 && l.CurrentLinkWidth < l.MaxLinkWidth
+}
+
+// GenerationDowntrained returns true if the current link generation is below the max.
+// Note that the GPU may lower the link generation when idle to save power.
+func (l PCIeLink) GenerationDowntrained() bool {
+	return l.Supported && l.MaxLinkGeneration > 0 && l.CurrentLinkGeneration < l.MaxLinkGeneration
+}
+
+// GetPCIeLink returns the current and max PCIe link width and generation.
+// It returns a non-supported result (without an error) if the device does not support the queries.
+func GetPCIeLink(uuid string, dev device.Device) (PCIeLink, error) {
+	link := PCIeLink{
+		UUID:      uuid,
+		Supported: true,
+	}
+
+	for _, q := range []struct {
+		dst       *int
+		get       func() (int, nvml.Return)
+		errorDesc string
+	}{
+		{dst: &link.CurrentLinkWidth, get: dev.GetCurrPcieLinkWidth, errorDesc: "current link width"},
+		{dst: &link.MaxLinkWidth, get: dev.GetMaxPcieLinkWidth, errorDesc: "max link width"},
+		{dst: &link.CurrentLinkGeneration, get: dev.GetCurrPcieLinkGeneration, errorDesc: "current link generation"},
+		{dst: &link.MaxLinkGeneration, get: dev.GetMaxPcieLinkGeneration, errorDesc: "max link generation"},
+	} {
+		v, ret := q.get()
+		if IsNotSupportError(ret) {
+			return PCIeLink{UUID: uuid, Supported: false}, nil
+		}
+
+		// not a "not supported" error, not a success return, thus return an error here
+		if ret != nvml.SUCCESS {
+			return link, fmt.Errorf("(%s) failed to get device pcie link: %s", q.errorDesc, nvml.ErrorString(ret))
+		}
+		*q.dst = v
+	}
+
+	return link, nil
+}
@@ -0,0 +1,92 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetPCIeLink(t *testing.T) {
+	tests := []struct {
+		name                 string
+		currWidth, maxWidth  int
+		currGen, maxGen      int
+		ret                  nvml.Return
+		expected             PCIeLink
+		wantWidthDowntrained bool
+		wantGenDowntrained   bool
+		wantErr              bool
+	}{
+		{
+			name:      "healthy x16 gen4",
+			currWidth: 16, maxWidth: 16,
+			currGen: 4, maxGen: 4,
+			ret:      nvml.SUCCESS,
+			expected: PCIeLink{UUID: "gpu-0", CurrentLinkWidth: 16, MaxLinkWidth: 16, CurrentLinkGeneration: 4, MaxLinkGeneration: 4, Supported: true},
+		},
+		{
+			name:      "downtrained x8",
+			currWidth: 8, maxWidth: 16,
+			currGen: 4, maxGen: 4,
+			ret:                  nvml.SUCCESS,
+			expected:             PCIeLink{UUID: "gpu-0", CurrentLinkWidth: 8, MaxLinkWidth: 16, CurrentLinkGeneration: 4, MaxLinkGeneration: 4, Supported: true},
+			wantWidthDowntrained: true,
+		},
+		{
+			name:      "downtrained gen3",
+			currWidth: 16, maxWidth: 16,
+			currGen: 3, maxGen: 4,
+			ret:                nvml.SUCCESS,
+			expected:           PCIeLink{UUID: "gpu-0", CurrentLinkWidth: 16, MaxLinkWidth: 16, CurrentLinkGeneration: 3, MaxLinkGeneration: 4, Supported: true},
+			wantGenDowntrained: true,
+		},
+		{
+			name:     "not supported",
+			ret:      nvml.ERROR_NOT_SUPPORTED,
+			expected: PCIeLink{UUID: "gpu-0", Supported: false},
+		},
+		{
+			name:    "unknown error",
+			ret:     nvml.ERROR_UNKNOWN,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := testutil.CreateDevice(&mock.Device{
+				GetCurrPcieLinkWidthFunc: func() (int, nvml.Return) {
+					return tt.currWidth, tt.ret
+				},
+				GetMaxPcieLinkWidthFunc: func() (int, nvml.Return) {
+					return tt.maxWidth, tt.ret
+				},
+				GetCurrPcieLinkGenerationFunc: func() (int, nvml.Return) {
+					return tt.currGen, tt.ret
+				},
+				GetMaxPcieLinkGenerationFunc: func() (int, nvml.Return) {
+					return tt.maxGen, tt.ret
+				},
+			})
+
+			link, err := GetPCIeLink("gpu-0", dev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPCIeLink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if link != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, link)
+			}
+			if link.WidthDowntrained() != tt.wantWidthDowntrained {
+				t.Errorf("expected width downtrained %v, got %v", tt.wantWidthDowntrained, link.WidthDowntrained())
+			}
+			if link.GenerationDowntrained() != tt.wantGenDowntrained {
+				t.Errorf("expected generation downtrained %v, got %v", tt.wantGenDowntrained, link.GenerationDowntrained())
+			}
+		})
+	}
+}
@@ -50,3 +50,14 @@ func GetPersistenceMode(uuid string, dev device.Device) (PersistenceMode, error)
 
 	return mode, nil
 }
+
+// EnablePersistenceMode enables the persistence mode of the device (equivalent to "nvidia-smi -pm 1").
+// Requires root, and the setting does not persist across the reboots.
+func EnablePersistenceMode(dev device.Device) error {
+	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceCommands.html
+	ret := dev.SetPersistenceMode(nvml.FEATURE_ENABLED)
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to enable device persistence mode: %v", nvml.ErrorString(ret))
+	}
+	return nil
+}
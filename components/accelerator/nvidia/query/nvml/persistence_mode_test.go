@@ -0,0 +1,66 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetPersistenceMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         nvml.EnableState
+		ret           nvml.Return
+		wantEnabled   bool
+		wantSupported bool
+		wantErr       bool
+	}{
+		{name: "enabled", state: nvml.FEATURE_ENABLED, ret: nvml.SUCCESS, wantEnabled: true, wantSupported: true},
+		{name: "disabled", state: nvml.FEATURE_DISABLED, ret: nvml.SUCCESS, wantSupported: true},
+		{name: "not supported", ret: nvml.ERROR_NOT_SUPPORTED},
+		{name: "error", ret: nvml.ERROR_UNKNOWN, wantSupported: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := testutil.CreateDevice(&mock.Device{
+				GetPersistenceModeFunc: func() (nvml.EnableState, nvml.Return) {
+					return tt.state, tt.ret
+				},
+			})
+			mode, err := GetPersistenceMode("GPU-0", dev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if mode.Enabled != tt.wantEnabled || mode.Supported != tt.wantSupported {
+				t.Errorf("unexpected mode %+v", mode)
+			}
+		})
+	}
+}
+
+func TestEnablePersistenceMode(t *testing.T) {
+	var set nvml.EnableState
+	dev := testutil.CreateDevice(&mock.Device{
+		SetPersistenceModeFunc: func(state nvml.EnableState) nvml.Return {
+			set = state
+			return nvml.SUCCESS
+		},
+	})
+	if err := EnablePersistenceMode(dev); err != nil {
+		t.Fatal(err)
+	}
+	if set != nvml.FEATURE_ENABLED {
+		t.Errorf("expected persistence mode enabled, got %v", set)
+	}
+
+	dev = testutil.CreateDevice(&mock.Device{
+		SetPersistenceModeFunc: func(state nvml.EnableState) nvml.Return {
+			return nvml.ERROR_NO_PERMISSION
+		},
+	})
+	if err := EnablePersistenceMode(dev); err == nil {
+		t.Error("expected error without permission")
+	}
+}
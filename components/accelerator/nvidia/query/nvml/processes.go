@@ -1,12 +1,17 @@
 package nvml
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/leptonai/gpud/errdefs"
 	"github.com/leptonai/gpud/log"
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
@@ -49,6 +54,13 @@ type Process struct {
 	// This implements "DCGM_FR_BAD_CUDA_ENV" logic in DCGM.
 	BadEnvVarsForCUDA map[string]string `json:"bad_env_vars_for_cuda,omitempty"`
 
+	// CgroupPath is the cgroup path of the process (best-effort).
+	// Prefers the cgroup v2 unified hierarchy if available.
+	CgroupPath string `json:"cgroup_path,omitempty"`
+	// ContainerID is the container ID parsed from the cgroup path (best-effort),
+	// empty if the process is not running in a container.
+	ContainerID string `json:"container_id,omitempty"`
+
 	CmdArgs                     []string    `json:"cmd_args,omitempty"`
 	CreateTime                  metav1.Time `json:"create_time,omitempty"`
 	GPUUsedPercent              uint32      `json:"gpu_used_percent,omitempty"`
@@ -93,9 +105,13 @@ func GetProcesses(uuid string, dev device.Device) (Processes, error) {
 			return Processes{}, fmt.Errorf("failed to get process %d: %v", proc.Pid, err)
 		}
 
+		// e.g., gpud not running as root cannot inspect the processes of other users
 		args, err := procObject.CmdlineSlice()
 		if err != nil {
-			return Processes{}, fmt.Errorf("failed to get process %d args: %v", proc.Pid, err)
+			if !errdefs.IsPermission(err) {
+				return Processes{}, fmt.Errorf("failed to get process %d args: %v", proc.Pid, err)
+			}
+			log.Logger.Debugw("permission denied reading process args -- skipping", "pid", proc.Pid, "error", err)
 		}
 		createTimeUnixMS, err := procObject.CreateTime()
 		if err != nil {
@@ -148,7 +164,10 @@ func GetProcesses(uuid string, dev device.Device) (Processes, error) {
 
 		envs, err := procObject.Environ()
 		if err != nil {
-			return procs, fmt.Errorf("failed to get process %d environ: %v", proc.Pid, err)
+			if !errdefs.IsPermission(err) {
+				return procs, fmt.Errorf("failed to get process %d environ: %v", proc.Pid, err)
+			}
+			log.Logger.Debugw("permission denied reading process environ -- skipping", "pid", proc.Pid, "error", err)
 		}
 
 		badEnvVars := make(map[string]string)
@@ -167,9 +186,14 @@ func GetProcesses(uuid string, dev device.Device) (Processes, error) {
 			badEnvVars = nil
 		}
 
+		cgroupPath := readProcessCgroupPath(proc.Pid)
+
 		procs.RunningProcesses = append(procs.RunningProcesses, Process{
 			PID: proc.Pid,
 
+			CgroupPath:  cgroupPath,
+			ContainerID: parseContainerID(cgroupPath),
+
 			Status:       status,
 			ZombieStatus: isZombie,
 
@@ -189,3 +213,53 @@ func GetProcesses(uuid string, dev device.Device) (Processes, error) {
 
 	return procs, nil
 }
+
+// readProcessCgroupPath returns the cgroup path of the process.
+// Returns an empty string if the cgroup cannot be read (e.g., process exited).
+func readProcessCgroupPath(pid uint32) string {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		log.Logger.Debugw("failed to read process cgroup", "pid", pid, "error", err)
+		return ""
+	}
+	defer f.Close()
+	return parseProcCgroup(f)
+}
+
+// parseProcCgroup parses the "/proc/<pid>/cgroup" contents
+// (e.g., "0::/system.slice/containerd.service") and returns the cgroup path.
+// Returns the cgroup v2 unified path if available,
+// otherwise the first non-root cgroup v1 path.
+func parseProcCgroup(rd io.Reader) string {
+	v1Path := ""
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if v1Path == "" && parts[2] != "/" {
+			v1Path = parts[2]
+		}
+	}
+	return v1Path
+}
+
+// matches the 64-character hex container IDs used by docker, containerd, and cri-o
+// e.g., "/docker/<id>", "/kubepods/besteffort/pod<uid>/<id>", "cri-containerd-<id>.scope"
+var containerIDRegex = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// parseContainerID returns the container ID from the cgroup path.
+// Returns an empty string if the path does not belong to a container.
+func parseContainerID(cgroupPath string) string {
+	matches := containerIDRegex.FindAllString(cgroupPath, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	// the innermost match is the container, e.g., for nested cgroups
+	return matches[len(matches)-1]
+}
@@ -0,0 +1,145 @@
+package nvml
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetProcesses(t *testing.T) {
+	if _, err := os.Stat("/proc/self/cgroup"); err != nil {
+		t.Skip("/proc/self/cgroup not found")
+	}
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	selfPID := uint32(os.Getpid())
+	childPID := uint32(cmd.Process.Pid)
+
+	mockDevice := &mock.Device{
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return []nvml.ProcessInfo{
+				{Pid: selfPID, UsedGpuMemory: 1024 * 1024},
+				{Pid: childPID, UsedGpuMemory: 2 * 1024 * 1024},
+			}, nvml.SUCCESS
+		},
+		GetProcessUtilizationFunc: func(lastSeen uint64) ([]nvml.ProcessUtilizationSample, nvml.Return) {
+			return []nvml.ProcessUtilizationSample{
+				{TimeStamp: 1, MemUtil: 10},
+				{TimeStamp: 2, MemUtil: 20},
+			}, nvml.SUCCESS
+		},
+	}
+
+	procs, err := GetProcesses("GPU-1234", testutil.CreateDevice(mockDevice))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if procs.UUID != "GPU-1234" {
+		t.Errorf("expected uuid GPU-1234, got %q", procs.UUID)
+	}
+	if len(procs.RunningProcesses) != 2 {
+		t.Fatalf("expected 2 processes, got %d", len(procs.RunningProcesses))
+	}
+
+	expectedCgroup := readProcessCgroupPath(selfPID)
+	for i, want := range []struct {
+		pid    uint32
+		memory uint64
+	}{
+		{selfPID, 1024 * 1024},
+		{childPID, 2 * 1024 * 1024},
+	} {
+		got := procs.RunningProcesses[i]
+		if got.PID != want.pid {
+			t.Errorf("process %d: expected pid %d, got %d", i, want.pid, got.PID)
+		}
+		if got.GPUUsedMemoryBytes != want.memory {
+			t.Errorf("process %d: expected used memory %d, got %d", i, want.memory, got.GPUUsedMemoryBytes)
+		}
+		if got.GPUUsedPercent != 20 {
+			t.Errorf("process %d: expected the latest memory utilization 20, got %d", i, got.GPUUsedPercent)
+		}
+		if got.CgroupPath != expectedCgroup {
+			t.Errorf("process %d: expected cgroup path %q, got %q", i, expectedCgroup, got.CgroupPath)
+		}
+		if got.ContainerID != parseContainerID(expectedCgroup) {
+			t.Errorf("process %d: expected container id %q, got %q", i, parseContainerID(expectedCgroup), got.ContainerID)
+		}
+	}
+	if args := procs.RunningProcesses[1].CmdArgs; len(args) != 2 || args[0] != "sleep" {
+		t.Errorf("unexpected child process args %v", args)
+	}
+}
+
+func TestGetProcessesNotSupported(t *testing.T) {
+	mockDevice := &mock.Device{
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return nil, nvml.ERROR_NOT_SUPPORTED
+		},
+	}
+	procs, err := GetProcesses("GPU-1234", testutil.CreateDevice(mockDevice))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if procs.GetComputeRunningProcessesSupported {
+		t.Error("expected compute running processes not supported")
+	}
+}
+
+func TestParseProcCgroup(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantPath        string
+		wantContainerID string
+	}{
+		{
+			name:     "cgroup v2 host",
+			input:    "0::/system.slice/gpud.service\n",
+			wantPath: "/system.slice/gpud.service",
+		},
+		{
+			name:            "cgroup v2 containerd",
+			input:           "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1234.slice/cri-containerd-" + strings.Repeat("a1", 32) + ".scope\n",
+			wantPath:        "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1234.slice/cri-containerd-" + strings.Repeat("a1", 32) + ".scope",
+			wantContainerID: strings.Repeat("a1", 32),
+		},
+		{
+			name: "cgroup v1 docker",
+			input: `12:memory:/docker/` + strings.Repeat("0f", 32) + `
+11:cpu,cpuacct:/docker/` + strings.Repeat("0f", 32) + `
+1:name=systemd:/
+`,
+			wantPath:        "/docker/" + strings.Repeat("0f", 32),
+			wantContainerID: strings.Repeat("0f", 32),
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := parseProcCgroup(strings.NewReader(tt.input))
+			if path != tt.wantPath {
+				t.Errorf("expected path %q, got %q", tt.wantPath, path)
+			}
+			if id := parseContainerID(path); id != tt.wantContainerID {
+				t.Errorf("expected container id %q, got %q", tt.wantContainerID, id)
+			}
+		})
+	}
+}
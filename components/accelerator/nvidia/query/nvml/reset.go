@@ -0,0 +1,80 @@
+package nvml
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// ResetCapability represents whether the GPU can be reset now
+// (e.g., "nvidia-smi --gpu-reset" for the GPUs reporting the Xid).
+// The GPU reset requires no process using the GPU, and the GPU not driving a display.
+// ref. https://docs.nvidia.com/deploy/nvidia-smi/index.html
+type ResetCapability struct {
+	// Represents the GPU UUID.
+	UUID string `json:"uuid"`
+
+	// Supported is false if the GPU cannot be reset at all
+	// (e.g., the GPU drives an active display).
+	Supported bool `json:"supported"`
+
+	// PIDs of the compute and graphics processes running on the GPU.
+	RunningProcessIDs []uint32 `json:"running_process_ids,omitempty"`
+
+	// Reasons why the GPU cannot be reset now, empty if resettable.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Resettable returns true if the GPU can be reset now.
+func (c ResetCapability) Resettable() bool {
+	return c.Supported && len(c.RunningProcessIDs) == 0
+}
+
+// GetResetCapability checks whether the GPU can be reset now.
+// The compute and graphics processes not supported by the device are not checked.
+func GetResetCapability(uuid string, dev device.Device) (ResetCapability, error) {
+	capability := ResetCapability{
+		UUID:      uuid,
+		Supported: true,
+	}
+
+	// ref. https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+	displayActive, ret := dev.GetDisplayActive()
+	if ret != nvml.SUCCESS && !IsNotSupportError(ret) {
+		return capability, fmt.Errorf("failed to get device display active: %v", nvml.ErrorString(ret))
+	}
+	if ret == nvml.SUCCESS && displayActive == nvml.FEATURE_ENABLED {
+		capability.Supported = false
+		capability.Reasons = append(capability.Reasons, "GPU drives an active display")
+	}
+
+	seen := make(map[uint32]struct{})
+	for _, get := range []struct {
+		name string
+		f    func() ([]nvml.ProcessInfo, nvml.Return)
+	}{
+		{name: "compute", f: dev.GetComputeRunningProcesses},
+		{name: "graphics", f: dev.GetGraphicsRunningProcesses},
+	} {
+		procs, ret := get.f()
+		if IsNotSupportError(ret) {
+			continue
+		}
+		if ret != nvml.SUCCESS {
+			return capability, fmt.Errorf("failed to get device %s processes: %v", get.name, nvml.ErrorString(ret))
+		}
+		for _, proc := range procs {
+			if _, ok := seen[proc.Pid]; ok {
+				continue
+			}
+			seen[proc.Pid] = struct{}{}
+			capability.RunningProcessIDs = append(capability.RunningProcessIDs, proc.Pid)
+		}
+	}
+	if len(capability.RunningProcessIDs) > 0 {
+		capability.Reasons = append(capability.Reasons, fmt.Sprintf("%d process(es) running on the GPU", len(capability.RunningProcessIDs)))
+	}
+
+	return capability, nil
+}
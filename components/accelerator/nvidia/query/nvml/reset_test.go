@@ -0,0 +1,111 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func mockResetDevice(display nvml.EnableState, compute, graphics []nvml.ProcessInfo) *mock.Device {
+	return &mock.Device{
+		GetDisplayActiveFunc: func() (nvml.EnableState, nvml.Return) {
+			return display, nvml.SUCCESS
+		},
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return compute, nvml.SUCCESS
+		},
+		GetGraphicsRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return graphics, nvml.SUCCESS
+		},
+	}
+}
+
+func TestGetResetCapabilityIdle(t *testing.T) {
+	dev := testutil.CreateDevice(mockResetDevice(nvml.FEATURE_DISABLED, nil, nil))
+
+	c, err := GetResetCapability("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Supported || !c.Resettable() || len(c.Reasons) != 0 {
+		t.Errorf("expected idle GPU to be resettable, got %+v", c)
+	}
+}
+
+func TestGetResetCapabilityRunningProcesses(t *testing.T) {
+	dev := testutil.CreateDevice(mockResetDevice(
+		nvml.FEATURE_DISABLED,
+		[]nvml.ProcessInfo{{Pid: 100}, {Pid: 200}},
+		[]nvml.ProcessInfo{{Pid: 200}, {Pid: 300}},
+	))
+
+	c, err := GetResetCapability("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Supported {
+		t.Error("expected reset supported")
+	}
+	if c.Resettable() {
+		t.Error("expected GPU with running processes not resettable")
+	}
+	expected := []uint32{100, 200, 300}
+	if len(c.RunningProcessIDs) != len(expected) {
+		t.Fatalf("expected pids %v, got %v", expected, c.RunningProcessIDs)
+	}
+	for i := range expected {
+		if c.RunningProcessIDs[i] != expected[i] {
+			t.Errorf("expected pids %v, got %v", expected, c.RunningProcessIDs)
+		}
+	}
+	if len(c.Reasons) != 1 || c.Reasons[0] != "3 process(es) running on the GPU" {
+		t.Errorf("unexpected reasons %v", c.Reasons)
+	}
+}
+
+func TestGetResetCapabilityDisplayActive(t *testing.T) {
+	dev := testutil.CreateDevice(mockResetDevice(nvml.FEATURE_ENABLED, nil, nil))
+
+	c, err := GetResetCapability("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Supported || c.Resettable() {
+		t.Errorf("expected GPU driving a display not resettable, got %+v", c)
+	}
+}
+
+func TestGetResetCapabilityNotSupported(t *testing.T) {
+	dev := testutil.CreateDevice(&mock.Device{
+		GetDisplayActiveFunc: func() (nvml.EnableState, nvml.Return) {
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetComputeRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return nil, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetGraphicsRunningProcessesFunc: func() ([]nvml.ProcessInfo, nvml.Return) {
+			return nil, nvml.ERROR_NOT_SUPPORTED
+		},
+	})
+
+	c, err := GetResetCapability("GPU-0", dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Resettable() {
+		t.Errorf("expected the unsupported queries to be skipped, got %+v", c)
+	}
+}
+
+func TestGetResetCapabilityError(t *testing.T) {
+	m := mockResetDevice(nvml.FEATURE_DISABLED, nil, nil)
+	m.GetComputeRunningProcessesFunc = func() ([]nvml.ProcessInfo, nvml.Return) {
+		return nil, nvml.ERROR_UNKNOWN
+	}
+	if _, err := GetResetCapability("GPU-0", testutil.CreateDevice(m)); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -9,6 +9,7 @@ var _ device.Device = (*mockDevice)(nil)
 
 type mockDevice struct {
 	*mock.Device
+	migDevices []device.MigDevice
 }
 
 func (d *mockDevice) GetArchitectureAsString() (string, error) {
@@ -21,18 +22,55 @@ func (d *mockDevice) GetCudaComputeCapabilityAsString() (string, error) {
 	return "", nil
 }
 func (d *mockDevice) GetMigDevices() ([]device.MigDevice, error) {
-	return nil, nil
+	return d.migDevices, nil
 }
 func (d *mockDevice) GetMigProfiles() ([]device.MigProfile, error) {
 	return nil, nil
 }
-func (d *mockDevice) GetPCIBusID() (string, error)                                { return "", nil }
-func (d *mockDevice) IsFabricAttached() (bool, error)                             { return false, nil }
-func (d *mockDevice) IsMigCapable() (bool, error)                                 { return false, nil }
-func (d *mockDevice) IsMigEnabled() (bool, error)                                 { return false, nil }
-func (d *mockDevice) VisitMigDevices(func(j int, m device.MigDevice) error) error { return nil }
-func (d *mockDevice) VisitMigProfiles(func(p device.MigProfile) error) error      { return nil }
+func (d *mockDevice) GetPCIBusID() (string, error)                           { return "", nil }
+func (d *mockDevice) IsFabricAttached() (bool, error)                        { return false, nil }
+func (d *mockDevice) IsMigCapable() (bool, error)                            { return false, nil }
+func (d *mockDevice) IsMigEnabled() (bool, error)                            { return len(d.migDevices) > 0, nil }
+func (d *mockDevice) VisitMigProfiles(func(p device.MigProfile) error) error { return nil }
+func (d *mockDevice) VisitMigDevices(visit func(j int, m device.MigDevice) error) error {
+	for j, m := range d.migDevices {
+		if err := visit(j, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ device.MigDevice = (*mockMigDevice)(nil)
+
+type mockMigDevice struct {
+	*mock.Device
+	profile string
+}
+
+func (d *mockMigDevice) GetProfile() (device.MigProfile, error) {
+	return mockMigProfile(d.profile), nil
+}
+
+var _ device.MigProfile = mockMigProfile("")
+
+type mockMigProfile string
+
+func (p mockMigProfile) String() string                      { return string(p) }
+func (p mockMigProfile) GetInfo() device.MigProfileInfo      { return device.MigProfileInfo{} }
+func (p mockMigProfile) Equals(other device.MigProfile) bool { return p.String() == other.String() }
+func (p mockMigProfile) Matches(profile string) bool         { return string(p) == profile }
 
 func CreateDevice(m *mock.Device) device.Device {
 	return &mockDevice{Device: m}
 }
+
+// CreateMIGDevice creates the MIG-enabled device partitioned into the MIG devices.
+func CreateMIGDevice(m *mock.Device, migs ...device.MigDevice) device.Device {
+	return &mockDevice{Device: m, migDevices: migs}
+}
+
+// CreateMIGInstance creates the MIG device of the profile (e.g., "3g.20gb").
+func CreateMIGInstance(m *mock.Device, profile string) device.MigDevice {
+	return &mockMigDevice{Device: m, profile: profile}
+}
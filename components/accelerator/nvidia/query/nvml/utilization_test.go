@@ -0,0 +1,55 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetUtilization(t *testing.T) {
+	tests := []struct {
+		name     string
+		ret      nvml.Return
+		expected Utilization
+		wantErr  bool
+	}{
+		{
+			name:     "supported",
+			ret:      nvml.SUCCESS,
+			expected: Utilization{UUID: "gpu-0", GPUUsedPercent: 70, MemoryUsedPercent: 30, Supported: true},
+		},
+		{
+			name:     "not supported",
+			ret:      nvml.ERROR_NOT_SUPPORTED,
+			expected: Utilization{UUID: "gpu-0", Supported: false},
+		},
+		{
+			name:    "unknown error",
+			ret:     nvml.ERROR_UNKNOWN,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := testutil.CreateDevice(&mock.Device{
+				GetUtilizationRatesFunc: func() (nvml.Utilization, nvml.Return) {
+					return nvml.Utilization{Gpu: 70, Memory: 30}, tt.ret
+				},
+			})
+
+			util, err := GetUtilization("gpu-0", dev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetUtilization() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if util != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, util)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package nvml
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// ViolationCounters represents the cumulative durations that the GPU has been
+// throttled (in violation state) due to the power and thermal constraints.
+// The counters are monotonically increasing since the driver load,
+// thus the fraction of time spent throttled can be computed from the deltas.
+// ref. https://docs.nvidia.com/deploy/nvml-api/structnvmlViolationTime__t.html
+type ViolationCounters struct {
+	UUID string `json:"uuid"`
+
+	// Represents the cumulative time the GPU was throttled due to the power cap.
+	PowerViolationDuration time.Duration `json:"power_violation_duration"`
+	// Represents the cumulative time the GPU was throttled due to the thermal limit.
+	ThermalViolationDuration time.Duration `json:"thermal_violation_duration"`
+
+	// Supported is true if the violation counters are supported by the device.
+	Supported bool `json:"supported"`
+}
+
+// GetViolationCounters returns the cumulative throttle durations of the device.
+// It returns a non-supported result (without an error) if the device does not support the violation counters.
+func GetViolationCounters(uuid string, dev device.Device) (ViolationCounters, error) {
+	vc := ViolationCounters{
+		UUID:      uuid,
+		Supported: true,
+	}
+
+	// "ViolationTime" is the violation time in nanoseconds
+	power, ret := dev.GetViolationStatus(nvml.PERF_POLICY_POWER)
+	if IsNotSupportError(ret) {
+		vc.Supported = false
+		return vc, nil
+	}
+	// not a "not supported" error, not a success return, thus return an error here
+	if ret != nvml.SUCCESS {
+		return vc, fmt.Errorf("failed to get device power violation status: %v", nvml.ErrorString(ret))
+	}
+	vc.PowerViolationDuration = time.Duration(power.ViolationTime)
+
+	thermal, ret := dev.GetViolationStatus(nvml.PERF_POLICY_THERMAL)
+	if IsNotSupportError(ret) {
+		vc.Supported = false
+		return vc, nil
+	}
+	// not a "not supported" error, not a success return, thus return an error here
+	if ret != nvml.SUCCESS {
+		return vc, fmt.Errorf("failed to get device thermal violation status: %v", nvml.ErrorString(ret))
+	}
+	vc.ThermalViolationDuration = time.Duration(thermal.ViolationTime)
+
+	return vc, nil
+}
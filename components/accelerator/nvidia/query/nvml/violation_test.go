@@ -0,0 +1,71 @@
+package nvml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func TestGetViolationCounters(t *testing.T) {
+	tests := []struct {
+		name       string
+		powerRet   nvml.Return
+		thermalRet nvml.Return
+		want       ViolationCounters
+		wantErr    bool
+	}{
+		{
+			name:       "supported",
+			powerRet:   nvml.SUCCESS,
+			thermalRet: nvml.SUCCESS,
+			want: ViolationCounters{
+				UUID:                     "gpu-0",
+				PowerViolationDuration:   3 * time.Second,
+				ThermalViolationDuration: 5 * time.Millisecond,
+				Supported:                true,
+			},
+		},
+		{
+			name:       "power not supported",
+			powerRet:   nvml.ERROR_NOT_SUPPORTED,
+			thermalRet: nvml.SUCCESS,
+			want:       ViolationCounters{UUID: "gpu-0", Supported: false},
+		},
+		{
+			name:       "thermal unknown error",
+			powerRet:   nvml.SUCCESS,
+			thermalRet: nvml.ERROR_UNKNOWN,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := testutil.CreateDevice(&mock.Device{
+				GetViolationStatusFunc: func(perfPolicyType nvml.PerfPolicyType) (nvml.ViolationTime, nvml.Return) {
+					switch perfPolicyType {
+					case nvml.PERF_POLICY_POWER:
+						return nvml.ViolationTime{ViolationTime: uint64(3 * time.Second)}, tt.powerRet
+					case nvml.PERF_POLICY_THERMAL:
+						return nvml.ViolationTime{ViolationTime: uint64(5 * time.Millisecond)}, tt.thermalRet
+					}
+					return nvml.ViolationTime{}, nvml.ERROR_NOT_SUPPORTED
+				},
+			})
+
+			vc, err := GetViolationCounters("gpu-0", dev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetViolationCounters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if vc != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, vc)
+			}
+		})
+	}
+}
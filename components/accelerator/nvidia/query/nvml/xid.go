@@ -137,7 +137,8 @@ func (inst *instance) pollXidEvents() {
 		if !ok {
 			msg = "received event but xid unknown"
 		}
-		log.Logger.Warnw("detected xid event", "xid", xid, "message", msg)
+		// the same xid may repeat many times in a burst
+		log.Sampled(fmt.Sprintf("nvml-xid-%d", xid), time.Minute).Warnw("detected xid event", "xid", xid, "message", msg)
 
 		var deviceUUID string
 		var deviceUUIDErr error
@@ -195,9 +196,9 @@ func (inst *instance) pollXidEvents() {
 		case <-inst.rootCtx.Done():
 			return
 		case inst.xidEventCh <- event:
-			log.Logger.Warnw("notified xid event", "event", event)
+			log.Sampled(fmt.Sprintf("nvml-xid-notified-%d", xid), time.Minute).Warnw("notified xid event", "event", event)
 		default:
-			log.Logger.Warnw("xid event channel is full, skipping event")
+			log.Sampled("nvml-xid-channel-full", time.Minute).Warnw("xid event channel is full, skipping event")
 		}
 	}
 }
@@ -13,6 +13,8 @@ type Op struct {
 	hwslowdownEventsStore    events_db.Store
 	nvidiaSMICommand         string
 	nvidiaSMIQueryCommand    string
+	nvidiaSMIXMLCommand      string
+	nvidiaSMIXMLFallback     bool
 	ibstatCommand            string
 	infinibandClassDirectory string
 	debug                    bool
@@ -31,6 +33,9 @@ func (op *Op) applyOpts(opts []OpOption) error {
 	if op.nvidiaSMIQueryCommand == "" {
 		op.nvidiaSMIQueryCommand = "nvidia-smi --query"
 	}
+	if op.nvidiaSMIXMLCommand == "" {
+		op.nvidiaSMIXMLCommand = "nvidia-smi -q -x"
+	}
 	if op.ibstatCommand == "" {
 		op.ibstatCommand = "ibstat"
 	}
@@ -78,6 +83,22 @@ func WithNvidiaSMIQueryCommand(p string) OpOption {
 	}
 }
 
+// Specifies the "nvidia-smi -q -x" command to overwrite the default command.
+func WithNvidiaSMIXMLCommand(p string) OpOption {
+	return func(op *Op) {
+		op.nvidiaSMIXMLCommand = p
+	}
+}
+
+// WithNvidiaSMIXMLFallback collects the "nvidia-smi -q -x" output
+// for the basic device info, temperature, and memory when NVML is unavailable
+// (e.g., NVML fails to initialize but "nvidia-smi" works).
+func WithNvidiaSMIXMLFallback(b bool) OpOption {
+	return func(op *Op) {
+		op.nvidiaSMIXMLFallback = b
+	}
+}
+
 // Specifies the ibstat binary path to overwrite the default path.
 func WithIbstatCommand(p string) OpOption {
 	return func(op *Op) {
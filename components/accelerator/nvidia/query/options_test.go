@@ -17,6 +17,8 @@ func TestOpOptions(t *testing.T) {
 		// Check default values
 		assert.Equal(t, "nvidia-smi", op.nvidiaSMICommand)
 		assert.Equal(t, "nvidia-smi --query", op.nvidiaSMIQueryCommand)
+		assert.Equal(t, "nvidia-smi -q -x", op.nvidiaSMIXMLCommand)
+		assert.False(t, op.nvidiaSMIXMLFallback)
 		assert.Equal(t, "ibstat", op.ibstatCommand)
 		assert.Equal(t, "/sys/class/infiniband", op.infinibandClassDirectory)
 		assert.False(t, op.debug)
@@ -34,6 +36,8 @@ func TestOpOptions(t *testing.T) {
 			WithHWSlowdownEventsStore(mockStore),
 			WithNvidiaSMICommand("/custom/nvidia-smi"),
 			WithNvidiaSMIQueryCommand("/custom/nvidia-smi-query"),
+			WithNvidiaSMIXMLCommand("/custom/nvidia-smi-xml"),
+			WithNvidiaSMIXMLFallback(true),
 			WithIbstatCommand("/custom/ibstat"),
 			WithInfinibandClassDirectory("/custom/infiniband"),
 			WithDebug(true),
@@ -48,6 +52,8 @@ func TestOpOptions(t *testing.T) {
 		assert.Equal(t, mockStore, op.hwslowdownEventsStore)
 		assert.Equal(t, "/custom/nvidia-smi", op.nvidiaSMICommand)
 		assert.Equal(t, "/custom/nvidia-smi-query", op.nvidiaSMIQueryCommand)
+		assert.Equal(t, "/custom/nvidia-smi-xml", op.nvidiaSMIXMLCommand)
+		assert.True(t, op.nvidiaSMIXMLFallback)
 		assert.Equal(t, "/custom/ibstat", op.ibstatCommand)
 		assert.Equal(t, "/custom/infiniband", op.infinibandClassDirectory)
 		assert.True(t, op.debug)
@@ -18,6 +18,7 @@ import (
 	metrics_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/ecc"
 	metrics_memory "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/memory"
 	metrics_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/nvlink"
+	metrics_pcie "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/pcie"
 	metrics_power "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/power"
 	metrics_processes "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/processes"
 	metrics_remapped_rows "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/remapped-rows"
@@ -47,8 +48,9 @@ func SetDefaultPoller(opts ...OpOption) {
 		defaultPoller = query.New(
 			"shared-nvidia-poller",
 			query_config.Config{
-				Interval:  metav1.Duration{Duration: query_config.DefaultPollInterval},
-				QueueSize: query_config.DefaultQueueSize,
+				Interval:   metav1.Duration{Duration: query_config.DefaultPollInterval},
+				GetTimeout: metav1.Duration{Duration: query_config.GetDefaultGetTimeout()},
+				QueueSize:  query_config.DefaultQueueSize,
 				State: &query_config.State{
 					Retention: metav1.Duration{Duration: query_config.DefaultStateRetention},
 				},
@@ -90,7 +92,10 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 		return nil, fmt.Errorf("failed to apply options: %w", err)
 	}
 
-	if err := nvml.StartDefaultInstance(
+	p, err := file.LocateExecutable(strings.Split(op.nvidiaSMICommand, " ")[0])
+	smiExists := err == nil && p != ""
+
+	nvmlErr := nvml.StartDefaultInstance(
 		ctx,
 		nvml.WithDBRW(op.dbRW), // to deprecate in favor of events store
 		nvml.WithDBRO(op.dbRO), // to deprecate in favor of events store
@@ -107,13 +112,14 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 			go_nvml.GPM_METRIC_FP32_UTIL,
 			go_nvml.GPM_METRIC_FP16_UTIL,
 		),
-	); err != nil {
-		return nil, fmt.Errorf("failed to start nvml instance: %w", err)
+	)
+	if nvmlErr != nil {
+		if !op.nvidiaSMIXMLFallback || !smiExists {
+			return nil, fmt.Errorf("failed to start nvml instance: %w", nvmlErr)
+		}
+		log.Logger.Warnw("failed to start nvml instance -- falling back to nvidia-smi xml", "error", nvmlErr)
 	}
 
-	p, err := file.LocateExecutable(strings.Split(op.nvidiaSMICommand, " ")[0])
-	smiExists := err == nil && p != ""
-
 	p, err = file.LocateExecutable(strings.Split(op.ibstatCommand, " ")[0])
 	ibstatExists := err == nil && p != ""
 
@@ -121,6 +127,7 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 
 	o := &Output{
 		Time:                  time.Now().UTC(),
+		DataSource:            DataSourceNVML,
 		SMIExists:             smiExists,
 		FabricManagerExists:   FabricManagerExists(),
 		InfinibandClassExists: ibClassCount > 0,
@@ -214,23 +221,28 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 		o.LsmodPeermemErrors = append(o.LsmodPeermemErrors, err.Error())
 	}
 
-	log.Logger.Debugw("waiting for default nvml instance")
-	select {
-	case <-ctx.Done():
-		return o, fmt.Errorf("context canceled waiting for nvml instance: %w", ctx.Err())
-	case <-nvml.DefaultInstanceReady():
-		log.Logger.Debugw("default nvml instance ready")
-	}
-
-	// TODO
-	// this may timeout when the GPU is broken
-	// e.g.,
-	// "nvAssertOkFailedNoLog: Assertion failed: Call timed out [NV_ERR_TIMEOUT]"
-	o.NVML, err = nvml.DefaultInstance().Get()
-	if err != nil {
-		log.Logger.Warnw("nvml get failed", "error", err)
-		o.NVMLErrors = append(o.NVMLErrors, err.Error())
+	if nvmlErr != nil {
+		o.NVMLErrors = append(o.NVMLErrors, fmt.Sprintf("failed to start nvml instance: %v", nvmlErr))
 	} else {
+		log.Logger.Debugw("waiting for default nvml instance")
+		select {
+		case <-ctx.Done():
+			return o, fmt.Errorf("context canceled waiting for nvml instance: %w", ctx.Err())
+		case <-nvml.DefaultInstanceReady():
+			log.Logger.Debugw("default nvml instance ready")
+		}
+
+		// TODO
+		// this may timeout when the GPU is broken
+		// e.g.,
+		// "nvAssertOkFailedNoLog: Assertion failed: Call timed out [NV_ERR_TIMEOUT]"
+		o.NVML, nvmlErr = nvml.DefaultInstance().Get()
+		if nvmlErr != nil {
+			log.Logger.Warnw("nvml get failed", "error", nvmlErr)
+			o.NVMLErrors = append(o.NVMLErrors, nvmlErr.Error())
+		}
+	}
+	if nvmlErr == nil {
 		now := time.Now().UTC()
 		nowUnix := float64(now.Unix())
 
@@ -244,6 +256,7 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 		metrics_utilization.SetLastUpdateUnixSeconds(nowUnix)
 		metrics_processes.SetLastUpdateUnixSeconds(nowUnix)
 		metrics_remapped_rows.SetLastUpdateUnixSeconds(nowUnix)
+		metrics_pcie.SetLastUpdateUnixSeconds(nowUnix)
 
 		for _, dev := range o.NVML.DeviceInfos {
 			if err := setMetricsForDevice(ctx, dev, now, o); err != nil {
@@ -267,10 +280,17 @@ func Get(ctx context.Context, opts ...OpOption) (output any, err error) {
 	if o.SMIExists {
 		// call this with a timeout, as a broken GPU may block the command.
 		cctx, ccancel := context.WithTimeout(ctx, 2*time.Minute)
-		o.SMI, err = GetSMIOutput(cctx,
-			[]string{op.nvidiaSMICommand},
-			[]string{op.nvidiaSMIQueryCommand},
-		)
+		if nvmlErr != nil && op.nvidiaSMIXMLFallback {
+			// NVML is unavailable, so use the XML output of "nvidia-smi -q -x"
+			// for the basic device info, temperature, and memory
+			o.DataSource = DataSourceSMIXML
+			o.SMI, err = GetSMIXMLOutput(cctx, []string{op.nvidiaSMIXMLCommand})
+		} else {
+			o.SMI, err = GetSMIOutput(cctx,
+				[]string{op.nvidiaSMICommand},
+				[]string{op.nvidiaSMIQueryCommand},
+			)
+		}
 		ccancel()
 		if err != nil {
 			o.SMIQueryErrors = append(o.SMIQueryErrors, err.Error())
@@ -330,6 +350,11 @@ type Output struct {
 	// Time is the time when the query is executed.
 	Time time.Time `json:"time"`
 
+	// DataSource is the source of the device data:
+	// "nvml" by default, or "nvidia-smi-xml" if NVML is unavailable
+	// and the "nvidia-smi -q -x" output is used as fallback (see WithNvidiaSMIXMLFallback).
+	DataSource string `json:"data_source,omitempty"`
+
 	// GPU device count from the /dev directory.
 	GPUDeviceCount int `json:"gpu_device_count"`
 
@@ -614,6 +639,10 @@ func setMetricsForDevice(ctx context.Context, dev *nvml.DeviceInfo, now time.Tim
 			return err
 		}
 	}
+	if dev.ViolationCounters.Supported {
+		metrics_clock.SetPowerViolationDuration(dev.UUID, dev.ViolationCounters.PowerViolationDuration)
+		metrics_clock.SetThermalViolationDuration(dev.UUID, dev.ViolationCounters.ThermalViolationDuration)
+	}
 
 	if err := setClockSpeedMetrics(ctx, dev, now); err != nil {
 		return err
@@ -651,6 +680,10 @@ func setMetricsForDevice(ctx context.Context, dev *nvml.DeviceInfo, now time.Tim
 		return err
 	}
 
+	if err := setPCIeMetrics(ctx, dev, now); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -671,9 +704,24 @@ func setClockSpeedMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Ti
 	if err := metrics_clockspeed.SetGraphicsMHz(ctx, dev.UUID, dev.ClockSpeed.GraphicsMHz, now); err != nil {
 		return err
 	}
+	if dev.ClockSpeed.ClockSMSupported {
+		if err := metrics_clockspeed.SetSMMHz(ctx, dev.UUID, dev.ClockSpeed.SMMHz, now); err != nil {
+			return err
+		}
+	}
 	if err := metrics_clockspeed.SetMemoryMHz(ctx, dev.UUID, dev.ClockSpeed.MemoryMHz, now); err != nil {
 		return err
 	}
+
+	if dev.ClockSpeed.GraphicsMaxMHz > 0 {
+		metrics_clockspeed.SetGraphicsMaxMHz(dev.UUID, dev.ClockSpeed.GraphicsMaxMHz)
+	}
+	if dev.ClockSpeed.SMMaxMHz > 0 {
+		metrics_clockspeed.SetSMMaxMHz(dev.UUID, dev.ClockSpeed.SMMaxMHz)
+	}
+	if dev.ClockSpeed.MemoryMaxMHz > 0 {
+		metrics_clockspeed.SetMemoryMaxMHz(dev.UUID, dev.ClockSpeed.MemoryMaxMHz)
+	}
 	return nil
 }
 
@@ -690,6 +738,24 @@ func setECCMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Time) err
 	if err := metrics_ecc.SetVolatileTotalUncorrected(ctx, dev.UUID, float64(dev.ECCErrors.Volatile.Total.Uncorrected), now); err != nil {
 		return err
 	}
+
+	// the device memory counts are only queried with the ECC mode enabled (see "GetECCErrors"),
+	// skip the other devices, rather than reporting zero counts
+	if !dev.ECCErrors.Supported || !dev.ECCMode.EnabledCurrent {
+		return nil
+	}
+	if err := metrics_ecc.SetVolatileSingleBit(ctx, dev.UUID, float64(dev.ECCErrors.Volatile.GPUDeviceMemory.Corrected), now); err != nil {
+		return err
+	}
+	if err := metrics_ecc.SetVolatileDoubleBit(ctx, dev.UUID, float64(dev.ECCErrors.Volatile.GPUDeviceMemory.Uncorrected), now); err != nil {
+		return err
+	}
+	if err := metrics_ecc.SetAggregateSingleBit(ctx, dev.UUID, float64(dev.ECCErrors.Aggregate.GPUDeviceMemory.Corrected), now); err != nil {
+		return err
+	}
+	if err := metrics_ecc.SetAggregateDoubleBit(ctx, dev.UUID, float64(dev.ECCErrors.Aggregate.GPUDeviceMemory.Uncorrected), now); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -710,6 +776,23 @@ func setMemoryMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Time,
 			return err
 		}
 	}
+
+	migIDs := make([]string, 0, len(dev.Memory.MIGInstances))
+	for _, mig := range dev.Memory.MIGInstances {
+		migIDs = append(migIDs, mig.UUID)
+	}
+	metrics_memory.DeleteStaleMIG(dev.UUID, migIDs)
+
+	for _, mig := range dev.Memory.MIGInstances {
+		metrics_memory.SetMIGTotalBytes(dev.UUID, mig.UUID, float64(mig.TotalBytes))
+		metrics_memory.SetMIGUsedBytes(dev.UUID, mig.UUID, float64(mig.UsedBytes))
+		migUsedPercent, err := mig.GetUsedPercent()
+		if err != nil {
+			o.NVMLErrors = append(o.NVMLErrors, err.Error())
+			continue
+		}
+		metrics_memory.SetMIGUsedPercent(dev.UUID, mig.UUID, migUsedPercent)
+	}
 	return nil
 }
 
@@ -726,6 +809,12 @@ func setNVLinkMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Time)
 	if err := metrics_nvlink.SetCRCErrors(ctx, dev.UUID, dev.NVLink.States.TotalCRCErrors(), now); err != nil {
 		return err
 	}
+	for _, state := range dev.NVLink.States {
+		metrics_nvlink.SetLinkErrors(dev.UUID, state.Link, metrics_nvlink.LinkErrorTypeReplay, state.ReplayErrors)
+		metrics_nvlink.SetLinkErrors(dev.UUID, state.Link, metrics_nvlink.LinkErrorTypeRecovery, state.RecoveryErrors)
+		metrics_nvlink.SetLinkErrors(dev.UUID, state.Link, metrics_nvlink.LinkErrorTypeCRCFlit, state.CRCErrors)
+		metrics_nvlink.SetLinkErrors(dev.UUID, state.Link, metrics_nvlink.LinkErrorTypeCRCData, state.CRCDataErrors)
+	}
 	if err := metrics_nvlink.SetRxBytes(ctx, dev.UUID, float64(dev.NVLink.States.TotalThroughputRawRxBytes()), now); err != nil {
 		return err
 	}
@@ -760,6 +849,7 @@ func setTemperatureMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.T
 	if err := metrics_temperature.SetThresholdSlowdownCelsius(ctx, dev.UUID, float64(dev.Temperature.ThresholdCelsiusSlowdown), now); err != nil {
 		return err
 	}
+	metrics_temperature.SetThresholdShutdownCelsius(dev.UUID, float64(dev.Temperature.ThresholdCelsiusShutdown))
 	usedPercent, err := dev.Temperature.GetUsedPercentSlowdown()
 	if err != nil {
 		o.NVMLErrors = append(o.NVMLErrors, err.Error())
@@ -772,6 +862,10 @@ func setTemperatureMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.T
 }
 
 func setUtilizationMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Time) error {
+	// skip the devices without utilization support, rather than reporting zero percents
+	if !dev.Utilization.Supported {
+		return nil
+	}
 	if err := metrics_utilization.SetGPUUtilPercent(ctx, dev.UUID, dev.Utilization.GPUUsedPercent, now); err != nil {
 		return err
 	}
@@ -800,3 +894,23 @@ func setRemappedRowsMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.
 	}
 	return nil
 }
+
+func setPCIeMetrics(ctx context.Context, dev *nvml.DeviceInfo, now time.Time) error {
+	// skip the devices without the pcie link info, rather than reporting zero widths
+	if !dev.PCIeLink.Supported {
+		return nil
+	}
+	if err := metrics_pcie.SetCurrentLinkWidth(ctx, dev.UUID, dev.PCIeLink.CurrentLinkWidth, now); err != nil {
+		return err
+	}
+	if err := metrics_pcie.SetMaxLinkWidth(ctx, dev.UUID, dev.PCIeLink.MaxLinkWidth, now); err != nil {
+		return err
+	}
+	if err := metrics_pcie.SetCurrentLinkGeneration(ctx, dev.UUID, dev.PCIeLink.CurrentLinkGeneration, now); err != nil {
+		return err
+	}
+	if err := metrics_pcie.SetMaxLinkGeneration(ctx, dev.UUID, dev.PCIeLink.MaxLinkGeneration, now); err != nil {
+		return err
+	}
+	return nil
+}
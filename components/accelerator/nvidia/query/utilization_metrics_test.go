@@ -0,0 +1,108 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	metrics_utilization "github.com/leptonai/gpud/components/accelerator/nvidia/query/metrics/utilization"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestSetUtilizationMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	tableName := "test_metrics"
+	if err := components_metrics_state.CreateTableMetrics(ctx, dbRW, tableName); err != nil {
+		t.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	if err := metrics_utilization.Register(reg, dbRW, dbRO, tableName); err != nil {
+		t.Fatal(err)
+	}
+
+	newDevice := func(gpu, memory uint32, ret nvml.Return) device.Device {
+		return testutil.CreateDevice(&mock.Device{
+			GetUtilizationRatesFunc: func() (nvml.Utilization, nvml.Return) {
+				return nvml.Utilization{Gpu: gpu, Memory: memory}, ret
+			},
+		})
+	}
+	devs := map[string]device.Device{
+		"GPU-0": newDevice(90, 40, nvml.SUCCESS),
+		"GPU-1": newDevice(10, 5, nvml.SUCCESS),
+		"GPU-2": newDevice(0, 0, nvml.ERROR_NOT_SUPPORTED),
+	}
+	now := time.Now().UTC()
+	for uuid, dev := range devs {
+		util, err := nvidia_query_nvml.GetUtilization(uuid, dev)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", uuid, err)
+		}
+		if err := setUtilizationMetrics(ctx, &nvidia_query_nvml.DeviceInfo{UUID: uuid, Utilization: util}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			if len(m.GetLabel()) != 1 { // skip the averages with the period labels
+				continue
+			}
+			for _, l := range m.GetLabel() {
+				if l.GetName() != "gpu_id" {
+					continue
+				}
+				if values[mf.GetName()] == nil {
+					values[mf.GetName()] = make(map[string]float64)
+				}
+				values[mf.GetName()][l.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+
+	expected := map[string]map[string]float64{
+		"gpu_util_percent":    {"GPU-0": 90, "GPU-1": 10},
+		"memory_util_percent": {"GPU-0": 40, "GPU-1": 5},
+	}
+	for name, want := range expected {
+		got := values[metrics_utilization.SubSystem+"_"+name]
+		if len(got) != len(want) {
+			t.Errorf("%s: expected metrics for %v, got %v", name, want, got)
+			continue
+		}
+		for id, v := range want {
+			if got[id] != v {
+				t.Errorf("%s: expected %v for %s, got %v", name, v, id, got[id])
+			}
+		}
+		if _, ok := got["GPU-2"]; ok {
+			t.Errorf("%s: expected the unsupported GPU-2 skipped", name)
+		}
+	}
+
+	ms, err := metrics_utilization.ReadGPUUtilPercents(ctx, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 {
+		t.Errorf("expected GPU utilization observations for the supported GPUs, got %+v", ms)
+	}
+}
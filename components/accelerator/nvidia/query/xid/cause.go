@@ -0,0 +1,123 @@
+package xid
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CauseFlags is the bitset of the potential causes of an Xid,
+// as defined in https://docs.nvidia.com/deploy/xid-errors/index.html#xid-error-listing.
+type CauseFlags uint8
+
+const (
+	CauseHWError CauseFlags = 1 << iota
+	CauseDriverError
+	CauseUserAppError
+	CauseSystemMemoryCorruption
+	CauseBusError
+	CauseThermalIssue
+	CauseFBCorruption
+)
+
+// causeNames is in the order of the flag bits, to render the flags stably.
+var causeNames = []struct {
+	flag CauseFlags
+	name string
+}{
+	{CauseHWError, "hw_error"},
+	{CauseDriverError, "driver_error"},
+	{CauseUserAppError, "user_app_error"},
+	{CauseSystemMemoryCorruption, "system_memory_corruption"},
+	{CauseBusError, "bus_error"},
+	{CauseThermalIssue, "thermal_issue"},
+	{CauseFBCorruption, "fb_corruption"},
+}
+
+// PotentialCauses returns the potential causes of the Xid as a bitset.
+func (d Detail) PotentialCauses() CauseFlags {
+	var f CauseFlags
+	if d.PotentialHWError {
+		f |= CauseHWError
+	}
+	if d.PotentialDriverError {
+		f |= CauseDriverError
+	}
+	if d.PotentialUserAppError {
+		f |= CauseUserAppError
+	}
+	if d.PotentialSystemMemoryCorruption {
+		f |= CauseSystemMemoryCorruption
+	}
+	if d.PotentialBusError {
+		f |= CauseBusError
+	}
+	if d.PotentialThermalIssue {
+		f |= CauseThermalIssue
+	}
+	if d.PotentialFBCorruption {
+		f |= CauseFBCorruption
+	}
+	return f
+}
+
+// Has returns true if all the given flags are set.
+func (f CauseFlags) Has(flags CauseFlags) bool {
+	return flags != 0 && f&flags == flags
+}
+
+// Any returns true if any potential cause is set.
+func (f CauseFlags) Any() bool {
+	return f != 0
+}
+
+// Names returns the names of the set flags in the order of the flag bits
+// (e.g., ["hw_error", "driver_error"]).
+func (f CauseFlags) Names() []string {
+	names := []string{}
+	for _, c := range causeNames {
+		if f.Has(c.flag) {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// String returns the names of the set flags joined by "|"
+// (e.g., "hw_error|driver_error"), or "none" if no flag is set.
+func (f CauseFlags) String() string {
+	if !f.Any() {
+		return "none"
+	}
+	return strings.Join(f.Names(), "|")
+}
+
+// MarshalJSON encodes the flags as the list of the names.
+func (f CauseFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Names())
+}
+
+// UnmarshalJSON decodes the flags from the list of the names.
+func (f *CauseFlags) UnmarshalJSON(b []byte) error {
+	var names []string
+	if err := json.Unmarshal(b, &names); err != nil {
+		return err
+	}
+
+	var flags CauseFlags
+	for _, name := range names {
+		found := false
+		for _, c := range causeNames {
+			if c.name == name {
+				flags |= c.flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown xid cause %q", name)
+		}
+	}
+	*f = flags
+	return nil
+}
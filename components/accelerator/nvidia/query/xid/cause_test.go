@@ -0,0 +1,99 @@
+package xid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDetailPotentialCauses(t *testing.T) {
+	d, ok := GetDetail(13)
+	if !ok {
+		t.Fatal("xid 13 not found")
+	}
+	causes := d.PotentialCauses()
+	for _, f := range []CauseFlags{CauseHWError, CauseDriverError, CauseUserAppError} {
+		if !causes.Has(f) {
+			t.Errorf("expected xid 13 causes %s to include %s", causes, f)
+		}
+	}
+	if !causes.Has(CauseHWError | CauseDriverError | CauseUserAppError) {
+		t.Errorf("expected xid 13 causes %s to include all of hw/driver/user app errors", causes)
+	}
+	if !causes.Any() {
+		t.Error("expected xid 13 to have causes")
+	}
+
+	for _, detail := range GetAllDetails() {
+		causes := detail.PotentialCauses()
+		if causes.Has(CauseHWError) != detail.PotentialHWError ||
+			causes.Has(CauseDriverError) != detail.PotentialDriverError ||
+			causes.Has(CauseUserAppError) != detail.PotentialUserAppError ||
+			causes.Has(CauseSystemMemoryCorruption) != detail.PotentialSystemMemoryCorruption ||
+			causes.Has(CauseBusError) != detail.PotentialBusError ||
+			causes.Has(CauseThermalIssue) != detail.PotentialThermalIssue ||
+			causes.Has(CauseFBCorruption) != detail.PotentialFBCorruption {
+			t.Errorf("xid %d: causes %s do not match the detail", detail.Xid, causes)
+		}
+		if (causes == CauseHWError) != detail.IsOnlyHWError() {
+			t.Errorf("xid %d: causes %s do not match IsOnlyHWError", detail.Xid, causes)
+		}
+	}
+}
+
+func TestCauseFlagsString(t *testing.T) {
+	tests := []struct {
+		flags CauseFlags
+		want  string
+	}{
+		{0, "none"},
+		{CauseHWError, "hw_error"},
+		{CauseUserAppError | CauseHWError | CauseDriverError, "hw_error|driver_error|user_app_error"},
+		{CauseFBCorruption | CauseBusError, "bus_error|fb_corruption"},
+	}
+	for _, tt := range tests {
+		// render multiple times to make sure the order is stable
+		for i := 0; i < 3; i++ {
+			if got := tt.flags.String(); got != tt.want {
+				t.Errorf("CauseFlags(%d).String() = %q, want %q", tt.flags, got, tt.want)
+			}
+		}
+	}
+
+	if CauseFlags(0).Has(0) {
+		t.Error("expected no flags to have no flags")
+	}
+	if (CauseHWError).Has(CauseHWError | CauseDriverError) {
+		t.Error("expected hw error not to have both hw and driver errors")
+	}
+}
+
+func TestCauseFlagsJSON(t *testing.T) {
+	flags := CauseHWError | CauseThermalIssue
+	b, err := json.Marshal(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `["hw_error","thermal_issue"]` {
+		t.Errorf("unexpected json %s", b)
+	}
+
+	var decoded CauseFlags
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != flags {
+		t.Errorf("expected %s, got %s", flags, decoded)
+	}
+
+	b, err = json.Marshal(CauseFlags(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[]` {
+		t.Errorf("unexpected json %s", b)
+	}
+
+	if err := json.Unmarshal([]byte(`["unknown"]`), &decoded); err == nil {
+		t.Error("expected error for unknown cause")
+	}
+}
@@ -4,6 +4,7 @@ package xid
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/leptonai/gpud/components/common"
 )
@@ -51,6 +52,10 @@ type Detail struct {
 	// PotentialFBCorruption is true if the Xid indicates a potential framebuffer corruption.
 	// Source: https://docs.nvidia.com/deploy/xid-errors/index.html#xid-error-listing
 	PotentialFBCorruption bool `json:"potential_fb_corruption"`
+
+	// IsSynthetic is true if the Xid is not in the known list
+	// and the detail is synthesized by GetDetailOrDefault.
+	IsSynthetic bool `json:"is_synthetic,omitempty"`
 }
 
 func (d Detail) JSON() ([]byte, error) {
@@ -59,41 +64,17 @@ func (d Detail) JSON() ([]byte, error) {
 
 // if nvidia says only possible reason is hw, then we do hard inspections directly
 func (d Detail) IsOnlyHWError() bool {
-	if !d.PotentialHWError {
-		return false
-	}
-	return !d.PotentialDriverError &&
-		!d.PotentialUserAppError &&
-		!d.PotentialSystemMemoryCorruption &&
-		!d.PotentialBusError &&
-		!d.PotentialThermalIssue &&
-		!d.PotentialFBCorruption
+	return d.PotentialCauses() == CauseHWError
 }
 
 // if nvidia says this can be only because of user error, then we ignore, don’t mark it as critical
 func (d Detail) IsOnlyUserAppError() bool {
-	if !d.PotentialUserAppError {
-		return false
-	}
-	return !d.PotentialHWError &&
-		!d.PotentialDriverError &&
-		!d.PotentialSystemMemoryCorruption &&
-		!d.PotentialBusError &&
-		!d.PotentialThermalIssue &&
-		!d.PotentialFBCorruption
+	return d.PotentialCauses() == CauseUserAppError
 }
 
 // if nvidia says this can be only because of driver error, then we only reboot
 func (d Detail) IsOnlyDriverError() bool {
-	if !d.PotentialDriverError {
-		return false
-	}
-	return !d.PotentialHWError &&
-		!d.PotentialUserAppError &&
-		!d.PotentialSystemMemoryCorruption &&
-		!d.PotentialBusError &&
-		!d.PotentialThermalIssue &&
-		!d.PotentialFBCorruption
+	return d.PotentialCauses() == CauseDriverError
 }
 
 // IsMarkedAsCriticalByGPUd returns true if the GPUd marks this Xid as a critical error.
@@ -108,6 +89,76 @@ func GetDetail(id int) (*Detail, bool) {
 	return &e, ok
 }
 
+// GetDetailOrDefault returns the Xid detail if found.
+// Otherwise, returns the synthesized warning detail (marked as IsSynthetic),
+// so that the Xids unknown to GPUd (e.g., introduced by the newer drivers)
+// are still reported and monitored, rather than silently dropped.
+func GetDetailOrDefault(id int) Detail {
+	if d, ok := details[id]; ok {
+		return d
+	}
+	return Detail{
+		Xid:         id,
+		Name:        fmt.Sprintf("Unknown Xid %d", id),
+		Description: "unknown XID, monitor",
+
+		EventType: common.EventTypeWarning,
+
+		IsSynthetic: true,
+	}
+}
+
+// GetAllDetails returns all the Xid details in the ascending order of Xid.
+func GetAllDetails() []Detail {
+	ret := make([]Detail, 0, len(details))
+	for _, id := range sortedIDs() {
+		ret = append(ret, details[id])
+	}
+	return ret
+}
+
+// GetXidsByRepairAction returns the sorted list of Xids
+// whose suggested repair actions by GPUd include the given action
+// (e.g., all Xids requiring hardware inspection).
+func GetXidsByRepairAction(action common.RepairActionType) []int {
+	ids := []int{}
+	for _, id := range sortedIDs() {
+		d := details[id]
+		if d.SuggestedActionsByGPUd == nil {
+			continue
+		}
+		for _, a := range d.SuggestedActionsByGPUd.RepairActions {
+			if a == action {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// GetCriticalXids returns the sorted list of Xids marked as critical by GPUd.
+func GetCriticalXids() []int {
+	ids := []int{}
+	for _, id := range sortedIDs() {
+		if details[id].CriticalErrorMarkedByGPUd {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// sortedIDs returns the Xids in the ascending order,
+// to iterate the details map deterministically.
+func sortedIDs() []int {
+	ids := make([]int, 0, len(details))
+	for id := range details {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 // make sure we do not have unknown event type
 func init() {
 	for id, detail := range details {
@@ -0,0 +1,45 @@
+package xid
+
+import (
+	"github.com/leptonai/gpud/components/common"
+)
+
+// SuggestedActionsForXid95 returns the suggested actions for Xid 95 (uncontained ECC error)
+// based on the MIG mode of the GPU reporting the Xid, as the official guideline differs:
+// if MIG is enabled, drain any work on the other GPU instances and reset the GPU,
+// and if MIG is disabled, reboot the node immediately.
+// ref. https://docs.nvidia.com/deploy/gpu-debug-guidelines/index.html#xid-messages
+//
+// Use the static Xid 95 detail (see GetDetail) if the MIG mode is unknown.
+func SuggestedActionsForXid95(migEnabled bool) *common.SuggestedActions {
+	var references []string
+	if d, ok := GetDetail(95); ok && d.SuggestedActionsByGPUd != nil {
+		references = append(references, d.SuggestedActionsByGPUd.References...)
+	}
+
+	if migEnabled {
+		return &common.SuggestedActions{
+			References: references,
+			Descriptions: []string{
+				"Xid 95, marked as critical in GPUd, indicates uncontained ECC errors with row-remapping, failing to suppress the errors. MIG is enabled, thus drain any work on the other GPU instances, wait for all work to complete, and reset the GPU reporting the Xid.",
+				"Xid 95, marked as critical in GPUd, indicates uncontained ECC errors with row-remapping, failing to suppress the errors. If the same Xid is reported again after resetting the GPU, the GPU hardware should be inspected and repaired.",
+			},
+			RepairActions: []common.RepairActionType{
+				common.RepairActionTypeCheckUserAppAndGPU,
+				common.RepairActionTypeHardwareInspection,
+			},
+		}
+	}
+
+	return &common.SuggestedActions{
+		References: references,
+		Descriptions: []string{
+			"Xid 95, marked as critical in GPUd, indicates uncontained ECC errors with row-remapping, failing to suppress the errors. MIG is disabled, thus the node should be rebooted immediately since there is an uncorrectable uncontained ECC error.",
+			"Xid 95, marked as critical in GPUd, indicates uncontained ECC errors with row-remapping, failing to suppress the errors. If the same Xid is reported again after rebooting the system, the GPU hardware should be inspected and repaired.",
+		},
+		RepairActions: []common.RepairActionType{
+			common.RepairActionTypeRebootSystem,
+			common.RepairActionTypeHardwareInspection,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package xid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestSuggestedActionsForXid95(t *testing.T) {
+	enabled := SuggestedActionsForXid95(true)
+	disabled := SuggestedActionsForXid95(false)
+
+	if !reflect.DeepEqual(enabled.RepairActions, []common.RepairActionType{common.RepairActionTypeCheckUserAppAndGPU, common.RepairActionTypeHardwareInspection}) {
+		t.Errorf("unexpected MIG-enabled repair actions %v", enabled.RepairActions)
+	}
+	if enabled.RequiresReboot() {
+		t.Error("expected no reboot when MIG is enabled")
+	}
+
+	if !reflect.DeepEqual(disabled.RepairActions, []common.RepairActionType{common.RepairActionTypeRebootSystem, common.RepairActionTypeHardwareInspection}) {
+		t.Errorf("unexpected MIG-disabled repair actions %v", disabled.RepairActions)
+	}
+	if !disabled.RequiresReboot() {
+		t.Error("expected reboot when MIG is disabled")
+	}
+
+	if reflect.DeepEqual(enabled.Descriptions, disabled.Descriptions) {
+		t.Error("expected different descriptions for the MIG modes")
+	}
+
+	d, ok := GetDetail(95)
+	if !ok {
+		t.Fatal("expected Xid 95 detail")
+	}
+	if !reflect.DeepEqual(enabled.References, d.SuggestedActionsByGPUd.References) {
+		t.Error("expected the references of the static Xid 95 detail")
+	}
+
+	// the static detail is unchanged for the callers without the MIG mode
+	if !reflect.DeepEqual(d.SuggestedActionsByGPUd.RepairActions, []common.RepairActionType{common.RepairActionTypeRebootSystem, common.RepairActionTypeHardwareInspection}) {
+		t.Errorf("unexpected static repair actions %v", d.SuggestedActionsByGPUd.RepairActions)
+	}
+
+	// returns a new value every call
+	enabled.RepairActions[0] = common.RepairActionTypeIgnoreNoActionRequired
+	if SuggestedActionsForXid95(true).RepairActions[0] != common.RepairActionTypeCheckUserAppAndGPU {
+		t.Error("expected a new value every call")
+	}
+}
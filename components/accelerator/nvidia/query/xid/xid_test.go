@@ -1,6 +1,9 @@
 package xid
 
 import (
+	"reflect"
+	"slices"
+	"sort"
 	"testing"
 
 	"github.com/leptonai/gpud/components/common"
@@ -215,3 +218,84 @@ func TestDetailsValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDetailOrDefault(t *testing.T) {
+	d := GetDetailOrDefault(79)
+	if d.IsSynthetic {
+		t.Error("expected Xid 79 not to be synthetic")
+	}
+	if known, _ := GetDetail(79); !reflect.DeepEqual(d, *known) {
+		t.Errorf("expected the known detail, got %+v", d)
+	}
+
+	d = GetDetailOrDefault(999)
+	if !d.IsSynthetic {
+		t.Error("expected unknown Xid 999 to be synthetic")
+	}
+	if d.Xid != 999 {
+		t.Errorf("expected Xid 999, got %d", d.Xid)
+	}
+	if d.EventType != common.EventTypeWarning {
+		t.Errorf("expected warning event type, got %s", d.EventType)
+	}
+	if d.CriticalErrorMarkedByGPUd || d.SuggestedActionsByGPUd != nil {
+		t.Errorf("expected unknown Xid not to be critical, got %+v", d)
+	}
+}
+
+func TestGetXidsByRepairAction(t *testing.T) {
+	ids := GetXidsByRepairAction(common.RepairActionTypeHardwareInspection)
+	if !sort.IntsAreSorted(ids) {
+		t.Errorf("expected sorted Xids, got %v", ids)
+	}
+
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	for _, id := range []int{32, 38, 44} {
+		if !set[id] {
+			t.Errorf("expected Xid %d to require hardware inspection", id)
+		}
+	}
+	if set[13] {
+		t.Error("expected Xid 13 not to require hardware inspection")
+	}
+
+	if ids := GetXidsByRepairAction(common.RepairActionType("UNKNOWN")); len(ids) != 0 {
+		t.Errorf("expected no Xid for unknown action, got %v", ids)
+	}
+}
+
+func TestGetCriticalXids(t *testing.T) {
+	ids := GetCriticalXids()
+	if !sort.IntsAreSorted(ids) {
+		t.Errorf("expected sorted Xids, got %v", ids)
+	}
+	for _, id := range ids {
+		d, ok := GetDetail(id)
+		if !ok || !d.CriticalErrorMarkedByGPUd {
+			t.Errorf("expected Xid %d to be marked as critical", id)
+		}
+	}
+	for _, id := range []int{32, 38, 44} {
+		if !slices.Contains(ids, id) {
+			t.Errorf("expected Xid %d to be critical", id)
+		}
+	}
+	if slices.Contains(ids, 13) {
+		t.Error("expected Xid 13 not to be critical")
+	}
+}
+
+func TestGetAllDetails(t *testing.T) {
+	all := GetAllDetails()
+	if len(all) != len(details) {
+		t.Fatalf("expected %d details, got %d", len(details), len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Xid >= all[i].Xid {
+			t.Fatalf("expected ascending Xids, got %d before %d", all[i-1].Xid, all[i].Xid)
+		}
+	}
+}
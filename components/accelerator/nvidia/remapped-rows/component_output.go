@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/leptonai/gpud/components"
@@ -25,6 +26,11 @@ func ToOutput(i *nvidia_query.Output) *Output {
 
 	rmaMsgs := make([]string, 0)
 	needRebootMsgs := make([]string, 0)
+	needResetMsgs := make([]string, 0)
+
+	// PCI bus IDs of the GPUs that need reset and support the secondary bus reset,
+	// to not recommend the system reboot for the same GPUs reported by nvidia-smi
+	sbrResetBusIDs := make(map[string]struct{})
 
 	if i.NVML != nil {
 		for _, device := range i.NVML.DeviceInfos {
@@ -33,7 +39,14 @@ func ToOutput(i *nvidia_query.Output) *Output {
 			requiresReset := device.RemappedRows.RequiresReset()
 			if requiresReset {
 				msg := fmt.Sprintf("NVML indicates GPU %s needs reset (pending remapping %v)", device.UUID, requiresReset)
-				needRebootMsgs = append(needRebootMsgs, msg)
+				if device.SecondaryBusResetSupported {
+					// the GPU can be reset via the secondary bus reset (SBR), without the full system reboot
+					msg += fmt.Sprintf(" -- GPU supports secondary bus reset (PCI bus ID %s), thus reset the GPU instead of rebooting the system", device.PCIBusID)
+					needResetMsgs = append(needResetMsgs, msg)
+					sbrResetBusIDs[normalizePCIBusID(device.PCIBusID)] = struct{}{}
+				} else {
+					needRebootMsgs = append(needRebootMsgs, msg)
+				}
 			}
 
 			rma := device.RemappedRows.QualifiesForRMA()
@@ -62,8 +75,10 @@ func ToOutput(i *nvidia_query.Output) *Output {
 				continue
 			}
 			if requiresReset {
-				msg := fmt.Sprintf("nvidia-smi indicates GPU %q needs reset (pending remapping %v)", parsed.ID, requiresReset)
-				needRebootMsgs = append(needRebootMsgs, msg)
+				if _, ok := sbrResetBusIDs[normalizePCIBusID(parsed.ID)]; !ok {
+					msg := fmt.Sprintf("nvidia-smi indicates GPU %q needs reset (pending remapping %v)", parsed.ID, requiresReset)
+					needRebootMsgs = append(needRebootMsgs, msg)
+				}
 			}
 
 			rma, err := parsed.QualifiesForRMA()
@@ -86,6 +101,14 @@ func ToOutput(i *nvidia_query.Output) *Output {
 		o.SuggestedActions.Descriptions = append(o.SuggestedActions.Descriptions, strings.Join(needRebootMsgs, ", "))
 		o.SuggestedActions.RepairActions = append(o.SuggestedActions.RepairActions, common.RepairActionTypeRebootSystem)
 	}
+	if len(needResetMsgs) > 0 {
+		if o.SuggestedActions == nil {
+			o.SuggestedActions = &common.SuggestedActions{}
+		}
+
+		o.SuggestedActions.Descriptions = append(o.SuggestedActions.Descriptions, strings.Join(needResetMsgs, ", "))
+		o.SuggestedActions.RepairActions = append(o.SuggestedActions.RepairActions, common.RepairActionTypeResetGPU)
+	}
 	if len(rmaMsgs) > 0 {
 		if o.SuggestedActions == nil {
 			o.SuggestedActions = &common.SuggestedActions{}
@@ -98,6 +121,17 @@ func ToOutput(i *nvidia_query.Output) *Output {
 	return o
 }
 
+// normalizePCIBusID returns the PCI bus ID without the domain in lower case,
+// since NVML and nvidia-smi format the domain differently
+// (e.g., "00000000:3B:00.0" and "0000:3b:00.0" both return "3b:00.0").
+func normalizePCIBusID(id string) string {
+	id = strings.ToLower(id)
+	if strings.Count(id, ":") == 2 {
+		id = id[strings.Index(id, ":")+1:]
+	}
+	return id
+}
+
 type Output struct {
 	GPUProductName                    string                                         `json:"gpu_product_name"`
 	MemoryErrorManagementCapabilities nvidia_query.MemoryErrorManagementCapabilities `json:"memory_error_management_capabilities"`
@@ -129,6 +163,18 @@ const (
 	StateValueRemappedRowsEncodingJSON = "json"
 )
 
+const (
+	// Summarizes the row remapping status from NVML across all the GPUs.
+	StateNameRemapStatus = "remap_status"
+
+	// The number of GPUs with the row remappings pending.
+	StateKeyRemapStatusPendingRemaps = "pending_remaps"
+	// The number of GPUs with the row remapping failures.
+	StateKeyRemapStatusFailedRemaps = "failed_remaps"
+	// Set to "true" if any GPU requires a reset (or reboot) to apply the pending remappings.
+	StateKeyRemapStatusPendingReboot = "pending_reboot"
+)
+
 func ParseStateRemappedRows(m map[string]string) (*Output, error) {
 	data := m[StateKeyRemappedRowsData]
 	return ParseOutputJSON([]byte(data))
@@ -144,6 +190,10 @@ func ParseStatesToOutput(states ...components.State) (*Output, error) {
 			}
 			return o, nil
 
+		case StateNameRemapStatus:
+			// summary only, the data is in the remapped rows state
+			continue
+
 		default:
 			return nil, fmt.Errorf("unknown state name: %s", state.Name)
 		}
@@ -210,6 +260,67 @@ func (o *Output) Evaluate() (string, bool, error) {
 	return reason, healthy, nil
 }
 
+// EvaluateRemapStatus returns the row remapping status reason and event type
+// from the NVML remapped rows. The status is critical if any GPU has
+// a remapping pending a reboot, or a remapping failure.
+func (o *Output) EvaluateRemapStatus() (string, common.EventType) {
+	reasons := []string{}
+	eventType := common.EventTypeInfo
+	for _, r := range o.RemappedRowsNVML {
+		if !r.Supported {
+			continue
+		}
+		if r.RemappingPending {
+			reasons = append(reasons, fmt.Sprintf("GPU %s has pending row remappings that require a reboot", r.UUID))
+			eventType = common.EventTypeCritical
+		}
+		if r.RemappingFailed {
+			reasons = append(reasons, fmt.Sprintf("GPU %s has row remapping failures (remapped due to uncorrectable errors %d)", r.UUID, r.RemappedDueToUncorrectableErrors))
+			eventType = common.EventTypeCritical
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no pending or failed row remapping")
+	}
+	return strings.Join(reasons, "; "), eventType
+}
+
+func (o *Output) remapStatusState() components.State {
+	pending, failed := 0, 0
+	for _, r := range o.RemappedRowsNVML {
+		if !r.Supported {
+			continue
+		}
+		if r.RemappingPending {
+			pending++
+		}
+		if r.RemappingFailed {
+			failed++
+		}
+	}
+
+	reason, eventType := o.EvaluateRemapStatus()
+	healthy := true
+	health := components.StateHealthy
+	if eventType == common.EventTypeCritical {
+		healthy = false
+		health = components.StateUnhealthy
+	}
+
+	return components.State{
+		Name:    StateNameRemapStatus,
+		Healthy: healthy,
+		Health:  health,
+		Reason:  reason,
+		ExtraInfo: map[string]string{
+			StateKeyRemapStatusPendingRemaps: strconv.Itoa(pending),
+			StateKeyRemapStatusFailedRemaps:  strconv.Itoa(failed),
+			StateKeyRemapStatusPendingReboot: strconv.FormatBool(pending > 0),
+		},
+	}
+}
+
 func (o *Output) States() ([]components.State, error) {
 	outputReasons, healthy, err := o.Evaluate()
 	if err != nil {
@@ -231,5 +342,5 @@ func (o *Output) States() ([]components.State, error) {
 		state.SuggestedActions = o.SuggestedActions
 	}
 
-	return []components.State{state}, nil
+	return []components.State{state, o.remapStatusState()}, nil
 }
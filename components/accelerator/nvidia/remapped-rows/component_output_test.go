@@ -0,0 +1,150 @@
+package remappedrows
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestRemapStatusState(t *testing.T) {
+	tests := []struct {
+		name        string
+		corrRows    int
+		uncRows     int
+		isPending   bool
+		failed      bool
+		wantHealth  string
+		wantPending string
+		wantFailed  string
+		wantReboot  string
+		wantHealthy bool
+	}{
+		{
+			name:        "no remap",
+			wantHealthy: true,
+			wantHealth:  components.StateHealthy,
+			wantPending: "0",
+			wantFailed:  "0",
+			wantReboot:  "false",
+		},
+		{
+			name:        "pending reboot",
+			corrRows:    1,
+			isPending:   true,
+			wantHealthy: false,
+			wantHealth:  components.StateUnhealthy,
+			wantPending: "1",
+			wantFailed:  "0",
+			wantReboot:  "true",
+		},
+		{
+			name:        "failure",
+			uncRows:     8,
+			failed:      true,
+			wantHealthy: false,
+			wantHealth:  components.StateUnhealthy,
+			wantPending: "0",
+			wantFailed:  "1",
+			wantReboot:  "false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := testutil.CreateDevice(&mock.Device{
+				GetRemappedRowsFunc: func() (int, int, bool, bool, nvml.Return) {
+					return tt.corrRows, tt.uncRows, tt.isPending, tt.failed, nvml.SUCCESS
+				},
+			})
+			rr, err := nvidia_query_nvml.GetRemappedRows("GPU-0", dev)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			o := ToOutput(&nvidia_query.Output{
+				NVML: &nvidia_query_nvml.Output{
+					Exists:      true,
+					DeviceInfos: []*nvidia_query_nvml.DeviceInfo{{UUID: "GPU-0", RemappedRows: rr}},
+				},
+			})
+			states, err := o.States()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found *components.State
+			for i := range states {
+				if states[i].Name == StateNameRemapStatus {
+					found = &states[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("state %q not found in %+v", StateNameRemapStatus, states)
+			}
+			if found.Healthy != tt.wantHealthy || found.Health != tt.wantHealth {
+				t.Errorf("expected healthy %v (%s), got %v (%s)", tt.wantHealthy, tt.wantHealth, found.Healthy, found.Health)
+			}
+			if v := found.ExtraInfo[StateKeyRemapStatusPendingRemaps]; v != tt.wantPending {
+				t.Errorf("expected pending remaps %q, got %q", tt.wantPending, v)
+			}
+			if v := found.ExtraInfo[StateKeyRemapStatusFailedRemaps]; v != tt.wantFailed {
+				t.Errorf("expected failed remaps %q, got %q", tt.wantFailed, v)
+			}
+			if v := found.ExtraInfo[StateKeyRemapStatusPendingReboot]; v != tt.wantReboot {
+				t.Errorf("expected pending reboot %q, got %q", tt.wantReboot, v)
+			}
+
+			// the summary state must not break parsing the states back to the output
+			if _, err := ParseStatesToOutput(states...); err != nil {
+				t.Errorf("failed to parse states: %v", err)
+			}
+		})
+	}
+}
+
+func TestToOutputSecondaryBusReset(t *testing.T) {
+	pending := nvidia_query_nvml.RemappedRows{RemappingPending: true}
+	tests := []struct {
+		name        string
+		sbr         bool
+		wantActions []common.RepairActionType
+	}{
+		{name: "secondary bus reset supported", sbr: true, wantActions: []common.RepairActionType{common.RepairActionTypeResetGPU}},
+		{name: "secondary bus reset not supported", sbr: false, wantActions: []common.RepairActionType{common.RepairActionTypeRebootSystem}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := ToOutput(&nvidia_query.Output{
+				NVML: &nvidia_query_nvml.Output{
+					Exists: true,
+					DeviceInfos: []*nvidia_query_nvml.DeviceInfo{{
+						UUID:                       "GPU-0",
+						PCIBusID:                   "0000:3b:00.0",
+						SecondaryBusResetSupported: tt.sbr,
+						RemappedRows:               pending,
+					}},
+				},
+				// the same GPU reported by nvidia-smi must not override the reset
+				SMI: &nvidia_query.SMIOutput{
+					GPUs: []nvidia_query.NvidiaSMIGPU{{
+						ID:           "00000000:3B:00.0",
+						RemappedRows: &nvidia_query.SMIRemappedRows{ID: "00000000:3B:00.0", Pending: "Yes", RemappingFailureOccurred: "No"},
+					}},
+				},
+			})
+			if o.SuggestedActions == nil {
+				t.Fatal("expected suggested actions")
+			}
+			if !reflect.DeepEqual(o.SuggestedActions.RepairActions, tt.wantActions) {
+				t.Errorf("expected repair actions %v, got %v", tt.wantActions, o.SuggestedActions.RepairActions)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package thermalthreshold tracks the NVIDIA GPU temperatures against the slowdown and shutdown thresholds.
+package thermalthreshold
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_thermal_threshold_id "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold/id"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+)
+
+func New(ctx context.Context, cfg Config) (components.Component, error) {
+	if nvidia_query.GetDefaultPoller() == nil {
+		return nil, nvidia_query.ErrDefaultPollerNotSet
+	}
+
+	cfg.Query.SetDefaultsIfNotSet()
+
+	cctx, ccancel := context.WithCancel(ctx)
+	nvidia_query.GetDefaultPoller().Start(cctx, cfg.Query, nvidia_thermal_threshold_id.Name)
+
+	return &component{
+		rootCtx:               ctx,
+		cancel:                ccancel,
+		poller:                nvidia_query.GetDefaultPoller(),
+		slowdownMarginCelsius: cfg.SlowdownMarginCelsius,
+	}, nil
+}
+
+var _ components.Component = (*component)(nil)
+
+type component struct {
+	rootCtx context.Context
+	cancel  context.CancelFunc
+	poller  query.Poller
+
+	slowdownMarginCelsius uint32
+}
+
+func (c *component) Name() string { return nvidia_thermal_threshold_id.Name }
+
+func (c *component) Start() error { return nil }
+
+func (c *component) States(ctx context.Context) (states []components.State, err error) {
+	last, err := c.poller.LastSuccess()
+	if err == query.ErrNoData { // no data
+		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", nvidia_thermal_threshold_id.Name)
+		return []components.State{
+			{
+				Name:    nvidia_thermal_threshold_id.Name,
+				Healthy: true,
+				Error:   query.ErrNoData.Error(),
+				Reason:  query.ErrNoData.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allOutput, ok := last.Output.(*nvidia_query.Output)
+	if !ok {
+		return nil, fmt.Errorf("invalid output type: %T", last.Output)
+	}
+	freshness := query.CheckFreshness(c.poller, allOutput.Time, time.Now().UTC())
+	defer func() {
+		freshness.Mark(states)
+	}()
+
+	output := ToOutput(allOutput, c.slowdownMarginCelsius)
+	return output.States()
+}
+
+func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
+	return nil, nil
+}
+
+func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
+	log.Logger.Debugw("querying metrics", "since", since)
+
+	return nil, nil
+}
+
+func (c *component) Close() error {
+	log.Logger.Debugw("closing component")
+
+	// safe to call stop multiple times
+	_ = c.poller.Stop(nvidia_thermal_threshold_id.Name)
+
+	return nil
+}
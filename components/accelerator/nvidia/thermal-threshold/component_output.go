@@ -0,0 +1,133 @@
+package thermalthreshold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/common"
+)
+
+// ToOutput converts nvidia_query.Output to Output.
+// It returns an empty non-nil object, if the input or the required field is nil (e.g., i.NVML).
+func ToOutput(i *nvidia_query.Output, slowdownMarginCelsius uint32) *Output {
+	o := &Output{SlowdownMarginCelsius: slowdownMarginCelsius}
+	if i == nil {
+		return o
+	}
+
+	if i.NVML != nil {
+		for _, device := range i.NVML.DeviceInfos {
+			o.TemperaturesNVML = append(o.TemperaturesNVML, device.Temperature)
+		}
+	}
+
+	return o
+}
+
+type Output struct {
+	SlowdownMarginCelsius uint32                          `json:"slowdown_margin_celsius"`
+	TemperaturesNVML      []nvidia_query_nvml.Temperature `json:"temperatures_nvml"`
+}
+
+func (o *Output) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func ParseOutputJSON(data []byte) (*Output, error) {
+	o := new(Output)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+const (
+	StateNameThermalThreshold = "thermal_threshold"
+
+	StateKeyThermalThresholdData           = "data"
+	StateKeyThermalThresholdEncoding       = "encoding"
+	StateValueThermalThresholdEncodingJSON = "json"
+)
+
+func ParseStateThermalThreshold(m map[string]string) (*Output, error) {
+	data := m[StateKeyThermalThresholdData]
+	return ParseOutputJSON([]byte(data))
+}
+
+func ParseStatesToOutput(states ...components.State) (*Output, error) {
+	for _, state := range states {
+		switch state.Name {
+		case StateNameThermalThreshold:
+			o, err := ParseStateThermalThreshold(state.ExtraInfo)
+			if err != nil {
+				return nil, err
+			}
+			return o, nil
+
+		default:
+			return nil, fmt.Errorf("unknown state name: %s", state.Name)
+		}
+	}
+	return nil, errors.New("no state found")
+}
+
+// Evaluate returns the output evaluation reason and the most severe event type across the GPUs.
+// The GPU at (or above) the shutdown threshold is critical, and the one within the margin
+// of the slowdown threshold is a warning. The thresholds not reported by the driver (zero) are ignored.
+func (o *Output) Evaluate() (string, common.EventType, error) {
+	reasons := []string{}
+	eventType := common.EventTypeInfo
+	for _, t := range o.TemperaturesNVML {
+		switch {
+		case t.ThresholdCelsiusShutdown > 0 && t.CurrentCelsiusGPUCore >= t.ThresholdCelsiusShutdown:
+			reasons = append(reasons, fmt.Sprintf("GPU %s temperature %d °C reached the shutdown threshold %d °C", t.UUID, t.CurrentCelsiusGPUCore, t.ThresholdCelsiusShutdown))
+			eventType = common.EventTypeCritical
+
+		case t.ThresholdCelsiusSlowdown > 0 && t.CurrentCelsiusGPUCore+o.SlowdownMarginCelsius >= t.ThresholdCelsiusSlowdown:
+			reasons = append(reasons, fmt.Sprintf("GPU %s temperature %d °C is within %d °C of the slowdown threshold %d °C", t.UUID, t.CurrentCelsiusGPUCore, o.SlowdownMarginCelsius, t.ThresholdCelsiusSlowdown))
+			if eventType != common.EventTypeCritical {
+				eventType = common.EventTypeWarning
+			}
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no issue detected")
+	}
+	return strings.Join(reasons, "; "), eventType, nil
+}
+
+func (o *Output) States() ([]components.State, error) {
+	outputReasons, eventType, err := o.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	b, _ := o.JSON()
+
+	healthy := true
+	health := components.StateHealthy
+	switch eventType {
+	case common.EventTypeCritical:
+		healthy = false
+		health = components.StateUnhealthy
+	case common.EventTypeWarning:
+		health = components.StateDegraded
+	}
+
+	state := components.State{
+		Name:    StateNameThermalThreshold,
+		Healthy: healthy,
+		Health:  health,
+		Reason:  outputReasons,
+		ExtraInfo: map[string]string{
+			StateKeyThermalThresholdData:     string(b),
+			StateKeyThermalThresholdEncoding: StateValueThermalThresholdEncodingJSON,
+		},
+	}
+	return []components.State{state}, nil
+}
@@ -0,0 +1,97 @@
+package thermalthreshold
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_query "github.com/leptonai/gpud/components/accelerator/nvidia/query"
+	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
+	"github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml/testutil"
+)
+
+func createFakeDevice(current uint32) *mock.Device {
+	return &mock.Device{
+		GetTemperatureFunc: func(sensor nvml.TemperatureSensors) (uint32, nvml.Return) {
+			return current, nvml.SUCCESS
+		},
+		GetTemperatureThresholdFunc: func(thresholdType nvml.TemperatureThresholds) (uint32, nvml.Return) {
+			switch thresholdType {
+			case nvml.TEMPERATURE_THRESHOLD_SLOWDOWN:
+				return 90, nvml.SUCCESS
+			case nvml.TEMPERATURE_THRESHOLD_SHUTDOWN:
+				return 100, nvml.SUCCESS
+			}
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+	}
+}
+
+func TestOutputStates(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     uint32
+		wantHealthy bool
+		wantHealth  string
+	}{
+		{name: "below threshold", current: 60, wantHealthy: true, wantHealth: components.StateHealthy},
+		{name: "near slowdown", current: 87, wantHealthy: true, wantHealth: components.StateDegraded},
+		{name: "at shutdown", current: 100, wantHealthy: false, wantHealth: components.StateUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp, err := nvidia_query_nvml.GetTemperature("gpu-0", testutil.CreateDevice(createFakeDevice(tt.current)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			in := &nvidia_query.Output{
+				NVML: &nvidia_query_nvml.Output{
+					DeviceInfos: []*nvidia_query_nvml.DeviceInfo{{UUID: "gpu-0", Temperature: temp}},
+				},
+			}
+			states, err := ToOutput(in, DefaultSlowdownMarginCelsius).States()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if states[0].Healthy != tt.wantHealthy {
+				t.Errorf("expected healthy %v, got %v", tt.wantHealthy, states[0].Healthy)
+			}
+			if states[0].Health != tt.wantHealth {
+				t.Errorf("expected health %q, got %q (%s)", tt.wantHealth, states[0].Health, states[0].Reason)
+			}
+		})
+	}
+}
+
+func TestOutputStatesThresholdsNotSupported(t *testing.T) {
+	in := &nvidia_query.Output{
+		NVML: &nvidia_query_nvml.Output{
+			DeviceInfos: []*nvidia_query_nvml.DeviceInfo{
+				{UUID: "gpu-0", Temperature: nvidia_query_nvml.Temperature{UUID: "gpu-0", CurrentCelsiusGPUCore: 95}},
+			},
+		},
+	}
+	states, err := ToOutput(in, DefaultSlowdownMarginCelsius).States()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if states[0].Health != components.StateHealthy {
+		t.Errorf("expected healthy without thresholds, got %q", states[0].Health)
+	}
+}
+
+func TestConfigValidateDefaults(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SlowdownMarginCelsius != DefaultSlowdownMarginCelsius {
+		t.Errorf("expected default margin %d, got %d", DefaultSlowdownMarginCelsius, cfg.SlowdownMarginCelsius)
+	}
+}
@@ -0,0 +1,48 @@
+package thermalthreshold
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	query_config "github.com/leptonai/gpud/components/query/config"
+)
+
+// DefaultSlowdownMarginCelsius is the default margin below the slowdown threshold
+// at which the GPU is reported as running hot.
+const DefaultSlowdownMarginCelsius = 5
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// SlowdownMarginCelsius is the margin in celsius below the slowdown threshold
+	// at which the GPU is reported as degraded (e.g., 5 to warn at 85 °C for 90 °C slowdown threshold).
+	// If not set, it defaults to DefaultSlowdownMarginCelsius.
+	SlowdownMarginCelsius uint32 `json:"slowdown_margin_celsius"`
+
+	nvidia_common.ToolOverwrites
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.SlowdownMarginCelsius == 0 {
+		cfg.SlowdownMarginCelsius = DefaultSlowdownMarginCelsius
+	}
+	return nil
+}
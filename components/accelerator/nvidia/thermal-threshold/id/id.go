@@ -0,0 +1,4 @@
+// Package id defines the GPU thermal threshold component ID.
+package id
+
+const Name = "accelerator-nvidia-thermal-threshold"
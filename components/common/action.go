@@ -20,6 +20,14 @@ const (
 	// For instance, NVIDIA may report XID 45 as user app error, but the underlying GPU might have other issues
 	// thus requires further diagnosis of the application and the GPU.
 	RepairActionTypeCheckUserAppAndGPU RepairActionType = "CHECK_USER_APP_AND_GPU"
+
+	// RepairActionTypeResetGPU represents a suggested action to reset the GPU
+	// (e.g., "nvidia-smi --gpu-reset") without rebooting the system.
+	RepairActionTypeResetGPU RepairActionType = "RESET_GPU"
+
+	// RepairActionTypeDrain represents a suggested action to drain the workloads
+	// off the machine (e.g., "kubectl drain") before the other repair actions.
+	RepairActionTypeDrain RepairActionType = "DRAIN"
 )
 
 // SuggestedActions represents a set of suggested actions to mitigate an issue.
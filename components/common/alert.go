@@ -0,0 +1,107 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertClass represents how urgently an issue should be escalated to the operators.
+type AlertClass string
+
+const (
+	// AlertClassNone represents an issue that does not require any alert.
+	AlertClassNone AlertClass = "None"
+
+	// AlertClassTicket represents a non-urgent issue that can be handled
+	// in the business hours (e.g., convenience reboot).
+	AlertClassTicket AlertClass = "Ticket"
+
+	// AlertClassPage represents an urgent issue that must page the operators
+	// immediately (e.g., fatal error, hardware inspection/RMA).
+	AlertClassPage AlertClass = "Page"
+)
+
+// GetAlertClass returns the alert class for the event type and its suggested actions.
+// Fatal events and the ones requiring hardware inspection always page.
+func GetAlertClass(eventType EventType, actions *SuggestedActions) AlertClass {
+	if eventType == EventTypeFatal || actions.RequiresRepair() {
+		return AlertClassPage
+	}
+	if eventType == EventTypeCritical || eventType == EventTypeWarning {
+		return AlertClassTicket
+	}
+	if actions.RequiresReboot() || actions.RequiresCheckUserAppAndGPU() {
+		return AlertClassTicket
+	}
+	return AlertClassNone
+}
+
+// TimeWindow represents a daily time window in the local time zone,
+// with the start and end in the "HH:MM" format (e.g., "22:00" to "06:00").
+// The window wraps around midnight if the end is before the start.
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+const timeWindowLayout = "15:04"
+
+func (w TimeWindow) Validate() error {
+	if _, err := time.Parse(timeWindowLayout, w.Start); err != nil {
+		return fmt.Errorf("invalid time window start %q: %w", w.Start, err)
+	}
+	if _, err := time.Parse(timeWindowLayout, w.End); err != nil {
+		return fmt.Errorf("invalid time window end %q: %w", w.End, err)
+	}
+	return nil
+}
+
+// Contains returns true if the time of the day of "t" is within the window.
+// The start is inclusive and the end is exclusive.
+// Returns false if the window is invalid.
+func (w TimeWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(timeWindowLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(timeWindowLayout, w.End)
+	if err != nil {
+		return false
+	}
+
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+	nowMin := t.Hour()*60 + t.Minute()
+
+	if startMin <= endMin {
+		return startMin <= nowMin && nowMin < endMin
+	}
+	// wraps around midnight
+	return nowMin >= startMin || nowMin < endMin
+}
+
+// AlertDecision is the result of applying the quiet hours to an alert.
+type AlertDecision struct {
+	Class AlertClass `json:"class"`
+	// Set true if the alert is suppressed due to the quiet hours.
+	// The suppressed alert should still be recorded (e.g., events),
+	// but should not notify the operators.
+	Suppressed bool `json:"suppressed"`
+}
+
+// ApplyQuietHours downgrades the ticket-class alerts to suppressed
+// if "now" is within any of the quiet hours windows.
+// Page-class alerts (e.g., fatal, RMA) are never suppressed.
+func ApplyQuietHours(class AlertClass, quietHours []TimeWindow, now time.Time) AlertDecision {
+	d := AlertDecision{Class: class}
+	if class != AlertClassTicket {
+		return d
+	}
+	for _, w := range quietHours {
+		if w.Contains(now) {
+			d.Suppressed = true
+			break
+		}
+	}
+	return d
+}
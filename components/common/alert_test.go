@@ -0,0 +1,94 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetAlertClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType EventType
+		actions   *SuggestedActions
+		want      AlertClass
+	}{
+		{name: "fatal", eventType: EventTypeFatal, want: AlertClassPage},
+		{
+			name:      "hardware inspection",
+			eventType: EventTypeCritical,
+			actions:   &SuggestedActions{RepairActions: []RepairActionType{RepairActionTypeHardwareInspection}},
+			want:      AlertClassPage,
+		},
+		{
+			name:      "reboot",
+			eventType: EventTypeInfo,
+			actions:   &SuggestedActions{RepairActions: []RepairActionType{RepairActionTypeRebootSystem}},
+			want:      AlertClassTicket,
+		},
+		{name: "critical", eventType: EventTypeCritical, want: AlertClassTicket},
+		{name: "warning", eventType: EventTypeWarning, want: AlertClassTicket},
+		{name: "info", eventType: EventTypeInfo, want: AlertClassNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetAlertClass(tt.eventType, tt.actions); got != tt.want {
+				t.Errorf("GetAlertClass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		window TimeWindow
+		at     time.Duration
+		want   bool
+	}{
+		{name: "within", window: TimeWindow{Start: "01:00", End: "05:00"}, at: 3 * time.Hour, want: true},
+		{name: "start inclusive", window: TimeWindow{Start: "01:00", End: "05:00"}, at: time.Hour, want: true},
+		{name: "end exclusive", window: TimeWindow{Start: "01:00", End: "05:00"}, at: 5 * time.Hour, want: false},
+		{name: "outside", window: TimeWindow{Start: "01:00", End: "05:00"}, at: 12 * time.Hour, want: false},
+		{name: "wraps midnight before", window: TimeWindow{Start: "22:00", End: "06:00"}, at: 23 * time.Hour, want: true},
+		{name: "wraps midnight after", window: TimeWindow{Start: "22:00", End: "06:00"}, at: 3 * time.Hour, want: true},
+		{name: "wraps midnight outside", window: TimeWindow{Start: "22:00", End: "06:00"}, at: 12 * time.Hour, want: false},
+		{name: "invalid", window: TimeWindow{Start: "25:00", End: "06:00"}, at: 3 * time.Hour, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(day.Add(tt.at)); got != tt.want {
+				t.Errorf("TimeWindow.Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyQuietHours(t *testing.T) {
+	quietHours := []TimeWindow{{Start: "22:00", End: "06:00"}}
+	threeAM := time.Date(2024, 1, 1, 3, 0, 0, 0, time.Local)
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name           string
+		class          AlertClass
+		now            time.Time
+		wantSuppressed bool
+	}{
+		{name: "urgent during quiet hours still pages", class: AlertClassPage, now: threeAM, wantSuppressed: false},
+		{name: "non-urgent during quiet hours is suppressed", class: AlertClassTicket, now: threeAM, wantSuppressed: true},
+		{name: "non-urgent outside quiet hours", class: AlertClassTicket, now: noon, wantSuppressed: false},
+		{name: "urgent outside quiet hours", class: AlertClassPage, now: noon, wantSuppressed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ApplyQuietHours(tt.class, quietHours, tt.now)
+			if d.Class != tt.class {
+				t.Errorf("expected class %v, got %v", tt.class, d.Class)
+			}
+			if d.Suppressed != tt.wantSuppressed {
+				t.Errorf("expected suppressed %v, got %v", tt.wantSuppressed, d.Suppressed)
+			}
+		})
+	}
+}
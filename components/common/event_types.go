@@ -38,3 +38,27 @@ func EventTypeFromString(s string) EventType {
 		return EventTypeUnknown
 	}
 }
+
+// Severity returns the severity rank of the event type,
+// where the higher value is more severe.
+func (t EventType) Severity() int {
+	switch t {
+	case EventTypeInfo:
+		return 1
+	case EventTypeWarning:
+		return 2
+	case EventTypeCritical:
+		return 3
+	case EventTypeFatal:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// EventTypeHealthMapping maps the event types to whether the component
+// stays healthy (true) or is marked unhealthy (false) on the event,
+// overriding the component defaults (e.g., map "Critical" to true
+// to keep the component healthy on the critical events, independent of "Fatal").
+// The event types not in the mapping follow the component defaults.
+type EventTypeHealthMapping map[EventType]bool
@@ -65,6 +65,12 @@ type OutputProvider interface {
 	Output() (any, error)
 }
 
+// Defines an optional component interface that returns the component labels
+// (e.g., "role=inference"), used to filter components by label selectors.
+type Labeler interface {
+	Labels() map[string]string
+}
+
 // Defines an optional component interface that supports Prometheus metrics.
 type PromRegisterer interface {
 	RegisterCollectors(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error
@@ -78,7 +84,21 @@ type State struct {
 	Error     string            `json:"error,omitempty"`      // the unprocessed error returned from the component
 	ExtraInfo map[string]string `json:"extra_info,omitempty"` // any extra information the component may want to expose
 
+	// ReasonCode is the machine-parseable code of the reason (e.g., "XID_CRITICAL"),
+	// for the consumers to match on rather than the human-readable reason.
+	// Empty if the component does not define a code for the state.
+	// The reason code does not imply the state is unhealthy (e.g., "ECC_DBE"
+	// is set on the healthy ECC state, as the driver recovers from the error),
+	// thus use "Healthy" and "Health" for the health.
+	ReasonCode string `json:"reason_code,omitempty"`
+
 	SuggestedActions *common.SuggestedActions `json:"suggested_actions,omitempty"`
+
+	// LastUpdated is the time of the last successful poll the state is based on.
+	LastUpdated metav1.Time `json:"last_updated,omitempty"`
+	// Stale is true if the state is based on the cached data,
+	// because the most recent poll failed or the last successful poll is too old.
+	Stale bool `json:"stale,omitempty"`
 }
 
 const (
@@ -87,6 +107,22 @@ const (
 	StateDegraded  = "Degraded"
 )
 
+// Reason codes of the states (see State.ReasonCode).
+const (
+	// ReasonCodeXidCritical is set on the unhealthy Xid state when a critical Xid error is detected.
+	ReasonCodeXidCritical = "XID_CRITICAL"
+	// ReasonCodeECCDBE is set when an uncorrectable double bit ECC error is detected,
+	// on the unhealthy Xid state (Xid 48), and on the ECC state that stays healthy
+	// since the driver performs the error recovery.
+	ReasonCodeECCDBE = "ECC_DBE"
+	// ReasonCodeDiskFull is set on the unhealthy "disk_full" state
+	// when a filesystem runs out of free space or inodes.
+	ReasonCodeDiskFull = "DISK_FULL"
+	// ReasonCodeNVLinkDown is set on the degraded (but healthy) NVLink state
+	// when an NVLink is down while the other links of the GPU are up.
+	ReasonCodeNVLinkDown = "NVLINK_DOWN"
+)
+
 type Event struct {
 	Time             metav1.Time              `json:"time"`
 	Name             string                   `json:"name,omitempty"`
@@ -94,6 +130,13 @@ type Event struct {
 	Message          string                   `json:"message,omitempty"`    // detailed message of the event
 	ExtraInfo        map[string]string        `json:"extra_info,omitempty"` // any extra information the component may want to expose
 	SuggestedActions *common.SuggestedActions `json:"suggested_actions,omitempty"`
+
+	// FirstSeen and LastSeen are the times of the first and the last occurrences,
+	// set only if the identical events are coalesced into the event.
+	FirstSeen *metav1.Time `json:"first_seen,omitempty"`
+	LastSeen  *metav1.Time `json:"last_seen,omitempty"`
+	// Occurrences is the number of the identical events coalesced into the event.
+	Occurrences int `json:"occurrences,omitempty"`
 }
 
 type Metric struct {
@@ -134,6 +177,31 @@ func RegisterComponent(name string, comp Component) error {
 	return nil
 }
 
+// Register registers the component by its name, so that custom components
+// (e.g., site-specific checks) can be added without modifying this repository.
+// Returns errdefs.ErrAlreadyExists if the component with the same name is already registered.
+func Register(comp Component) error {
+	if comp == nil {
+		return fmt.Errorf("component is nil: %w", errdefs.ErrInvalidArgument)
+	}
+	return RegisterComponent(comp.Name(), comp)
+}
+
+// Deregister removes the component from the registry, and returns the removed component.
+// The component is not closed, the caller is responsible for closing it.
+// Returns errdefs.ErrNotFound if the component is not registered.
+func Deregister(name string) (Component, error) {
+	defaultSetMu.Lock()
+	defer defaultSetMu.Unlock()
+
+	comp, err := getComponent(defaultSet, name)
+	if err != nil {
+		return nil, err
+	}
+	delete(defaultSet, name)
+	return comp, nil
+}
+
 func GetComponent(name string) (Component, error) {
 	defaultSetMu.RLock()
 	defer defaultSetMu.RUnlock()
@@ -153,8 +221,31 @@ func getComponent(set map[string]Component, name string) (Component, error) {
 	return v, nil
 }
 
+// GetAllComponents returns the copy of all the registered components.
 func GetAllComponents() map[string]Component {
 	defaultSetMu.RLock()
 	defer defaultSetMu.RUnlock()
-	return defaultSet
+
+	copied := make(map[string]Component, len(defaultSet))
+	for k, v := range defaultSet {
+		copied[k] = v
+	}
+	return copied
+}
+
+// FilterEventsSinceBoot returns the events at or after the boot time,
+// preserving the order of the events.
+// Returns all the events if the boot time is zero (e.g., unknown boot time).
+func FilterEventsSinceBoot(events []Event, bootTime time.Time) []Event {
+	if bootTime.IsZero() {
+		return events
+	}
+	filtered := make([]Event, 0, len(events))
+	for _, ev := range events {
+		if ev.Time.Time.Before(bootTime) {
+			continue
+		}
+		filtered = append(filtered, ev)
+	}
+	return filtered
 }
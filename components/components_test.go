@@ -1,10 +1,15 @@
 package components
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/leptonai/gpud/errdefs"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetComponentErrors(t *testing.T) {
@@ -15,3 +20,153 @@ func TestGetComponentErrors(t *testing.T) {
 		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
+
+type fakeComponent struct {
+	name string
+}
+
+func (c *fakeComponent) Name() string { return c.name }
+
+func (c *fakeComponent) Start() error { return nil }
+
+func (c *fakeComponent) States(ctx context.Context) ([]State, error) {
+	return []State{{Name: c.name, Healthy: true, Reason: "ib probe ok"}}, nil
+}
+
+func (c *fakeComponent) Events(ctx context.Context, since time.Time) ([]Event, error) {
+	return nil, nil
+}
+
+func (c *fakeComponent) Metrics(ctx context.Context, since time.Time) ([]Metric, error) {
+	return nil, nil
+}
+
+func (c *fakeComponent) Close() error { return nil }
+
+func TestRegisterDeregister(t *testing.T) {
+	comp := &fakeComponent{name: "test-site-ib-probe"}
+	if err := Register(comp); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if err := Register(comp); !errors.Is(err, errdefs.ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := Register(nil); !errors.Is(err, errdefs.ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	if !IsComponentRegistered(comp.name) {
+		t.Errorf("expected %q to be registered", comp.name)
+	}
+	if _, ok := GetAllComponents()[comp.name]; !ok {
+		t.Errorf("expected %q in all components", comp.name)
+	}
+	got, err := GetComponent(comp.name)
+	if err != nil {
+		t.Fatalf("failed to get component: %v", err)
+	}
+	states, err := got.States(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get states: %v", err)
+	}
+	if len(states) != 1 || !states[0].Healthy {
+		t.Errorf("unexpected states %+v", states)
+	}
+
+	removed, err := Deregister(comp.name)
+	if err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	if removed != comp {
+		t.Errorf("expected the registered component to be returned")
+	}
+	if IsComponentRegistered(comp.name) {
+		t.Errorf("expected %q to be deregistered", comp.name)
+	}
+	if _, err := GetComponent(comp.name); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := Deregister(comp.name); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// can be registered again after deregistered
+	if err := Register(comp); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if _, err := Deregister(comp.name); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+}
+
+func TestFilterEventsSinceBoot(t *testing.T) {
+	bootTime := time.Unix(1737432104, 0).UTC()
+	events := []Event{
+		{Time: metav1.Time{Time: bootTime.Add(time.Hour)}, Name: "after"},
+		{Time: metav1.Time{Time: bootTime}, Name: "boot"},
+		{Time: metav1.Time{Time: bootTime.Add(-time.Second)}, Name: "before"},
+		{Time: metav1.Time{Time: bootTime.Add(-time.Hour)}, Name: "long-before"},
+	}
+
+	filtered := FilterEventsSinceBoot(events, bootTime)
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(filtered))
+	}
+	if filtered[0].Name != "after" || filtered[1].Name != "boot" {
+		t.Errorf("unexpected events %+v", filtered)
+	}
+
+	if filtered = FilterEventsSinceBoot(events, time.Time{}); len(filtered) != len(events) {
+		t.Errorf("expected all events for zero boot time, got %d", len(filtered))
+	}
+	if filtered = FilterEventsSinceBoot(events, bootTime.Add(2*time.Hour)); len(filtered) != 0 {
+		t.Errorf("expected no event, got %+v", filtered)
+	}
+}
+
+func TestEventOccurrencesJSON(t *testing.T) {
+	firstSeen := metav1.NewTime(time.Unix(1700000000, 0).UTC())
+	lastSeen := metav1.NewTime(time.Unix(1700000090, 0).UTC())
+	ev := Event{
+		Time:        firstSeen,
+		Name:        "error_xid",
+		FirstSeen:   &firstSeen,
+		LastSeen:    &lastSeen,
+		Occurrences: 3,
+	}
+
+	b, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["first_seen"] != "2023-11-14T22:13:20Z" || m["last_seen"] != "2023-11-14T22:14:50Z" || m["occurrences"] != float64(3) {
+		t.Errorf("unexpected json %s", b)
+	}
+
+	var decoded Event
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Occurrences != 3 || decoded.FirstSeen == nil || !decoded.FirstSeen.Equal(&firstSeen) || decoded.LastSeen == nil || !decoded.LastSeen.Equal(&lastSeen) {
+		t.Errorf("unexpected decoded event %+v", decoded)
+	}
+
+	// not coalesced
+	b, err = json.Marshal(Event{Time: firstSeen, Name: "reboot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = nil
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"first_seen", "last_seen", "occurrences"} {
+		if _, ok := m[k]; ok {
+			t.Errorf("expected no %q in %s", k, b)
+		}
+	}
+}
@@ -79,6 +79,10 @@ type Store interface {
 	Insert(ctx context.Context, ev components.Event) error
 	Find(ctx context.Context, ev components.Event) (*components.Event, error)
 
+	// Updates the extra info of the event that matches the timestamp, name, type,
+	// and extra info of the given event.
+	UpdateExtraInfo(ctx context.Context, ev components.Event, extraInfo map[string]string) error
+
 	// Returns the event in the descending order of timestamp (latest event first).
 	Get(ctx context.Context, since time.Time) ([]components.Event, error)
 
@@ -97,7 +101,14 @@ var _ Store = (*storeImpl)(nil)
 // Creates a new DB instance with the table created.
 // Requires write-only and read-only instances for minimize conflicting writes/reads.
 // ref. https://github.com/mattn/go-sqlite3/issues/1179#issuecomment-1638083995
+//
+// If the in-memory backend is set (see SetBackend), returns the in-memory store of the table
+// without using the DB instances.
 func NewStore(dbRW *sql.DB, dbRO *sql.DB, tableName string, retention time.Duration) (Store, error) {
+	if s, ok := getMemoryStore(tableName, retention); ok {
+		return withSinks(s, tableName), nil
+	}
+
 	if dbRW == nil {
 		return nil, ErrNoDBRWSet
 	}
@@ -123,7 +134,7 @@ func NewStore(dbRW *sql.DB, dbRO *sql.DB, tableName string, retention time.Durat
 	}
 	go s.runPurge()
 
-	return s, nil
+	return withSinks(s, tableName), nil
 }
 
 func (s *storeImpl) runPurge() {
@@ -171,6 +182,10 @@ func (s *storeImpl) Find(ctx context.Context, ev components.Event) (*components.
 	return findEvent(ctx, s.dbRO, s.table, ev)
 }
 
+func (s *storeImpl) UpdateExtraInfo(ctx context.Context, ev components.Event, extraInfo map[string]string) error {
+	return updateEventExtraInfo(ctx, s.dbRW, s.table, ev, extraInfo)
+}
+
 // Returns the event in the descending order of timestamp (latest event first).
 func (s *storeImpl) Get(ctx context.Context, since time.Time) ([]components.Event, error) {
 	return getEvents(ctx, s.dbRO, s.table, since)
@@ -332,6 +347,50 @@ SELECT %s, %s, %s, %s, %s, %s FROM %s WHERE %s = ? AND %s = ? AND %s = ?`,
 	return nil, nil
 }
 
+func updateEventExtraInfo(ctx context.Context, db *sql.DB, tableName string, ev components.Event, extraInfo map[string]string) error {
+	var prevExtraInfoJSON, extraInfoJSON []byte
+	var err error
+	if ev.ExtraInfo != nil {
+		prevExtraInfoJSON, err = json.Marshal(ev.ExtraInfo)
+		if err != nil {
+			return fmt.Errorf("failed to marshal extra info: %w", err)
+		}
+	}
+	if extraInfo != nil {
+		extraInfoJSON, err = json.Marshal(extraInfo)
+		if err != nil {
+			return fmt.Errorf("failed to marshal extra info: %w", err)
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s = NULLIF(?, '') WHERE %s = ? AND %s = ? AND %s = ? AND %s = ?",
+		tableName,
+		ColumnExtraInfo,
+		ColumnTimestamp,
+		ColumnName,
+		ColumnType,
+		ColumnExtraInfo,
+	)
+	params := []any{string(extraInfoJSON), ev.Time.Unix(), ev.Name, ev.Type, string(prevExtraInfoJSON)}
+	if ev.ExtraInfo == nil {
+		query = fmt.Sprintf("UPDATE %s SET %s = NULLIF(?, '') WHERE %s = ? AND %s = ? AND %s = ? AND %s IS NULL",
+			tableName,
+			ColumnExtraInfo,
+			ColumnTimestamp,
+			ColumnName,
+			ColumnType,
+			ColumnExtraInfo,
+		)
+		params = params[:4]
+	}
+
+	start := time.Now()
+	_, err = db.ExecContext(ctx, query, params...)
+	sqlite.RecordInsertUpdate(time.Since(start).Seconds())
+
+	return err
+}
+
 // Returns the event in the descending order of timestamp (latest event first).
 func getEvents(ctx context.Context, db *sql.DB, tableName string, since time.Time) ([]components.Event, error) {
 	query := fmt.Sprintf(`SELECT %s, %s, %s, %s, %s, %s
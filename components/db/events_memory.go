@@ -0,0 +1,313 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BackendSQLite persists the events in the SQLite tables (default).
+	BackendSQLite = "sqlite"
+	// BackendMemory keeps the events in memory, lost on restart
+	// (e.g., for ephemeral or CI runs).
+	BackendMemory = "memory"
+
+	// DefaultMemoryStoreMaxEvents is the default maximum number of events
+	// per table in the in-memory store.
+	DefaultMemoryStoreMaxEvents = 10000
+)
+
+var (
+	backendMu        sync.Mutex
+	backend          = BackendSQLite
+	memoryMaxEvents  = DefaultMemoryStoreMaxEvents
+	memoryStoreTable = make(map[string]*memoryStore)
+)
+
+// SetBackend sets the backend of the stores created by NewStore afterwards.
+// For the in-memory backend, the stores keep up to maxEvents events per table
+// (DefaultMemoryStoreMaxEvents if zero), evicting the oldest events first.
+func SetBackend(b string, maxEvents int) error {
+	switch b {
+	case "", BackendSQLite, BackendMemory:
+	default:
+		return fmt.Errorf("unknown event store backend %q", b)
+	}
+	if maxEvents < 0 {
+		return fmt.Errorf("max events must be non-negative, got %d", maxEvents)
+	}
+	if b == "" {
+		b = BackendSQLite
+	}
+	if maxEvents == 0 {
+		maxEvents = DefaultMemoryStoreMaxEvents
+	}
+
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = b
+	memoryMaxEvents = maxEvents
+	return nil
+}
+
+// getMemoryStore returns the in-memory store of the table if the backend is in-memory,
+// shared by all the callers of the same table (as the SQLite table is).
+func getMemoryStore(tableName string, retention time.Duration) (Store, bool) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+
+	if backend != BackendMemory {
+		return nil, false
+	}
+	if s, ok := memoryStoreTable[tableName]; ok {
+		return s, true
+	}
+	s := newMemoryStore(tableName, memoryMaxEvents, retention)
+	memoryStoreTable[tableName] = s
+	return s, true
+}
+
+type memoryStore struct {
+	rootCtx    context.Context
+	rootCancel context.CancelFunc
+
+	table     string
+	maxEvents int
+	retention time.Duration
+
+	mu sync.RWMutex
+	// in the insertion order, the oldest first
+	events []components.Event
+}
+
+var _ Store = (*memoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory store that keeps up to maxEvents events
+// (DefaultMemoryStoreMaxEvents if zero), evicting the oldest inserted events first.
+// The events older than the retention are purged periodically, if the retention is set.
+func NewMemoryStore(maxEvents int, retention time.Duration) Store {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMemoryStoreMaxEvents
+	}
+	return newMemoryStore("", maxEvents, retention)
+}
+
+func newMemoryStore(tableName string, maxEvents int, retention time.Duration) *memoryStore {
+	rootCtx, rootCancel := context.WithCancel(context.Background())
+	s := &memoryStore{
+		rootCtx:    rootCtx,
+		rootCancel: rootCancel,
+		table:      tableName,
+		maxEvents:  maxEvents,
+		retention:  retention,
+	}
+	go s.runPurge()
+	return s
+}
+
+func (s *memoryStore) runPurge() {
+	if s.retention < time.Second {
+		return
+	}
+
+	checkInterval := s.retention / 5
+	if checkInterval < time.Second {
+		checkInterval = time.Second
+	}
+	for {
+		select {
+		case <-s.rootCtx.Done():
+			return
+		case <-time.After(checkInterval):
+		}
+
+		purged, _ := s.Purge(s.rootCtx, time.Now().UTC().Add(-s.retention).Unix())
+		log.Logger.Debugw("purged data", "table", s.table, "retention", s.retention, "purged", purged)
+	}
+}
+
+func (s *memoryStore) Close() {
+	s.rootCancel()
+}
+
+func (s *memoryStore) Insert(ctx context.Context, ev components.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// same as the SQLite store, the timestamp is stored in unix seconds
+	ev.Time = metav1.Time{Time: time.Unix(ev.Time.Unix(), 0)}
+	ev, err := copyEvent(ev)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	if evicted := len(s.events) - s.maxEvents; evicted > 0 {
+		s.events = append([]components.Event(nil), s.events[evicted:]...)
+	}
+	return nil
+}
+
+func (s *memoryStore) Find(ctx context.Context, ev components.Event) (*components.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var suggestedActionsJSON []byte
+	if ev.SuggestedActions != nil {
+		var err error
+		suggestedActionsJSON, err = json.Marshal(ev.SuggestedActions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal suggested actions: %w", err)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cur := range s.events {
+		if cur.Time.Unix() != ev.Time.Unix() || cur.Name != ev.Name || cur.Type != ev.Type {
+			continue
+		}
+		if ev.Message != "" && cur.Message != ev.Message {
+			continue
+		}
+		if ev.SuggestedActions != nil {
+			if cur.SuggestedActions == nil {
+				continue
+			}
+			b, err := json.Marshal(cur.SuggestedActions)
+			if err != nil || string(b) != string(suggestedActionsJSON) {
+				continue
+			}
+		}
+		if compareEvent(cur, ev) {
+			found, err := copyEvent(cur)
+			if err != nil {
+				return nil, err
+			}
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) UpdateExtraInfo(ctx context.Context, ev components.Event, extraInfo map[string]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, cur := range s.events {
+		if cur.Time.Unix() != ev.Time.Unix() || cur.Name != ev.Name || cur.Type != ev.Type {
+			continue
+		}
+		if (cur.ExtraInfo == nil) != (ev.ExtraInfo == nil) || !maps.Equal(cur.ExtraInfo, ev.ExtraInfo) {
+			continue
+		}
+		s.events[i].ExtraInfo = maps.Clone(extraInfo)
+	}
+	return nil
+}
+
+// Returns the event in the descending order of timestamp (latest event first).
+func (s *memoryStore) Get(ctx context.Context, since time.Time) ([]components.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []components.Event
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if s.events[i].Time.Unix() <= since.UTC().Unix() {
+			continue
+		}
+		ev, err := copyEvent(s.events[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Unix() > events[j].Time.Unix()
+	})
+	return events, nil
+}
+
+// Returns the latest event.
+// Returns nil if no event found.
+func (s *memoryStore) Latest(ctx context.Context) (*components.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := -1
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if latest == -1 || s.events[i].Time.Unix() > s.events[latest].Time.Unix() {
+			latest = i
+		}
+	}
+	if latest == -1 {
+		return nil, nil
+	}
+	ev, err := copyEvent(s.events[latest])
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func (s *memoryStore) Purge(ctx context.Context, beforeTimestamp int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]components.Event, 0, len(s.events))
+	for _, ev := range s.events {
+		if ev.Time.Unix() >= beforeTimestamp {
+			kept = append(kept, ev)
+		}
+	}
+	purged := len(s.events) - len(kept)
+	s.events = kept
+	return purged, nil
+}
+
+// copyEvent deep-copies the event, so that the callers cannot modify the stored events.
+func copyEvent(ev components.Event) (components.Event, error) {
+	ev.ExtraInfo = maps.Clone(ev.ExtraInfo)
+	ev.FirstSeen = ev.FirstSeen.DeepCopy()
+	ev.LastSeen = ev.LastSeen.DeepCopy()
+	if ev.SuggestedActions != nil {
+		b, err := json.Marshal(ev.SuggestedActions)
+		if err != nil {
+			return ev, fmt.Errorf("failed to marshal suggested actions: %w", err)
+		}
+		ev.SuggestedActions = nil
+		if err := json.Unmarshal(b, &ev.SuggestedActions); err != nil {
+			return ev, fmt.Errorf("failed to unmarshal suggested actions: %w", err)
+		}
+	}
+	return ev, nil
+}
@@ -0,0 +1,264 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/pkg/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestStoreContract runs the same test cases against all the store backends.
+func TestStoreContract(t *testing.T) {
+	t.Parallel()
+
+	backends := map[string]func(t *testing.T) Store{
+		BackendSQLite: func(t *testing.T) Store {
+			dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+			t.Cleanup(cleanup)
+			s, err := NewStore(dbRW, dbRO, "test_table", 0)
+			assert.NoError(t, err)
+			return s
+		},
+		BackendMemory: func(t *testing.T) Store {
+			return NewMemoryStore(0, 0)
+		},
+	}
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Run("insert and get by time", func(t *testing.T) {
+				store := newStore(t)
+				defer store.Close()
+				testStoreInsertGet(t, store)
+			})
+			t.Run("find and update extra info", func(t *testing.T) {
+				store := newStore(t)
+				defer store.Close()
+				testStoreFindUpdate(t, store)
+			})
+			t.Run("latest and purge", func(t *testing.T) {
+				store := newStore(t)
+				defer store.Close()
+				testStoreLatestPurge(t, store)
+			})
+		})
+	}
+}
+
+func testStoreInsertGet(t *testing.T, store Store) {
+	ctx := context.Background()
+	first := time.Now().UTC().Truncate(time.Second)
+
+	events, err := store.Get(ctx, first.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, store.Insert(ctx, components.Event{
+			Time:      metav1.Time{Time: first.Add(time.Duration(i) * time.Second)},
+			Name:      "test",
+			Type:      common.EventTypeWarning,
+			Message:   fmt.Sprintf("event %d", i),
+			ExtraInfo: map[string]string{"id": fmt.Sprint(i)},
+			SuggestedActions: &common.SuggestedActions{
+				RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem},
+			},
+		}))
+	}
+
+	// latest first
+	events, err = store.Get(ctx, first.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, events, 5)
+	for i, ev := range events {
+		assert.Equal(t, fmt.Sprintf("event %d", 4-i), ev.Message)
+		assert.Equal(t, first.Add(time.Duration(4-i)*time.Second).Unix(), ev.Time.Unix())
+		assert.Equal(t, map[string]string{"id": fmt.Sprint(4 - i)}, ev.ExtraInfo)
+		assert.True(t, ev.SuggestedActions.RequiresReboot())
+	}
+
+	// exclusive of the since time
+	events, err = store.Get(ctx, first.Add(2*time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	events, err = store.Get(ctx, first.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func testStoreFindUpdate(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	ev := components.Event{
+		Time:      metav1.Time{Time: now},
+		Name:      "test",
+		Type:      common.EventTypeCritical,
+		Message:   "message",
+		ExtraInfo: map[string]string{"a": "b"},
+	}
+	found, err := store.Find(ctx, ev)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	assert.NoError(t, store.Insert(ctx, ev))
+	found, err = store.Find(ctx, ev)
+	assert.NoError(t, err)
+	if assert.NotNil(t, found) {
+		assert.Equal(t, "message", found.Message)
+	}
+
+	// no match for the different extra info or message
+	found, err = store.Find(ctx, components.Event{Time: ev.Time, Name: ev.Name, Type: ev.Type, ExtraInfo: map[string]string{"a": "c"}})
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+	found, err = store.Find(ctx, components.Event{Time: ev.Time, Name: ev.Name, Type: ev.Type, Message: "other", ExtraInfo: ev.ExtraInfo})
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	assert.NoError(t, store.UpdateExtraInfo(ctx, ev, map[string]string{"a": "c"}))
+	found, err = store.Find(ctx, ev)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	updated := ev
+	updated.ExtraInfo = map[string]string{"a": "c"}
+	found, err = store.Find(ctx, updated)
+	assert.NoError(t, err)
+	assert.NotNil(t, found)
+}
+
+func testStoreLatestPurge(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	latest, err := store.Latest(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, store.Insert(ctx, components.Event{
+			Time: metav1.Time{Time: now.Add(time.Duration(i-9) * time.Minute)},
+			Name: fmt.Sprintf("event-%d", i),
+			Type: common.EventTypeInfo,
+		}))
+	}
+
+	latest, err = store.Latest(ctx)
+	assert.NoError(t, err)
+	if assert.NotNil(t, latest) {
+		assert.Equal(t, "event-9", latest.Name)
+	}
+
+	// purge the events before 5 minutes ago (event-0 to event-3)
+	purged, err := store.Purge(ctx, now.Add(-5*time.Minute).Unix())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, purged)
+
+	events, err := store.Get(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, events, 6)
+	assert.Equal(t, "event-4", events[len(events)-1].Name)
+
+	purged, err = store.Purge(ctx, now.Add(time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.Equal(t, 6, purged)
+
+	latest, err = store.Latest(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore(3, 0)
+	defer store.Close()
+
+	now := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, store.Insert(ctx, components.Event{
+			Time: metav1.Time{Time: now.Add(time.Duration(i) * time.Second)},
+			Name: fmt.Sprintf("event-%d", i),
+			Type: common.EventTypeInfo,
+		}))
+	}
+
+	// the oldest inserted events are evicted first
+	events, err := store.Get(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	names := []string{}
+	for _, ev := range events {
+		names = append(names, ev.Name)
+	}
+	assert.Equal(t, []string{"event-4", "event-3", "event-2"}, names)
+}
+
+func TestMemoryStoreCopies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryStore(0, 0)
+	defer store.Close()
+
+	ev := components.Event{
+		Time:      metav1.Time{Time: time.Now().UTC()},
+		Name:      "test",
+		Type:      common.EventTypeInfo,
+		ExtraInfo: map[string]string{"a": "b"},
+	}
+	assert.NoError(t, store.Insert(ctx, ev))
+	ev.ExtraInfo["a"] = "modified"
+
+	events, err := store.Get(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "b", events[0].ExtraInfo["a"])
+
+	events[0].ExtraInfo["a"] = "modified"
+	latest, err := store.Latest(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", latest.ExtraInfo["a"])
+}
+
+func TestSetBackend(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SetBackend(BackendSQLite, 0))
+	}()
+
+	assert.Error(t, SetBackend("redis", 0))
+	assert.Error(t, SetBackend(BackendMemory, -1))
+
+	assert.NoError(t, SetBackend(BackendMemory, 2))
+
+	// no DB required, and shared by the same table
+	s1, err := NewStore(nil, nil, "test_set_backend", 0)
+	assert.NoError(t, err)
+	s2, err := NewStore(nil, nil, "test_set_backend", 0)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s1.Insert(ctx, components.Event{
+			Time: metav1.Time{Time: now.Add(time.Duration(i) * time.Second)},
+			Name: fmt.Sprintf("event-%d", i),
+			Type: common.EventTypeInfo,
+		}))
+	}
+	events, err := s2.Get(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	assert.NoError(t, SetBackend("", 0))
+	_, err = NewStore(nil, nil, "test_set_backend", 0)
+	assert.Equal(t, ErrNoDBRWSet, err)
+}
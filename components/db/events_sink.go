@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leptonai/gpud/components"
+)
+
+// Sink receives the events newly inserted into the stores
+// (e.g., to forward them to an external alerting system).
+// Send must not block the caller.
+type Sink interface {
+	Send(table string, ev components.Event)
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+// SetSinks sets the sinks of the stores created by NewStore afterwards.
+// Set none to stop forwarding the events of the stores created afterwards.
+func SetSinks(ss ...Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = ss
+}
+
+func getSinks() []Sink {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	return sinks
+}
+
+// withSinks wraps the store to forward the inserted events to the sinks, if any.
+func withSinks(s Store, table string) Store {
+	ss := getSinks()
+	if len(ss) == 0 {
+		return s
+	}
+	return &sinkStore{Store: s, table: table, sinks: ss}
+}
+
+type sinkStore struct {
+	Store
+	table string
+	sinks []Sink
+}
+
+func (s *sinkStore) Insert(ctx context.Context, ev components.Event) error {
+	if err := s.Store.Insert(ctx, ev); err != nil {
+		return err
+	}
+	for _, sk := range s.sinks {
+		sk.Send(s.table, ev)
+	}
+	return nil
+}
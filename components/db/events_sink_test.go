@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/pkg/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testSink struct {
+	mu     sync.Mutex
+	tables []string
+	events []components.Event
+}
+
+func (s *testSink) Send(table string, ev components.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables = append(s.tables, table)
+	s.events = append(s.events, ev)
+}
+
+func TestSetSinks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	sink := &testSink{}
+	SetSinks(sink)
+	defer SetSinks()
+
+	store, err := NewStore(dbRW, dbRO, "test_set_sinks", 0)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	ev := components.Event{
+		Time: metav1.Time{Time: time.Unix(time.Now().Unix(), 0).UTC()},
+		Name: "test",
+		Type: common.EventTypeCritical,
+	}
+	assert.NoError(t, store.Insert(ctx, ev))
+
+	// the wrapped store still persists the event
+	found, err := store.Find(ctx, ev)
+	assert.NoError(t, err)
+	assert.NotNil(t, found)
+
+	assert.Equal(t, []string{"test_set_sinks"}, sink.tables)
+	assert.Equal(t, []components.Event{ev}, sink.events)
+
+	// the stores created without the sink do not forward
+	SetSinks()
+	unwrapped, err := NewStore(dbRW, dbRO, "test_set_sinks_unwrapped", 0)
+	assert.NoError(t, err)
+	defer unwrapped.Close()
+	assert.NoError(t, unwrapped.Insert(ctx, ev))
+	assert.Len(t, sink.events, 1)
+}
@@ -377,6 +377,49 @@ func TestFindEvent(t *testing.T) {
 	assert.Equal(t, testEvent.SuggestedActions.Descriptions[0], found.SuggestedActions.Descriptions[0])
 }
 
+func TestUpdateEventExtraInfo(t *testing.T) {
+	t.Parallel()
+
+	testTableName := "test_table"
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store, err := NewStore(dbRW, dbRO, testTableName, 0)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	baseTime := time.Now().UTC()
+	ev1 := components.Event{
+		Time:      metav1.Time{Time: baseTime},
+		Name:      "dmesg",
+		Type:      common.EventTypeWarning,
+		ExtraInfo: map[string]string{"a": "b", "count": "1"},
+	}
+	ev2 := components.Event{
+		Time:      metav1.Time{Time: baseTime},
+		Name:      "dmesg",
+		Type:      common.EventTypeWarning,
+		ExtraInfo: map[string]string{"a": "c", "count": "1"},
+	}
+	assert.NoError(t, store.Insert(ctx, ev1))
+	assert.NoError(t, store.Insert(ctx, ev2))
+
+	// only the matching event is updated
+	assert.NoError(t, store.UpdateExtraInfo(ctx, ev1, map[string]string{"a": "b", "count": "2"}))
+
+	events, err := store.Get(ctx, baseTime.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	extraInfos := []map[string]string{events[0].ExtraInfo, events[1].ExtraInfo}
+	assert.Contains(t, extraInfos, map[string]string{"a": "b", "count": "2"})
+	assert.Contains(t, extraInfos, map[string]string{"a": "c", "count": "1"})
+}
+
 func TestFindEventPartialMatch(t *testing.T) {
 	t.Parallel()
 
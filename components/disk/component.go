@@ -12,6 +12,7 @@ import (
 	"github.com/leptonai/gpud/components/disk/metrics"
 	"github.com/leptonai/gpud/components/query"
 	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/disk"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -24,9 +25,10 @@ func New(ctx context.Context, cfg Config) components.Component {
 	getDefaultPoller().Start(cctx, cfg.Query, disk_id.Name)
 
 	return &component{
-		rootCtx: ctx,
-		cancel:  ccancel,
-		poller:  getDefaultPoller(),
+		rootCtx:     ctx,
+		cancel:      ccancel,
+		poller:      getDefaultPoller(),
+		mountPoints: cfg.MountPointsToTrackUsage,
 	}
 }
 
@@ -37,6 +39,9 @@ type component struct {
 	cancel   context.CancelFunc
 	poller   query.Poller
 	gatherer prometheus.Gatherer
+
+	// mount points to stat on probe
+	mountPoints []string
 }
 
 func (c *component) Name() string { return disk_id.Name }
@@ -85,6 +90,18 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	return output.States()
 }
 
+var _ components.Prober = (*component)(nil)
+
+// Probe stats the tracked mount points.
+func (c *component) Probe(ctx context.Context) components.ProbeResult {
+	for _, mp := range c.mountPoints {
+		if _, err := disk.GetUsage(ctx, mp); err != nil {
+			return components.ProbeResult{Error: fmt.Sprintf("failed to stat %s: %v", mp, err)}
+		}
+	}
+	return components.ProbeResult{Passed: true, Message: fmt.Sprintf("stat %d mount point(s)", len(c.mountPoints))}
+}
+
 func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
 	return nil, nil
 }
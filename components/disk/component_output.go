@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,12 +24,15 @@ type Output struct {
 	DiskExtPartitions disk.Partitions               `json:"disk_ext_partitions"`
 	DiskBlockDevices  disk.BlockDevices             `json:"disk_block_devices"`
 	MountTargetUsages map[string]disk.FindMntOutput `json:"mount_target_usages"`
+	FillProjections   []FillProjection              `json:"fill_projections,omitempty"`
 }
 
 const (
 	StateNameDiskExtPartition  = "disk_ext_partition"
 	StateNameDiskBlockDevices  = "disk_block_devices"
 	StateNameMountTargetUsages = "mount_target_usages"
+	StateNameFillProjections   = "disk_fill_projections"
+	StateNameDiskFull          = "disk_full"
 
 	StateKeyData           = "data"
 	StateKeyEncoding       = "encoding"
@@ -96,6 +100,11 @@ func (o *Output) States() ([]components.State, error) {
 		}
 	}
 
+	fillProjectionsState, err := o.fillProjectionsState()
+	if err != nil {
+		return nil, err
+	}
+
 	return []components.State{
 		querySucceededState,
 		{
@@ -145,6 +154,77 @@ func (o *Output) States() ([]components.State, error) {
 				StateKeyEncoding: StateValueEncodingJSON,
 			},
 		},
+		fillProjectionsState,
+		o.diskFullState(),
+	}, nil
+}
+
+// diskFullState returns the unhealthy state
+// if any mounted partition has no free space or inodes left.
+func (o *Output) diskFullState() components.State {
+	var msgs []string
+	for _, p := range o.DiskExtPartitions {
+		if !p.Mounted || p.Usage == nil || p.Usage.TotalBytes == 0 {
+			continue
+		}
+		if p.Usage.FreeBytes == 0 {
+			msgs = append(msgs, fmt.Sprintf("%s has no free space (%s used)", p.MountPoint, p.Usage.UsedHumanized))
+		} else if p.Usage.InodesTotal > 0 && p.Usage.InodesFree == 0 {
+			msgs = append(msgs, fmt.Sprintf("%s has no free inodes (%d used)", p.MountPoint, p.Usage.InodesUsed))
+		}
+	}
+
+	if len(msgs) == 0 {
+		return components.State{
+			Name:    StateNameDiskFull,
+			Healthy: true,
+			Health:  components.StateHealthy,
+			Reason:  "no full filesystem found",
+		}
+	}
+	return components.State{
+		Name:       StateNameDiskFull,
+		Healthy:    false,
+		Health:     components.StateUnhealthy,
+		Reason:     strings.Join(msgs, ", "),
+		ReasonCode: components.ReasonCodeDiskFull,
+	}
+}
+
+// fillProjectionsState returns the degraded state
+// if any mount point is projected to be full within the horizon.
+func (o *Output) fillProjectionsState() (components.State, error) {
+	var data []byte
+	if len(o.FillProjections) > 0 {
+		var err error
+		data, err = json.Marshal(o.FillProjections)
+		if err != nil {
+			return components.State{}, err
+		}
+	}
+
+	var msgs []string
+	for _, p := range o.FillProjections {
+		if p.WithinHorizon {
+			msgs = append(msgs, fmt.Sprintf("%s projected to be full in %.1f hours", p.MountPoint, p.HoursUntilFull))
+		}
+	}
+
+	health := components.StateHealthy
+	reason := fmt.Sprintf("no mount point projected to be full (%d projected)", len(o.FillProjections))
+	if len(msgs) > 0 {
+		health = components.StateDegraded
+		reason = strings.Join(msgs, ", ")
+	}
+	return components.State{
+		Name:    StateNameFillProjections,
+		Healthy: health == components.StateHealthy,
+		Health:  health,
+		Reason:  reason,
+		ExtraInfo: map[string]string{
+			StateKeyData:     string(data),
+			StateKeyEncoding: StateValueEncodingJSON,
+		},
 	}, nil
 }
 
@@ -187,6 +267,20 @@ func CreateGet(cfg Config) query.GetFunc {
 		mountPointsToTrackUsage[mt] = struct{}{}
 	}
 
+	window := cfg.FillProjectionWindow.Duration
+	if window == 0 {
+		window = DefaultFillProjectionWindow
+	}
+	horizon := cfg.FillProjectionHorizon.Duration
+	if horizon == 0 {
+		horizon = DefaultFillProjectionHorizon
+	}
+	projector := newFillProjector(window)
+	lsblkRuns := cfg.LsblkRuns
+	if lsblkRuns == 0 {
+		lsblkRuns = DefaultLsblkRuns
+	}
+
 	return func(ctx context.Context) (_ any, e error) {
 		defer func() {
 			if e != nil {
@@ -201,9 +295,13 @@ func CreateGet(cfg Config) query.GetFunc {
 		prevFailed := false
 		for i := 0; i < 5; i++ {
 			cctx, ccancel := context.WithTimeout(ctx, time.Minute)
-			blks, err := disk.GetBlockDevices(cctx, disk.WithDeviceType(func(dt string) bool {
-				return dt == "disk"
-			}))
+			blks, err := disk.GetBlockDevices(
+				cctx,
+				disk.WithDeviceType(func(dt string) bool {
+					return dt == "disk"
+				}),
+				disk.WithLsblkRuns(lsblkRuns),
+			)
 			ccancel()
 			if err != nil {
 				log.Logger.Errorw("failed to get block devices", "error", err)
@@ -225,6 +323,8 @@ func CreateGet(cfg Config) query.GetFunc {
 			break
 		}
 		if len(o.DiskBlockDevices) == 0 {
+			// polled every interval, thus only warn once in a while
+			log.Sampled("disk-no-block-device", 10*time.Minute).Warnw("no block device found")
 			return nil, errors.New("no block device found")
 		}
 
@@ -287,6 +387,17 @@ func CreateGet(cfg Config) query.GetFunc {
 				return nil, err
 			}
 			metrics.SetUsedInodesPercent(p.MountPoint, usage.InodesUsedPercentFloat)
+
+			hours, ok := projector.observe(p.MountPoint, now, usage.UsedBytes, usage.FreeBytes)
+			if !ok {
+				continue
+			}
+			metrics.SetHoursUntilFull(p.MountPoint, hours)
+			o.FillProjections = append(o.FillProjections, FillProjection{
+				MountPoint:     p.MountPoint,
+				HoursUntilFull: hours,
+				WithinHorizon:  hours != HoursUntilFullNever && hours < horizon.Hours(),
+			})
 		}
 
 		for _, target := range cfg.MountTargetsToTrackUsage {
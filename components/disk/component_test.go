@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/leptonai/gpud/components"
 	query_config "github.com/leptonai/gpud/components/query/config"
+	"github.com/leptonai/gpud/pkg/disk"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -33,3 +35,57 @@ func TestComponent(t *testing.T) {
 	}
 	t.Logf("states: %+v", states)
 }
+
+func TestComponentProbe(t *testing.T) {
+	c := &component{mountPoints: []string{t.TempDir()}}
+	if result := c.Probe(context.Background()); !result.Passed {
+		t.Errorf("expected probe passed, got %+v", result)
+	}
+
+	c = &component{mountPoints: []string{"/does/not/exist"}}
+	if result := c.Probe(context.Background()); result.Passed || result.Error == "" {
+		t.Errorf("expected probe failed, got %+v", result)
+	}
+}
+
+func TestOutputDiskFullState(t *testing.T) {
+	o := &Output{
+		DiskExtPartitions: disk.Partitions{
+			{MountPoint: "/", Mounted: true, Usage: &disk.Usage{TotalBytes: 100, FreeBytes: 50, UsedBytes: 50, InodesTotal: 10, InodesFree: 5}},
+			// not mounted, thus ignored
+			{MountPoint: "/mnt", Mounted: false, Usage: &disk.Usage{TotalBytes: 100}},
+		},
+	}
+
+	states, err := o.States()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := states[len(states)-1]
+	if st.Name != StateNameDiskFull || !st.Healthy || st.ReasonCode != "" {
+		t.Fatalf("expected healthy disk full state, got %+v", st)
+	}
+
+	o.DiskExtPartitions = append(o.DiskExtPartitions, disk.Partition{
+		MountPoint: "/data",
+		Mounted:    true,
+		Usage:      &disk.Usage{TotalBytes: 100, FreeBytes: 0, UsedBytes: 100, UsedHumanized: "100 B"},
+	})
+	st = o.diskFullState()
+	if st.Healthy || st.Health != components.StateUnhealthy {
+		t.Fatalf("expected unhealthy state, got %+v", st)
+	}
+	if st.ReasonCode != components.ReasonCodeDiskFull {
+		t.Errorf("expected reason code %q, got %q", components.ReasonCodeDiskFull, st.ReasonCode)
+	}
+	if st.Reason != "/data has no free space (100 B used)" {
+		t.Errorf("unexpected reason %q", st.Reason)
+	}
+
+	// out of inodes
+	o.DiskExtPartitions[2].Usage = &disk.Usage{TotalBytes: 100, FreeBytes: 10, InodesTotal: 10, InodesUsed: 10}
+	st = o.diskFullState()
+	if st.ReasonCode != components.ReasonCodeDiskFull || st.Reason != "/data has no free inodes (10 used)" {
+		t.Errorf("unexpected state %+v", st)
+	}
+}
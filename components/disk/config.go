@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"time"
 
 	query_config "github.com/leptonai/gpud/components/query/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Config struct {
@@ -17,8 +20,27 @@ type Config struct {
 
 	// Mount targets to track the disk usage for (e.g., /var/lib/kubelet).
 	MountTargetsToTrackUsage []string `json:"mount_targets_to_track_usage"`
+
+	// Window of the recent usage samples to project the time until the mount point is full.
+	// Defaults to DefaultFillProjectionWindow if zero.
+	FillProjectionWindow metav1.Duration `json:"fill_projection_window"`
+
+	// Mount points projected to be full within the horizon are reported as degraded.
+	// Defaults to DefaultFillProjectionHorizon if zero.
+	FillProjectionHorizon metav1.Duration `json:"fill_projection_horizon"`
+
+	// Number of lsblk runs whose results are merged per poll,
+	// to tolerate the controllers that intermittently omit a device.
+	// Defaults to DefaultLsblkRuns if zero.
+	LsblkRuns int `json:"lsblk_runs"`
 }
 
+const (
+	DefaultFillProjectionWindow  = 6 * time.Hour
+	DefaultFillProjectionHorizon = 24 * time.Hour
+	DefaultLsblkRuns             = 2
+)
+
 func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
 	raw, err := json.Marshal(b)
 	if err != nil {
@@ -47,5 +69,15 @@ func (cfg Config) Validate() error {
 		}
 	}
 
+	if cfg.FillProjectionWindow.Duration < 0 {
+		return errors.New("fill projection window must be non-negative")
+	}
+	if cfg.FillProjectionHorizon.Duration < 0 {
+		return errors.New("fill projection horizon must be non-negative")
+	}
+	if cfg.LsblkRuns < 0 {
+		return errors.New("lsblk runs must be non-negative")
+	}
+
 	return nil
 }
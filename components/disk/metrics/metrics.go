@@ -97,6 +97,16 @@ var (
 		},
 		[]string{"mount_point"},
 	)
+
+	hoursUntilFull = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "hours_until_full",
+			Help:      "tracks the projected hours until the disk is full from the recent usage trend (-1 if never)",
+		},
+		[]string{"mount_point"},
+	)
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
@@ -198,6 +208,10 @@ func SetUsedInodesPercent(mountPoint string, pct float64) {
 	usedInodesPercent.WithLabelValues(mountPoint).Set(pct)
 }
 
+func SetHoursUntilFull(mountPoint string, hours float64) {
+	hoursUntilFull.WithLabelValues(mountPoint).Set(hours)
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -225,5 +239,8 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(usedInodesPercent); err != nil {
 		return err
 	}
+	if err := reg.Register(hoursUntilFull); err != nil {
+		return err
+	}
 	return nil
 }
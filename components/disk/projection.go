@@ -0,0 +1,93 @@
+package disk
+
+import (
+	"time"
+)
+
+// HoursUntilFullNever is the projected hours until full
+// when the usage is flat or shrinking, thus never to be full.
+const HoursUntilFullNever = float64(-1)
+
+// minFillProjectionSamples is the minimum number of usage samples
+// in the window to project the time until full.
+const minFillProjectionSamples = 3
+
+// FillProjection is the projected time until the mount point is full,
+// based on the linear trend of the recent usage samples.
+type FillProjection struct {
+	MountPoint string `json:"mount_point"`
+	// HoursUntilFull is the projected hours until the free space runs out,
+	// or HoursUntilFullNever if the usage is not growing.
+	HoursUntilFull float64 `json:"hours_until_full"`
+	// WithinHorizon is true if projected to be full within the configured horizon.
+	WithinHorizon bool `json:"within_horizon"`
+}
+
+type usageSample struct {
+	time      time.Time
+	usedBytes float64
+}
+
+// fillProjector keeps the usage samples per mount point within the rolling window,
+// to project the time until full with the least squares linear fit.
+type fillProjector struct {
+	window  time.Duration
+	samples map[string][]usageSample
+}
+
+func newFillProjector(window time.Duration) *fillProjector {
+	return &fillProjector{
+		window:  window,
+		samples: make(map[string][]usageSample),
+	}
+}
+
+// observe adds the usage sample of the mount point, evicting the samples older than the window,
+// and returns the projected hours until the free bytes run out.
+// Returns false if there are not enough samples to project.
+func (p *fillProjector) observe(mountPoint string, now time.Time, usedBytes uint64, freeBytes uint64) (float64, bool) {
+	samples := append(p.samples[mountPoint], usageSample{time: now, usedBytes: float64(usedBytes)})
+
+	cutoff := now.Add(-p.window)
+	first := 0
+	for first < len(samples) && samples[first].time.Before(cutoff) {
+		first++
+	}
+	samples = samples[first:]
+	p.samples[mountPoint] = samples
+
+	if len(samples) < minFillProjectionSamples {
+		return 0, false
+	}
+
+	bytesPerSecond, ok := linearSlope(samples)
+	if !ok {
+		return 0, false
+	}
+	if bytesPerSecond <= 0 {
+		return HoursUntilFullNever, true
+	}
+	return float64(freeBytes) / bytesPerSecond / time.Hour.Seconds(), true
+}
+
+// linearSlope returns the least squares slope of the used bytes per second.
+func linearSlope(samples []usageSample) (float64, bool) {
+	n := float64(len(samples))
+	start := samples[0].time
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.time.Sub(start).Seconds()
+		sumX += x
+		sumY += s.usedBytes
+		sumXY += x * s.usedBytes
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		// all samples at the same time
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denom, true
+}
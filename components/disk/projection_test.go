@@ -0,0 +1,115 @@
+package disk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+)
+
+func TestFillProjectorIncreasing(t *testing.T) {
+	p := newFillProjector(6 * time.Hour)
+
+	const gb = uint64(1e9)
+	start := time.Unix(1737432104, 0)
+
+	// 1 GB per hour with 50 GB free at the start
+	var (
+		hours float64
+		ok    bool
+	)
+	for i := 0; i <= 18; i++ {
+		now := start.Add(time.Duration(i) * 10 * time.Minute)
+		used := 50*gb + uint64(i)*gb/6
+		free := 50*gb - uint64(i)*gb/6
+		hours, ok = p.observe("/", now, used, free)
+		if i < minFillProjectionSamples-1 && ok {
+			t.Fatalf("expected no projection with %d samples", i+1)
+		}
+	}
+	if !ok {
+		t.Fatal("expected projection")
+	}
+	// 47 GB free after 3 hours
+	if math.Abs(hours-47) > 0.01 {
+		t.Errorf("expected ~47 hours until full, got %.2f", hours)
+	}
+}
+
+func TestFillProjectorShrinking(t *testing.T) {
+	p := newFillProjector(time.Hour)
+
+	start := time.Unix(1737432104, 0)
+	var (
+		hours float64
+		ok    bool
+	)
+	for i := 0; i < 5; i++ {
+		hours, ok = p.observe("/", start.Add(time.Duration(i)*time.Minute), uint64(100-i), uint64(i))
+	}
+	if !ok || hours != HoursUntilFullNever {
+		t.Errorf("expected never, got %v (%v)", hours, ok)
+	}
+
+	// flat usage
+	for i := 5; i < 10; i++ {
+		hours, ok = p.observe("/flat", start.Add(time.Duration(i)*time.Minute), 100, 0)
+	}
+	if !ok || hours != HoursUntilFullNever {
+		t.Errorf("expected never for flat usage, got %v (%v)", hours, ok)
+	}
+}
+
+func TestFillProjectorWindow(t *testing.T) {
+	p := newFillProjector(30 * time.Minute)
+
+	start := time.Unix(1737432104, 0)
+
+	// shrinking, then growing after the window
+	for i := 0; i < 5; i++ {
+		p.observe("/", start.Add(time.Duration(i)*time.Minute), uint64(1000-i*100), 0)
+	}
+	var (
+		hours float64
+		ok    bool
+	)
+	for i := 0; i < 3; i++ {
+		hours, ok = p.observe("/", start.Add(time.Hour+time.Duration(i)*time.Hour/60), uint64(i*3600), 3600*10)
+	}
+	if len(p.samples["/"]) != 3 {
+		t.Fatalf("expected the old samples evicted, got %d samples", len(p.samples["/"]))
+	}
+	// 60 bytes per second with 36000 bytes free
+	if !ok || math.Abs(hours-36000.0/60/3600) > 1e-9 {
+		t.Errorf("unexpected projection %v (%v)", hours, ok)
+	}
+}
+
+func TestOutputFillProjectionsState(t *testing.T) {
+	o := &Output{
+		FillProjections: []FillProjection{
+			{MountPoint: "/", HoursUntilFull: HoursUntilFullNever},
+			{MountPoint: "/data", HoursUntilFull: 47},
+		},
+	}
+	st, err := o.fillProjectionsState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Healthy {
+		t.Errorf("expected healthy state, got %+v", st)
+	}
+
+	o.FillProjections[1].WithinHorizon = true
+	st, err = o.fillProjectionsState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Healthy || st.Health != components.StateDegraded {
+		t.Errorf("expected degraded state, got %+v", st)
+	}
+	if st.Reason != "/data projected to be full in 47.0 hours" {
+		t.Errorf("unexpected reason %q", st.Reason)
+	}
+}
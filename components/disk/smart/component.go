@@ -0,0 +1,144 @@
+// Package smart monitors the drive health from the SMART attributes reported by smartctl.
+package smart
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/leptonai/gpud/components"
+	disk_smart_id "github.com/leptonai/gpud/components/disk/smart/id"
+	"github.com/leptonai/gpud/components/disk/smart/metrics"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+)
+
+func New(ctx context.Context, cfg Config) components.Component {
+	cfg.Query.SetDefaultsIfNotSet()
+	setDefaultPoller(cfg)
+
+	cctx, ccancel := context.WithCancel(ctx)
+	getDefaultPoller().Start(cctx, cfg.Query, disk_smart_id.Name)
+
+	return &component{
+		cfg:     cfg,
+		rootCtx: ctx,
+		cancel:  ccancel,
+		poller:  getDefaultPoller(),
+	}
+}
+
+var _ components.Component = (*component)(nil)
+
+type component struct {
+	cfg      Config
+	rootCtx  context.Context
+	cancel   context.CancelFunc
+	poller   query.Poller
+	gatherer prometheus.Gatherer
+}
+
+func (c *component) Name() string { return disk_smart_id.Name }
+
+func (c *component) Start() error { return nil }
+
+func (c *component) States(ctx context.Context) ([]components.State, error) {
+	last, err := c.poller.Last()
+	if err == query.ErrNoData { // no data
+		log.Logger.Debugw("nothing found in last state (no data collected yet)", "component", disk_smart_id.Name)
+		return []components.State{
+			{
+				Name:    disk_smart_id.Name,
+				Healthy: true,
+				Reason:  query.ErrNoData.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if last.Error != nil {
+		return []components.State{
+			{
+				Name:    disk_smart_id.Name,
+				Healthy: false,
+				Error:   last.Error.Error(),
+				Reason:  "last query failed",
+			},
+		}, nil
+	}
+	if last.Output == nil {
+		return []components.State{
+			{
+				Name:    disk_smart_id.Name,
+				Healthy: true,
+				Reason:  "no output",
+			},
+		}, nil
+	}
+
+	output, ok := last.Output.(*Output)
+	if !ok {
+		return nil, fmt.Errorf("invalid output type: %T", last.Output)
+	}
+	return output.States(c.cfg)
+}
+
+func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
+	return nil, nil
+}
+
+func (c *component) Metrics(ctx context.Context, since time.Time) ([]components.Metric, error) {
+	log.Logger.Debugw("querying metrics", "since", since)
+
+	temperatures, err := metrics.ReadTemperatureCelsius(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read temperatures: %w", err)
+	}
+	reallocated, err := metrics.ReadReallocatedSectors(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reallocated sectors: %w", err)
+	}
+	mediaErrors, err := metrics.ReadMediaErrors(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media errors: %w", err)
+	}
+	percentageUsed, err := metrics.ReadPercentageUsed(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read percentage used: %w", err)
+	}
+
+	ms := make([]components.Metric, 0, len(temperatures)+len(reallocated)+len(mediaErrors)+len(percentageUsed))
+	for _, m := range temperatures {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	for _, m := range reallocated {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	for _, m := range mediaErrors {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	for _, m := range percentageUsed {
+		ms = append(ms, components.Metric{Metric: m})
+	}
+	return ms, nil
+}
+
+func (c *component) Close() error {
+	log.Logger.Debugw("closing component")
+
+	// safe to call stop multiple times
+	_ = c.poller.Stop(disk_smart_id.Name)
+
+	return nil
+}
+
+var _ components.PromRegisterer = (*component)(nil)
+
+func (c *component) RegisterCollectors(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	c.gatherer = reg
+	return metrics.Register(reg, dbRW, dbRO, tableName)
+}
@@ -0,0 +1,198 @@
+package smart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	disk_smart_id "github.com/leptonai/gpud/components/disk/smart/id"
+	"github.com/leptonai/gpud/components/disk/smart/metrics"
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/disk"
+)
+
+type Output struct {
+	// SmartctlNotFound is true if smartctl is not installed on the host.
+	SmartctlNotFound bool             `json:"smartctl_not_found,omitempty"`
+	Devices          []disk.SmartInfo `json:"devices"`
+}
+
+const (
+	StateKeyDevice   = "device"
+	StateKeyModel    = "model"
+	StateKeySerial   = "serial"
+	StateKeyProtocol = "protocol"
+)
+
+// States returns the health state per device, evaluating the SMART attributes
+// against the configured thresholds: degraded if any attribute reaches the warning threshold,
+// unhealthy if any attribute reaches the critical threshold or the SMART self-assessment fails.
+// The devices not supporting SMART are reported healthy, since the component is not applicable.
+func (o *Output) States(cfg Config) ([]components.State, error) {
+	if o.SmartctlNotFound {
+		return []components.State{
+			{
+				Name:    disk_smart_id.Name,
+				Healthy: true,
+				Health:  components.StateHealthy,
+				Reason:  "smartctl not found (not applicable)",
+			},
+		}, nil
+	}
+	if len(o.Devices) == 0 {
+		return []components.State{
+			{
+				Name:    disk_smart_id.Name,
+				Healthy: true,
+				Health:  components.StateHealthy,
+				Reason:  "no device found (not applicable)",
+			},
+		}, nil
+	}
+
+	states := make([]components.State, 0, len(o.Devices))
+	for _, dev := range o.Devices {
+		health, reason := evaluate(dev, cfg)
+		states = append(states, components.State{
+			Name:    dev.Device,
+			Healthy: health == components.StateHealthy,
+			Health:  health,
+			Reason:  reason,
+			ExtraInfo: map[string]string{
+				StateKeyDevice:   dev.Device,
+				StateKeyModel:    dev.Model,
+				StateKeySerial:   dev.Serial,
+				StateKeyProtocol: dev.Protocol,
+			},
+		})
+	}
+	return states, nil
+}
+
+func evaluate(dev disk.SmartInfo, cfg Config) (string, string) {
+	if !dev.Supported {
+		return components.StateHealthy, fmt.Sprintf("%s SMART data not available (%s)", dev.Device, dev.Message)
+	}
+
+	var warnings, criticals []string
+	if !dev.Passed {
+		criticals = append(criticals, "SMART overall-health self-assessment failed")
+	}
+	for _, attr := range []struct {
+		name  string
+		value *int64
+		th    Threshold
+	}{
+		{"reallocated sectors", dev.ReallocatedSectors, cfg.ReallocatedSectors},
+		{"media errors", dev.MediaErrors, cfg.MediaErrors},
+		{"percentage used", dev.PercentageUsed, cfg.PercentageUsed},
+		{"temperature celsius", dev.TemperatureCelsius, cfg.TemperatureCelsius},
+	} {
+		if attr.value == nil {
+			continue
+		}
+		switch {
+		case *attr.value >= attr.th.Critical:
+			criticals = append(criticals, fmt.Sprintf("%s %d reached critical threshold %d", attr.name, *attr.value, attr.th.Critical))
+		case *attr.value >= attr.th.Warning:
+			warnings = append(warnings, fmt.Sprintf("%s %d reached warning threshold %d", attr.name, *attr.value, attr.th.Warning))
+		}
+	}
+
+	switch {
+	case len(criticals) > 0:
+		return components.StateUnhealthy, dev.Device + ": " + strings.Join(append(criticals, warnings...), ", ")
+	case len(warnings) > 0:
+		return components.StateDegraded, dev.Device + ": " + strings.Join(warnings, ", ")
+	default:
+		return components.StateHealthy, dev.Device + " SMART attributes within thresholds"
+	}
+}
+
+var (
+	defaultPollerOnce sync.Once
+	defaultPoller     query.Poller
+)
+
+func setDefaultPoller(cfg Config) {
+	defaultPollerOnce.Do(func() {
+		defaultPoller = query.New(
+			disk_smart_id.Name,
+			cfg.Query,
+			CreateGet(),
+			nil,
+		)
+	})
+}
+
+func getDefaultPoller() query.Poller {
+	return defaultPoller
+}
+
+func CreateGet() query.GetFunc {
+	return func(ctx context.Context) (_ any, e error) {
+		defer func() {
+			if e != nil {
+				components_metrics.SetGetFailed(disk_smart_id.Name)
+			} else {
+				components_metrics.SetGetSuccess(disk_smart_id.Name)
+			}
+		}()
+
+		devs, err := disk.ScanSmartDevices(ctx)
+		if errors.Is(err, disk.ErrSmartctlNotFound) {
+			return &Output{SmartctlNotFound: true}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		infos := make([]disk.SmartInfo, 0, len(devs))
+		for _, dev := range devs {
+			info, err := disk.GetSmartInfo(ctx, dev)
+			if err != nil {
+				log.Logger.Warnw("failed to get smart info", "device", dev.Name, "error", err)
+				info = disk.SmartInfo{Device: dev.Name, Type: dev.Type, Protocol: dev.Protocol, Message: err.Error()}
+			}
+			infos = append(infos, info)
+		}
+
+		if err := setMetrics(ctx, infos, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		return &Output{Devices: infos}, nil
+	}
+}
+
+func setMetrics(ctx context.Context, infos []disk.SmartInfo, now time.Time) error {
+	metrics.SetLastUpdateUnixSeconds(float64(now.Unix()))
+	for _, info := range infos {
+		if info.TemperatureCelsius != nil {
+			if err := metrics.SetTemperatureCelsius(ctx, info.Device, *info.TemperatureCelsius, now); err != nil {
+				return err
+			}
+		}
+		if info.ReallocatedSectors != nil {
+			if err := metrics.SetReallocatedSectors(ctx, info.Device, *info.ReallocatedSectors, now); err != nil {
+				return err
+			}
+		}
+		if info.MediaErrors != nil {
+			if err := metrics.SetMediaErrors(ctx, info.Device, *info.MediaErrors, now); err != nil {
+				return err
+			}
+		}
+		if info.PercentageUsed != nil {
+			if err := metrics.SetPercentageUsed(ctx, info.Device, *info.PercentageUsed, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
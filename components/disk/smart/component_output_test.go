@@ -0,0 +1,137 @@
+package smart
+
+import (
+	"testing"
+
+	"github.com/leptonai/gpud/components"
+	disk_smart_id "github.com/leptonai/gpud/components/disk/smart/id"
+	"github.com/leptonai/gpud/pkg/disk"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestOutputStates(t *testing.T) {
+	cfg := Config{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		output     Output
+		wantName   string
+		wantHealth string
+	}{
+		{
+			name:       "smartctl not found",
+			output:     Output{SmartctlNotFound: true},
+			wantName:   disk_smart_id.Name,
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name:       "no device",
+			output:     Output{},
+			wantName:   disk_smart_id.Name,
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name: "not supported",
+			output: Output{Devices: []disk.SmartInfo{
+				{Device: "/dev/sdb", Message: "Unable to detect device type"},
+			}},
+			wantName:   "/dev/sdb",
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name: "healthy nvme",
+			output: Output{Devices: []disk.SmartInfo{
+				{Device: "/dev/nvme0", Supported: true, Passed: true, TemperatureCelsius: int64Ptr(41), MediaErrors: int64Ptr(0), PercentageUsed: int64Ptr(3)},
+			}},
+			wantName:   "/dev/nvme0",
+			wantHealth: components.StateHealthy,
+		},
+		{
+			name: "reallocated sectors warning",
+			output: Output{Devices: []disk.SmartInfo{
+				{Device: "/dev/sda", Supported: true, Passed: true, ReallocatedSectors: int64Ptr(12), PercentageUsed: int64Ptr(9)},
+			}},
+			wantName:   "/dev/sda",
+			wantHealth: components.StateDegraded,
+		},
+		{
+			name: "media errors critical",
+			output: Output{Devices: []disk.SmartInfo{
+				{Device: "/dev/nvme1", Supported: true, Passed: true, MediaErrors: int64Ptr(10)},
+			}},
+			wantName:   "/dev/nvme1",
+			wantHealth: components.StateUnhealthy,
+		},
+		{
+			name: "self-assessment failed",
+			output: Output{Devices: []disk.SmartInfo{
+				{Device: "/dev/sdc", Supported: true, Passed: false},
+			}},
+			wantName:   "/dev/sdc",
+			wantHealth: components.StateUnhealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := tt.output.States(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 state, got %d", len(states))
+			}
+			if states[0].Name != tt.wantName {
+				t.Errorf("expected name %q, got %q", tt.wantName, states[0].Name)
+			}
+			if states[0].Health != tt.wantHealth {
+				t.Errorf("expected health %q, got %q (%s)", tt.wantHealth, states[0].Health, states[0].Reason)
+			}
+			if states[0].Healthy != (tt.wantHealth == components.StateHealthy) {
+				t.Errorf("unexpected healthy %v for health %q", states[0].Healthy, states[0].Health)
+			}
+		})
+	}
+}
+
+func TestOutputStatesCustomThresholds(t *testing.T) {
+	cfg := Config{TemperatureCelsius: Threshold{Warning: 30, Critical: 40}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	o := Output{Devices: []disk.SmartInfo{
+		{Device: "/dev/sda", Supported: true, Passed: true, TemperatureCelsius: int64Ptr(34)},
+		{Device: "/dev/nvme0", Supported: true, Passed: true, TemperatureCelsius: int64Ptr(41)},
+	}}
+	states, err := o.States(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if states[0].Health != components.StateDegraded || states[1].Health != components.StateUnhealthy {
+		t.Errorf("unexpected states %+v", states)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := Config{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ReallocatedSectors != DefaultReallocatedSectorsThreshold ||
+		cfg.MediaErrors != DefaultMediaErrorsThreshold ||
+		cfg.PercentageUsed != DefaultPercentageUsedThreshold ||
+		cfg.TemperatureCelsius != DefaultTemperatureCelsiusThreshold {
+		t.Errorf("unexpected defaults %+v", cfg)
+	}
+
+	cfg = Config{TemperatureCelsius: Threshold{Warning: 80, Critical: 70}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for warning threshold exceeding critical")
+	}
+}
@@ -0,0 +1,82 @@
+package smart
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	query_config "github.com/leptonai/gpud/components/query/config"
+)
+
+type Config struct {
+	Query query_config.Config `json:"query"`
+
+	// ReallocatedSectors is the thresholds of the reallocated sector count (ATA only).
+	ReallocatedSectors Threshold `json:"reallocated_sectors"`
+	// MediaErrors is the thresholds of the media errors
+	// (NVMe media errors, or ATA reported uncorrectable errors).
+	MediaErrors Threshold `json:"media_errors"`
+	// PercentageUsed is the thresholds of the estimated percentage of the device life used.
+	PercentageUsed Threshold `json:"percentage_used"`
+	// TemperatureCelsius is the thresholds of the drive temperature.
+	TemperatureCelsius Threshold `json:"temperature_celsius"`
+}
+
+// Threshold is the warning and critical thresholds of a SMART attribute.
+// The device is degraded if the attribute value is greater than or equal to the warning threshold,
+// and unhealthy if greater than or equal to the critical threshold.
+type Threshold struct {
+	Warning  int64 `json:"warning"`
+	Critical int64 `json:"critical"`
+}
+
+func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	err = json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Query.State != nil {
+		cfg.Query.State.DBRW = dbRW
+		cfg.Query.State.DBRO = dbRO
+	}
+	return cfg, nil
+}
+
+var (
+	DefaultReallocatedSectorsThreshold = Threshold{Warning: 1, Critical: 100}
+	DefaultMediaErrorsThreshold        = Threshold{Warning: 1, Critical: 10}
+	DefaultPercentageUsedThreshold     = Threshold{Warning: 80, Critical: 100}
+	DefaultTemperatureCelsiusThreshold = Threshold{Warning: 65, Critical: 75}
+)
+
+func (cfg *Config) Validate() error {
+	for _, th := range []struct {
+		name string
+		th   *Threshold
+		def  Threshold
+	}{
+		{"reallocated_sectors", &cfg.ReallocatedSectors, DefaultReallocatedSectorsThreshold},
+		{"media_errors", &cfg.MediaErrors, DefaultMediaErrorsThreshold},
+		{"percentage_used", &cfg.PercentageUsed, DefaultPercentageUsedThreshold},
+		{"temperature_celsius", &cfg.TemperatureCelsius, DefaultTemperatureCelsiusThreshold},
+	} {
+		if th.th.Warning == 0 {
+			th.th.Warning = th.def.Warning
+		}
+		if th.th.Critical == 0 {
+			th.th.Critical = th.def.Critical
+		}
+		if th.th.Warning < 0 || th.th.Critical < 0 {
+			return fmt.Errorf("invalid %s threshold %+v", th.name, *th.th)
+		}
+		if th.th.Warning > th.th.Critical {
+			return fmt.Errorf("%s warning threshold %d exceeds critical threshold %d", th.name, th.th.Warning, th.th.Critical)
+		}
+	}
+	return nil
+}
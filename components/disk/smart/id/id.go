@@ -0,0 +1,5 @@
+// Package id represents the disk SMART component ID.
+package id
+
+// Name is the ID of the disk SMART component.
+const Name = "disk-smart"
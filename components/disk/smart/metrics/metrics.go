@@ -0,0 +1,149 @@
+// Package metrics implements the disk SMART attribute metrics collection and reporting.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	components_metrics "github.com/leptonai/gpud/components/metrics"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const SubSystem = "disk_smart"
+
+var (
+	lastUpdateUnixSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "last_update_unix_seconds",
+			Help:      "tracks the last update time in unix seconds",
+		},
+	)
+
+	temperatureCelsius = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "temperature_celsius",
+			Help:      "tracks the drive temperature in celsius",
+		},
+		[]string{"device"},
+	)
+	temperatureCelsiusAverager = components_metrics.NewNoOpAverager()
+
+	reallocatedSectors = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "reallocated_sectors",
+			Help:      "tracks the number of reallocated sectors",
+		},
+		[]string{"device"},
+	)
+	reallocatedSectorsAverager = components_metrics.NewNoOpAverager()
+
+	mediaErrors = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "media_errors",
+			Help:      "tracks the number of media errors",
+		},
+		[]string{"device"},
+	)
+	mediaErrorsAverager = components_metrics.NewNoOpAverager()
+
+	percentageUsed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "percentage_used",
+			Help:      "tracks the estimated percentage of the device life used",
+		},
+		[]string{"device"},
+	)
+	percentageUsedAverager = components_metrics.NewNoOpAverager()
+)
+
+func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
+	temperatureCelsiusAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_temperature_celsius")
+	reallocatedSectorsAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_reallocated_sectors")
+	mediaErrorsAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_media_errors")
+	percentageUsedAverager = components_metrics.NewAverager(dbRW, dbRO, tableName, SubSystem+"_percentage_used")
+}
+
+func ReadTemperatureCelsius(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return temperatureCelsiusAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadReallocatedSectors(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return reallocatedSectorsAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadMediaErrors(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return mediaErrorsAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func ReadPercentageUsed(ctx context.Context, since time.Time) (components_metrics_state.Metrics, error) {
+	return percentageUsedAverager.Read(ctx, components_metrics.WithSince(since))
+}
+
+func SetLastUpdateUnixSeconds(unixSeconds float64) {
+	lastUpdateUnixSeconds.Set(unixSeconds)
+}
+
+func SetTemperatureCelsius(ctx context.Context, device string, v int64, currentTime time.Time) error {
+	return set(ctx, temperatureCelsius, temperatureCelsiusAverager, device, v, currentTime)
+}
+
+func SetReallocatedSectors(ctx context.Context, device string, v int64, currentTime time.Time) error {
+	return set(ctx, reallocatedSectors, reallocatedSectorsAverager, device, v, currentTime)
+}
+
+func SetMediaErrors(ctx context.Context, device string, v int64, currentTime time.Time) error {
+	return set(ctx, mediaErrors, mediaErrorsAverager, device, v, currentTime)
+}
+
+func SetPercentageUsed(ctx context.Context, device string, v int64, currentTime time.Time) error {
+	return set(ctx, percentageUsed, percentageUsedAverager, device, v, currentTime)
+}
+
+func set(ctx context.Context, gauge *prometheus.GaugeVec, averager components_metrics.Averager, device string, v int64, currentTime time.Time) error {
+	gauge.WithLabelValues(device).Set(float64(v))
+
+	if err := averager.Observe(
+		ctx,
+		float64(v),
+		components_metrics.WithCurrentTime(currentTime),
+		components_metrics.WithMetricSecondaryName(device),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
+	InitAveragers(dbRW, dbRO, tableName)
+
+	if err := reg.Register(lastUpdateUnixSeconds); err != nil {
+		return err
+	}
+	if err := reg.Register(temperatureCelsius); err != nil {
+		return err
+	}
+	if err := reg.Register(reallocatedSectors); err != nil {
+		return err
+	}
+	if err := reg.Register(mediaErrors); err != nil {
+		return err
+	}
+	if err := reg.Register(percentageUsed); err != nil {
+		return err
+	}
+	return nil
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
 	info_id "github.com/leptonai/gpud/components/info/id"
+	"github.com/leptonai/gpud/internal/repair"
 	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/manager"
 	"github.com/leptonai/gpud/pkg/file"
@@ -79,6 +81,11 @@ const (
 	StateKeyGPUdStartTimeHumanized  = "gpud_start_time_humanized"
 
 	StateNameAnnotations = "annotations"
+
+	StateNameRepair = "repair"
+
+	StateKeyRebootRecommendedTotal = "reboot_recommended_total"
+	StateKeyRebootPerformedTotal   = "reboot_performed_total"
 )
 
 var (
@@ -167,7 +174,7 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 	}
 	gpudStartTimeHumanized := humanize.Time(time.Unix(int64(gpudStartTimeInUnixTime), 0))
 
-	return []components.State{
+	states := []components.State{
 		{
 			Name:    StateNameDaemon,
 			Healthy: true,
@@ -209,7 +216,25 @@ func (c *component) States(ctx context.Context) ([]components.State, error) {
 			Reason:    fmt.Sprintf("annotations: %v", c.annotations),
 			ExtraInfo: c.annotations,
 		},
-	}, nil
+	}
+
+	if c.dbRO != nil {
+		// persisted across restarts, to correlate the node instability
+		rebootCounts, err := repair.ReadCounts(ctx, c.dbRO, common.RepairActionTypeRebootSystem)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, components.State{
+			Name:    StateNameRepair,
+			Healthy: true,
+			Reason:  fmt.Sprintf("reboot recommended %d time(s), performed %d time(s)", rebootCounts.Recommended, rebootCounts.Performed),
+			ExtraInfo: map[string]string{
+				StateKeyRebootRecommendedTotal: fmt.Sprintf("%d", rebootCounts.Recommended),
+				StateKeyRebootPerformedTotal:   fmt.Sprintf("%d", rebootCounts.Performed),
+			},
+		})
+	}
+	return states, nil
 }
 
 func (c *component) Events(ctx context.Context, since time.Time) ([]components.Event, error) {
@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/internal/repair"
+	"github.com/leptonai/gpud/pkg/sqlite"
 )
 
 func TestComponent(t *testing.T) {
@@ -22,3 +26,36 @@ func TestComponent(t *testing.T) {
 
 	t.Logf("states: %+v", states)
 }
+
+func TestComponentRepairCounts(t *testing.T) {
+	ctx := context.Background()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := repair.CreateTableActionCounts(ctx, dbRW); err != nil {
+		t.Fatal(err)
+	}
+	if err := repair.IncRecommended(ctx, dbRW, common.RepairActionTypeRebootSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	component := New(nil, dbRO, prometheus.DefaultGatherer)
+	states, err := component.States(ctx)
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+
+	var found bool
+	for _, s := range states {
+		if s.Name != StateNameRepair {
+			continue
+		}
+		found = true
+		if s.ExtraInfo[StateKeyRebootRecommendedTotal] != "1" || s.ExtraInfo[StateKeyRebootPerformedTotal] != "0" {
+			t.Errorf("unexpected repair counts %+v", s.ExtraInfo)
+		}
+	}
+	if !found {
+		t.Errorf("expected %q state, got %+v", StateNameRepair, states)
+	}
+}
@@ -14,8 +14,10 @@ import (
 	network_latency_id "github.com/leptonai/gpud/components/network/latency/id"
 	"github.com/leptonai/gpud/components/network/latency/metrics"
 	"github.com/leptonai/gpud/components/query"
+	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/pkg/latency"
 	latency_edge "github.com/leptonai/gpud/pkg/latency/edge"
+	"github.com/leptonai/gpud/pkg/latency/edge/derpmap"
 )
 
 type Output struct {
@@ -124,6 +126,10 @@ func getDefaultPoller() query.Poller {
 
 const minLatencyTimeout = 30 * time.Second
 
+// defaultLatencyStats tracks the recent latencies per edge region
+// across the polls to compute the percentiles.
+var defaultLatencyStats = newLatencyStats(defaultLatencyStatsWindow)
+
 func createGetFunc(cfg Config) query.GetFunc {
 	timeout := time.Duration(2*cfg.GlobalMillisecondThreshold) * time.Millisecond
 	if timeout < minLatencyTimeout {
@@ -150,21 +156,37 @@ func createGetFunc(cfg Config) query.GetFunc {
 		cctx, ccancel := context.WithTimeout(ctx, timeout)
 		defer ccancel()
 
-		var err error
-		o.EgressLatencies, err = latency_edge.Measure(cctx)
+		dm, refreshed, err := derpmap.LoadWithRefresh(ctx, cfg.DERPMapMaxAge.Duration)
+		if err != nil {
+			log.Logger.Warnw("failed to refresh derp map, using the previous one", "error", err)
+		} else if refreshed {
+			log.Logger.Infow("refreshed derp map", "regions", len(dm.Regions))
+		}
+
+		o.EgressLatencies, err = latency_edge.Measure(cctx, latency_edge.WithDERPMap(dm))
 		if err != nil {
 			return nil, err
 		}
 
 		for _, latency := range o.EgressLatencies {
+			providerRegion := fmt.Sprintf("%s (%s)", latency.RegionName, latency.Provider)
 			if err := metrics.SetEdgeInMilliseconds(
 				cctx,
-				fmt.Sprintf("%s (%s)", latency.RegionName, latency.Provider),
+				providerRegion,
 				float64(latency.LatencyMilliseconds),
 				now,
 			); err != nil {
 				return nil, err
 			}
+
+			defaultLatencyStats.Add(providerRegion, latency.Latency.Duration)
+			p50, p90, p99 := defaultLatencyStats.Percentiles(providerRegion)
+			metrics.SetEdgePercentilesInMilliseconds(
+				providerRegion,
+				float64(p50.Milliseconds()),
+				float64(p90.Milliseconds()),
+				float64(p99.Milliseconds()),
+			)
 		}
 
 		return o, nil
@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	query_config "github.com/leptonai/gpud/components/query/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -22,6 +24,11 @@ type Config struct {
 	// If all DERP latencies are greater than this threshold, the component will be marked as failed.
 	// If at least one DERP latency is less than this threshold, the component will be marked as healthy.
 	GlobalMillisecondThreshold int64 `json:"global_millisecond_threshold"`
+
+	// DERPMapMaxAge is the maximum age of the DERP map before refreshing it
+	// from the public Tailscale endpoint. The embedded DERP map is used
+	// until the first successful refresh. Zero disables the refresh.
+	DERPMapMaxAge metav1.Duration `json:"derp_map_max_age"`
 }
 
 func ParseConfig(b any, dbRW *sql.DB, dbRO *sql.DB) (*Config, error) {
@@ -45,5 +52,8 @@ func (cfg Config) Validate() error {
 	if cfg.GlobalMillisecondThreshold > 0 && cfg.GlobalMillisecondThreshold < MinGlobalMillisecondThreshold {
 		return fmt.Errorf("global millisecond threshold must be greater than %d", MinGlobalMillisecondThreshold)
 	}
+	if cfg.DERPMapMaxAge.Duration < 0 {
+		return fmt.Errorf("derp map max age must be non-negative, got %s", cfg.DERPMapMaxAge.Duration)
+	}
 	return nil
 }
@@ -34,6 +34,16 @@ var (
 		[]string{"provider_region"},
 	)
 	edgeInMillisecondsAverager = components_metrics.NewNoOpAverager()
+
+	edgePercentileInMilliseconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "",
+			Subsystem: SubSystem,
+			Name:      "edge_percentile_in_milliseconds",
+			Help:      "tracks the edge latency percentiles in milliseconds over the recent measurements",
+		},
+		[]string{"provider_region", "percentile"}, // percentile is one of "p50", "p90", "p99"
+	)
 )
 
 func InitAveragers(dbRW *sql.DB, dbRO *sql.DB, tableName string) {
@@ -62,6 +72,12 @@ func SetEdgeInMilliseconds(ctx context.Context, providerRegion string, latencyIn
 	return nil
 }
 
+func SetEdgePercentilesInMilliseconds(providerRegion string, p50, p90, p99 float64) {
+	edgePercentileInMilliseconds.WithLabelValues(providerRegion, "p50").Set(p50)
+	edgePercentileInMilliseconds.WithLabelValues(providerRegion, "p90").Set(p90)
+	edgePercentileInMilliseconds.WithLabelValues(providerRegion, "p99").Set(p99)
+}
+
 func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName string) error {
 	InitAveragers(dbRW, dbRO, tableName)
 
@@ -71,5 +87,8 @@ func Register(reg *prometheus.Registry, dbRW *sql.DB, dbRO *sql.DB, tableName st
 	if err := reg.Register(edgeInMilliseconds); err != nil {
 		return err
 	}
+	if err := reg.Register(edgePercentileInMilliseconds); err != nil {
+		return err
+	}
 	return nil
 }
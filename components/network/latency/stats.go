@@ -0,0 +1,78 @@
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultLatencyStatsWindow is the number of the most recent measurements
+	// kept per region to compute the percentiles.
+	defaultLatencyStatsWindow = 100
+
+	// minLatencyStatsSamples is the minimum number of samples to compute
+	// the percentiles. With fewer samples, the max observed latency is returned.
+	minLatencyStatsSamples = 10
+)
+
+// latencyStats keeps a rolling window of the latency measurements per region.
+type latencyStats struct {
+	mu      sync.Mutex
+	window  int
+	samples map[string][]time.Duration
+}
+
+func newLatencyStats(window int) *latencyStats {
+	if window <= 0 {
+		window = defaultLatencyStatsWindow
+	}
+	return &latencyStats{
+		window:  window,
+		samples: make(map[string][]time.Duration),
+	}
+}
+
+// Add records the latency measurement for the region,
+// evicting the oldest one if the window is full.
+func (s *latencyStats) Add(region string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := append(s.samples[region], d)
+	if len(samples) > s.window {
+		samples = samples[len(samples)-s.window:]
+	}
+	s.samples[region] = samples
+}
+
+// Percentiles returns the p50, p90, and p99 latencies of the region
+// using the nearest-rank method. If the region has fewer than
+// minLatencyStatsSamples samples, the max observed latency is returned for all.
+// Returns zeros if the region has no sample.
+func (s *latencyStats) Percentiles(region string) (p50, p90, p99 time.Duration) {
+	s.mu.Lock()
+	sorted := make([]time.Duration, len(s.samples[region]))
+	copy(sorted, s.samples[region])
+	s.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0, 0, 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	if len(sorted) < minLatencyStatsSamples {
+		maxObserved := sorted[len(sorted)-1]
+		return maxObserved, maxObserved, maxObserved
+	}
+	return percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99)
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,62 @@
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyStatsPercentiles(t *testing.T) {
+	s := newLatencyStats(0)
+
+	// 1ms to 100ms, in the reverse order
+	for i := 100; i >= 1; i-- {
+		s.Add("us-east-1", time.Duration(i)*time.Millisecond)
+	}
+
+	p50, p90, p99 := s.Percentiles("us-east-1")
+	if p50 != 50*time.Millisecond {
+		t.Errorf("expected p50 50ms, got %v", p50)
+	}
+	if p90 != 90*time.Millisecond {
+		t.Errorf("expected p90 90ms, got %v", p90)
+	}
+	if p99 != 99*time.Millisecond {
+		t.Errorf("expected p99 99ms, got %v", p99)
+	}
+}
+
+func TestLatencyStatsFewSamples(t *testing.T) {
+	s := newLatencyStats(0)
+	s.Add("eu-west-1", 30*time.Millisecond)
+	s.Add("eu-west-1", 70*time.Millisecond)
+	s.Add("eu-west-1", 10*time.Millisecond)
+
+	p50, p90, p99 := s.Percentiles("eu-west-1")
+	for _, p := range []time.Duration{p50, p90, p99} {
+		if p != 70*time.Millisecond {
+			t.Errorf("expected the max observed 70ms, got %v", p)
+		}
+	}
+
+	p50, p90, p99 = s.Percentiles("unknown")
+	if p50 != 0 || p90 != 0 || p99 != 0 {
+		t.Errorf("expected zeros for unknown region, got %v %v %v", p50, p90, p99)
+	}
+}
+
+func TestLatencyStatsRollingWindow(t *testing.T) {
+	s := newLatencyStats(10)
+
+	// old samples must be evicted
+	for i := 0; i < 10; i++ {
+		s.Add("ap-northeast-1", time.Second)
+	}
+	for i := 1; i <= 10; i++ {
+		s.Add("ap-northeast-1", time.Duration(i)*time.Millisecond)
+	}
+
+	p50, p90, p99 := s.Percentiles("ap-northeast-1")
+	if p50 != 5*time.Millisecond || p90 != 9*time.Millisecond || p99 != 10*time.Millisecond {
+		t.Errorf("unexpected percentiles %v %v %v", p50, p90, p99)
+	}
+}
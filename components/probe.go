@@ -0,0 +1,69 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines an optional component interface that actively tests the component on demand
+// (e.g., a lightweight query to the underlying data source), beyond the passive polling.
+// The probe should return as soon as the context is done.
+type Prober interface {
+	Probe(ctx context.Context) ProbeResult
+}
+
+// ProbeResult is the result of the component probe.
+// The component only sets the pass/fail, message, and error,
+// and RunProbe fills in the rest.
+type ProbeResult struct {
+	Component string      `json:"component"`
+	Time      metav1.Time `json:"time"`
+
+	Passed  bool            `json:"passed"`
+	Latency metav1.Duration `json:"latency" swaggertype:"string"`
+
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RunProbe runs the probe of the component and measures its latency.
+// Returns errdefs.ErrNotImplemented if the component does not implement Prober.
+// The probe fails with the timeout if it does not return before the context is done.
+func RunProbe(ctx context.Context, comp Component) (ProbeResult, error) {
+	var v any = comp
+	if orig, ok := comp.(interface{ Unwrap() interface{} }); ok {
+		v = orig.Unwrap()
+	}
+	prober, ok := v.(Prober)
+	if !ok {
+		return ProbeResult{}, fmt.Errorf("component %s does not support probe: %w", comp.Name(), errdefs.ErrNotImplemented)
+	}
+
+	start := time.Now().UTC()
+
+	// buffered to not block the probe returning after the timeout
+	resultc := make(chan ProbeResult, 1)
+	go func() {
+		resultc <- prober.Probe(ctx)
+	}()
+
+	var result ProbeResult
+	select {
+	case result = <-resultc:
+	case <-ctx.Done():
+		result = ProbeResult{Error: fmt.Sprintf("probe did not complete: %v", ctx.Err())}
+	}
+
+	result.Component = comp.Name()
+	result.Time = metav1.NewTime(start)
+	result.Latency = metav1.Duration{Duration: time.Since(start)}
+	if result.Error != "" {
+		result.Passed = false
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+)
+
+type fakeProbeComponent struct {
+	fakeComponent
+	probe func(ctx context.Context) ProbeResult
+}
+
+func (c *fakeProbeComponent) Probe(ctx context.Context) ProbeResult {
+	return c.probe(ctx)
+}
+
+type fakeWrappedComponent struct {
+	Component
+}
+
+func (c *fakeWrappedComponent) Unwrap() interface{} { return c.Component }
+
+func TestRunProbe(t *testing.T) {
+	comp := &fakeProbeComponent{
+		fakeComponent: fakeComponent{name: "fake-ok"},
+		probe: func(ctx context.Context) ProbeResult {
+			time.Sleep(10 * time.Millisecond)
+			return ProbeResult{Passed: true, Message: "ok"}
+		},
+	}
+
+	// the server wraps the components to track metrics
+	result, err := RunProbe(context.Background(), &fakeWrappedComponent{Component: comp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || result.Message != "ok" || result.Error != "" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Component != "fake-ok" {
+		t.Errorf("expected component fake-ok, got %q", result.Component)
+	}
+	if result.Latency.Duration < 10*time.Millisecond {
+		t.Errorf("expected latency >= 10ms, got %v", result.Latency.Duration)
+	}
+	if result.Time.IsZero() {
+		t.Error("expected probe time set")
+	}
+}
+
+func TestRunProbeTimeout(t *testing.T) {
+	comp := &fakeProbeComponent{
+		fakeComponent: fakeComponent{name: "fake-hang"},
+		probe: func(ctx context.Context) ProbeResult {
+			// ignores the context, e.g., hanging driver call
+			time.Sleep(time.Second)
+			return ProbeResult{Passed: true}
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := RunProbe(ctx, comp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected probe to time out early, took %v", elapsed)
+	}
+	if result.Passed || result.Error == "" {
+		t.Errorf("expected failed result with error, got %+v", result)
+	}
+}
+
+func TestRunProbeFailed(t *testing.T) {
+	comp := &fakeProbeComponent{
+		fakeComponent: fakeComponent{name: "fake-failed"},
+		probe: func(ctx context.Context) ProbeResult {
+			return ProbeResult{Passed: true, Error: "device not found"}
+		},
+	}
+	result, err := RunProbe(context.Background(), comp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Errorf("expected failed result with the error, got %+v", result)
+	}
+}
+
+func TestRunProbeNotImplemented(t *testing.T) {
+	_, err := RunProbe(context.Background(), &fakeComponent{name: "fake"})
+	if !errors.Is(err, errdefs.ErrNotImplemented) {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+}
@@ -16,10 +16,29 @@ const (
 	DefaultStateRetention = 30 * time.Minute
 )
 
+var defaultGetTimeout = DefaultGetTimeout
+
+// SetDefaultGetTimeout sets the timeout for each get operation
+// of the pollers with no timeout configured.
+// Must be called before the component configs are created.
+func SetDefaultGetTimeout(d time.Duration) {
+	if d > 0 {
+		defaultGetTimeout = d
+	}
+}
+
+// GetDefaultGetTimeout returns the timeout for each get operation
+// of the pollers with no timeout configured.
+func GetDefaultGetTimeout() time.Duration {
+	return defaultGetTimeout
+}
+
 type Config struct {
 	Interval metav1.Duration `json:"interval"`
 
 	// Timeout for each get operation.
+	// The get operation is canceled on timeout (killing any spawned subprocess)
+	// and the poll is recorded as failed, so that the component state is marked stale.
 	GetTimeout metav1.Duration `json:"get_timeout"`
 
 	QueueSize int    `json:"queue_size"`
@@ -29,7 +48,7 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		Interval:   metav1.Duration{Duration: DefaultPollInterval},
-		GetTimeout: metav1.Duration{Duration: DefaultGetTimeout},
+		GetTimeout: metav1.Duration{Duration: defaultGetTimeout},
 		QueueSize:  DefaultQueueSize,
 		State: &State{
 			Retention: metav1.Duration{Duration: DefaultStateRetention},
@@ -42,7 +61,7 @@ func (cfg *Config) SetDefaultsIfNotSet() {
 		cfg.Interval.Duration = DefaultPollInterval
 	}
 	if cfg.GetTimeout.Duration == 0 {
-		cfg.GetTimeout.Duration = DefaultGetTimeout
+		cfg.GetTimeout.Duration = defaultGetTimeout
 	}
 	if cfg.QueueSize == 0 {
 		cfg.QueueSize = DefaultQueueSize
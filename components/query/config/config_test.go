@@ -97,3 +97,32 @@ func TestSetDefaultsIfNotSet(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDefaultGetTimeout(t *testing.T) {
+	defer SetDefaultGetTimeout(DefaultGetTimeout)
+
+	SetDefaultGetTimeout(30 * time.Second)
+	if d := GetDefaultGetTimeout(); d != 30*time.Second {
+		t.Fatalf("expected 30s, got %v", d)
+	}
+
+	// ignores the non-positive timeout
+	SetDefaultGetTimeout(0)
+	if d := GetDefaultGetTimeout(); d != 30*time.Second {
+		t.Fatalf("expected 30s, got %v", d)
+	}
+
+	if d := DefaultConfig().GetTimeout.Duration; d != 30*time.Second {
+		t.Errorf("expected default config timeout 30s, got %v", d)
+	}
+	cfg := Config{}
+	cfg.SetDefaultsIfNotSet()
+	if cfg.GetTimeout.Duration != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %v", cfg.GetTimeout.Duration)
+	}
+	cfg = Config{GetTimeout: metav1.Duration{Duration: time.Minute}}
+	cfg.SetDefaultsIfNotSet()
+	if cfg.GetTimeout.Duration != time.Minute {
+		t.Errorf("expected the configured timeout 1m, got %v", cfg.GetTimeout.Duration)
+	}
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultMaxAgeIntervals is the number of the poll intervals
+// after which the last successful poll result is considered stale.
+const DefaultMaxAgeIntervals = 2
+
+// Freshness describes whether the last successful poll result is up-to-date.
+type Freshness struct {
+	// LastUpdated is the time of the last successful poll.
+	LastUpdated time.Time
+	// Stale is true if the most recent poll failed,
+	// or the last successful poll is older than the max age.
+	Stale bool
+}
+
+// CheckFreshness returns the freshness of the last successful poll result
+// polled at "lastUpdated". The max age is DefaultMaxAgeIntervals times the
+// poll interval, and is not checked if the interval is not set.
+func CheckFreshness(pl Poller, lastUpdated time.Time, now time.Time) Freshness {
+	f := Freshness{LastUpdated: lastUpdated}
+
+	if last, err := pl.Last(); err == nil && last.Error != nil {
+		log.Logger.Warnw("last query failed -- returning cached, possibly stale data", "id", pl.ID(), "error", last.Error)
+		f.Stale = true
+	}
+
+	interval := pl.Config().Interval.Duration
+	elapsed := now.Sub(lastUpdated)
+	if interval > 0 && elapsed > DefaultMaxAgeIntervals*interval {
+		log.Logger.Warnw("last poll is too old", "id", pl.ID(), "elapsed", elapsed, "interval", interval)
+		f.Stale = true
+	}
+
+	return f
+}
+
+// Mark sets the last updated time and the stale flag of the states.
+func (f Freshness) Mark(states []components.State) {
+	for i := range states {
+		states[i].LastUpdated = metav1.NewTime(f.LastUpdated)
+		states[i].Stale = f.Stale
+	}
+}
@@ -0,0 +1,111 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	query_config "github.com/leptonai/gpud/components/query/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckFreshness(t *testing.T) {
+	now := time.Now().UTC()
+	cfg := query_config.Config{Interval: metav1.Duration{Duration: time.Minute}}
+
+	tests := []struct {
+		name      string
+		items     []Item
+		wantStale bool
+	}{
+		{
+			name: "fresh",
+			items: []Item{
+				{Time: metav1.NewTime(now.Add(-time.Minute)), Output: "good"},
+			},
+			wantStale: false,
+		},
+		{
+			name: "most recent poll failed",
+			items: []Item{
+				{Time: metav1.NewTime(now.Add(-time.Minute)), Output: "good"},
+				{Time: metav1.NewTime(now), Error: errors.New("test error")},
+			},
+			wantStale: true,
+		},
+		{
+			name: "recovered from a failed poll",
+			items: []Item{
+				{Time: metav1.NewTime(now.Add(-2 * time.Minute)), Error: errors.New("test error")},
+				{Time: metav1.NewTime(now.Add(-time.Minute)), Output: "good"},
+			},
+			wantStale: false,
+		},
+		{
+			name: "last poll too old",
+			items: []Item{
+				{Time: metav1.NewTime(now.Add(-3 * time.Minute)), Output: "good"},
+			},
+			wantStale: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &poller{cfg: cfg, lastItems: tt.items}
+
+			last, err := pl.LastSuccess()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if last.Output != "good" {
+				t.Fatalf("expected last good output, got %v", last.Output)
+			}
+
+			f := CheckFreshness(pl, last.Time.Time, now)
+			if f.Stale != tt.wantStale {
+				t.Errorf("expected stale %v, got %v", tt.wantStale, f.Stale)
+			}
+			if !f.LastUpdated.Equal(last.Time.Time) {
+				t.Errorf("expected last updated %v, got %v", last.Time.Time, f.LastUpdated)
+			}
+		})
+	}
+}
+
+func TestCheckFreshnessNoInterval(t *testing.T) {
+	now := time.Now().UTC()
+	pl := &poller{lastItems: []Item{{Time: metav1.NewTime(now.Add(-time.Hour))}}}
+
+	if f := CheckFreshness(pl, now.Add(-time.Hour), now); f.Stale {
+		t.Error("expected not stale without the poll interval")
+	}
+}
+
+func TestFreshnessMark(t *testing.T) {
+	lastUpdated := time.Unix(100, 0).UTC()
+	states := []components.State{
+		{Name: "a", Healthy: true, Reason: "ok", ExtraInfo: map[string]string{"k": "v"}},
+		{Name: "b", Healthy: false, Reason: "not ok"},
+	}
+
+	Freshness{LastUpdated: lastUpdated, Stale: true}.Mark(states)
+	for _, s := range states {
+		if !s.Stale {
+			t.Errorf("expected state %q to be stale", s.Name)
+		}
+		if !s.LastUpdated.Time.Equal(lastUpdated) {
+			t.Errorf("expected last updated %v, got %v", lastUpdated, s.LastUpdated)
+		}
+	}
+
+	// the last-good values are retained
+	if states[0].Reason != "ok" || states[0].ExtraInfo["k"] != "v" || !states[0].Healthy {
+		t.Errorf("unexpected state %+v", states[0])
+	}
+	if states[1].Reason != "not ok" || states[1].Healthy {
+		t.Errorf("unexpected state %+v", states[1])
+	}
+}
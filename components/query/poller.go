@@ -3,10 +3,12 @@ package query
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	query_config "github.com/leptonai/gpud/components/query/config"
+	"github.com/leptonai/gpud/errdefs"
 	"github.com/leptonai/gpud/log"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -123,6 +125,9 @@ func pollLoops(ctx context.Context, id string, ch chan<- Item, interval time.Dur
 	// to get output very first time and start wait
 	ticker := time.NewTicker(1)
 	defer ticker.Stop()
+
+	// closed when the get operation that exceeded its timeout returns
+	var running <-chan struct{}
 	for {
 		select {
 		case <-ctx.Done():
@@ -142,17 +147,23 @@ func pollLoops(ctx context.Context, id string, ch chan<- Item, interval time.Dur
 
 		log.Logger.Debugw("polling", "id", id)
 
-		// to prevent component from being blocked by the get operation
-		var cctx context.Context
-		var ccancel context.CancelFunc
-		if getTimeout > 0 {
-			cctx, ccancel = context.WithTimeout(ctx, getTimeout)
+		var output any
+		var err error
+		if running != nil {
+			select {
+			case <-running:
+				running = nil
+			default:
+			}
+		}
+		if running != nil {
+			// the previous get operation ignored its timeout and is still blocked
+			// (e.g., nvidia-smi in the uninterruptible sleep state),
+			// skip this poll rather than piling up the blocked get operations
+			err = fmt.Errorf("previous get operation is still running (%w)", errdefs.ErrTimeout)
 		} else {
-			cctx = ctx
-			ccancel = func() {}
+			output, running, err = getWithTimeout(ctx, getTimeout, getFunc)
 		}
-		output, err := getFunc(cctx)
-		ccancel()
 
 		err = getErrHandler(err)
 
@@ -180,6 +191,69 @@ func pollLoops(ctx context.Context, id string, ch chan<- Item, interval time.Dur
 	}
 }
 
+type getResult struct {
+	output any
+	err    error
+}
+
+// getWithTimeout runs the get operation with the timeout, if set.
+// The get operation is canceled via the context on timeout, and the timeout error
+// is returned even if the get operation does not respect the context cancellation,
+// so that a blocked get operation does not block the poll loop.
+// In such case, the returned channel is closed once the get operation returns.
+func getWithTimeout(ctx context.Context, getTimeout time.Duration, getFunc GetFunc) (any, <-chan struct{}, error) {
+	if getTimeout <= 0 {
+		output, err := getFunc(ctx)
+		return output, nil, err
+	}
+
+	cctx, ccancel := context.WithTimeout(ctx, getTimeout)
+	defer ccancel()
+
+	done := make(chan struct{})
+	resc := make(chan getResult, 1)
+	go func() {
+		defer close(done)
+		output, err := getFunc(cctx)
+		resc <- getResult{output: output, err: err}
+	}()
+
+	select {
+	case res := <-resc:
+		if res.err != nil && ctx.Err() == nil && errors.Is(cctx.Err(), context.DeadlineExceeded) {
+			// the get operation exceeded its timeout, not the poller being stopped
+			res.err = fmt.Errorf("%w (%w)", res.err, errdefs.ErrTimeout)
+		}
+		return res.output, nil, res.err
+
+	case <-cctx.Done():
+		// give the get operation a chance to return with its own error
+		// (e.g., partial output), if it respects the context cancellation
+		grace := getTimeout
+		if grace > maxGetReturnGracePeriod {
+			grace = maxGetReturnGracePeriod
+		}
+		select {
+		case res := <-resc:
+			if res.err != nil && ctx.Err() == nil {
+				res.err = fmt.Errorf("%w (%w)", res.err, errdefs.ErrTimeout)
+			}
+			return res.output, nil, res.err
+		case <-time.After(grace):
+		}
+
+		if ctx.Err() != nil {
+			return nil, done, ctx.Err()
+		}
+		return nil, done, fmt.Errorf("get operation did not return within %v (%w)", getTimeout, errdefs.ErrTimeout)
+	}
+}
+
+// maxGetReturnGracePeriod is the maximum time to wait for the get operation
+// to return after its timeout, before abandoning it.
+// The wait is capped at the timeout itself for the short timeouts.
+const maxGetReturnGracePeriod = 3 * time.Second
+
 func (pl *poller) ID() string {
 	return pl.id
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	query_config "github.com/leptonai/gpud/components/query/config"
+	"github.com/leptonai/gpud/errdefs"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -290,3 +292,107 @@ func TestReadLastErrReturnsErrNoDataForEmptyArray(t *testing.T) {
 		t.Errorf("expected ErrNoData, got %v", err)
 	}
 }
+
+func TestPollLoopsTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := startPoll(ctx, "test", time.Hour, 10*time.Millisecond, func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, func(err error) error {
+		return err
+	})
+
+	select {
+	case item := <-ch:
+		if !errdefs.IsTimeout(item.Error) {
+			t.Errorf("expected timeout error, got %v", item.Error)
+		}
+		if !errors.Is(item.Error, errdefs.ErrTimeout) {
+			t.Errorf("expected wrapped ErrTimeout, got %v", item.Error)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for poll result")
+	}
+}
+
+func TestPollLoopsTimeoutBlockedGet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// a hung component that ignores the cancellation (e.g., a stuck subprocess)
+	canceled := make(chan struct{})
+	unblock := make(chan struct{})
+	var slowCalls atomic.Int32
+	slow := startPoll(ctx, "slow", 20*time.Millisecond, 10*time.Millisecond, func(ctx context.Context) (any, error) {
+		slowCalls.Add(1)
+		<-ctx.Done()
+		select {
+		case <-canceled:
+		default:
+			close(canceled)
+		}
+		<-unblock
+		return "late", nil
+	}, func(err error) error { return err })
+
+	// other component polls independently
+	fast := startPoll(ctx, "fast", 10*time.Millisecond, time.Second, func(ctx context.Context) (any, error) {
+		return "ok", nil
+	}, func(err error) error { return err })
+
+	select {
+	case item := <-slow:
+		if !errors.Is(item.Error, errdefs.ErrTimeout) {
+			t.Errorf("expected timeout error, got %v", item.Error)
+		}
+		if item.Output != nil {
+			t.Errorf("expected no output, got %v", item.Output)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the slow poll result")
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the slow get operation to be canceled")
+	}
+
+	// the blocked get operation is not retried while still running
+	for i := 0; i < 3; i++ {
+		select {
+		case item := <-slow:
+			if !errors.Is(item.Error, errdefs.ErrTimeout) {
+				t.Errorf("expected timeout error, got %v", item.Error)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the slow poll result")
+		}
+	}
+	if n := slowCalls.Load(); n != 1 {
+		t.Errorf("expected 1 get call while blocked, got %d", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case item := <-fast:
+			if item.Error != nil || item.Output != "ok" {
+				t.Errorf("unexpected fast poll result %+v", item)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the fast poll result")
+		}
+	}
+
+	// polls again once the blocked get operation returns
+	close(unblock)
+	deadline := time.After(5 * time.Second)
+	for slowCalls.Load() < 2 {
+		select {
+		case <-slow:
+		case <-deadline:
+			t.Fatal("expected the slow get operation to be polled again")
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package simulate
+
+import (
+	"fmt"
+	"time"
+
+	nvidia_component_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	"github.com/leptonai/gpud/components/common"
+)
+
+type Op struct {
+	quietHours      []common.TimeWindow
+	coalesceWindow  time.Duration
+	xidOverrides    nvidia_component_error_xid.XidOverrides
+	eventTypeHealth common.EventTypeHealthMapping
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) error {
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	for _, w := range op.quietHours {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	cfg := nvidia_component_error_xid.Config{
+		XidIgnoreList:        op.xidOverrides.Ignore,
+		XidForceCriticalList: op.xidOverrides.ForceCritical,
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	for eventType := range op.eventTypeHealth {
+		switch eventType {
+		case common.EventTypeInfo, common.EventTypeWarning, common.EventTypeCritical, common.EventTypeFatal:
+		default:
+			return fmt.Errorf("event type health mapping keys must be one of Info, Warning, Critical, or Fatal, got %q", eventType)
+		}
+	}
+	return nil
+}
+
+// Specifies the quiet hours to suppress the ticket-class alerts.
+func WithQuietHours(windows ...common.TimeWindow) OpOption {
+	return func(op *Op) {
+		op.quietHours = append(op.quietHours, windows...)
+	}
+}
+
+// Specifies the window to coalesce the same Xid on the same device.
+// Defaults to the Xid component default if not set.
+func WithCoalesceWindow(d time.Duration) OpOption {
+	return func(op *Op) {
+		op.coalesceWindow = d
+	}
+}
+
+// Specifies the Xids to ignore or to force as critical,
+// same as the Xid component "xid_ignore_list" and "xid_force_critical_list" config.
+func WithXidOverrides(overrides nvidia_component_error_xid.XidOverrides) OpOption {
+	return func(op *Op) {
+		op.xidOverrides = overrides
+	}
+}
+
+// Specifies whether the Xid events of the type mark the Xid component unhealthy,
+// same as the "event_type_health_mapping" config.
+func WithEventTypeHealthMapping(mapping common.EventTypeHealthMapping) OpOption {
+	return func(op *Op) {
+		op.eventTypeHealth = mapping
+	}
+}
@@ -0,0 +1,155 @@
+// Package simulate runs the log classification and alerting pipeline
+// against a saved log, to show what GPUd would have done.
+package simulate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_component_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	sxid_dmesg "github.com/leptonai/gpud/components/accelerator/nvidia/query/sxid/dmesg"
+	nvidia_query_xid "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/pkg/dmesg"
+)
+
+// Decision is the simulated decision for a classified log line.
+type Decision struct {
+	// LineNumber is the 1-based line number in the log.
+	LineNumber int       `json:"line_number"`
+	Time       time.Time `json:"time"`
+
+	// Kind is the kind of the classified error (e.g., "xid", "sxid").
+	Kind       string `json:"kind"`
+	Code       int    `json:"code"`
+	Name       string `json:"name"`
+	DeviceUUID string `json:"device_uuid,omitempty"`
+
+	EventType        common.EventType         `json:"event_type"`
+	SuggestedActions *common.SuggestedActions `json:"suggested_actions,omitempty"`
+
+	// Set true if the Xid was coalesced into the previously stored event
+	// of the same (xid, device uuid) within the coalesce window,
+	// as the Xid component does. The coalesced Xid is not alerted.
+	Coalesced bool `json:"coalesced"`
+	// Occurrences is the number of the identical Xids coalesced into the event.
+	Occurrences int `json:"occurrences,omitempty"`
+
+	Alert common.AlertDecision `json:"alert"`
+
+	// State is the Xid component health state evaluated after the Xid.
+	// Not set for the SXid errors.
+	State *components.State `json:"state,omitempty"`
+}
+
+// Summary returns the one-line human-readable summary of the decision.
+func (d Decision) Summary() string {
+	action := "none"
+	if d.SuggestedActions != nil && len(d.SuggestedActions.RepairActions) > 0 {
+		action = fmt.Sprintf("%v", d.SuggestedActions.RepairActions)
+	}
+
+	alert := string(d.Alert.Class)
+	switch {
+	case d.Coalesced:
+		alert = fmt.Sprintf("coalesced (%d occurrences)", d.Occurrences)
+	case d.Alert.Suppressed:
+		alert += " (suppressed by quiet hours)"
+	}
+
+	summary := fmt.Sprintf("line %d: %s %d (%s) on %q -> event %s, alert %s, suggested actions %s",
+		d.LineNumber, d.Kind, d.Code, d.Name, d.DeviceUUID, d.EventType, alert, action)
+	if d.State != nil {
+		summary += fmt.Sprintf(", state %s", d.State.Health)
+		if d.State.ReasonCode != "" {
+			summary += fmt.Sprintf(" (%s)", d.State.ReasonCode)
+		}
+	}
+	return summary
+}
+
+// Run classifies each log line (classify -> coalesce -> alert class -> suggested actions)
+// and returns the simulated decisions for the matched lines.
+// The Xid errors go through the same pipeline as the Xid component
+// (see "nvidia_component_error_xid.Replayer"). The SXid errors are not coalesced,
+// same as the SXid component. It never executes any action.
+func Run(r io.Reader, opts ...OpOption) ([]Decision, error) {
+	op := &Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return nil, err
+	}
+
+	xids := nvidia_component_error_xid.NewReplayer(
+		nvidia_component_error_xid.WithCoalesceWindow(op.coalesceWindow),
+		nvidia_component_error_xid.WithXidOverrides(op.xidOverrides),
+		nvidia_component_error_xid.WithEventTypeHealthMapping(op.eventTypeHealth),
+	)
+
+	var decisions []Decision
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		parsed := dmesg.ParseLogLine(scanner.Text())
+
+		var d Decision
+		if event, coalesced, state, ok := xids.Add(parsed); ok {
+			d = fromXid(event, coalesced, state)
+		} else if m := sxid_dmesg.Match(parsed.Content); m != nil {
+			d = fromSXid(m)
+		} else {
+			continue
+		}
+		d.LineNumber = lineNumber
+		d.Time = parsed.Timestamp
+
+		if d.Coalesced {
+			d.Alert = common.AlertDecision{Class: common.AlertClassNone}
+		} else {
+			class := common.GetAlertClass(d.EventType, d.SuggestedActions)
+			d.Alert = common.ApplyQuietHours(class, op.quietHours, d.Time)
+		}
+
+		decisions = append(decisions, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return decisions, nil
+}
+
+func fromXid(event components.Event, coalesced bool, state components.State) Decision {
+	code, _ := strconv.Atoi(event.ExtraInfo[nvidia_component_error_xid.EventKeyErroXidData])
+	return Decision{
+		Kind:             "xid",
+		Code:             code,
+		Name:             nvidia_query_xid.GetDetailOrDefault(code).Name,
+		DeviceUUID:       event.ExtraInfo[nvidia_component_error_xid.EventKeyDeviceUUID],
+		EventType:        event.Type,
+		SuggestedActions: event.SuggestedActions,
+		Coalesced:        coalesced,
+		Occurrences:      event.Occurrences,
+		State:            &state,
+	}
+}
+
+func fromSXid(m *sxid_dmesg.SXidError) Decision {
+	d := Decision{
+		Kind:       "sxid",
+		Code:       m.SXid,
+		DeviceUUID: m.DeviceUUID,
+		EventType:  common.EventTypeUnknown,
+	}
+	if m.Detail != nil {
+		d.Name = m.Detail.Name
+		d.EventType = m.Detail.EventType
+		d.SuggestedActions = m.Detail.SuggestedActionsByGPUd
+	}
+	return d
+}
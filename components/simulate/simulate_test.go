@@ -0,0 +1,147 @@
+package simulate
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	nvidia_component_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestRun(t *testing.T) {
+	f, err := os.Open("testdata/dmesg.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decisions, err := Run(f, WithQuietHours(common.TimeWindow{Start: "22:00", End: "06:00"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type expected struct {
+		lineNumber int
+		kind       string
+		code       int
+		coalesced  bool
+		class      common.AlertClass
+		suppressed bool
+	}
+	exps := []expected{
+		{lineNumber: 2, kind: "xid", code: 13, class: common.AlertClassTicket, suppressed: true},
+		{lineNumber: 3, kind: "xid", code: 13, coalesced: true, class: common.AlertClassNone},
+		{lineNumber: 4, kind: "xid", code: 79, class: common.AlertClassPage},
+		{lineNumber: 5, kind: "sxid", code: 12028, class: common.AlertClassPage},
+		{lineNumber: 6, kind: "xid", code: 13, class: common.AlertClassTicket},
+	}
+	if len(decisions) != len(exps) {
+		t.Fatalf("expected %d decisions, got %d", len(exps), len(decisions))
+	}
+	for i, exp := range exps {
+		d := decisions[i]
+		if d.LineNumber != exp.lineNumber || d.Kind != exp.kind || d.Code != exp.code {
+			t.Errorf("decision %d: expected line %d %s %d, got line %d %s %d", i, exp.lineNumber, exp.kind, exp.code, d.LineNumber, d.Kind, d.Code)
+		}
+		if d.Coalesced != exp.coalesced {
+			t.Errorf("decision %d: expected coalesced %v, got %v", i, exp.coalesced, d.Coalesced)
+		}
+		if (d.State != nil) != (exp.kind == "xid") {
+			t.Errorf("decision %d: expected the state only for xid, got %+v", i, d.State)
+		}
+		if d.Alert.Class != exp.class {
+			t.Errorf("decision %d: expected alert class %q, got %q", i, exp.class, d.Alert.Class)
+		}
+		if d.Alert.Suppressed != exp.suppressed {
+			t.Errorf("decision %d: expected suppressed %v, got %v", i, exp.suppressed, d.Alert.Suppressed)
+		}
+	}
+
+	if !decisions[2].SuggestedActions.RequiresReboot() {
+		t.Errorf("expected xid 79 to suggest reboot")
+	}
+	if decisions[1].Occurrences != 2 {
+		t.Errorf("expected 2 occurrences of the coalesced xid 13, got %d", decisions[1].Occurrences)
+	}
+	if decisions[2].State.Healthy {
+		t.Errorf("expected unhealthy state after xid 79, got %+v", decisions[2].State)
+	}
+	if !strings.Contains(decisions[0].Summary(), "suppressed by quiet hours") {
+		t.Errorf("unexpected summary %q", decisions[0].Summary())
+	}
+}
+
+func TestRunWithOverrides(t *testing.T) {
+	f, err := os.Open("testdata/dmesg.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decisions, err := Run(f, WithXidOverrides(nvidia_component_error_xid.XidOverrides{Ignore: []int{79}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 5 {
+		t.Fatalf("expected 5 decisions, got %d", len(decisions))
+	}
+
+	// ignored xid 79 no longer pages nor marks the xid component unhealthy
+	d := decisions[2]
+	if d.Code != 79 {
+		t.Fatalf("expected xid 79, got %s %d", d.Kind, d.Code)
+	}
+	if d.EventType != common.EventTypeInfo {
+		t.Errorf("expected event type %q for the ignored xid 79, got %q", common.EventTypeInfo, d.EventType)
+	}
+	if d.Alert.Class != common.AlertClassNone {
+		t.Errorf("expected alert class %q for the ignored xid 79, got %q", common.AlertClassNone, d.Alert.Class)
+	}
+	if !d.State.Healthy {
+		t.Errorf("expected healthy state after the ignored xid 79, got %+v", d.State)
+	}
+}
+
+func TestRunWithEventTypeHealthMapping(t *testing.T) {
+	f, err := os.Open("testdata/dmesg.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decisions, err := Run(f, WithEventTypeHealthMapping(common.EventTypeHealthMapping{common.EventTypeFatal: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 5 {
+		t.Fatalf("expected 5 decisions, got %d", len(decisions))
+	}
+
+	// fatal xid 79 still pages, but keeps the xid component healthy
+	d := decisions[2]
+	if d.Alert.Class != common.AlertClassPage {
+		t.Errorf("expected alert class %q for xid 79, got %q", common.AlertClassPage, d.Alert.Class)
+	}
+	if !d.State.Healthy {
+		t.Errorf("expected healthy state after xid 79 mapped to healthy, got %+v", d.State)
+	}
+}
+
+func TestRunInvalidOverrides(t *testing.T) {
+	_, err := Run(strings.NewReader(""), WithXidOverrides(nvidia_component_error_xid.XidOverrides{Ignore: []int{13}, ForceCritical: []int{13}}))
+	if err == nil {
+		t.Fatal("expected error for the xid in both ignore and force critical lists")
+	}
+	_, err = Run(strings.NewReader(""), WithEventTypeHealthMapping(common.EventTypeHealthMapping{"Bogus": true}))
+	if err == nil {
+		t.Fatal("expected error for invalid event type")
+	}
+}
+
+func TestRunInvalidQuietHours(t *testing.T) {
+	_, err := Run(strings.NewReader(""), WithQuietHours(common.TimeWindow{Start: "25:00", End: "06:00"}))
+	if err == nil {
+		t.Fatal("expected error for invalid quiet hours")
+	}
+}
@@ -4,10 +4,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/leptonai/gpud/components/common"
+	events_db "github.com/leptonai/gpud/components/db"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -25,6 +29,10 @@ type Config struct {
 	// Component specific configurations.
 	Components map[string]any `json:"components,omitempty"`
 
+	// Component labels (e.g., "role": "inference") keyed by the component name.
+	// Overwrites the labels returned by the component itself, if any.
+	ComponentLabels map[string]map[string]string `json:"component_labels,omitempty"`
+
 	// State file that persists the latest status.
 	// If empty, the states are not persisted to file.
 	State string `json:"state"`
@@ -36,6 +44,38 @@ type Config struct {
 	// Interval at which to compact the state database.
 	CompactPeriod metav1.Duration `json:"compact_period"`
 
+	// Backend of the component event stores, either "sqlite" (persisted in the state file)
+	// or "memory" (lost on restart, e.g., for ephemeral or CI runs).
+	// Defaults to "sqlite" if empty.
+	EventStore string `json:"event_store,omitempty"`
+
+	// Maximum number of events per component in the "memory" event store,
+	// evicting the oldest events first.
+	// Defaults to 10,000 if zero.
+	EventStoreMaxEvents int `json:"event_store_max_events,omitempty"`
+
+	// Webhook URL to post the newly generated events to as JSON
+	// (e.g., to push the XID errors to an alerting system).
+	// Disables the webhook if empty.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Headers of the webhook requests (e.g., "Authorization").
+	WebhookHeaders map[string]string `json:"webhook_headers,omitempty"`
+
+	// Minimum event type to post to the webhook and the Slack webhook
+	// (e.g., "Fatal" to skip the critical events).
+	// Defaults to "Critical" if empty.
+	WebhookMinEventType common.EventType `json:"webhook_min_event_type,omitempty"`
+
+	// Slack incoming webhook URL to post the newly generated events to
+	// as the formatted Slack messages.
+	// Disables the Slack notifications if empty.
+	SlackWebhookURL string `json:"slack_webhook_url,omitempty"`
+
+	// Period to suppress the repeated identical Slack alerts.
+	// Defaults to 10 minutes if zero.
+	SlackCooldown metav1.Duration `json:"slack_cooldown,omitempty"`
+
 	// Interval at which to refresh selected components.
 	// Disables refresh if not set.
 	RefreshComponentsInterval metav1.Duration `json:"refresh_components_interval"`
@@ -49,6 +89,52 @@ type Config struct {
 	// Overwrites the tool binaries for testing.
 	ToolOverwriteOptions ToolOverwriteOptions `json:"tool_overwrite_options"`
 
+	// Daily time windows during which the non-urgent (ticket-class) alerts
+	// are suppressed (but still recorded), while urgent (page-class) alerts
+	// such as fatal errors or hardware inspection still page.
+	// Applies to the webhook and slack alerts.
+	QuietHours []common.TimeWindow `json:"quiet_hours,omitempty"`
+
+	// Names of the components that must be healthy at startup
+	// before "/readyz" reports ready (e.g., "accelerator-nvidia-info").
+	// If empty, "/readyz" reports ready as soon as the server starts.
+	RequiredHealthyComponents []string `json:"required_healthy_components,omitempty"`
+
+	// Minimum event type at which a component is reported unhealthy
+	// by the "/v1/healthz" health rollup (e.g., "Critical" to escalate critical events).
+	// Unhealthy component states count as "Critical".
+	// Defaults to "Fatal" if empty.
+	HealthRollupThreshold common.EventType `json:"health_rollup_threshold,omitempty"`
+
+	// Overrides whether the events of the type mark the Xid and SXid components
+	// unhealthy (false) or keep them healthy (true) (e.g., {"Critical": true}
+	// to not mark the components unhealthy on the critical errors, while the fatal
+	// errors still do). The event types not in the mapping keep the defaults,
+	// where "Critical" and "Fatal" mark the components unhealthy.
+	EventTypeHealthMapping common.EventTypeHealthMapping `json:"event_type_health_mapping,omitempty"`
+
+	// Set true to only log the repair actions (e.g., reboot requested by the control plane)
+	// without executing them.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Interval between the attempts to (re-)establish the control plane session connections.
+	// Defaults to 1 second if zero.
+	SessionKeepAliveInterval metav1.Duration `json:"session_keep_alive_interval,omitempty"`
+
+	// Maximum duration the session reader waits without receiving any message
+	// before the connection is dropped and re-established.
+	// Defaults to 2 minutes if zero.
+	SessionKeepAliveTimeout metav1.Duration `json:"session_keep_alive_timeout,omitempty"`
+
+	// Capacity of the queue between the session reader and the request handler.
+	// Defaults to 20 if zero.
+	SessionReaderQueueSize int `json:"session_reader_queue_size,omitempty"`
+
+	// Policy applied when the session reader queue is full, either "drop"
+	// (drops the incoming message) or "block" (waits until the queue has room).
+	// Defaults to "drop" if empty.
+	SessionReaderQueuePolicy string `json:"session_reader_queue_policy,omitempty"`
+
 	// Set false to disable auto update
 	EnableAutoUpdate bool `json:"enable_auto_update"`
 
@@ -78,6 +164,10 @@ type ToolOverwriteOptions struct {
 	NvidiaSMIQueryCommand    string `json:"nvidia_smi_query_command"`
 	IbstatCommand            string `json:"ibstat_command"`
 	InfinibandClassDirectory string `json:"infiniband_class_directory"`
+
+	// NvidiaSMIXMLFallback collects the "nvidia-smi -q -x" output
+	// for the basic device info when NVML is unavailable.
+	NvidiaSMIXMLFallback bool `json:"nvidia_smi_xml_fallback,omitempty"`
 }
 
 var ErrInvalidAutoUpdateExitCode = errors.New("auto_update_exit_code is only valid when auto_update is enabled")
@@ -98,12 +188,79 @@ func (config *Config) Validate() error {
 	if config.Web != nil && config.Web.SincePeriod.Duration < 10*time.Minute {
 		return fmt.Errorf("web_metrics_since_period must be at least 10 minutes, got %d", config.Web.SincePeriod.Duration)
 	}
+	for _, w := range config.QuietHours {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid quiet_hours: %w", err)
+		}
+	}
+	switch config.EventStore {
+	case "", events_db.BackendSQLite, events_db.BackendMemory:
+	default:
+		return fmt.Errorf("event_store must be one of sqlite or memory, got %q", config.EventStore)
+	}
+	if config.EventStoreMaxEvents < 0 {
+		return fmt.Errorf("event_store_max_events must be non-negative, got %d", config.EventStoreMaxEvents)
+	}
+	if err := validateWebhookURL("webhook_url", config.WebhookURL); err != nil {
+		return err
+	}
+	if err := validateWebhookURL("slack_webhook_url", config.SlackWebhookURL); err != nil {
+		return err
+	}
+	if config.SlackCooldown.Duration < 0 {
+		return fmt.Errorf("slack_cooldown must be non-negative, got %d", config.SlackCooldown.Duration)
+	}
+	if config.SessionKeepAliveInterval.Duration < 0 {
+		return fmt.Errorf("session_keep_alive_interval must be non-negative, got %d", config.SessionKeepAliveInterval.Duration)
+	}
+	if config.SessionKeepAliveTimeout.Duration < 0 {
+		return fmt.Errorf("session_keep_alive_timeout must be non-negative, got %d", config.SessionKeepAliveTimeout.Duration)
+	}
+	if config.SessionReaderQueueSize < 0 {
+		return fmt.Errorf("session_reader_queue_size must be non-negative, got %d", config.SessionReaderQueueSize)
+	}
+	switch config.SessionReaderQueuePolicy {
+	case "", "drop", "block":
+	default:
+		return fmt.Errorf("session_reader_queue_policy must be one of drop or block, got %q", config.SessionReaderQueuePolicy)
+	}
+	switch config.WebhookMinEventType {
+	case "", common.EventTypeInfo, common.EventTypeWarning, common.EventTypeCritical, common.EventTypeFatal:
+	default:
+		return fmt.Errorf("webhook_min_event_type must be one of Info, Warning, Critical, or Fatal, got %q", config.WebhookMinEventType)
+	}
+	switch config.HealthRollupThreshold {
+	case "", common.EventTypeWarning, common.EventTypeCritical, common.EventTypeFatal:
+	default:
+		return fmt.Errorf("health_rollup_threshold must be one of Warning, Critical, or Fatal, got %q", config.HealthRollupThreshold)
+	}
+	for eventType := range config.EventTypeHealthMapping {
+		switch eventType {
+		case common.EventTypeInfo, common.EventTypeWarning, common.EventTypeCritical, common.EventTypeFatal:
+		default:
+			return fmt.Errorf("event_type_health_mapping keys must be one of Info, Warning, Critical, or Fatal, got %q", eventType)
+		}
+	}
 	if !config.EnableAutoUpdate && config.AutoUpdateExitCode != -1 {
 		return ErrInvalidAutoUpdateExitCode
 	}
 	return nil
 }
 
+func validateWebhookURL(field string, webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", field, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s must be an http or https url, got %q", field, webhookURL)
+	}
+	return nil
+}
+
 func (config *Config) YAML() ([]byte, error) {
 	return yaml.Marshal(config)
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/leptonai/gpud/components/common"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -58,6 +60,143 @@ func TestConfigValidate_AutoUpdateExitCode(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_EventStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventStore string
+		maxEvents  int
+		wantErr    bool
+	}{
+		{name: "Valid: default", eventStore: ""},
+		{name: "Valid: sqlite", eventStore: "sqlite"},
+		{name: "Valid: memory with max events", eventStore: "memory", maxEvents: 100},
+		{name: "Invalid: unknown backend", eventStore: "redis", wantErr: true},
+		{name: "Invalid: negative max events", eventStore: "memory", maxEvents: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				RetentionPeriod:           metav1.Duration{Duration: time.Hour},
+				CompactPeriod:             metav1.Duration{Duration: time.Hour},
+				RefreshComponentsInterval: metav1.Duration{Duration: time.Hour},
+				Address:                   "localhost:8080",
+				EnableAutoUpdate:          true,
+				EventStore:                tt.eventStore,
+				EventStoreMaxEvents:       tt.maxEvents,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_Webhook(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		minEventType  common.EventType
+		slackURL      string
+		slackCooldown time.Duration
+		wantErr       bool
+	}{
+		{name: "Valid: disabled", url: ""},
+		{name: "Valid: https", url: "https://alerts.example.com/hook"},
+		{name: "Valid: min event type", url: "http://localhost:9000", minEventType: common.EventTypeFatal},
+		{name: "Invalid: scheme", url: "ftp://alerts.example.com", wantErr: true},
+		{name: "Invalid: min event type", url: "https://alerts.example.com", minEventType: "Severe", wantErr: true},
+		{name: "Valid: slack", slackURL: "https://hooks.slack.com/services/T/B/X"},
+		{name: "Invalid: slack scheme", slackURL: "hooks.slack.com/services/T/B/X", wantErr: true},
+		{name: "Invalid: negative slack cooldown", slackURL: "https://hooks.slack.com/services/T/B/X", slackCooldown: -time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				RetentionPeriod:           metav1.Duration{Duration: time.Hour},
+				CompactPeriod:             metav1.Duration{Duration: time.Hour},
+				RefreshComponentsInterval: metav1.Duration{Duration: time.Hour},
+				Address:                   "localhost:8080",
+				EnableAutoUpdate:          true,
+				WebhookURL:                tt.url,
+				WebhookMinEventType:       tt.minEventType,
+				SlackWebhookURL:           tt.slackURL,
+				SlackCooldown:             metav1.Duration{Duration: tt.slackCooldown},
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_Session(t *testing.T) {
+	tests := []struct {
+		name              string
+		keepAliveInterval time.Duration
+		keepAliveTimeout  time.Duration
+		queueSize         int
+		queuePolicy       string
+		wantErr           bool
+	}{
+		{name: "Valid: defaults"},
+		{name: "Valid: custom", keepAliveInterval: 5 * time.Second, keepAliveTimeout: time.Minute, queueSize: 100, queuePolicy: "block"},
+		{name: "Invalid: negative keep alive interval", keepAliveInterval: -time.Second, wantErr: true},
+		{name: "Invalid: negative keep alive timeout", keepAliveTimeout: -time.Second, wantErr: true},
+		{name: "Invalid: negative queue size", queueSize: -1, wantErr: true},
+		{name: "Invalid: queue policy", queuePolicy: "evict", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				RetentionPeriod:           metav1.Duration{Duration: time.Hour},
+				CompactPeriod:             metav1.Duration{Duration: time.Hour},
+				RefreshComponentsInterval: metav1.Duration{Duration: time.Hour},
+				Address:                   "localhost:8080",
+				EnableAutoUpdate:          true,
+				SessionKeepAliveInterval:  metav1.Duration{Duration: tt.keepAliveInterval},
+				SessionKeepAliveTimeout:   metav1.Duration{Duration: tt.keepAliveTimeout},
+				SessionReaderQueueSize:    tt.queueSize,
+				SessionReaderQueuePolicy:  tt.queuePolicy,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_EventTypeHealthMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping common.EventTypeHealthMapping
+		wantErr bool
+	}{
+		{name: "Valid: empty"},
+		{name: "Valid: critical healthy", mapping: common.EventTypeHealthMapping{common.EventTypeCritical: true}},
+		{name: "Valid: warning unhealthy", mapping: common.EventTypeHealthMapping{common.EventTypeWarning: false}},
+		{name: "Invalid: unknown event type", mapping: common.EventTypeHealthMapping{"Severe": true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				RetentionPeriod:           metav1.Duration{Duration: time.Hour},
+				CompactPeriod:             metav1.Duration{Duration: time.Hour},
+				RefreshComponentsInterval: metav1.Duration{Duration: time.Hour},
+				Address:                   "localhost:8080",
+				EnableAutoUpdate:          true,
+				EventTypeHealthMapping:    tt.mapping,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadConfigYAML(t *testing.T) {
 	t.Parallel()
 
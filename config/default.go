@@ -18,10 +18,13 @@ import (
 	nvidia_gpm "github.com/leptonai/gpud/components/accelerator/nvidia/gpm"
 	nvidia_gsp_firmware_mode_id "github.com/leptonai/gpud/components/accelerator/nvidia/gsp-firmware-mode/id"
 	nvidia_hw_slowdown_id "github.com/leptonai/gpud/components/accelerator/nvidia/hw-slowdown/id"
+	nvidia_infiniband_link_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/id"
 	nvidia_infiniband_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband/id"
 	nvidia_info "github.com/leptonai/gpud/components/accelerator/nvidia/info"
+	nvidia_inventory_id "github.com/leptonai/gpud/components/accelerator/nvidia/inventory/id"
 	nvidia_memory "github.com/leptonai/gpud/components/accelerator/nvidia/memory"
 	nvidia_nccl_id "github.com/leptonai/gpud/components/accelerator/nvidia/nccl/id"
+	nvidia_numa_id "github.com/leptonai/gpud/components/accelerator/nvidia/numa/id"
 	nvidia_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/nvlink"
 	nvidia_peermem_id "github.com/leptonai/gpud/components/accelerator/nvidia/peermem/id"
 	nvidia_persistence_mode_id "github.com/leptonai/gpud/components/accelerator/nvidia/persistence-mode/id"
@@ -31,12 +34,14 @@ import (
 	nvidia_query_nvml "github.com/leptonai/gpud/components/accelerator/nvidia/query/nvml"
 	nvidia_remapped_rows "github.com/leptonai/gpud/components/accelerator/nvidia/remapped-rows"
 	nvidia_temperature "github.com/leptonai/gpud/components/accelerator/nvidia/temperature"
+	nvidia_thermal_threshold_id "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold/id"
 	nvidia_utilization "github.com/leptonai/gpud/components/accelerator/nvidia/utilization"
 	containerd_pod "github.com/leptonai/gpud/components/containerd/pod"
 	containerd_pod_id "github.com/leptonai/gpud/components/containerd/pod/id"
 	cpu_id "github.com/leptonai/gpud/components/cpu/id"
 	"github.com/leptonai/gpud/components/disk"
 	disk_id "github.com/leptonai/gpud/components/disk/id"
+	disk_smart_id "github.com/leptonai/gpud/components/disk/smart/id"
 	"github.com/leptonai/gpud/components/dmesg"
 	docker_container "github.com/leptonai/gpud/components/docker/container"
 	docker_container_id "github.com/leptonai/gpud/components/docker/container/id"
@@ -160,6 +165,7 @@ func DefaultConfig(ctx context.Context, opts ...OpOption) (*Config, error) {
 			NvidiaSMIQueryCommand:    options.NvidiaSMIQueryCommand,
 			IbstatCommand:            options.IbstatCommand,
 			InfinibandClassDirectory: options.InfinibandClassDirectory,
+			NvidiaSMIXMLFallback:     options.NvidiaSMIXMLFallback,
 		},
 
 		EnableAutoUpdate: true,
@@ -186,6 +192,12 @@ func DefaultConfig(ctx context.Context, opts ...OpOption) (*Config, error) {
 		cfg.Components[power_supply_id.Name] = nil
 	}
 
+	if runtime.GOOS == "linux" {
+		if _, err := pkg_file.LocateExecutable("smartctl"); err == nil {
+			cfg.Components[disk_smart_id.Name] = nil
+		}
+	}
+
 	cc, exists, err := DefaultDmesgComponent(ctx)
 	if err != nil {
 		return nil, err
@@ -296,9 +308,12 @@ func DefaultConfig(ctx context.Context, opts ...OpOption) (*Config, error) {
 		cfg.Components[nvidia_nvlink.Name] = nil
 		cfg.Components[nvidia_power_id.Name] = nil
 		cfg.Components[nvidia_temperature.Name] = nil
+		cfg.Components[nvidia_thermal_threshold_id.Name] = nil
 		cfg.Components[nvidia_utilization.Name] = nil
 		cfg.Components[nvidia_processes.Name] = nil
 		cfg.Components[nvidia_remapped_rows.Name] = nil
+		cfg.Components[nvidia_numa_id.Name] = nil
+		cfg.Components[nvidia_inventory_id.Name] = nil
 		cfg.Components[library_id.Name] = library.Config{
 			Libraries:  DefaultNVIDIALibraries,
 			SearchDirs: DefaultNVIDIALibrariesSearchDirs,
@@ -308,6 +323,7 @@ func DefaultConfig(ctx context.Context, opts ...OpOption) (*Config, error) {
 		cfg.Components[nvidia_fabric_manager.Name] = nil
 
 		cfg.Components[nvidia_infiniband_id.Name] = nil
+		cfg.Components[nvidia_infiniband_link_id.Name] = nil
 
 		cfg.Components[nvidia_nccl_id.Name] = nil
 		cfg.Components[nvidia_peermem_id.Name] = nil
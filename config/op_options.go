@@ -60,6 +60,14 @@ func WithNvidiaSMIQueryCommand(p string) OpOption {
 	}
 }
 
+// WithNvidiaSMIXMLFallback collects the "nvidia-smi -q -x" output
+// for the basic device info when NVML is unavailable.
+func WithNvidiaSMIXMLFallback(b bool) OpOption {
+	return func(op *Op) {
+		op.NvidiaSMIXMLFallback = b
+	}
+}
+
 // Specifies the ibstat binary path to overwrite the default path.
 func WithIbstatCommand(p string) OpOption {
 	return func(op *Op) {
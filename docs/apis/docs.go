@@ -50,6 +50,12 @@ const docTemplate = `{
                         "description": "Component Name, leave empty to query all components",
                         "name": "component",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum event type to return (Info, Warning, Critical, or Fatal), leave empty to return all events",
+                        "name": "minSeverity",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -65,6 +71,30 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/healthz": {
+            "get": {
+                "description": "reports unhealthy (503) if any component reports an event or state at or above the configured threshold (default \"Fatal\")",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query the overall health of all components in gpud",
+                "operationId": "getHealthRollup",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.HealthRollup"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.HealthRollup"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/info": {
             "get": {
                 "description": "get component Events/Metrics/States interface by component name",
@@ -79,6 +109,24 @@ const docTemplate = `{
                         "description": "Component Name, leave empty to query all components",
                         "name": "component",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label selector (e.g., role=inference), leave empty to query all components",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events per component, leave empty to return all events",
+                        "name": "eventLimit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the events page of the component, from the nextEventCursor of the previous response (repeat for multiple components)",
+                        "name": "eventCursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -108,6 +156,12 @@ const docTemplate = `{
                         "description": "Component Name, leave empty to query all components",
                         "name": "component",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Duration (e.g., 10m) or RFC3339 timestamp to query the metrics since, default 30m",
+                        "name": "since",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -123,19 +177,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/states": {
+        "/v1/probe": {
             "get": {
-                "description": "get component States interface by component name",
+                "description": "runs the on-demand self-test of the component, and returns the pass/fail with the latency",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query component States interface in gpud",
-                "operationId": "getStates",
+                "summary": "Probe a component in gpud",
+                "operationId": "getProbe",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Component Name, leave empty to query all components",
+                        "description": "Component Name",
                         "name": "component",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Probe timeout (e.g., 10s), defaults to 30s",
+                        "name": "timeout",
                         "in": "query"
                     }
                 ],
@@ -143,62 +204,105 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v1.LeptonComponentStates"
-                            }
+                            "$ref": "#/definitions/components.ProbeResult"
                         }
                     }
                 }
             }
         },
-        "/v1/update/install": {
-            "post": {
-                "description": "get current update progress",
+        "/v1/states": {
+            "get": {
+                "description": "get component States interface by component name",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get current update progress",
-                "operationId": "updateInstall",
+                "summary": "Query component States interface in gpud",
+                "operationId": "getStates",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "next version to update",
-                        "name": "version",
-                        "in": "query",
-                        "required": true
+                        "description": "Component Name, leave empty to query all components",
+                        "name": "component",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.UpdateStatus"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.LeptonComponentStates"
+                            }
                         }
                     }
                 }
             }
+        }
+    },
+    "definitions": {
+        "common.EventType": {
+            "type": "string",
+            "enum": [
+                "Unknown",
+                "Info",
+                "Warning",
+                "Critical",
+                "Fatal"
+            ],
+            "x-enum-varnames": [
+                "EventTypeUnknown",
+                "EventTypeInfo",
+                "EventTypeWarning",
+                "EventTypeCritical",
+                "EventTypeFatal"
+            ]
         },
-        "/v1/update/progress": {
-            "get": {
-                "description": "get current update progress",
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Get current update progress",
-                "operationId": "updateProgress",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateStatus"
-                        }
+        "common.RepairActionType": {
+            "type": "string",
+            "enum": [
+                "IGNORE_NO_ACTION_REQUIRED",
+                "REBOOT_SYSTEM",
+                "HARDWARE_INSPECTION",
+                "CHECK_USER_APP_AND_GPU",
+                "RESET_GPU",
+                "DRAIN"
+            ],
+            "x-enum-varnames": [
+                "RepairActionTypeIgnoreNoActionRequired",
+                "RepairActionTypeRebootSystem",
+                "RepairActionTypeHardwareInspection",
+                "RepairActionTypeCheckUserAppAndGPU",
+                "RepairActionTypeResetGPU",
+                "RepairActionTypeDrain"
+            ]
+        },
+        "common.SuggestedActions": {
+            "type": "object",
+            "properties": {
+                "descriptions": {
+                    "description": "A list of reasons and descriptions for the suggested actions.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "references": {
+                    "description": "References to the descriptions.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "repair_actions": {
+                    "description": "A list of repair actions to mitigate the issue.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/common.RepairActionType"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "components.Event": {
             "type": "object",
             "properties": {
@@ -209,6 +313,13 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "first_seen": {
+                    "description": "FirstSeen and LastSeen are the times of the first and the last occurrences,\nset only if the identical events are coalesced into the event.",
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
                 "message": {
                     "description": "detailed message of the event",
                     "type": "string"
@@ -216,12 +327,18 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "occurrences": {
+                    "description": "Occurrences is the number of the identical events coalesced into the event.",
+                    "type": "integer"
+                },
+                "suggested_actions": {
+                    "$ref": "#/definitions/common.SuggestedActions"
+                },
                 "time": {
                     "type": "string"
                 },
                 "type": {
-                    "description": "optional: ErrCritical, ErrWarning, Info, Resolution, ...",
-                    "type": "string"
+                    "$ref": "#/definitions/common.EventType"
                 }
             }
         },
@@ -250,13 +367,57 @@ const docTemplate = `{
         },
         "components.Metric": {
             "type": "object",
-            "additionalProperties": true
+            "properties": {
+                "extra_info": {
+                    "description": "any extra information the component may want to expose",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metric_name": {
+                    "type": "string"
+                },
+                "metric_secondary_name": {
+                    "type": "string"
+                },
+                "unix_seconds": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "components.ProbeResult": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "latency": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
         },
         "components.State": {
             "type": "object",
             "properties": {
                 "error": {
-                    "description": "the unprocessed error returned from the component"
+                    "description": "the unprocessed error returned from the component",
+                    "type": "string"
                 },
                 "extra_info": {
                     "description": "any extra information the component may want to expose",
@@ -265,32 +426,50 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "health": {
+                    "description": "Healthy, Degraded, Unhealthy",
+                    "type": "string"
+                },
                 "healthy": {
                     "type": "boolean"
                 },
+                "last_updated": {
+                    "description": "LastUpdated is the time of the last successful poll the state is based on.",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
                 "reason": {
                     "description": "a detailed and processed reason on why the component is not healthy",
                     "type": "string"
+                },
+                "reason_code": {
+                    "description": "ReasonCode is the machine-parseable code of the reason (e.g., \"XID_CRITICAL\"),\nfor the consumers to match on rather than the human-readable reason.\nEmpty if the component does not define a code for the state.\nThe reason code does not imply the state is unhealthy (e.g., \"ECC_DBE\"\nis set on the healthy ECC state, as the driver recovers from the error),\nthus use \"Healthy\" and \"Health\" for the health.",
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "Stale is true if the state is based on the cached data,\nbecause the most recent poll failed or the last successful poll is too old.",
+                    "type": "boolean"
+                },
+                "suggested_actions": {
+                    "$ref": "#/definitions/common.SuggestedActions"
                 }
             }
         },
-        "server.UpdateStatus": {
-            "type": "integer",
-            "enum": [
-                0,
-                1,
-                2,
-                3
-            ],
-            "x-enum-varnames": [
-                "updateStatusInit",
-                "updateStatusInProgress",
-                "updateStatusFailed",
-                "updateStatusFinished"
-            ]
+        "server.HealthRollup": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "type": "boolean"
+                },
+                "unhealthy_components": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
         },
         "v1.LeptonComponentEvents": {
             "type": "object",
@@ -314,6 +493,9 @@ const docTemplate = `{
         },
         "v1.LeptonComponentInfo": {
             "type": "object",
+            "required": [
+                "component"
+            ],
             "properties": {
                 "component": {
                     "type": "string"
@@ -321,9 +503,23 @@ const docTemplate = `{
                 "endTime": {
                     "type": "string"
                 },
+                "error": {
+                    "description": "Set to the error of the component, if any of its events, states,\nor metrics failed to be gathered (e.g., the component panicked).\nThe rest of the info is still returned.",
+                    "type": "string"
+                },
                 "info": {
                     "$ref": "#/definitions/components.Info"
                 },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "nextEventCursor": {
+                    "description": "Set to the cursor of the next page of the events,\nif the event limit is set and more events exist.",
+                    "type": "string"
+                },
                 "startTime": {
                     "type": "string"
                 }
@@ -0,0 +1,171 @@
+package apis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+)
+
+// InfoSchemaPath is the swagger path of the LeptonInfo response.
+const InfoSchemaPath = "/v1/info"
+
+// ValidateInfoAgainstSchema validates the JSON-encoded info against the "/v1/info"
+// response schema in the embedded swagger doc, so that the drift between
+// the LeptonInfo struct and the API doc is caught.
+//
+// The unknown fields and the missing required fields are reported with their paths
+// (e.g., "[0].info.states[0].foo"). A required field is missing if it is absent,
+// null, or an empty string.
+func ValidateInfoAgainstSchema(info v1.LeptonInfo) error {
+	doc, err := loadSwagger()
+	if err != nil {
+		return err
+	}
+	s, err := responseSchema(doc, InfoSchemaPath)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	sv := &schemaValidator{definitions: doc.Definitions}
+	sv.validate("", v, s)
+	if len(sv.errs) == 0 {
+		return nil
+	}
+	sort.Strings(sv.errs)
+	return fmt.Errorf("info does not match the schema: %s", strings.Join(sv.errs, "; "))
+}
+
+func loadSwagger() (*spec.Swagger, error) {
+	doc := new(spec.Swagger)
+	if err := json.Unmarshal([]byte(SwaggerInfo.ReadDoc()), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger doc: %w", err)
+	}
+	return doc, nil
+}
+
+func responseSchema(doc *spec.Swagger, path string) (*spec.Schema, error) {
+	if doc.Paths == nil {
+		return nil, errors.New("swagger doc has no path")
+	}
+	item, ok := doc.Paths.Paths[path]
+	if !ok || item.Get == nil || item.Get.Responses == nil {
+		return nil, fmt.Errorf("swagger doc has no GET %s", path)
+	}
+	resp, ok := item.Get.Responses.StatusCodeResponses[http.StatusOK]
+	if !ok || resp.Schema == nil {
+		return nil, fmt.Errorf("swagger doc has no response schema for GET %s", path)
+	}
+	return resp.Schema, nil
+}
+
+type schemaValidator struct {
+	definitions spec.Definitions
+	errs        []string
+}
+
+func (sv *schemaValidator) errorf(format string, args ...any) {
+	sv.errs = append(sv.errs, fmt.Sprintf(format, args...))
+}
+
+func (sv *schemaValidator) validate(path string, v any, s *spec.Schema) {
+	if ref := s.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		def, ok := sv.definitions[name]
+		if !ok {
+			sv.errorf("%s: unknown schema reference %q", displayPath(path), ref)
+			return
+		}
+		s = &def
+	}
+	if v == nil {
+		return
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		if !sv.expectType(path, s, "object") {
+			return
+		}
+		for _, name := range s.Required {
+			if fv, ok := val[name]; !ok || fv == nil || fv == "" {
+				sv.errorf("%s: missing required field", joinPath(path, name))
+			}
+		}
+		for name, fv := range val {
+			if prop, ok := s.Properties[name]; ok {
+				sv.validate(joinPath(path, name), fv, &prop)
+				continue
+			}
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				sv.validate(joinPath(path, name), fv, s.AdditionalProperties.Schema)
+				continue
+			}
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Allows {
+				continue
+			}
+			sv.errorf("%s: unknown field", joinPath(path, name))
+		}
+
+	case []any:
+		if !sv.expectType(path, s, "array") {
+			return
+		}
+		if s.Items == nil || s.Items.Schema == nil {
+			return
+		}
+		for i, item := range val {
+			sv.validate(fmt.Sprintf("%s[%d]", path, i), item, s.Items.Schema)
+		}
+
+	case string:
+		sv.expectType(path, s, "string")
+	case bool:
+		sv.expectType(path, s, "boolean")
+	case float64:
+		sv.expectType(path, s, "number", "integer")
+	}
+}
+
+// expectType returns true if the schema has no type or one of the expected types.
+func (sv *schemaValidator) expectType(path string, s *spec.Schema, expected ...string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+	for _, typ := range expected {
+		if s.Type.Contains(typ) {
+			return true
+		}
+	}
+	sv.errorf("%s: expected %s, got %s", displayPath(path), strings.Join(s.Type, ","), expected[0])
+	return false
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,102 @@
+package apis
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateInfoAgainstSchema(t *testing.T) {
+	now := time.Now().UTC()
+	info := v1.LeptonInfo{
+		{
+			Component: "accelerator-nvidia-error-xid",
+			Labels:    map[string]string{"role": "inference"},
+			StartTime: now.Add(-time.Minute),
+			EndTime:   now,
+			Info: components.Info{
+				States: []components.State{
+					{
+						Name:      "error_xid",
+						Healthy:   false,
+						Health:    components.StateUnhealthy,
+						Reason:    "xid 79 detected",
+						Error:     "xid 79",
+						ExtraInfo: map[string]string{"xid": "79"},
+						SuggestedActions: &common.SuggestedActions{
+							Descriptions:  []string{"GPU has fallen off the bus"},
+							RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem},
+						},
+					},
+				},
+				Events: []components.Event{
+					{
+						Time:    metav1.NewTime(now),
+						Name:    "error_xid",
+						Type:    common.EventTypeCritical,
+						Message: "xid 79 detected",
+					},
+				},
+				Metrics: []components.Metric{
+					{
+						Metric: components_metrics_state.Metric{
+							UnixSeconds: now.Unix(),
+							MetricName:  "xid_count",
+							Value:       1,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := ValidateInfoAgainstSchema(info); err != nil {
+		t.Fatalf("ValidateInfoAgainstSchema() error = %v", err)
+	}
+
+	info[0].Component = ""
+	err := ValidateInfoAgainstSchema(info)
+	if err == nil {
+		t.Fatal("expected error for missing component name")
+	}
+	if !strings.Contains(err.Error(), "[0].component: missing required field") {
+		t.Errorf("expected the missing field path in error, got %v", err)
+	}
+}
+
+func TestSchemaValidatorUnknownField(t *testing.T) {
+	doc, err := loadSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := responseSchema(doc, InfoSchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sv := &schemaValidator{definitions: doc.Definitions}
+	sv.validate("", []any{
+		map[string]any{
+			"component": "cpu",
+			"info": map[string]any{
+				"states": []any{map[string]any{"name": "cpu", "foo": "bar", "healthy": "yes"}},
+			},
+		},
+	}, s)
+	expected := []string{
+		"[0].info.states[0].foo: unknown field",
+		"[0].info.states[0].healthy: expected boolean, got string",
+	}
+	sort.Strings(sv.errs)
+	if !reflect.DeepEqual(sv.errs, expected) {
+		t.Errorf("expected %v, got %v", expected, sv.errs)
+	}
+}
@@ -15,6 +15,10 @@ var MockInstance = &nvmlmock.Interface{
 		return "535.161.08", nvml.SUCCESS
 	},
 
+	SystemGetNVMLVersionFunc: func() (string, nvml.Return) {
+		return "12.535.161.08", nvml.SUCCESS
+	},
+
 	DeviceGetCountFunc: func() (int, nvml.Return) {
 		return 1, nvml.SUCCESS
 	},
@@ -4,6 +4,7 @@ package errdefs
 import (
 	"context"
 	"errors"
+	"os"
 )
 
 // Definitions of common error types used. All errors returned by
@@ -19,8 +20,10 @@ var (
 	ErrNotFound           = errors.New("not found")
 	ErrAlreadyExists      = errors.New("already exists")
 	ErrFailedPrecondition = errors.New("failed precondition")
-	ErrUnavailable        = errors.New("unavailable")
+	ErrUnavailable        = errors.New("unavailable")     // e.g., hardware or driver not present
 	ErrNotImplemented     = errors.New("not implemented") // represents not supported and unimplemented
+	ErrTimeout            = errors.New("timeout")
+	ErrPermission         = errors.New("permission denied")
 )
 
 // IsInvalidArgument returns true if the error is due to an invalid argument
@@ -55,6 +58,18 @@ func IsNotImplemented(err error) bool {
 	return errors.Is(err, ErrNotImplemented)
 }
 
+// IsTimeout returns true if the error is due to an operation timing out,
+// including `context.DeadlineExceeded`.
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsPermission returns true if the error is due to insufficient permissions,
+// including `os.ErrPermission`.
+func IsPermission(err error) bool {
+	return errors.Is(err, ErrPermission) || errors.Is(err, os.ErrPermission)
+}
+
 // IsCanceled returns true if the error is due to `context.Canceled`.
 func IsCanceled(err error) bool {
 	return errors.Is(err, context.Canceled)
@@ -0,0 +1,43 @@
+package errdefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		check func(error) bool
+	}{
+		{name: "unavailable", err: fmt.Errorf("failed to initialize NVML: %w", ErrUnavailable), check: IsUnavailable},
+		{name: "timeout", err: fmt.Errorf("nvidia-smi took too long: %w", ErrTimeout), check: IsTimeout},
+		{name: "deadline exceeded as timeout", err: fmt.Errorf("query failed: %w", context.DeadlineExceeded), check: IsTimeout},
+		{name: "permission", err: fmt.Errorf("%q is not executable: %w", "/usr/bin/nvidia-smi", ErrPermission), check: IsPermission},
+		{name: "os permission", err: fmt.Errorf("failed to open: %w", os.ErrPermission), check: IsPermission},
+		{name: "not found", err: fmt.Errorf("component not found: %w", ErrNotFound), check: IsNotFound},
+		{name: "multiple wrapped", err: fmt.Errorf("exec failed: %w (%w)", errors.New("exit status 1"), ErrUnavailable), check: IsUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.check(tt.err) {
+				t.Errorf("expected %v to be classified as %s", tt.err, tt.name)
+			}
+		})
+	}
+
+	// not cross-classified
+	if IsTimeout(fmt.Errorf("wrapped: %w", ErrUnavailable)) {
+		t.Error("unavailable error classified as timeout")
+	}
+	if IsUnavailable(fmt.Errorf("wrapped: %w", ErrTimeout)) {
+		t.Error("timeout error classified as unavailable")
+	}
+	if IsPermission(fmt.Errorf("wrapped: %w", ErrNotFound)) {
+		t.Error("not found error classified as permission")
+	}
+}
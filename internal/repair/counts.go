@@ -0,0 +1,216 @@
+package repair
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+const (
+	// TableNameActionCounts is the table that persists the number of times
+	// each repair action was recommended and performed, across the restarts.
+	TableNameActionCounts = "repair_action_counts"
+
+	ColumnAction      = "action"
+	ColumnRecommended = "recommended"
+	ColumnPerformed   = "performed"
+)
+
+// Counts is the number of times a repair action was recommended and performed.
+type Counts struct {
+	Action common.RepairActionType `json:"action"`
+	// Number of the events that suggested the action.
+	Recommended int64 `json:"recommended"`
+	// Number of times the action was executed (not in the dry-run mode).
+	Performed int64 `json:"performed"`
+}
+
+func CreateTableActionCounts(ctx context.Context, dbRW *sql.DB) error {
+	_, err := dbRW.ExecContext(ctx, fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+	%s TEXT PRIMARY KEY,
+	%s INTEGER NOT NULL DEFAULT 0,
+	%s INTEGER NOT NULL DEFAULT 0
+);`, TableNameActionCounts, ColumnAction, ColumnRecommended, ColumnPerformed))
+	return err
+}
+
+// IncRecommended increments the number of times the action was recommended.
+func IncRecommended(ctx context.Context, dbRW *sql.DB, action common.RepairActionType) error {
+	return incCount(ctx, dbRW, action, ColumnRecommended, 1)
+}
+
+// IncPerformed increments the number of times the action was performed.
+func IncPerformed(ctx context.Context, dbRW *sql.DB, action common.RepairActionType) error {
+	return incCount(ctx, dbRW, action, ColumnPerformed, 1)
+}
+
+func incCount(ctx context.Context, dbRW *sql.DB, action common.RepairActionType, column string, delta int64) error {
+	query := fmt.Sprintf(`
+INSERT INTO %s (%s, %s) VALUES (?, ?)
+ON CONFLICT(%s) DO UPDATE SET %s = %s + excluded.%s;
+`, TableNameActionCounts, ColumnAction, column, ColumnAction, column, column, column)
+
+	start := time.Now()
+	_, err := dbRW.ExecContext(ctx, query, string(action), delta)
+	sqlite.RecordInsertUpdate(time.Since(start).Seconds())
+	if err != nil {
+		return err
+	}
+
+	counts, err := ReadCounts(ctx, dbRW, action)
+	if err != nil {
+		return err
+	}
+	setCountsMetrics(counts)
+	return nil
+}
+
+// ReadCounts reads the counts of the action.
+// Returns the zero counts if the action was never recommended nor performed.
+func ReadCounts(ctx context.Context, dbRO *sql.DB, action common.RepairActionType) (Counts, error) {
+	query := fmt.Sprintf(`
+SELECT %s, %s FROM %s WHERE %s = ?;
+`, ColumnRecommended, ColumnPerformed, TableNameActionCounts, ColumnAction)
+
+	counts := Counts{Action: action}
+
+	start := time.Now()
+	err := dbRO.QueryRowContext(ctx, query, string(action)).Scan(&counts.Recommended, &counts.Performed)
+	sqlite.RecordSelect(time.Since(start).Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return counts, nil
+	}
+	return counts, err
+}
+
+// ReadAllCounts reads the counts of all the actions, sorted by the action.
+func ReadAllCounts(ctx context.Context, dbRO *sql.DB) ([]Counts, error) {
+	query := fmt.Sprintf(`
+SELECT %s, %s, %s FROM %s ORDER BY %s ASC;
+`, ColumnAction, ColumnRecommended, ColumnPerformed, TableNameActionCounts, ColumnAction)
+
+	start := time.Now()
+	rows, err := dbRO.QueryContext(ctx, query)
+	sqlite.RecordSelect(time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []Counts
+	for rows.Next() {
+		var counts Counts
+		var action string
+		if err := rows.Scan(&action, &counts.Recommended, &counts.Performed); err != nil {
+			return nil, err
+		}
+		counts.Action = common.RepairActionType(action)
+		all = append(all, counts)
+	}
+	return all, rows.Err()
+}
+
+// LoadMetrics sets the metrics to the persisted counts (e.g., on restart).
+func LoadMetrics(ctx context.Context, dbRO *sql.DB) error {
+	all, err := ReadAllCounts(ctx, dbRO)
+	if err != nil {
+		return err
+	}
+	for _, counts := range all {
+		setCountsMetrics(counts)
+	}
+	return nil
+}
+
+// RecommendationCounter counts the events that suggest the repair actions
+// (e.g., the XID events that require a reboot).
+// It implements the event store sink, and buffers the counts in memory
+// so that the event insert never waits on the database.
+// Call Start to begin persisting the buffered counts.
+type RecommendationCounter struct {
+	dbRW *sql.DB
+
+	mu      sync.Mutex
+	pending map[common.RepairActionType]int64
+
+	notify chan struct{}
+}
+
+func NewRecommendationCounter(dbRW *sql.DB) *RecommendationCounter {
+	return &RecommendationCounter{
+		dbRW:    dbRW,
+		pending: make(map[common.RepairActionType]int64),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Send buffers the recommended counts of the actions suggested by the event.
+// It never blocks on the database.
+func (rc *RecommendationCounter) Send(table string, ev components.Event) {
+	if ev.SuggestedActions == nil || len(ev.SuggestedActions.RepairActions) == 0 {
+		return
+	}
+
+	rc.mu.Lock()
+	seen := make(map[common.RepairActionType]struct{})
+	for _, action := range ev.SuggestedActions.RepairActions {
+		if _, ok := seen[action]; ok {
+			continue
+		}
+		seen[action] = struct{}{}
+		rc.pending[action]++
+	}
+	rc.mu.Unlock()
+
+	select {
+	case rc.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Start persists the buffered counts until the context is canceled.
+func (rc *RecommendationCounter) Start(ctx context.Context) {
+	go rc.run(ctx)
+}
+
+func (rc *RecommendationCounter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-rc.notify:
+		}
+
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		rc.Flush(cctx)
+		cancel()
+	}
+}
+
+// Flush persists the buffered counts and updates the metrics.
+// The counts that failed to persist are kept for the next flush.
+func (rc *RecommendationCounter) Flush(ctx context.Context) {
+	rc.mu.Lock()
+	pending := rc.pending
+	rc.pending = make(map[common.RepairActionType]int64)
+	rc.mu.Unlock()
+
+	for action, n := range pending {
+		if err := incCount(ctx, rc.dbRW, action, ColumnRecommended, n); err != nil {
+			log.Logger.Warnw("failed to count recommended repair action", "action", action, "count", n, "error", err)
+
+			rc.mu.Lock()
+			rc.pending[action] += n
+			rc.mu.Unlock()
+		}
+	}
+}
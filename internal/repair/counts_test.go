@@ -0,0 +1,208 @@
+package repair
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/pkg/sqlite"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCountsPersistAcrossRestart(t *testing.T) {
+	ctx := context.Background()
+	dbFile := filepath.Join(t.TempDir(), "gpud.state")
+
+	open := func() (func(), *RecommendationCounter, *Executor) {
+		dbRW, err := sqlite.Open(dbFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := CreateTableActionCounts(ctx, dbRW); err != nil {
+			t.Fatal(err)
+		}
+		e, err := New(WithDBRW(dbRW), WithNodeName("node-1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.reboot = func(context.Context) error { return nil }
+		e.runCommand = func(context.Context, string) error { return nil }
+		return func() { _ = dbRW.Close() }, NewRecommendationCounter(dbRW), e
+	}
+
+	closeDB, rc, e := open()
+	rebootEvent := components.Event{
+		Name: "error_xid",
+		SuggestedActions: &common.SuggestedActions{
+			RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem, common.RepairActionTypeRebootSystem},
+		},
+	}
+	rc.Send("test", rebootEvent)
+	rc.Send("test", rebootEvent)
+	rc.Send("test", components.Event{Name: "no_action"})
+	rc.Flush(ctx)
+	if _, err := e.Execute(ctx, common.RepairActionTypeRebootSystem, ""); err != nil {
+		t.Fatal(err)
+	}
+	closeDB()
+
+	// simulate the restart
+	dbRO, err := sqlite.Open(dbFile, sqlite.WithReadOnly(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbRO.Close()
+
+	recommendedCount.Reset()
+	performedCount.Reset()
+	if err := LoadMetrics(ctx, dbRO); err != nil {
+		t.Fatal(err)
+	}
+	counts, err := ReadCounts(ctx, dbRO, common.RepairActionTypeRebootSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Counts{Action: common.RepairActionTypeRebootSystem, Recommended: 2, Performed: 1}
+	if counts != expected {
+		t.Errorf("expected %+v, got %+v", expected, counts)
+	}
+	if v := gaugeValue(t, recommendedCount.WithLabelValues(string(common.RepairActionTypeRebootSystem))); v != 2 {
+		t.Errorf("expected recommended metric 2, got %v", v)
+	}
+	if v := gaugeValue(t, performedCount.WithLabelValues(string(common.RepairActionTypeRebootSystem))); v != 1 {
+		t.Errorf("expected performed metric 1, got %v", v)
+	}
+
+	// keeps counting after the restart
+	closeDB, rc, e = open()
+	defer closeDB()
+	rc.Send("test", rebootEvent)
+	rc.Flush(ctx)
+	if _, err := e.Execute(ctx, common.RepairActionTypeRebootSystem, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Execute(ctx, common.RepairActionTypeDrain, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := ReadAllCounts(ctx, dbRO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAll := []Counts{
+		{Action: common.RepairActionTypeDrain, Performed: 1},
+		{Action: common.RepairActionTypeRebootSystem, Recommended: 3, Performed: 2},
+	}
+	if len(all) != len(expectedAll) {
+		t.Fatalf("expected %+v, got %+v", expectedAll, all)
+	}
+	for i := range all {
+		if all[i] != expectedAll[i] {
+			t.Errorf("expected %+v, got %+v", expectedAll[i], all[i])
+		}
+	}
+	if v := gaugeValue(t, performedCount.WithLabelValues(string(common.RepairActionTypeRebootSystem))); v != 2 {
+		t.Errorf("expected performed metric 2, got %v", v)
+	}
+}
+
+func TestRecommendationCounterAsync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableActionCounts(ctx, dbRW); err != nil {
+		t.Fatal(err)
+	}
+	rc := NewRecommendationCounter(dbRW)
+	ev := components.Event{
+		Name: "error_xid",
+		SuggestedActions: &common.SuggestedActions{
+			RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem},
+		},
+	}
+	rc.Send("test", ev)
+	rc.Send("test", ev)
+
+	// not persisted until the buffered counts are applied
+	counts, err := ReadCounts(ctx, dbRO, common.RepairActionTypeRebootSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.Recommended != 0 {
+		t.Fatalf("expected no counts before start, got %+v", counts)
+	}
+
+	rc.Start(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		counts, err = ReadCounts(ctx, dbRO, common.RepairActionTypeRebootSystem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts.Recommended == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 recommended, got %+v", counts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCountsDryRunNotPerformed(t *testing.T) {
+	ctx := context.Background()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableActionCounts(ctx, dbRW); err != nil {
+		t.Fatal(err)
+	}
+	e, err := New(WithDryRun(true), WithDBRW(dbRW), WithNodeName("node-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Execute(ctx, common.RepairActionTypeRebootSystem, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := ReadCounts(ctx, dbRO, common.RepairActionTypeRebootSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.Performed != 0 || counts.Recommended != 0 {
+		t.Errorf("expected no counts in the dry-run mode, got %+v", counts)
+	}
+}
+
+func TestReadCountsNotFound(t *testing.T) {
+	ctx := context.Background()
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableActionCounts(ctx, dbRW); err != nil {
+		t.Fatal(err)
+	}
+	counts, err := ReadCounts(ctx, dbRO, common.RepairActionTypeRebootSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts != (Counts{Action: common.RepairActionTypeRebootSystem}) {
+		t.Errorf("expected zero counts, got %+v", counts)
+	}
+}
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
@@ -0,0 +1,41 @@
+package repair
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	recommendedCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "repair",
+			Subsystem: "action",
+			Name:      "recommended",
+			Help:      "number of times the repair action was recommended, persisted across restarts",
+		},
+		[]string{"action"},
+	)
+	performedCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "repair",
+			Subsystem: "action",
+			Name:      "performed",
+			Help:      "number of times the repair action was performed, persisted across restarts",
+		},
+		[]string{"action"},
+	)
+)
+
+func Register(reg *prometheus.Registry) error {
+	if err := reg.Register(recommendedCount); err != nil {
+		return err
+	}
+	if err := reg.Register(performedCount); err != nil {
+		return err
+	}
+	return nil
+}
+
+func setCountsMetrics(counts Counts) {
+	recommendedCount.WithLabelValues(string(counts.Action)).Set(float64(counts.Recommended))
+	performedCount.WithLabelValues(string(counts.Action)).Set(float64(counts.Performed))
+}
@@ -0,0 +1,191 @@
+// Package repair executes the repair actions (e.g., reboot, GPU reset),
+// or only plans them in the dry-run mode.
+package repair
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/process"
+	"github.com/leptonai/gpud/pkg/reboot"
+)
+
+const DefaultNvidiaSMICommand = "nvidia-smi"
+
+type Op struct {
+	dryRun           bool
+	nvidiaSMICommand string
+	nodeName         string
+	dbRW             *sql.DB
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) error {
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	if op.nvidiaSMICommand == "" {
+		op.nvidiaSMICommand = DefaultNvidiaSMICommand
+	}
+	if op.nodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname: %w", err)
+		}
+		op.nodeName = hostname
+	}
+	return nil
+}
+
+// Set true to only log the repair actions without executing them.
+func WithDryRun(b bool) OpOption {
+	return func(op *Op) {
+		op.dryRun = b
+	}
+}
+
+// Specifies the nvidia-smi command to reset the GPU.
+func WithNvidiaSMICommand(cmd string) OpOption {
+	return func(op *Op) {
+		op.nvidiaSMICommand = cmd
+	}
+}
+
+// Specifies the Kubernetes node name to drain.
+// Defaults to the host name.
+func WithNodeName(name string) OpOption {
+	return func(op *Op) {
+		op.nodeName = name
+	}
+}
+
+// Specifies the database to persist the number of times
+// each repair action was performed (see TableNameActionCounts).
+// The counts are not persisted if not set.
+func WithDBRW(db *sql.DB) OpOption {
+	return func(op *Op) {
+		op.dbRW = db
+	}
+}
+
+var (
+	ErrNotExecutable = errors.New("repair action is not executable")
+	ErrNoGPUUUID     = errors.New("gpu uuid is required to reset the gpu")
+)
+
+// Plan is the repair action to execute.
+type Plan struct {
+	Action common.RepairActionType `json:"action"`
+	// Command to execute the action.
+	Command string `json:"command"`
+	// True if the action is only planned but not executed.
+	DryRun bool `json:"dry_run"`
+}
+
+// Executor executes the repair actions.
+type Executor struct {
+	op Op
+
+	// overwritten for testing
+	reboot     func(ctx context.Context) error
+	runCommand func(ctx context.Context, cmd string) error
+}
+
+func New(opts ...OpOption) (*Executor, error) {
+	op := Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return nil, err
+	}
+	return &Executor{
+		op: op,
+		reboot: func(ctx context.Context) error {
+			return reboot.Reboot(ctx, reboot.WithDelaySeconds(0))
+		},
+		runCommand: runCommand,
+	}, nil
+}
+
+// DryRun returns true if the executor only plans the repair actions.
+func (e *Executor) DryRun() bool {
+	return e.op.dryRun
+}
+
+// Execute executes the repair action, and returns the plan of the action.
+// In the dry-run mode, it only logs the planned action without executing it.
+// The GPU UUID is required to reset the GPU, and ignored otherwise.
+func (e *Executor) Execute(ctx context.Context, action common.RepairActionType, gpuUUID string) (Plan, error) {
+	plan := Plan{Action: action, DryRun: e.op.dryRun}
+	switch action {
+	case common.RepairActionTypeRebootSystem:
+		plan.Command = "reboot"
+	case common.RepairActionTypeResetGPU:
+		if gpuUUID == "" {
+			return plan, ErrNoGPUUUID
+		}
+		plan.Command = fmt.Sprintf("%s --gpu-reset -i %s", e.op.nvidiaSMICommand, gpuUUID)
+	case common.RepairActionTypeDrain:
+		plan.Command = fmt.Sprintf("kubectl drain %s --ignore-daemonsets --delete-emptydir-data", e.op.nodeName)
+	default:
+		return plan, fmt.Errorf("%w: %q", ErrNotExecutable, action)
+	}
+
+	if e.op.dryRun {
+		log.Logger.Infow("dry-run: skipping repair action", "action", action, "command", plan.Command)
+		return plan, nil
+	}
+
+	log.Logger.Infow("executing repair action", "action", action, "command", plan.Command)
+	if e.op.dbRW != nil {
+		// count before the execution, since the reboot may not return
+		if err := IncPerformed(ctx, e.op.dbRW, action); err != nil {
+			log.Logger.Warnw("failed to count performed repair action", "action", action, "error", err)
+		}
+	}
+
+	var err error
+	if action == common.RepairActionTypeRebootSystem {
+		err = e.reboot(ctx)
+	} else {
+		err = e.runCommand(ctx, plan.Command)
+	}
+	if err != nil {
+		return plan, fmt.Errorf("failed to execute repair action %q: %w", action, err)
+	}
+	return plan, nil
+}
+
+func runCommand(ctx context.Context, cmd string) error {
+	proc, err := process.New(
+		process.WithCommand(cmd),
+		process.WithRunAsBashScript(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := proc.Start(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := proc.Close(ctx); err != nil {
+			log.Logger.Warnw("failed to abort command", "err", err)
+		}
+	}()
+
+	return process.Read(
+		ctx,
+		proc,
+		process.WithReadStdout(),
+		process.WithReadStderr(),
+		process.WithProcessLine(func(line string) {
+			log.Logger.Infow("repair command output", "command", cmd, "line", line)
+		}),
+		process.WithWaitForCmd(),
+	)
+}
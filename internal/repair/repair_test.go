@@ -0,0 +1,101 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leptonai/gpud/components/common"
+)
+
+func TestExecuteDryRun(t *testing.T) {
+	e, err := New(WithDryRun(true), WithNodeName("node-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.reboot = func(context.Context) error {
+		t.Fatal("dry-run must not reboot")
+		return nil
+	}
+	e.runCommand = func(_ context.Context, cmd string) error {
+		t.Fatalf("dry-run must not run %q", cmd)
+		return nil
+	}
+
+	tests := []struct {
+		action  common.RepairActionType
+		gpuUUID string
+		command string
+	}{
+		{action: common.RepairActionTypeRebootSystem, command: "reboot"},
+		{action: common.RepairActionTypeResetGPU, gpuUUID: "GPU-a1b2", command: "nvidia-smi --gpu-reset -i GPU-a1b2"},
+		{action: common.RepairActionTypeDrain, command: "kubectl drain node-1 --ignore-daemonsets --delete-emptydir-data"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			plan, err := e.Execute(context.Background(), tt.action, tt.gpuUUID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Action != tt.action {
+				t.Errorf("expected planned action %q, got %q", tt.action, plan.Action)
+			}
+			if plan.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, plan.Command)
+			}
+			if !plan.DryRun {
+				t.Error("expected dry-run plan")
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	e, err := New(WithNvidiaSMICommand("/usr/bin/nvidia-smi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebooted := false
+	e.reboot = func(context.Context) error {
+		rebooted = true
+		return nil
+	}
+	var ran []string
+	e.runCommand = func(_ context.Context, cmd string) error {
+		ran = append(ran, cmd)
+		return nil
+	}
+
+	plan, err := e.Execute(context.Background(), common.RepairActionTypeRebootSystem, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rebooted || plan.DryRun || plan.Action != common.RepairActionTypeRebootSystem {
+		t.Errorf("expected reboot executed, got %+v", plan)
+	}
+
+	if _, err = e.Execute(context.Background(), common.RepairActionTypeResetGPU, "GPU-a1b2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "/usr/bin/nvidia-smi --gpu-reset -i GPU-a1b2" {
+		t.Errorf("unexpected commands %v", ran)
+	}
+
+	e.runCommand = func(context.Context, string) error { return errors.New("exit status 1") }
+	if _, err = e.Execute(context.Background(), common.RepairActionTypeDrain, ""); err == nil {
+		t.Error("expected error for the failed command")
+	}
+}
+
+func TestExecuteInvalid(t *testing.T) {
+	e, err := New(WithDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Execute(context.Background(), common.RepairActionTypeHardwareInspection, ""); !errors.Is(err, ErrNotExecutable) {
+		t.Errorf("expected %v, got %v", ErrNotExecutable, err)
+	}
+	if _, err := e.Execute(context.Background(), common.RepairActionTypeResetGPU, ""); !errors.Is(err, ErrNoGPUUUID) {
+		t.Errorf("expected %v, got %v", ErrNoGPUUUID, err)
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/leptonai/gpud/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressMinSize is the minimum response body size in bytes to compress,
+// below which the compression overhead outweighs the savings.
+const DefaultCompressMinSize = 1024
+
+// compressResponse returns the middleware that compresses the response body
+// with the encoding accepted by the client (gzip preferred over deflate),
+// if the body is at least minSize bytes.
+// The body is buffered in full, so the middleware is not for the streaming responses.
+func compressResponse(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader(RequestHeaderAcceptEncoding))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		orig := c.Writer
+		cw := &compressWriter{ResponseWriter: orig}
+		c.Writer = cw
+		c.Next()
+		c.Writer = orig
+
+		// headers are already sent (e.g., no body allowed for the status)
+		if orig.Written() {
+			_, _ = orig.Write(cw.buf.Bytes())
+			return
+		}
+		orig.Header().Add("Vary", RequestHeaderAcceptEncoding)
+
+		body := cw.buf.Bytes()
+		if len(body) < minSize {
+			if len(body) > 0 {
+				_, _ = orig.Write(body)
+			}
+			return
+		}
+
+		compressed, err := compressBody(encoding, body)
+		if err != nil {
+			log.Logger.Warnw("failed to compress response, sending uncompressed", "encoding", encoding, "error", err)
+			_, _ = orig.Write(body)
+			return
+		}
+		orig.Header().Set(ResponseHeaderContentEncoding, encoding)
+		orig.Header().Set("Content-Length", strconv.Itoa(len(compressed)))
+		_, _ = orig.Write(compressed)
+	}
+}
+
+// compressWriter buffers the response body to decide whether to compress
+// once the handler returns.
+type compressWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
+
+// negotiateEncoding returns the supported encoding from the "Accept-Encoding" header,
+// or an empty string if none is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	deflate := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case RequestHeaderEncodingGzip:
+			return RequestHeaderEncodingGzip
+		case RequestHeaderEncodingDeflate:
+			deflate = true
+		}
+	}
+	if deflate {
+		return RequestHeaderEncodingDeflate
+	}
+	return ""
+}
+
+// compressBody compresses the body with the encoding,
+// where "deflate" is the zlib format as defined in RFC 9110.
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case RequestHeaderEncodingGzip:
+		w = gzip.NewWriter(&buf)
+	default:
+		w = zlib.NewWriter(&buf)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	lep_components "github.com/leptonai/gpud/components"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"br", ""},
+		{"gzip", "gzip"},
+		{"GZIP", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0.0", ""},
+		{"br;q=1.0, gzip;q=0.8, *;q=0.1", "gzip"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.acceptEncoding); got != tt.expected {
+			t.Errorf("negotiateEncoding(%q) = %q, expected %q", tt.acceptEncoding, got, tt.expected)
+		}
+	}
+}
+
+func TestCompressResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat("gpud", DefaultCompressMinSize)
+	router := gin.New()
+	router.Use(compressResponse(DefaultCompressMinSize))
+	router.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, large)
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/no-content", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		path             string
+		acceptEncoding   string
+		expectedCode     int
+		expectedEncoding string
+		expectedBody     string
+	}{
+		{"/large", "", http.StatusOK, "", large},
+		{"/large", "gzip", http.StatusOK, "gzip", large},
+		{"/large", "deflate", http.StatusOK, "deflate", large},
+		{"/small", "gzip", http.StatusOK, "", "ok"},
+		{"/no-content", "gzip", http.StatusNoContent, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+tt.acceptEncoding, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set(RequestHeaderAcceptEncoding, tt.acceptEncoding)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if got := w.Header().Get(ResponseHeaderContentEncoding); got != tt.expectedEncoding {
+				t.Fatalf("expected content encoding %q, got %q", tt.expectedEncoding, got)
+			}
+			if tt.expectedEncoding != "" && w.Body.Len() >= len(tt.expectedBody) {
+				t.Fatalf("expected compressed body smaller than %d bytes, got %d", len(tt.expectedBody), w.Body.Len())
+			}
+			if got := decompress(t, tt.expectedEncoding, w.Body.Bytes()); got != tt.expectedBody {
+				t.Fatalf("expected body of %d bytes, got %d bytes", len(tt.expectedBody), len(got))
+			}
+		})
+	}
+}
+
+func TestCompressResponseInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now().UTC()
+	comp := &mockComponent{name: "test-info-compress"}
+	for i := 0; i < 100; i++ {
+		comp.events = append(comp.events, lep_components.Event{
+			Time:    metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			Name:    fmt.Sprintf("event-%d", i),
+			Message: "the same repetitive event message",
+		})
+	}
+	if err := lep_components.RegisterComponent(comp.name, comp); err != nil {
+		t.Fatal(err)
+	}
+	g := newGlobalHandler(nil, staticComponents(map[string]lep_components.Component{comp.name: comp}))
+	router := gin.New()
+	router.Use(compressResponse(DefaultCompressMinSize))
+	router.GET(URLPathInfo, g.getInfo)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, URLPathInfo+"?components="+comp.name, nil)
+	req.Header.Set(RequestHeaderAcceptEncoding, RequestHeaderEncodingGzip)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get(ResponseHeaderContentEncoding); got != RequestHeaderEncodingGzip {
+		t.Fatalf("expected gzip content encoding, got %q", got)
+	}
+	if got := w.Header().Get("Content-Length"); got != fmt.Sprint(w.Body.Len()) {
+		t.Fatalf("expected content length %d, got %q", w.Body.Len(), got)
+	}
+
+	var info v1.LeptonInfo
+	if err := json.Unmarshal([]byte(decompress(t, RequestHeaderEncodingGzip, w.Body.Bytes())), &info); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(info) != 1 || len(info[0].Info.Events) != 100 {
+		t.Fatalf("expected 1 component with 100 events, got %+v", info)
+	}
+}
+
+func decompress(t *testing.T, encoding string, b []byte) string {
+	var rd io.Reader = bytes.NewReader(b)
+	switch encoding {
+	case RequestHeaderEncodingGzip:
+		gr, err := gzip.NewReader(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd = gr
+	case RequestHeaderEncodingDeflate:
+		zr, err := zlib.NewReader(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd = zr
+	}
+	out, err := io.ReadAll(rd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,37 +12,56 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
+	v1 "github.com/leptonai/gpud/api/v1"
 	lep_components "github.com/leptonai/gpud/components"
+	lep_common "github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/components/query"
 	lep_config "github.com/leptonai/gpud/config"
+	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/manager"
 
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/yaml"
 )
 
 type globalHandler struct {
-	cfg        *lep_config.Config
-	components map[string]lep_components.Component
+	cfg *lep_config.Config
 
-	componentNamesMu sync.RWMutex
-	componentNames   []string
-}
+	// returns the currently registered components,
+	// called on every request so that the components
+	// registered (or deregistered) after startup are reflected
+	componentsFunc func() map[string]lep_components.Component
 
-func newGlobalHandler(cfg *lep_config.Config, components map[string]lep_components.Component) *globalHandler {
-	var componentNames []string
-	for name := range components {
-		componentNames = append(componentNames, name)
-	}
-	sort.Strings(componentNames)
+	// set once all the required components are healthy
+	ready atomic.Bool
+}
 
+func newGlobalHandler(cfg *lep_config.Config, componentsFunc func() map[string]lep_components.Component) *globalHandler {
 	return &globalHandler{
 		cfg:            cfg,
-		components:     components,
-		componentNames: componentNames,
+		componentsFunc: componentsFunc,
+	}
+}
+
+// getComponent returns the currently registered component by its name.
+func (g *globalHandler) getComponent(name string) (lep_components.Component, bool) {
+	comp, ok := g.componentsFunc()[name]
+	return comp, ok
+}
+
+// getComponentNames returns the sorted names of the currently registered components.
+func (g *globalHandler) getComponentNames() []string {
+	comps := g.componentsFunc()
+	names := make([]string, 0, len(comps))
+	for name := range comps {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
 func (g *globalHandler) getReqTime(c *gin.Context) (time.Time, time.Time, error) {
@@ -63,12 +86,149 @@ func (g *globalHandler) getReqTime(c *gin.Context) (time.Time, time.Time, error)
 	return startTime, endTime, nil
 }
 
+// getReqEventPage parses the "eventLimit" and "eventCursor" query parameters.
+// The "eventCursor" may be repeated, one per component, keyed by the component name.
+// Returns zero limit (no pagination) and no cursor if the parameters are not set.
+func (g *globalHandler) getReqEventPage(c *gin.Context) (int, map[string]eventCursor, error) {
+	limit := 0
+	if raw := c.Query("eventLimit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return 0, nil, fmt.Errorf("invalid event limit %q", raw)
+		}
+		limit = v
+	}
+
+	cursors := make(map[string]eventCursor)
+	for _, raw := range c.QueryArray("eventCursor") {
+		if raw == "" {
+			continue
+		}
+		cur, err := decodeEventCursor(raw)
+		if err != nil {
+			return 0, nil, err
+		}
+		cursors[cur.Component] = cur
+	}
+	return limit, cursors, nil
+}
+
+// getReqMinSeverity parses the "minSeverity" query parameter (e.g., "Critical").
+// Returns an empty event type (no filtering) if the parameter is not set.
+func (g *globalHandler) getReqMinSeverity(c *gin.Context) (lep_common.EventType, error) {
+	raw := c.Query("minSeverity")
+	if raw == "" {
+		return "", nil
+	}
+	t := lep_common.EventTypeFromString(raw)
+	if t == lep_common.EventTypeUnknown {
+		return "", fmt.Errorf("invalid min severity %q (must be one of Info, Warning, Critical, or Fatal)", raw)
+	}
+	return t, nil
+}
+
+// filterEventsBySeverity returns the events at or above the minimum severity.
+// Returns the events as is if the minimum severity is empty.
+func filterEventsBySeverity(events []lep_components.Event, minSeverity lep_common.EventType) []lep_components.Event {
+	if minSeverity == "" {
+		return events
+	}
+	ret := make([]lep_components.Event, 0, len(events))
+	for _, ev := range events {
+		if ev.Type.Severity() >= minSeverity.Severity() {
+			ret = append(ret, ev)
+		}
+	}
+	return ret
+}
+
+// eventCursor is the position of the last event of the previous page of the component,
+// identified by the event time and the event ID (see "eventID").
+// Unlike the offset, the position is stable while the new events arrive
+// or the query window slides between the page requests.
+type eventCursor struct {
+	Component string `json:"c"`
+	UnixNano  int64  `json:"t"`
+	ID        string `json:"i"`
+}
+
+// encodeEventCursor encodes the position of the event into an opaque cursor.
+func encodeEventCursor(component string, ev lep_components.Event) string {
+	b, _ := json.Marshal(eventCursor{
+		Component: component,
+		UnixNano:  ev.Time.UnixNano(),
+		ID:        eventID(ev),
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeEventCursor(cursor string) (eventCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return eventCursor{}, fmt.Errorf("invalid event cursor %q", cursor)
+	}
+	var cur eventCursor
+	if err := json.Unmarshal(b, &cur); err != nil || cur.Component == "" || cur.ID == "" {
+		return eventCursor{}, fmt.Errorf("invalid event cursor %q", cursor)
+	}
+	return cur, nil
+}
+
+// eventID returns the ID of the event, derived from its contents,
+// since the events are not stored with the unique IDs.
+func eventID(ev lep_components.Event) string {
+	b, _ := json.Marshal(ev)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// paginateEvents returns the page of the events after the cursor (if any) up to the limit,
+// and the cursor of the next page if more events exist.
+// The events are paged from the newest to the oldest.
+// If the event of the cursor is no longer found (e.g., purged),
+// the page starts from the events older than the cursor.
+// Returns the events as is if the limit is zero and no cursor is given.
+func paginateEvents(component string, events []lep_components.Event, limit int, cursor *eventCursor) ([]lep_components.Event, string) {
+	if limit == 0 && cursor == nil {
+		return events, ""
+	}
+
+	sorted := make([]lep_components.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.After(sorted[j].Time.Time)
+	})
+
+	start := 0
+	if cursor != nil {
+		start = len(sorted)
+		for i, ev := range sorted {
+			ts := ev.Time.UnixNano()
+			if ts == cursor.UnixNano && eventID(ev) == cursor.ID {
+				start = i + 1
+				break
+			}
+			if ts < cursor.UnixNano {
+				start = i
+				break
+			}
+		}
+	}
+
+	page := sorted[start:]
+	if len(page) == 0 {
+		return nil, ""
+	}
+	if limit == 0 || len(page) <= limit {
+		return page, ""
+	}
+	return page[:limit], encodeEventCursor(component, page[limit-1])
+}
+
 func (g *globalHandler) getReqComponents(c *gin.Context) ([]string, error) {
 	components := c.Query("components")
 	if components == "" {
-		g.componentNamesMu.RLock()
-		defer g.componentNamesMu.RUnlock()
-		return g.componentNames, nil
+		return g.getComponentNames(), nil
 	}
 
 	var ret []string
@@ -81,6 +241,58 @@ func (g *globalHandler) getReqComponents(c *gin.Context) ([]string, error) {
 	return ret, nil
 }
 
+// getReqLabelSelector parses the "label" query parameter (e.g., "role=inference").
+// Returns a selector that matches everything if the parameter is not set.
+func (g *globalHandler) getReqLabelSelector(c *gin.Context) (labels.Selector, error) {
+	selector := c.Query("label")
+	if selector == "" {
+		return labels.Everything(), nil
+	}
+	return labels.Parse(selector)
+}
+
+// getComponentLabels returns the labels of the component,
+// where the labels in the config overwrite the ones from the component.
+func (g *globalHandler) getComponentLabels(name string) map[string]string {
+	ret := make(map[string]string)
+
+	comp, ok := g.getComponent(name)
+	if ok {
+		var v any = comp
+		if orig, ok := comp.(interface{ Unwrap() interface{} }); ok {
+			v = orig.Unwrap()
+		}
+		if labeler, ok := v.(lep_components.Labeler); ok {
+			for k, v := range labeler.Labels() {
+				ret[k] = v
+			}
+		}
+	}
+
+	if g.cfg != nil {
+		for k, v := range g.cfg.ComponentLabels[name] {
+			ret[k] = v
+		}
+	}
+
+	return ret
+}
+
+// filterComponentsByLabels returns the components whose labels match the selector.
+func (g *globalHandler) filterComponentsByLabels(components []string, selector labels.Selector) []string {
+	if selector == nil || selector.Empty() {
+		return components
+	}
+
+	ret := make([]string, 0, len(components))
+	for _, name := range components {
+		if selector.Matches(labels.Set(g.getComponentLabels(name))) {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
 const (
 	URLPathSwagger     = "/swagger/*any"
 	URLPathSwaggerDesc = "Swagger endpoint for docs"
@@ -124,6 +336,129 @@ func createHealthzHandler() func(ctx *gin.Context) {
 	}
 }
 
+const (
+	URLPathReadyz     = "/readyz"
+	URLPathReadyzDesc = "Get the readiness of the gpud instance, gated by the required healthy components"
+)
+
+// checkReady returns nil if all the components in "required_healthy_components"
+// are registered and report healthy states.
+// Once passed, the instance stays ready, since the gate only applies at startup.
+func (g *globalHandler) checkReady(ctx context.Context) error {
+	if g.ready.Load() {
+		return nil
+	}
+
+	if g.cfg != nil {
+		for _, name := range g.cfg.RequiredHealthyComponents {
+			comp, ok := g.getComponent(name)
+			if !ok {
+				return fmt.Errorf("required component %q not found", name)
+			}
+			states, err := comp.States(ctx)
+			if err != nil {
+				return fmt.Errorf("required component %q failed to get states: %w", name, err)
+			}
+			if len(states) == 0 {
+				return fmt.Errorf("required component %q has no state", name)
+			}
+			for _, state := range states {
+				if !state.Healthy {
+					return fmt.Errorf("required component %q is not healthy (state %q, reason %q)", name, state.Name, state.Reason)
+				}
+			}
+		}
+	}
+
+	g.ready.Store(true)
+	return nil
+}
+
+func (g *globalHandler) getReadyz(c *gin.Context) {
+	if err := g.checkReady(c); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": http.StatusServiceUnavailable, "message": "not ready: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, DefaultHealthz)
+}
+
+const (
+	URLPathHealthRollup     = "/healthz"
+	URLPathHealthRollupDesc = "Get the overall health of all gpud components"
+)
+
+// HealthRollup summarizes the health of all components into a single status.
+type HealthRollup struct {
+	Healthy             bool     `json:"healthy"`
+	UnhealthyComponents []string `json:"unhealthy_components"`
+}
+
+// getComponentSeverity returns the most severe event type of the component
+// from its events since the given time and its current states.
+// The unhealthy states are treated as critical, and the degraded ones as warning.
+func getComponentSeverity(ctx context.Context, name string, comp lep_components.Component, since time.Time) lep_common.EventType {
+	states, err := comp.States(ctx)
+	if err != nil {
+		log.Logger.Errorw("failed to invoke component states", "operation", "GetHealthRollup", "component", name, "error", err)
+	}
+	var events []lep_components.Event
+	for _, state := range states {
+		switch {
+		case state.Health == lep_components.StateUnhealthy || (state.Health == "" && !state.Healthy):
+			events = append(events, lep_components.Event{Name: state.Name, Type: lep_common.EventTypeCritical})
+		case state.Health == lep_components.StateDegraded:
+			events = append(events, lep_components.Event{Name: state.Name, Type: lep_common.EventTypeWarning})
+		}
+	}
+
+	compEvents, err := comp.Events(ctx, since)
+	if err != nil && !errors.Is(err, query.ErrNoData) {
+		log.Logger.Errorw("failed to invoke component events", "operation", "GetHealthRollup", "component", name, "error", err)
+	}
+	events = append(events, compEvents...)
+
+	return v1.AggregateEventType(events)
+}
+
+// getHealthRollup godoc
+// @Summary Query the overall health of all components in gpud
+// @Description reports unhealthy (503) if any component reports an event or state at or above the configured threshold (default "Fatal")
+// @ID getHealthRollup
+// @Produce  json
+// @Success 200 {object} HealthRollup
+// @Failure 503 {object} HealthRollup
+// @Router /v1/healthz [get]
+func (g *globalHandler) getHealthRollup(c *gin.Context) {
+	threshold := lep_common.EventTypeFatal
+	if g.cfg != nil && g.cfg.HealthRollupThreshold != "" {
+		threshold = g.cfg.HealthRollupThreshold
+	}
+	since := time.Now().UTC().Add(-DefaultQuerySince)
+
+	names := g.getComponentNames()
+
+	rollup := HealthRollup{
+		Healthy:             true,
+		UnhealthyComponents: []string{},
+	}
+	for _, name := range names {
+		comp, ok := g.getComponent(name)
+		if !ok {
+			continue
+		}
+		if getComponentSeverity(c, name, comp, since).Severity() >= threshold.Severity() {
+			rollup.Healthy = false
+			rollup.UnhealthyComponents = append(rollup.UnhealthyComponents, name)
+		}
+	}
+
+	if !rollup.Healthy {
+		c.JSON(http.StatusServiceUnavailable, rollup)
+		return
+	}
+	c.JSON(http.StatusOK, rollup)
+}
+
 const (
 	URLPathConfig     = "/config"
 	URLPathConfigDesc = "Get the configuration of the gpud instance"
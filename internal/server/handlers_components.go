@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
-	"sort"
+	"runtime/debug"
 	"time"
 
 	v1 "github.com/leptonai/gpud/api/v1"
@@ -22,8 +24,11 @@ const (
 	RequestHeaderYAML        = "application/yaml"
 	RequestHeaderJSONIndent  = "json-indent"
 
-	RequestHeaderAcceptEncoding = "Accept-Encoding"
-	RequestHeaderEncodingGzip   = "gzip"
+	RequestHeaderAcceptEncoding  = "Accept-Encoding"
+	RequestHeaderEncodingGzip    = "gzip"
+	RequestHeaderEncodingDeflate = "deflate"
+
+	ResponseHeaderContentEncoding = "Content-Encoding"
 )
 
 type componentHandlerDescription struct {
@@ -64,6 +69,18 @@ func (g *globalHandler) registerComponentRoutes(r gin.IRoutes) []componentHandle
 		Desc: URLPathMetricsDesc,
 	})
 
+	r.GET(URLPathHealthRollup, g.getHealthRollup)
+	paths = append(paths, componentHandlerDescription{
+		Path: URLPathHealthRollup,
+		Desc: URLPathHealthRollupDesc,
+	})
+
+	r.GET(URLPathProbe, g.getProbe)
+	paths = append(paths, componentHandlerDescription{
+		Path: URLPathProbe,
+		Desc: URLPathProbeDesc,
+	})
+
 	return paths
 }
 
@@ -80,11 +97,7 @@ const (
 // @Success 200 {object} []string
 // @Router /v1/components [get]
 func (g *globalHandler) getComponents(c *gin.Context) {
-	components := make([]string, 0, len(g.components))
-	for name := range g.components {
-		components = append(components, name)
-	}
-	sort.Strings(components)
+	components := g.getComponentNames()
 
 	switch c.GetHeader(RequestHeaderContentType) {
 	case RequestHeaderYAML:
@@ -193,6 +206,7 @@ const (
 // @Description get component Events interface by component name
 // @ID getEvents
 // @Param   component     query    string     false        "Component Name, leave empty to query all components"
+// @Param   minSeverity   query    string     false        "Minimum event type to return (Info, Warning, Critical, or Fatal), leave empty to return all events"
 // @Produce  json
 // @Success 200 {object} v1.LeptonEvents
 // @Router /v1/events [get]
@@ -213,6 +227,11 @@ func (g *globalHandler) getEvents(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse time: " + err.Error()})
 		return
 	}
+	minSeverity, err := g.getReqMinSeverity(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse min severity: " + err.Error()})
+		return
+	}
 	for _, componentName := range components {
 		currEvent := v1.LeptonComponentEvents{
 			Component: componentName,
@@ -242,7 +261,7 @@ func (g *globalHandler) getEvents(c *gin.Context) {
 				"error", err,
 			)
 		} else {
-			currEvent.Events = event
+			currEvent.Events = filterEventsBySeverity(event, minSeverity)
 		}
 		events = append(events, currEvent)
 	}
@@ -270,6 +289,33 @@ func (g *globalHandler) getEvents(c *gin.Context) {
 
 const DefaultQuerySince = 30 * time.Minute
 
+// getReqSince parses the "since" query parameter, either as a duration
+// relative to now (e.g., "10m") or as an RFC3339 timestamp.
+// Returns now minus DefaultQuerySince if the parameter is not set.
+func (g *globalHandler) getReqSince(c *gin.Context, now time.Time) (time.Time, error) {
+	sinceRaw := c.Query("since")
+	if sinceRaw == "" {
+		return now.Add(-DefaultQuerySince), nil
+	}
+	if dur, err := time.ParseDuration(sinceRaw); err == nil {
+		return now.Add(-dur), nil
+	}
+	return time.Parse(time.RFC3339Nano, sinceRaw)
+}
+
+// filterMetricsSince drops the metrics older than the since time,
+// in case the component returns the metrics out of the requested range.
+func filterMetricsSince(metrics []lep_components.Metric, since time.Time) []lep_components.Metric {
+	ret := make([]lep_components.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		if m.UnixSeconds < since.Unix() {
+			continue
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
 const (
 	URLPathInfo     = "/info"
 	URLPathInfoDesc = "Get the information of all gpud components"
@@ -280,6 +326,9 @@ const (
 // @Description get component Events/Metrics/States interface by component name
 // @ID getInfo
 // @Param   component     query    string     false        "Component Name, leave empty to query all components"
+// @Param   label         query    string     false        "Label selector (e.g., role=inference), leave empty to query all components"
+// @Param   eventLimit    query    int        false        "Maximum number of events per component, leave empty to return all events"
+// @Param   eventCursor   query    string     false        "Cursor of the events page of the component, from the nextEventCursor of the previous response (repeat for multiple components)"
 // @Produce  json
 // @Success 200 {object} v1.LeptonInfo
 // @Router /v1/info [get]
@@ -295,26 +344,33 @@ func (g *globalHandler) getInfo(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse components: " + err.Error()})
 		return
 	}
+	selector, err := g.getReqLabelSelector(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse label selector: " + err.Error()})
+		return
+	}
+	components = g.filterComponentsByLabels(components, selector)
 	startTime, endTime, err := g.getReqTime(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse time: " + err.Error()})
 		return
 	}
 
-	now := startTime.UTC()
-	metricsSince := now.Add(-DefaultQuerySince)
-	if sinceRaw := c.Query("since"); sinceRaw != "" {
-		dur, err := time.ParseDuration(sinceRaw)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse duration: " + err.Error()})
-			return
-		}
-		metricsSince = now.Add(-dur)
+	metricsSince, err := g.getReqSince(c, startTime.UTC())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse since: " + err.Error()})
+		return
+	}
+	eventLimit, eventCursors, err := g.getReqEventPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse event page: " + err.Error()})
+		return
 	}
 
 	for _, componentName := range components {
 		currInfo := v1.LeptonComponentInfo{
 			Component: componentName,
+			Labels:    g.getComponentLabels(componentName),
 			StartTime: startTime,
 			EndTime:   endTime,
 			Info:      lep_components.Info{},
@@ -329,7 +385,12 @@ func (g *globalHandler) getInfo(c *gin.Context) {
 			infos = append(infos, currInfo)
 			continue
 		}
-		events, err := component.Events(c, startTime)
+		var errs []error
+		var events []lep_components.Event
+		err = callComponent(componentName, "Events", func() (err error) {
+			events, err = component.Events(c, startTime)
+			return err
+		})
 		if err != nil {
 			if errors.Is(err, query.ErrNoData) {
 				log.Logger.Debugw("no event found", "component", componentName)
@@ -341,28 +402,42 @@ func (g *globalHandler) getInfo(c *gin.Context) {
 				"component", componentName,
 				"error", err,
 			)
+			errs = append(errs, fmt.Errorf("failed to get events: %w", err))
 		} else {
-			currInfo.Info.Events = events
+			var cursor *eventCursor
+			if cur, ok := eventCursors[componentName]; ok {
+				cursor = &cur
+			}
+			currInfo.Info.Events, currInfo.NextEventCursor = paginateEvents(componentName, events, eventLimit, cursor)
 		}
-		state, err := component.States(c)
+		err = callComponent(componentName, "States", func() (err error) {
+			currInfo.Info.States, err = component.States(c)
+			return err
+		})
 		if err != nil {
 			log.Logger.Errorw("failed to invoke component states",
 				"operation", "GetInfo",
 				"component", componentName,
 				"error", err,
 			)
-		} else {
-			currInfo.Info.States = state
+			currInfo.Info.States = nil
+			errs = append(errs, fmt.Errorf("failed to get states: %w", err))
 		}
-		metric, err := component.Metrics(c, metricsSince)
+		err = callComponent(componentName, "Metrics", func() (err error) {
+			currInfo.Info.Metrics, err = component.Metrics(c, metricsSince)
+			return err
+		})
 		if err != nil {
 			log.Logger.Errorw("failed to invoke component metrics",
 				"operation", "GetInfo",
 				"component", componentName,
 				"error", err,
 			)
-		} else {
-			currInfo.Info.Metrics = metric
+			currInfo.Info.Metrics = nil
+			errs = append(errs, fmt.Errorf("failed to get metrics: %w", err))
+		}
+		if len(errs) > 0 {
+			currInfo.Error = errors.Join(errs...).Error()
 		}
 		infos = append(infos, currInfo)
 	}
@@ -388,6 +463,25 @@ func (g *globalHandler) getInfo(c *gin.Context) {
 	}
 }
 
+var errComponentPanic = errors.New("component panicked")
+
+// callComponent calls the component interface, recovering from a panic
+// so that one failing component does not break the whole response.
+func callComponent(componentName string, operation string, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Logger.Errorw("recovered from component panic",
+				"operation", operation,
+				"component", componentName,
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			err = fmt.Errorf("%w: %v", errComponentPanic, r)
+		}
+	}()
+	return f()
+}
+
 const (
 	URLPathMetrics     = "/metrics"
 	URLPathMetricsDesc = "Get the metrics of all gpud components"
@@ -398,6 +492,7 @@ const (
 // @Description get component Metrics interface by component name
 // @ID getMetrics
 // @Param   component     query    string     false        "Component Name, leave empty to query all components"
+// @Param   since         query    string     false        "Duration (e.g., 10m) or RFC3339 timestamp to query the metrics since, default 30m"
 // @Produce  json
 // @Success 200 {object} v1.LeptonMetrics
 // @Router /v1/metrics [get]
@@ -413,15 +508,10 @@ func (g *globalHandler) getMetrics(c *gin.Context) {
 		return
 	}
 
-	now := time.Now().UTC()
-	metricsSince := now.Add(-DefaultQuerySince)
-	if sinceRaw := c.Query("since"); sinceRaw != "" {
-		dur, err := time.ParseDuration(sinceRaw)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse duration: " + err.Error()})
-			return
-		}
-		metricsSince = now.Add(-dur)
+	metricsSince, err := g.getReqSince(c, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "failed to parse since: " + err.Error()})
+		return
 	}
 
 	var metrics v1.LeptonMetrics
@@ -447,7 +537,7 @@ func (g *globalHandler) getMetrics(c *gin.Context) {
 				"error", err,
 			)
 		} else {
-			currMetrics.Metrics = currMetric
+			currMetrics.Metrics = filterMetricsSince(currMetric, metricsSince)
 		}
 		metrics = append(metrics, currMetrics)
 	}
@@ -472,3 +562,63 @@ func (g *globalHandler) getMetrics(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "invalid content type"})
 	}
 }
+
+const (
+	URLPathProbe     = "/probe"
+	URLPathProbeDesc = "Actively test a gpud component on demand"
+)
+
+// DefaultProbeTimeout is the default timeout of the component probe.
+const DefaultProbeTimeout = 30 * time.Second
+
+// getProbe godoc
+// @Summary Probe a component in gpud
+// @Description runs the on-demand self-test of the component, and returns the pass/fail with the latency
+// @ID getProbe
+// @Param   component     query    string     true        "Component Name"
+// @Param   timeout     query    string     false        "Probe timeout (e.g., 10s), defaults to 30s"
+// @Produce  json
+// @Success 200 {object} components.ProbeResult
+// @Router /v1/probe [get]
+func (g *globalHandler) getProbe(c *gin.Context) {
+	name := c.Query("component")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "component is required"})
+		return
+	}
+	comp, ok := g.getComponent(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"code": errdefs.ErrNotFound, "message": "component not found: " + name})
+		return
+	}
+
+	timeout := DefaultProbeTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": errdefs.ErrInvalidArgument, "message": "invalid timeout: " + raw})
+			return
+		}
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(c, timeout)
+	defer cancel()
+
+	result, err := lep_components.RunProbe(ctx, comp)
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotImplemented) {
+			c.JSON(http.StatusNotImplemented, gin.H{"code": errdefs.ErrNotImplemented, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusInternalServerError, "message": "failed to probe component " + err.Error()})
+		return
+	}
+	log.Logger.Debugw("probed component", "component", name, "passed", result.Passed, "latency", result.Latency.Duration)
+
+	if c.GetHeader(RequestHeaderJSONIndent) == "true" {
+		c.IndentedJSON(http.StatusOK, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,648 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	v1 "github.com/leptonai/gpud/api/v1"
+	lep_components "github.com/leptonai/gpud/components"
+	lep_common "github.com/leptonai/gpud/components/common"
+	components_metrics_state "github.com/leptonai/gpud/components/metrics/state"
+	lep_config "github.com/leptonai/gpud/config"
+)
+
+type mockComponent struct {
+	name    string
+	labels  map[string]string
+	states  []lep_components.State
+	events  []lep_components.Event
+	metrics []lep_components.Metric
+}
+
+func (m *mockComponent) Name() string { return m.name }
+func (m *mockComponent) Start() error { return nil }
+func (m *mockComponent) States(ctx context.Context) ([]lep_components.State, error) {
+	return m.states, nil
+}
+func (m *mockComponent) Events(ctx context.Context, since time.Time) ([]lep_components.Event, error) {
+	return m.events, nil
+}
+func (m *mockComponent) Metrics(ctx context.Context, since time.Time) ([]lep_components.Metric, error) {
+	return m.metrics, nil
+}
+func (m *mockComponent) Close() error { return nil }
+func (m *mockComponent) Labels() map[string]string {
+	return m.labels
+}
+
+func staticComponents(comps map[string]lep_components.Component) func() map[string]lep_components.Component {
+	return func() map[string]lep_components.Component {
+		return comps
+	}
+}
+
+func TestGlobalHandlerComponentsRegisteredLater(t *testing.T) {
+	comps := map[string]lep_components.Component{
+		"cpu": &mockComponent{name: "cpu"},
+	}
+	g := newGlobalHandler(nil, staticComponents(comps))
+
+	comps["pod"] = &mockComponent{name: "pod"}
+	if got := g.getComponentNames(); !reflect.DeepEqual(got, []string{"cpu", "pod"}) {
+		t.Errorf("expected [cpu pod], got %v", got)
+	}
+	if _, ok := g.getComponent("pod"); !ok {
+		t.Error("expected component registered later to be found")
+	}
+
+	delete(comps, "cpu")
+	if got := g.getComponentNames(); !reflect.DeepEqual(got, []string{"pod"}) {
+		t.Errorf("expected [pod], got %v", got)
+	}
+}
+
+func TestFilterComponentsByLabels(t *testing.T) {
+	cfg := &lep_config.Config{
+		ComponentLabels: map[string]map[string]string{
+			"disk":   {"role": "inference"},
+			"memory": {"role": "training"},
+		},
+	}
+	comps := map[string]lep_components.Component{
+		"cpu":    &mockComponent{name: "cpu", labels: map[string]string{"role": "inference"}},
+		"disk":   &mockComponent{name: "disk"},
+		"memory": &mockComponent{name: "memory", labels: map[string]string{"role": "inference"}},
+		"os":     &mockComponent{name: "os"},
+	}
+	g := newGlobalHandler(cfg, staticComponents(comps))
+
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "", expected: []string{"cpu", "disk", "memory", "os"}},
+		{selector: "role=inference", expected: []string{"cpu", "disk"}},
+		{selector: "role=training", expected: []string{"memory"}},
+		{selector: "role!=inference", expected: []string{"memory", "os"}},
+		{selector: "role=unknown", expected: []string{}},
+		{selector: "team", expected: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := labels.Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("failed to parse selector %q: %v", tt.selector, err)
+			}
+			got := g.filterComponentsByLabels(g.getComponentNames(), selector)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetComponentLabelsConfigOverwrite(t *testing.T) {
+	cfg := &lep_config.Config{
+		ComponentLabels: map[string]map[string]string{
+			"cpu": {"role": "training"},
+		},
+	}
+	comps := map[string]lep_components.Component{
+		"cpu": &mockComponent{name: "cpu", labels: map[string]string{"role": "inference", "zone": "a"}},
+	}
+	g := newGlobalHandler(cfg, staticComponents(comps))
+
+	expected := map[string]string{"role": "training", "zone": "a"}
+	if got := g.getComponentLabels("cpu"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthy := []lep_components.State{{Name: "test", Healthy: true}}
+	tests := []struct {
+		name     string
+		required []string
+		comps    map[string]lep_components.Component
+		expected int
+	}{
+		{
+			name:     "no required components",
+			comps:    map[string]lep_components.Component{},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "all required healthy",
+			required: []string{"nvidia-info", "nvidia-modules"},
+			comps: map[string]lep_components.Component{
+				"nvidia-info":    &mockComponent{name: "nvidia-info", states: healthy},
+				"nvidia-modules": &mockComponent{name: "nvidia-modules", states: healthy},
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "one required missing",
+			required: []string{"nvidia-info", "nvidia-modules"},
+			comps: map[string]lep_components.Component{
+				"nvidia-info": &mockComponent{name: "nvidia-info", states: healthy},
+			},
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "one required unhealthy",
+			required: []string{"nvidia-info"},
+			comps: map[string]lep_components.Component{
+				"nvidia-info": &mockComponent{name: "nvidia-info", states: []lep_components.State{{Name: "gpu_count", Healthy: false, Reason: "expected 8, got 7"}}},
+			},
+			expected: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGlobalHandler(&lep_config.Config{RequiredHealthyComponents: tt.required}, staticComponents(tt.comps))
+			router := gin.New()
+			router.GET(URLPathReadyz, g.getReadyz)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, URLPathReadyz, nil)
+			router.ServeHTTP(w, req)
+			if w.Code != tt.expected {
+				t.Errorf("expected status %d, got %d (%s)", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetHealthRollup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthy := []lep_components.State{{Name: "test", Healthy: true}}
+	newComps := func(events []lep_components.Event) map[string]lep_components.Component {
+		return map[string]lep_components.Component{
+			"cpu":              &mockComponent{name: "cpu", states: healthy},
+			"memory":           &mockComponent{name: "memory", states: healthy, events: []lep_components.Event{{Name: "oom", Type: lep_common.EventTypeWarning}}},
+			"accelerator-xid":  &mockComponent{name: "accelerator-xid", states: healthy, events: events},
+			"accelerator-info": &mockComponent{name: "accelerator-info", states: []lep_components.State{{Name: "gpu_count", Healthy: false}}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		threshold    lep_common.EventType
+		comps        map[string]lep_components.Component
+		expectedCode int
+		expected     HealthRollup
+	}{
+		{
+			name:         "all healthy",
+			comps:        newComps([]lep_components.Event{{Name: "xid", Type: lep_common.EventTypeCritical}}),
+			expectedCode: http.StatusOK,
+			expected:     HealthRollup{Healthy: true, UnhealthyComponents: []string{}},
+		},
+		{
+			name:         "one fatal",
+			comps:        newComps([]lep_components.Event{{Name: "xid", Type: lep_common.EventTypeFatal}}),
+			expectedCode: http.StatusServiceUnavailable,
+			expected:     HealthRollup{Healthy: false, UnhealthyComponents: []string{"accelerator-xid"}},
+		},
+		{
+			name:         "critical escalated",
+			threshold:    lep_common.EventTypeCritical,
+			comps:        newComps([]lep_components.Event{{Name: "xid", Type: lep_common.EventTypeCritical}}),
+			expectedCode: http.StatusServiceUnavailable,
+			expected:     HealthRollup{Healthy: false, UnhealthyComponents: []string{"accelerator-info", "accelerator-xid"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGlobalHandler(&lep_config.Config{HealthRollupThreshold: tt.threshold}, staticComponents(tt.comps))
+			router := gin.New()
+			router.GET(URLPathHealthRollup, g.getHealthRollup)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, URLPathHealthRollup, nil)
+			router.ServeHTTP(w, req)
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d (%s)", tt.expectedCode, w.Code, w.Body.String())
+			}
+
+			var got HealthRollup
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetMetricsSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now().UTC()
+	newMetric := func(ts time.Time, v float64) lep_components.Metric {
+		return lep_components.Metric{
+			Metric: components_metrics_state.Metric{UnixSeconds: ts.Unix(), MetricName: "test", Value: v},
+		}
+	}
+
+	// the mock returns all the metrics regardless of the since time,
+	// so the handler must filter them out
+	comp := &mockComponent{
+		name: "test-metrics-since",
+		metrics: []lep_components.Metric{
+			newMetric(now.Add(-2*time.Hour), 1),
+			newMetric(now.Add(-40*time.Minute), 2),
+			newMetric(now.Add(-5*time.Minute), 3),
+		},
+	}
+	if err := lep_components.RegisterComponent(comp.name, comp); err != nil {
+		t.Fatal(err)
+	}
+	g := newGlobalHandler(nil, staticComponents(map[string]lep_components.Component{comp.name: comp}))
+
+	tests := []struct {
+		name     string
+		since    string
+		expected []float64
+	}{
+		{name: "default", since: "", expected: []float64{3}},
+		{name: "duration", since: "1h", expected: []float64{2, 3}},
+		{name: "timestamp", since: now.Add(-3 * time.Hour).Format(time.RFC3339), expected: []float64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET(URLPathMetrics, g.getMetrics)
+
+			q := url.Values{}
+			if tt.since != "" {
+				q.Set("since", tt.since)
+			}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, URLPathMetrics+"?"+q.Encode(), nil)
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d (%s)", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var got v1.LeptonMetrics
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("expected 1 component, got %+v", got)
+			}
+			values := make([]float64, 0, len(got[0].Metrics))
+			for _, m := range got[0].Metrics {
+				values = append(values, m.Value)
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		router := gin.New()
+		router.GET(URLPathMetrics, g.getMetrics)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, URLPathMetrics+"?since=yesterday", nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d (%s)", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	})
+}
+
+type mockProbeComponent struct {
+	mockComponent
+	probe func(ctx context.Context) lep_components.ProbeResult
+}
+
+func (m *mockProbeComponent) Probe(ctx context.Context) lep_components.ProbeResult {
+	return m.probe(ctx)
+}
+
+func TestGetProbe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	comps := map[string]lep_components.Component{
+		"ok": &mockProbeComponent{
+			mockComponent: mockComponent{name: "ok"},
+			probe: func(ctx context.Context) lep_components.ProbeResult {
+				return lep_components.ProbeResult{Passed: true, Message: "ok"}
+			},
+		},
+		"hang": &mockProbeComponent{
+			mockComponent: mockComponent{name: "hang"},
+			probe: func(ctx context.Context) lep_components.ProbeResult {
+				<-ctx.Done()
+				return lep_components.ProbeResult{Error: ctx.Err().Error()}
+			},
+		},
+		"no-probe": &mockComponent{name: "no-probe"},
+	}
+	g := newGlobalHandler(nil, staticComponents(comps))
+	router := gin.New()
+	router.GET(URLPathProbe, g.getProbe)
+
+	tests := []struct {
+		query        string
+		expectedCode int
+		passed       bool
+	}{
+		{query: "component=ok", expectedCode: http.StatusOK, passed: true},
+		{query: "component=hang&timeout=50ms", expectedCode: http.StatusOK, passed: false},
+		{query: "component=no-probe", expectedCode: http.StatusNotImplemented},
+		{query: "component=unknown", expectedCode: http.StatusNotFound},
+		{query: "", expectedCode: http.StatusBadRequest},
+		{query: "component=ok&timeout=invalid", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, URLPathProbe+"?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d (%s)", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result lep_components.ProbeResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if result.Passed != tt.passed {
+				t.Errorf("expected passed %v, got %+v", tt.passed, result)
+			}
+		})
+	}
+}
+
+func TestGetInfoEventPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now().UTC()
+	comp := &mockComponent{name: "test-info-event-page"}
+	for i := 0; i < 5; i++ {
+		comp.events = append(comp.events, lep_components.Event{
+			Time: metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			Name: fmt.Sprintf("event-%d", i),
+		})
+	}
+	if err := lep_components.RegisterComponent(comp.name, comp); err != nil {
+		t.Fatal(err)
+	}
+	g := newGlobalHandler(nil, staticComponents(map[string]lep_components.Component{comp.name: comp}))
+	router := gin.New()
+	router.GET(URLPathInfo, g.getInfo)
+
+	get := func(t *testing.T, q url.Values) (int, v1.LeptonComponentInfo) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, URLPathInfo+"?"+q.Encode(), nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, v1.LeptonComponentInfo{}
+		}
+		var got v1.LeptonInfo
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("expected 1 component, got %+v", got)
+		}
+		return w.Code, got[0]
+	}
+	names := func(info v1.LeptonComponentInfo) []string {
+		ret := []string{}
+		for _, ev := range info.Info.Events {
+			ret = append(ret, ev.Name)
+		}
+		return ret
+	}
+
+	// no limit, all events
+	_, info := get(t, url.Values{"components": {comp.name}})
+	if len(info.Info.Events) != 5 || info.NextEventCursor != "" {
+		t.Fatalf("expected all 5 events with no cursor, got %v (cursor %q)", names(info), info.NextEventCursor)
+	}
+
+	// walk the pages
+	var pages [][]string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		q := url.Values{"components": {comp.name}, "eventLimit": {"2"}}
+		if cursor != "" {
+			q.Set("eventCursor", cursor)
+		}
+		_, info := get(t, q)
+		pages = append(pages, names(info))
+		cursor = info.NextEventCursor
+		if cursor == "" {
+			break
+		}
+	}
+	// paged from the newest to the oldest
+	expected := [][]string{{"event-4", "event-3"}, {"event-2", "event-1"}, {"event-0"}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("expected pages %v, got %v", expected, pages)
+	}
+
+	// new events arriving between the page requests do not shift the pages
+	_, info = get(t, url.Values{"components": {comp.name}, "eventLimit": {"2"}})
+	cursor = info.NextEventCursor
+	comp.events = append(comp.events, lep_components.Event{
+		Time: metav1.NewTime(now.Add(10 * time.Second)),
+		Name: "event-new",
+	})
+	_, info = get(t, url.Values{"components": {comp.name}, "eventLimit": {"2"}, "eventCursor": {cursor}})
+	if got := names(info); !reflect.DeepEqual(got, []string{"event-2", "event-1"}) {
+		t.Errorf("expected [event-2 event-1] after the new event, got %v", got)
+	}
+	comp.events = comp.events[:5]
+
+	// exact boundary, no next page
+	_, info = get(t, url.Values{"components": {comp.name}, "eventLimit": {"5"}})
+	if len(info.Info.Events) != 5 || info.NextEventCursor != "" {
+		t.Errorf("expected all 5 events with no cursor, got %v (cursor %q)", names(info), info.NextEventCursor)
+	}
+
+	// cursor past the end
+	oldest := lep_components.Event{Time: metav1.NewTime(now.Add(-time.Hour)), Name: "purged"}
+	_, info = get(t, url.Values{"components": {comp.name}, "eventLimit": {"2"}, "eventCursor": {encodeEventCursor(comp.name, oldest)}})
+	if len(info.Info.Events) != 0 || info.NextEventCursor != "" {
+		t.Errorf("expected no events with no cursor, got %v (cursor %q)", names(info), info.NextEventCursor)
+	}
+
+	// cursor of the other component does not apply
+	_, info = get(t, url.Values{"components": {comp.name}, "eventLimit": {"2"}, "eventCursor": {encodeEventCursor("other", comp.events[4])}})
+	if got := names(info); !reflect.DeepEqual(got, []string{"event-4", "event-3"}) {
+		t.Errorf("expected the first page for the other component's cursor, got %v", got)
+	}
+
+	for _, q := range []url.Values{
+		{"components": {comp.name}, "eventLimit": {"-1"}},
+		{"components": {comp.name}, "eventLimit": {"abc"}},
+		{"components": {comp.name}, "eventCursor": {"!!!"}},
+		{"components": {comp.name}, "eventCursor": {base64.RawURLEncoding.EncodeToString([]byte("10"))}},
+	} {
+		if code, _ := get(t, q); code != http.StatusBadRequest {
+			t.Errorf("expected status %d for %v, got %d", http.StatusBadRequest, q, code)
+		}
+	}
+}
+
+func TestGetEventsMinSeverity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now().UTC()
+	comp := &mockComponent{
+		name: "test-events-min-severity",
+		events: []lep_components.Event{
+			{Time: metav1.NewTime(now), Name: "info", Type: lep_common.EventTypeInfo},
+			{Time: metav1.NewTime(now), Name: "warning", Type: lep_common.EventTypeWarning},
+			{Time: metav1.NewTime(now), Name: "critical", Type: lep_common.EventTypeCritical},
+			{Time: metav1.NewTime(now), Name: "fatal", Type: lep_common.EventTypeFatal},
+		},
+	}
+	if err := lep_components.RegisterComponent(comp.name, comp); err != nil {
+		t.Fatal(err)
+	}
+	g := newGlobalHandler(nil, staticComponents(map[string]lep_components.Component{comp.name: comp}))
+	router := gin.New()
+	router.GET(URLPathEvents, g.getEvents)
+
+	get := func(t *testing.T, q url.Values) (int, []string) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, URLPathEvents+"?"+q.Encode(), nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var got v1.LeptonEvents
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("expected 1 component, got %+v", got)
+		}
+		names := []string{}
+		for _, ev := range got[0].Events {
+			names = append(names, ev.Name)
+		}
+		return w.Code, names
+	}
+
+	tests := []struct {
+		minSeverity string
+		expected    []string
+	}{
+		{minSeverity: "", expected: []string{"info", "warning", "critical", "fatal"}},
+		{minSeverity: "Warning", expected: []string{"warning", "critical", "fatal"}},
+		{minSeverity: "Critical", expected: []string{"critical", "fatal"}},
+		{minSeverity: "Fatal", expected: []string{"fatal"}},
+	}
+	for _, tt := range tests {
+		q := url.Values{"components": {comp.name}}
+		if tt.minSeverity != "" {
+			q.Set("minSeverity", tt.minSeverity)
+		}
+		_, names := get(t, q)
+		if !reflect.DeepEqual(names, tt.expected) {
+			t.Errorf("min severity %q: expected %v, got %v", tt.minSeverity, tt.expected, names)
+		}
+	}
+
+	if code, _ := get(t, url.Values{"components": {comp.name}, "minSeverity": {"Severe"}}); code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
+
+type panicComponent struct {
+	mockComponent
+}
+
+func (p *panicComponent) States(ctx context.Context) ([]lep_components.State, error) {
+	panic("nvml poll panicked")
+}
+
+func TestGetInfoComponentPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthy := &mockComponent{
+		name:    "test-info-healthy",
+		states:  []lep_components.State{{Name: "test-info-healthy", Healthy: true}},
+		metrics: []lep_components.Metric{{Metric: components_metrics_state.Metric{MetricName: "test", Value: 1}}},
+	}
+	failing := &panicComponent{mockComponent{
+		name:   "test-info-panic",
+		events: []lep_components.Event{{Name: "event-0"}},
+	}}
+	for _, comp := range []lep_components.Component{healthy, failing} {
+		if err := lep_components.RegisterComponent(comp.Name(), comp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := newGlobalHandler(nil, staticComponents(map[string]lep_components.Component{healthy.name: healthy, failing.name: failing}))
+	router := gin.New()
+	router.GET(URLPathInfo, g.getInfo)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, URLPathInfo+"?components="+failing.name+","+healthy.name, nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var got v1.LeptonInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	infos := make(map[string]v1.LeptonComponentInfo)
+	for _, info := range got {
+		infos[info.Component] = info
+	}
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 components, got %+v", got)
+	}
+
+	h := infos[healthy.name]
+	if h.Error != "" {
+		t.Errorf("expected no error for the healthy component, got %q", h.Error)
+	}
+	if !reflect.DeepEqual(h.Info.States, healthy.states) || len(h.Info.Metrics) != 1 {
+		t.Errorf("unexpected healthy component info %+v", h.Info)
+	}
+
+	f := infos[failing.name]
+	if !strings.Contains(f.Error, "failed to get states") || !strings.Contains(f.Error, "nvml poll panicked") {
+		t.Errorf("expected the panic error, got %q", f.Error)
+	}
+	if len(f.Info.States) != 0 {
+		t.Errorf("expected no states, got %+v", f.Info.States)
+	}
+	// the other info of the failing component is still returned
+	if len(f.Info.Events) != 1 {
+		t.Errorf("expected 1 event, got %+v", f.Info.Events)
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	"github.com/nxadm/tail"
 	"github.com/prometheus/client_golang/prometheus"
@@ -36,6 +35,8 @@ import (
 	nvidia_clock_speed "github.com/leptonai/gpud/components/accelerator/nvidia/clock-speed"
 	nvidia_clock_speed_id "github.com/leptonai/gpud/components/accelerator/nvidia/clock-speed/id"
 	nvidia_common "github.com/leptonai/gpud/components/accelerator/nvidia/common"
+	nvidia_dmon "github.com/leptonai/gpud/components/accelerator/nvidia/dmon"
+	nvidia_dmon_id "github.com/leptonai/gpud/components/accelerator/nvidia/dmon/id"
 	nvidia_ecc "github.com/leptonai/gpud/components/accelerator/nvidia/ecc"
 	nvidia_ecc_id "github.com/leptonai/gpud/components/accelerator/nvidia/ecc/id"
 	nvidia_error "github.com/leptonai/gpud/components/accelerator/nvidia/error"
@@ -53,12 +54,20 @@ import (
 	nvidia_hw_slowdown_id "github.com/leptonai/gpud/components/accelerator/nvidia/hw-slowdown/id"
 	nvidia_hw_slowdown_state "github.com/leptonai/gpud/components/accelerator/nvidia/hw-slowdown/state"
 	nvidia_infiniband "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband"
+	nvidia_infiniband_link "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link"
+	nvidia_infiniband_link_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband-link/id"
 	nvidia_infiniband_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband/id"
 	nvidia_info "github.com/leptonai/gpud/components/accelerator/nvidia/info"
+	nvidia_inventory "github.com/leptonai/gpud/components/accelerator/nvidia/inventory"
+	nvidia_inventory_id "github.com/leptonai/gpud/components/accelerator/nvidia/inventory/id"
 	nvidia_memory "github.com/leptonai/gpud/components/accelerator/nvidia/memory"
 	nvidia_nccl "github.com/leptonai/gpud/components/accelerator/nvidia/nccl"
 	nvidia_nccl_id "github.com/leptonai/gpud/components/accelerator/nvidia/nccl/id"
+	nvidia_numa "github.com/leptonai/gpud/components/accelerator/nvidia/numa"
+	nvidia_numa_id "github.com/leptonai/gpud/components/accelerator/nvidia/numa/id"
 	nvidia_nvlink "github.com/leptonai/gpud/components/accelerator/nvidia/nvlink"
+	nvidia_pcie "github.com/leptonai/gpud/components/accelerator/nvidia/pcie"
+	nvidia_pcie_id "github.com/leptonai/gpud/components/accelerator/nvidia/pcie/id"
 	nvidia_peermem "github.com/leptonai/gpud/components/accelerator/nvidia/peermem"
 	nvidia_peermem_id "github.com/leptonai/gpud/components/accelerator/nvidia/peermem/id"
 	nvidia_persistence_mode "github.com/leptonai/gpud/components/accelerator/nvidia/persistence-mode"
@@ -73,6 +82,8 @@ import (
 	nvidia_xid_sxid_state "github.com/leptonai/gpud/components/accelerator/nvidia/query/xid-sxid-state"
 	nvidia_remapped_rows "github.com/leptonai/gpud/components/accelerator/nvidia/remapped-rows"
 	nvidia_temperature "github.com/leptonai/gpud/components/accelerator/nvidia/temperature"
+	nvidia_thermal_threshold "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold"
+	nvidia_thermal_threshold_id "github.com/leptonai/gpud/components/accelerator/nvidia/thermal-threshold/id"
 	nvidia_utilization "github.com/leptonai/gpud/components/accelerator/nvidia/utilization"
 	containerd_pod "github.com/leptonai/gpud/components/containerd/pod"
 	containerd_pod_id "github.com/leptonai/gpud/components/containerd/pod/id"
@@ -81,6 +92,8 @@ import (
 	events_db "github.com/leptonai/gpud/components/db"
 	"github.com/leptonai/gpud/components/disk"
 	disk_id "github.com/leptonai/gpud/components/disk/id"
+	disk_smart "github.com/leptonai/gpud/components/disk/smart"
+	disk_smart_id "github.com/leptonai/gpud/components/disk/smart/id"
 	"github.com/leptonai/gpud/components/dmesg"
 	docker_container "github.com/leptonai/gpud/components/docker/container"
 	docker_container_id "github.com/leptonai/gpud/components/docker/container/id"
@@ -123,7 +136,9 @@ import (
 	lepconfig "github.com/leptonai/gpud/config"
 	_ "github.com/leptonai/gpud/docs/apis"
 	"github.com/leptonai/gpud/internal/login"
+	"github.com/leptonai/gpud/internal/repair"
 	"github.com/leptonai/gpud/internal/session"
+	"github.com/leptonai/gpud/internal/webhook"
 	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/manager"
 	"github.com/leptonai/gpud/pkg/sqlite"
@@ -143,6 +158,8 @@ type Server struct {
 	session               *session.Session
 	enableAutoUpdate      bool
 	autoUpdateExitCode    int
+	dryRun                bool
+	sessionOpts           []session.OpOption
 }
 
 func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID string, packageManager *manager.Manager, opts ...gpud_config.OpOption) (_ *Server, retErr error) {
@@ -159,6 +176,44 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 	if config.State != "" {
 		stateFile = config.State
 	}
+	// set before creating any event store
+	if err := events_db.SetBackend(config.EventStore, config.EventStoreMaxEvents); err != nil {
+		return nil, fmt.Errorf("failed to set event store backend: %w", err)
+	}
+	var sinks []events_db.Sink
+	if config.WebhookURL != "" {
+		sender, err := webhook.New(
+			config.WebhookURL,
+			webhook.WithHeaders(config.WebhookHeaders),
+			webhook.WithMinEventType(config.WebhookMinEventType),
+			webhook.WithQuietHours(config.QuietHours...),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook sender: %w", err)
+		}
+		sender.Start(ctx)
+		sinks = append(sinks, sender)
+	}
+	if config.SlackWebhookURL != "" {
+		hostname, err := goOS.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		slackOpts := []webhook.OpOption{
+			webhook.WithMinEventType(config.WebhookMinEventType),
+			webhook.WithQuietHours(config.QuietHours...),
+		}
+		if config.SlackCooldown.Duration > 0 {
+			slackOpts = append(slackOpts, webhook.WithCooldown(config.SlackCooldown.Duration))
+		}
+		sender, err := webhook.NewSlack(config.SlackWebhookURL, hostname, slackOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create slack sender: %w", err)
+		}
+		sender.Start(ctx)
+		sinks = append(sinks, sender)
+	}
+
 	dbRW, err := sqlite.Open(stateFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open state file (for read-write): %w", err)
@@ -172,6 +227,24 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 	if err := sqlite.Register(promReg); err != nil {
 		return nil, fmt.Errorf("failed to register sqlite metrics: %w", err)
 	}
+	if err := session.Register(promReg); err != nil {
+		return nil, fmt.Errorf("failed to register session metrics: %w", err)
+	}
+	if err := repair.Register(promReg); err != nil {
+		return nil, fmt.Errorf("failed to register repair metrics: %w", err)
+	}
+
+	// set before any event is inserted
+	if err := repair.CreateTableActionCounts(ctx, dbRW); err != nil {
+		return nil, fmt.Errorf("failed to create repair action counts table: %w", err)
+	}
+	if err := repair.LoadMetrics(ctx, dbRO); err != nil {
+		return nil, fmt.Errorf("failed to load repair action counts: %w", err)
+	}
+	recommendationCounter := repair.NewRecommendationCounter(dbRW)
+	recommendationCounter.Start(ctx)
+	sinks = append(sinks, recommendationCounter)
+	events_db.SetSinks(sinks...)
 
 	fifoPath, err := lepconfig.DefaultFifoFile()
 	if err != nil {
@@ -184,6 +257,13 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 		fifoPath:           fifoPath,
 		enableAutoUpdate:   config.EnableAutoUpdate,
 		autoUpdateExitCode: config.AutoUpdateExitCode,
+		dryRun:             config.DryRun,
+		sessionOpts: []session.OpOption{
+			session.WithKeepAliveInterval(config.SessionKeepAliveInterval.Duration),
+			session.WithKeepAliveTimeout(config.SessionKeepAliveTimeout.Duration),
+			session.WithReaderQueueSize(config.SessionReaderQueueSize),
+			session.WithReaderQueuePolicy(session.ReaderQueuePolicy(config.SessionReaderQueuePolicy)),
+		},
 	}
 	defer func() {
 		if retErr != nil {
@@ -225,6 +305,7 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			nvidia_query.WithNvidiaSMIQueryCommand(options.NvidiaSMIQueryCommand),
 			nvidia_query.WithIbstatCommand(options.IbstatCommand),
 			nvidia_query.WithInfinibandClassDirectory(options.InfinibandClassDirectory),
+			nvidia_query.WithNvidiaSMIXMLFallback(options.NvidiaSMIXMLFallback),
 		)
 	}
 
@@ -343,8 +424,16 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 					continue
 				}
 				if ev.Detail == nil {
-					log.Logger.Errorw("failed to parse xid dmesg line", "line", string(line), "error", "no detail")
-					continue
+					xidNum := nvidia_query_xid.ExtractNVRMXid(string(line))
+					if xidNum == 0 {
+						log.Logger.Errorw("failed to parse xid dmesg line", "line", string(line), "error", "no xid")
+						continue
+					}
+
+					// unknown Xids (e.g., introduced by the newer drivers) are
+					// still recorded with the synthesized detail, rather than dropped
+					detail := nvidia_query_xid.GetDetailOrDefault(xidNum)
+					ev.Detail = &detail
 				}
 
 				eventToInsert := nvidia_xid_sxid_state.Event{
@@ -485,6 +574,20 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			}
 			allComponents = append(allComponents, disk.New(ctx, cfg))
 
+		case disk_smart_id.Name:
+			cfg := disk_smart.Config{Query: defaultQueryCfg}
+			if configValue != nil {
+				parsed, err := disk_smart.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			allComponents = append(allComponents, disk_smart.New(ctx, cfg))
+
 		case fuse_id.Name:
 			cfg := fuse.Config{
 				Query:                                defaultQueryCfg,
@@ -737,18 +840,21 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			allComponents = append(allComponents, tailscale.New(ctx, cfg))
 
 		case nvidia_info.Name:
-			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
+			cfg := &nvidia_info.Config{
+				Query:          defaultQueryCfg,
+				ToolOverwrites: options.ToolOverwrites,
+			}
 			if configValue != nil {
-				parsed, err := nvidia_common.ParseConfig(configValue, dbRW, dbRO)
+				parsed, err := nvidia_info.ParseConfig(configValue, dbRW, dbRO)
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
 				}
-				cfg = *parsed
+				*cfg = *parsed
 			}
 			if err := cfg.Validate(); err != nil {
 				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
 			}
-			c, err := nvidia_info.New(ctx, cfg)
+			c, err := nvidia_info.New(ctx, *cfg)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
 			}
@@ -791,11 +897,23 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			allComponents = append(allComponents, c)
 
 		case nvidia_component_error_xid_id.Name:
-			allComponents = append(allComponents, nvidia_error_xid.New(ctx, dbRW, dbRO))
+			var opts []nvidia_error_xid.OpOption
+			if configValue != nil {
+				parsed, err := nvidia_error_xid.ParseConfig(configValue)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				if err := parsed.Validate(); err != nil {
+					return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+				}
+				opts = parsed.Options()
+			}
+			opts = append(opts, nvidia_error_xid.WithEventTypeHealthMapping(config.EventTypeHealthMapping))
+			allComponents = append(allComponents, nvidia_error_xid.New(ctx, dbRW, dbRO, opts...))
 
 		case nvidia_component_error_sxid_id.Name:
 			// db object to read sxid events (read-only, writes are done in poller)
-			allComponents = append(allComponents, nvidia_error_sxid.New(ctx, dbRW, dbRO))
+			allComponents = append(allComponents, nvidia_error_sxid.New(ctx, dbRW, dbRO, nvidia_error_sxid.WithEventTypeHealthMapping(config.EventTypeHealthMapping)))
 
 		case nvidia_component_error_xid_sxid_id.Name:
 			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
@@ -902,18 +1020,21 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			allComponents = append(allComponents, nvidia_gpm.New(ctx, cfg))
 
 		case nvidia_nvlink.Name:
-			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
+			cfg := &nvidia_nvlink.Config{
+				Query:          defaultQueryCfg,
+				ToolOverwrites: options.ToolOverwrites,
+			}
 			if configValue != nil {
-				parsed, err := nvidia_common.ParseConfig(configValue, dbRW, dbRO)
+				parsed, err := nvidia_nvlink.ParseConfig(configValue, dbRW, dbRO)
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
 				}
-				cfg = *parsed
+				*cfg = *parsed
 			}
 			if err := cfg.Validate(); err != nil {
 				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
 			}
-			c, err := nvidia_nvlink.New(ctx, cfg)
+			c, err := nvidia_nvlink.New(ctx, *cfg)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
 			}
@@ -1070,6 +1191,20 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			}
 			allComponents = append(allComponents, c)
 
+		case nvidia_infiniband_link_id.Name:
+			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
+			if configValue != nil {
+				parsed, err := nvidia_common.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			allComponents = append(allComponents, nvidia_infiniband_link.New(ctx, cfg))
+
 		case nvidia_peermem_id.Name:
 			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
 			if configValue != nil {
@@ -1106,6 +1241,101 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 			}
 			allComponents = append(allComponents, c)
 
+		case nvidia_inventory_id.Name:
+			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
+			if configValue != nil {
+				parsed, err := nvidia_common.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			c, err := nvidia_inventory.New(ctx, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
+			}
+			allComponents = append(allComponents, c)
+
+		case nvidia_pcie_id.Name:
+			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
+			if configValue != nil {
+				parsed, err := nvidia_common.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			c, err := nvidia_pcie.New(ctx, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
+			}
+			allComponents = append(allComponents, c)
+
+		case nvidia_thermal_threshold_id.Name:
+			cfg := &nvidia_thermal_threshold.Config{
+				Query:          defaultQueryCfg,
+				ToolOverwrites: options.ToolOverwrites,
+			}
+			if configValue != nil {
+				parsed, err := nvidia_thermal_threshold.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				*cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			c, err := nvidia_thermal_threshold.New(ctx, *cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
+			}
+			allComponents = append(allComponents, c)
+
+		case nvidia_dmon_id.Name:
+			cfg := &nvidia_dmon.Config{
+				Query:          defaultQueryCfg,
+				ToolOverwrites: options.ToolOverwrites,
+			}
+			if configValue != nil {
+				parsed, err := nvidia_dmon.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				*cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			allComponents = append(allComponents, nvidia_dmon.New(ctx, *cfg))
+
+		case nvidia_numa_id.Name:
+			cfg := &nvidia_numa.Config{
+				Query:          defaultQueryCfg,
+				ToolOverwrites: options.ToolOverwrites,
+			}
+			if configValue != nil {
+				parsed, err := nvidia_numa.ParseConfig(configValue, dbRW, dbRO)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse component %s config: %w", k, err)
+				}
+				*cfg = *parsed
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to validate component %s config: %w", k, err)
+			}
+			c, err := nvidia_numa.New(ctx, *cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create component %s: %w", k, err)
+			}
+			allComponents = append(allComponents, c)
+
 		case nvidia_nccl_id.Name:
 			cfg := nvidia_common.Config{Query: defaultQueryCfg, ToolOverwrites: options.ToolOverwrites}
 			if configValue != nil {
@@ -1369,11 +1599,12 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 
 	v1 := router.Group("/v1")
 
-	// if the request header is set "Accept-Encoding: gzip",
-	// the middleware automatically gzip-compresses the response with the response header "Content-Encoding: gzip"
-	v1.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPaths([]string{"/update/"})))
+	// if the request header is set "Accept-Encoding: gzip" (or "deflate"),
+	// the middleware compresses the response with the response header "Content-Encoding: gzip",
+	// unless the response is smaller than the threshold
+	v1.Use(compressResponse(DefaultCompressMinSize))
 
-	ghler := newGlobalHandler(config, components.GetAllComponents())
+	ghler := newGlobalHandler(config, components.GetAllComponents)
 	registeredPaths := ghler.registerComponentRoutes(v1)
 	for i := range registeredPaths {
 		registeredPaths[i].Path = path.Join(v1.BasePath(), registeredPaths[i].Path)
@@ -1383,6 +1614,8 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 		Path: "/metrics",
 		Desc: "Prometheus metrics",
 	})
+	// all the metrics are gathered at once per scrape, and stamped with the scrape time by Prometheus
+	// (no explicit sample timestamps, which would break the staleness handling)
 	promHandler := promhttp.HandlerFor(promReg, promhttp.HandlerOpts{})
 	router.GET("/metrics", func(ctx *gin.Context) {
 		promHandler.ServeHTTP(ctx.Writer, ctx.Request)
@@ -1394,6 +1627,11 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 		Path: URLPathHealthz,
 		Desc: URLPathHealthzDesc,
 	})
+	router.GET(URLPathReadyz, ghler.getReadyz)
+	registeredPaths = append(registeredPaths, componentHandlerDescription{
+		Path: URLPathReadyz,
+		Desc: URLPathReadyzDesc,
+	})
 
 	admin := router.Group("/admin")
 
@@ -1538,18 +1776,9 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 					}
 				}
 
-				newComponentNames := make([]string, len(componentNames))
-				copy(newComponentNames, componentNames)
-				for _, c := range componentsToAdd {
-					newComponentNames = append(newComponentNames, c.Name())
-				}
-				if err = state.UpdateComponents(ctx, dbRW, s.uid, strings.Join(newComponentNames, ",")); err != nil {
+				if err = state.UpdateComponents(ctx, dbRW, s.uid, strings.Join(ghler.getComponentNames(), ",")); err != nil {
 					log.Logger.Errorw("failed to update components", "error", err)
 				}
-
-				ghler.componentNamesMu.Lock()
-				ghler.componentNames = newComponentNames
-				ghler.componentNamesMu.Unlock()
 			}
 		}()
 	}
@@ -1573,10 +1802,7 @@ func New(ctx context.Context, config *lepconfig.Config, endpoint string, cliUID
 		}
 	}()
 
-	ghler.componentNamesMu.RLock()
-	currComponents := ghler.componentNames
-	ghler.componentNamesMu.RUnlock()
-	if err = login.Gossip(endpoint, uid, config.Address, currComponents); err != nil {
+	if err = login.Gossip(endpoint, uid, config.Address, ghler.getComponentNames()); err != nil {
 		log.Logger.Debugf("failed to gossip: %v", err)
 	}
 	return s, nil
@@ -1669,6 +1895,19 @@ func (s *Server) generateSelfSignedCert() (tls.Certificate, error) {
 	return cert, nil
 }
 
+func (s *Server) newSession(ctx context.Context, uid string, endpoint string) (*session.Session, error) {
+	opts := []session.OpOption{
+		session.WithMachineID(uid),
+		session.WithPipeInterval(3 * time.Second),
+		session.WithEnableAutoUpdate(s.enableAutoUpdate),
+		session.WithAutoUpdateExitCode(s.autoUpdateExitCode),
+		session.WithDryRun(s.dryRun),
+		session.WithDBRW(s.dbRW),
+	}
+	opts = append(opts, s.sessionOpts...)
+	return session.NewSession(ctx, fmt.Sprintf("https://%s/api/v1/session", endpoint), opts...)
+}
+
 func (s *Server) updateToken(ctx context.Context, db *sql.DB, uid string, endpoint string) {
 	var userToken string
 	pipePath := s.fifoPath
@@ -1678,14 +1917,7 @@ func (s *Server) updateToken(ctx context.Context, db *sql.DB, uid string, endpoi
 
 	if userToken != "" {
 		var err error
-		s.session, err = session.NewSession(
-			ctx,
-			fmt.Sprintf("https://%s/api/v1/session", endpoint),
-			session.WithMachineID(uid),
-			session.WithPipeInterval(3*time.Second),
-			session.WithEnableAutoUpdate(s.enableAutoUpdate),
-			session.WithAutoUpdateExitCode(s.autoUpdateExitCode),
-		)
+		s.session, err = s.newSession(ctx, uid, endpoint)
 		if err != nil {
 			log.Logger.Errorw("error creating session", "error", err)
 		}
@@ -1723,14 +1955,7 @@ func (s *Server) updateToken(ctx context.Context, db *sql.DB, uid string, endpoi
 			if s.session != nil {
 				s.session.Stop()
 			}
-			s.session, err = session.NewSession(
-				ctx,
-				fmt.Sprintf("https://%s/api/v1/session", endpoint),
-				session.WithMachineID(uid),
-				session.WithPipeInterval(3*time.Second),
-				session.WithEnableAutoUpdate(s.enableAutoUpdate),
-				session.WithAutoUpdateExitCode(s.autoUpdateExitCode),
-			)
+			s.session, err = s.newSession(ctx, uid, endpoint)
 			if err != nil {
 				log.Logger.Errorw("error creating session", "error", err)
 			}
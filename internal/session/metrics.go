@@ -0,0 +1,23 @@
+package session
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	readerDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "session",
+			Subsystem: "reader",
+			Name:      "dropped_total",
+			Help:      "total number of messages dropped because the reader queue is full",
+		},
+	)
+)
+
+func Register(reg *prometheus.Registry) error {
+	if err := reg.Register(readerDroppedTotal); err != nil {
+		return err
+	}
+	return nil
+}
@@ -19,10 +19,10 @@ import (
 	nvidia_component_error_xid_id "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid/id"
 	nvidia_infiniband "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband"
 	nvidia_infiniband_id "github.com/leptonai/gpud/components/accelerator/nvidia/infiniband/id"
+	"github.com/leptonai/gpud/components/common"
 	"github.com/leptonai/gpud/components/metrics"
 	"github.com/leptonai/gpud/components/query"
 	"github.com/leptonai/gpud/log"
-	"github.com/leptonai/gpud/pkg/reboot"
 	"github.com/leptonai/gpud/pkg/systemd"
 	"github.com/leptonai/gpud/update"
 )
@@ -56,10 +56,11 @@ func (s *Session) serve() {
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 		if payload.Method == "reboot" {
-			rerr := reboot.Reboot(ctx, reboot.WithDelaySeconds(0))
-
+			plan, rerr := s.repairExecutor.Execute(ctx, common.RepairActionTypeRebootSystem, "")
 			if rerr != nil {
 				log.Logger.Errorf("failed to trigger reboot machine: %v", rerr)
+			} else if plan.DryRun {
+				log.Logger.Infow("reboot requested in dry-run mode, not rebooting", "command", plan.Command)
 			}
 
 			cancel()
@@ -190,9 +191,11 @@ func (s *Session) serve() {
 		cancel()
 
 		responseRaw, _ := json.Marshal(response)
-		s.writer <- Body{
+		if !s.sendWriter(Body{
 			Data:  responseRaw,
 			ReqID: body.ReqID,
+		}) {
+			log.Logger.Debugw("session stopped, dropping response", "reqID", body.ReqID)
 		}
 
 		if needExit != -1 {
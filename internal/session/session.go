@@ -2,28 +2,66 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/internal/repair"
 	"github.com/leptonai/gpud/log"
 )
 
+const (
+	// DefaultKeepAliveInterval is the default interval between attempts
+	// to (re-)establish the session reader and writer connections.
+	DefaultKeepAliveInterval = time.Second
+
+	// DefaultKeepAliveTimeout is the default maximum duration the reader
+	// waits without receiving any message before dropping the connection.
+	DefaultKeepAliveTimeout = 2 * time.Minute
+
+	// DefaultReaderQueueSize is the default capacity of the queue
+	// between the session reader and the request handler.
+	DefaultReaderQueueSize = 20
+)
+
+// ReaderQueuePolicy defines what the session reader does
+// when the reader queue is full.
+type ReaderQueuePolicy string
+
+const (
+	// ReaderQueuePolicyDrop drops the incoming message when the queue is full.
+	ReaderQueuePolicyDrop ReaderQueuePolicy = "drop"
+	// ReaderQueuePolicyBlock blocks the reader until the queue has room,
+	// the connection is closed, or the session is stopped.
+	ReaderQueuePolicyBlock ReaderQueuePolicy = "block"
+)
+
 type Op struct {
 	machineID          string
 	pipeInterval       time.Duration
+	keepAliveInterval  time.Duration
+	keepAliveTimeout   time.Duration
+	readerQueueSize    int
+	readerQueuePolicy  ReaderQueuePolicy
 	enableAutoUpdate   bool
 	autoUpdateExitCode int
+	dryRun             bool
+	dbRW               *sql.DB
 }
 
 type OpOption func(*Op)
 
-var ErrAutoUpdateDisabledButExitCodeSet = errors.New("auto update is disabled but auto update by exit code is set")
+var (
+	ErrAutoUpdateDisabledButExitCodeSet = errors.New("auto update is disabled but auto update by exit code is set")
+	ErrInvalidReaderQueuePolicy         = errors.New("invalid reader queue policy")
+)
 
 func (op *Op) applyOpts(opts []OpOption) error {
 	op.autoUpdateExitCode = -1
@@ -32,6 +70,23 @@ func (op *Op) applyOpts(opts []OpOption) error {
 		opt(op)
 	}
 
+	if op.keepAliveInterval <= 0 {
+		op.keepAliveInterval = DefaultKeepAliveInterval
+	}
+	if op.keepAliveTimeout <= 0 {
+		op.keepAliveTimeout = DefaultKeepAliveTimeout
+	}
+	if op.readerQueueSize <= 0 {
+		op.readerQueueSize = DefaultReaderQueueSize
+	}
+	switch op.readerQueuePolicy {
+	case "":
+		op.readerQueuePolicy = ReaderQueuePolicyDrop
+	case ReaderQueuePolicyDrop, ReaderQueuePolicyBlock:
+	default:
+		return ErrInvalidReaderQueuePolicy
+	}
+
 	if !op.enableAutoUpdate && op.autoUpdateExitCode != -1 {
 		return ErrAutoUpdateDisabledButExitCodeSet
 	}
@@ -51,6 +106,39 @@ func WithPipeInterval(t time.Duration) OpOption {
 	}
 }
 
+// Sets the interval between attempts to (re-)establish the session connections.
+// Defaults to DefaultKeepAliveInterval if zero.
+func WithKeepAliveInterval(t time.Duration) OpOption {
+	return func(op *Op) {
+		op.keepAliveInterval = t
+	}
+}
+
+// Sets the maximum duration to wait without receiving any message
+// before the reader connection is dropped and re-established.
+// Defaults to DefaultKeepAliveTimeout if zero.
+func WithKeepAliveTimeout(t time.Duration) OpOption {
+	return func(op *Op) {
+		op.keepAliveTimeout = t
+	}
+}
+
+// Sets the capacity of the queue between the session reader and the request handler.
+// Defaults to DefaultReaderQueueSize if zero.
+func WithReaderQueueSize(n int) OpOption {
+	return func(op *Op) {
+		op.readerQueueSize = n
+	}
+}
+
+// Sets the policy applied when the reader queue is full.
+// Defaults to ReaderQueuePolicyDrop.
+func WithReaderQueuePolicy(p ReaderQueuePolicy) OpOption {
+	return func(op *Op) {
+		op.readerQueuePolicy = p
+	}
+}
+
 func WithEnableAutoUpdate(enableAutoUpdate bool) OpOption {
 	return func(op *Op) {
 		op.enableAutoUpdate = enableAutoUpdate
@@ -66,24 +154,52 @@ func WithAutoUpdateExitCode(autoUpdateExitCode int) OpOption {
 	}
 }
 
+// Set true to only log the repair actions requested by the control plane
+// (e.g., reboot) without executing them.
+func WithDryRun(dryRun bool) OpOption {
+	return func(op *Op) {
+		op.dryRun = dryRun
+	}
+}
+
+// Specifies the database to persist the number of the performed repair actions.
+func WithDBRW(db *sql.DB) OpOption {
+	return func(op *Op) {
+		op.dbRW = db
+	}
+}
+
 type Session struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	pipeInterval time.Duration
+	pipeInterval      time.Duration
+	keepAliveInterval time.Duration
+	keepAliveTimeout  time.Duration
+
+	readerQueuePolicy ReaderQueuePolicy
 
 	machineID string
 	endpoint  string
 
 	components []string
 
-	closer *closeOnce
+	closerMu sync.RWMutex
+	closer   *closeOnce
+
+	// chMu guards the sends on the reader and writer channels
+	// so that Stop never closes a channel while a send is in flight
+	chMu     sync.RWMutex
+	chClosed bool
+	stopOnce sync.Once
 
 	writer chan Body
 	reader chan Body
 
 	enableAutoUpdate   bool
 	autoUpdateExitCode int
+
+	repairExecutor *repair.Executor
 }
 
 type closeOnce struct {
@@ -108,6 +224,11 @@ func NewSession(ctx context.Context, endpoint string, opts ...OpOption) (*Sessio
 		return nil, err
 	}
 
+	repairExecutor, err := repair.New(repair.WithDryRun(op.dryRun), repair.WithDBRW(op.dbRW))
+	if err != nil {
+		return nil, err
+	}
+
 	cps := make([]string, 0)
 	allComponents := components.GetAllComponents()
 	for key := range allComponents {
@@ -119,7 +240,11 @@ func NewSession(ctx context.Context, endpoint string, opts ...OpOption) (*Sessio
 		ctx:    cctx,
 		cancel: ccancel,
 
-		pipeInterval: op.pipeInterval,
+		pipeInterval:      op.pipeInterval,
+		keepAliveInterval: op.keepAliveInterval,
+		keepAliveTimeout:  op.keepAliveTimeout,
+
+		readerQueuePolicy: op.readerQueuePolicy,
 
 		endpoint:  endpoint,
 		machineID: op.machineID,
@@ -128,9 +253,11 @@ func NewSession(ctx context.Context, endpoint string, opts ...OpOption) (*Sessio
 
 		enableAutoUpdate:   op.enableAutoUpdate,
 		autoUpdateExitCode: op.autoUpdateExitCode,
+
+		repairExecutor: repairExecutor,
 	}
 
-	s.reader = make(chan Body, 20)
+	s.reader = make(chan Body, op.readerQueueSize)
 	s.writer = make(chan Body, 20)
 	s.closer = &closeOnce{closer: make(chan any)}
 	go s.keepAlive()
@@ -144,8 +271,77 @@ type Body struct {
 	ReqID string `json:"req_id,omitempty"`
 }
 
+func (s *Session) getCloser() *closeOnce {
+	s.closerMu.RLock()
+	defer s.closerMu.RUnlock()
+	return s.closer
+}
+
+func (s *Session) setCloser(c *closeOnce) {
+	s.closerMu.Lock()
+	defer s.closerMu.Unlock()
+	s.closer = c
+}
+
+// sendReader forwards the body to the reader channel.
+// In block mode, it waits until the channel has room, the connection
+// is closed (closec), or the session is stopped.
+// In drop mode, it drops the body if the channel is full.
+// Returns false if the body was not delivered.
+func (s *Session) sendReader(body Body, closec <-chan any) bool {
+	s.chMu.RLock()
+	defer s.chMu.RUnlock()
+	if s.chClosed {
+		return false
+	}
+	select {
+	case <-s.ctx.Done():
+		return false
+	default:
+	}
+
+	if s.readerQueuePolicy == ReaderQueuePolicyBlock {
+		select {
+		case s.reader <- body:
+			return true
+		case <-closec:
+			return false
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+
+	select {
+	case s.reader <- body:
+		return true
+	default:
+		readerDroppedTotal.Inc()
+		return false
+	}
+}
+
+// sendWriter forwards the body to the writer channel,
+// blocking until it is accepted or the session is stopped.
+func (s *Session) sendWriter(body Body) bool {
+	s.chMu.RLock()
+	defer s.chMu.RUnlock()
+	if s.chClosed {
+		return false
+	}
+	select {
+	case s.writer <- body:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
 func (s *Session) keepAlive() {
-	ticker := time.NewTicker(1 * time.Second)
+	interval := s.keepAliveInterval
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		select {
@@ -155,8 +351,8 @@ func (s *Session) keepAlive() {
 		case <-ticker.C:
 			readerExit := make(chan any)
 			writerExit := make(chan any)
-			s.closer = &closeOnce{closer: make(chan any)}
-			ctx, cancel := context.WithCancel(context.Background()) // create local context for each session
+			s.setCloser(&closeOnce{closer: make(chan any)})
+			ctx, cancel := context.WithCancel(s.ctx) // create local context for each session
 			go s.startReader(ctx, readerExit)
 			go s.startWriter(ctx, writerExit)
 			<-readerExit
@@ -174,7 +370,7 @@ func (s *Session) startWriter(ctx context.Context, writerExit chan any) {
 	goroutineCloseCh := make(chan any)
 	defer func() {
 		close(goroutineCloseCh)
-		s.closer.Close()
+		s.getCloser().Close()
 		<-pipeFinishCh
 		close(writerExit)
 	}()
@@ -216,9 +412,10 @@ func (s *Session) handleWriterPipe(writer *io.PipeWriter, closec, finish chan an
 	defer close(finish)
 	defer writer.Close()
 	log.Logger.Debug("session writer: pipe handler started")
+	closer := s.getCloser()
 	for {
 		select {
-		case <-s.closer.Done():
+		case <-closer.Done():
 			log.Logger.Debug("session writer: session closed, closing pipe handler")
 			return
 
@@ -226,7 +423,11 @@ func (s *Session) handleWriterPipe(writer *io.PipeWriter, closec, finish chan an
 			log.Logger.Debug("session writer: request finished, closing pipe handler")
 			return
 
-		case body := <-s.writer:
+		case body, ok := <-s.writer:
+			if !ok {
+				log.Logger.Debug("session writer: session stopped, closing pipe handler")
+				return
+			}
 			bytes, err := json.Marshal(body)
 			if err != nil {
 				log.Logger.Errorf("session writer: failed to marshal body: %v", err)
@@ -248,7 +449,7 @@ func (s *Session) startReader(ctx context.Context, readerExit chan any) {
 	pipeFinishCh := make(chan any)
 	defer func() {
 		close(goroutineCloseCh)
-		s.closer.Close()
+		s.getCloser().Close()
 		<-pipeFinishCh
 		close(readerExit)
 	}()
@@ -288,9 +489,10 @@ func (s *Session) startReader(ctx context.Context, readerExit chan any) {
 		return
 	}
 
-	lastPackageTimestamp := &time.Time{}
-	*lastPackageTimestamp = time.Now()
+	lastPackageTimestamp := &atomic.Int64{}
+	lastPackageTimestamp.Store(time.Now().UnixNano())
 	decoder := json.NewDecoder(resp.Body)
+	closer := s.getCloser()
 	go s.handleReaderPipe(resp.Body, lastPackageTimestamp, goroutineCloseCh, pipeFinishCh)
 	for {
 		var content Body
@@ -300,37 +502,47 @@ func (s *Session) startReader(ctx context.Context, readerExit chan any) {
 			break
 		}
 		select {
-		case <-s.closer.Done():
+		case <-closer.Done():
 			log.Logger.Debug("session reader: session closed, dropping message")
 			return
-		case s.reader <- content:
-			*lastPackageTimestamp = time.Now()
-			log.Logger.Debug("session reader: request received and written to pipe")
 		default:
-			log.Logger.Errorw("session reader: reader channel full, dropping message")
 		}
+		if !s.sendReader(content, closer.Done()) {
+			log.Logger.Errorw("session reader: reader channel full or closed, dropping message")
+			continue
+		}
+		lastPackageTimestamp.Store(time.Now().UnixNano())
+		log.Logger.Debug("session reader: request received and written to pipe")
 	}
 }
 
-func (s *Session) handleReaderPipe(respBody io.ReadCloser, lastPackageTimestamp *time.Time, closec, finish chan any) {
+func (s *Session) handleReaderPipe(respBody io.ReadCloser, lastPackageTimestamp *atomic.Int64, closec, finish chan any) {
 	defer close(finish)
 	log.Logger.Debug("session reader: pipe handler started")
-	threshold := 2 * time.Minute
-	ticker := time.NewTicker(1 * time.Second)
+	closer := s.getCloser()
+	threshold := s.keepAliveTimeout
+	if threshold <= 0 {
+		threshold = DefaultKeepAliveTimeout
+	}
+	checkInterval := time.Second
+	if threshold < checkInterval {
+		checkInterval = threshold
+	}
+	ticker := time.NewTicker(checkInterval)
 	defer func() {
 		respBody.Close()
 		ticker.Stop()
 	}()
 	for {
 		select {
-		case <-s.closer.Done():
+		case <-closer.Done():
 			log.Logger.Debug("session reader: session closed, closing read pipe handler")
 			return
 		case <-closec:
 			log.Logger.Debug("session reader: request finished, closing read pipe handler")
 			return
 		case <-ticker.C:
-			if time.Since(*lastPackageTimestamp) > threshold {
+			if time.Since(time.Unix(0, lastPackageTimestamp.Load())) > threshold {
 				log.Logger.Debugf("session reader: exceed read wait timeout, closing read pipe handler")
 				return
 			}
@@ -338,15 +550,19 @@ func (s *Session) handleReaderPipe(respBody io.ReadCloser, lastPackageTimestamp
 	}
 }
 
+// Stop cancels the keep alive loop and closes the session channels.
+// Safe to call multiple times.
 func (s *Session) Stop() {
-	select {
-	case <-s.ctx.Done():
-		return
-	default:
+	s.stopOnce.Do(func() {
 		log.Logger.Debug("closing session...")
 		s.cancel()
-		s.closer.Close()
+		s.getCloser().Close()
+
+		// cancel first so that blocked senders return before we acquire the lock
+		s.chMu.Lock()
+		s.chClosed = true
 		close(s.reader)
 		close(s.writer)
-	}
+		s.chMu.Unlock()
+	})
 }
@@ -3,10 +3,14 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestApplyOpts(t *testing.T) {
@@ -50,6 +54,13 @@ func TestApplyOpts(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid reader queue policy",
+			opts: []OpOption{
+				WithReaderQueuePolicy("unknown"),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,13 +70,103 @@ func TestApplyOpts(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("applyOpts() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if tt.wantErr && err != ErrAutoUpdateDisabledButExitCodeSet {
-				t.Errorf("applyOpts() expected error %v, got %v", ErrAutoUpdateDisabledButExitCodeSet, err)
+			if tt.wantErr && err != ErrAutoUpdateDisabledButExitCodeSet && err != ErrInvalidReaderQueuePolicy {
+				t.Errorf("applyOpts() unexpected error %v", err)
 			}
 		})
 	}
 }
 
+func TestApplyOptsKeepAlive(t *testing.T) {
+	op := &Op{}
+	if err := op.applyOpts(nil); err != nil {
+		t.Fatal(err)
+	}
+	if op.keepAliveInterval != DefaultKeepAliveInterval {
+		t.Errorf("expected keep alive interval %v, got %v", DefaultKeepAliveInterval, op.keepAliveInterval)
+	}
+	if op.keepAliveTimeout != DefaultKeepAliveTimeout {
+		t.Errorf("expected keep alive timeout %v, got %v", DefaultKeepAliveTimeout, op.keepAliveTimeout)
+	}
+
+	op = &Op{}
+	if err := op.applyOpts([]OpOption{WithKeepAliveInterval(5 * time.Second), WithKeepAliveTimeout(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if op.keepAliveInterval != 5*time.Second {
+		t.Errorf("expected keep alive interval 5s, got %v", op.keepAliveInterval)
+	}
+	if op.keepAliveTimeout != time.Minute {
+		t.Errorf("expected keep alive timeout 1m, got %v", op.keepAliveTimeout)
+	}
+}
+
+func TestKeepAliveInterval(t *testing.T) {
+	var reads atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("session_type") == "read" {
+			reads.Add(1)
+		}
+		// reject every connection so that the keep alive loop retries on each tick
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s, err := NewSession(context.Background(), server.URL, WithKeepAliveInterval(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("error creating session: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if n := reads.Load(); n < 3 {
+		t.Errorf("expected at least 3 keep alive attempts, got %d", n)
+	}
+
+	s.Stop()
+	s.Stop() // no-op
+
+	// allow an in-flight attempt to finish
+	time.Sleep(100 * time.Millisecond)
+	stopped := reads.Load()
+	time.Sleep(200 * time.Millisecond)
+	if n := reads.Load(); n != stopped {
+		t.Errorf("expected no keep alive attempts after stop, got %d more", n-stopped)
+	}
+}
+
+func TestKeepAliveTimeout(t *testing.T) {
+	var reads atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("session_type") != "read" {
+			// drain the writer stream until the client closes it
+			_, _ = io.Copy(io.Discard, r.Body)
+			return
+		}
+		reads.Add(1)
+
+		// hold the connection open without sending any message
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	s, err := NewSession(context.Background(), server.URL,
+		WithKeepAliveInterval(10*time.Millisecond),
+		WithKeepAliveTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("error creating session: %v", err)
+	}
+	defer s.Stop()
+
+	// idle reader connections are dropped after the timeout and re-established
+	time.Sleep(time.Second)
+	if n := reads.Load(); n < 2 {
+		t.Errorf("expected the idle reader to reconnect at least once, got %d connections", n)
+	}
+}
+
 func TestNewSession(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -252,3 +353,181 @@ func TestReaderWriterServerError(t *testing.T) {
 		t.Errorf("Writer channel should be closed")
 	}
 }
+
+func TestStopConcurrentWithTraffic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("session_type") {
+		case "write":
+			_, _ = io.Copy(io.Discard, r.Body)
+
+		case "read":
+			// stream messages until the client goes away
+			enc := json.NewEncoder(w)
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				default:
+				}
+				if err := enc.Encode(Body{ReqID: "server_response_id"}); err != nil {
+					return
+				}
+				w.(http.Flusher).Flush()
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+
+	s, err := NewSession(context.Background(), server.URL, WithKeepAliveInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("error creating session: %v", err)
+	}
+
+	stopc := make(chan struct{})
+	trafficDone := make(chan struct{})
+	go func() {
+		defer close(trafficDone)
+		for {
+			select {
+			case <-stopc:
+				return
+			default:
+			}
+			s.sendWriter(Body{ReqID: "client_req"})
+		}
+	}()
+
+	// let the reader and writer exchange traffic before stopping
+	time.Sleep(200 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			s.Stop()
+			stopped <- struct{}{}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for Stop")
+		}
+	}
+	close(stopc)
+	<-trafficDone
+
+	if s.sendWriter(Body{ReqID: "after_stop"}) {
+		t.Error("expected send after stop to be dropped")
+	}
+	if s.sendReader(Body{ReqID: "after_stop"}, nil) {
+		t.Error("expected send after stop to be dropped")
+	}
+
+	// returns only once the reader channel is closed
+	for range s.reader {
+	}
+}
+
+func TestReaderQueueDrop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		for i := 0; i < 5; i++ {
+			if err := enc.Encode(Body{ReqID: "server_response_id"}); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// no consumer on the reader channel
+	s := &Session{
+		ctx:               ctx,
+		cancel:            cancel,
+		endpoint:          server.URL,
+		readerQueuePolicy: ReaderQueuePolicyDrop,
+		writer:            make(chan Body, 1),
+		reader:            make(chan Body, 2),
+		closer:            &closeOnce{closer: make(chan any)},
+	}
+
+	before := readDroppedTotal(t)
+
+	readerExit := make(chan any)
+	go s.startReader(ctx, readerExit)
+	select {
+	case <-readerExit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reader blocked on a full queue")
+	}
+
+	if n := len(s.reader); n != 2 {
+		t.Errorf("expected 2 queued messages, got %d", n)
+	}
+	if dropped := readDroppedTotal(t) - before; dropped != 3 {
+		t.Errorf("expected 3 dropped messages, got %v", dropped)
+	}
+
+	s.Stop()
+}
+
+func TestReaderQueueBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &Session{
+		ctx:               ctx,
+		cancel:            cancel,
+		readerQueuePolicy: ReaderQueuePolicyBlock,
+		writer:            make(chan Body, 1),
+		reader:            make(chan Body, 1),
+		closer:            &closeOnce{closer: make(chan any)},
+	}
+
+	if !s.sendReader(Body{ReqID: "1"}, s.closer.Done()) {
+		t.Fatal("expected the first message to be queued")
+	}
+
+	sent := make(chan bool)
+	go func() {
+		sent <- s.sendReader(Body{ReqID: "2"}, s.closer.Done())
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("expected the reader to block on a full queue")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case ok := <-sent:
+		if ok {
+			t.Error("expected the blocked message to be dropped on stop")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not unblock the reader")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
+
+func readDroppedTotal(t *testing.T) float64 {
+	var m dto.Metric
+	if err := readerDroppedTotal.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
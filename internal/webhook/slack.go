@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	nvidia_error_sxid "github.com/leptonai/gpud/components/accelerator/nvidia/error/sxid"
+	nvidia_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+)
+
+// DefaultSlackCooldown is the default period to suppress the repeated identical Slack alerts.
+const DefaultSlackCooldown = 10 * time.Minute
+
+// NewSlack creates a sender that posts the events as Slack messages
+// to the Slack incoming webhook URL, with the host name in each message.
+// The repeated identical alerts are suppressed within DefaultSlackCooldown,
+// unless overwritten by WithCooldown.
+// ref. https://api.slack.com/messaging/webhooks
+func NewSlack(webhookURL string, hostname string, opts ...OpOption) (*Sender, error) {
+	opts = append([]OpOption{WithCooldown(DefaultSlackCooldown)}, opts...)
+	opts = append(opts,
+		WithCooldownKey(slackCooldownKey(hostname)),
+		WithEncoder(func(p Payload) ([]byte, error) {
+			return json.Marshal(newSlackMessage(hostname, p))
+		}),
+	)
+	return New(webhookURL, opts...)
+}
+
+// SlackMessage is the Slack message with the Block Kit layout.
+// ref. https://api.slack.com/reference/block-kit/blocks
+type SlackMessage struct {
+	// Fallback text for the notifications.
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackAlert is the event detail shown in the Slack message.
+type slackAlert struct {
+	hostname string
+	table    string
+	name     string
+	typ      string
+	message  string
+	gpuUUID  string
+	xid      string
+	actions  string
+}
+
+func newSlackAlert(hostname string, p Payload) slackAlert {
+	a := slackAlert{
+		hostname: hostname,
+		table:    p.Table,
+		name:     p.Event.Name,
+		typ:      string(p.Event.Type),
+		message:  p.Event.Message,
+	}
+	switch p.Event.Name {
+	case nvidia_error_xid.EventNameErroXid:
+		a.gpuUUID = p.Event.ExtraInfo[nvidia_error_xid.EventKeyDeviceUUID]
+		a.xid = p.Event.ExtraInfo[nvidia_error_xid.EventKeyErroXidData]
+	case nvidia_error_sxid.EventNameErroSXid:
+		a.gpuUUID = p.Event.ExtraInfo[nvidia_error_sxid.EventKeyDeviceUUID]
+		a.xid = p.Event.ExtraInfo[nvidia_error_sxid.EventKeyErroSXidData]
+	}
+	if p.Event.SuggestedActions != nil {
+		actions := make([]string, 0, len(p.Event.SuggestedActions.RepairActions))
+		for _, action := range p.Event.SuggestedActions.RepairActions {
+			actions = append(actions, string(action))
+		}
+		a.actions = strings.Join(actions, ", ")
+	}
+	return a
+}
+
+// slackCooldownKey returns the key that identifies the identical alerts,
+// ignoring the event time and the occurrence counts.
+// The Xid events use the same (xid, device uuid) key as the Xid event coalescing.
+func slackCooldownKey(hostname string) func(Payload) string {
+	return func(p Payload) string {
+		if p.Event.Name == nvidia_error_xid.EventNameErroXid {
+			if xid, err := strconv.Atoi(p.Event.ExtraInfo[nvidia_error_xid.EventKeyErroXidData]); err == nil {
+				return nvidia_error_xid.XidEventKey(p.Event.ExtraInfo[nvidia_error_xid.EventKeyDeviceUUID], xid)
+			}
+		}
+		a := newSlackAlert(hostname, p)
+		return strings.Join([]string{a.table, a.name, a.typ, a.message, a.gpuUUID, a.xid, a.actions}, "\x00")
+	}
+}
+
+func newSlackMessage(hostname string, p Payload) SlackMessage {
+	a := newSlackAlert(hostname, p)
+
+	title := fmt.Sprintf("%s: %s on %s", a.typ, a.name, a.hostname)
+	msg := SlackMessage{
+		Text: title,
+		Blocks: []SlackBlock{
+			{
+				Type: "header",
+				Text: &SlackText{Type: "plain_text", Text: title},
+			},
+		},
+	}
+	if a.message != "" {
+		msg.Text += ": " + a.message
+		msg.Blocks = append(msg.Blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: a.message},
+		})
+	}
+
+	fields := []SlackText{
+		{Type: "mrkdwn", Text: "*Host*\n" + a.hostname},
+	}
+	if a.gpuUUID != "" {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: "*GPU UUID*\n" + a.gpuUUID})
+	}
+	if a.xid != "" {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: "*XID*\n" + a.xid})
+	}
+	if a.actions != "" {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: "*Suggested actions*\n" + a.actions})
+	}
+	msg.Blocks = append(msg.Blocks,
+		SlackBlock{Type: "section", Fields: fields},
+		SlackBlock{
+			Type: "context",
+			Elements: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("%s at %s", a.table, p.Event.Time.UTC().Format(time.RFC3339))},
+			},
+		},
+	)
+	return msg
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	nvidia_error_xid "github.com/leptonai/gpud/components/accelerator/nvidia/error/xid"
+	"github.com/leptonai/gpud/components/common"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newXidEvent(xid string, occurrences string) components.Event {
+	return components.Event{
+		Time: metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Name: nvidia_error_xid.EventNameErroXid,
+		Type: common.EventTypeFatal,
+		ExtraInfo: map[string]string{
+			nvidia_error_xid.EventKeyErroXidData:     xid,
+			nvidia_error_xid.EventKeyDeviceUUID:      "GPU-a1b2",
+			nvidia_error_xid.EventKeyOccurrenceCount: occurrences,
+		},
+		SuggestedActions: &common.SuggestedActions{
+			RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem},
+		},
+	}
+}
+
+func TestSlackMessage(t *testing.T) {
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var raw json.RawMessage
+		if err := json.NewDecoder(req.Body).Decode(&raw); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- raw
+	}))
+	defer srv.Close()
+
+	s, err := NewSlack(srv.URL, "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	s.Send("components_accelerator_nvidia_error_xid_events_v0_4_0", newXidEvent("79", "1"))
+
+	var raw []byte
+	select {
+	case raw = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for slack message")
+	}
+
+	expected := `{
+  "text": "Fatal: error_xid on node-1",
+  "blocks": [
+    {"type": "header", "text": {"type": "plain_text", "text": "Fatal: error_xid on node-1"}},
+    {"type": "section", "fields": [
+      {"type": "mrkdwn", "text": "*Host*\nnode-1"},
+      {"type": "mrkdwn", "text": "*GPU UUID*\nGPU-a1b2"},
+      {"type": "mrkdwn", "text": "*XID*\n79"},
+      {"type": "mrkdwn", "text": "*Suggested actions*\nREBOOT_SYSTEM"}
+    ]},
+    {"type": "context", "elements": [
+      {"type": "mrkdwn", "text": "components_accelerator_nvidia_error_xid_events_v0_4_0 at 2024-01-02T03:04:05Z"}
+    ]}
+  ]
+}`
+	var got, want any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	gotB, _ := json.Marshal(got)
+	wantB, _ := json.Marshal(want)
+	if string(gotB) != string(wantB) {
+		t.Errorf("unexpected slack message\nwant: %s\ngot:  %s", wantB, gotB)
+	}
+}
+
+func TestSlackMessageWithEventMessage(t *testing.T) {
+	msg := newSlackMessage("node-1", Payload{
+		Table: "components_memory_events_v0_4_0",
+		Event: components.Event{Name: "memory_oom", Type: common.EventTypeCritical, Message: "oom kill detected"},
+	})
+	if msg.Text != "Critical: memory_oom on node-1: oom kill detected" {
+		t.Errorf("unexpected text %q", msg.Text)
+	}
+	if len(msg.Blocks) != 4 || msg.Blocks[1].Type != "section" || msg.Blocks[1].Text == nil || msg.Blocks[1].Text.Text != "oom kill detected" {
+		t.Fatalf("unexpected blocks %+v", msg.Blocks)
+	}
+	// no GPU UUID, XID, or suggested actions for the non-GPU events
+	if len(msg.Blocks[2].Fields) != 1 {
+		t.Errorf("expected only the host field, got %+v", msg.Blocks[2].Fields)
+	}
+}
+
+func TestSlackCooldown(t *testing.T) {
+	s, err := NewSlack("http://localhost", "node-1", WithCooldown(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	table := "components_accelerator_nvidia_error_xid_events_v0_4_0"
+	s.Send(table, newXidEvent("79", "1"))
+	// identical alert except for the occurrence count is suppressed
+	s.Send(table, newXidEvent("79", "2"))
+	// the same (xid, device uuid) is suppressed, even if the message changes
+	ev := newXidEvent("79", "3")
+	ev.Message = "XID 79 detected 3 times"
+	s.Send(table, ev)
+	// different XID is not suppressed
+	s.Send(table, newXidEvent("48", "1"))
+
+	if len(s.queue) != 2 || s.Suppressed() != 2 {
+		t.Fatalf("expected 2 queued and 2 suppressed, got %d queued and %d suppressed", len(s.queue), s.Suppressed())
+	}
+
+	// after the cooldown, the identical alert is sent again
+	now = now.Add(time.Minute)
+	s.Send(table, newXidEvent("79", "4"))
+	if len(s.queue) != 3 || s.Suppressed() != 2 {
+		t.Fatalf("expected 3 queued and 2 suppressed, got %d queued and %d suppressed", len(s.queue), s.Suppressed())
+	}
+}
+
+func TestCooldownRequiresKey(t *testing.T) {
+	if _, err := New("http://localhost", WithCooldown(time.Minute)); err == nil {
+		t.Fatal("expected error for the cooldown without the key")
+	}
+}
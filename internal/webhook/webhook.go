@@ -0,0 +1,392 @@
+// Package webhook implements the sink that posts the newly generated events
+// to an external webhook (e.g., an alerting system).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+	"github.com/leptonai/gpud/log"
+)
+
+const (
+	// DefaultMinEventType is the default minimum event type to post.
+	DefaultMinEventType = common.EventTypeCritical
+
+	// DefaultQueueSize is the default maximum number of the events
+	// waiting to be posted, dropping the oldest events first.
+	DefaultQueueSize = 1000
+
+	// DefaultMaxRetries is the default number of retries per event
+	// before dropping the event.
+	DefaultMaxRetries = 5
+
+	// DefaultInitialBackoff is the default wait before the first retry,
+	// doubled on every retry up to DefaultMaxBackoff.
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+
+	// DefaultRequestTimeout is the default timeout of each webhook request.
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+type Op struct {
+	headers        map[string]string
+	minEventType   common.EventType
+	queueSize      int
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	httpClient     *http.Client
+	encode         func(Payload) ([]byte, error)
+	cooldown       time.Duration
+	cooldownKey    func(Payload) string
+	quietHours     []common.TimeWindow
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) error {
+	op.maxRetries = -1
+
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	switch op.minEventType {
+	case "":
+		op.minEventType = DefaultMinEventType
+	case common.EventTypeInfo, common.EventTypeWarning, common.EventTypeCritical, common.EventTypeFatal:
+	default:
+		return fmt.Errorf("invalid min event type %q", op.minEventType)
+	}
+	if op.queueSize <= 0 {
+		op.queueSize = DefaultQueueSize
+	}
+	if op.maxRetries < 0 {
+		op.maxRetries = DefaultMaxRetries
+	}
+	if op.initialBackoff <= 0 {
+		op.initialBackoff = DefaultInitialBackoff
+	}
+	if op.maxBackoff <= 0 {
+		op.maxBackoff = DefaultMaxBackoff
+	}
+	if op.maxBackoff < op.initialBackoff {
+		op.maxBackoff = op.initialBackoff
+	}
+	if op.httpClient == nil {
+		op.httpClient = &http.Client{Timeout: DefaultRequestTimeout}
+	}
+	if op.encode == nil {
+		op.encode = func(p Payload) ([]byte, error) { return json.Marshal(p) }
+	}
+	if op.cooldown > 0 && op.cooldownKey == nil {
+		return errors.New("cooldown requires the key function")
+	}
+	for _, w := range op.quietHours {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid quiet hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// WithHeaders sets the headers of the webhook requests (e.g., "Authorization").
+func WithHeaders(headers map[string]string) OpOption {
+	return func(op *Op) {
+		op.headers = headers
+	}
+}
+
+// WithMinEventType sets the minimum event type to post
+// (e.g., "Fatal" to skip the critical events).
+func WithMinEventType(t common.EventType) OpOption {
+	return func(op *Op) {
+		op.minEventType = t
+	}
+}
+
+// WithQueueSize sets the maximum number of the events waiting to be posted.
+func WithQueueSize(size int) OpOption {
+	return func(op *Op) {
+		op.queueSize = size
+	}
+}
+
+// WithMaxRetries sets the number of retries per event (zero to not retry).
+func WithMaxRetries(retries int) OpOption {
+	return func(op *Op) {
+		op.maxRetries = retries
+	}
+}
+
+// WithBackoff sets the initial and maximum wait between the retries.
+func WithBackoff(initial, maxBackoff time.Duration) OpOption {
+	return func(op *Op) {
+		op.initialBackoff = initial
+		op.maxBackoff = maxBackoff
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the webhook requests.
+func WithHTTPClient(cli *http.Client) OpOption {
+	return func(op *Op) {
+		op.httpClient = cli
+	}
+}
+
+// WithEncoder sets the function that encodes the request body of each event
+// (e.g., into a Slack message). Defaults to the JSON-encoded Payload.
+func WithEncoder(encode func(Payload) ([]byte, error)) OpOption {
+	return func(op *Op) {
+		op.encode = encode
+	}
+}
+
+// WithCooldown suppresses the events with the same key (see WithCooldownKey)
+// as an event queued within the cooldown period (e.g., the repeated identical alerts).
+func WithCooldown(cooldown time.Duration) OpOption {
+	return func(op *Op) {
+		op.cooldown = cooldown
+	}
+}
+
+// WithCooldownKey sets the function that returns the key of the identical events.
+func WithCooldownKey(key func(Payload) string) OpOption {
+	return func(op *Op) {
+		op.cooldownKey = key
+	}
+}
+
+// WithQuietHours suppresses the non-urgent (ticket-class) events
+// within the daily time windows, while the page-class events are still posted.
+func WithQuietHours(windows ...common.TimeWindow) OpOption {
+	return func(op *Op) {
+		op.quietHours = append(op.quietHours, windows...)
+	}
+}
+
+// Payload is the JSON body of each webhook request.
+type Payload struct {
+	// Table is the event store table of the component
+	// that generated the event.
+	Table string           `json:"table"`
+	Event components.Event `json:"event"`
+}
+
+// Sender posts the events to the webhook in the background.
+// It implements the event store sink.
+type Sender struct {
+	url string
+	op  Op
+
+	mu         sync.Mutex
+	queue      []Payload
+	dropped    int
+	lastQueued map[string]time.Time
+	suppressed int
+
+	now    func() time.Time
+	notify chan struct{}
+}
+
+// New creates a sender that posts to the webhook URL.
+// Call Start to begin posting the queued events.
+func New(webhookURL string, opts ...OpOption) (*Sender, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook url scheme %q", u.Scheme)
+	}
+
+	op := Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return nil, err
+	}
+	return &Sender{
+		url:        webhookURL,
+		op:         op,
+		lastQueued: make(map[string]time.Time),
+		now:        time.Now,
+		notify:     make(chan struct{}, 1),
+	}, nil
+}
+
+// Send queues the event to post, if its type is at least the minimum event type
+// and it is not suppressed by the quiet hours or the cooldown.
+// It never blocks, and drops the oldest queued event if the queue is full.
+func (s *Sender) Send(table string, ev components.Event) {
+	if ev.Type.Severity() < s.op.minEventType.Severity() {
+		return
+	}
+	if len(s.op.quietHours) > 0 {
+		decision := common.ApplyQuietHours(common.GetAlertClass(ev.Type, ev.SuggestedActions), s.op.quietHours, s.now())
+		if decision.Suppressed {
+			s.mu.Lock()
+			s.suppressed++
+			s.mu.Unlock()
+			log.Logger.Debugw("webhook event suppressed in quiet hours", "table", table, "event", ev.Name)
+			return
+		}
+	}
+	p := Payload{Table: table, Event: ev}
+
+	s.mu.Lock()
+	if s.op.cooldown > 0 && s.inCooldown(p) {
+		s.suppressed++
+		s.mu.Unlock()
+		log.Logger.Debugw("webhook event suppressed in cooldown", "table", table, "event", ev.Name)
+		return
+	}
+	if len(s.queue) >= s.op.queueSize {
+		s.queue = s.queue[1:]
+		s.dropped++
+		log.Logger.Warnw("webhook queue full, dropped the oldest event", "dropped", s.dropped)
+	}
+	s.queue = append(s.queue, p)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the number of the events dropped from the full queue.
+func (s *Sender) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// inCooldown returns true if an event with the same key was queued
+// within the cooldown, otherwise records the event. Requires the lock held.
+func (s *Sender) inCooldown(p Payload) bool {
+	now := s.now()
+	key := s.op.cooldownKey(p)
+	if last, ok := s.lastQueued[key]; ok && now.Sub(last) < s.op.cooldown {
+		return true
+	}
+	s.lastQueued[key] = now
+
+	// bound the memory by evicting the expired keys
+	if len(s.lastQueued) > s.op.queueSize {
+		for k, last := range s.lastQueued {
+			if now.Sub(last) >= s.op.cooldown {
+				delete(s.lastQueued, k)
+			}
+		}
+	}
+	return false
+}
+
+// Suppressed returns the number of the events suppressed by the quiet hours or the cooldown.
+func (s *Sender) Suppressed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed
+}
+
+// Start posts the queued events until the context is canceled.
+func (s *Sender) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *Sender) run(ctx context.Context) {
+	for {
+		p, ok := s.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.notify:
+			}
+			continue
+		}
+
+		if err := s.post(ctx, p); err != nil {
+			log.Logger.Warnw("failed to post event to webhook", "table", p.Table, "event", p.Event.Name, "error", err)
+		}
+	}
+}
+
+func (s *Sender) pop() (Payload, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return Payload{}, false
+	}
+	p := s.queue[0]
+	s.queue = s.queue[1:]
+	return p, true
+}
+
+var errNotRetryable = errors.New("not retryable")
+
+// post posts the payload, retrying with the exponential backoff
+// on the request errors and the retryable status codes.
+func (s *Sender) post(ctx context.Context, p Payload) error {
+	body, err := s.op.encode(p)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.op.initialBackoff
+	for attempt := 0; ; attempt++ {
+		err = s.postOnce(ctx, body)
+		if err == nil || errors.Is(err, errNotRetryable) || attempt >= s.op.maxRetries {
+			return err
+		}
+
+		log.Logger.Debugw("retrying webhook request", "attempt", attempt+1, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > s.op.maxBackoff {
+			backoff = s.op.maxBackoff
+		}
+	}
+}
+
+func (s *Sender) postOnce(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errNotRetryable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.op.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.op.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: unexpected status code %d", errNotRetryable, resp.StatusCode)
+	}
+}
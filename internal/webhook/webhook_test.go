@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/components"
+	"github.com/leptonai/gpud/components/common"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	payloads []Payload
+	headers  []http.Header
+	received chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{received: make(chan struct{}, 100)}
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var p Payload
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.payloads = append(r.payloads, p)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.mu.Unlock()
+	r.received <- struct{}{}
+}
+
+func (r *recorder) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for webhook request %d", i+1)
+		}
+	}
+}
+
+func TestSenderPayload(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s, err := New(srv.URL, WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	now := metav1.NewTime(time.Unix(1700000000, 0).UTC())
+	ev := components.Event{
+		Time:      now,
+		Name:      "error_xid",
+		Type:      common.EventTypeFatal,
+		Message:   "XID 79 detected",
+		ExtraInfo: map[string]string{"xid": "79"},
+		SuggestedActions: &common.SuggestedActions{
+			RepairActions: []common.RepairActionType{common.RepairActionTypeRebootSystem},
+		},
+	}
+	s.Send("components_accelerator_nvidia_error_xid_events_v0_4_0", ev)
+	rec.wait(t, 1)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.payloads) != 1 {
+		t.Fatalf("expected 1 payload, got %d", len(rec.payloads))
+	}
+	p := rec.payloads[0]
+	if p.Table != "components_accelerator_nvidia_error_xid_events_v0_4_0" {
+		t.Errorf("unexpected table %q", p.Table)
+	}
+	if !p.Event.Time.Equal(&now) || p.Event.Name != ev.Name || p.Event.Type != ev.Type || p.Event.Message != ev.Message || p.Event.ExtraInfo["xid"] != "79" {
+		t.Errorf("unexpected event %+v", p.Event)
+	}
+	if p.Event.SuggestedActions == nil || len(p.Event.SuggestedActions.RepairActions) != 1 {
+		t.Errorf("unexpected suggested actions %+v", p.Event.SuggestedActions)
+	}
+	if got := rec.headers[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected Authorization header, got %q", got)
+	}
+	if got := rec.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected json content type, got %q", got)
+	}
+}
+
+func TestSenderMinEventType(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s, err := New(srv.URL, WithMinEventType(common.EventTypeFatal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	for _, typ := range []common.EventType{
+		common.EventTypeInfo,
+		common.EventTypeWarning,
+		common.EventTypeCritical,
+		common.EventTypeFatal,
+	} {
+		s.Send("test", components.Event{Name: string(typ), Type: typ})
+	}
+	rec.wait(t, 1)
+
+	// give the sender the chance to post any unexpected event
+	time.Sleep(100 * time.Millisecond)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.payloads) != 1 || rec.payloads[0].Event.Type != common.EventTypeFatal {
+		t.Errorf("expected only the fatal event, got %+v", rec.payloads)
+	}
+}
+
+func TestSenderDefaultMinEventType(t *testing.T) {
+	s, err := New("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []common.EventType{
+		common.EventTypeInfo,
+		common.EventTypeWarning,
+		common.EventTypeCritical,
+		common.EventTypeFatal,
+	} {
+		s.Send("test", components.Event{Type: typ})
+	}
+	if len(s.queue) != 2 || s.queue[0].Event.Type != common.EventTypeCritical || s.queue[1].Event.Type != common.EventTypeFatal {
+		t.Errorf("expected only the critical and fatal events queued, got %+v", s.queue)
+	}
+}
+
+func TestSenderQuietHours(t *testing.T) {
+	s, err := New("http://localhost", WithQuietHours(common.TimeWindow{Start: "22:00", End: "06:00"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 23, 0, 0, 0, time.Local) }
+
+	// the ticket-class event is suppressed, while the fatal event still pages
+	s.Send("test", components.Event{Name: "critical", Type: common.EventTypeCritical})
+	s.Send("test", components.Event{Name: "fatal", Type: common.EventTypeFatal})
+	if len(s.queue) != 1 || s.queue[0].Event.Name != "fatal" {
+		t.Errorf("expected only the fatal event queued, got %+v", s.queue)
+	}
+	if s.Suppressed() != 1 {
+		t.Errorf("expected 1 suppressed event, got %d", s.Suppressed())
+	}
+
+	// outside the quiet hours, the ticket-class event is queued
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local) }
+	s.Send("test", components.Event{Name: "critical", Type: common.EventTypeCritical})
+	if len(s.queue) != 2 || s.queue[1].Event.Name != "critical" {
+		t.Errorf("expected the critical event queued, got %+v", s.queue)
+	}
+
+	if _, err := New("http://localhost", WithQuietHours(common.TimeWindow{Start: "25:00", End: "06:00"})); err == nil {
+		t.Error("expected error for the invalid quiet hours")
+	}
+}
+
+func TestSenderQueueDropsOldest(t *testing.T) {
+	s, err := New("http://localhost", WithQueueSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		s.Send("test", components.Event{Name: name, Type: common.EventTypeCritical})
+	}
+	if s.Dropped() != 1 {
+		t.Errorf("expected 1 dropped event, got %d", s.Dropped())
+	}
+	if len(s.queue) != 2 || s.queue[0].Event.Name != "b" || s.queue[1].Event.Name != "c" {
+		t.Errorf("expected the oldest event dropped, got %+v", s.queue)
+	}
+}
+
+func TestSenderRetry(t *testing.T) {
+	var attempts atomic.Int32
+	rec := newRecorder()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rec.ServeHTTP(w, req)
+	}))
+	defer srv.Close()
+
+	s, err := New(srv.URL, WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	s.Send("test", components.Event{Name: "retried", Type: common.EventTypeCritical})
+	rec.wait(t, 1)
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestSenderNoRetryOnClientError(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s, err := New(srv.URL, WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.post(context.Background(), Payload{Table: "test"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New("ftp://localhost"); err == nil {
+		t.Error("expected error for the invalid scheme")
+	}
+	if _, err := New("http://localhost", WithMinEventType("Severe")); err == nil {
+		t.Error("expected error for the invalid min event type")
+	}
+}
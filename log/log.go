@@ -3,7 +3,9 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -18,6 +20,24 @@ func init() {
 	Logger = CreateLogger(DefaultLoggerConfig())
 }
 
+// Format is the log output format.
+type Format string
+
+const (
+	FormatConsole Format = "console"
+	FormatJSON    Format = "json"
+)
+
+// ParseFormat parses the log format (e.g., "console", "json").
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatConsole, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q", s)
+	}
+}
+
 func DefaultLoggerConfig() *zap.Config {
 	c := zap.NewProductionConfig()
 	c.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC3339)
@@ -28,19 +48,83 @@ func CreateLogger(config *zap.Config) *LeptonLogger {
 	if config == nil {
 		config = DefaultLoggerConfig()
 	}
+	format, err := ParseFormat(config.Encoding)
+	if err != nil {
+		panic(err)
+	}
 
-	l, err := config.Build()
+	// build the logger per format with the shared level,
+	// so that the format can be switched at runtime
+	cfg := *config
+	cfg.Encoding = string(FormatJSON)
+	jsonLogger, err := cfg.Build()
 	if err != nil {
 		panic(err)
 	}
+	cfg.Encoding = string(FormatConsole)
+	consoleLogger, err := cfg.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	core := &formatCore{
+		format:  new(atomic.Value),
+		json:    jsonLogger.Core(),
+		console: consoleLogger.Core(),
+	}
+	core.format.Store(format)
 
+	l := jsonLogger.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return core
+	}))
 	return &LeptonLogger{
-		l.Sugar(),
+		SugaredLogger: l.Sugar(),
+		level:         config.Level,
+		format:        core.format,
 	}
 }
 
 type LeptonLogger struct {
 	*zap.SugaredLogger
+
+	level  zap.AtomicLevel
+	format *atomic.Value
+}
+
+// Level returns the current minimum enabled log level.
+func (l *LeptonLogger) Level() zapcore.Level {
+	return l.level.Level()
+}
+
+// SetLevel changes the minimum enabled log level at runtime.
+// Safe to call concurrently with the logging.
+func (l *LeptonLogger) SetLevel(lvl zapcore.Level) {
+	l.level.SetLevel(lvl)
+}
+
+// Format returns the current log output format.
+func (l *LeptonLogger) Format() Format {
+	return l.format.Load().(Format)
+}
+
+// SetFormat changes the log output format at runtime.
+// Safe to call concurrently with the logging.
+func (l *LeptonLogger) SetFormat(f Format) error {
+	if _, err := ParseFormat(string(f)); err != nil {
+		return err
+	}
+	l.format.Store(f)
+	return nil
+}
+
+// SetLevel changes the minimum enabled log level of the default logger.
+func SetLevel(lvl zapcore.Level) {
+	Logger.SetLevel(lvl)
+}
+
+// SetFormat changes the log output format of the default logger.
+func SetFormat(f Format) error {
+	return Logger.SetFormat(f)
 }
 
 // Override the default logger's Errorw func to down level context canceled error
@@ -64,3 +148,74 @@ func (l *LeptonLogger) Errorw(msg string, keysAndValues ...interface{}) {
 func (l *LeptonLogger) Printf(format string, v ...any) {
 	l.SugaredLogger.Infof(format, v...)
 }
+
+// formatPanicMarker is appended to the raw format string
+// when formatting the log message panicked.
+const formatPanicMarker = "[log format panic]"
+
+// Safelogf logs the formatted message at the info level with the default logger.
+// See LeptonLogger.Safelogf.
+func Safelogf(format string, v ...any) {
+	Logger.Safelogf(format, v...)
+}
+
+// Safelogf logs the formatted message at the info level, recovering from the panics
+// while formatting the arguments (e.g., a string with the nil data pointer).
+// On panic, the raw format string is logged with a marker instead of crashing the daemon.
+func (l *LeptonLogger) Safelogf(format string, v ...any) {
+	msg, perr := safeSprintf(format, v...)
+	if perr != nil {
+		l.SugaredLogger.Warnw(format+" "+formatPanicMarker, "panic", fmt.Sprint(perr))
+		return
+	}
+	l.SugaredLogger.Info(msg)
+}
+
+func safeSprintf(format string, v ...any) (msg string, perr any) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr = r
+		}
+	}()
+	return fmt.Sprintf(format, v...), nil
+}
+
+// formatCore writes the log entries with the core of the current format.
+type formatCore struct {
+	format  *atomic.Value
+	json    zapcore.Core
+	console zapcore.Core
+}
+
+var _ zapcore.Core = (*formatCore)(nil)
+
+func (c *formatCore) current() zapcore.Core {
+	if c.format.Load().(Format) == FormatConsole {
+		return c.console
+	}
+	return c.json
+}
+
+func (c *formatCore) Enabled(lvl zapcore.Level) bool {
+	return c.current().Enabled(lvl)
+}
+
+func (c *formatCore) With(fields []zapcore.Field) zapcore.Core {
+	return &formatCore{
+		format:  c.format,
+		json:    c.json.With(fields),
+		console: c.console.With(fields),
+	}
+}
+
+func (c *formatCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.current().Check(ent, ce)
+}
+
+func (c *formatCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(ent, fields)
+}
+
+func (c *formatCore) Sync() error {
+	return c.current().Sync()
+}
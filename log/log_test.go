@@ -0,0 +1,167 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func createTestLogger(t *testing.T) (*LeptonLogger, string) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "gpud.log")
+	cfg := DefaultLoggerConfig()
+	cfg.OutputPaths = []string{file}
+	cfg.Sampling = nil
+	return CreateLogger(cfg), file
+}
+
+func readLines(t *testing.T, file string) []string {
+	t.Helper()
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestSetFormat(t *testing.T) {
+	l, file := createTestLogger(t)
+	if l.Format() != FormatJSON {
+		t.Fatalf("expected json format, got %q", l.Format())
+	}
+
+	l.Infow("json message", "key", "value")
+	if err := l.SetFormat(FormatConsole); err != nil {
+		t.Fatal(err)
+	}
+	l.Infow("console message", "key", "value")
+	if err := l.SetFormat("invalid"); err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+	if l.Format() != FormatConsole {
+		t.Fatalf("expected console format, got %q", l.Format())
+	}
+	_ = l.Sync()
+
+	lines := readLines(t, file)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatalf("expected json line, got %q (%v)", lines[0], err)
+	}
+	if m["msg"] != "json message" || m["key"] != "value" {
+		t.Errorf("unexpected json line %q", lines[0])
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &m); err == nil {
+		t.Errorf("expected console line, got %q", lines[1])
+	}
+	if !strings.Contains(lines[1], "console message") || !strings.Contains(lines[1], "info") {
+		t.Errorf("unexpected console line %q", lines[1])
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	l, file := createTestLogger(t)
+
+	l.Debug("debug before")
+	l.SetLevel(zapcore.DebugLevel)
+	l.Debug("debug after")
+	l.SetLevel(zapcore.WarnLevel)
+	l.Info("info filtered")
+	l.Warn("warn logged")
+	if l.Level() != zapcore.WarnLevel {
+		t.Fatalf("expected warn level, got %v", l.Level())
+	}
+	_ = l.Sync()
+
+	lines := readLines(t, file)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+	if !strings.Contains(lines[0], "debug after") || !strings.Contains(lines[1], "warn logged") {
+		t.Errorf("unexpected lines %q", lines)
+	}
+}
+
+func TestSetFormatConcurrent(t *testing.T) {
+	l, file := createTestLogger(t)
+	l = &LeptonLogger{SugaredLogger: l.With("worker", true), level: l.level, format: l.format}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infow("message", "index", j)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		f := FormatJSON
+		if i%2 == 0 {
+			f = FormatConsole
+		}
+		if err := l.SetFormat(f); err != nil {
+			t.Fatal(err)
+		}
+		l.SetLevel(zapcore.InfoLevel)
+	}
+	wg.Wait()
+	_ = l.Sync()
+
+	lines := readLines(t, file)
+	if len(lines) != 400 {
+		t.Fatalf("expected 400 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "worker") {
+			t.Fatalf("expected the fields to be kept, got %q", line)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"json", "JSON", " console "} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) unexpected error %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("text"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestSafelogf(t *testing.T) {
+	l, file := createTestLogger(t)
+
+	// string with the nil data pointer but non-zero length
+	// (e.g., read while being written by another goroutine)
+	var torn string
+	(*[2]uintptr)(unsafe.Pointer(&torn))[1] = 5
+
+	l.Safelogf("version is %s", torn)
+	l.Safelogf("version is %s", "1.2.3")
+	_ = l.Sync()
+
+	lines := readLines(t, file)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+	if !strings.Contains(lines[0], "version is %s "+formatPanicMarker) {
+		t.Errorf("expected the raw format with the marker, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "version is 1.2.3") {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// nowFunc is the clock of the log sampler, overwritten in tests.
+var nowFunc = time.Now
+
+var (
+	samplersMu sync.Mutex
+	samplers   = make(map[string]*sampler)
+)
+
+// sampler tracks the last logged time and the number of suppressed logs of a key.
+type sampler struct {
+	last       time.Time
+	suppressed int
+}
+
+// SampledLogger logs the messages with the same key at most once per interval,
+// to not flood the logs with the repetitive warnings (e.g., a failing command polled every few seconds).
+// The next logged message is suffixed with the number of messages suppressed in between.
+type SampledLogger struct {
+	l     *LeptonLogger
+	key   string
+	every time.Duration
+}
+
+// Sampled returns the sampled logger of the default logger for the key.
+// See LeptonLogger.Sampled.
+func Sampled(key string, every time.Duration) *SampledLogger {
+	return Logger.Sampled(key, every)
+}
+
+// Sampled returns the logger that logs the messages of the key
+// at most once per "every" interval. The key is shared across the callers,
+// so use the same key for the identical warnings (e.g., "disk-no-block-device").
+func (l *LeptonLogger) Sampled(key string, every time.Duration) *SampledLogger {
+	return &SampledLogger{l: l, key: key, every: every}
+}
+
+// allow returns the message to log with the suppressed count suffix,
+// and false if the message should be suppressed.
+func (s *SampledLogger) allow(msg string) (string, bool) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+
+	now := nowFunc()
+	sp, ok := samplers[s.key]
+	if !ok {
+		samplers[s.key] = &sampler{last: now}
+		return msg, true
+	}
+	if now.Sub(sp.last) < s.every {
+		sp.suppressed++
+		return "", false
+	}
+
+	if sp.suppressed > 0 {
+		msg = fmt.Sprintf("%s (suppressed %d similar messages)", msg, sp.suppressed)
+	}
+	sp.last = now
+	sp.suppressed = 0
+	return msg, true
+}
+
+func (s *SampledLogger) Infow(msg string, keysAndValues ...interface{}) {
+	if m, ok := s.allow(msg); ok {
+		s.l.Infow(m, keysAndValues...)
+	}
+}
+
+func (s *SampledLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	if m, ok := s.allow(msg); ok {
+		s.l.Warnw(m, keysAndValues...)
+	}
+}
+
+func (s *SampledLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	if m, ok := s.allow(msg); ok {
+		s.l.Errorw(m, keysAndValues...)
+	}
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampled(t *testing.T) {
+	l, file := createTestLogger(t)
+
+	now := time.Now()
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = time.Now })
+
+	for i := 0; i < 100; i++ {
+		l.Sampled("test-sampled", time.Minute).Warnw("no block device found", "index", i)
+	}
+	_ = l.Sync()
+
+	lines := readLines(t, file)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line within the interval, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "no block device found") || strings.Contains(lines[0], "suppressed") {
+		t.Errorf("unexpected first line %q", lines[0])
+	}
+
+	// the other key is not suppressed
+	l.Sampled("test-sampled-other", time.Minute).Warnw("other warning")
+
+	// next interval carries the suppressed count
+	now = now.Add(time.Minute)
+	for i := 0; i < 100; i++ {
+		l.Sampled("test-sampled", time.Minute).Warnw("no block device found", "index", i)
+	}
+	_ = l.Sync()
+
+	lines = readLines(t, file)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	if !strings.Contains(lines[1], "other warning") {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "no block device found (suppressed 99 similar messages)") {
+		t.Errorf("expected the suppressed count, got %q", lines[2])
+	}
+}
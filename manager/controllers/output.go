@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+	"github.com/leptonai/gpud/pkg/sqlite"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const TableNameProcessOutputs = "gpud_manager_process_outputs"
+
+const (
+	// process id in the format of "<package name>/<command>" (e.g., "nvidia-driver/install")
+	ColumnID = "id"
+
+	// unix timestamp in seconds when the process exited
+	ColumnUnixSeconds = "unix_seconds"
+
+	// process exit error, empty if the process exited successfully
+	ColumnError = "error"
+
+	// combined stdout and stderr of the process
+	ColumnOutput = "output"
+)
+
+// DefaultMaxStoredOutputBytes is the default maximum number of bytes
+// of the process output to persist.
+const DefaultMaxStoredOutputBytes = 64 * 1024
+
+// ProcessID returns the ID of the process output for the package command.
+func ProcessID(packageName string, command string) string {
+	return packageName + "/" + command
+}
+
+func CreateTableProcessOutputs(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+	%s TEXT NOT NULL PRIMARY KEY,
+	%s INTEGER NOT NULL,
+	%s TEXT,
+	%s TEXT
+);`, TableNameProcessOutputs,
+		ColumnID,
+		ColumnUnixSeconds,
+		ColumnError,
+		ColumnOutput,
+	))
+	return err
+}
+
+// InsertOutput persists the process output, overwriting the previous one with the same ID.
+// Only the last "maxBytes" bytes are kept if the output exceeds the limit,
+// since the end of the output is most useful to debug the failure.
+func InsertOutput(ctx context.Context, db *sql.DB, id string, unixSeconds int64, processErr error, output string, maxBytes int) error {
+	if maxBytes > 0 && len(output) > maxBytes {
+		output = output[len(output)-maxBytes:]
+	}
+	errMsg := ""
+	if processErr != nil {
+		errMsg = processErr.Error()
+	}
+
+	insertStatement := fmt.Sprintf(`
+INSERT OR REPLACE INTO %s (%s, %s, %s, %s) VALUES (?, ?, NULLIF(?, ''), ?);
+`,
+		TableNameProcessOutputs,
+		ColumnID,
+		ColumnUnixSeconds,
+		ColumnError,
+		ColumnOutput,
+	)
+
+	start := time.Now()
+	_, err := db.ExecContext(ctx, insertStatement, id, unixSeconds, errMsg, output)
+	sqlite.RecordInsertUpdate(time.Since(start).Seconds())
+
+	return err
+}
+
+// ReadOutput returns the persisted process output.
+// Returns errdefs.ErrNotFound if no output is found for the ID.
+func ReadOutput(ctx context.Context, db *sql.DB, id string) (string, error) {
+	query := fmt.Sprintf(`
+SELECT %s FROM %s WHERE %s = ?;
+`,
+		ColumnOutput,
+		TableNameProcessOutputs,
+		ColumnID,
+	)
+
+	start := time.Now()
+	var output sql.NullString
+	err := db.QueryRowContext(ctx, query, id).Scan(&output)
+	sqlite.RecordSelect(time.Since(start).Seconds())
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("output %q not found: %w", id, errdefs.ErrNotFound)
+		}
+		return "", err
+	}
+	return output.String, nil
+}
+
+// PruneOutputs deletes the process outputs persisted before the unix timestamp in seconds,
+// except the ones with the excluded IDs (e.g., processes still running).
+// Returns the number of deleted rows.
+func PruneOutputs(ctx context.Context, db *sql.DB, beforeUnixSeconds int64, excludeIDs []string) (int, error) {
+	deleteStatement := fmt.Sprintf(`DELETE FROM %s WHERE %s < ?`,
+		TableNameProcessOutputs,
+		ColumnUnixSeconds,
+	)
+	args := []any{beforeUnixSeconds}
+	if len(excludeIDs) > 0 {
+		deleteStatement += fmt.Sprintf(` AND %s NOT IN (?%s)`,
+			ColumnID,
+			strings.Repeat(", ?", len(excludeIDs)-1),
+		)
+		for _, id := range excludeIDs {
+			args = append(args, id)
+		}
+	}
+
+	start := time.Now()
+	rs, err := db.ExecContext(ctx, deleteStatement, args...)
+	if err != nil {
+		return 0, err
+	}
+	sqlite.RecordDelete(time.Since(start).Seconds())
+
+	affected, err := rs.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
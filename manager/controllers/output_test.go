@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func writeScript(t *testing.T, contents string) string {
+	t.Helper()
+
+	script := filepath.Join(t.TempDir(), "init.sh")
+	if err := os.WriteFile(script, []byte(contents), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return script
+}
+
+func TestRunCommandStoreOutput(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableProcessOutputs(ctx, dbRW); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	script := writeScript(t, `#!/bin/bash
+echo "hello $1"
+echo "world $1" >&2
+`)
+	c := NewPackageController(nil, dbRW, DefaultMaxStoredOutputBytes)
+	if err := c.runCommand(ctx, "test-pkg", script, "install", nil); err != nil {
+		t.Fatalf("failed to run command: %v", err)
+	}
+
+	output, err := ReadOutput(ctx, dbRO, ProcessID("test-pkg", "install"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(output, "hello install") || !strings.Contains(output, "world install") {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	if _, err := ReadOutput(ctx, dbRO, ProcessID("test-pkg", "delete")); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRunCommandStoreOutputTruncated(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableProcessOutputs(ctx, dbRW); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	script := writeScript(t, `#!/bin/bash
+echo "aaaaaaaaaa"
+echo "bbbbbbbbbb"
+exit 1
+`)
+	c := NewPackageController(nil, dbRW, 11)
+	if err := c.runCommand(ctx, "test-pkg", script, "install", nil); err == nil {
+		t.Fatal("expected error from the failed command")
+	}
+
+	output, err := ReadOutput(ctx, dbRO, ProcessID("test-pkg", "install"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if output != "bbbbbbbbbb\n" {
+		t.Errorf("expected the last 11 bytes to be kept, got %q", output)
+	}
+}
+
+func TestRunCommandWithoutDB(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	script := writeScript(t, `#!/bin/bash
+echo "hello"
+`)
+	c := NewPackageController(nil, nil, DefaultMaxStoredOutputBytes)
+	if err := c.runCommand(ctx, "test-pkg", script, "install", nil); err != nil {
+		t.Fatalf("failed to run command: %v", err)
+	}
+}
+
+func TestRunCommandResult(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// keeps running after the output, to not race the output reader with the pipe close
+	script := writeScript(t, `#!/bin/bash
+echo "1.2.3"
+sleep 0.2
+`)
+	c := NewPackageController(nil, nil, DefaultMaxStoredOutputBytes)
+	for i := 0; i < 3; i++ {
+		var version string
+		if err := c.runCommand(ctx, "test-pkg", script, "version", &version); err != nil {
+			t.Fatalf("failed to run command: %v", err)
+		}
+		// must be set once the command returns
+		if version != "1.2.3" {
+			t.Fatalf("expected version 1.2.3, got %q", version)
+		}
+	}
+}
+
+func TestPruneOutputs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	if err := CreateTableProcessOutputs(ctx, dbRW); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	now := time.Now().UTC()
+	old := now.Add(-2 * time.Hour).Unix()
+	rows := []struct {
+		id          string
+		unixSeconds int64
+		err         error
+	}{
+		{id: ProcessID("pkg-a", "install"), unixSeconds: old},
+		{id: ProcessID("pkg-b", "install"), unixSeconds: old, err: errors.New("exit status 1")},
+		// still running with the output from the previous run
+		{id: ProcessID("pkg-c", "install"), unixSeconds: old},
+		{id: ProcessID("pkg-d", "install"), unixSeconds: now.Unix()},
+	}
+	for _, r := range rows {
+		if err := InsertOutput(ctx, dbRW, r.id, r.unixSeconds, r.err, "hello\n", DefaultMaxStoredOutputBytes); err != nil {
+			t.Fatalf("failed to insert output: %v", err)
+		}
+	}
+
+	c := NewPackageController(nil, dbRW, DefaultMaxStoredOutputBytes)
+	c.markRunning(ProcessID("pkg-c", "install"))
+
+	deleted, err := c.PruneOutputs(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to prune outputs: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 deleted outputs, got %d", deleted)
+	}
+
+	for _, id := range []string{ProcessID("pkg-a", "install"), ProcessID("pkg-b", "install")} {
+		if _, err := ReadOutput(ctx, dbRO, id); !errors.Is(err, errdefs.ErrNotFound) {
+			t.Errorf("expected %q to be pruned, got %v", id, err)
+		}
+	}
+	for _, id := range []string{ProcessID("pkg-c", "install"), ProcessID("pkg-d", "install")} {
+		if _, err := ReadOutput(ctx, dbRO, id); err != nil {
+			t.Errorf("expected %q to be kept, got %v", id, err)
+		}
+	}
+
+	// pruned once the process is finished
+	c.unmarkRunning(ProcessID("pkg-c", "install"))
+	if ids := c.RunningProcessIDs(); len(ids) != 0 {
+		t.Errorf("expected no running process, got %v", ids)
+	}
+	deleted, err = c.PruneOutputs(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to prune outputs: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 deleted output, got %d", deleted)
+	}
+}
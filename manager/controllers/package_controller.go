@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,13 +21,27 @@ type PackageController struct {
 	packageStatus map[string]*packages.PackageStatus
 	syncPeriod    time.Duration
 	sync.RWMutex
+
+	// optional database to persist the process outputs
+	dbRW                 *sql.DB
+	maxStoredOutputBytes int
+
+	// number of running processes per process id
+	runningMu sync.Mutex
+	running   map[string]int
 }
 
-func NewPackageController(watcher chan packages.PackageInfo) *PackageController {
+// NewPackageController creates a new package controller.
+// If dbRW is not nil, the outputs of the package commands are persisted
+// up to maxStoredOutputBytes (the table must be created beforehand).
+func NewPackageController(watcher chan packages.PackageInfo, dbRW *sql.DB, maxStoredOutputBytes int) *PackageController {
 	r := &PackageController{
-		fileWatcher:   watcher,
-		packageStatus: make(map[string]*packages.PackageStatus),
-		syncPeriod:    3 * time.Second,
+		fileWatcher:          watcher,
+		packageStatus:        make(map[string]*packages.PackageStatus),
+		syncPeriod:           3 * time.Second,
+		dbRW:                 dbRW,
+		maxStoredOutputBytes: maxStoredOutputBytes,
+		running:              make(map[string]int),
 	}
 	return r
 }
@@ -97,12 +112,12 @@ func (c *PackageController) updateRunner(ctx context.Context) {
 				continue
 			}
 			var version string
-			err := runCommand(ctx, pkg.ScriptPath, "version", &version)
+			err := c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "version", &version)
 			if err != nil || version == "" {
 				log.Logger.Errorf("[package controller]: %v unexpected version failure: %v, version: %s", pkg.Name, err, version)
 				continue
 			}
-			log.Logger.Infof("[package controller]: %v version is %v, target is %v", pkg.Name, version, pkg.TargetVersion)
+			log.Safelogf("[package controller]: %v version is %v, target is %v", pkg.Name, version, pkg.TargetVersion)
 			c.Lock()
 			c.packageStatus[pkg.Name].CurrentVersion = version
 			c.Unlock()
@@ -135,7 +150,7 @@ func (c *PackageController) updateRunner(ctx context.Context) {
 					}
 				}
 			}()
-			err = runCommand(ctx, pkg.ScriptPath, "upgrade", nil)
+			err = c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "upgrade", nil)
 			close(done)
 			c.Lock()
 			c.packageStatus[pkg.Name].Installing = false
@@ -185,7 +200,7 @@ func (c *PackageController) installRunner(ctx context.Context) {
 				continue
 			}
 			// if installing, then skip
-			err := runCommand(ctx, pkg.ScriptPath, "isInstalled", nil)
+			err := c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "isInstalled", nil)
 			if err == nil {
 				c.Lock()
 				c.packageStatus[pkg.Name].Progress = 100
@@ -222,12 +237,12 @@ func (c *PackageController) installRunner(ctx context.Context) {
 						}
 					}
 				}()
-				err = runCommand(ctx, pkg.ScriptPath, "install", nil)
+				err = c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "install", nil)
 				close(done)
 				if err != nil {
 					log.Logger.Errorf("[package controller]: %v unexpected install failure: %v", pkg.Name, err)
 				} else {
-					if err = runCommand(ctx, pkg.ScriptPath, "start", nil); err != nil {
+					if err = c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "start", nil); err != nil {
 						log.Logger.Errorf("[package controller]: %v failed to start after installing: %v", pkg.Name, err)
 					}
 				}
@@ -251,10 +266,10 @@ func (c *PackageController) deleteRunner(ctx context.Context) {
 			ticker.Reset(c.syncPeriod)
 		}
 		for _, pkg := range c.packageStatus {
-			if err := runCommand(ctx, pkg.ScriptPath, "needDelete", nil); err != nil {
+			if err := c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "needDelete", nil); err != nil {
 				continue
 			}
-			err := runCommand(ctx, pkg.ScriptPath, "delete", nil)
+			err := c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "delete", nil)
 			if err != nil {
 				log.Logger.Infof("[package controller]: %v failed to delete: %v", pkg.Name, err)
 			}
@@ -276,7 +291,7 @@ func (c *PackageController) statusRunner(ctx context.Context) {
 			if !pkg.IsInstalled {
 				continue
 			}
-			err := runCommand(ctx, pkg.ScriptPath, "status", nil)
+			err := c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "status", nil)
 			if err == nil {
 				c.Lock()
 				c.packageStatus[pkg.Name].Status = true
@@ -285,26 +300,38 @@ func (c *PackageController) statusRunner(ctx context.Context) {
 				continue
 			}
 			log.Logger.Errorf("[package controller]: %v status not ok, restarting", pkg.Name)
-			if err = runCommand(ctx, pkg.ScriptPath, "stop", nil); err != nil {
+			if err = c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "stop", nil); err != nil {
 				log.Logger.Errorf("[package controller]: %v unexpected stop failure: %v", pkg.Name, err)
 				continue
 			}
-			if err = runCommand(ctx, pkg.ScriptPath, "start", nil); err != nil {
+			if err = c.runCommand(ctx, pkg.Name, pkg.ScriptPath, "start", nil); err != nil {
 				log.Logger.Errorf("[package controller]: %v unexpected start failure: %v", pkg.Name, err)
 			}
 		}
 	}
 }
 
-func runCommand(ctx context.Context, script, arg string, result *string) error {
+func (c *PackageController) runCommand(ctx context.Context, name, script, arg string, result *string) (retErr error) {
+	// unmarked after the output is persisted, to not prune the output being stored
+	id := ProcessID(name, arg)
+	c.markRunning(id)
+	defer c.unmarkRunning(id)
+
 	var ops []process.OpOption
 	if result == nil {
-		f, err := os.OpenFile(filepath.Join(filepath.Dir(script), arg+".log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		outputFile := filepath.Join(filepath.Dir(script), arg+".log")
+		f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if err != nil {
 			return err
 		}
 		defer f.Close()
 		ops = append(ops, process.WithOutputFile(f))
+
+		// persist the output once the process exits
+		// in case the output file is gone (e.g., after reboot)
+		defer func() {
+			c.storeOutput(id, outputFile, retErr)
+		}()
 	}
 
 	p, err := process.New(append(ops, process.WithCommand("bash", script, arg))...)
@@ -320,7 +347,11 @@ func runCommand(ctx context.Context, script, arg string, result *string) error {
 		}
 	}()
 
+	// the output is only set to the result after the reader completes,
+	// so the caller never reads the result being written (e.g., logging a torn string)
+	var readc chan string
 	if result != nil {
+		readc = make(chan string, 1)
 		go func() {
 			lines := make([]string, 0)
 			err := process.Read(
@@ -333,11 +364,10 @@ func runCommand(ctx context.Context, script, arg string, result *string) error {
 				}),
 			)
 			output := strings.Join(lines, "\n")
-			if err == nil {
-				*result = output
-			} else {
-				*result = fmt.Sprintf("failed to run '%s %s' with error %v\n\noutput:\n%s", script, arg, err, output)
+			if err != nil {
+				output = fmt.Sprintf("failed to run '%s %s' with error %v\n\noutput:\n%s", script, arg, err, output)
 			}
+			readc <- output
 		}()
 	}
 	select {
@@ -348,5 +378,69 @@ func runCommand(ctx context.Context, script, arg string, result *string) error {
 			return err
 		}
 	}
+	if readc != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case *result = <-readc:
+		}
+	}
 	return nil
 }
+
+func (c *PackageController) storeOutput(id string, outputFile string, processErr error) {
+	if c.dbRW == nil {
+		return
+	}
+
+	b, err := os.ReadFile(outputFile)
+	if err != nil {
+		log.Logger.Warnw("failed to read process output", "id", id, "error", err)
+		return
+	}
+
+	// not using the root context, to persist the output even when the root context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := InsertOutput(ctx, c.dbRW, id, time.Now().UTC().Unix(), processErr, string(b), c.maxStoredOutputBytes); err != nil {
+		log.Logger.Warnw("failed to persist process output", "id", id, "error", err)
+	}
+}
+
+func (c *PackageController) markRunning(id string) {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	c.running[id]++
+}
+
+func (c *PackageController) unmarkRunning(id string) {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	c.running[id]--
+	if c.running[id] <= 0 {
+		delete(c.running, id)
+	}
+}
+
+// RunningProcessIDs returns the sorted IDs of the package commands currently running.
+func (c *PackageController) RunningProcessIDs() []string {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+
+	ids := make([]string, 0, len(c.running))
+	for id := range c.running {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// PruneOutputs deletes the process outputs persisted before the given time,
+// except the ones of the package commands still running.
+// Returns the number of deleted rows.
+func (c *PackageController) PruneOutputs(ctx context.Context, before time.Time) (int, error) {
+	if c.dbRW == nil {
+		return 0, nil
+	}
+	return PruneOutputs(ctx, c.dbRW, before.Unix(), c.RunningProcessIDs())
+}
@@ -2,30 +2,133 @@ package manager
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/manager/controllers"
 	"github.com/leptonai/gpud/manager/informer"
 	"github.com/leptonai/gpud/manager/packages"
 )
 
+// Config configures the package manager.
+type Config struct {
+	// Database to persist the package command outputs (stdout/stderr).
+	// If nil, the outputs are not persisted.
+	DBRW *sql.DB
+	DBRO *sql.DB
+
+	// Maximum number of bytes of each command output to persist.
+	// Only the last bytes are kept if the output exceeds the limit.
+	// Defaults to controllers.DefaultMaxStoredOutputBytes if zero.
+	MaxStoredOutputBytes int
+
+	// Period to retain the persisted outputs of the finished processes.
+	// The outputs older than the period are pruned in the background.
+	// The outputs are retained indefinitely if zero.
+	RetentionPeriod time.Duration
+}
+
+// DefaultRetentionPeriod is the default period to retain the persisted process outputs.
+const DefaultRetentionPeriod = 7 * 24 * time.Hour
+
+// maxPruneInterval is the maximum interval between the background prunes.
+const maxPruneInterval = time.Hour
+
 type Manager struct {
+	cfg               Config
 	packageController *controllers.PackageController
 }
 
 var GlobalController *controllers.PackageController
 
-func New() (*Manager, error) {
-	return &Manager{}, nil
+func New(cfg Config) (*Manager, error) {
+	if cfg.MaxStoredOutputBytes < 0 {
+		return nil, fmt.Errorf("invalid max stored output bytes %d", cfg.MaxStoredOutputBytes)
+	}
+	if cfg.MaxStoredOutputBytes == 0 {
+		cfg.MaxStoredOutputBytes = controllers.DefaultMaxStoredOutputBytes
+	}
+	if cfg.RetentionPeriod < 0 {
+		return nil, fmt.Errorf("invalid retention period %v", cfg.RetentionPeriod)
+	}
+
+	if cfg.DBRW != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := controllers.CreateTableProcessOutputs(ctx, cfg.DBRW)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create process outputs table: %w", err)
+		}
+	}
+
+	return &Manager{cfg: cfg}, nil
 }
 
 func (a *Manager) Start(ctx context.Context) {
 	watcher := informer.NewFileInformer()
-	packageController := controllers.NewPackageController(watcher)
+	packageController := controllers.NewPackageController(watcher, a.cfg.DBRW, a.cfg.MaxStoredOutputBytes)
 	_ = packageController.Run(ctx)
 	a.packageController = packageController
 	GlobalController = packageController
+
+	if a.cfg.DBRW != nil && a.cfg.RetentionPeriod > 0 {
+		go a.pruneLoop(ctx)
+	}
+}
+
+func (a *Manager) pruneLoop(ctx context.Context) {
+	interval := a.cfg.RetentionPeriod
+	if interval > maxPruneInterval {
+		interval = maxPruneInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := a.Prune(ctx)
+		if err != nil {
+			log.Logger.Warnw("failed to prune process outputs", "error", err)
+			continue
+		}
+		log.Logger.Debugw("pruned process outputs", "deleted", deleted)
+	}
+}
+
+// Prune deletes the persisted outputs of the finished processes
+// older than the retention period, and returns the number of deleted outputs.
+// The outputs of the processes still running are not deleted.
+// No-op if the outputs are not persisted or the retention period is not set.
+func (a *Manager) Prune(ctx context.Context) (int, error) {
+	if a.cfg.DBRW == nil || a.cfg.RetentionPeriod == 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-a.cfg.RetentionPeriod)
+	if a.packageController != nil {
+		return a.packageController.PruneOutputs(ctx, before)
+	}
+	return controllers.PruneOutputs(ctx, a.cfg.DBRW, before.Unix(), nil)
 }
 
 func (a *Manager) Status(ctx context.Context) ([]packages.PackageStatus, error) {
 	return a.packageController.Status(ctx)
 }
+
+// GetOutput returns the last persisted output of the package command,
+// where the id is in the format of "<package name>/<command>" (e.g., "nvidia-driver/install").
+// Returns errdefs.ErrNotFound if no output is found.
+func (a *Manager) GetOutput(ctx context.Context, id string) (string, error) {
+	if a.cfg.DBRO == nil {
+		return "", errors.New("process outputs are not persisted")
+	}
+	return controllers.ReadOutput(ctx, a.cfg.DBRO, id)
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leptonai/gpud/errdefs"
+	"github.com/leptonai/gpud/manager/controllers"
+	"github.com/leptonai/gpud/pkg/sqlite"
+)
+
+func TestGetOutput(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	m, err := New(Config{DBRW: dbRW, DBRO: dbRO})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	id := controllers.ProcessID("test-pkg", "install")
+	if _, err := m.GetOutput(ctx, id); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := controllers.InsertOutput(ctx, dbRW, id, time.Now().Unix(), nil, "hello\n", m.cfg.MaxStoredOutputBytes); err != nil {
+		t.Fatalf("failed to insert output: %v", err)
+	}
+	output, err := m.GetOutput(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to get output: %v", err)
+	}
+	if output != "hello\n" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	if _, err := New(Config{MaxStoredOutputBytes: -1}); err == nil {
+		t.Error("expected error for negative max stored output bytes")
+	}
+	m, err := New(Config{})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	if _, err := m.GetOutput(context.Background(), "test-pkg/install"); err == nil {
+		t.Error("expected error when outputs are not persisted")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbRW, dbRO, cleanup := sqlite.OpenTestDB(t)
+	defer cleanup()
+
+	m, err := New(Config{DBRW: dbRW, DBRO: dbRO, RetentionPeriod: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	oldID := controllers.ProcessID("test-pkg", "install")
+	recentID := controllers.ProcessID("test-pkg", "status")
+	if err := controllers.InsertOutput(ctx, dbRW, oldID, time.Now().Add(-2*time.Hour).Unix(), nil, "old\n", m.cfg.MaxStoredOutputBytes); err != nil {
+		t.Fatalf("failed to insert output: %v", err)
+	}
+	if err := controllers.InsertOutput(ctx, dbRW, recentID, time.Now().Unix(), nil, "recent\n", m.cfg.MaxStoredOutputBytes); err != nil {
+		t.Fatalf("failed to insert output: %v", err)
+	}
+
+	deleted, err := m.Prune(ctx)
+	if err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 deleted output, got %d", deleted)
+	}
+	if _, err := m.GetOutput(ctx, oldID); !errors.Is(err, errdefs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := m.GetOutput(ctx, recentID); err != nil {
+		t.Errorf("expected the recent output to be kept, got %v", err)
+	}
+
+	if _, err := New(Config{RetentionPeriod: -time.Hour}); err == nil {
+		t.Error("expected error for negative retention period")
+	}
+}
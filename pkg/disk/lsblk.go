@@ -28,6 +28,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/leptonai/gpud/log"
 	"github.com/leptonai/gpud/pkg/file"
@@ -117,7 +118,14 @@ func decideLsblkFlag(ctx context.Context) (string, func([]byte, ...OpOption) (Bl
 // GetBlockDevices run os lsblk command for device and construct BlockDevice struct based on output
 // Receives device path. If device is empty string, info about all devices will be collected
 // Returns slice of BlockDevice structs or error if something went wrong
+// If configured with "WithLsblkRuns", runs lsblk multiple times and merges the results
+// (see "MergeBlockDevices").
 func GetBlockDevices(ctx context.Context, opts ...OpOption) (BlockDevices, error) {
+	op := &Op{}
+	if err := op.applyOpts(opts); err != nil {
+		return nil, err
+	}
+
 	lsblkPath, err := file.LocateExecutable("lsblk")
 	if err != nil {
 		return nil, err
@@ -130,8 +138,38 @@ func GetBlockDevices(ctx context.Context, opts ...OpOption) (BlockDevices, error
 		flags, parseFunc = lsblkFlags+" "+lsblkJsonFlag, ParseJSON
 	}
 
+	runs := make([]BlockDevices, 0, op.lsblkRuns)
+	var lastErr error
+	for i := 0; i < op.lsblkRuns; i++ {
+		b, err := runLsblk(ctx, lsblkPath+" "+flags)
+		if err == nil {
+			var devs BlockDevices
+			devs, err = parseFunc(b, opts...)
+			if err == nil {
+				runs = append(runs, devs)
+				continue
+			}
+		}
+
+		if op.lsblkRuns == 1 {
+			return nil, err
+		}
+		log.Sampled("disk-lsblk-failed", 10*time.Minute).Warnw("failed to run lsblk", "run", i+1, "runs", op.lsblkRuns, "error", err)
+		lastErr = err
+	}
+	if len(runs) == 0 {
+		return nil, lastErr
+	}
+	if len(runs) == 1 {
+		return runs[0], nil
+	}
+
+	return MergeBlockDevices(runs...), nil
+}
+
+func runLsblk(ctx context.Context, cmd string) ([]byte, error) {
 	p, err := process.New(
-		process.WithCommand(lsblkPath+" "+flags),
+		process.WithCommand(cmd),
 		process.WithRunAsBashScript(),
 	)
 	if err != nil {
@@ -161,7 +199,78 @@ func GetBlockDevices(ctx context.Context, opts ...OpOption) (BlockDevices, error
 		return nil, fmt.Errorf("failed to read lsblk output: %w\n\noutput:\n%s", err, strings.Join(lines, "\n"))
 	}
 
-	return parseFunc([]byte(strings.Join(lines, "\n")), opts...)
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// MergeBlockDevices unions the block devices from multiple lsblk runs,
+// keyed by the device name (partitions and multipath paths share the WWN
+// of their parent disk, so the WWN alone does not identify a device).
+// The device from the earliest run that reports it is kept (with the WWN
+// filled in from a later run if the earliest run omitted it), and
+// the device that is missing in some of the runs is marked as inconsistent.
+// The children are merged the same way, across the runs that report the parent.
+func MergeBlockDevices(runs ...BlockDevices) BlockDevices {
+	type merged struct {
+		dev      BlockDevice
+		seen     int
+		lastRun  int
+		children []BlockDevices
+	}
+
+	keys := make([]string, 0)
+	devs := make(map[string]*merged)
+	for i, run := range runs {
+		for _, dev := range run {
+			key := dev.Name
+
+			m, ok := devs[key]
+			if !ok {
+				m = &merged{dev: dev, lastRun: -1}
+				devs[key] = m
+				keys = append(keys, key)
+			}
+			if m.dev.WWN == "" {
+				m.dev.WWN = dev.WWN
+			}
+
+			// count each device once per run,
+			// even if the same run reports it twice
+			if m.lastRun == i {
+				last := len(m.children) - 1
+				m.children[last] = append(m.children[last], dev.Children...)
+				continue
+			}
+			m.seen++
+			m.lastRun = i
+			m.children = append(m.children, append(BlockDevices(nil), dev.Children...))
+		}
+	}
+
+	ret := make(BlockDevices, 0, len(keys))
+	for _, key := range keys {
+		m := devs[key]
+
+		dev := m.dev
+		dev.Inconsistent = dev.Inconsistent || m.seen < len(runs)
+
+		hasChildren := false
+		for _, children := range m.children {
+			if len(children) > 0 {
+				hasChildren = true
+				break
+			}
+		}
+		if hasChildren {
+			dev.Children = MergeBlockDevices(m.children...)
+		}
+
+		ret = append(ret, dev)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
 }
 
 func ParseJSON(b []byte, opts ...OpOption) (BlockDevices, error) {
@@ -422,6 +531,7 @@ type BlockDevice struct {
 	FSType           string        `json:"fstype,omitempty"`
 	PartUUID         string        `json:"partuuid,omitempty"`
 	PKName           string        `json:"-"`
+	Inconsistent     bool          `json:"inconsistent,omitempty"` // missing in some of the merged lsblk runs
 	Children         []BlockDevice `json:"children,omitempty"`
 }
 
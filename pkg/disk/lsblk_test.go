@@ -94,3 +94,53 @@ func TestCheckVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeBlockDevices(t *testing.T) {
+	t.Parallel()
+
+	run1 := BlockDevices{
+		{Name: "/dev/nvme0n1", Type: "disk", WWN: "eui.0001", Children: []BlockDevice{{Name: "/dev/nvme0n1p1", Type: "part"}}},
+		{Name: "/dev/nvme1n1", Type: "disk", WWN: "eui.0002"},
+		{Name: "/dev/sda", Type: "disk"},
+	}
+	run2 := BlockDevices{
+		{Name: "/dev/nvme0n1", Type: "disk", WWN: "eui.0001", Children: []BlockDevice{{Name: "/dev/nvme0n1p1", Type: "part"}}},
+		{Name: "/dev/sda", Type: "disk"},
+	}
+
+	merged := MergeBlockDevices(run1, run2)
+	expected := BlockDevices{
+		{Name: "/dev/nvme0n1", Type: "disk", WWN: "eui.0001", Children: BlockDevices{{Name: "/dev/nvme0n1p1", Type: "part"}}},
+		{Name: "/dev/nvme1n1", Type: "disk", WWN: "eui.0002", Inconsistent: true},
+		{Name: "/dev/sda", Type: "disk"},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+}
+
+func TestMergeBlockDevicesSharedWWN(t *testing.T) {
+	t.Parallel()
+
+	// partitions share the WWN of their parent disk
+	run := BlockDevices{
+		{Name: "/dev/sda", Type: "disk", WWN: "0x5000", Children: []BlockDevice{
+			{Name: "/dev/sda1", Type: "part", WWN: "0x5000"},
+			{Name: "/dev/sda2", Type: "part", WWN: "0x5000"},
+		}},
+	}
+
+	// the same device reported twice in one run is counted once
+	dup := BlockDevices{run[0], run[0]}
+
+	merged := MergeBlockDevices(run, dup)
+	expected := BlockDevices{
+		{Name: "/dev/sda", Type: "disk", WWN: "0x5000", Children: BlockDevices{
+			{Name: "/dev/sda1", Type: "part", WWN: "0x5000"},
+			{Name: "/dev/sda2", Type: "part", WWN: "0x5000"},
+		}},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+}
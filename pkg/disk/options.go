@@ -5,6 +5,7 @@ import "strings"
 type Op struct {
 	matchFuncFstype     MatchFunc
 	matchFuncDeviceType MatchFunc
+	lsblkRuns           int
 }
 
 type MatchFunc func(fs string) bool
@@ -28,6 +29,10 @@ func (op *Op) applyOpts(opts []OpOption) error {
 		}
 	}
 
+	if op.lsblkRuns < 1 {
+		op.lsblkRuns = 1
+	}
+
 	return nil
 }
 
@@ -43,6 +48,15 @@ func WithDeviceType(matchFunc MatchFunc) OpOption {
 	}
 }
 
+// WithLsblkRuns sets the number of lsblk invocations whose results are merged,
+// to tolerate the controllers that intermittently omit a device in a single run.
+// Defaults to 1.
+func WithLsblkRuns(runs int) OpOption {
+	return func(op *Op) {
+		op.lsblkRuns = runs
+	}
+}
+
 func DefaultMatchFuncFstype(fs string) bool {
 	return strings.HasPrefix(fs, "ext4") ||
 		strings.HasPrefix(fs, "apfs") ||
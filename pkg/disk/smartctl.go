@@ -0,0 +1,282 @@
+package disk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/file"
+	"github.com/leptonai/gpud/pkg/process"
+)
+
+// ErrSmartctlNotFound is returned when the smartctl binary is not installed.
+var ErrSmartctlNotFound = errors.New("smartctl not found")
+
+// smartctl exit status bits for the command line parse error
+// and the device open failure, where the output has no SMART data
+// ref. https://www.smartmontools.org/browser/trunk/smartmontools/smartctl.8.in
+const smartctlExitStatusFailedMask = 0x03
+
+// SmartDevice is the physical device from the "smartctl --scan-open --json" output.
+type SmartDevice struct {
+	// Name is the device path (e.g., "/dev/nvme0", "/dev/sda").
+	Name string `json:"name"`
+	// Type is the device type for the "-d" flag (e.g., "nvme", "sat").
+	Type string `json:"type"`
+	// Protocol is the device protocol (e.g., "NVMe", "ATA").
+	Protocol string `json:"protocol"`
+}
+
+// SmartInfo is the summary of the SMART attributes of a device,
+// parsed from the "smartctl --json --all" output.
+// The attribute fields are nil if not reported by the device
+// (e.g., NVMe has no reallocated sector count).
+type SmartInfo struct {
+	Device   string `json:"device"`
+	Type     string `json:"type"`
+	Protocol string `json:"protocol"`
+	Model    string `json:"model,omitempty"`
+	Serial   string `json:"serial,omitempty"`
+
+	// Supported is true if the device supports SMART and SMART is enabled.
+	Supported bool `json:"supported"`
+	// Message is the reason why the SMART data is not available, if any.
+	Message string `json:"message,omitempty"`
+
+	// Passed is the SMART overall-health self-assessment result.
+	Passed bool `json:"passed"`
+
+	// TemperatureCelsius is the current drive temperature.
+	TemperatureCelsius *int64 `json:"temperature_celsius,omitempty"`
+	// ReallocatedSectors is the raw value of the ATA attribute 5 (Reallocated_Sector_Ct).
+	ReallocatedSectors *int64 `json:"reallocated_sectors,omitempty"`
+	// MediaErrors is the NVMe media and data integrity errors,
+	// or the raw value of the ATA attribute 187 (Reported_Uncorrect).
+	MediaErrors *int64 `json:"media_errors,omitempty"`
+	// PercentageUsed is the estimated percentage of the device life used (wear leveling),
+	// from the NVMe percentage used, or derived from the normalized ATA wear attributes.
+	PercentageUsed *int64 `json:"percentage_used,omitempty"`
+}
+
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+
+	Device       SmartDevice `json:"device"`
+	ModelName    string      `json:"model_name"`
+	SerialNumber string      `json:"serial_number"`
+
+	SmartSupport *struct {
+		Available bool `json:"available"`
+		Enabled   bool `json:"enabled"`
+	} `json:"smart_support"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current int64 `json:"current"`
+	} `json:"temperature"`
+
+	NVMeSmartHealthInformationLog *struct {
+		PercentageUsed int64 `json:"percentage_used"`
+		MediaErrors    int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+
+	ATASmartAttributes *struct {
+		Table []smartctlATAAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+type smartctlATAAttribute struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+	Raw   struct {
+		Value int64 `json:"value"`
+	} `json:"raw"`
+}
+
+const (
+	ataAttrReallocatedSectorCount = 5
+	ataAttrReportedUncorrect      = 187
+)
+
+// ATA attributes whose normalized values are the remaining life in percent
+// (e.g., 177 "Wear_Leveling_Count" for Samsung, 233 "Media_Wearout_Indicator" for Intel)
+var ataAttrsRemainingLife = []int{177, 202, 231, 233}
+
+// ParseSmartctlJSON parses the "smartctl --json --all" output of a device.
+func ParseSmartctlJSON(b []byte) (SmartInfo, error) {
+	var out smartctlOutput
+	if err := json.Unmarshal(b, &out); err != nil {
+		return SmartInfo{}, err
+	}
+
+	info := SmartInfo{
+		Device:   out.Device.Name,
+		Type:     out.Device.Type,
+		Protocol: out.Device.Protocol,
+		Model:    out.ModelName,
+		Serial:   out.SerialNumber,
+	}
+
+	if out.Smartctl.ExitStatus&smartctlExitStatusFailedMask != 0 {
+		msgs := make([]string, 0, len(out.Smartctl.Messages))
+		for _, m := range out.Smartctl.Messages {
+			msgs = append(msgs, m.String)
+		}
+		info.Message = strings.Join(msgs, "; ")
+		if info.Message == "" {
+			info.Message = fmt.Sprintf("smartctl exited with status %d", out.Smartctl.ExitStatus)
+		}
+		return info, nil
+	}
+
+	switch {
+	case out.SmartSupport != nil:
+		info.Supported = out.SmartSupport.Available && out.SmartSupport.Enabled
+	default:
+		// old smartctl versions do not report the SMART support for NVMe
+		info.Supported = out.NVMeSmartHealthInformationLog != nil
+	}
+	if !info.Supported {
+		info.Message = "SMART not supported or disabled"
+		return info, nil
+	}
+
+	if out.SmartStatus != nil {
+		info.Passed = out.SmartStatus.Passed
+	}
+	if out.Temperature != nil {
+		info.TemperatureCelsius = int64Ptr(out.Temperature.Current)
+	}
+
+	if nvme := out.NVMeSmartHealthInformationLog; nvme != nil {
+		info.MediaErrors = int64Ptr(nvme.MediaErrors)
+		info.PercentageUsed = int64Ptr(nvme.PercentageUsed)
+	}
+
+	if out.ATASmartAttributes != nil {
+		attrs := make(map[int]smartctlATAAttribute, len(out.ATASmartAttributes.Table))
+		for _, attr := range out.ATASmartAttributes.Table {
+			attrs[attr.ID] = attr
+		}
+		if attr, ok := attrs[ataAttrReallocatedSectorCount]; ok {
+			info.ReallocatedSectors = int64Ptr(attr.Raw.Value)
+		}
+		if attr, ok := attrs[ataAttrReportedUncorrect]; ok {
+			info.MediaErrors = int64Ptr(attr.Raw.Value)
+		}
+		for _, id := range ataAttrsRemainingLife {
+			if attr, ok := attrs[id]; ok {
+				info.PercentageUsed = int64Ptr(100 - attr.Value)
+				break
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+// ParseSmartctlScanJSON parses the "smartctl --scan-open --json" output.
+func ParseSmartctlScanJSON(b []byte) ([]SmartDevice, error) {
+	var out struct {
+		Devices []SmartDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out.Devices, nil
+}
+
+// ScanSmartDevices lists the physical devices with "smartctl --scan-open".
+// Returns ErrSmartctlNotFound if smartctl is not installed.
+func ScanSmartDevices(ctx context.Context) ([]SmartDevice, error) {
+	smartctlPath, err := file.LocateExecutable("smartctl")
+	if err != nil {
+		return nil, ErrSmartctlNotFound
+	}
+
+	b, err := runSmartctl(ctx, smartctlPath+" --scan-open --json")
+	if err != nil {
+		return nil, err
+	}
+	return ParseSmartctlScanJSON(b)
+}
+
+// GetSmartInfo reads the SMART attributes of the device with "smartctl --json --all".
+// Returns ErrSmartctlNotFound if smartctl is not installed.
+func GetSmartInfo(ctx context.Context, dev SmartDevice) (SmartInfo, error) {
+	smartctlPath, err := file.LocateExecutable("smartctl")
+	if err != nil {
+		return SmartInfo{}, ErrSmartctlNotFound
+	}
+
+	cmd := smartctlPath + " --json --all"
+	if dev.Type != "" {
+		cmd += " -d " + dev.Type
+	}
+	b, err := runSmartctl(ctx, cmd+" "+dev.Name)
+	if err != nil {
+		return SmartInfo{}, err
+	}
+
+	info, err := ParseSmartctlJSON(b)
+	if err != nil {
+		return SmartInfo{}, err
+	}
+	if info.Device == "" {
+		info.Device = dev.Name
+	}
+	return info, nil
+}
+
+func runSmartctl(ctx context.Context, cmd string) ([]byte, error) {
+	p, err := process.New(
+		process.WithCommand(cmd),
+		process.WithRunAsBashScript(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Start(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			log.Logger.Warnw("failed to abort command", "err", err)
+		}
+	}()
+
+	lines := make([]string, 0)
+	readErr := process.Read(
+		ctx,
+		p,
+		process.WithReadStdout(),
+		process.WithProcessLine(func(line string) {
+			lines = append(lines, line)
+		}),
+		process.WithWaitForCmd(),
+	)
+	output := strings.Join(lines, "\n")
+
+	// smartctl exits with the non-zero status bits for the disk problems
+	// (e.g., failing SMART status), while still writing the valid JSON output
+	if readErr != nil && !json.Valid([]byte(output)) {
+		return nil, fmt.Errorf("failed to read smartctl output: %w\n\noutput:\n%s", readErr, output)
+	}
+	return []byte(output), nil
+}
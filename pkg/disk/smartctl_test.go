@@ -0,0 +1,105 @@
+package disk
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseSmartctlJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file     string
+		expected SmartInfo
+	}{
+		{
+			file: "smartctl.nvme.json",
+			expected: SmartInfo{
+				Device:             "/dev/nvme0",
+				Type:               "nvme",
+				Protocol:           "NVMe",
+				Model:              "SAMSUNG MZQL23T8HCLS-00A07",
+				Serial:             "S64HNE0T500123",
+				Supported:          true,
+				Passed:             true,
+				TemperatureCelsius: int64Ptr(41),
+				MediaErrors:        int64Ptr(0),
+				PercentageUsed:     int64Ptr(3),
+			},
+		},
+		{
+			file: "smartctl.sata.json",
+			expected: SmartInfo{
+				Device:             "/dev/sda",
+				Type:               "sat",
+				Protocol:           "ATA",
+				Model:              "Samsung SSD 860 EVO 500GB",
+				Serial:             "S3Z2NB0K812345A",
+				Supported:          true,
+				Passed:             true,
+				TemperatureCelsius: int64Ptr(34),
+				ReallocatedSectors: int64Ptr(12),
+				MediaErrors:        int64Ptr(0),
+				PercentageUsed:     int64Ptr(9),
+			},
+		},
+		{
+			file: "smartctl.unsupported.json",
+			expected: SmartInfo{
+				Message: "/dev/sdb: Unable to detect device type",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			b, err := os.ReadFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := ParseSmartctlJSON(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(info, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, info)
+			}
+		})
+	}
+}
+
+func TestParseSmartctlJSONNotSupported(t *testing.T) {
+	t.Parallel()
+
+	info, err := ParseSmartctlJSON([]byte(`{"device":{"name":"/dev/sdc","type":"sat","protocol":"ATA"},"smart_support":{"available":false,"enabled":false}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Supported || info.Message == "" || info.TemperatureCelsius != nil {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	if _, err := ParseSmartctlJSON([]byte("invalid")); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
+
+func TestParseSmartctlScanJSON(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("testdata/smartctl.scan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	devs, err := ParseSmartctlScanJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []SmartDevice{
+		{Name: "/dev/sda", Type: "sat", Protocol: "ATA"},
+		{Name: "/dev/nvme0", Type: "nvme", Protocol: "NVMe"},
+	}
+	if !reflect.DeepEqual(devs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, devs)
+	}
+}
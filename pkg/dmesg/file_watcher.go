@@ -0,0 +1,177 @@
+package dmesg
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leptonai/gpud/log"
+)
+
+// defaultFilePollInterval is the interval to check the log file for new lines,
+// once the reader reaches the end of the file.
+var defaultFilePollInterval = time.Second
+
+// NewFileWatcher returns a watcher that tails the log file (e.g., "/var/log/syslog").
+// The file is read from the beginning, same as the dmesg buffer being replayed on start.
+// The file is re-opened when rotated or truncated, or on read errors.
+func NewFileWatcher(file string) (Watcher, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan LogLine, 1000)
+	go tailFile(ctx, file, defaultFilePollInterval, ch)
+	return &watcher{ch: ch, cancel: cancel}, nil
+}
+
+func tailFile(ctx context.Context, file string, pollInterval time.Duration, ch chan<- LogLine) {
+	defer close(ch)
+
+	var (
+		f      *os.File
+		rd     *bufio.Reader
+		offset int64
+		// buffers the partial line until the writer completes it with a new line
+		partial string
+	)
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}()
+
+	reopen := func(resetOffset bool) {
+		if f != nil {
+			_ = f.Close()
+			f = nil
+		}
+		if resetOffset {
+			offset = 0
+			partial = ""
+		}
+
+		var err error
+		f, err = os.Open(file)
+		if err != nil {
+			log.Logger.Debugw("failed to open log file", "file", file, "error", err)
+			f = nil
+			return
+		}
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			log.Logger.Warnw("failed to seek log file", "file", file, "error", err)
+			_ = f.Close()
+			f = nil
+			return
+		}
+		rd = bufio.NewReader(f)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	reopen(true)
+	for {
+		if f != nil {
+			for {
+				line, err := rd.ReadString('\n')
+				offset += int64(len(line))
+				if err == nil {
+					line = strings.TrimRight(partial+line, "\r\n")
+					partial = ""
+					if line == "" {
+						continue
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case ch <- ParseLogLine(line):
+					default:
+						log.Logger.Warnw("failed to send event -- dropped")
+					}
+					continue
+				}
+
+				partial += line
+				if !errors.Is(err, io.EOF) {
+					log.Logger.Warnw("failed to read log file -- re-opening", "file", file, "error", err)
+					reopen(false)
+				}
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if f == nil {
+			reopen(true)
+			continue
+		}
+
+		// check if the file is rotated (different file at the same path) or truncated
+		cur, err := os.Stat(file)
+		if err != nil {
+			// may be in the middle of rotation, retry in the next tick
+			continue
+		}
+		prev, err := f.Stat()
+		if err != nil || !os.SameFile(prev, cur) {
+			log.Logger.Infow("log file rotated -- re-opening", "file", file)
+			reopen(true)
+			continue
+		}
+		if cur.Size() < offset {
+			log.Logger.Infow("log file truncated -- re-opening", "file", file)
+			reopen(true)
+		}
+	}
+}
+
+var syslogTimeFormats = []string{
+	time.RFC3339Nano,
+
+	// e.g., "journalctl -o short-iso" output
+	"2006-01-02T15:04:05-0700",
+}
+
+// ParseLogLine parses the log line from the dmesg output or the syslog file.
+// It falls back to the current time if the timestamp is not found.
+func ParseLogLine(line string) LogLine {
+	if findISOTimestampIndex(line) != -1 {
+		return ParseDmesgLine(line)
+	}
+
+	// e.g., "2025-01-21T04:41:44.123456+00:00 host kernel: ..." (high precision syslog)
+	if fields := strings.SplitN(line, " ", 2); len(fields) == 2 {
+		for _, format := range syslogTimeFormats {
+			if ts, err := time.Parse(format, fields[0]); err == nil {
+				return LogLine{Timestamp: ts.UTC(), Content: strings.TrimSpace(fields[1])}
+			}
+		}
+	}
+
+	// e.g., "Jan 21 04:41:44 host kernel: ..." (traditional syslog without the year)
+	const syslogTimeFormat = "Jan _2 15:04:05"
+	if len(line) > len(syslogTimeFormat) {
+		if ts, err := time.ParseInLocation(syslogTimeFormat, line[:len(syslogTimeFormat)], time.Local); err == nil {
+			now := time.Now()
+			ts = ts.AddDate(now.Year(), 0, 0)
+			if ts.After(now.Add(24 * time.Hour)) {
+				// logged in the last year (e.g., "Dec 31" read on "Jan 1")
+				ts = ts.AddDate(-1, 0, 0)
+			}
+			return LogLine{Timestamp: ts.UTC(), Content: strings.TrimSpace(line[len(syslogTimeFormat):])}
+		}
+	}
+
+	return LogLine{Timestamp: time.Now().UTC(), Content: line}
+}
@@ -0,0 +1,129 @@
+package dmesg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTailFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "syslog")
+	if err := os.WriteFile(file, []byte("line 1\nline 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan LogLine, 100)
+	go tailFile(ctx, file, 10*time.Millisecond, ch)
+
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case line := <-ch:
+			if line.Content != want {
+				t.Fatalf("expected %q, got %q", want, line.Content)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+	appendLines := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// existing lines are replayed
+	expect("line 1")
+	expect("line 2")
+
+	// appended lines, including the partial line completed later
+	appendLines("line 3\nline")
+	expect("line 3")
+	time.Sleep(50 * time.Millisecond)
+	appendLines(" 4\n")
+	expect("line 4")
+
+	// truncated
+	if err := os.WriteFile(file, []byte("new 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect("new 1")
+
+	// rotated
+	if err := os.Rename(file, file+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("rotated 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect("rotated 1")
+}
+
+func TestNewFileWatcherNotExist(t *testing.T) {
+	if _, err := NewFileWatcher(filepath.Join(t.TempDir(), "does-not-exist")); err == nil {
+		t.Fatal("expected error for nonexistent file")
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		line        string
+		wantTime    time.Time
+		wantContent string
+	}{
+		{
+			name:        "dmesg iso",
+			line:        "kern  :warn  : 2025-01-21T04:41:44,285060+00:00 NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+			wantTime:    time.Date(2025, 1, 21, 4, 41, 44, 285060000, time.UTC),
+			wantContent: "NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+		},
+		{
+			name:        "high precision syslog",
+			line:        "2025-01-21T04:41:44.123456+00:00 host kernel: NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+			wantTime:    time.Date(2025, 1, 21, 4, 41, 44, 123456000, time.UTC),
+			wantContent: "host kernel: NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+		},
+		{
+			name:        "short iso",
+			line:        "2025-01-21T04:41:44+0000 host kernel: NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+			wantTime:    time.Date(2025, 1, 21, 4, 41, 44, 0, time.UTC),
+			wantContent: "host kernel: NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+		},
+		{
+			name:        "traditional syslog",
+			line:        now.Format("Jan _2 15:04:05") + " host kernel: [ 123.456789] NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+			wantTime:    now.Truncate(time.Second).UTC(),
+			wantContent: "host kernel: [ 123.456789] NVRM: Xid (PCI:0000:05:00): 79, GPU has fallen off the bus.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLogLine(tt.line)
+			if !got.Timestamp.Equal(tt.wantTime) {
+				t.Errorf("expected time %v, got %v", tt.wantTime, got.Timestamp)
+			}
+			if got.Content != tt.wantContent {
+				t.Errorf("expected content %q, got %q", tt.wantContent, got.Content)
+			}
+		})
+	}
+
+	// no timestamp, falls back to the current time
+	got := ParseLogLine("no timestamp")
+	if got.Content != "no timestamp" || time.Since(got.Timestamp) > time.Minute {
+		t.Errorf("unexpected log line %+v", got)
+	}
+}
@@ -0,0 +1,199 @@
+package dmesg
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/leptonai/gpud/log"
+	"github.com/leptonai/gpud/pkg/host"
+)
+
+// DefaultKmsgFile is the kernel log device, often the only kernel log source
+// available in a container (e.g., no "dmesg" binary nor the syslog files).
+const DefaultKmsgFile = "/dev/kmsg"
+
+// kmsgMaxRecordSize is the max size of a record read from "/dev/kmsg"
+// (the kernel returns EINVAL if the read buffer is smaller than the record).
+const kmsgMaxRecordSize = 8192
+
+// NewKmsgWatcher returns a watcher that reads the kernel log records from the "/dev/kmsg" device.
+// The records are read from the beginning of the ring buffer, same as the dmesg buffer being replayed on start.
+func NewKmsgWatcher(file string) (Watcher, error) {
+	bootTime, err := host.GetBootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	// non-blocking, so that the pending read is interrupted on close
+	f, err := os.OpenFile(file, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan LogLine, 1000)
+	go func() {
+		<-ctx.Done()
+		_ = f.Close()
+	}()
+	go readKmsg(ctx, f, bootTime, ch)
+	return &watcher{ch: ch, cancel: cancel}, nil
+}
+
+func readKmsg(ctx context.Context, rd io.Reader, bootTime time.Time, ch chan<- LogLine) {
+	defer close(ch)
+
+	br := bufio.NewReaderSize(rd, kmsgMaxRecordSize)
+
+	var lastSeq uint64
+	seen := false
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			// the records being read were overwritten in the ring buffer,
+			// and the next read continues from the oldest record available
+			if errors.Is(err, syscall.EPIPE) {
+				log.Logger.Warnw("kmsg ring buffer wrapped -- messages lost")
+				continue
+			}
+			if !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) {
+				select {
+				case <-ctx.Done():
+				case ch <- LogLine{Timestamp: time.Now().UTC(), Error: fmt.Sprintf("reading kmsg failed: %v", err)}:
+				}
+			}
+			return
+		}
+
+		line = strings.TrimRight(line, "\n")
+
+		// continuation lines (starting with a space) are the key/value pairs
+		// of the previous record (e.g., " SUBSYSTEM=pci")
+		if line == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		rec, err := ParseKmsgRecord(line)
+		if err != nil {
+			log.Logger.Warnw("failed to parse kmsg record", "line", line, "error", err)
+			continue
+		}
+
+		if seen && rec.Sequence > lastSeq+1 {
+			log.Logger.Warnw("kmsg ring buffer wrapped -- messages lost", "lost", rec.Sequence-lastSeq-1)
+		}
+		lastSeq, seen = rec.Sequence, true
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- rec.LogLine(bootTime):
+		default:
+			log.Logger.Warnw("failed to send event -- dropped")
+		}
+	}
+}
+
+// KmsgRecord is a kernel log record in the "/dev/kmsg" format.
+// ref. https://www.kernel.org/doc/Documentation/ABI/testing/dev-kmsg
+type KmsgRecord struct {
+	// Priority is the syslog facility and level combined ("facility << 3 | level").
+	Priority int
+	// Sequence is the 64-bit record sequence number.
+	Sequence uint64
+	// SinceBoot is the monotonic time of the record since the boot.
+	SinceBoot time.Duration
+	// Continued is true if the record is a fragment of a line (the "c" flag).
+	Continued bool
+	// Message is the record text with the escaped characters (e.g., "\x0a") decoded.
+	Message string
+}
+
+// ParseKmsgRecord parses the first line of the record
+// in the "<priority>,<sequence>,<timestamp>,<flags>[,...];<message>" format
+// (e.g., "3,1234,5678901,-;NVRM: Xid ..."), where the flags and the following fields are optional.
+func ParseKmsgRecord(line string) (KmsgRecord, error) {
+	prefix, msg, ok := strings.Cut(line, ";")
+	if !ok {
+		return KmsgRecord{}, errors.New("no message separator")
+	}
+
+	fields := strings.Split(prefix, ",")
+	if len(fields) < 3 {
+		return KmsgRecord{}, fmt.Errorf("expected at least 3 prefix fields, got %d", len(fields))
+	}
+
+	var rec KmsgRecord
+	var err error
+	rec.Priority, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return KmsgRecord{}, fmt.Errorf("failed to parse priority %q: %w", fields[0], err)
+	}
+	rec.Sequence, err = strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return KmsgRecord{}, fmt.Errorf("failed to parse sequence %q: %w", fields[1], err)
+	}
+	usec, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return KmsgRecord{}, fmt.Errorf("failed to parse timestamp %q: %w", fields[2], err)
+	}
+	rec.SinceBoot = time.Duration(usec) * time.Microsecond
+	if len(fields) > 3 {
+		rec.Continued = strings.Contains(fields[3], "c")
+	}
+	rec.Message = unescapeKmsg(msg)
+
+	return rec, nil
+}
+
+var (
+	kmsgFacilities = []string{"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp"}
+	kmsgLevels     = []string{"emerg", "alert", "crit", "err", "warn", "notice", "info", "debug"}
+)
+
+// LogLine converts the record to the log line, same as the decoded dmesg output.
+// The current time is used if the boot time is unknown.
+func (r KmsgRecord) LogLine(bootTime time.Time) LogLine {
+	l := LogLine{
+		Timestamp: time.Now().UTC(),
+		Level:     kmsgLevels[r.Priority&7],
+		Content:   r.Message,
+	}
+	if !bootTime.IsZero() {
+		l.Timestamp = bootTime.Add(r.SinceBoot).UTC()
+	}
+	if facility := r.Priority >> 3; facility >= 0 && facility < len(kmsgFacilities) {
+		l.Facility = kmsgFacilities[facility]
+	} else if facility >= 16 && facility <= 23 {
+		l.Facility = "local" + strconv.Itoa(facility-16)
+	}
+	return l
+}
+
+// unescapeKmsg decodes the non-printable characters escaped by the kernel (e.g., "\x0a" for the new line).
+func unescapeKmsg(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if v, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package dmesg
+
+import (
+	"context"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const testKmsg = `6,1230,5678000,-;nvidia-nvlink: Nvlink Core is being initialized
+3,1234,5678901,-;NVRM: Xid (PCI:0000:05:00): 79, pid='<unknown>', name=<unknown>, GPU has fallen off the bus.
+ SUBSYSTEM=pci
+ DEVICE=+pci:0000:05:00.0
+4,1235,5679000,c;NVRM: line 1\x0aline 2
+30,1236,5680000,-;systemd[1]: Started Session 1 of user root.
+`
+
+func TestReadKmsg(t *testing.T) {
+	bootTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ch := make(chan LogLine, 100)
+	readKmsg(context.Background(), strings.NewReader(testKmsg), bootTime, ch)
+
+	var lines []LogLine
+	for l := range ch {
+		lines = append(lines, l)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %+v", len(lines), lines)
+	}
+
+	xid := lines[1]
+	if !strings.HasPrefix(xid.Content, "NVRM: Xid (PCI:0000:05:00): 79,") {
+		t.Fatalf("unexpected xid content %q", xid.Content)
+	}
+	if xid.Facility != "kern" || xid.Level != "err" {
+		t.Fatalf("unexpected facility/level %q/%q", xid.Facility, xid.Level)
+	}
+	if want := bootTime.Add(5678901 * time.Microsecond); !xid.Timestamp.Equal(want) {
+		t.Fatalf("expected timestamp %v, got %v", want, xid.Timestamp)
+	}
+	if xid.Error != "" {
+		t.Fatalf("unexpected error %q", xid.Error)
+	}
+
+	if lines[2].Content != "NVRM: line 1\nline 2" {
+		t.Fatalf("unexpected multi-line content %q", lines[2].Content)
+	}
+	if lines[3].Facility != "daemon" || lines[3].Level != "info" {
+		t.Fatalf("unexpected facility/level %q/%q", lines[3].Facility, lines[3].Level)
+	}
+}
+
+// epipeReader fails the first read with EPIPE, as "/dev/kmsg" does
+// when the next record was overwritten in the ring buffer.
+type epipeReader struct {
+	failed bool
+	rd     io.Reader
+}
+
+func (r *epipeReader) Read(p []byte) (int, error) {
+	if !r.failed {
+		r.failed = true
+		return 0, syscall.EPIPE
+	}
+	return r.rd.Read(p)
+}
+
+func TestReadKmsgRingBufferWrap(t *testing.T) {
+	ch := make(chan LogLine, 100)
+	rd := &epipeReader{rd: strings.NewReader(testKmsg)}
+	readKmsg(context.Background(), rd, time.Time{}, ch)
+
+	cnt := 0
+	for l := range ch {
+		if l.Error != "" {
+			t.Fatalf("unexpected error %q", l.Error)
+		}
+		cnt++
+	}
+	if cnt != 4 {
+		t.Fatalf("expected 4 lines, got %d", cnt)
+	}
+}
+
+func TestParseKmsgRecord(t *testing.T) {
+	rec, err := ParseKmsgRecord("4,1235,5679000,c;NVRM: line 1\\x0aline 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Priority != 4 || rec.Sequence != 1235 || rec.SinceBoot != 5679*time.Millisecond || !rec.Continued {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	if rec.Message != "NVRM: line 1\nline 2" {
+		t.Fatalf("unexpected message %q", rec.Message)
+	}
+
+	// flags are optional
+	rec, err = ParseKmsgRecord("6,1,2;hello; world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Continued || rec.Message != "hello; world" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+
+	for _, line := range []string{
+		"",
+		"6,1,2,-",
+		"6,1;hello",
+		"x,1,2,-;hello",
+		"6,x,2,-;hello",
+		"6,1,x,-;hello",
+	} {
+		if _, err := ParseKmsgRecord(line); err == nil {
+			t.Errorf("expected error for %q", line)
+		}
+	}
+}
+
+func TestUnescapeKmsg(t *testing.T) {
+	for in, want := range map[string]string{
+		"no escapes":        "no escapes",
+		`a\x0ab`:            "a\nb",
+		`trailing\x0`:       `trailing\x0`,
+		`invalid\xzz`:       `invalid\xzz`,
+		`back\x5cslash\x09`: "back\\slash\t",
+	} {
+		if got := unescapeKmsg(in); got != want {
+			t.Errorf("unescapeKmsg(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
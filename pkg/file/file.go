@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+
+	"github.com/leptonai/gpud/errdefs"
 )
 
 func LocateExecutable(bin string) (string, error) {
@@ -12,7 +14,7 @@ func LocateExecutable(bin string) (string, error) {
 	if err == nil {
 		return execPath, CheckExecutable(execPath)
 	}
-	return "", fmt.Errorf("executable %q not found in PATH: %w", bin, err)
+	return "", fmt.Errorf("executable %q not found in PATH: %w (%w)", bin, err, errdefs.ErrUnavailable)
 }
 
 func CheckExecutable(file string) error {
@@ -26,7 +28,7 @@ func CheckExecutable(file string) error {
 	}
 
 	if s.Mode()&0111 == 0 {
-		return fmt.Errorf("%q is not executable", file)
+		return fmt.Errorf("%q is not executable: %w", file, errdefs.ErrPermission)
 	}
 
 	return nil
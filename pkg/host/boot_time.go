@@ -0,0 +1,20 @@
+package host
+
+import (
+	"context"
+	"time"
+
+	gopsutil_host "github.com/shirou/gopsutil/v4/host"
+)
+
+// GetBootTime returns the system boot time (e.g., the "btime" field in "/proc/stat" on linux).
+func GetBootTime() (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	secs, err := gopsutil_host.BootTimeWithContext(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(secs), 0).UTC(), nil
+}
@@ -0,0 +1,16 @@
+package host
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBootTime(t *testing.T) {
+	bootTime, err := GetBootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bootTime.IsZero() || bootTime.After(time.Now()) {
+		t.Errorf("unexpected boot time %v", bootTime)
+	}
+}
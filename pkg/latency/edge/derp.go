@@ -53,6 +53,9 @@ func measureDERP(ctx context.Context, opts ...OpOption) (latency.Latencies, erro
 	}
 
 	dm := derpmap.DefaultDERPMap
+	if op.derpMap != nil {
+		dm = *op.derpMap
+	}
 	report, err := c.GetReport(ctx, &dm, nil)
 	if err != nil {
 		return nil, err
@@ -5,11 +5,16 @@
 package derpmap
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leptonai/gpud/log"
 
 	"tailscale.com/tailcfg"
 )
@@ -34,12 +39,28 @@ const TailscaleDERPMapURL = "https://controlplane.tailscale.com/derpmap/default"
 // DownloadTailcaleDERPMap downloads the official Tailscale public DERP map.
 // ref. "prodDERPMap" in tailscale/tailscale/cmd/tailscale/cli/netcheck.go
 func DownloadTailcaleDERPMap() (*tailcfg.DERPMap, error) {
-	res, err := http.Get(TailscaleDERPMapURL)
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+	return downloadDERPMap(ctx, TailscaleDERPMapURL)
+}
+
+const downloadTimeout = 15 * time.Second
+
+func downloadDERPMap(ctx context.Context, url string) (*tailcfg.DERPMap, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %q", res.StatusCode, url)
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -49,5 +70,82 @@ func DownloadTailcaleDERPMap() (*tailcfg.DERPMap, error) {
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
+	if len(data.Regions) == 0 {
+		return nil, fmt.Errorf("empty DERP map from %q", url)
+	}
 	return &data, nil
 }
+
+const (
+	// minRefreshBackoff is the wait after the first failed refresh,
+	// doubled on every consecutive failure up to maxRefreshBackoff.
+	minRefreshBackoff = time.Minute
+	maxRefreshBackoff = time.Hour
+)
+
+var (
+	refreshedMu        sync.Mutex
+	refreshed          *tailcfg.DERPMap
+	refreshedTimestamp time.Time
+
+	// refreshing is true while a download is in flight,
+	// so that the concurrent callers use the latest known DERP map.
+	refreshing bool
+	// the failed refresh is not retried until nextRefresh
+	refreshBackoff time.Duration
+	nextRefresh    time.Time
+)
+
+// LoadWithRefresh returns the latest known DERP map.
+// If the last refreshed DERP map is older than "maxAge",
+// it downloads a fresh one from the public Tailscale endpoint.
+// The sync time of the embedded DERP map is unknown,
+// so it is refreshed on the first load.
+// Returns true if a refresh happened.
+//
+// On download failure, it falls back to the latest known DERP map,
+// and returns it along with the error. The failed refresh is retried
+// with the exponential backoff, not on every load.
+// Set "maxAge" to zero to never refresh.
+func LoadWithRefresh(ctx context.Context, maxAge time.Duration) (*tailcfg.DERPMap, bool, error) {
+	return loadWithRefresh(ctx, TailscaleDERPMapURL, time.Now(), maxAge)
+}
+
+func loadWithRefresh(ctx context.Context, url string, now time.Time, maxAge time.Duration) (*tailcfg.DERPMap, bool, error) {
+	refreshedMu.Lock()
+	latest, latestTimestamp := &DefaultDERPMap, time.Time{}
+	if refreshed != nil {
+		latest, latestTimestamp = refreshed, refreshedTimestamp
+	}
+	if maxAge <= 0 || refreshing || now.Before(nextRefresh) || (!latestTimestamp.IsZero() && now.Sub(latestTimestamp) <= maxAge) {
+		refreshedMu.Unlock()
+		return latest, false, nil
+	}
+	refreshing = true
+	refreshedMu.Unlock()
+
+	log.Logger.Infow("refreshing stale DERP map", "timestamp", latestTimestamp, "maxAge", maxAge)
+	cctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	dm, err := downloadDERPMap(cctx, url)
+	cancel()
+
+	refreshedMu.Lock()
+	defer refreshedMu.Unlock()
+	refreshing = false
+
+	if err != nil {
+		refreshBackoff *= 2
+		if refreshBackoff < minRefreshBackoff {
+			refreshBackoff = minRefreshBackoff
+		}
+		if refreshBackoff > maxRefreshBackoff {
+			refreshBackoff = maxRefreshBackoff
+		}
+		nextRefresh = now.Add(refreshBackoff)
+		return latest, false, fmt.Errorf("failed to refresh DERP map (retry after %v): %w", refreshBackoff, err)
+	}
+
+	refreshed, refreshedTimestamp = dm, now
+	refreshBackoff, nextRefresh = 0, time.Time{}
+	return dm, true, nil
+}
@@ -1,7 +1,14 @@
 package derpmap
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"tailscale.com/tailcfg"
 )
 
 func TestDefaultDERPMap(t *testing.T) {
@@ -30,3 +37,123 @@ func TestDefaultDERPMap(t *testing.T) {
 		t.Logf("region name %q has %d nodes", region.RegionName, len(region.Nodes))
 	}
 }
+
+func resetRefreshed() {
+	refreshedMu.Lock()
+	refreshed, refreshedTimestamp = nil, time.Time{}
+	refreshing, refreshBackoff, nextRefresh = false, 0, time.Time{}
+	refreshedMu.Unlock()
+}
+
+func TestLoadWithRefreshStale(t *testing.T) {
+	resetRefreshed()
+	defer resetRefreshed()
+
+	fresh := tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			999: {RegionID: 999, RegionCode: "test", RegionName: "Test"},
+		},
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(fresh)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	dm, refreshed, err := loadWithRefresh(ctx, srv.URL, now, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refreshed {
+		t.Fatal("expected refresh for the embedded DERP map")
+	}
+	if _, ok := dm.Regions[999]; !ok || len(dm.Regions) != 1 {
+		t.Fatalf("expected the refreshed DERP map, got %d regions", len(dm.Regions))
+	}
+
+	// the refreshed map is fresh, thus no more download
+	dm, refreshed, err = loadWithRefresh(ctx, srv.URL, now.Add(time.Hour), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed {
+		t.Fatal("expected no refresh for the recently refreshed DERP map")
+	}
+	if _, ok := dm.Regions[999]; !ok {
+		t.Fatal("expected the cached refreshed DERP map")
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+
+	// the refreshed map became stale
+	if _, refreshed, err = loadWithRefresh(ctx, srv.URL, now.Add(25*time.Hour), 24*time.Hour); err != nil || !refreshed {
+		t.Fatalf("expected refresh for the stale DERP map, got refreshed %v, err %v", refreshed, err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestLoadWithRefreshServerDown(t *testing.T) {
+	resetRefreshed()
+	defer resetRefreshed()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	dm, refreshed, err := loadWithRefresh(ctx, url, now, 24*time.Hour)
+	if err == nil {
+		t.Fatal("expected error when the server is down")
+	}
+	if refreshed {
+		t.Fatal("expected no refresh when the server is down")
+	}
+	if dm != &DefaultDERPMap {
+		t.Fatal("expected the embedded DERP map as fallback")
+	}
+
+	// backs off without retrying
+	if _, refreshed, err = loadWithRefresh(ctx, url, now.Add(minRefreshBackoff/2), 24*time.Hour); err != nil || refreshed {
+		t.Fatalf("expected no retry in the backoff, got refreshed %v, err %v", refreshed, err)
+	}
+	if _, _, err = loadWithRefresh(ctx, url, now.Add(minRefreshBackoff), 24*time.Hour); err == nil {
+		t.Fatal("expected retry after the backoff")
+	}
+	if refreshBackoff != 2*minRefreshBackoff {
+		t.Fatalf("expected the doubled backoff, got %v", refreshBackoff)
+	}
+}
+
+func TestLoadWithRefreshNotStale(t *testing.T) {
+	resetRefreshed()
+	defer resetRefreshed()
+
+	ctx := context.Background()
+	now := time.Now()
+	// refreshed an hour ago
+	refreshed, refreshedTimestamp = &DefaultDERPMap, now.Add(-time.Hour)
+
+	dm, refreshed, err := loadWithRefresh(ctx, "http://127.0.0.1:0", now, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed {
+		t.Fatal("expected no refresh")
+	}
+	if dm != &DefaultDERPMap {
+		t.Fatal("expected the embedded DERP map")
+	}
+
+	// zero max age never refreshes
+	if _, refreshed, err = loadWithRefresh(ctx, "http://127.0.0.1:0", now.Add(48*time.Hour), 0); err != nil || refreshed {
+		t.Fatalf("expected no refresh with zero max age, got refreshed %v, err %v", refreshed, err)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"context"
 
 	"github.com/leptonai/gpud/pkg/latency"
+
+	"tailscale.com/tailcfg"
 )
 
 type Op struct {
 	verbose bool
+	derpMap *tailcfg.DERPMap
 }
 
 type OpOption func(*Op)
@@ -27,6 +30,13 @@ func WithVerbose(verbose bool) OpOption {
 	}
 }
 
+// WithDERPMap overwrites the DERP map to measure against (default: the embedded DERP map).
+func WithDERPMap(dm *tailcfg.DERPMap) OpOption {
+	return func(op *Op) {
+		op.derpMap = dm
+	}
+}
+
 // Measure measures the latencies from local to the global edge nodes.
 func Measure(ctx context.Context, opts ...OpOption) (latency.Latencies, error) {
 	return measureDERP(ctx, opts...)
@@ -0,0 +1,50 @@
+package pci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NoNUMANode is the NUMA node of the PCI device without any NUMA affinity
+// (e.g., single NUMA node system, or the platform firmware does not report it).
+const NoNUMANode = -1
+
+// GetNUMAAffinity returns the NUMA node that the PCI device is attached to.
+// The PCI bus ID is in the "domain:bus:device.function" format (e.g., "0000:3b:00.0").
+// Returns NoNUMANode with no error, if the kernel does not report the NUMA node.
+func GetNUMAAffinity(pciBusID string) (int, error) {
+	return getNUMAAffinity(DefaultSysfsDevicesDir, pciBusID)
+}
+
+func getNUMAAffinity(sysfsDir string, pciBusID string) (int, error) {
+	busID := NormalizeBusID(pciBusID)
+	if busID == "" {
+		return NoNUMANode, errors.New("empty PCI bus ID")
+	}
+
+	devDir := filepath.Join(sysfsDir, busID)
+	if _, err := os.Stat(devDir); err != nil {
+		return NoNUMANode, fmt.Errorf("failed to find PCI device %q: %w", busID, err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(devDir, "numa_node"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NoNUMANode, nil
+		}
+		return NoNUMANode, fmt.Errorf("failed to read NUMA node for PCI device %q: %w", busID, err)
+	}
+
+	node, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return NoNUMANode, fmt.Errorf("failed to parse NUMA node for PCI device %q: %w", busID, err)
+	}
+	if node < 0 {
+		return NoNUMANode, nil
+	}
+	return node, nil
+}
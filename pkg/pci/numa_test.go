@@ -0,0 +1,30 @@
+package pci
+
+import "testing"
+
+func TestGetNUMAAffinity(t *testing.T) {
+	tests := []struct {
+		name     string
+		busID    string
+		expected int
+		wantErr  bool
+	}{
+		{name: "node 0", busID: "0000:3b:00.0", expected: 0},
+		{name: "node 1 with NVML bus ID", busID: "00000000:5E:00.0", expected: 1},
+		{name: "no NUMA node reported", busID: "0000:d8:00.0", expected: NoNUMANode},
+		{name: "no numa_node file", busID: "0000:86:00.0", expected: NoNUMANode},
+		{name: "device not found", busID: "0000:af:00.0", expected: NoNUMANode, wantErr: true},
+		{name: "empty bus ID", busID: "", expected: NoNUMANode, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := getNUMAAffinity("testdata/sysfs", tt.busID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getNUMAAffinity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if node != tt.expected {
+				t.Errorf("getNUMAAffinity() = %d, want %d", node, tt.expected)
+			}
+		})
+	}
+}
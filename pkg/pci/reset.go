@@ -0,0 +1,76 @@
+package pci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSysfsDevicesDir is the sysfs directory of the PCI devices.
+const DefaultSysfsDevicesDir = "/sys/bus/pci/devices"
+
+// resetMethodBus is the reset method for the secondary bus reset (SBR)
+// in the sysfs "reset_method" file (e.g., "flr bus").
+// ref. https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-bus-pci
+const resetMethodBus = "bus"
+
+// SupportsSecondaryBusReset returns true if the PCI device supports
+// the secondary bus reset (SBR), meaning the device can be reset
+// without requiring a full system reboot.
+// The PCI bus ID is in the "domain:bus:device.function" format (e.g., "0000:3b:00.0").
+// Returns false with no error, if the kernel does not expose the reset methods.
+func SupportsSecondaryBusReset(pciBusID string) (bool, error) {
+	return supportsSecondaryBusReset(DefaultSysfsDevicesDir, pciBusID)
+}
+
+func supportsSecondaryBusReset(sysfsDir string, pciBusID string) (bool, error) {
+	busID := NormalizeBusID(pciBusID)
+	if busID == "" {
+		return false, errors.New("empty PCI bus ID")
+	}
+
+	devDir := filepath.Join(sysfsDir, busID)
+	if _, err := os.Stat(devDir); err != nil {
+		return false, fmt.Errorf("failed to find PCI device %q: %w", busID, err)
+	}
+
+	// "reset_method" is only available since Linux 5.15
+	b, err := os.ReadFile(filepath.Join(devDir, "reset_method"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read reset method for PCI device %q: %w", busID, err)
+	}
+
+	for _, method := range strings.Fields(string(b)) {
+		if method == resetMethodBus {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NormalizeBusID converts the PCI bus ID into the sysfs format
+// (e.g., "00000000:3B:00.0" from NVML into "0000:3b:00.0").
+func NormalizeBusID(pciBusID string) string {
+	id := strings.ToLower(strings.TrimSpace(pciBusID))
+	if id == "" {
+		return ""
+	}
+
+	domain, rest, found := strings.Cut(id, ":")
+	if !found {
+		return id
+	}
+	if strings.Count(rest, ":") == 0 {
+		// no domain (e.g., "3b:00.0")
+		return "0000:" + id
+	}
+	if len(domain) > 4 {
+		domain = domain[len(domain)-4:]
+	}
+	return domain + ":" + rest
+}
@@ -0,0 +1,48 @@
+package pci
+
+import "testing"
+
+func TestSupportsSecondaryBusReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		busID    string
+		expected bool
+		wantErr  bool
+	}{
+		{name: "supported", busID: "0000:3b:00.0", expected: true},
+		{name: "supported with NVML bus ID", busID: "00000000:3B:00.0", expected: true},
+		{name: "unsupported", busID: "0000:5e:00.0", expected: false},
+		{name: "no reset method", busID: "0000:86:00.0", expected: false},
+		{name: "device not found", busID: "0000:af:00.0", wantErr: true},
+		{name: "empty bus ID", busID: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, err := supportsSecondaryBusReset("testdata/sysfs", tt.busID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("supportsSecondaryBusReset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if supported != tt.expected {
+				t.Errorf("supportsSecondaryBusReset() = %v, want %v", supported, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeBusID(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "0000:3b:00.0", expected: "0000:3b:00.0"},
+		{input: "00000000:3B:00.0", expected: "0000:3b:00.0"},
+		{input: "3b:00.0", expected: "0000:3b:00.0"},
+		{input: " 0000:3B:00.0\n", expected: "0000:3b:00.0"},
+		{input: "", expected: ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeBusID(tt.input); got != tt.expected {
+			t.Errorf("NormalizeBusID(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
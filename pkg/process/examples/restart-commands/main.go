@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/leptonai/gpud/pkg/process"
@@ -44,15 +43,15 @@ func main() {
 
 	for i := 0; i < 3; i++ {
 		select {
-		case err := <-p.Wait():
-			if err == nil {
+		case res := <-p.WaitResult():
+			if res.Err == nil {
 				panic("expected error")
 			}
-			if strings.Contains(err.Error(), "exit status 1") {
-				fmt.Println(err)
+			if res.ExitCode == 1 {
+				fmt.Println(res.Err)
 				continue
 			}
-			panic(err)
+			panic(res.Err)
 
 		case <-time.After(2 * time.Second):
 			panic("timeout")
@@ -60,9 +59,9 @@ func main() {
 	}
 
 	select {
-	case err := <-p.Wait():
-		if err != nil {
-			fmt.Println("wait error:", err)
+	case res := <-p.WaitResult():
+		if res.Err != nil {
+			fmt.Println("wait error:", res.Err)
 		}
 	case <-time.After(2 * time.Second):
 		panic("timeout")
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 )
@@ -15,13 +16,26 @@ type Op struct {
 	labels map[string]string
 
 	envs       []string
+	inheritEnv *bool
+	workDir    string
 	outputFile *os.File
 
+	rotatingOutputFile     string
+	rotatingOutputMaxBytes int64
+	rotatingOutputMaxFiles int
+
 	commandsToRun           [][]string
 	bashScriptContentsToRun string
 	runAsBashScript         bool
+	scriptInterpreter       string
 
 	restartConfig *RestartConfig
+
+	idleTimeout time.Duration
+
+	niceness *int
+
+	resourceLimits *ResourceLimits
 }
 
 func (op *Op) applyOpts(opts []OpOption) error {
@@ -61,10 +75,46 @@ func (op *Op) applyOpts(opts []OpOption) error {
 	if op.restartConfig != nil && op.restartConfig.Interval == 0 {
 		op.restartConfig.Interval = 5 * time.Second
 	}
+	if op.restartConfig != nil {
+		if op.restartConfig.BackoffMultiplier != 0 && op.restartConfig.BackoffMultiplier < 1 {
+			return fmt.Errorf("invalid restart backoff multiplier: %v", op.restartConfig.BackoffMultiplier)
+		}
+		if op.restartConfig.MaxInterval < 0 {
+			return fmt.Errorf("invalid restart max interval: %v", op.restartConfig.MaxInterval)
+		}
+		if op.restartConfig.MaxInterval > 0 && op.restartConfig.MaxInterval < op.restartConfig.Interval {
+			return fmt.Errorf("restart max interval %v is less than the interval %v", op.restartConfig.MaxInterval, op.restartConfig.Interval)
+		}
+		if op.restartConfig.StableWindow < 0 {
+			return fmt.Errorf("invalid restart stable window: %v", op.restartConfig.StableWindow)
+		}
+	}
+
+	if op.rotatingOutputFile != "" {
+		if op.outputFile != nil {
+			return errors.New("cannot set both output file and rotating output file")
+		}
+		if op.rotatingOutputMaxBytes <= 0 {
+			return fmt.Errorf("invalid rotating output file max bytes: %d", op.rotatingOutputMaxBytes)
+		}
+		if op.rotatingOutputMaxFiles < 0 {
+			return fmt.Errorf("invalid rotating output file max files: %d", op.rotatingOutputMaxFiles)
+		}
+	}
+
+	if op.idleTimeout < 0 {
+		return fmt.Errorf("invalid idle timeout: %v", op.idleTimeout)
+	}
+	if op.niceness != nil && (*op.niceness < MinNiceness || *op.niceness > MaxNiceness) {
+		return fmt.Errorf("invalid niceness %d (must be between %d and %d)", *op.niceness, MinNiceness, MaxNiceness)
+	}
 
 	if op.bashScriptContentsToRun != "" && !op.runAsBashScript {
 		op.runAsBashScript = true
 	}
+	if op.scriptInterpreter == "" {
+		op.scriptInterpreter = DefaultScriptInterpreter
+	}
 
 	return nil
 }
@@ -86,6 +136,70 @@ func WithEnvs(envs ...string) OpOption {
 	}
 }
 
+// Add new environment variables to the process.
+// The variables overwrite the inherited ones with the same name.
+func WithEnv(envs map[string]string) OpOption {
+	return func(op *Op) {
+		keys := make([]string, 0, len(envs))
+		for k := range envs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			op.envs = append(op.envs, k+"="+envs[k])
+		}
+	}
+}
+
+// Set false to not inherit the environment variables of the current process,
+// in which case only the variables set via WithEnv/WithEnvs plus "PATH" are set.
+// Default is true.
+func WithInheritEnv(inherit bool) OpOption {
+	return func(op *Op) {
+		op.inheritEnv = &inherit
+	}
+}
+
+// envsToSet returns the environment variables for the process.
+// Returns nil if the process should simply inherit the current environment.
+func (op *Op) envsToSet() []string {
+	inherit := op.inheritEnv == nil || *op.inheritEnv
+	if inherit && len(op.envs) == 0 {
+		return nil
+	}
+
+	var base []string
+	if inherit {
+		base = os.Environ()
+	} else if path, ok := os.LookupEnv("PATH"); ok {
+		base = []string{"PATH=" + path}
+	}
+
+	overwritten := make(map[string]struct{}, len(op.envs))
+	for _, env := range op.envs {
+		overwritten[strings.SplitN(env, "=", 2)[0]] = struct{}{}
+	}
+
+	envs := make([]string, 0, len(base)+len(op.envs))
+	for _, env := range base {
+		if _, ok := overwritten[strings.SplitN(env, "=", 2)[0]]; ok {
+			continue
+		}
+		envs = append(envs, env)
+	}
+	return append(envs, op.envs...)
+}
+
+// Sets the working directory of the process,
+// for both the commands and the bash script.
+// If not set, the process runs in the current working directory.
+func WithWorkDir(dir string) OpOption {
+	return func(op *Op) {
+		op.workDir = dir
+	}
+}
+
 // Add a new command to run.
 func WithCommand(args ...string) OpOption {
 	return func(op *Op) {
@@ -121,6 +235,22 @@ func WithOutputFile(file *os.File) OpOption {
 	}
 }
 
+// Sets the file path to which stderr and stdout will be written,
+// rotating the file when it would exceed "maxBytes" (e.g., for long-running streamed processes).
+// On rotation, the file is renamed to "path.1" (and "path.1" to "path.2", and so on),
+// keeping at most "maxFiles" rotated files in addition to the current one.
+// The rotation is safe while the process is writing, and persists across the restarts.
+// The stdout/stderr readers are not available with the rotating output file,
+// thus read the files directly.
+// Cannot be used with WithOutputFile.
+func WithRotatingOutputFile(path string, maxBytes int64, maxFiles int) OpOption {
+	return func(op *Op) {
+		op.rotatingOutputFile = path
+		op.rotatingOutputMaxBytes = maxBytes
+		op.rotatingOutputMaxFiles = maxFiles
+	}
+}
+
 // Set true to run commands as a bash script.
 // This is useful for running multiple/complicated commands.
 func WithRunAsBashScript() OpOption {
@@ -129,6 +259,20 @@ func WithRunAsBashScript() OpOption {
 	}
 }
 
+// DefaultScriptInterpreter is the default interpreter to run the script
+// with WithRunAsBashScript and WithBashScriptContentsToRun.
+const DefaultScriptInterpreter = "/bin/bash"
+
+// Sets the interpreter (e.g., "/bin/sh", "zsh") to run the script
+// with WithRunAsBashScript and WithBashScriptContentsToRun.
+// A name without a path separator is looked up in PATH on start.
+// Default is DefaultScriptInterpreter.
+func WithScriptInterpreter(path string) OpOption {
+	return func(op *Op) {
+		op.scriptInterpreter = path
+	}
+}
+
 // Configures the process restart behavior.
 // If the process exits with a non-zero exit code, stdout/stderr pipes may not work.
 func WithRestartConfig(config RestartConfig) OpOption {
@@ -137,6 +281,71 @@ func WithRestartConfig(config RestartConfig) OpOption {
 	}
 }
 
+// Aborts the process if no output is read from the stdout/stderr readers
+// for the given duration (e.g., a script hanging without any output).
+// The idle timer is reset on every read of the stdout/stderr readers
+// (including the "Read" helper), and is independent of the context timeout.
+// The process "Wait" returns ErrProcessIdleTimeout when aborted by the idle timeout.
+// Disabled if zero.
+func WithIdleTimeout(timeout time.Duration) OpOption {
+	return func(op *Op) {
+		op.idleTimeout = timeout
+	}
+}
+
+const (
+	// MinNiceness is the highest scheduling priority (requires the CAP_SYS_NICE capability on Linux).
+	MinNiceness = -20
+	// MaxNiceness is the lowest scheduling priority.
+	MaxNiceness = 19
+)
+
+// Sets the niceness of the process (e.g., 10 to run the background maintenance scripts
+// at the lower CPU priority), applied on start (and on each restart) via "setpriority"
+// to the process group of the command, so the processes spawned by the command
+// (e.g., the commands in the bash script) get the niceness, even if spawned
+// before the niceness is applied. Must be between MinNiceness and MaxNiceness.
+// Lowering the niceness below the current one requires the privileges, otherwise
+// the process is killed and "Start" returns ErrNicenessPermissionDenied.
+// No-op on the platforms without "setpriority" (e.g., Windows).
+// If not set, the process inherits the niceness of the current process.
+func WithNiceness(n int) OpOption {
+	return func(op *Op) {
+		op.niceness = &n
+	}
+}
+
+// ResourceLimits is the resource limits (rlimits) of the process.
+// Zero values are not limited (inherits the limit of the current process).
+type ResourceLimits struct {
+	// MaxMemoryBytes is the maximum size of the virtual memory (address space) in bytes
+	// (RLIMIT_AS). The allocations beyond the limit fail, which usually
+	// terminates the process (e.g., "bash: xmalloc: cannot allocate").
+	MaxMemoryBytes uint64
+	// MaxCPUSeconds is the maximum CPU time in seconds (RLIMIT_CPU).
+	// The process is terminated by SIGXCPU when exceeded,
+	// and "Wait" returns ErrProcessCPULimitExceeded.
+	MaxCPUSeconds uint64
+	// MaxOpenFiles is the maximum number of open file descriptors (RLIMIT_NOFILE).
+	MaxOpenFiles uint64
+}
+
+// Sets the resource limits of the process (e.g., to prevent a runaway script
+// from exhausting the node memory), applied on start (and on each restart)
+// by running the command via the "prlimit" command, which sets the limits
+// before executing the command. The processes spawned by the command
+// (e.g., the commands in the bash script) inherit the limits,
+// and the limits are enforced per process.
+// "Start" returns an error if the "prlimit" command is not found.
+// Raising the limits above the hard limits of the current process requires the privileges,
+// otherwise "prlimit" fails and the process exits with the error.
+// No-op on the platforms without "prlimit" (e.g., macOS, Windows).
+func WithResourceLimits(limits ResourceLimits) OpOption {
+	return func(op *Op) {
+		op.resourceLimits = &limits
+	}
+}
+
 func commandExists(name string) bool {
 	p, err := exec.LookPath(name)
 	if err != nil {
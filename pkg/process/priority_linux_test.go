@@ -0,0 +1,143 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessWithNiceness(t *testing.T) {
+	p, err := New(
+		WithCommand("sleep", "10"),
+		WithNiceness(10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	nice, err := readNiceness(p.PID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nice != 10 {
+		t.Fatalf("expected niceness 10, got %d", nice)
+	}
+}
+
+func TestProcessWithNicenessProcessGroup(t *testing.T) {
+	// the child is spawned right away, possibly before the niceness is applied
+	p, err := New(
+		WithBashScriptContentsToRun(`sleep 10 & echo $!; wait`),
+		WithNiceness(10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// stop reading once the child pid is printed
+	readCtx, readCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer readCancel()
+
+	var childPID int
+	if err := Read(
+		readCtx,
+		p,
+		WithReadStdout(),
+		WithProcessLine(func(line string) {
+			if childPID == 0 {
+				childPID, _ = strconv.Atoi(strings.TrimSpace(line))
+				readCancel()
+			}
+		}),
+	); err != nil && childPID == 0 {
+		t.Fatal(err)
+	}
+	if childPID == 0 {
+		t.Fatal("child pid not found")
+	}
+
+	nice, err := readNiceness(int32(childPID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nice != 10 {
+		t.Fatalf("expected child niceness 10, got %d", nice)
+	}
+}
+
+func TestProcessWithNicenessPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("skipping test as root can lower the niceness")
+	}
+
+	p, err := New(
+		WithCommand("sleep", "10"),
+		WithNiceness(MinNiceness),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = p.Start(ctx)
+	if !errors.Is(err, ErrNicenessPermissionDenied) {
+		t.Fatalf("expected ErrNicenessPermissionDenied, got %v", err)
+	}
+}
+
+// readNiceness reads the niceness from the 19th field of "/proc/[pid]/stat".
+// ref. https://man7.org/linux/man-pages/man5/proc_pid_stat.5.html
+func readNiceness(pid int32) (int, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// the command name may contain spaces and parentheses
+	s := string(b)
+	idx := strings.LastIndex(s, ")")
+	if idx < 0 {
+		return 0, fmt.Errorf("unexpected stat %q", s)
+	}
+
+	// fields after the command name start from the 3rd field (state)
+	fields := strings.Fields(s[idx+1:])
+	if len(fields) < 17 {
+		return 0, fmt.Errorf("unexpected stat %q", s)
+	}
+	return strconv.Atoi(fields[16])
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// ErrNicenessPermissionDenied is returned by "Start" when the niceness set via WithNiceness
+// cannot be applied without the privileges (e.g., negative niceness without CAP_SYS_NICE).
+var ErrNicenessPermissionDenied = errors.New("permission denied to set niceness")
+
+// setNiceness sets the scheduling priority of the process group led by the process
+// (see setProcessGroup), so that the processes spawned by the command
+// before the priority is set are also covered.
+func setNiceness(pid int, n int) error {
+	err := syscall.Setpriority(syscall.PRIO_PGRP, pid, n)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES) {
+		return fmt.Errorf("%w: niceness %d for process group %d (%v)", ErrNicenessPermissionDenied, n, pid, err)
+	}
+	return fmt.Errorf("failed to set niceness %d for process group %d: %w", n, pid, err)
+}
@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package process
+
+import (
+	"errors"
+
+	"github.com/leptonai/gpud/log"
+)
+
+// ErrNicenessPermissionDenied is never returned on Windows, where the niceness is not supported.
+var ErrNicenessPermissionDenied = errors.New("permission denied to set niceness")
+
+// setNiceness is a no-op, as Windows has no "setpriority"
+// (the priority classes are not mapped from the niceness).
+func setNiceness(pid int, n int) error {
+	log.Logger.Warnw("niceness not supported on windows, ignoring", "pid", pid, "niceness", n)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -36,6 +37,19 @@ type Process interface {
 	// If the command completes successfully, the error will be nil.
 	Wait() <-chan error
 
+	// Waits for the process to exit and returns the result with the exit code
+	// and the terminating signal, if any, so that the callers do not need to
+	// parse the error message (e.g., "exit status 1").
+	// Receives the same number of results as "Wait", one per process exit.
+	WaitResult() <-chan ProcessResult
+
+	// Returns a channel that receives the process state transitions
+	// (e.g., starting, running, restarting, aborted, exited).
+	// The channel is closed when the process finally terminates.
+	// Sends never block the process execution: if the buffer is full,
+	// the oldest state is dropped.
+	StatusUpdates() <-chan ProcessState
+
 	// Returns the current pid of the process.
 	PID() int32
 
@@ -71,9 +85,111 @@ type RestartConfig struct {
 	// Set the maximum number of restarts.
 	Limit int
 	// Set the interval between restarts.
+	// Used as the initial interval if the backoff multiplier is set.
 	Interval time.Duration
+
+	// Set the multiplier (> 1) to back off the restart interval exponentially,
+	// multiplying the interval after every restart.
+	// Zero to restart at the fixed interval.
+	BackoffMultiplier float64
+	// Set the maximum interval between restarts when backing off.
+	// Zero for no maximum.
+	MaxInterval time.Duration
+	// Set the duration a run must exceed to be considered stable,
+	// resetting the backed-off interval to the initial interval.
+	// Zero to never reset.
+	StableWindow time.Duration
+}
+
+// restartBackoff tracks the interval between restarts.
+type restartBackoff struct {
+	cfg      *RestartConfig
+	interval time.Duration
+}
+
+func newRestartBackoff(cfg *RestartConfig) *restartBackoff {
+	return &restartBackoff{cfg: cfg, interval: cfg.Interval}
+}
+
+// next returns the interval to wait before the next restart,
+// given how long the last run lasted before exiting.
+func (b *restartBackoff) next(ranFor time.Duration) time.Duration {
+	if b.cfg.BackoffMultiplier <= 1 {
+		return b.cfg.Interval
+	}
+
+	if b.cfg.StableWindow > 0 && ranFor >= b.cfg.StableWindow {
+		b.interval = b.cfg.Interval
+	}
+	interval := b.interval
+
+	b.interval = time.Duration(float64(b.interval) * b.cfg.BackoffMultiplier)
+	if b.cfg.MaxInterval > 0 && b.interval > b.cfg.MaxInterval {
+		b.interval = b.cfg.MaxInterval
+	}
+	return interval
+}
+
+// ProcessState is the state of the process.
+type ProcessState string
+
+const (
+	// The process command is being started.
+	ProcessStateStarting ProcessState = "starting"
+	// The process command has been started and is running.
+	ProcessStateRunning ProcessState = "running"
+	// The process command exited with an error and is to be restarted.
+	ProcessStateRestarting ProcessState = "restarting"
+	// The process was aborted (e.g., context canceled or closed).
+	ProcessStateAborted ProcessState = "aborted"
+	// The process exited and will not be restarted.
+	ProcessStateExited ProcessState = "exited"
+)
+
+// ProcessResult is the result of the process exit.
+type ProcessResult struct {
+	// The exit code of the process.
+	// 0 if the process exits successfully.
+	// -1 if the process is terminated by a signal or fails to wait.
+	ExitCode int
+	// The error from waiting for the process, same as the one from "Wait".
+	Err error
+	// The signal that terminated the process, if any.
+	Signal os.Signal
 }
 
+// newProcessResult creates the process result from the error returned by the command wait.
+func newProcessResult(err error) ProcessResult {
+	if err == nil {
+		return ProcessResult{ExitCode: 0}
+	}
+
+	ret := ProcessResult{ExitCode: -1, Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		ret.ExitCode = exitErr.ExitCode()
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			ret.Signal = ws.Signal()
+		}
+	}
+	return ret
+}
+
+// ErrProcessIdleTimeout is returned by "Wait" when the process is aborted
+// for not producing any output within the idle timeout.
+var ErrProcessIdleTimeout = errors.New("process aborted by idle timeout")
+
+// ErrScriptInterpreterNotFound is returned by "Start" when the script
+// interpreter set via WithScriptInterpreter cannot be found.
+var ErrScriptInterpreterNotFound = errors.New("script interpreter not found")
+
+// ErrProcessCPULimitExceeded is returned by "Wait" when the process is terminated
+// for exceeding the CPU time limit set via WithResourceLimits.
+var ErrProcessCPULimitExceeded = errors.New("process terminated by cpu time limit")
+
+// defaultStatusUpdatesBuffer is the buffer size of the status updates channel.
+const defaultStatusUpdatesBuffer = 32
+
 type process struct {
 	labels map[string]string
 
@@ -91,17 +207,35 @@ type process struct {
 
 	// error streaming channel, closed on command exit
 	errc chan error
+	// result streaming channel, closed on command exit
+	resultc chan ProcessResult
+
+	// state streaming channel, closed on command exit
+	statuscMu sync.Mutex
+	statusc   chan ProcessState
+
+	// closed on command exit
+	exitc chan struct{}
+
+	idleTimeout  time.Duration
+	lastActivity atomic.Int64 // unix nano of the last read from stdout/stderr
+	idleTimedOut atomic.Bool
 
 	pid         int32
 	commandArgs []string
 	envs        []string
+	workDir     string
 	runBashFile *os.File
 
 	outputFile       *os.File
+	rotatingOutput   *rotatingFile
 	stdoutReadCloser io.ReadCloser
 	stderrReadCloser io.ReadCloser
 
 	restartConfig *RestartConfig
+
+	niceness       *int
+	resourceLimits *ResourceLimits
 }
 
 func New(opts ...OpOption) (Process, error) {
@@ -124,14 +258,14 @@ func New(opts ...OpOption) (Process, error) {
 				return nil, err
 			}
 		} else {
-			if _, err := bashFile.Write([]byte(bashScriptHeader)); err != nil {
+			if _, err := bashFile.Write([]byte(scriptHeader(op.scriptInterpreter))); err != nil {
 				return nil, err
 			}
 		}
 		defer func() {
 			_ = bashFile.Sync()
 		}()
-		cmdArgs = []string{"bash", bashFile.Name()}
+		cmdArgs = []string{op.scriptInterpreter, bashFile.Name()}
 	}
 
 	for _, args := range op.commandsToRun {
@@ -148,6 +282,15 @@ func New(opts ...OpOption) (Process, error) {
 		}
 	}
 
+	var rotatingOutput *rotatingFile
+	if op.rotatingOutputFile != "" {
+		var err error
+		rotatingOutput, err = openRotatingFile(op.rotatingOutputFile, op.rotatingOutputMaxBytes, op.rotatingOutputMaxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open rotating output file: %w", err)
+		}
+	}
+
 	errcBuffer := 1
 	if op.restartConfig != nil && op.restartConfig.OnError && op.restartConfig.Limit > 0 {
 		errcBuffer = op.restartConfig.Limit
@@ -159,14 +302,27 @@ func New(opts ...OpOption) (Process, error) {
 		started: false,
 		aborted: false,
 
-		errc: make(chan error, errcBuffer),
-
-		commandArgs: cmdArgs,
-		envs:        op.envs,
-		runBashFile: bashFile,
-		outputFile:  op.outputFile,
+		// one more than the max number of exits (initial run + restarts),
+		// so that the callers only waiting on either "Wait" or "WaitResult"
+		// never block the process
+		errc:    make(chan error, errcBuffer+1),
+		resultc: make(chan ProcessResult, errcBuffer+1),
+		statusc: make(chan ProcessState, defaultStatusUpdatesBuffer),
+		exitc:   make(chan struct{}),
+
+		commandArgs:    cmdArgs,
+		envs:           op.envsToSet(),
+		workDir:        op.workDir,
+		runBashFile:    bashFile,
+		outputFile:     op.outputFile,
+		rotatingOutput: rotatingOutput,
 
 		restartConfig: op.restartConfig,
+
+		idleTimeout: op.idleTimeout,
+
+		niceness:       op.niceness,
+		resourceLimits: op.resourceLimits,
 	}, nil
 }
 
@@ -204,13 +360,18 @@ func (p *process) Start(ctx context.Context) error {
 	p.ctx = cctx
 	p.cancel = ccancel
 
+	p.updateStatus(ProcessStateStarting)
 	if err := p.startCommand(); err != nil {
 		return err
 	}
+	p.updateStatus(ProcessStateRunning)
 
 	go func() {
 		p.watchCmd()
 	}()
+	if p.idleTimeout > 0 {
+		go p.watchIdle()
+	}
 
 	return nil
 }
@@ -224,8 +385,35 @@ func (p *process) Started() bool {
 
 func (p *process) startCommand() error {
 	log.Logger.Debugw("starting command", "command", p.commandArgs)
-	p.cmd = exec.CommandContext(p.ctx, p.commandArgs[0], p.commandArgs[1:]...)
+	if p.workDir != "" {
+		info, err := os.Stat(p.workDir)
+		if err != nil {
+			return fmt.Errorf("failed to stat work dir %q: %w", p.workDir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("work dir %q is not a directory", p.workDir)
+		}
+	}
+
+	if p.runBashFile != nil {
+		if _, err := exec.LookPath(p.commandArgs[0]); err != nil {
+			return fmt.Errorf("%w: %q (%v)", ErrScriptInterpreterNotFound, p.commandArgs[0], err)
+		}
+	}
+
+	args := p.commandArgs
+	if p.resourceLimits != nil {
+		var err error
+		args, err = withResourceLimits(args, *p.resourceLimits)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.cmd = exec.CommandContext(p.ctx, args[0], args[1:]...)
 	p.cmd.Env = p.envs
+	p.cmd.Dir = p.workDir
+	setProcessGroup(p.cmd)
 
 	switch {
 	case p.outputFile != nil:
@@ -235,6 +423,11 @@ func (p *process) startCommand() error {
 		p.stdoutReadCloser = p.outputFile
 		p.stderrReadCloser = p.outputFile
 
+	case p.rotatingOutput != nil:
+		// the same writer for both, so that "exec" serializes the writes
+		p.cmd.Stdout = p.rotatingOutput
+		p.cmd.Stderr = p.rotatingOutput
+
 	default:
 		var err error
 		p.stdoutReadCloser, err = p.cmd.StdoutPipe()
@@ -250,7 +443,16 @@ func (p *process) startCommand() error {
 	if err := p.cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start command: %w", err)
 	}
+	if p.niceness != nil {
+		if err := setNiceness(p.cmd.Process.Pid, *p.niceness); err != nil {
+			// do not keep running at the unexpected priority
+			_ = signalProcessGroup(p.cmd, syscall.SIGKILL)
+			_ = p.cmd.Wait()
+			return err
+		}
+	}
 	atomic.StoreInt32(&p.pid, int32(p.cmd.Process.Pid))
+	p.lastActivity.Store(time.Now().UnixNano())
 
 	p.startedMu.Lock()
 	p.started = true
@@ -265,14 +467,70 @@ func (p *process) Wait() <-chan error {
 	return p.errc
 }
 
+// Returns a channel where the command watcher sends the exit result.
+// The channel is closed on the command exit.
+func (p *process) WaitResult() <-chan ProcessResult {
+	return p.resultc
+}
+
+// Returns a channel where the command watcher sends the process state transitions.
+// The channel is closed on the command exit.
+func (p *process) StatusUpdates() <-chan ProcessState {
+	return p.statusc
+}
+
+// updateStatus sends the state to the status updates channel without blocking.
+// If the channel buffer is full, the oldest state is dropped.
+func (p *process) updateStatus(state ProcessState) {
+	p.statuscMu.Lock()
+	defer p.statuscMu.Unlock()
+
+	for {
+		select {
+		case p.statusc <- state:
+			return
+		default:
+		}
+
+		// buffer is full, drop the oldest one
+		select {
+		case <-p.statusc:
+		default:
+		}
+	}
+}
+
 func (p *process) watchCmd() {
 	if p.cmd == nil {
 		return
 	}
 	defer func() {
+		if p.ctx.Err() != nil {
+			p.updateStatus(ProcessStateAborted)
+		} else {
+			p.updateStatus(ProcessStateExited)
+		}
+
+		p.statuscMu.Lock()
+		close(p.statusc)
+		p.statuscMu.Unlock()
+
 		close(p.errc)
+		close(p.resultc)
+		close(p.exitc)
+
+		// no more writes once the command exits
+		if p.rotatingOutput != nil {
+			_ = p.rotatingOutput.Close()
+		}
 	}()
 
+	var backoff *restartBackoff
+	if p.restartConfig != nil {
+		backoff = newRestartBackoff(p.restartConfig)
+	}
+	runStart := time.Now()
+
 	restartCount := 0
 	for {
 		if p.cmd.Process == nil { // Wait cannot be called if the process is not started yet
@@ -293,19 +551,22 @@ func (p *process) watchCmd() {
 		case <-p.ctx.Done():
 			// command aborted (e.g., Stop called)
 			// cmd.Wait will return error
-			err := <-errc
-			p.errc <- err
+			err := p.wrapIdleTimeoutErr(<-errc)
+			p.sendResult(err)
 			return
 
 		case err := <-errc:
-			p.errc <- err
+			err = p.wrapIdleTimeoutErr(p.wrapCPULimitErr(err))
+			p.sendResult(err)
 
 			if err == nil {
 				log.Logger.Debugw("process exited successfully")
 				return
 			}
 
-			if exitErr, ok := err.(*exec.ExitError); ok {
+			if errors.Is(err, ErrProcessCPULimitExceeded) {
+				log.Logger.Warnw("command was terminated by the cpu time limit", "cmd", p.cmd.String(), "maxCPUSeconds", p.resourceLimits.MaxCPUSeconds)
+			} else if exitErr, ok := err.(*exec.ExitError); ok {
 				if exitErr.ExitCode() == -1 {
 					if p.ctx.Err() != nil {
 						log.Logger.Debugw("command was terminated (exit code -1) by the root context cancellation", "cmd", p.cmd.String(), "contextError", p.ctx.Err())
@@ -328,23 +589,88 @@ func (p *process) watchCmd() {
 				log.Logger.Warnw("process exited with error, but restart limits reached", "restartCount", restartCount, "error", err)
 				return
 			}
+			p.updateStatus(ProcessStateRestarting)
 		}
 
+		interval := backoff.next(time.Since(runStart))
+		log.Logger.Debugw("restarting command", "cmd", p.cmd.String(), "interval", interval, "restartCount", restartCount)
+
 		select {
 		case <-p.ctx.Done():
 			return
-		case <-time.After(p.restartConfig.Interval):
+		case <-time.After(interval):
 		}
 
 		if err := p.startCommand(); err != nil {
 			log.Logger.Warnw("failed to restart command", "error", err)
 			return
 		}
+		p.updateStatus(ProcessStateRunning)
+		runStart = time.Now()
 
 		restartCount++
 	}
 }
 
+// sendResult sends the wait error and the exit result.
+func (p *process) sendResult(err error) {
+	p.resultc <- newProcessResult(err)
+	p.errc <- err
+}
+
+// watchIdle aborts the process if nothing is read from stdout/stderr
+// within the idle timeout.
+func (p *process) watchIdle() {
+	interval := p.idleTimeout / 10
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.exitc:
+			return
+		case <-ticker.C:
+		}
+
+		idle := time.Since(time.Unix(0, p.lastActivity.Load()))
+		if idle < p.idleTimeout {
+			continue
+		}
+
+		log.Logger.Warnw("aborting process with no output", "idleTimeout", p.idleTimeout, "cmd", p.commandArgs)
+		p.idleTimedOut.Store(true)
+		p.cancel()
+		return
+	}
+}
+
+func (p *process) wrapIdleTimeoutErr(err error) error {
+	if !p.idleTimedOut.Load() {
+		return err
+	}
+	if err == nil {
+		return ErrProcessIdleTimeout
+	}
+	return fmt.Errorf("%w (%v)", ErrProcessIdleTimeout, err)
+}
+
+// wrapCPULimitErr wraps the wait error with ErrProcessCPULimitExceeded
+// if the process is terminated by SIGXCPU, while keeping the exit error.
+func (p *process) wrapCPULimitErr(err error) error {
+	if err == nil || p.resourceLimits == nil || p.resourceLimits.MaxCPUSeconds == 0 {
+		return err
+	}
+	if !isCPULimitSignal(newProcessResult(err).Signal) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrProcessCPULimitExceeded, err)
+}
+
 func (p *process) Close(ctx context.Context) error {
 	p.startedMu.RLock()
 	started := p.started
@@ -371,8 +697,8 @@ func (p *process) Close(ctx context.Context) error {
 
 	if p.cmd.Process != nil {
 		finished := false
-		if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
-			if err.Error() == "os: process already finished" {
+		if err := signalProcessGroup(p.cmd, syscall.SIGTERM); err != nil {
+			if errors.Is(err, os.ErrProcessDone) {
 				finished = true
 			} else {
 				log.Logger.Warnw("failed to send SIGTERM to process", "error", err)
@@ -382,7 +708,7 @@ func (p *process) Close(ctx context.Context) error {
 			select {
 			case <-p.ctx.Done():
 			case <-time.After(3 * time.Second):
-				if err := p.cmd.Process.Kill(); err != nil {
+				if err := signalProcessGroup(p.cmd, syscall.SIGKILL); err != nil && !errors.Is(err, os.ErrProcessDone) {
 					log.Logger.Warnw("failed to send SIGKILL to process", "error", err)
 				}
 			}
@@ -443,9 +769,12 @@ func (p *process) StdoutReader() io.Reader {
 	defer p.cmdMu.RUnlock()
 
 	if p.outputFile != nil {
-		return p.outputFile
+		return p.trackActivity(p.outputFile)
+	}
+	if p.stdoutReadCloser == nil {
+		return nil
 	}
-	return p.stdoutReadCloser
+	return p.trackActivity(p.stdoutReadCloser)
 }
 
 func (p *process) StderrReader() io.Reader {
@@ -453,9 +782,55 @@ func (p *process) StderrReader() io.Reader {
 	defer p.cmdMu.RUnlock()
 
 	if p.outputFile != nil {
-		return p.outputFile
+		return p.trackActivity(p.outputFile)
+	}
+	if p.stderrReadCloser == nil {
+		return nil
+	}
+	return p.trackActivity(p.stderrReadCloser)
+}
+
+// trackActivity wraps the reader to reset the idle timer on every read,
+// if the idle timeout is configured.
+func (p *process) trackActivity(rd io.Reader) io.Reader {
+	if p.idleTimeout == 0 {
+		return rd
+	}
+	return &activityReader{rd: rd, lastActivity: &p.lastActivity}
+}
+
+type activityReader struct {
+	rd           io.Reader
+	lastActivity *atomic.Int64
+}
+
+func (r *activityReader) Read(b []byte) (int, error) {
+	n, err := r.rd.Read(b)
+	if n > 0 {
+		r.lastActivity.Store(time.Now().UnixNano())
+	}
+	return n, err
+}
+
+// scriptHeader returns the header for the script run by the interpreter.
+// "pipefail" is only set for the shells that support it.
+func scriptHeader(interpreter string) string {
+	if interpreter == DefaultScriptInterpreter {
+		return bashScriptHeader
 	}
-	return p.stderrReadCloser
+
+	header := "#!" + interpreter + "\n\n"
+	switch filepath.Base(interpreter) {
+	case "bash", "zsh", "ksh":
+		header += "# do not mask errors in a pipeline\nset -o pipefail\n\n"
+	}
+	return header + `# treat unset variables as an error
+set -o nounset
+
+# exit script whenever it errs
+set -o errexit
+
+`
 }
 
 const bashScriptHeader = `#!/bin/bash
@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group,
+// so that the processes spawned by the command (e.g., the commands in the bash script)
+// are also killed when the command is aborted or its context is canceled.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return signalProcessGroup(cmd, syscall.SIGKILL)
+	}
+}
+
+// signalProcessGroup sends the signal to the process group of the command.
+// Returns os.ErrProcessDone if no process in the group is running.
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) error {
+	if cmd.Process == nil {
+		return errors.New("process not started")
+	}
+	err := syscall.Kill(-cmd.Process.Pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
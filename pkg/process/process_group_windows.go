@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package process
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) error {
+	if cmd.Process == nil {
+		return errors.New("process not started")
+	}
+	if sig == syscall.SIGKILL {
+		return cmd.Process.Kill()
+	}
+	return cmd.Process.Signal(sig)
+}
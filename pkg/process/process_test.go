@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -348,6 +351,264 @@ func TestProcessWithRestarts(t *testing.T) {
 	}
 }
 
+func TestProcessWithRestartsWaitResultOnly(t *testing.T) {
+	p, err := New(
+		WithCommand("echo hello && exit 1"),
+		WithRunAsBashScript(),
+		WithRestartConfig(RestartConfig{
+			OnError:  true,
+			Limit:    3,
+			Interval: 10 * time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the initial run plus the restarts, without reading "Wait"
+	results := 0
+	for {
+		select {
+		case res, ok := <-p.WaitResult():
+			if !ok {
+				if results != 4 {
+					t.Fatalf("expected 4 results, got %d", results)
+				}
+				return
+			}
+			if res.ExitCode != 1 {
+				t.Fatalf("expected exit code 1, got %d", res.ExitCode)
+			}
+			results++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for the process to finish after %d results", results)
+		}
+	}
+}
+
+func TestProcessWithRestartsBackoff(t *testing.T) {
+	p, err := New(
+		WithCommand("echo 111 && exit 1"),
+		WithRunAsBashScript(),
+		WithRestartConfig(RestartConfig{
+			OnError:           true,
+			Limit:             4,
+			Interval:          100 * time.Millisecond,
+			BackoffMultiplier: 2,
+			MaxInterval:       400 * time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// exits 5 times (1 run + 4 restarts), backing off 100ms, 200ms, 400ms, 400ms
+	var exits []time.Time
+	for i := 0; i < 5; i++ {
+		select {
+		case err := <-p.Wait():
+			if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+				t.Fatalf("expected exit status 1, got %v", err)
+			}
+			exits = append(exits, time.Now())
+
+		case <-time.After(3 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 400 * time.Millisecond}
+	for i := 1; i < len(exits); i++ {
+		gap := exits[i].Sub(exits[i-1])
+		t.Logf("restart %d interval %v", i, gap)
+		if gap < expected[i-1] {
+			t.Errorf("restart %d: expected interval >= %v, got %v", i, expected[i-1], gap)
+		}
+	}
+	if exits[3].Sub(exits[2]) <= exits[1].Sub(exits[0]) {
+		t.Errorf("expected increasing intervals, got %v", exits)
+	}
+
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	// fixed interval when the multiplier is unset
+	b := newRestartBackoff(&RestartConfig{Interval: 100 * time.Millisecond})
+	for i := 0; i < 3; i++ {
+		if d := b.next(0); d != 100*time.Millisecond {
+			t.Fatalf("expected fixed interval 100ms, got %v", d)
+		}
+	}
+
+	b = newRestartBackoff(&RestartConfig{
+		Interval:          100 * time.Millisecond,
+		BackoffMultiplier: 2,
+		MaxInterval:       time.Second,
+		StableWindow:      time.Minute,
+	})
+	for _, expected := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		if d := b.next(time.Second); d != expected {
+			t.Fatalf("expected interval %v, got %v", expected, d)
+		}
+	}
+
+	// reset after a stable run
+	if d := b.next(2 * time.Minute); d != 100*time.Millisecond {
+		t.Fatalf("expected interval reset to 100ms, got %v", d)
+	}
+	if d := b.next(time.Second); d != 200*time.Millisecond {
+		t.Fatalf("expected interval 200ms after reset, got %v", d)
+	}
+}
+
+func TestProcessWithRestartsBackoffInvalid(t *testing.T) {
+	for _, cfg := range []RestartConfig{
+		{OnError: true, BackoffMultiplier: 0.5},
+		{OnError: true, Interval: time.Second, MaxInterval: time.Millisecond},
+		{OnError: true, MaxInterval: -time.Second},
+		{OnError: true, StableWindow: -time.Second},
+	} {
+		if _, err := New(WithCommand("echo", "hello"), WithRestartConfig(cfg)); err == nil {
+			t.Errorf("expected error for restart config %+v", cfg)
+		}
+	}
+}
+
+func TestProcessWithNicenessInvalid(t *testing.T) {
+	for _, n := range []int{MinNiceness - 1, MaxNiceness + 1} {
+		if _, err := New(WithCommand("echo", "hello"), WithNiceness(n)); err == nil {
+			t.Errorf("expected error for niceness %d", n)
+		}
+	}
+}
+
+func TestProcessStatusUpdatesWithRestarts(t *testing.T) {
+	p, err := New(
+		WithCommand("echo 111 && exit 1"),
+		WithRunAsBashScript(),
+		WithRestartConfig(RestartConfig{
+			OnError:  true,
+			Limit:    3,
+			Interval: 100 * time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// drain the error channel so the watcher does not block
+	go func() {
+		for range p.Wait() {
+		}
+	}()
+
+	var states []ProcessState
+	for {
+		select {
+		case state, ok := <-p.StatusUpdates():
+			if !ok {
+				expected := []ProcessState{
+					ProcessStateStarting,
+					ProcessStateRunning,
+					ProcessStateRestarting,
+					ProcessStateRunning,
+					ProcessStateRestarting,
+					ProcessStateRunning,
+					ProcessStateRestarting,
+					ProcessStateRunning,
+					ProcessStateExited,
+				}
+				if !reflect.DeepEqual(states, expected) {
+					t.Fatalf("expected %v, got %v", expected, states)
+				}
+				return
+			}
+			states = append(states, state)
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout, received states %v", states)
+		}
+	}
+}
+
+func TestProcessStatusUpdatesAborted(t *testing.T) {
+	p, err := New(
+		WithCommand("sleep", "10"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var states []ProcessState
+	for state := range p.StatusUpdates() {
+		states = append(states, state)
+	}
+	expected := []ProcessState{ProcessStateStarting, ProcessStateRunning, ProcessStateAborted}
+	if !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected %v, got %v", expected, states)
+	}
+}
+
+func TestProcessStatusUpdatesDropOldest(t *testing.T) {
+	p := &process{statusc: make(chan ProcessState, 2)}
+	p.updateStatus(ProcessStateStarting)
+	p.updateStatus(ProcessStateRunning)
+	p.updateStatus(ProcessStateExited)
+
+	if got := <-p.statusc; got != ProcessStateRunning {
+		t.Fatalf("expected %q, got %q", ProcessStateRunning, got)
+	}
+	if got := <-p.statusc; got != ProcessStateExited {
+		t.Fatalf("expected %q, got %q", ProcessStateExited, got)
+	}
+}
+
 func TestProcessSleep(t *testing.T) {
 	p, err := New(
 		WithCommand("sleep", "99999"),
@@ -379,6 +640,65 @@ func TestProcessSleep(t *testing.T) {
 	}
 }
 
+func TestProcessCloseKillsSpawnedProcesses(t *testing.T) {
+	p, err := New(
+		WithBashScriptContentsToRun(`#!/bin/bash
+
+sleep 99999 &
+echo $!
+wait
+`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(p.StdoutReader())
+	if !scanner.Scan() {
+		t.Fatalf("failed to read the spawned process pid: %v", scanner.Err())
+	}
+	var childPID int
+	if _, err := fmt.Sscanf(scanner.Text(), "%d", &childPID); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("pid: %d, spawned pid: %d", p.PID(), childPID)
+
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the spawned process is killed with the process group
+	// (either reaped, or a zombie if the reaper does not reap the orphans)
+	for i := 0; i < 30; i++ {
+		if !processRunning(childPID) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("spawned process %d still running after close", childPID)
+}
+
+func processRunning(pid int) bool {
+	if err := syscall.Kill(pid, 0); err != nil {
+		return false
+	}
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		// no procfs (e.g., darwin)
+		return true
+	}
+	// e.g., "123 (sleep) Z ..."
+	fields := strings.Fields(string(b[strings.LastIndex(string(b), ")")+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
+
 func TestProcessStream(t *testing.T) {
 	opts := []OpOption{
 		WithRunAsBashScript(),
@@ -420,3 +740,515 @@ func TestProcessStream(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestProcessIdleTimeoutStreaming(t *testing.T) {
+	p, err := New(
+		WithBashScriptContentsToRun(`for i in $(seq 1 15); do echo "line $i"; sleep 0.1; done`),
+		WithIdleTimeout(time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	lines := 0
+	if err := Read(
+		ctx,
+		p,
+		WithReadStdout(),
+		WithReadStderr(),
+		WithProcessLine(func(line string) {
+			lines++
+		}),
+		WithWaitForCmd(),
+	); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lines != 15 {
+		t.Fatalf("expected 15 lines, got %d", lines)
+	}
+}
+
+func TestProcessIdleTimeoutSilent(t *testing.T) {
+	p, err := New(
+		WithBashScriptContentsToRun(`echo "hello"; sleep 10`),
+		WithIdleTimeout(500*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	start := time.Now()
+	err = Read(
+		ctx,
+		p,
+		WithReadStdout(),
+		WithReadStderr(),
+		WithWaitForCmd(),
+	)
+	if !errors.Is(err, ErrProcessIdleTimeout) {
+		t.Fatalf("expected idle timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the process to be aborted by the idle timeout, took %v", elapsed)
+	}
+}
+
+func TestProcessWithEnv(t *testing.T) {
+	t.Setenv("FOO", "inherited")
+
+	tests := []struct {
+		name    string
+		opts    []OpOption
+		wantFoo string
+	}{
+		{
+			name:    "inherit",
+			wantFoo: "inherited",
+		},
+		{
+			name:    "inherit with overwrite",
+			opts:    []OpOption{WithEnv(map[string]string{"FOO": "overwritten"})},
+			wantFoo: "overwritten",
+		},
+		{
+			name:    "no inherit",
+			opts:    []OpOption{WithInheritEnv(false)},
+			wantFoo: "",
+		},
+		{
+			name:    "no inherit with env",
+			opts:    []OpOption{WithInheritEnv(false), WithEnv(map[string]string{"FOO": "bar"})},
+			wantFoo: "bar",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "process-test-*.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tmpFile.Name())
+			defer tmpFile.Close()
+
+			opts := append([]OpOption{
+				// "PATH" must be set even without inheriting the environment
+				WithCommand("sh", "-c", `echo "foo=${FOO:-}"; command -v sh >/dev/null && echo "path ok"`),
+				WithOutputFile(tmpFile),
+			}, tt.opts...)
+			p, err := New(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := p.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case err := <-p.Wait():
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout")
+			}
+
+			if err := p.Close(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := os.ReadFile(tmpFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := fmt.Sprintf("foo=%s\npath ok\n", tt.wantFoo)
+			if string(content) != expected {
+				t.Fatalf("expected %q, got %q", expected, string(content))
+			}
+		})
+	}
+}
+
+func TestProcessWithWorkDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bash := range []bool{false, true} {
+		t.Run(fmt.Sprintf("bash=%v", bash), func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "process-test-*.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tmpFile.Name())
+			defer tmpFile.Close()
+
+			opts := []OpOption{
+				WithCommand("pwd"),
+				WithWorkDir(dir),
+				WithOutputFile(tmpFile),
+			}
+			if bash {
+				opts = append(opts, WithRunAsBashScript())
+			}
+			p, err := New(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := p.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case err := <-p.Wait():
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout")
+			}
+
+			if err := p.Close(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := os.ReadFile(tmpFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(content)) != dir {
+				t.Fatalf("expected %q, got %q", dir, string(content))
+			}
+		})
+	}
+}
+
+func TestProcessWithWorkDirNotExist(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "does-not-exist")
+
+	p, err := New(
+		WithCommand("pwd"),
+		WithWorkDir(dir),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = p.Start(ctx)
+	if err == nil {
+		t.Fatal("expected error for nonexistent work dir")
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+	if !strings.Contains(err.Error(), dir) {
+		t.Fatalf("expected error to contain %q, got %v", dir, err)
+	}
+}
+
+func TestProcessWaitResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		command      string
+		expectedCode int
+		expectErr    bool
+	}{
+		{name: "clean exit", command: "exit 0", expectedCode: 0},
+		{name: "non-zero exit", command: "exit 1", expectedCode: 1, expectErr: true},
+		{name: "non-zero exit 3", command: "exit 3", expectedCode: 3, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(
+				WithCommand("bash", "-c", tt.command),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if err := p.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = p.Close(ctx)
+			}()
+
+			select {
+			case res := <-p.WaitResult():
+				if res.ExitCode != tt.expectedCode {
+					t.Fatalf("expected exit code %d, got %d (%v)", tt.expectedCode, res.ExitCode, res.Err)
+				}
+				if (res.Err != nil) != tt.expectErr {
+					t.Fatalf("expected error %v, got %v", tt.expectErr, res.Err)
+				}
+				if res.Signal != nil {
+					t.Fatalf("expected no signal, got %v", res.Signal)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+
+			// the error is also sent to "Wait"
+			select {
+			case err := <-p.Wait():
+				if (err != nil) != tt.expectErr {
+					t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+		})
+	}
+}
+
+func TestProcessWaitResultSignal(t *testing.T) {
+	p, err := New(
+		WithCommand("sleep", "10"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = p.Close(ctx)
+	}()
+
+	proc, err := os.FindProcess(int(p.PID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Signal(syscall.SIGKILL); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case res := <-p.WaitResult():
+		if res.ExitCode != -1 {
+			t.Fatalf("expected exit code -1, got %d", res.ExitCode)
+		}
+		if res.Err == nil {
+			t.Fatal("expected error")
+		}
+		if res.Signal != syscall.SIGKILL {
+			t.Fatalf("expected signal %v, got %v", syscall.SIGKILL, res.Signal)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestProcessScriptInterpreter(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("/bin/sh not found")
+	}
+
+	tests := []struct {
+		name string
+		opts []OpOption
+	}{
+		{
+			name: "script contents",
+			opts: []OpOption{WithBashScriptContentsToRun(`echo "bash=${BASH_VERSION:-none}"`)},
+		},
+		{
+			name: "commands as script",
+			opts: []OpOption{WithCommand(`echo "bash=${BASH_VERSION:-none}"`), WithRunAsBashScript()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, interp := range []string{DefaultScriptInterpreter, "/bin/sh"} {
+				f, err := os.CreateTemp(t.TempDir(), "out")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+
+				p, err := New(append(tt.opts, WithScriptInterpreter(interp), WithOutputFile(f))...)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := p.Start(ctx); err != nil {
+					t.Fatal(err)
+				}
+				select {
+				case err := <-p.Wait():
+					if err != nil {
+						t.Fatal(err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("timeout")
+				}
+				if err := p.Close(ctx); err != nil {
+					t.Fatal(err)
+				}
+
+				b, err := os.ReadFile(f.Name())
+				if err != nil {
+					t.Fatal(err)
+				}
+				out := strings.TrimSpace(string(b))
+
+				// BASH_VERSION is only set when running under bash
+				if interp == "/bin/sh" && out != "bash=none" {
+					t.Errorf("expected the script to run under /bin/sh, got %q", out)
+				}
+				if interp == DefaultScriptInterpreter && out == "bash=none" {
+					t.Errorf("expected the script to run under bash, got %q", out)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessScriptInterpreterNotFound(t *testing.T) {
+	p, err := New(
+		WithBashScriptContentsToRun(`echo hello`),
+		WithScriptInterpreter("/nonexistent/shell"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = p.Start(ctx)
+	if !errors.Is(err, ErrScriptInterpreterNotFound) {
+		t.Fatalf("expected %v, got %v", ErrScriptInterpreterNotFound, err)
+	}
+	_ = p.Close(ctx)
+}
+
+func TestProcessWithRotatingOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+
+	// 10 bytes per line, 2 lines per file
+	p, err := New(
+		WithBashScriptContentsToRun(`#!/bin/bash
+for i in $(seq 1 9); do
+  printf "line-%04d\n" "$i"
+done
+`),
+		WithRotatingOutputFile(path, 20, 3),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p.StdoutReader() != nil {
+		t.Fatal("expected no stdout reader with the rotating output file")
+	}
+
+	select {
+	case err := <-p.Wait():
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// lines 1-2 are removed with only 3 rotated files kept
+	// the oldest rotated file first
+	expected := []struct {
+		path    string
+		content string
+	}{
+		{path + ".3", "line-0003\nline-0004\n"},
+		{path + ".2", "line-0005\nline-0006\n"},
+		{path + ".1", "line-0007\nline-0008\n"},
+		{path, "line-0009\n"},
+	}
+	for _, e := range expected {
+		b, err := os.ReadFile(e.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != e.content {
+			t.Errorf("%s: expected %q, got %q", filepath.Base(e.path), e.content, string(b))
+		}
+	}
+	matches, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != len(expected) {
+		t.Errorf("expected %d files, got %v", len(expected), matches)
+	}
+}
+
+func TestProcessWithRotatingOutputFileInvalid(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "process-test-*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	path := filepath.Join(t.TempDir(), "output.log")
+	for _, opts := range [][]OpOption{
+		{WithRotatingOutputFile(path, 0, 1)},
+		{WithRotatingOutputFile(path, 10, -1)},
+		{WithRotatingOutputFile(path, 10, 1), WithOutputFile(tmpFile)},
+	} {
+		if _, err := New(append(opts, WithCommand("echo", "hello"))...); err == nil {
+			t.Errorf("expected error for options %d", len(opts))
+		}
+	}
+}
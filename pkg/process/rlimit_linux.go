@@ -0,0 +1,45 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// withResourceLimits returns the command args wrapped with "prlimit",
+// which sets the resource limits and then executes the command,
+// so the limits are in place before the command runs (same pid).
+// The soft and hard limits are set to the same value, except the CPU time limit
+// whose hard limit is one second later, so that the process gets SIGXCPU
+// (rather than SIGKILL) when exceeded.
+func withResourceLimits(args []string, limits ResourceLimits) ([]string, error) {
+	prlimit, err := exec.LookPath("prlimit")
+	if err != nil {
+		return nil, fmt.Errorf("prlimit not found to set the resource limits: %w", err)
+	}
+
+	wrapped := []string{prlimit}
+	if limits.MaxMemoryBytes > 0 {
+		wrapped = append(wrapped, fmt.Sprintf("--as=%d", limits.MaxMemoryBytes))
+	}
+	if limits.MaxCPUSeconds > 0 {
+		wrapped = append(wrapped, fmt.Sprintf("--cpu=%d:%d", limits.MaxCPUSeconds, limits.MaxCPUSeconds+1))
+	}
+	if limits.MaxOpenFiles > 0 {
+		wrapped = append(wrapped, fmt.Sprintf("--nofile=%d", limits.MaxOpenFiles))
+	}
+	if len(wrapped) == 1 {
+		return args, nil
+	}
+	wrapped = append(wrapped, "--")
+	return append(wrapped, args...), nil
+}
+
+// isCPULimitSignal returns true if the signal is sent for exceeding the CPU time limit.
+func isCPULimitSignal(sig os.Signal) bool {
+	return sig == syscall.SIGXCPU
+}
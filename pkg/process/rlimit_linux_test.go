@@ -0,0 +1,174 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestProcessWithResourceLimitsMemory(t *testing.T) {
+	p, err := New(
+		// reads 512 MiB into the variable
+		WithBashScriptContentsToRun(`x=$(head -c 536870912 /dev/zero | tr '\0' 'a'); echo done`),
+		WithResourceLimits(ResourceLimits{MaxMemoryBytes: 128 * 1024 * 1024}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []string
+	if err := Read(
+		ctx,
+		p,
+		WithReadStdout(),
+		WithReadStderr(),
+		WithProcessLine(func(line string) {
+			t.Logf("output: %q", line)
+			out = append(out, line)
+		}),
+	); err != nil {
+		t.Logf("read error: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the process to be terminated")
+	case res := <-p.WaitResult():
+		if res.Err == nil {
+			t.Fatal("expected the process to be terminated by the memory limit")
+		}
+		t.Logf("exit code: %d, signal: %v, error: %v", res.ExitCode, res.Signal, res.Err)
+	}
+	for _, line := range out {
+		if line == "done" {
+			t.Fatal("expected the process to be terminated before done")
+		}
+	}
+
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessWithResourceLimitsCPU(t *testing.T) {
+	p, err := New(
+		WithBashScriptContentsToRun(`while :; do :; done`),
+		WithResourceLimits(ResourceLimits{MaxCPUSeconds: 1}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the process to be terminated")
+	case res := <-p.WaitResult():
+		if !errors.Is(res.Err, ErrProcessCPULimitExceeded) {
+			t.Fatalf("expected ErrProcessCPULimitExceeded, got %v", res.Err)
+		}
+		if res.Signal != syscall.SIGXCPU {
+			t.Fatalf("expected SIGXCPU, got %v", res.Signal)
+		}
+	}
+
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessWithResourceLimitsOpenFiles(t *testing.T) {
+	p, err := New(
+		WithCommand("sleep", "10"),
+		WithResourceLimits(ResourceLimits{MaxOpenFiles: 64}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// the limits are set by "prlimit" before it executes the command
+	var line string
+	for i := 0; i < 50; i++ {
+		line, err = readOpenFilesLimit(p.PID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fields := strings.Fields(line); len(fields) >= 5 && fields[3] == "64" && fields[4] == "64" {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("unexpected open files limit %q", line)
+}
+
+// readOpenFilesLimit reads the "Max open files" line of "/proc/[pid]/limits".
+func readOpenFilesLimit(pid int32) (string, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pid))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.HasPrefix(line, "Max open files") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("open files limit not found in %q", string(b))
+}
+
+func TestWithResourceLimits(t *testing.T) {
+	args, err := withResourceLimits([]string{"sleep", "10"}, ResourceLimits{MaxCPUSeconds: 5, MaxOpenFiles: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"--cpu=5:6", "--nofile=64", "--", "sleep", "10"}
+	if len(args) != len(expected)+1 || !strings.HasSuffix(args[0], "prlimit") {
+		t.Fatalf("expected prlimit %v, got %v", expected, args)
+	}
+	for i := range expected {
+		if args[i+1] != expected[i] {
+			t.Fatalf("expected prlimit %v, got %v", expected, args)
+		}
+	}
+
+	// no limit set
+	args, err = withResourceLimits([]string{"sleep", "10"}, ResourceLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[0] != "sleep" {
+		t.Fatalf("expected the command as is, got %v", args)
+	}
+}
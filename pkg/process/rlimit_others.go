@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/leptonai/gpud/log"
+)
+
+// withResourceLimits returns the command args as is, as "prlimit" is only available on Linux.
+func withResourceLimits(args []string, limits ResourceLimits) ([]string, error) {
+	log.Logger.Warnw("resource limits not supported, ignoring", "os", runtime.GOOS, "command", args, "limits", limits)
+	return args, nil
+}
+
+// isCPULimitSignal always returns false, as the CPU time limit is never set.
+func isCPULimitSignal(os.Signal) bool {
+	return false
+}
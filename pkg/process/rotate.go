@@ -0,0 +1,149 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is an io.WriteCloser that writes to the file at the path,
+// and rotates the file when the next write would exceed the max bytes.
+// On rotation, "path" is renamed to "path.1", "path.1" to "path.2", and so on,
+// and the oldest file beyond the max files is removed.
+// Safe for concurrent writes (e.g., stdout and stderr of the same process).
+type rotatingFile struct {
+	mu sync.Mutex
+
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxBytes int64, maxFiles int) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:     path,
+		maxBytes: maxBytes,
+		maxFiles: maxFiles,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file at the path in the append mode,
+// continuing from the existing file size.
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write writes the bytes to the current file, rotating the file as needed.
+// The output of a process is copied via a pipe in arbitrary chunks,
+// thus the bytes are split at the last newline that fits the current file,
+// so that the lines are not split across the files (unless a line exceeds the max bytes).
+func (r *rotatingFile) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	written := 0
+	for len(b) > 0 {
+		chunk := b
+		if r.size+int64(len(b)) > r.maxBytes {
+			room := r.maxBytes - r.size
+			if room < 0 {
+				room = 0
+			}
+			idx := bytes.LastIndexByte(b[:room], '\n')
+			switch {
+			case idx >= 0:
+				chunk = b[:idx+1]
+			case r.size > 0:
+				// no full line fits, start a new file
+				if err := r.rotate(); err != nil {
+					return written, err
+				}
+				continue
+			default:
+				// a single line longer than the max bytes
+				chunk = b[:room]
+			}
+		}
+
+		n, err := r.file.Write(chunk)
+		r.size += int64(n)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+
+		if len(b) > 0 {
+			if err := r.rotate(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if r.maxFiles == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+
+	if err := os.Remove(r.backupPath(r.maxFiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(r.backupPath(i), r.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
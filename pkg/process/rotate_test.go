@@ -0,0 +1,134 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+
+	// 10 bytes per line, 3 lines per file
+	r, err := openRotatingFile(path, 30, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := fmt.Fprintf(r, "line-%04d\n", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// lines 0-2, 3-5 are removed, 6-8 are in "path.1"
+	// only the last line 9 is in the current file
+	expected := map[string]string{
+		path:        "line-0009\n",
+		path + ".1": "line-0006\nline-0007\nline-0008\n",
+		path + ".2": "line-0003\nline-0004\nline-0005\n",
+	}
+	for p, want := range expected {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s: expected %q, got %q", filepath.Base(p), want, string(b))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected no more than 2 rotated files, got %v", err)
+	}
+
+	if _, err := r.Write([]byte("closed\n")); err != os.ErrClosed {
+		t.Errorf("expected %v after close, got %v", os.ErrClosed, err)
+	}
+}
+
+func TestRotatingFileNoBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+
+	r, err := openRotatingFile(path, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, s := range []string{"aaaa\n", "bbbb\n", "cccc\n"} {
+		if _, err := r.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "cccc\n" {
+		t.Errorf("expected %q, got %q", "cccc\n", string(b))
+	}
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no rotated files, got %v", matches)
+	}
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+
+	r, err := openRotatingFile(path, 100, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if _, err := fmt.Fprintf(r, "w%d-%04d\n", w, i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := 0
+	for _, m := range matches {
+		b, err := os.ReadFile(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) > 100 {
+			t.Errorf("%s: expected at most 100 bytes, got %d", filepath.Base(m), len(b))
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+			if len(line) != 7 {
+				t.Errorf("%s: unexpected torn line %q", filepath.Base(m), line)
+			}
+			lines++
+		}
+	}
+	if lines != 400 {
+		t.Errorf("expected 400 lines, got %d", lines)
+	}
+}
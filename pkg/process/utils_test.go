@@ -56,6 +56,18 @@ func (p *testProcess) Wait() <-chan error {
 	return p.waitCh
 }
 
+func (p *testProcess) WaitResult() <-chan ProcessResult {
+	ch := make(chan ProcessResult, 1)
+	close(ch)
+	return ch
+}
+
+func (p *testProcess) StatusUpdates() <-chan ProcessState {
+	ch := make(chan ProcessState)
+	close(ch)
+	return ch
+}
+
 func (p *testProcess) Close(ctx context.Context) error {
 	if p.cmd.Process != nil {
 		return p.cmd.Process.Kill()
@@ -273,6 +285,18 @@ func (p *nilReaderProcess) Wait() <-chan error {
 	return ch
 }
 
+func (p *nilReaderProcess) WaitResult() <-chan ProcessResult {
+	ch := make(chan ProcessResult, 1)
+	close(ch)
+	return ch
+}
+
+func (p *nilReaderProcess) StatusUpdates() <-chan ProcessState {
+	ch := make(chan ProcessState)
+	close(ch)
+	return ch
+}
+
 func (p *nilReaderProcess) Close(context.Context) error {
 	return nil
 }
@@ -317,6 +341,18 @@ func (p *stateProcess) Wait() <-chan error {
 	return ch
 }
 
+func (p *stateProcess) WaitResult() <-chan ProcessResult {
+	ch := make(chan ProcessResult, 1)
+	close(ch)
+	return ch
+}
+
+func (p *stateProcess) StatusUpdates() <-chan ProcessState {
+	ch := make(chan ProcessState)
+	close(ch)
+	return ch
+}
+
 func (p *stateProcess) Close(context.Context) error {
 	return nil
 }